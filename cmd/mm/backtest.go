@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/backtest"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/recorder"
+)
+
+// runBacktest replays a recorded session against the configured strategy
+func runBacktest(args []string) error {
+	fs := flag.NewFlagSet("backtest", flag.ExitOnError)
+	configPath := fs.String("config", "configs/config.yaml", "Path to config file")
+	recording := fs.String("recording", "logs/session.jsonl", "Recorded session (see recorder in config)")
+	fillBps := fs.Uint("fill-bps", uint(backtest.DefaultOptions().FillThresholdBps), "Simulated taker tolerance vs mid (basis points)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	entries, err := recorder.ReadFile(*recording)
+	if err != nil {
+		return err
+	}
+
+	// Candidate strategy: replace with your own to evaluate it
+	seed := cfg.Mock.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	strategy := quote.DefaultMockStrategyWithSeed(seed)
+	strategy.JitterBps = cfg.Mock.JitterBps
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	report, err := backtest.Run(context.Background(), entries, strategy, cfg, logger,
+		&backtest.Options{FillThresholdBps: uint32(*fillBps)})
+	if err != nil {
+		return fmt.Errorf("backtest failed: %w", err)
+	}
+
+	fmt.Printf("Backtest of %s (%d messages, seed %d)\n", *recording, len(entries), seed)
+	report.Write(os.Stdout)
+	return nil
+}
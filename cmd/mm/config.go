@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+)
+
+// runConfig runs the config subcommands
+func runConfig(args []string) error {
+	if len(args) == 0 || args[0] != "print" {
+		return fmt.Errorf("usage: mm config print [flags]")
+	}
+	return runConfigPrint(args[1:], os.Stdout)
+}
+
+// runConfigPrint prints the config with defaults filled in and secrets redacted
+// With -admin, it prints the config of the running MM instead, including the values
+// changed through the admin API.
+func runConfigPrint(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("config print", flag.ExitOnError)
+	configPath := fs.String("config", "configs/config.yaml", "Path to config file")
+	adminURL := fs.String("admin", "", "Admin API base URL of a running MM, e.g. http://127.0.0.1:9092")
+	token := fs.String("token", "", "Admin API token (default: admin.token or admin.tokenEnv of -config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *adminURL == "" {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		return cfg.Print(w)
+	}
+
+	if *token == "" {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			return fmt.Errorf("no -token and failed to load config: %w", err)
+		}
+		*token = cfg.Admin.GetToken()
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(*adminURL, "/")+"/admin/v1/config", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+*token)
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("admin API: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/demo"
+)
+
+// runDemo runs the market maker against an in-process swap engine
+func runDemo(args []string) error {
+	defaults := demo.DefaultOptions()
+	fs := flag.NewFlagSet("demo", flag.ExitOnError)
+	requests := fs.Int("requests", defaults.Requests, "Quote requests to send (the last one is for an unsupported pair)")
+	interval := fs.Duration("interval", defaults.Interval, "Pause between requests")
+	seed := fs.Int64("seed", defaults.Seed, "Seed of the request amounts and the mock strategy")
+	verbose := fs.Bool("v", false, "Also print the market maker logs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var logOut io.Writer = io.Discard
+	if *verbose {
+		logOut = os.Stderr
+	}
+	logger := slog.New(slog.NewTextHandler(logOut, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	summary, err := demo.Run(ctx, demo.Options{Requests: *requests, Interval: *interval, Seed: *seed}, os.Stdout, logger)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nRequests: %d, signed quotes: %d (%d verified), rejects: %d, depth snapshots: %d\n",
+		summary.Requests, summary.Signed, summary.Verified, summary.Rejected, summary.DepthSnapshots)
+	for _, v := range summary.Violations {
+		fmt.Printf("Protocol violation: %s\n", v)
+	}
+	if len(summary.Violations) > 0 {
+		return fmt.Errorf("%d protocol violations", len(summary.Violations))
+	}
+	return nil
+}
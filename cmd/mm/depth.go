@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/runner"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// runDepth runs the depth subcommands
+func runDepth(args []string) error {
+	if len(args) == 0 || args[0] != "preview" {
+		return fmt.Errorf("usage: mm depth preview [flags]")
+	}
+	return runDepthPreview(args[1:])
+}
+
+// runDepthPreview prints the depth snapshot the configured provider would push for a pair
+// Nothing is sent: the command does not connect to the gateway
+func runDepthPreview(args []string) error {
+	fs := flag.NewFlagSet("depth preview", flag.ExitOnError)
+	configPath := fs.String("config", "configs/config.yaml", "Path to config file")
+	chainID := fs.Uint64("chain", 0, "Chain of the pair (0 = the first pair with -pair)")
+	pairID := fs.String("pair", "", "Pair ID, e.g. WBNB-USDT")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	pair, err := findPair(cfg, *chainID, *pairID)
+	if err != nil {
+		return err
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	pusher, err := runner.NewDepthPusher(cfg, logger)
+	if err != nil {
+		return err
+	}
+	msg, err := pusher.Preview(*pair)
+	if err != nil {
+		return fmt.Errorf("depth preview failed: %w", err)
+	}
+
+	out, err := protojson.MarshalOptions{Multiline: true}.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	explainSnapshot(os.Stdout, msg.GetDepthSnapshot(), *pair)
+	return nil
+}
+
+// explainSnapshot writes the levels of snapshot in token units next to their wire values
+func explainSnapshot(w io.Writer, snapshot *mmv1.DepthSnapshot, pair config.PairConfig) {
+	// Wire prices are quote wei per base wei; in token units they scale by 10^(baseDecimals-quoteDecimals)
+	scale := decimal.New(1, pair.BaseTokenDecimals-pair.QuoteTokenDecimals)
+	unit := decimal.New(1, -pair.BaseTokenDecimals)
+
+	fmt.Fprintln(w, "\nPrices: quote token (tokenB) wei per base token (tokenA) wei. Amounts: base token wei.")
+	for _, side := range []struct {
+		name   string
+		levels []*mmv1.PriceLevel
+	}{{"ask", snapshot.GetAsks()}, {"bid", snapshot.GetBids()}} {
+		for i, level := range side.levels {
+			price, errPrice := decimal.Parse(level.Price)
+			amount, errAmount := decimal.Parse(level.Amount)
+			if errPrice != nil || errAmount != nil {
+				fmt.Fprintf(w, "  %s %d: invalid level %v\n", side.name, i, level)
+				continue
+			}
+			fmt.Fprintf(w, "  %s %d: price %s (%s per token), amount %s (%s tokens)\n", side.name, i,
+				level.Price, price.Mul(scale), level.Amount, amount.Mul(unit))
+		}
+	}
+	if att, ok := depth.SnapshotAttestation(snapshot); ok {
+		fmt.Fprintf(w, "Attestation sequence %d at %d\n", att.Sequence, att.Timestamp)
+	}
+}
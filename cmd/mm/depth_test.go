@@ -0,0 +1,29 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+func TestExplainSnapshot(t *testing.T) {
+	// WETH (18 decimals) / USDC (6 decimals) at 3400 USDC per WETH
+	pair := config.PairConfig{PairID: "WETH-USDC", BaseTokenDecimals: 18, QuoteTokenDecimals: 6}
+	snapshot := &mmv1.DepthSnapshot{
+		Asks: []*mmv1.PriceLevel{{Price: "0.0000000034", Amount: "3280000000000000000"}},
+		Bids: []*mmv1.PriceLevel{{Price: "bad", Amount: "1"}},
+	}
+
+	var out strings.Builder
+	explainSnapshot(&out, snapshot, pair)
+	for _, want := range []string{
+		"ask 0: price 0.0000000034 (3400 per token), amount 3280000000000000000 (3.28 tokens)",
+		"bid 0: invalid level",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -3,52 +3,109 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"strings"
 
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/buildinfo"
 	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/logging"
 	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/runner"
 )
 
+// commands are the subcommands; without one, mm runs the market maker
+var commands = map[string]func(args []string) error{
+	"backtest":      runBacktest,
+	"config":        runConfig,
+	"quote":         runQuote,
+	"demo":          runDemo,
+	"depth":         runDepth,
+	"new-strategy":  runNewStrategy,
+	"vectors":       runVectors,
+	"verify-domain": runVerifyDomain,
+}
+
 func main() {
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		cmd, ok := commands[os.Args[1]]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+			os.Exit(2)
+		}
+		if err := cmd(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Parse command line arguments
 	configPath := flag.String("config", "configs/config.yaml", "Path to config file")
+	version := flag.Bool("version", false, "Print the version and exit")
 	flag.Parse()
+	if *version {
+		fmt.Println(buildinfo.Get())
+		return
+	}
 
 	// Initialize logger
-	logger := setupLogger()
+	logger, closeLog := setupLogger()
+	fatal := func(msg string, err error) {
+		logger.Error(msg, "error", err)
+		closeLog()
+		os.Exit(1)
+	}
 
 	logger.Info("Starting DarkPool Market Maker Example",
-		"configPath", *configPath)
+		"configPath", *configPath,
+		"version", buildinfo.Version,
+		"commit", buildinfo.Get().ShortCommit())
 
 	// Load configuration
 	cfg, err := config.Load(*configPath)
 	if err != nil {
-		logger.Error("Failed to load config", "error", err)
-		os.Exit(1)
+		fatal("Failed to load config", err)
+	}
+	if logger, closeLog, err = addLogSinks(cfg, logger, closeLog); err != nil {
+		fatal("Failed to open log sinks", err)
 	}
 
 	logger.Info("Config loaded successfully",
 		"app", cfg.App.Name,
 		"pairs", len(cfg.Pairs),
-		"domains", len(cfg.EIP712Domains))
+		"domains", len(cfg.EIP712Domains),
+		"instances", len(cfg.Instances))
+
+	// Several MM identities run as a group sharing the price feeds and metrics
+	if len(cfg.Instances) > 0 {
+		g, err := runner.NewGroup(cfg, logger)
+		if err != nil {
+			fatal("Failed to create instances", err)
+		}
+		if err := g.Run(context.Background()); err != nil {
+			fatal("Service error", err)
+		}
+		closeLog()
+		return
+	}
 
 	// Create and run service
 	r, err := runner.New(cfg, logger)
 	if err != nil {
-		logger.Error("Failed to create runner", "error", err)
-		os.Exit(1)
+		fatal("Failed to create runner", err)
 	}
 
 	if err := r.Run(context.Background()); err != nil {
-		logger.Error("Service error", "error", err)
-		os.Exit(1)
+		fatal("Service error", err)
 	}
+	closeLog()
 }
 
 // setupLogger initializes the logger
-func setupLogger() *slog.Logger {
+// Records are written by a background goroutine; call the returned func to flush them before exiting
+func setupLogger() (*slog.Logger, func()) {
 	// Create logs directory
 	if err := os.MkdirAll("logs", 0755); err != nil {
 		slog.Error("Failed to create logs directory", "error", err)
@@ -59,14 +116,56 @@ func setupLogger() *slog.Logger {
 	if err != nil {
 		slog.Error("Failed to open log file", "error", err)
 		// Fallback to stdout
-		return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		h := logging.NewAsyncHandler(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 			Level: slog.LevelInfo,
-		}))
+		}), logging.DefaultQueueSize)
+		return slog.New(logging.NewTraceHandler(h)), func() { _ = h.Close() }
 	}
 
 	// Output to both file and stdout
 	multiWriter := io.MultiWriter(os.Stdout, logFile)
-	return slog.New(slog.NewTextHandler(multiWriter, &slog.HandlerOptions{
+	h := logging.NewAsyncHandler(slog.NewTextHandler(multiWriter, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
-	}))
+	}), logging.DefaultQueueSize)
+	return slog.New(logging.NewTraceHandler(h)), func() {
+		_ = h.Close()
+		_ = logFile.Close()
+	}
+}
+
+// addLogSinks returns a logger that also writes to the sinks of logging.sinks
+// The returned func flushes and closes the sinks, then calls closeLog.
+func addLogSinks(cfg *config.Config, logger *slog.Logger, closeLog func()) (*slog.Logger, func(), error) {
+	if len(cfg.Logging.Sinks) == 0 {
+		return logger, closeLog, nil
+	}
+	handlers := []slog.Handler{logger.Handler()}
+	sinks := make([]*logging.AsyncHandler, 0, len(cfg.Logging.Sinks))
+	for i, sinkCfg := range cfg.Logging.Sinks {
+		tag := sinkCfg.Tag
+		if tag == "" {
+			tag = cfg.App.Name
+		}
+		sink, err := logging.NewSink(logging.SinkConfig{
+			Type:    sinkCfg.Type,
+			Address: sinkCfg.Address,
+			TLS:     sinkCfg.TLS,
+			Tag:     tag,
+			Level:   sinkCfg.SlogLevel(),
+		})
+		if err != nil {
+			for _, s := range sinks {
+				_ = s.Close()
+			}
+			return logger, closeLog, fmt.Errorf("logging.sinks[%d]: %w", i, err)
+		}
+		sinks = append(sinks, sink)
+		handlers = append(handlers, logging.NewTraceHandler(sink))
+	}
+	return slog.New(logging.NewMultiHandler(handlers...)), func() {
+		for _, s := range sinks {
+			_ = s.Close()
+		}
+		closeLog()
+	}, nil
 }
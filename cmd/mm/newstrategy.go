@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/strategy/*.tmpl
+var strategyTemplates embed.FS
+
+// strategyNamePattern restricts strategy names to valid lowercase package names
+var strategyNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// strategyFiles maps each template to its output file in the strategy package
+var strategyFiles = map[string]string{
+	"config.go.tmpl":        "config.go",
+	"strategy.go.tmpl":      "strategy.go",
+	"provider.go.tmpl":      "provider.go",
+	"strategy_test.go.tmpl": "strategy_test.go",
+}
+
+// strategyData is the template input
+type strategyData struct {
+	Name       string // Strategy name, also the package name
+	Package    string
+	Module     string // Go module path
+	ImportPath string // Import path of the generated package
+}
+
+// runNewStrategy generates a strategy package skeleton
+func runNewStrategy(args []string) error {
+	fs := flag.NewFlagSet("new-strategy", flag.ExitOnError)
+	dir := fs.String("dir", "internal/strategies", "Parent directory of the generated package")
+	module := fs.String("module", "", "Go module path (default: read from go.mod)")
+	register := fs.Bool("register", true, "Generate cmd/mm/strategy_<name>.go that links the strategy into mm")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: mm new-strategy [flags] <name>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one strategy name")
+	}
+
+	name := fs.Arg(0)
+	if !strategyNamePattern.MatchString(name) || token.IsKeyword(name) || name == "mock" {
+		return fmt.Errorf("invalid strategy name %q: use a lowercase Go package name other than mock", name)
+	}
+	if *module == "" {
+		m, err := readModulePath("go.mod")
+		if err != nil {
+			return err
+		}
+		*module = m
+	}
+
+	pkgDir := filepath.Join(*dir, name)
+	data := strategyData{
+		Name:       name,
+		Package:    name,
+		Module:     *module,
+		ImportPath: *module + "/" + filepath.ToSlash(pkgDir),
+	}
+
+	files, err := renderStrategy(data)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(pkgDir); err == nil {
+		return fmt.Errorf("%s already exists", pkgDir)
+	}
+	if err := os.MkdirAll(pkgDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", pkgDir, err)
+	}
+	for file, src := range files {
+		path := filepath.Join(pkgDir, file)
+		if file == "register.go" {
+			if !*register {
+				continue
+			}
+			path = filepath.Join("cmd", "mm", "strategy_"+name+".go")
+		}
+		if err := os.WriteFile(path, src, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Println("Created", path)
+	}
+
+	fmt.Printf("\nNext steps:\n")
+	fmt.Printf("  1. Implement your pricing in %s and %s\n", filepath.Join(pkgDir, "strategy.go"), filepath.Join(pkgDir, "provider.go"))
+	fmt.Printf("  2. Set strategy.name: %s and its params in your config\n", name)
+	fmt.Printf("  3. go test ./%s/ && make build\n", filepath.ToSlash(pkgDir))
+	return nil
+}
+
+// renderStrategy renders and gofmts every template; register.go is the mm registration file
+func renderStrategy(data strategyData) (map[string][]byte, error) {
+	outputs := map[string]string{"register.go.tmpl": "register.go"}
+	for tmpl, file := range strategyFiles {
+		outputs[tmpl] = file
+	}
+
+	files := make(map[string][]byte, len(outputs))
+	for tmplName, file := range outputs {
+		tmpl, err := template.ParseFS(strategyTemplates, "templates/strategy/"+tmplName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", tmplName, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", tmplName, err)
+		}
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("failed to format %s: %w", file, err)
+		}
+		files[file] = src
+	}
+	return files, nil
+}
+
+// readModulePath reads the module path from a go.mod file
+func readModulePath(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read module path (run from the repository root or set -module): %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return "", fmt.Errorf("no module directive in %s", path)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestRenderStrategy(t *testing.T) {
+	data := strategyData{
+		Name:       "acme",
+		Package:    "acme",
+		Module:     "example.com/mm",
+		ImportPath: "example.com/mm/internal/strategies/acme",
+	}
+
+	files, err := renderStrategy(data)
+	if err != nil {
+		t.Fatalf("renderStrategy failed: %v", err)
+	}
+	if len(files) != len(strategyFiles)+1 {
+		t.Errorf("Files = %d, want %d", len(files), len(strategyFiles)+1)
+	}
+
+	fset := token.NewFileSet()
+	for name, src := range files {
+		f, err := parser.ParseFile(fset, name, src, parser.ImportsOnly)
+		if err != nil {
+			t.Errorf("%s does not parse: %v", name, err)
+			continue
+		}
+		want := "acme"
+		if name == "register.go" {
+			want = "main"
+		}
+		if f.Name.Name != want {
+			t.Errorf("%s package = %s, want %s", name, f.Name.Name, want)
+		}
+		if strings.Contains(string(src), "{{") {
+			t.Errorf("%s contains unrendered template actions", name)
+		}
+	}
+	if !strings.Contains(string(files["register.go"]), `_ "example.com/mm/internal/strategies/acme"`) {
+		t.Error("register.go does not import the strategy package")
+	}
+}
+
+func TestStrategyNamePattern(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"acme", true},
+		{"acme2", true},
+		{"Acme", false},
+		{"acme-mm", false},
+		{"2acme", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := strategyNamePattern.MatchString(tt.name); got != tt.want {
+			t.Errorf("MatchString(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
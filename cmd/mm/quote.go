@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/runner"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// runQuote runs the quote subcommands
+func runQuote(args []string) error {
+	if len(args) == 0 || args[0] != "simulate" {
+		return fmt.Errorf("usage: mm quote simulate [flags]")
+	}
+	return runQuoteSimulate(args[1:])
+}
+
+// runQuoteSimulate quotes one request with the configured pipeline and prints the response
+// Nothing is sent: the command does not connect to the gateway
+func runQuoteSimulate(args []string) error {
+	fs := flag.NewFlagSet("quote simulate", flag.ExitOnError)
+	configPath := fs.String("config", "configs/config.yaml", "Path to config file")
+	chainID := fs.Uint64("chain", 0, "Chain of the pair (0 = the first pair with -pair)")
+	pairID := fs.String("pair", "", "Pair ID, e.g. WBNB-USDT")
+	side := fs.String("side", "sell", "sell: the taker sells the base token, buy: the taker buys it")
+	amount := fs.String("amount", "1", "Input amount in token units (base token to sell, quote token to buy with)")
+	from := fs.String("from", "0x0000000000000000000000000000000000000001", "Taker address (from and recipient)")
+	nonce := fs.String("nonce", "1", "Quote nonce (uint256)")
+	ttl := fs.Duration("deadline", 30*time.Second, "Quote deadline from now")
+	typedData := fs.Bool("typed-data", false, "Also print the signed quote as eth_signTypedData_v4 JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	req, err := simulateRequest(cfg, *chainID, *pairID, *side, *amount)
+	if err != nil {
+		return err
+	}
+	req.From, req.Recipient = *from, *from
+	req.Nonce = *nonce
+	req.Deadline = time.Now().Add(*ttl).Unix()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	handler, domains, err := runner.NewQuoteHandler(cfg, logger)
+	if err != nil {
+		return err
+	}
+	msg, err := handler.HandleQuoteRequest(context.Background(), req)
+	if err != nil {
+		return fmt.Errorf("quote failed: %w", err)
+	}
+
+	out, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	order := msg.GetQuoteResponse().GetOrder()
+	if order == nil {
+		return nil
+	}
+	quote, err := orderQuote(req, order)
+	if err != nil {
+		return err
+	}
+	digest, err := domains.Digest(req.ChainId, quote)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Digest:    %s\n", digest.Hex())
+	fmt.Printf("Signature: %s\n", hexutil.Encode(order.Signature))
+	sig := append([]byte(nil), order.Signature...)
+	if len(sig) == 65 && sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pub, err := crypto.SigToPub(digest.Bytes(), sig)
+	if err != nil {
+		return fmt.Errorf("signature does not recover: %w", err)
+	}
+	fmt.Printf("Recovers:  %s\n", crypto.PubkeyToAddress(*pub).Hex())
+	if !*typedData {
+		return nil
+	}
+	td, err := domains.TypedData(req.ChainId, quote)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(td) // The params[1] of an eth_signTypedData_v4 request
+	if err != nil {
+		return err
+	}
+	fmt.Printf("TypedData: %s\n", data)
+	return nil
+}
+
+// simulateRequest builds the quote request of a pair side; amount is in input token units
+func simulateRequest(cfg *config.Config, chainID uint64, pairID, side, amount string) (*mmv1.QuoteRequest, error) {
+	pair, err := findPair(cfg, chainID, pairID)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenIn, tokenOut, decimals := pair.BaseToken, pair.QuoteToken, pair.BaseTokenDecimals
+	switch side {
+	case "sell":
+	case "buy":
+		tokenIn, tokenOut, decimals = pair.QuoteToken, pair.BaseToken, pair.QuoteTokenDecimals
+	default:
+		return nil, fmt.Errorf("side must be buy or sell, got %q", side)
+	}
+	amountIn, err := config.TickWei(amount, decimals)
+	if err != nil || amountIn.Sign() <= 0 {
+		return nil, fmt.Errorf("amount %q is not a positive amount with %d decimals", amount, decimals)
+	}
+
+	return &mmv1.QuoteRequest{
+		QuoteId:  fmt.Sprintf("simulate-%d", time.Now().UnixNano()),
+		ChainId:  pair.ChainID,
+		TokenIn:  tokenIn,
+		TokenOut: tokenOut,
+		AmountIn: amountIn.String(),
+	}, nil
+}
+
+// findPair returns the first pair with pairID on chainID, or on any chain if chainID is 0
+func findPair(cfg *config.Config, chainID uint64, pairID string) (*config.PairConfig, error) {
+	for i := range cfg.Pairs {
+		if cfg.Pairs[i].PairID == pairID && (chainID == 0 || cfg.Pairs[i].ChainID == chainID) {
+			return &cfg.Pairs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("pair %q not configured (chain %d)", pairID, chainID)
+}
+
+// orderQuote returns the MMQuote the order of req was signed over
+func orderQuote(req *mmv1.QuoteRequest, order *mmv1.SignedOrder) (*signer.MMQuote, error) {
+	amountIn, okIn := new(big.Int).SetString(order.AmountIn, 10)
+	amountOut, okOut := new(big.Int).SetString(order.AmountOut, 10)
+	nonce, okNonce := new(big.Int).SetString(order.Nonce, 10)
+	if !okIn || !okOut || !okNonce {
+		return nil, fmt.Errorf("order has invalid amounts or nonce")
+	}
+	return &signer.MMQuote{
+		RFQManager:  common.HexToAddress(order.RfqManager),
+		From:        common.HexToAddress(req.From),
+		To:          common.HexToAddress(req.Recipient),
+		InputToken:  common.HexToAddress(req.TokenIn),
+		OutputToken: common.HexToAddress(req.TokenOut),
+		AmountIn:    amountIn,
+		AmountOut:   amountOut,
+		Deadline:    big.NewInt(order.Deadline),
+		Nonce:       nonce,
+		ExtraData:   order.ExtraData,
+	}, nil
+}
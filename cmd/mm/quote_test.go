@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+)
+
+func TestSimulateRequest(t *testing.T) {
+	cfg := testutil.Config()
+	pair := cfg.Pairs[0]
+
+	req, err := simulateRequest(cfg, 0, pair.PairID, "sell", "1.5")
+	if err != nil {
+		t.Fatalf("simulateRequest failed: %v", err)
+	}
+	if req.ChainId != pair.ChainID || req.TokenIn != pair.BaseToken || req.TokenOut != pair.QuoteToken || req.AmountIn != "1500000000000000000" {
+		t.Errorf("sell request = %v, want 1.5 base token in", req)
+	}
+
+	req, err = simulateRequest(cfg, pair.ChainID, pair.PairID, "buy", "600")
+	if err != nil {
+		t.Fatalf("simulateRequest failed: %v", err)
+	}
+	if req.TokenIn != pair.QuoteToken || req.TokenOut != pair.BaseToken || req.AmountIn != "600000000000000000000" {
+		t.Errorf("buy request = %v, want 600 quote token in", req)
+	}
+
+	for _, tt := range []struct {
+		chain              uint64
+		pair, side, amount string
+	}{
+		{0, "FOO-BAR", "sell", "1"},
+		{1, pair.PairID, "sell", "1"},
+		{0, pair.PairID, "short", "1"},
+		{0, pair.PairID, "sell", "0"},
+		{0, pair.PairID, "sell", "abc"},
+	} {
+		if _, err := simulateRequest(cfg, tt.chain, tt.pair, tt.side, tt.amount); err == nil {
+			t.Errorf("simulateRequest(%d, %s, %s, %s) = nil error, want error", tt.chain, tt.pair, tt.side, tt.amount)
+		}
+	}
+}
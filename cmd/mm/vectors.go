@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+// defaultVectorsPath is the golden vector file checked by internal/signer tests
+const defaultVectorsPath = "internal/signer/testdata/eip712_vectors.json"
+
+// runVectors regenerates the golden EIP-712 vectors
+func runVectors(args []string) error {
+	fs := flag.NewFlagSet("vectors", flag.ExitOnError)
+	out := fs.String("out", defaultVectorsPath, "Output file (- for stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	vectors, err := signer.GenerateVectors()
+	if err != nil {
+		return fmt.Errorf("failed to generate vectors: %w", err)
+	}
+	data, err := json.MarshalIndent(vectors, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode vectors: %w", err)
+	}
+	data = append(data, '\n')
+
+	if *out == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *out, err)
+	}
+	fmt.Printf("Wrote %d vectors to %s\n", len(vectors), *out)
+	return nil
+}
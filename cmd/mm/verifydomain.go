@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/runner"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+// runVerifyDomain checks the configured EIP-712 domains against their verifying contracts
+func runVerifyDomain(args []string) error {
+	fs := flag.NewFlagSet("verify-domain", flag.ExitOnError)
+	configPath := fs.String("config", "configs/config.yaml", "Path to config file")
+	chainID := fs.Uint64("chain", 0, "Chain to verify (0 = every domain with an rpcUrl)")
+	rpcURL := fs.String("rpc", "", "JSON-RPC endpoint of -chain, overriding its rpcUrl")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	domains, err := runner.DomainManager(cfg)
+	if err != nil {
+		return err
+	}
+
+	if *chainID == 0 {
+		if *rpcURL != "" {
+			return fmt.Errorf("-rpc requires -chain")
+		}
+		if !slices.ContainsFunc(cfg.EIP712Domains, func(d config.EIP712Domain) bool { return d.RPCURL != "" }) {
+			return fmt.Errorf("no eip712Domains entry has an rpcUrl: set one, or use -chain and -rpc")
+		}
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		return runner.VerifyDomains(context.Background(), cfg, domains, logger)
+	}
+
+	url := *rpcURL
+	if url == "" {
+		for _, domain := range cfg.EIP712Domains {
+			if domain.ChainID == *chainID {
+				url = domain.RPCURL
+			}
+		}
+	}
+	if url == "" {
+		return fmt.Errorf("no RPC endpoint for chain %d: set -rpc or its rpcUrl", *chainID)
+	}
+	if err := domains.VerifyDomainAgainstChain(context.Background(), *chainID, signer.NewHTTPRPCClient(url)); err != nil {
+		return err
+	}
+	fmt.Printf("Domain of chain %d matches its contract\n", *chainID)
+	return nil
+}
@@ -0,0 +1,58 @@
+// Package address validates and normalizes hex addresses from config and protocol messages
+// common.HexToAddress accepts anything: it drops invalid characters and pads or truncates to
+// 20 bytes, so a typo silently becomes another address. Parse refuses such input instead.
+package address
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Validation errors
+var (
+	ErrInvalid  = errors.New("invalid address")
+	ErrChecksum = errors.New("address checksum mismatch")
+	ErrZero     = errors.New("zero address not allowed")
+)
+
+// Parse parses a 0x-prefixed 40-digit hex address
+// All-lowercase and all-uppercase addresses are accepted as is; mixed-case addresses must carry
+// a valid EIP-55 checksum.
+func Parse(s string) (common.Address, error) {
+	digits, ok := strings.CutPrefix(s, "0x")
+	if !ok {
+		return common.Address{}, fmt.Errorf("%w %q: missing 0x prefix", ErrInvalid, s)
+	}
+	if len(digits) != 2*common.AddressLength {
+		return common.Address{}, fmt.Errorf("%w %q: %d hex digits, want %d", ErrInvalid, s, len(digits), 2*common.AddressLength)
+	}
+	if _, err := hex.DecodeString(digits); err != nil {
+		return common.Address{}, fmt.Errorf("%w %q: not hex", ErrInvalid, s)
+	}
+	addr := common.HexToAddress(s)
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && addr.Hex() != s {
+		return common.Address{}, fmt.Errorf("%w: %s, want %s", ErrChecksum, s, addr.Hex())
+	}
+	return addr, nil
+}
+
+// ParseNonZero is Parse for addresses that must not be the zero address
+func ParseNonZero(s string) (common.Address, error) {
+	addr, err := Parse(s)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if addr == (common.Address{}) {
+		return common.Address{}, ErrZero
+	}
+	return addr, nil
+}
+
+// Normalize returns the lowercase 0x form of addr, used in every outgoing message
+func Normalize(addr common.Address) string {
+	return strings.ToLower(addr.Hex())
+}
@@ -0,0 +1,54 @@
+package address
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParse(t *testing.T) {
+	wbnb := common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+	tests := []struct {
+		in      string
+		want    common.Address
+		wantErr error
+	}{
+		{"0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c", wbnb, nil},
+		{"0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c", wbnb, nil},
+		{"0xBB4CDB9CBD36B01BD1CBAEBF2DE08D9173BC095C", wbnb, nil},
+		{"0x0000000000000000000000000000000000000000", common.Address{}, nil},
+		{"0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095C", common.Address{}, ErrChecksum}, // Last digit case flipped
+		{"bb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c", common.Address{}, ErrInvalid},
+		{"0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095", common.Address{}, ErrInvalid},
+		{"0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095cc", common.Address{}, ErrInvalid},
+		{"0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095g", common.Address{}, ErrInvalid},
+		{"", common.Address{}, ErrInvalid},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if !errors.Is(err, tt.wantErr) || (err == nil && tt.wantErr != nil) {
+			t.Errorf("Parse(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.in, got.Hex(), tt.want.Hex())
+		}
+	}
+}
+
+func TestParseNonZero(t *testing.T) {
+	if _, err := ParseNonZero("0x0000000000000000000000000000000000000000"); !errors.Is(err, ErrZero) {
+		t.Errorf("ParseNonZero(zero) = %v, want ErrZero", err)
+	}
+	if _, err := ParseNonZero("0x1111111111111111111111111111111111111111"); err != nil {
+		t.Errorf("ParseNonZero = %v, want nil", err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	addr := common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+	if got := Normalize(addr); got != "0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c" {
+		t.Errorf("Normalize = %s, want the lowercase address", got)
+	}
+}
@@ -0,0 +1,324 @@
+package admin
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+// maxBody is the largest request body the API reads
+const maxBody = 64 << 10
+
+// Sources are the components the API acts on; nil sources are skipped
+type Sources struct {
+	Pusher   *depth.Pusher    // Pushes the depth of changed pairs at once
+	Failover *signer.Failover // Primary/backup signing keys switched on /admin/v1/signer
+
+	// Rotate reloads the primary signing key and rotates to it, on /admin/v1/signer/rotate
+	Rotate func() (signer.Rotation, error)
+}
+
+// API serves the admin endpoints, which change MM parameters while running
+// Every request needs the bearer token of admin.token; every change is logged and audited.
+type API struct {
+	cfg     *config.Config
+	tuning  *config.Tuning
+	token   string
+	sources Sources
+	logger  *slog.Logger
+	mux     *http.ServeMux
+
+	mu sync.Mutex // Serializes changes, so audit entries match the values they replace
+}
+
+// NewAPI creates the admin API; it attaches a Tuning to cfg, so call it before cfg is shared
+func NewAPI(cfg *config.Config, sources Sources, logger *slog.Logger) (*API, error) {
+	token := cfg.Admin.GetToken()
+	if token == "" {
+		return nil, fmt.Errorf("admin token is not set")
+	}
+	a := &API{
+		cfg:     cfg,
+		tuning:  cfg.EnableTuning(),
+		token:   token,
+		sources: sources,
+		logger:  logger.With("component", "AdminAPI"),
+		mux:     http.NewServeMux(),
+	}
+	a.mux.HandleFunc("/admin/v1/params", a.serveParams)
+	a.mux.HandleFunc("/admin/v1/config", a.serveConfig)
+	a.mux.HandleFunc("/admin/v1/runtime", a.serveRuntime)
+	a.mux.HandleFunc("/admin/v1/signer", a.serveSigner)
+	a.mux.HandleFunc("/admin/v1/signer/rotate", a.serveRotate)
+	return a, nil
+}
+
+// ServeHTTP implements http.Handler
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(auth), []byte(a.token)) != 1 {
+		a.logger.Warn("Unauthorized admin request", "remote", r.RemoteAddr, "path", r.URL.Path)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	a.mux.ServeHTTP(w, r)
+}
+
+// Params are the current values of the adjustable parameters
+type Params struct {
+	ValidDuration string        `json:"validDuration"`
+	Pairs         []PairParams  `json:"pairs"`
+	Chains        []ChainParams `json:"chains"`
+}
+
+// PairParams are the current parameters of a pair
+type PairParams struct {
+	ChainID uint64 `json:"chainId"`
+	PairID  string `json:"pairId"`
+	config.PairParams
+}
+
+// ChainParams are the current feature flags of a chain
+type ChainParams struct {
+	ChainID uint64 `json:"chainId"`
+	config.ChainFlags
+}
+
+// Change is a POST /admin/v1/params body; omitted fields are unchanged
+type Change struct {
+	ValidDuration string        `json:"validDuration,omitempty"` // e.g. "20s"
+	Pairs         []PairChange  `json:"pairs,omitempty"`
+	Chains        []ChainChange `json:"chains,omitempty"`
+}
+
+// PairChange changes parameters of a pair; an empty max amount means unlimited
+type PairChange struct {
+	ChainID    uint64  `json:"chainId"`
+	PairID     string  `json:"pairId"`
+	SpreadBps  *uint32 `json:"spreadBps,omitempty"`
+	MaxBaseIn  *string `json:"maxBaseIn,omitempty"`
+	MaxQuoteIn *string `json:"maxQuoteIn,omitempty"`
+}
+
+// ChainChange switches features of a chain
+type ChainChange struct {
+	ChainID      uint64 `json:"chainId"`
+	PauseQuoting *bool  `json:"pauseQuoting,omitempty"`
+	PauseDepth   *bool  `json:"pauseDepth,omitempty"`
+}
+
+// AuditEntry records one changed value
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	Remote string    `json:"remote"`
+	Field  string    `json:"field"` // quote.validDuration, pairs[chainId:pairId].<param>, chains[chainId].<flag>, signer.active or signer.key
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+}
+
+// serveParams serves the parameters on GET and changes them on POST
+func (a *API) serveParams(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPost:
+		var change Change
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&change); err != nil {
+			http.Error(w, "invalid body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := a.apply(change, r.RemoteAddr); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	a.writeJSON(w, r, a.params())
+}
+
+// serveConfig serves the running config as YAML, with tuned values and secrets redacted
+func (a *API) serveConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var buf bytes.Buffer
+	if err := a.cfg.Effective().Print(&buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// params returns the current parameters
+func (a *API) params() Params {
+	params := Params{ValidDuration: a.cfg.ValidDuration().String(), Pairs: make([]PairParams, 0, len(a.cfg.Pairs))}
+	for i := range a.cfg.Pairs {
+		pair := &a.cfg.Pairs[i]
+		params.Pairs = append(params.Pairs, PairParams{ChainID: pair.ChainID, PairID: pair.PairID, PairParams: a.cfg.PairParams(pair)})
+	}
+	for _, chainID := range a.cfg.ChainIDs() {
+		params.Chains = append(params.Chains, ChainParams{ChainID: chainID, ChainFlags: a.cfg.ChainFlags(chainID)})
+	}
+	return params
+}
+
+// apply validates a change and applies all of it, or none of it if any value is invalid
+func (a *API) apply(change Change, remote string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var entries []AuditEntry
+	now := time.Now()
+	record := func(field, from, to string) {
+		if from != to {
+			entries = append(entries, AuditEntry{Time: now, Remote: remote, Field: field, From: from, To: to})
+		}
+	}
+
+	tuned := config.TuningChange{
+		Pairs:  make(map[config.PairRef]config.PairParams, len(change.Pairs)),
+		Chains: make(map[uint64]config.ChainFlags, len(change.Chains)),
+	}
+	if change.ValidDuration != "" {
+		d, err := time.ParseDuration(change.ValidDuration)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("validDuration must be a positive duration, got %q", change.ValidDuration)
+		}
+		tuned.ValidDuration = d
+		record("quote.validDuration", a.cfg.ValidDuration().String(), d.String())
+	}
+
+	for i, pc := range change.Pairs {
+		pair := a.findPair(pc.ChainID, pc.PairID)
+		if pair == nil {
+			return fmt.Errorf("pairs[%d]: pair %q not configured on chain %d", i, pc.PairID, pc.ChainID)
+		}
+		ref := config.PairRef{ChainID: pc.ChainID, PairID: pc.PairID}
+		old, ok := tuned.Pairs[ref]
+		if !ok {
+			old = a.cfg.PairParams(pair)
+		}
+		params := old
+		if pc.SpreadBps != nil {
+			params.SpreadBps = *pc.SpreadBps
+		}
+		if pc.MaxBaseIn != nil {
+			params.MaxBaseIn = *pc.MaxBaseIn
+		}
+		if pc.MaxQuoteIn != nil {
+			params.MaxQuoteIn = *pc.MaxQuoteIn
+		}
+		if err := params.Validate(pair); err != nil {
+			return fmt.Errorf("pairs[%d]: %w", i, err)
+		}
+		prefix := fmt.Sprintf("pairs[%d:%s].", pc.ChainID, pc.PairID)
+		record(prefix+"spreadBps", strconv.FormatUint(uint64(old.SpreadBps), 10), strconv.FormatUint(uint64(params.SpreadBps), 10))
+		record(prefix+"maxBaseIn", old.MaxBaseIn, params.MaxBaseIn)
+		record(prefix+"maxQuoteIn", old.MaxQuoteIn, params.MaxQuoteIn)
+		tuned.Pairs[ref] = params
+	}
+
+	for i, cc := range change.Chains {
+		if a.cfg.GetEIP712Domain(cc.ChainID) == nil {
+			return fmt.Errorf("chains[%d]: chain %d not configured", i, cc.ChainID)
+		}
+		old, ok := tuned.Chains[cc.ChainID]
+		if !ok {
+			old = a.cfg.ChainFlags(cc.ChainID)
+		}
+		flags := old
+		if cc.PauseQuoting != nil {
+			flags.PauseQuoting = *cc.PauseQuoting
+		}
+		if cc.PauseDepth != nil {
+			flags.PauseDepth = *cc.PauseDepth
+		}
+		prefix := fmt.Sprintf("chains[%d].", cc.ChainID)
+		record(prefix+"pauseQuoting", strconv.FormatBool(old.PauseQuoting), strconv.FormatBool(flags.PauseQuoting))
+		record(prefix+"pauseDepth", strconv.FormatBool(old.PauseDepth), strconv.FormatBool(flags.PauseDepth))
+		tuned.Chains[cc.ChainID] = flags
+	}
+
+	a.tuning.Set(tuned)
+	for _, entry := range entries {
+		a.logger.Warn("Admin change", "field", entry.Field, "from", entry.From, "to", entry.To, "remote", entry.Remote)
+	}
+	if err := a.writeAudit(entries); err != nil {
+		a.logger.Error("Failed to write admin audit file", "path", a.cfg.Admin.AuditFile, "error", err)
+	}
+	// Publish the changed books now rather than at the next periodic push
+	if a.sources.Pusher != nil {
+		for ref := range tuned.Pairs {
+			a.sources.Pusher.Trigger(ref.ChainID, ref.PairID)
+		}
+		for chainID, flags := range tuned.Chains {
+			for i := range a.cfg.Pairs {
+				if a.cfg.Pairs[i].ChainID == chainID && !flags.PauseDepth {
+					a.sources.Pusher.Trigger(chainID, a.cfg.Pairs[i].PairID)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// findPair returns the configured pair with pairID on chainID
+func (a *API) findPair(chainID uint64, pairID string) *config.PairConfig {
+	for i := range a.cfg.Pairs {
+		if a.cfg.Pairs[i].ChainID == chainID && a.cfg.Pairs[i].PairID == pairID {
+			return &a.cfg.Pairs[i]
+		}
+	}
+	return nil
+}
+
+// writeAudit appends entries to admin.auditFile as JSON lines
+func (a *API) writeAudit(entries []AuditEntry) error {
+	path := a.cfg.Admin.AuditFile
+	if path == "" || len(entries) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeJSON writes v as the JSON response
+func (a *API) writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		a.logger.Debug("Failed to write response", "path", r.URL.Path, "error", err)
+	}
+}
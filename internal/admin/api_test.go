@@ -0,0 +1,246 @@
+package admin_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/admin"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+)
+
+const token = "admin-token"
+
+func do(t *testing.T, h http.Handler, method, path, body, auth string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if auth != "" {
+		req.Header.Set("Authorization", "Bearer "+auth)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAPI_ChangesParams(t *testing.T) {
+	cfg := testutil.Config()
+	pair := &cfg.Pairs[0]
+	cfg.Admin.Token = token
+	cfg.Admin.AuditFile = filepath.Join(t.TempDir(), "audit.jsonl")
+	api, err := admin.NewAPI(cfg, admin.Sources{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewAPI failed: %v", err)
+	}
+
+	if rec := do(t, api, http.MethodGet, "/admin/v1/params", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("without token: status %d, want 401", rec.Code)
+	}
+	if rec := do(t, api, http.MethodGet, "/admin/v1/params", "", "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong token: status %d, want 401", rec.Code)
+	}
+
+	change := `{"validDuration":"15s","pairs":[{"chainId":56,"pairId":"WBNB-USDT","spreadBps":25,"maxBaseIn":"10"}]}`
+	rec := do(t, api, http.MethodPost, "/admin/v1/params", change, token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST: status %d: %s", rec.Code, rec.Body)
+	}
+	var params admin.Params
+	if err := json.Unmarshal(rec.Body.Bytes(), &params); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if params.ValidDuration != "15s" || params.Pairs[0].SpreadBps != 25 || params.Pairs[0].MaxBaseIn != "10" {
+		t.Errorf("params = %+v", params)
+	}
+	if cfg.ValidDuration() != 15*time.Second || cfg.PairParams(pair).SpreadBps != 25 {
+		t.Errorf("config not tuned: validDuration %v, params %+v", cfg.ValidDuration(), cfg.PairParams(pair))
+	}
+	if base, _ := cfg.PairMaxAmountsIn(pair); base == nil || base.String() != "10000000000000000000" {
+		t.Errorf("max base in = %v, want 10 tokens", base)
+	}
+	if pair.SpreadBps != 0 || pair.MaxBaseIn != "" {
+		t.Error("the loaded pair config was modified")
+	}
+
+	// A change with an invalid value applies none of its values
+	rec = do(t, api, http.MethodPost, "/admin/v1/params", `{"validDuration":"5s","pairs":[{"chainId":56,"pairId":"WBNB-USDT","spreadBps":10000}]}`, token)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid spread: status %d, want 400", rec.Code)
+	}
+	if rec := do(t, api, http.MethodPost, "/admin/v1/params", `{"pairs":[{"chainId":1,"pairId":"WBNB-USDT","spreadBps":1}]}`, token); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown pair: status %d, want 400", rec.Code)
+	}
+	if cfg.ValidDuration() != 15*time.Second {
+		t.Errorf("validDuration = %v after a rejected change, want 15s", cfg.ValidDuration())
+	}
+
+	f, err := os.Open(cfg.Admin.AuditFile)
+	if err != nil {
+		t.Fatalf("audit file: %v", err)
+	}
+	defer f.Close()
+	var fields []string
+	for scanner := bufio.NewScanner(f); scanner.Scan(); {
+		var entry admin.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid audit line %q", scanner.Text())
+		}
+		fields = append(fields, entry.Field+" "+entry.From+" -> "+entry.To)
+	}
+	want := []string{"quote.validDuration 30s -> 15s", "pairs[56:WBNB-USDT].spreadBps 0 -> 25", "pairs[56:WBNB-USDT].maxBaseIn  -> 10"}
+	if strings.Join(fields, "\n") != strings.Join(want, "\n") {
+		t.Errorf("audit entries:\n%s\nwant:\n%s", strings.Join(fields, "\n"), strings.Join(want, "\n"))
+	}
+
+	// The printed config shows the tuned values and hides secrets
+	rec = do(t, api, http.MethodGet, "/admin/v1/config", "", token)
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.Contains(body, "validDuration: 15s") || !strings.Contains(body, "spreadBps: 25") {
+		t.Errorf("config: status %d:\n%s", rec.Code, body)
+	}
+	if strings.Contains(body, token) {
+		t.Error("config shows the admin token")
+	}
+}
+
+func TestAPI_PausesChains(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Admin.Token = token
+	api, err := admin.NewAPI(cfg, admin.Sources{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewAPI failed: %v", err)
+	}
+
+	rec := do(t, api, http.MethodPost, "/admin/v1/params", `{"chains":[{"chainId":56,"pauseQuoting":true}]}`, token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST: status %d: %s", rec.Code, rec.Body)
+	}
+	if flags := cfg.ChainFlags(56); !flags.PauseQuoting || flags.PauseDepth {
+		t.Errorf("flags = %+v, want quoting paused only", flags)
+	}
+	var params admin.Params
+	if err := json.Unmarshal(rec.Body.Bytes(), &params); err != nil || len(params.Chains) != 1 || !params.Chains[0].PauseQuoting {
+		t.Errorf("params = %+v (%v)", params, err)
+	}
+	if body := do(t, api, http.MethodGet, "/admin/v1/config", "", token).Body.String(); !strings.Contains(body, "pauseQuoting: true") {
+		t.Errorf("config does not show the paused chain:\n%s", body)
+	}
+
+	if rec := do(t, api, http.MethodPost, "/admin/v1/params", `{"chains":[{"chainId":1,"pauseDepth":true}]}`, token); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown chain: status %d, want 400", rec.Code)
+	}
+}
+
+func TestAPI_Runtime(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Admin.Token = token
+	api, err := admin.NewAPI(cfg, admin.Sources{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewAPI failed: %v", err)
+	}
+	if rec := do(t, api, http.MethodGet, "/admin/v1/runtime", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("without token: status %d, want 401", rec.Code)
+	}
+
+	runtime.GC()
+	rec := do(t, api, http.MethodGet, "/admin/v1/runtime", "", token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET: status %d: %s", rec.Code, rec.Body)
+	}
+	var rt admin.Runtime
+	if err := json.Unmarshal(rec.Body.Bytes(), &rt); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if rt.Build.Version == "" || rt.Build.GoVersion != runtime.Version() {
+		t.Errorf("build = %+v", rt.Build)
+	}
+	if rt.Goroutines <= 0 || rt.Memory.HeapAlloc == 0 || rt.GC.Cycles == 0 || rt.GC.Last == nil || rt.UptimeSeconds <= 0 {
+		t.Errorf("runtime = %+v", rt)
+	}
+}
+
+func TestAPI_SwitchesSigner(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Admin.Token = token
+	cfg.Admin.AuditFile = filepath.Join(t.TempDir(), "audit.jsonl")
+	primary, backup := testutil.NewFakeSigner(common.HexToAddress("0x01")), testutil.NewFakeSigner(common.HexToAddress("0x02"))
+	failover := signer.NewFailover(primary, backup, signer.FailoverConfig{FailBack: signer.FailBackManual})
+	api, err := admin.NewAPI(cfg, admin.Sources{Failover: failover}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewAPI failed: %v", err)
+	}
+
+	for _, tt := range []struct {
+		body   string
+		code   int
+		active string
+	}{
+		{`{"action":"failOver"}`, http.StatusOK, signer.BackupKeyName},
+		{`{"action":"failOver"}`, http.StatusConflict, signer.BackupKeyName},
+		{`{"action":"restart"}`, http.StatusConflict, signer.BackupKeyName},
+		{`{"action":"failBack"}`, http.StatusOK, signer.PrimaryKeyName},
+	} {
+		if rec := do(t, api, http.MethodPost, "/admin/v1/signer", tt.body, token); rec.Code != tt.code {
+			t.Errorf("POST %s: status %d, want %d: %s", tt.body, rec.Code, tt.code, rec.Body)
+		}
+		if active := failover.State().Active; active != tt.active {
+			t.Errorf("after %s: active %s, want %s", tt.body, active, tt.active)
+		}
+	}
+
+	rec := do(t, api, http.MethodGet, "/admin/v1/signer", "", token)
+	var state signer.FailoverState
+	if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil || state.Failovers != 1 || state.Backup != backup.Address {
+		t.Errorf("GET: %s (%v)", rec.Body, err)
+	}
+	audit, err := os.ReadFile(cfg.Admin.AuditFile)
+	if err != nil || strings.Count(string(audit), `"field":"signer.active"`) != 2 {
+		t.Errorf("audit file:\n%s (%v)", audit, err)
+	}
+
+	api, _ = admin.NewAPI(cfg, admin.Sources{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if rec := do(t, api, http.MethodGet, "/admin/v1/signer", "", token); rec.Code != http.StatusNotFound {
+		t.Errorf("without a backup: status %d, want 404", rec.Code)
+	}
+}
+
+func TestAPI_RotatesSigner(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Admin.Token = token
+	cfg.Admin.AuditFile = filepath.Join(t.TempDir(), "audit.jsonl")
+	rotating := signer.NewRotatingSigner(testutil.NewFakeSigner(common.HexToAddress("0x01")))
+	next := testutil.NewFakeSigner(common.HexToAddress("0x02"))
+	rotate := func() (signer.Rotation, error) { return rotating.Rotate(next) }
+	api, err := admin.NewAPI(cfg, admin.Sources{Rotate: rotate}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewAPI failed: %v", err)
+	}
+
+	if rec := do(t, api, http.MethodGet, "/admin/v1/signer/rotate", "", token); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status %d, want 405", rec.Code)
+	}
+	rec := do(t, api, http.MethodPost, "/admin/v1/signer/rotate", "", token)
+	var rotation signer.Rotation
+	if err := json.Unmarshal(rec.Body.Bytes(), &rotation); err != nil || rotation.To != next.Address || rotating.GetAddress() != next.Address {
+		t.Errorf("POST: %d %s (%v)", rec.Code, rec.Body, err)
+	}
+	// The key source still holds the current key
+	if rec := do(t, api, http.MethodPost, "/admin/v1/signer/rotate", "", token); rec.Code != http.StatusConflict {
+		t.Errorf("second POST: status %d, want 409", rec.Code)
+	}
+	audit, err := os.ReadFile(cfg.Admin.AuditFile)
+	if err != nil || strings.Count(string(audit), `"field":"signer.key"`) != 1 {
+		t.Errorf("audit file:\n%s (%v)", audit, err)
+	}
+}
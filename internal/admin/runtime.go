@@ -0,0 +1,79 @@
+package admin
+
+import (
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/buildinfo"
+)
+
+// Runtime is the GET /admin/v1/runtime body: the build and the Go runtime stats of the process
+type Runtime struct {
+	Build         buildinfo.Info `json:"build"`
+	Uptime        string         `json:"uptime"`
+	UptimeSeconds float64        `json:"uptimeSeconds"`
+	Goroutines    int            `json:"goroutines"`
+	GOMAXPROCS    int            `json:"gomaxprocs"`
+	Memory        MemoryStats    `json:"memory"`
+	GC            GCStats        `json:"gc"`
+}
+
+// MemoryStats are the heap and process memory in bytes
+type MemoryStats struct {
+	HeapAlloc   uint64 `json:"heapAlloc"`   // Bytes of live and not yet collected heap objects
+	HeapInuse   uint64 `json:"heapInuse"`   // Bytes in in-use heap spans
+	HeapObjects uint64 `json:"heapObjects"` // Allocated heap objects
+	Sys         uint64 `json:"sys"`         // Bytes obtained from the OS
+}
+
+// GCStats summarize the garbage collections since the process started
+type GCStats struct {
+	Cycles     uint32     `json:"cycles"`
+	PauseTotal string     `json:"pauseTotal"`
+	LastPause  string     `json:"lastPause"`
+	Last       *time.Time `json:"last,omitempty"` // nil before the first collection
+	CPUPercent float64    `json:"cpuPercent"`     // Share of CPU time spent in GC since start
+}
+
+// readRuntime collects the runtime stats; ReadMemStats stops the world briefly
+func readRuntime() Runtime {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	uptime := buildinfo.Uptime()
+	rt := Runtime{
+		Build:         buildinfo.Get(),
+		Uptime:        uptime.Round(time.Second).String(),
+		UptimeSeconds: uptime.Seconds(),
+		Goroutines:    runtime.NumGoroutine(),
+		GOMAXPROCS:    runtime.GOMAXPROCS(0),
+		Memory: MemoryStats{
+			HeapAlloc:   ms.HeapAlloc,
+			HeapInuse:   ms.HeapInuse,
+			HeapObjects: ms.HeapObjects,
+			Sys:         ms.Sys,
+		},
+		GC: GCStats{
+			Cycles:     ms.NumGC,
+			PauseTotal: time.Duration(ms.PauseTotalNs).String(),
+			LastPause:  time.Duration(0).String(),
+			CPUPercent: ms.GCCPUFraction * 100,
+		},
+	}
+	if ms.NumGC > 0 {
+		rt.GC.LastPause = time.Duration(ms.PauseNs[(ms.NumGC+255)%256]).String()
+		last := time.Unix(0, int64(ms.LastGC))
+		rt.GC.Last = &last
+	}
+	return rt
+}
+
+// serveRuntime serves the runtime stats
+func (a *API) serveRuntime(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	a.writeJSON(w, r, readRuntime())
+}
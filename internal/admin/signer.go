@@ -0,0 +1,104 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+// Signer actions of a POST /admin/v1/signer body
+const (
+	ActionFailOver = "failOver" // Sign with the backup key, e.g. ahead of primary maintenance
+	ActionFailBack = "failBack" // Sign with the primary key again; needed under the manual fail-back policy
+)
+
+// SignerAction is a POST /admin/v1/signer body
+type SignerAction struct {
+	Action string `json:"action"`
+}
+
+// serveSigner serves the primary/backup key state on GET and switches keys on POST
+func (a *API) serveSigner(w http.ResponseWriter, r *http.Request) {
+	failover := a.sources.Failover
+	if failover == nil {
+		http.Error(w, "no backup signer configured", http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPost:
+		var action SignerAction
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&action); err != nil {
+			http.Error(w, "invalid body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := a.switchSigner(failover, action.Action, r.RemoteAddr); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	a.writeJSON(w, r, failover.State())
+}
+
+// switchSigner applies a signer action and audits it
+func (a *API) switchSigner(failover *signer.Failover, action, remote string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	from := failover.State().Active
+	var err error
+	switch action {
+	case ActionFailOver:
+		err = failover.FailOver()
+	case ActionFailBack:
+		err = failover.FailBack()
+	default:
+		return fmt.Errorf("action must be %s or %s, got %q", ActionFailOver, ActionFailBack, action)
+	}
+	if err != nil {
+		return err
+	}
+	entry := AuditEntry{Time: time.Now(), Remote: remote, Field: "signer.active", From: from, To: failover.State().Active}
+	a.logger.Warn("Admin change", "field", entry.Field, "from", entry.From, "to", entry.To, "remote", entry.Remote)
+	if err := a.writeAudit([]AuditEntry{entry}); err != nil {
+		a.logger.Error("Failed to write admin audit file", "path", a.cfg.Admin.AuditFile, "error", err)
+	}
+	return nil
+}
+
+// serveRotate rotates the primary signing key on POST and answers with the rotation
+func (a *API) serveRotate(w http.ResponseWriter, r *http.Request) {
+	if a.sources.Rotate == nil {
+		http.Error(w, "signing key rotation not supported", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	a.mu.Lock()
+	rotation, err := a.sources.Rotate()
+	if err != nil {
+		a.mu.Unlock()
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	entry := AuditEntry{Time: rotation.Time, Remote: r.RemoteAddr, Field: "signer.key", From: rotation.From.Hex(), To: rotation.To.Hex()}
+	a.logger.Warn("Admin change", "field", entry.Field, "from", entry.From, "to", entry.To, "remote", entry.Remote)
+	if err := a.writeAudit([]AuditEntry{entry}); err != nil {
+		a.logger.Error("Failed to write admin audit file", "path", a.cfg.Admin.AuditFile, "error", err)
+	}
+	a.mu.Unlock()
+	a.writeJSON(w, r, rotation)
+}
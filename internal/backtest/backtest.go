@@ -0,0 +1,232 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/chains"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/recorder"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// Options backtest options
+type Options struct {
+	// FillThresholdBps is the simulated taker tolerance: a quote fills when its price is
+	// no worse than the recorded mid by more than this many basis points
+	// The protocol reports no fills, so this model stands in for taker behaviour
+	FillThresholdBps uint32
+}
+
+// DefaultOptions returns default options
+func DefaultOptions() *Options {
+	return &Options{FillThresholdBps: 30}
+}
+
+// Report backtest results
+type Report struct {
+	Requests        int            // Quote requests replayed
+	Quoted          int            // Answered with a signed quote by the candidate strategy
+	Rejected        int            // Rejected by the candidate strategy
+	RejectsByReason map[string]int // Candidate rejects by reason
+	Filled          int            // Quotes filled under the fill model
+	Unpriced        int            // Quotes without a recorded mid (fill and PnL unknown)
+
+	// PnL of filled quotes valued at the recorded mid, in tokenOut native units
+	// key: "chainId:tokenOut" (lowercase address)
+	PnL map[string]*big.Int
+
+	LiveQuoted   int // Quote responses sent in the recorded session
+	LiveRejected int // Quote rejects sent in the recorded session
+}
+
+// FillRate returns filled / priced quotes
+func (r *Report) FillRate() float64 {
+	priced := r.Quoted - r.Unpriced
+	if priced <= 0 {
+		return 0
+	}
+	return float64(r.Filled) / float64(priced)
+}
+
+// Write writes a human-readable report
+func (r *Report) Write(w io.Writer) {
+	fmt.Fprintf(w, "Requests:   %d\n", r.Requests)
+	fmt.Fprintf(w, "Quoted:     %d (live %d)\n", r.Quoted, r.LiveQuoted)
+	fmt.Fprintf(w, "Rejected:   %d (live %d)\n", r.Rejected, r.LiveRejected)
+	for _, reason := range sortedKeys(r.RejectsByReason) {
+		fmt.Fprintf(w, "  %-40s %d\n", reason, r.RejectsByReason[reason])
+	}
+	fmt.Fprintf(w, "Filled:     %d (fill rate %.1f%%, %d unpriced)\n", r.Filled, r.FillRate()*100, r.Unpriced)
+	fmt.Fprintln(w, "PnL (tokenOut native units, at mid):")
+	for _, key := range sortedKeys(r.PnL) {
+		fmt.Fprintf(w, "  %-52s %s\n", key, r.PnL[key])
+	}
+}
+
+// Run replays the quote requests of a recording against a candidate strategy
+// Each request is handled at its recorded time, and mids come from the depth snapshots
+// the MM pushed before it. Quotes are signed with a throwaway key.
+func Run(ctx context.Context, entries []recorder.Entry, strategy quote.QuoteStrategy, cfg *config.Config, logger *slog.Logger, opts *Options) (*Report, error) {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	domainManager := signer.NewDomainManager()
+	for _, domain := range cfg.EIP712Domains {
+		domainManager.AddPoolDomainWithConfig(domain.ChainID, domain.Name, domain.Version, domain.VerifyingContract)
+		if err := domainManager.SetQuoteType(domain.ChainID, domain.QuoteType); err != nil {
+			return nil, fmt.Errorf("eip712Domains chain %d quoteType: %w", domain.ChainID, err)
+		}
+		if err := domainManager.SetDomainFields(domain.ChainID, domain.Fields, domain.Salt); err != nil {
+			return nil, fmt.Errorf("eip712Domains chain %d: %w", domain.ChainID, err)
+		}
+	}
+
+	var now time.Time
+	handler := quote.NewHandler(strategy, signer.NewSigner(key, domainManager), cfg, logger)
+	handler.SetClock(func() time.Time { return now })
+
+	report := &Report{
+		RejectsByReason: make(map[string]int),
+		PnL:             make(map[string]*big.Int),
+	}
+	mids := make(map[string]decimal.Decimal) // "chainId:tokenA:tokenB" -> tokenB wei per tokenA wei
+	registry := cfg.ChainRegistry()
+
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		now = entry.Time
+		msg := entry.Message
+
+		if entry.Direction == recorder.DirectionOut {
+			switch msg.Type {
+			case mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT:
+				snapshot := msg.GetDepthSnapshot()
+				if mid, ok := midPrice(snapshot); ok {
+					mids[pairKey(snapshot.ChainId, snapshot.TokenA, snapshot.TokenB)] = mid
+				}
+			case mmv1.MessageType_MESSAGE_TYPE_QUOTE_RESPONSE:
+				report.LiveQuoted++
+			case mmv1.MessageType_MESSAGE_TYPE_QUOTE_REJECT:
+				report.LiveRejected++
+			}
+			continue
+		}
+
+		req := msg.GetQuoteRequest()
+		if msg.Type != mmv1.MessageType_MESSAGE_TYPE_QUOTE_REQUEST || req == nil {
+			continue
+		}
+		report.Requests++
+
+		resp, err := handler.HandleQuoteRequest(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("quote %s: %w", req.QuoteId, err)
+		}
+		if reject := resp.GetQuoteReject(); reject != nil {
+			report.Rejected++
+			report.RejectsByReason[ws.EnumName(reject.Reason)]++
+			continue
+		}
+		report.Quoted++
+
+		order := resp.GetQuoteResponse().GetOrder()
+		amountIn, _ := new(big.Int).SetString(order.AmountIn, 10)
+		amountOut, _ := new(big.Int).SetString(order.AmountOut, 10)
+		tokenIn, tokenOut := resolveToken(registry, req.ChainId, req.TokenIn), resolveToken(registry, req.ChainId, req.TokenOut)
+
+		mid, ok := lookupMid(mids, req.ChainId, tokenIn, tokenOut)
+		if !ok || amountIn == nil || amountOut == nil {
+			report.Unpriced++
+			continue
+		}
+
+		// Taker compares the quoted price against mid
+		fair := mid.MulInt(amountIn)
+		threshold := fair.Mul(decimal.New(10000-int64(opts.FillThresholdBps), -4))
+		if decimal.NewFromBigInt(amountOut).Cmp(threshold) < 0 {
+			continue
+		}
+		report.Filled++
+
+		// MM receives amountIn (worth amountIn*mid) and pays amountOut
+		pnl := fair.Sub(decimal.NewFromBigInt(amountOut)).Int()
+		k := fmt.Sprintf("%d:%s", req.ChainId, tokenOut)
+		if report.PnL[k] == nil {
+			report.PnL[k] = new(big.Int)
+		}
+		report.PnL[k].Add(report.PnL[k], pnl)
+	}
+
+	return report, nil
+}
+
+// midPrice returns (best bid + best ask) / 2 of a snapshot, false if either side is empty
+func midPrice(snapshot *mmv1.DepthSnapshot) (decimal.Decimal, bool) {
+	if snapshot == nil || len(snapshot.Asks) == 0 || len(snapshot.Bids) == 0 {
+		return decimal.Zero, false
+	}
+	ask, errAsk := decimal.Parse(snapshot.Asks[0].Price)
+	bid, errBid := decimal.Parse(snapshot.Bids[0].Price)
+	if errAsk != nil || errBid != nil {
+		return decimal.Zero, false
+	}
+	return ask.Add(bid).Quo(decimal.NewFromInt(2)), true
+}
+
+// lookupMid returns the tokenOut-per-tokenIn mid, inverting the pair if needed
+func lookupMid(mids map[string]decimal.Decimal, chainID uint64, tokenIn, tokenOut string) (decimal.Decimal, bool) {
+	if mid, ok := mids[pairKey(chainID, tokenIn, tokenOut)]; ok {
+		return mid, true
+	}
+	if mid, ok := mids[pairKey(chainID, tokenOut, tokenIn)]; ok && mid.Sign() > 0 {
+		return decimal.NewFromInt(1).Quo(mid), true
+	}
+	return decimal.Zero, false
+}
+
+// resolveToken lowercases a token address, mapping the zero address to the wrapped native token
+func resolveToken(registry *chains.Registry, chainID uint64, token string) string {
+	addr := common.HexToAddress(token)
+	if addr == (common.Address{}) {
+		if wrapped, ok := registry.WrappedNative(chainID); ok {
+			addr = wrapped
+		}
+	}
+	return strings.ToLower(addr.Hex())
+}
+
+// pairKey builds the mid lookup key
+func pairKey(chainID uint64, tokenA, tokenB string) string {
+	return fmt.Sprintf("%d:%s:%s", chainID, strings.ToLower(tokenA), strings.ToLower(tokenB))
+}
+
+// sortedKeys returns the sorted keys of a map
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,94 @@
+package backtest
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/recorder"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// recording is a short session recorded in 2024: depth at mid 600, then three requests
+func recording() []recorder.Entry {
+	start := time.Unix(1735084800, 0)
+	at := func(s int) time.Time { return start.Add(time.Duration(s) * time.Second) }
+
+	request := func(id, tokenIn, tokenOut string) *mmv1.Message {
+		req := testutil.QuoteRequest()
+		req.QuoteId = id
+		req.TokenIn = tokenIn
+		req.TokenOut = tokenOut
+		req.Deadline = at(30).Unix() // Expired today, valid at the recorded time
+		return testutil.NewQuoteRequest(req)
+	}
+
+	return []recorder.Entry{
+		{Time: at(0), Direction: recorder.DirectionOut, Message: &mmv1.Message{
+			Type: mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT,
+			Payload: &mmv1.Message_DepthSnapshot{DepthSnapshot: &mmv1.DepthSnapshot{
+				ChainId: 56,
+				TokenA:  testutil.DefaultTokenIn,
+				TokenB:  testutil.DefaultTokenOut,
+				Asks:    []*mmv1.PriceLevel{{Price: "601", Amount: "1"}},
+				Bids:    []*mmv1.PriceLevel{{Price: "599", Amount: "1"}},
+			}},
+		}},
+		{Time: at(1), Direction: recorder.DirectionIn, Message: request("q1", testutil.DefaultTokenIn, testutil.DefaultTokenOut)},
+		{Time: at(1), Direction: recorder.DirectionOut, Message: &mmv1.Message{Type: mmv1.MessageType_MESSAGE_TYPE_QUOTE_RESPONSE}},
+		{Time: at(2), Direction: recorder.DirectionIn, Message: request("q2", testutil.DefaultTokenIn, "0x0000000000000000000000000000000000000001")},
+		{Time: at(2), Direction: recorder.DirectionOut, Message: &mmv1.Message{Type: mmv1.MessageType_MESSAGE_TYPE_QUOTE_REJECT}},
+		{Time: at(60), Direction: recorder.DirectionIn, Message: request("q3", testutil.DefaultTokenIn, testutil.DefaultTokenOut)},
+	}
+}
+
+func TestRun(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	strategy := quote.DefaultMockStrategyWithSeed(1) // 600 with 50 bps spread
+
+	tests := []struct {
+		name       string
+		fillBps    uint32
+		wantFilled int
+	}{
+		{"spread wider than tolerance", 30, 0},
+		{"spread within tolerance", 60, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Run(context.Background(), recording(), strategy, testutil.Config(), logger, &Options{FillThresholdBps: tt.fillBps})
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			if report.Requests != 3 {
+				t.Errorf("Requests = %d, want 3", report.Requests)
+			}
+			if report.Quoted != 1 || report.Rejected != 2 {
+				t.Errorf("Quoted/Rejected = %d/%d, want 1/2", report.Quoted, report.Rejected)
+			}
+			if report.RejectsByReason["REJECT_REASON_PAIR_NOT_SUPPORTED"] != 1 {
+				t.Errorf("RejectsByReason = %v, want 1 REJECT_REASON_PAIR_NOT_SUPPORTED", report.RejectsByReason)
+			}
+			if report.LiveQuoted != 1 || report.LiveRejected != 1 {
+				t.Errorf("Live = %d/%d, want 1/1", report.LiveQuoted, report.LiveRejected)
+			}
+			if report.Filled != tt.wantFilled {
+				t.Errorf("Filled = %d, want %d", report.Filled, tt.wantFilled)
+			}
+
+			// 1 WBNB at mid 600 for 597 USDT: 3 USDT
+			if tt.wantFilled == 1 {
+				want, _ := new(big.Int).SetString("3000000000000000000", 10)
+				if got := report.PnL["56:"+testutil.DefaultTokenOut]; got == nil || got.Cmp(want) != 0 {
+					t.Errorf("PnL = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Package buildinfo identifies the running binary
+package buildinfo
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Build variables, set by the Makefile build target with
+// -ldflags "-X github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/buildinfo.Version=..."
+var (
+	Version   = "dev" // Release version, e.g. v1.4.0
+	Commit    = ""    // Git commit, empty = taken from the Go build info if available
+	BuildTime = ""    // RFC 3339 build time, empty = commit time from the Go build info
+)
+
+// started approximates the process start time
+var started = time.Now()
+
+// Info describes the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+	Modified  bool   `json:"modified,omitempty"` // Built from a working tree with uncommitted changes
+}
+
+// Get returns the build info of the running binary
+// Without ldflags, the commit and time come from the VCS stamp of go build, "unknown" without one.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
+
+// ShortCommit returns the first 12 characters of the commit
+func (i Info) ShortCommit() string {
+	if len(i.Commit) > 12 {
+		return i.Commit[:12]
+	}
+	return i.Commit
+}
+
+// String returns the --version line
+func (i Info) String() string {
+	commit := i.ShortCommit()
+	if i.Modified {
+		commit += "-dirty"
+	}
+	return fmt.Sprintf("mm %s (commit %s, built %s, %s)", i.Version, commit, i.BuildTime, i.GoVersion)
+}
+
+// UserAgent returns the User-Agent sent in the WebSocket handshake
+func (i Info) UserAgent() string {
+	return fmt.Sprintf("DarkPool-MM/%s (%s; %s)", i.Version, i.ShortCommit(), i.GoVersion)
+}
+
+// Uptime returns the time since the process started
+func Uptime() time.Duration {
+	return time.Since(started)
+}
@@ -0,0 +1,30 @@
+package buildinfo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGet_LinkerValues(t *testing.T) {
+	defer func(v, c, b string) { Version, Commit, BuildTime = v, c, b }(Version, Commit, BuildTime)
+	Version, Commit, BuildTime = "v1.2.3", "0123456789abcdef0123", "2026-01-02T03:04:05Z"
+
+	info := Get()
+	if info.Version != "v1.2.3" || info.Commit != Commit || info.BuildTime != BuildTime || info.GoVersion == "" {
+		t.Fatalf("Get() = %+v", info)
+	}
+	info.Modified = false
+	if got := info.String(); !strings.HasPrefix(got, "mm v1.2.3 (commit 0123456789ab, built 2026-01-02T03:04:05Z, go") {
+		t.Errorf("String() = %q", got)
+	}
+	if got := info.UserAgent(); !strings.HasPrefix(got, "DarkPool-MM/v1.2.3 (0123456789ab; go") {
+		t.Errorf("UserAgent() = %q", got)
+	}
+}
+
+func TestGet_Unstamped(t *testing.T) {
+	info := Get()
+	if info.Commit == "" || info.BuildTime == "" {
+		t.Errorf("Get() = %+v, want unknown rather than empty values", info)
+	}
+}
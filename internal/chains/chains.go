@@ -0,0 +1,133 @@
+// Package chains is the registry of the chains the MM knows: name, wrapped native token,
+// block explorer and native decimals by chain ID
+// The built-in entries cover the chains the DarkPool runs on; the chains section of the
+// config adds chains and overrides built-in values.
+package chains
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultNativeDecimals are the decimals of a native token whose chain does not set them
+const DefaultNativeDecimals = 18
+
+// Chain is the registry entry of a chain
+type Chain struct {
+	ID             uint64
+	Name           string         // Short lowercase name, e.g. "bsc"
+	WrappedNative  common.Address // Token quoted for the native token (zero address); zero = none
+	Explorer       string         // Block explorer base URL, e.g. "https://bscscan.com"; empty = none
+	NativeDecimals uint8          // Decimals of the native token
+}
+
+// builtin are the chains known without configuration
+var builtin = []Chain{
+	{ID: 1, Name: "ethereum", WrappedNative: common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"), Explorer: "https://etherscan.io", NativeDecimals: 18},
+	{ID: 10, Name: "optimism", WrappedNative: common.HexToAddress("0x4200000000000000000000000000000000000006"), Explorer: "https://optimistic.etherscan.io", NativeDecimals: 18},
+	{ID: 56, Name: "bsc", WrappedNative: common.HexToAddress("0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c"), Explorer: "https://bscscan.com", NativeDecimals: 18},
+	{ID: 8453, Name: "base", WrappedNative: common.HexToAddress("0x4200000000000000000000000000000000000006"), Explorer: "https://basescan.org", NativeDecimals: 18},
+	{ID: 42161, Name: "arbitrum", WrappedNative: common.HexToAddress("0x82af49447d8a07e3bd95bd0d56f35241523fbab1"), Explorer: "https://arbiscan.io", NativeDecimals: 18},
+}
+
+// Registry maps chain IDs to chains; it is not changed after New, so it is safe for concurrent use
+type Registry struct {
+	chains map[uint64]Chain
+}
+
+// New creates a registry of the built-in chains and overrides
+// A chain not built in is added; the non-zero fields of an override replace those of the
+// built-in chain. Added chains without NativeDecimals get DefaultNativeDecimals.
+func New(overrides ...Chain) *Registry {
+	r := &Registry{chains: make(map[uint64]Chain, len(builtin)+len(overrides))}
+	for _, c := range builtin {
+		r.chains[c.ID] = c
+	}
+	for _, o := range overrides {
+		c, ok := r.chains[o.ID]
+		if !ok {
+			c = Chain{ID: o.ID, NativeDecimals: DefaultNativeDecimals}
+		}
+		if o.Name != "" {
+			c.Name = o.Name
+		}
+		if o.WrappedNative != (common.Address{}) {
+			c.WrappedNative = o.WrappedNative
+		}
+		if o.Explorer != "" {
+			c.Explorer = strings.TrimSuffix(o.Explorer, "/")
+		}
+		if o.NativeDecimals != 0 {
+			c.NativeDecimals = o.NativeDecimals
+		}
+		r.chains[o.ID] = c
+	}
+	return r
+}
+
+// Default is the registry of the built-in chains
+var Default = New()
+
+// Lookup returns the chain of chainID
+func (r *Registry) Lookup(chainID uint64) (Chain, bool) {
+	c, ok := r.chains[chainID]
+	return c, ok
+}
+
+// Chains returns every chain, by chain ID
+func (r *Registry) Chains() []Chain {
+	out := make([]Chain, 0, len(r.chains))
+	for _, c := range r.chains {
+		out = append(out, c)
+	}
+	slices.SortFunc(out, func(a, b Chain) int { return cmp.Compare(a.ID, b.ID) })
+	return out
+}
+
+// Name returns the name of a chain, "chain_<id>" when it has none
+func (r *Registry) Name(chainID uint64) string {
+	if c, ok := r.chains[chainID]; ok && c.Name != "" {
+		return c.Name
+	}
+	return fmt.Sprintf("chain_%d", chainID)
+}
+
+// WrappedNative returns the wrapped native token of a chain
+func (r *Registry) WrappedNative(chainID uint64) (common.Address, bool) {
+	c, ok := r.chains[chainID]
+	if !ok || c.WrappedNative == (common.Address{}) {
+		return common.Address{}, false
+	}
+	return c.WrappedNative, true
+}
+
+// NativeDecimals returns the decimals of the native token of a chain, DefaultNativeDecimals when unknown
+func (r *Registry) NativeDecimals(chainID uint64) uint8 {
+	if c, ok := r.chains[chainID]; ok && c.NativeDecimals != 0 {
+		return c.NativeDecimals
+	}
+	return DefaultNativeDecimals
+}
+
+// AddressURL returns the explorer page of an address on a chain, empty without an explorer
+func (r *Registry) AddressURL(chainID uint64, addr common.Address) string {
+	return r.url(chainID, "address", addr.Hex())
+}
+
+// TxURL returns the explorer page of a transaction on a chain, empty without an explorer
+func (r *Registry) TxURL(chainID uint64, tx common.Hash) string {
+	return r.url(chainID, "tx", tx.Hex())
+}
+
+// url returns <explorer>/<kind>/<id> for a chain, empty without an explorer
+func (r *Registry) url(chainID uint64, kind, id string) string {
+	c, ok := r.chains[chainID]
+	if !ok || c.Explorer == "" {
+		return ""
+	}
+	return c.Explorer + "/" + kind + "/" + id
+}
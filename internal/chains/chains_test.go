@@ -0,0 +1,65 @@
+package chains
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRegistry_Builtin(t *testing.T) {
+	tests := []struct {
+		chainID uint64
+		name    string
+	}{
+		{1, "ethereum"},
+		{56, "bsc"},
+		{8453, "base"},
+		{42161, "arbitrum"},
+		{10, "optimism"},
+		{999, "chain_999"},
+	}
+	for _, tt := range tests {
+		if got := Default.Name(tt.chainID); got != tt.name {
+			t.Errorf("Name(%d) = %s, want %s", tt.chainID, got, tt.name)
+		}
+	}
+	if wbnb, ok := Default.WrappedNative(56); !ok || wbnb != common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c") {
+		t.Errorf("WrappedNative(56) = %s, %v, want WBNB", wbnb.Hex(), ok)
+	}
+	if _, ok := Default.WrappedNative(999); ok {
+		t.Error("WrappedNative of an unknown chain found")
+	}
+	if got := Default.NativeDecimals(999); got != DefaultNativeDecimals {
+		t.Errorf("NativeDecimals(999) = %d, want %d", got, DefaultNativeDecimals)
+	}
+	tx := common.HexToHash("0x01")
+	if got := Default.TxURL(8453, tx); got != "https://basescan.org/tx/"+tx.Hex() {
+		t.Errorf("TxURL = %q", got)
+	}
+	if got := Default.AddressURL(999, common.Address{}); got != "" {
+		t.Errorf("AddressURL without an explorer = %q, want empty", got)
+	}
+}
+
+func TestRegistry_Overrides(t *testing.T) {
+	monad := common.HexToAddress("0x5555555555555555555555555555555555555555")
+	r := New(
+		Chain{ID: 56, Explorer: "https://bsc.example.com/"},
+		Chain{ID: 143, Name: "monad", WrappedNative: monad},
+	)
+
+	bsc, _ := r.Lookup(56)
+	if bsc.Name != "bsc" || bsc.Explorer != "https://bsc.example.com" || bsc.WrappedNative == (common.Address{}) {
+		t.Errorf("overridden bsc = %+v, want the built-in entry with the new explorer", bsc)
+	}
+	added, ok := r.Lookup(143)
+	if !ok || added.Name != "monad" || added.WrappedNative != monad || added.NativeDecimals != DefaultNativeDecimals {
+		t.Errorf("added chain = %+v, %v", added, ok)
+	}
+	if chains := r.Chains(); len(chains) != len(builtin)+1 || chains[0].ID != 1 || chains[len(chains)-1].ID != 42161 {
+		t.Errorf("Chains() = %+v, want every chain by ID", chains)
+	}
+	if _, ok := Default.Lookup(143); ok {
+		t.Error("overrides changed the default registry")
+	}
+}
@@ -1,23 +1,86 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"gopkg.in/yaml.v3"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/address"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/chains"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/nonce"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/store"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
 )
 
 // Config application configuration
 type Config struct {
-	App           AppConfig           `yaml:"app"`
-	Signer        SignerConfig        `yaml:"signer"`
-	WebSocket     WebSocketConfig     `yaml:"websocket"`
-	EIP712Domains []EIP712Domain      `yaml:"eip712Domains"`
-	Quote         QuoteConfig         `yaml:"quote"`
-	Depth         DepthConfig         `yaml:"depth"`
-	Pairs         []PairConfig        `yaml:"pairs"`
+	App           AppConfig         `yaml:"app"`
+	Signer        SignerConfig      `yaml:"signer"`
+	WebSocket     WebSocketConfig   `yaml:"websocket"`
+	EIP712Domains []EIP712Domain    `yaml:"eip712Domains"`
+	Quote         QuoteConfig       `yaml:"quote"`
+	Depth         DepthConfig       `yaml:"depth"`
+	Consistency   ConsistencyConfig `yaml:"consistency"`
+	Oracle        OracleConfig      `yaml:"oracle"`
+	Pairs         []PairConfig      `yaml:"pairs"`
+	Chains        []ChainConfig     `yaml:"chains"`
+	Status        StatusConfig      `yaml:"status"`
+	Mock          MockConfig        `yaml:"mock"`
+	Recorder      RecorderConfig    `yaml:"recorder"`
+	FlowExport    FlowExportConfig  `yaml:"flowExport"`
+	Strategy      StrategyConfig    `yaml:"strategy"`
+	Shadow        ShadowConfig      `yaml:"shadow"`
+	CoSign        CoSignConfig      `yaml:"cosign"`
+	Stable        StableConfig      `yaml:"stable"`
+	Synthetic     SyntheticConfig   `yaml:"synthetic"`
+	Mid           MidConfig         `yaml:"mid"`
+	Schedule      ScheduleConfig    `yaml:"schedule"`
+	Profiling     ProfilingConfig   `yaml:"profiling"`
+	Metrics       MetricsConfig     `yaml:"metrics"`
+	Store         StoreConfig       `yaml:"store"`
+	Query         QueryConfig       `yaml:"query"`
+	Webhook       WebhookConfig     `yaml:"webhook"`
+	Logging       LoggingConfig     `yaml:"logging"`
+	Admin         AdminConfig       `yaml:"admin"`
+	Instances     []InstanceConfig  `yaml:"instances"` // Further MM identities run in this process, see ForInstances
+
+	index  *lookupIndex // Built by BuildIndex, nil = linear lookups
+	tuning *Tuning      // Attached by EnableTuning, nil = loaded values only
+}
+
+// lookupIndex indexes domains and pairs for per-RFQ lookups
+type lookupIndex struct {
+	domains map[uint64]int  // chainId -> index in EIP712Domains
+	pairs   map[pairKey]int // chainId + token pair -> index in Pairs
+	chains  *chains.Registry
+}
+
+// pairKey identifies a pair regardless of direction: tokens are stored in ascending order
+type pairKey struct {
+	chainID        uint64
+	tokenA, tokenB common.Address
+}
+
+// newPairKey builds the lookup key of a token pair
+func newPairKey(chainID uint64, token0, token1 common.Address) pairKey {
+	if bytes.Compare(token0[:], token1[:]) > 0 {
+		token0, token1 = token1, token0
+	}
+	return pairKey{chainID: chainID, tokenA: token0, tokenB: token1}
 }
 
 // AppConfig application basic configuration
@@ -28,8 +91,158 @@ type AppConfig struct {
 
 // SignerConfig signer configuration
 type SignerConfig struct {
-	PrivateKey    string `yaml:"privateKey"`    // Private key (hexadecimal, highest priority)
-	PrivateKeyEnv string `yaml:"privateKeyEnv"` // Private key environment variable name (fallback)
+	PrivateKey    string       `yaml:"privateKey"`    // Private key (hexadecimal, highest priority)
+	PrivateKeyEnv string       `yaml:"privateKeyEnv"` // Private key environment variable name (fallback)
+	Vault         VaultConfig  `yaml:"vault"`         // Private key in HashiCorp Vault (used without the above)
+	Ledger        LedgerConfig `yaml:"ledger"`        // Key on a Ledger device (replaces the above when enabled)
+
+	// Encrypted geth keystore file (UTC--... JSON), used without the above; its password is
+	// KeystorePassword or the PasswordEnv environment variable
+	KeystoreFile     string `yaml:"keystoreFile"`
+	KeystorePassword string `yaml:"keystorePassword"`
+	PasswordEnv      string `yaml:"passwordEnv"`
+
+	// Key held by a remote signing service, reached over mutual TLS (replaces the above when set)
+	Remote RemoteSignerConfig `yaml:"remote"`
+
+	// The signer refuses quotes with a deadline more than MaxDeadlineHorizon ahead, or shortens
+	// them to it when ClampDeadline is set; applies to every key of the pool
+	MaxDeadlineHorizon time.Duration `yaml:"maxDeadlineHorizon"` // 0 = unlimited
+	ClampDeadline      bool          `yaml:"clampDeadline"`      // Clamp instead of refusing
+
+	// Every key signs and verifies a canary quote at startup and every HealthCheckInterval,
+	// so an unusable key is noticed before a quote request needs it
+	HealthCheckInterval time.Duration `yaml:"healthCheckInterval"`
+
+	Pool   SignerPoolConfig   `yaml:"pool"`   // Additional quote signing keys
+	Backup SignerBackupConfig `yaml:"backup"` // Key taking over from the primary key while it fails
+
+	// Keys signing the quotes of one chain instead of the primary key, by chain ID
+	ChainKeys map[uint64]SignerChainKeyConfig `yaml:"chainKeys"`
+
+	// Smart contract wallet (ERC-1271, e.g. a Safe) that is the MM identity; the key above signs as its owner
+	ContractWallet ContractWalletConfig `yaml:"contractWallet"`
+}
+
+// ContractWalletConfig smart contract wallet identity configuration
+// Quotes name the wallet as their signer and are verified with its isValidSignature.
+type ContractWalletConfig struct {
+	Address string `yaml:"address"` // Wallet contract; empty = the key itself is the MM identity
+	Format  string `yaml:"format"`  // safe: Safe SafeMessage signature (default); direct: the owner signs the quote digest
+}
+
+// SignerChainKeyConfig signing key of one chain
+// The primary key stays the MM identity (mm_id); the chain's RFQ manager must authorize this key.
+type SignerChainKeyConfig struct {
+	PrivateKey    string `yaml:"privateKey"`
+	PrivateKeyEnv string `yaml:"privateKeyEnv"`
+}
+
+// VaultConfig locates the private key in a HashiCorp Vault KV version 2 secrets engine
+// The key is fetched at startup. Vault is authenticated with a token, or with an AppRole login.
+type VaultConfig struct {
+	Address   string `yaml:"address"`   // Vault server, e.g. https://vault.internal:8200; empty = not used
+	Namespace string `yaml:"namespace"` // Vault Enterprise namespace
+	Mount     string `yaml:"mount"`     // KV v2 mount path
+	Path      string `yaml:"path"`      // Secret path under the mount, e.g. mm/signer
+	Field     string `yaml:"field"`     // Secret field holding the hexadecimal key
+
+	Token    string `yaml:"token"`    // Vault token (highest priority)
+	TokenEnv string `yaml:"tokenEnv"` // Environment variable holding the token, e.g. VAULT_TOKEN
+
+	AppRole VaultAppRoleConfig `yaml:"appRole"` // Used without a token
+
+	Timeout time.Duration `yaml:"timeout"` // Per Vault request
+}
+
+// LedgerConfig Ledger hardware wallet signer configuration
+// Every signature must be confirmed on the device. Needs a binary built with the ledger tag.
+type LedgerConfig struct {
+	Enabled        bool          `yaml:"enabled"`
+	DerivationPath string        `yaml:"derivationPath"` // BIP-32 path of the signing account
+	Timeout        time.Duration `yaml:"timeout"`        // Wait for the device, including the confirmation
+}
+
+// RemoteSignerConfig remote signing service configuration
+// The service signs EIP-712 digests with a key the MM process never holds; see the README for its API.
+type RemoteSignerConfig struct {
+	URL      string        `yaml:"url"`      // https:// base URL of the service; empty = not used
+	Address  string        `yaml:"address"`  // Expected key address; empty = as reported by the service
+	CertFile string        `yaml:"certFile"` // Client certificate (PEM) for mutual TLS
+	KeyFile  string        `yaml:"keyFile"`  // Private key of certFile (PEM)
+	CAFile   string        `yaml:"caFile"`   // CA certificates of the service (PEM); empty = system roots
+	Timeout  time.Duration `yaml:"timeout"`  // Per attempt
+	Retries  int           `yaml:"retries"`  // Further attempts after a transport error or 5xx answer
+}
+
+// VaultAppRoleConfig AppRole credentials of Vault
+type VaultAppRoleConfig struct {
+	Mount       string `yaml:"mount"` // AppRole auth mount path
+	RoleID      string `yaml:"roleId"`
+	SecretID    string `yaml:"secretId"`
+	SecretIDEnv string `yaml:"secretIdEnv"` // Environment variable holding the secret ID
+}
+
+// Enabled reports whether the key is read from Vault
+func (v *VaultConfig) Enabled() bool {
+	return v.Address != ""
+}
+
+// GetToken returns the Vault token (config first, then environment variable)
+func (v *VaultConfig) GetToken() string {
+	if v.Token != "" {
+		return v.Token
+	}
+	if v.TokenEnv != "" {
+		return strings.TrimSpace(os.Getenv(v.TokenEnv))
+	}
+	return ""
+}
+
+// GetSecretID returns the AppRole secret ID (config first, then environment variable)
+func (a *VaultAppRoleConfig) GetSecretID() string {
+	if a.SecretID != "" {
+		return a.SecretID
+	}
+	if a.SecretIDEnv != "" {
+		return strings.TrimSpace(os.Getenv(a.SecretIDEnv))
+	}
+	return ""
+}
+
+// SignerBackupConfig backup signing key configuration
+// Quotes are signed with the backup key after FailAfter consecutive signing errors or failed
+// health checks of the primary key. The primary key stays the MM identity (mm_id).
+type SignerBackupConfig struct {
+	PrivateKey    string `yaml:"privateKey"`
+	PrivateKeyEnv string `yaml:"privateKeyEnv"`
+	FailAfter     int    `yaml:"failAfter"`     // Consecutive primary errors before failing over
+	FailBack      string `yaml:"failBack"`      // auto: after FailBackAfter passed health checks; manual: through the admin API
+	FailBackAfter int    `yaml:"failBackAfter"` // Passed primary health checks in a row before failing back
+}
+
+// Enabled reports whether a backup key is configured
+func (b *SignerBackupConfig) Enabled() bool {
+	return b.PrivateKey != "" || b.PrivateKeyEnv != ""
+}
+
+// SignerPoolConfig signing key pool configuration
+// Quotes are signed with the primary key above and Keys, assigned by Policy. The primary key
+// stays the MM identity (mm_id). Keys listed in DrainFile are taken out of rotation at runtime
+type SignerPoolConfig struct {
+	Keys      []SignerKeyConfig `yaml:"keys"`
+	Policy    string            `yaml:"policy"`    // roundRobin (default), perPair, perChain or failover
+	Pairs     map[string]string `yaml:"pairs"`     // Pair ID -> key name (perPair)
+	Chains    map[uint64]string `yaml:"chains"`    // Chain ID -> key name (perChain)
+	DrainFile string            `yaml:"drainFile"` // File of drained key names, one per line; re-read while running
+}
+
+// SignerKeyConfig a named signing key of the pool ("primary" is the key of SignerConfig)
+type SignerKeyConfig struct {
+	Name          string `yaml:"name"`
+	PrivateKey    string `yaml:"privateKey"`
+	PrivateKeyEnv string `yaml:"privateKeyEnv"`
+	Drained       bool   `yaml:"drained"` // Start out of rotation
 }
 
 // GetPrivateKey gets private key (prioritizes config file, falls back to environment variable)
@@ -47,6 +260,22 @@ func (c *SignerConfig) GetPrivateKey() (string, error) {
 	return "", fmt.Errorf("neither privateKey nor privateKeyEnv is configured")
 }
 
+// GetKeystorePassword returns the keystore password (config first, then environment variable)
+// An empty password is valid for a keystore, so an unset variable is an error rather than "".
+func (c *SignerConfig) GetKeystorePassword() (string, error) {
+	if c.KeystorePassword != "" {
+		return c.KeystorePassword, nil
+	}
+	if c.PasswordEnv != "" {
+		password, ok := os.LookupEnv(c.PasswordEnv)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", c.PasswordEnv)
+		}
+		return strings.TrimRight(password, "\r\n"), nil
+	}
+	return "", nil
+}
+
 // WebSocketConfig WebSocket configuration
 type WebSocketConfig struct {
 	ServerURL            string        `yaml:"serverUrl"`
@@ -56,6 +285,50 @@ type WebSocketConfig struct {
 	HeartbeatInterval    time.Duration `yaml:"heartbeatInterval"`
 	ReadTimeout          time.Duration `yaml:"readTimeout"`
 	WriteTimeout         time.Duration `yaml:"writeTimeout"`
+	ApplyServerConfig    bool          `yaml:"applyServerConfig"` // Apply server-suggested settings from ConnectionAck
+	HeartbeatHealth      bool          `yaml:"heartbeatHealth"`   // Attach client health to heartbeat pings
+	EventHistory         int           `yaml:"eventHistory"`      // Connection events kept for /connections, 0 = none
+
+	// Gateway certificate pins, "sha256/<base64>" (public key) or "cert-sha256/<base64>"
+	// (certificate), checked on top of CA validation for both regions; empty = no pinning
+	TLSPins []string `yaml:"tlsPins"`
+
+	Standby        StandbyConfig        `yaml:"standby"`        // Hot spare connection to a second gateway region
+	ReconnectStorm ReconnectStormConfig `yaml:"reconnectStorm"` // Process-wide reconnect limit
+	Bandwidth      BandwidthConfig      `yaml:"bandwidth"`      // Outbound byte budget
+	Dialer         DialerConfig         `yaml:"dialer"`         // Connection-level tuning for long-lived links
+}
+
+// DialerConfig tunes how connections are dialed and kept alive, for long-lived connections
+// over flaky NATs and load balancers
+type DialerConfig struct {
+	HandshakeTimeout   time.Duration `yaml:"handshakeTimeout"`   // TCP, TLS and WebSocket handshake
+	KeepAlive          time.Duration `yaml:"keepAlive"`          // TCP keepalive probe period, 0 = OS/Go default, negative = off
+	TLSSessionCache    int           `yaml:"tlsSessionCache"`    // TLS sessions kept for resumption, 0 = none
+	DNSRecheckInterval time.Duration `yaml:"dnsRecheckInterval"` // Reconnect when the server host moves, 0 = off
+}
+
+// BandwidthConfig outbound byte budget for metered or constrained links
+// Quotes and heartbeats are always sent; depth pushes are deferred while the budget is short
+type BandwidthConfig struct {
+	MaxOutboundBytes int64         `yaml:"maxOutboundBytes"` // Per window, 0 = unlimited
+	Window           time.Duration `yaml:"window"`
+	Reserve          float64       `yaml:"reserve"` // Fraction of the budget kept for quotes and heartbeats
+}
+
+// ReconnectStormConfig limits reconnect attempts across all connections of the process
+// Beyond MaxAttempts reconnects within Window, reconnects pause for one to two CoolOff periods
+type ReconnectStormConfig struct {
+	MaxAttempts int           `yaml:"maxAttempts"` // 0 = no limit
+	Window      time.Duration `yaml:"window"`
+	CoolOff     time.Duration `yaml:"coolOff"`
+}
+
+// StandbyConfig is a standby connection to a secondary gateway region
+// It is kept authenticated but idle, and takes over quoting when the active connection degrades
+type StandbyConfig struct {
+	ServerURL string `yaml:"serverUrl"` // Empty = no standby
+	APIToken  string `yaml:"apiToken"`  // Empty = websocket.apiToken
 }
 
 // EIP712Domain EIP-712 Domain configuration
@@ -64,17 +337,469 @@ type EIP712Domain struct {
 	Name              string `yaml:"name"`
 	Version           string `yaml:"version"`
 	VerifyingContract string `yaml:"verifyingContract"`
+	RPCURL            string `yaml:"rpcUrl"` // JSON-RPC endpoint of the chain; when set, its chain ID and the domain are verified at startup
+
+	// EIP-712 encoded MMQuote type of the contract, for RFQ Managers deployed with another
+	// struct layout; empty = the current layout (signer.MMQuoteType)
+	QuoteType string `yaml:"quoteType"`
+
+	// Members of the domain type, for contracts whose domain omits some or has a salt; empty =
+	// name, version, chainId and verifyingContract, plus salt when Salt is set
+	Fields []string `yaml:"fields"`
+	Salt   string   `yaml:"salt"` // 0x-prefixed bytes32 domain salt, empty = none
 }
 
 // QuoteConfig quote configuration
 type QuoteConfig struct {
-	ValidDuration time.Duration `yaml:"validDuration"` // Quote validity period
+	ValidDuration  time.Duration `yaml:"validDuration"`  // Longest validity of a signed quote, 0 = the requested deadline
+	StoreRetention time.Duration `yaml:"storeRetention"` // How long closed quotes are kept in the local quote store
+
+	// Latency budget of a quote request: the smaller of LatencyBudget and BudgetFraction of
+	// the time left to the request deadline. Requests not answered in time are rejected
+	LatencyBudget  time.Duration `yaml:"latencyBudget"`  // 0 = no fixed budget
+	BudgetFraction float64       `yaml:"budgetFraction"` // 0 = no deadline-derived budget
+
+	// Native <-> wrapped token requests (BNB <-> WBNB) are quoted 1:1 minus WrapFeeBps,
+	// without a pair or a strategy price
+	WrapConversion bool   `yaml:"wrapConversion"`
+	WrapFeeBps     uint32 `yaml:"wrapFeeBps"` // Fee taken from the output (basis points)
+
+	// Rounding of output amounts to the pair ticks: "down" always rounds in the MM's favor,
+	// "nearest" rounds half a tick up or down
+	Rounding string `yaml:"rounding"`
+
+	// File of signed quotes the operator has revoked, one "<nonce> <quoteId> [reason]" per line;
+	// re-read while running. Fills of revoked quotes are logged as alerts
+	RevocationFile string `yaml:"revocationFile"`
+
+	RateLimit RateLimitConfig `yaml:"rateLimit"` // Per-origin limit of quote requests
+
+	// Tolerance of deadline comparisons (request validation, quote expiry), so sub-second
+	// clock differences with the server do not reject requests or expire quotes early
+	DeadlineSkew time.Duration `yaml:"deadlineSkew"`
+
+	Nonce NonceConfig `yaml:"nonce"` // Nonces signed into quotes
+}
+
+// NonceConfig configures the nonces signed into quotes
+// Under "guard" and "issue", the highest nonce signed in each sequence persists in the state store.
+type NonceConfig struct {
+	Mode  string `yaml:"mode"`  // "request" (default): the request's nonce; "guard": the request's nonce if above every nonce signed before; "issue": nonces issued by the MM
+	Scope string `yaml:"scope"` // Sequence of each chain ("chain", default) or each RFQ manager ("pool")
+}
+
+// maxDeadlineSkew bounds quote.deadlineSkew: it absorbs clock differences, not late requests
+const maxDeadlineSkew = 5 * time.Second
+
+// RateLimitConfig per-origin limit of quote requests, keyed by the request recipient
+// Each origin has a token bucket of Burst requests refilled at PerSecond. An origin
+// rate-limited BanAfter times within BanWindow is rejected outright for BanDuration.
+type RateLimitConfig struct {
+	PerSecond   float64       `yaml:"perSecond"` // 0 = no limit
+	Burst       int           `yaml:"burst"`
+	BanAfter    int           `yaml:"banAfter"` // 0 = no bans
+	BanWindow   time.Duration `yaml:"banWindow"`
+	BanDuration time.Duration `yaml:"banDuration"`
 }
 
 // DepthConfig depth push configuration
 type DepthConfig struct {
 	Enabled      bool          `yaml:"enabled"`
 	PushInterval time.Duration `yaml:"pushInterval"`
+	BatchWrites  bool          `yaml:"batchWrites"` // Send all snapshots of a chain in one write burst
+
+	MaxConcurrency int           `yaml:"maxConcurrency"` // Pairs whose depth is fetched in parallel, per chain
+	MaxBackoff     time.Duration `yaml:"maxBackoff"`     // Longest pause of a chain whose provider fails for every pair
+
+	REST RESTDepthConfig `yaml:"rest"` // External HTTP depth source, replacing the strategy's provider
+
+	PriceFormat PriceFormatConfig `yaml:"priceFormat"`
+
+	// Sign every snapshot with the MM key (EIP-712), so the pool can later prove what depth
+	// was advertised; the signature travels in DepthSnapshot.attestation
+	Attest bool `yaml:"attest"`
+
+	Triggers DepthTriggersConfig `yaml:"triggers"`
+}
+
+// DepthTriggersConfig pushes of a pair between the periodic pushes
+// Fast markets are followed without lowering pushInterval for every pair
+type DepthTriggersConfig struct {
+	MidMoveBps    uint32        `yaml:"midMoveBps"`    // Push a pair whose mid price moved more than this since its last push (basis points), 0 = off
+	CheckInterval time.Duration `yaml:"checkInterval"` // How often the provider's mid prices are checked
+	MinInterval   time.Duration `yaml:"minInterval"`   // Minimum time between triggered pushes of a pair and its last push
+}
+
+// PriceFormatConfig formatting of the wei/wei prices of depth snapshots
+// Prices are always plain decimals, never in exponent notation. Rounding to fewer significant
+// digits rounds asks up and bids down, so the published book is never better than the provider's.
+type PriceFormatConfig struct {
+	SignificantDigits int  `yaml:"significantDigits"` // 0 = exact (every digit of the fixed-point price)
+	KeepTrailingZeros bool `yaml:"keepTrailingZeros"` // Pad prices to SignificantDigits digits
+}
+
+// RESTDepthConfig is an HTTP endpoint serving JSON order books, polled on every depth push
+type RESTDepthConfig struct {
+	URL     string            `yaml:"url"` // {chainId} and {pairId} are substituted; empty = the strategy's provider
+	Timeout time.Duration     `yaml:"timeout"`
+	Headers map[string]string `yaml:"headers"` // Sent with every request, e.g. Authorization
+	Mapping RESTMappingConfig `yaml:"mapping"`
+}
+
+// RESTMappingConfig locates the book in the endpoint's JSON
+// Paths are dot-separated object keys or array indexes, e.g. "data.book.bids" or "0"
+type RESTMappingConfig struct {
+	Bids      string `yaml:"bids"`      // Path of the bid levels array, best first
+	Asks      string `yaml:"asks"`      // Path of the ask levels array, best first
+	Price     string `yaml:"price"`     // Path of the price within a level
+	Amount    string `yaml:"amount"`    // Path of the base token amount within a level
+	Timestamp string `yaml:"timestamp"` // Path of the book time (unix milliseconds), empty = none
+	Units     string `yaml:"units"`     // "token": prices in quote per base token, amounts in base tokens; "wei": native units
+}
+
+// REST depth value units
+const (
+	UnitsToken = "token"
+	UnitsWei   = "wei"
+)
+
+// ConsistencyConfig depth/quote consistency check configuration
+// Amounts taken from the published depth are quoted by the strategy; quotes worse than the
+// book advertises by more than ToleranceBps are logged as alerts
+type ConsistencyConfig struct {
+	Enabled      bool          `yaml:"enabled"`
+	Interval     time.Duration `yaml:"interval"`     // Check interval
+	ToleranceBps uint32        `yaml:"toleranceBps"` // Accepted shortfall of a quote against the book (basis points)
+	Samples      int           `yaml:"samples"`      // Depth levels sampled per side, from the top to the bottom of the book
+}
+
+// OracleConfig sanity band of the published mid price
+// Each snapshot's mid price is compared with a reference price before it is published; a
+// snapshot further from the reference than MaxDeviationBps is not published and is logged
+// as an alert. Pairs with a Chainlink feed use it, the others the strategy's price (when the
+// strategy is a price feed).
+type OracleConfig struct {
+	Enabled          bool          `yaml:"enabled"`
+	MaxDeviationBps  uint32        `yaml:"maxDeviationBps"`  // Accepted distance of the mid price from the reference (basis points)
+	MaxAge           time.Duration `yaml:"maxAge"`           // References older than this are not used, 0 = no check
+	RequireReference bool          `yaml:"requireReference"` // Suppress snapshots without a usable reference price
+	Feeds            []OracleFeed  `yaml:"feeds"`
+}
+
+// OracleFeed is a Chainlink aggregator pricing the base token of a pair in its quote token
+type OracleFeed struct {
+	ChainID    uint64 `yaml:"chainId"`
+	PairID     string `yaml:"pairId"`
+	Aggregator string `yaml:"aggregator"` // Address of the AggregatorV3Interface contract
+	RPCURL     string `yaml:"rpcUrl"`     // JSON-RPC endpoint of the chain; empty = the rpcUrl of its eip712Domain
+}
+
+// StatusConfig periodic status report configuration
+type StatusConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"` // Report interval
+
+	// The session summary logged on shutdown is also written to this JSON file, empty = log only
+	SessionReport string `yaml:"sessionReport"`
+}
+
+// MockConfig mock strategy and depth provider configuration
+type MockConfig struct {
+	Seed      int64  `yaml:"seed"`      // RNG seed, 0 = time-based (the seed in use is logged at startup)
+	JitterBps uint32 `yaml:"jitterBps"` // Maximum random quote price deviation (basis points), 0 = fixed prices
+}
+
+// RecorderConfig session recording configuration
+type RecorderConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"` // JSON lines file, appended to
+}
+
+// FlowExportConfig anonymized RFQ flow export, for offline flow-toxicity analysis
+// A sample of quote requests is appended to a CSV file with coarse features only: the
+// recipient is an HMAC of the address, amounts are order-of-magnitude buckets, quote IDs are dropped.
+type FlowExportConfig struct {
+	Enabled    bool    `yaml:"enabled"`
+	File       string  `yaml:"file"`       // CSV file, appended to
+	SampleRate float64 `yaml:"sampleRate"` // Share of quote requests exported, 0 < rate <= 1 (default 1)
+	Salt       string  `yaml:"salt"`       // HMAC key of recipient hashes; empty = saltEnv, then a random key per run
+	SaltEnv    string  `yaml:"saltEnv"`    // Or the environment variable holding it
+}
+
+// GetSalt returns the recipient hash key, from the config file or SaltEnv; empty if neither is set
+func (f *FlowExportConfig) GetSalt() string {
+	if f.Salt != "" {
+		return f.Salt
+	}
+	if f.SaltEnv != "" {
+		return strings.TrimSpace(os.Getenv(f.SaltEnv))
+	}
+	return ""
+}
+
+// StoreConfig embedded state store configuration
+// The store journals signed quotes and keeps counters such as depth attestation sequences.
+type StoreConfig struct {
+	Backend string `yaml:"backend"` // "memory" (default, lost on restart), "file", "sqlite" or "badger"
+	Path    string `yaml:"path"`    // Directory of the file, sqlite and badger backends
+}
+
+// StableConfig stable-pair quoting configuration
+// Listed pairs are quoted near 1:1 instead of by the strategy, which quotes every other pair
+type StableConfig struct {
+	Pairs           []string `yaml:"pairs"`           // Pair IDs of stable or correlated pairs
+	FeeBps          uint32   `yaml:"feeBps"`          // Fee taken from the parity output (basis points)
+	MaxDeviationBps uint32   `yaml:"maxDeviationBps"` // Depeg guard: reject when the strategy's price is further from 1:1, 0 = disabled
+}
+
+// SyntheticConfig synthetic cross-pair pricing configuration
+// Routed pairs are priced by composing the strategy's prices of each leg
+type SyntheticConfig struct {
+	SpreadBps uint32           `yaml:"spreadBps"` // Spread applied to the composed price (basis points)
+	MaxAge    time.Duration    `yaml:"maxAge"`    // Reject when the oldest leg price is older, 0 = no check
+	MaxSkew   time.Duration    `yaml:"maxSkew"`   // Reject when leg prices are further apart in time, 0 = no check
+	Routes    []SyntheticRoute `yaml:"routes"`
+}
+
+// SyntheticRoute prices a configured pair through intermediate tokens
+// Example: WBNB-USDC via [USDT] composes WBNB/USDT x USDT/USDC
+type SyntheticRoute struct {
+	PairID string   `yaml:"pairId"` // Configured pair priced by the route
+	Via    []string `yaml:"via"`    // Intermediate token addresses, in order from base to quote token
+}
+
+// MidConfig liquidity-weighted mid prices of pairs traded on several pools or venues
+// The weighted mid prices the pair's quotes and recenters its depth, so both agree.
+type MidConfig struct {
+	Refresh time.Duration `yaml:"refresh"` // Sources are read at most this often per pair
+	Pairs   []MidPair     `yaml:"pairs"`
+}
+
+// MidPair is a pair whose mid is weighted over several sources
+type MidPair struct {
+	ChainID    uint64      `yaml:"chainId"`
+	PairID     string      `yaml:"pairId"`
+	SpreadBps  uint32      `yaml:"spreadBps"`  // Spread taken from quotes at the weighted mid (basis points)
+	MinSources int         `yaml:"minSources"` // Fewer usable sources = no price, 0 = 1
+	Sources    []MidSource `yaml:"sources"`
+}
+
+// Mid source types
+const (
+	MidSourceUniswapV2 = "uniswapV2" // Pair contract reserves
+	MidSourceUniswapV3 = "uniswapV3" // Pool price and in-range liquidity
+	MidSourceStrategy  = "strategy"  // The strategy's own price; needs a weight
+)
+
+// MidSource is a pool or venue the mid of a pair is weighted over
+type MidSource struct {
+	Name   string  `yaml:"name"`   // Label in logs and metrics
+	Type   string  `yaml:"type"`   // uniswapV2, uniswapV3 or strategy
+	Pool   string  `yaml:"pool"`   // Pool address of uniswapV2 and uniswapV3 sources
+	RPCURL string  `yaml:"rpcUrl"` // JSON-RPC endpoint of the chain; empty = the rpcUrl of its eip712Domain
+	Weight float64 `yaml:"weight"` // Fixed weight; 0 = the pool's liquidity in quote token units
+}
+
+// ScheduleConfig spread and size schedule configuration
+// Multipliers of every active window, event and the current volatility regime are multiplied
+// together and applied to the strategy and depth provider. A multiplier of 0 means 1 (unchanged).
+type ScheduleConfig struct {
+	Enabled  bool             `yaml:"enabled"`
+	Interval time.Duration    `yaml:"interval"` // How often the schedule is evaluated
+	Windows  []ScheduleWindow `yaml:"windows"`
+	Events   []ScheduleEvent  `yaml:"events"`
+	Regimes  RegimeConfig     `yaml:"regimes"`
+}
+
+// ScheduleWindow is a recurring daily time window (UTC)
+type ScheduleWindow struct {
+	Name             string   `yaml:"name"`
+	Start            string   `yaml:"start"` // "HH:MM" UTC
+	End              string   `yaml:"end"`   // "HH:MM" UTC, before Start to wrap past midnight
+	Days             []string `yaml:"days"`  // Weekdays the window starts on ("mon".."sun"), empty = every day
+	SpreadMultiplier float64  `yaml:"spreadMultiplier"`
+	SizeMultiplier   float64  `yaml:"sizeMultiplier"`
+}
+
+// ScheduleEvent is a one-off event such as a macro announcement
+type ScheduleEvent struct {
+	Name             string        `yaml:"name"`
+	At               time.Time     `yaml:"at"`     // RFC 3339
+	Before           time.Duration `yaml:"before"` // Active from At-Before
+	After            time.Duration `yaml:"after"`  // until At+After
+	SpreadMultiplier float64       `yaml:"spreadMultiplier"`
+	SizeMultiplier   float64       `yaml:"sizeMultiplier"`
+}
+
+// RegimeConfig volatility regime detection
+// Volatility is the price range of a pair over Window ((max-min)/min, basis points),
+// sampled from the strategy's prices; the highest level reached by any pair applies
+type RegimeConfig struct {
+	Window time.Duration `yaml:"window"`
+	Levels []RegimeLevel `yaml:"levels"`
+}
+
+// RegimeLevel applies from a volatility threshold upwards
+type RegimeLevel struct {
+	MinVolatilityBps uint32  `yaml:"minVolatilityBps"`
+	SpreadMultiplier float64 `yaml:"spreadMultiplier"`
+	SizeMultiplier   float64 `yaml:"sizeMultiplier"`
+}
+
+// ProfilingConfig profiling watchdog configuration
+// When a threshold is crossed, CPU, heap and goroutine profiles are written to Dir
+type ProfilingConfig struct {
+	Enabled        bool          `yaml:"enabled"`
+	Dir            string        `yaml:"dir"`            // Output directory, created if missing
+	CheckInterval  time.Duration `yaml:"checkInterval"`  // How often the thresholds are checked
+	LatencyP99     time.Duration `yaml:"latencyP99"`     // Quote p99 latency threshold, 0 = disabled
+	SendQueueDepth int           `yaml:"sendQueueDepth"` // Outbound queue depth threshold, 0 = disabled
+	CPUDuration    time.Duration `yaml:"cpuDuration"`    // Length of the CPU profile
+	Cooldown       time.Duration `yaml:"cooldown"`       // Minimum time between two captures
+}
+
+// MetricsConfig Prometheus metrics endpoint configuration
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Listen  string `yaml:"listen"` // host:port of the /metrics endpoint
+}
+
+// QueryConfig read-only query API configuration
+// The API only serves GET endpoints; it is kept apart from anything that changes MM state.
+type QueryConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Listen  string `yaml:"listen"` // host:port of the /api/v1 endpoints
+}
+
+// AdminConfig admin API configuration
+// The admin API changes MM parameters while running, so every request needs the bearer token
+// and every change is logged and audited. It listens apart from the read-only query API.
+type AdminConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Listen    string `yaml:"listen"`    // host:port of the /admin/v1 endpoints
+	Token     string `yaml:"token"`     // Bearer token of every request
+	TokenEnv  string `yaml:"tokenEnv"`  // Or the environment variable holding it
+	AuditFile string `yaml:"auditFile"` // JSON lines file every change is appended to, empty = log only
+}
+
+// GetToken returns the bearer token, from the config file or TokenEnv
+func (a *AdminConfig) GetToken() string {
+	if a.Token != "" {
+		return a.Token
+	}
+	if a.TokenEnv != "" {
+		return strings.TrimSpace(os.Getenv(a.TokenEnv))
+	}
+	return ""
+}
+
+// WebhookConfig outbound webhook configuration
+// Events are POSTed as JSON to every endpoint subscribed to their kind, signed with the
+// endpoint's HMAC secret and retried with exponential backoff.
+type WebhookConfig struct {
+	Endpoints    []WebhookEndpoint `yaml:"endpoints"`
+	LargeQuotes  map[string]string `yaml:"largeQuotes"`  // Pair ID -> notional in quote token units at or above which a signed quote is a large_quote
+	Timeout      time.Duration     `yaml:"timeout"`      // Per delivery attempt
+	MaxAttempts  int               `yaml:"maxAttempts"`  // Attempts per event, including the first
+	RetryBackoff time.Duration     `yaml:"retryBackoff"` // Wait before the first retry, doubled on each further retry
+}
+
+// LoggingConfig log shipping configuration
+// Records still go to stdout and logs/mm.log; each sink gets a copy.
+type LoggingConfig struct {
+	Sinks []LogSinkConfig `yaml:"sinks"`
+}
+
+// LogSinkConfig is a destination log records are shipped to
+type LogSinkConfig struct {
+	Type    string `yaml:"type"`    // "syslog", "journald" or "tcp" (JSON lines, e.g. a Vector socket source)
+	Address string `yaml:"address"` // syslog: udp://, tcp:// or tls://host:port, empty = local daemon; journald: socket path; tcp: host:port
+	TLS     bool   `yaml:"tls"`     // tcp: connect with TLS
+	Tag     string `yaml:"tag"`     // syslog/journald program identifier, empty = app.name
+	Level   string `yaml:"level"`   // Minimum level shipped: debug, info (default), warn or error
+}
+
+// SlogLevel returns the parsed level of the sink
+func (s LogSinkConfig) SlogLevel() slog.Level {
+	var level slog.Level
+	if s.Level != "" {
+		_ = level.UnmarshalText([]byte(s.Level))
+	}
+	return level
+}
+
+// WebhookEndpoint is a URL receiving webhook events
+type WebhookEndpoint struct {
+	URL       string   `yaml:"url"`
+	Secret    string   `yaml:"secret"`    // HMAC-SHA256 key of the X-MM-Signature header
+	SecretEnv string   `yaml:"secretEnv"` // Or the environment variable holding it
+	Events    []string `yaml:"events"`    // Event kinds delivered, empty = all
+}
+
+// WebhookEvents are the event kinds a webhook endpoint can subscribe to
+var WebhookEvents = []string{
+	"quote_signed", "large_quote", "quote_rejected", "fill_observed", "connection_state_changed", "risk_breach",
+	"signer_failover", "signer_rotated",
+}
+
+// InstanceConfig is one MM identity of a multi-instance process
+// Every instance has its own signer, connection and handlers; the rest of the config, metrics,
+// chain clients and price feeds are shared. Empty fields are taken from the top-level config.
+type InstanceConfig struct {
+	Name string `yaml:"name"` // Identifies the instance in logs, metrics and state paths
+
+	// The instance's MM identity, signing key pool and backup key, like signer; the deadline and health
+	// check settings of signer apply to every instance
+	PrivateKey    string             `yaml:"privateKey"`
+	PrivateKeyEnv string             `yaml:"privateKeyEnv"`
+	Pool          SignerPoolConfig   `yaml:"pool"`
+	Backup        SignerBackupConfig `yaml:"backup"`
+
+	ServerURL string   `yaml:"serverUrl"` // Empty = websocket.serverUrl
+	APIToken  string   `yaml:"apiToken"`  // Empty = websocket.apiToken
+	Pairs     []string `yaml:"pairs"`     // Pair IDs the instance quotes, empty = all
+}
+
+// StrategyConfig quote strategy and depth provider selection
+type StrategyConfig struct {
+	Name   string    `yaml:"name"`   // Registered strategy name (default: mock)
+	Params yaml.Node `yaml:"params"` // Strategy-specific settings, decoded by the strategy
+}
+
+// ShadowConfig shadow-mode strategy comparison configuration
+// The candidate strategy quotes every RFQ alongside the live one; its quotes are logged
+// and compared with the live quotes, never signed
+type ShadowConfig struct {
+	Enabled     bool           `yaml:"enabled"`
+	Strategy    StrategyConfig `yaml:"strategy"`    // Candidate strategy, selected like strategy
+	Timeout     time.Duration  `yaml:"timeout"`     // Max time of a candidate quote
+	MaxInFlight int            `yaml:"maxInFlight"` // Candidate quotes running at once; further RFQs are not shadowed
+}
+
+// CoSignConfig co-signing configuration
+// Quotes whose notional exceeds the threshold of their pair are released only after a co-signer
+// service signs the same EIP-712 digest with Address; otherwise they are rejected
+type CoSignConfig struct {
+	Enabled    bool              `yaml:"enabled"`
+	URL        string            `yaml:"url"`        // Co-signer service endpoint
+	Address    string            `yaml:"address"`    // Address the approval signature must recover to
+	Timeout    time.Duration     `yaml:"timeout"`    // Max time of an approval, within the quote latency budget
+	Thresholds map[string]string `yaml:"thresholds"` // Pair ID -> notional in quote token units; unlisted pairs need no approval
+}
+
+// ChainConfig per-chain settings
+// Pausing a chain isolates an incident on it (bad RPC, paused contract) from the other
+// chains; the flags can also be switched through the admin API
+type ChainConfig struct {
+	ChainID      uint64 `yaml:"chainId"`
+	PauseQuoting bool   `yaml:"pauseQuoting"` // Reject the chain's quote requests
+	PauseDepth   bool   `yaml:"pauseDepth"`   // Push no depth for the chain's pairs
+
+	// Chain registry entry (internal/chains), replacing the built-in values; empty = built-in
+	Name           string `yaml:"name"`           // Short name, e.g. "bsc"
+	WrappedNative  string `yaml:"wrappedNative"`  // Token quoted for the native token (zero address)
+	Explorer       string `yaml:"explorer"`       // Block explorer base URL, e.g. "https://bscscan.com"
+	NativeDecimals uint8  `yaml:"nativeDecimals"` // Decimals of the native token, 0 = built-in or 18
 }
 
 // PairConfig trading pair configuration
@@ -86,6 +811,66 @@ type PairConfig struct {
 	BaseTokenDecimals  int    `yaml:"baseTokenDecimals"`
 	QuoteTokenDecimals int    `yaml:"quoteTokenDecimals"`
 	FeeRate            uint32 `yaml:"feeRate"` // Fee rate (basis points)
+
+	// Spread taken over the strategy price (basis points): quotes give that much less output,
+	// and published asks and bids are widened by it. Adjustable through the admin API
+	SpreadBps uint32 `yaml:"spreadBps"`
+
+	// Output amounts are rounded to a multiple of the tick of the output token (token units,
+	// e.g. "0.0001"), following Quote.Rounding. Empty = 1 wei, no rounding
+	BaseTick  string `yaml:"baseTick"`
+	QuoteTick string `yaml:"quoteTick"`
+
+	// Largest accepted amount_in of each token (token units, e.g. "50"); larger RFQs are
+	// rejected and the published depth is capped to match. Empty = unlimited
+	MaxBaseIn  string `yaml:"maxBaseIn"`
+	MaxQuoteIn string `yaml:"maxQuoteIn"`
+
+	// Smallest accepted amount_in of each token (token units); smaller RFQs are rejected with
+	// AMOUNT_TOO_SMALL. Empty = no minimum
+	MinBaseIn  string `yaml:"minBaseIn"`
+	MinQuoteIn string `yaml:"minQuoteIn"`
+
+	// Bounds of the notional of a quote in quote token units (see quote.Notional): the quoted
+	// amount_out when selling the base token, amount_in otherwise. Empty = unbounded
+	MinNotional string `yaml:"minNotional"`
+	MaxNotional string `yaml:"maxNotional"`
+
+	// extraData signed into the pair's quotes: empty, or "v3-pool-hedge" for the hedge of the
+	// quote on the Uniswap V3 pool HedgePool (signer.ExtraDataParams)
+	ExtraData string `yaml:"extraData"`
+	HedgePool string `yaml:"hedgePool"`
+}
+
+// MaxAmountsIn returns the largest accepted amount_in of the base and quote token in native
+// units, nil = unlimited; limits that fail config validation are treated as unlimited
+func (p *PairConfig) MaxAmountsIn() (base, quote *big.Int) {
+	base, _ = TickWei(p.MaxBaseIn, p.BaseTokenDecimals)
+	quote, _ = TickWei(p.MaxQuoteIn, p.QuoteTokenDecimals)
+	return base, quote
+}
+
+// MinAmountsIn returns the smallest accepted amount_in of the base and quote token in native
+// units, nil = no minimum; limits that fail config validation are treated as no minimum
+func (p *PairConfig) MinAmountsIn() (base, quote *big.Int) {
+	base, _ = TickWei(p.MinBaseIn, p.BaseTokenDecimals)
+	quote, _ = TickWei(p.MinQuoteIn, p.QuoteTokenDecimals)
+	return base, quote
+}
+
+// NotionalBounds returns the smallest and largest accepted notional in quote token units,
+// nil = unbounded; bounds that fail config validation are treated as unbounded
+func (p *PairConfig) NotionalBounds() (lo, hi *decimal.Decimal) {
+	return parseNotional(p.MinNotional), parseNotional(p.MaxNotional)
+}
+
+// parseNotional parses a positive notional, nil if empty or invalid
+func parseNotional(s string) *decimal.Decimal {
+	d, err := decimal.Parse(s)
+	if s == "" || err != nil || d.Sign() <= 0 {
+		return nil
+	}
+	return &d
 }
 
 // Load loads configuration from file
@@ -108,6 +893,7 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
 
+	cfg.BuildIndex()
 	return &cfg, nil
 }
 
@@ -131,12 +917,187 @@ func (c *Config) setDefaults() {
 	if c.WebSocket.WriteTimeout == 0 {
 		c.WebSocket.WriteTimeout = 10 * time.Second
 	}
-	if c.Quote.ValidDuration == 0 {
-		c.Quote.ValidDuration = 30 * time.Second
+	if c.WebSocket.Bandwidth.Window == 0 {
+		c.WebSocket.Bandwidth.Window = time.Minute
+	}
+	if c.WebSocket.Bandwidth.Reserve == 0 {
+		c.WebSocket.Bandwidth.Reserve = 0.2
+	}
+	if c.WebSocket.Dialer.HandshakeTimeout == 0 {
+		c.WebSocket.Dialer.HandshakeTimeout = 10 * time.Second
+	}
+	if c.WebSocket.ReconnectStorm.Window == 0 {
+		c.WebSocket.ReconnectStorm.Window = 5 * time.Minute
+	}
+	if c.WebSocket.ReconnectStorm.CoolOff == 0 {
+		c.WebSocket.ReconnectStorm.CoolOff = 10 * time.Minute
+	}
+	if c.Quote.StoreRetention == 0 {
+		c.Quote.StoreRetention = 10 * time.Minute
+	}
+	if c.Quote.LatencyBudget == 0 {
+		c.Quote.LatencyBudget = 200 * time.Millisecond
+	}
+	if c.Quote.BudgetFraction == 0 {
+		c.Quote.BudgetFraction = 0.5
+	}
+	if c.Quote.Rounding == "" {
+		c.Quote.Rounding = RoundDown
+	}
+	if c.Quote.RateLimit.PerSecond > 0 && c.Quote.RateLimit.Burst == 0 {
+		c.Quote.RateLimit.Burst = max(1, int(math.Ceil(c.Quote.RateLimit.PerSecond)))
+	}
+	if c.Quote.Nonce.Mode == "" {
+		c.Quote.Nonce.Mode = nonce.ModeRequest
+	}
+	if c.Quote.Nonce.Scope == "" {
+		c.Quote.Nonce.Scope = nonce.ScopeChain
+	}
+	if c.Quote.RateLimit.BanWindow == 0 {
+		c.Quote.RateLimit.BanWindow = time.Minute
+	}
+	if c.Quote.RateLimit.BanDuration == 0 {
+		c.Quote.RateLimit.BanDuration = 5 * time.Minute
 	}
 	if c.Depth.PushInterval == 0 {
 		c.Depth.PushInterval = 3 * time.Second
 	}
+	if c.Depth.MaxConcurrency == 0 {
+		c.Depth.MaxConcurrency = 4
+	}
+	if c.Depth.MaxBackoff == 0 {
+		c.Depth.MaxBackoff = time.Minute
+	}
+	if c.Depth.Triggers.CheckInterval == 0 {
+		c.Depth.Triggers.CheckInterval = 500 * time.Millisecond
+	}
+	if c.Depth.Triggers.MinInterval == 0 {
+		c.Depth.Triggers.MinInterval = 250 * time.Millisecond
+	}
+	if c.Depth.REST.Timeout == 0 {
+		c.Depth.REST.Timeout = 2 * time.Second
+	}
+	if m := &c.Depth.REST.Mapping; m.Bids == "" && m.Asks == "" {
+		m.Bids, m.Asks = "bids", "asks"
+	}
+	if m := &c.Depth.REST.Mapping; m.Price == "" && m.Amount == "" {
+		m.Price, m.Amount = "price", "amount"
+	}
+	if c.Depth.REST.Mapping.Units == "" {
+		c.Depth.REST.Mapping.Units = UnitsToken
+	}
+	if c.Consistency.Interval == 0 {
+		c.Consistency.Interval = time.Minute
+	}
+	if c.Consistency.ToleranceBps == 0 {
+		c.Consistency.ToleranceBps = 20
+	}
+	if c.Consistency.Samples == 0 {
+		c.Consistency.Samples = 3
+	}
+	if c.Oracle.MaxDeviationBps == 0 {
+		c.Oracle.MaxDeviationBps = 500
+	}
+	if c.Status.Interval == 0 {
+		c.Status.Interval = time.Minute
+	}
+	if c.Signer.HealthCheckInterval == 0 {
+		c.Signer.HealthCheckInterval = time.Minute
+	}
+	setBackupDefaults(&c.Signer.Backup)
+	if c.Signer.Vault.Mount == "" {
+		c.Signer.Vault.Mount = "secret"
+	}
+	if c.Signer.Vault.Field == "" {
+		c.Signer.Vault.Field = "privateKey"
+	}
+	if c.Signer.Vault.AppRole.Mount == "" {
+		c.Signer.Vault.AppRole.Mount = "approle"
+	}
+	if c.Signer.Vault.Timeout == 0 {
+		c.Signer.Vault.Timeout = 10 * time.Second
+	}
+	if c.Signer.Remote.Timeout == 0 {
+		c.Signer.Remote.Timeout = 2 * time.Second
+	}
+	if c.Signer.Remote.Retries == 0 {
+		c.Signer.Remote.Retries = 2
+	}
+	if c.Signer.ContractWallet.Format == "" {
+		c.Signer.ContractWallet.Format = "safe"
+	}
+	if c.Signer.Ledger.DerivationPath == "" {
+		c.Signer.Ledger.DerivationPath = "m/44'/60'/0'/0/0"
+	}
+	if c.Signer.Ledger.Timeout == 0 {
+		c.Signer.Ledger.Timeout = time.Minute
+	}
+	for i := range c.Instances {
+		setBackupDefaults(&c.Instances[i].Backup)
+	}
+	if c.Recorder.Path == "" {
+		c.Recorder.Path = "logs/session.jsonl"
+	}
+	if c.Mid.Refresh == 0 {
+		c.Mid.Refresh = time.Second
+	}
+	if c.FlowExport.File == "" {
+		c.FlowExport.File = "logs/flow.csv"
+	}
+	if c.FlowExport.SampleRate == 0 {
+		c.FlowExport.SampleRate = 1
+	}
+	if c.Store.Backend == "" {
+		c.Store.Backend = "memory"
+	}
+	if c.Store.Path == "" {
+		c.Store.Path = "data/state"
+	}
+	if c.Strategy.Name == "" {
+		c.Strategy.Name = "mock"
+	}
+	if c.Schedule.Interval == 0 {
+		c.Schedule.Interval = 30 * time.Second
+	}
+	if c.Schedule.Regimes.Window == 0 {
+		c.Schedule.Regimes.Window = 5 * time.Minute
+	}
+	if c.Shadow.Timeout == 0 {
+		c.Shadow.Timeout = time.Second
+	}
+	if c.Shadow.MaxInFlight == 0 {
+		c.Shadow.MaxInFlight = 16
+	}
+	if c.CoSign.Timeout == 0 {
+		c.CoSign.Timeout = 100 * time.Millisecond
+	}
+	if c.Profiling.Dir == "" {
+		c.Profiling.Dir = "logs/profiles"
+	}
+	if c.Profiling.CheckInterval == 0 {
+		c.Profiling.CheckInterval = 5 * time.Second
+	}
+	if c.Profiling.CPUDuration == 0 {
+		c.Profiling.CPUDuration = 5 * time.Second
+	}
+	if c.Profiling.Cooldown == 0 {
+		c.Profiling.Cooldown = 5 * time.Minute
+	}
+	if c.Metrics.Listen == "" {
+		c.Metrics.Listen = "127.0.0.1:9464"
+	}
+	if c.Query.Listen == "" {
+		c.Query.Listen = "127.0.0.1:9465"
+	}
+	if c.Webhook.Timeout == 0 {
+		c.Webhook.Timeout = 5 * time.Second
+	}
+	if c.Webhook.MaxAttempts == 0 {
+		c.Webhook.MaxAttempts = 5
+	}
+	if c.Webhook.RetryBackoff == 0 {
+		c.Webhook.RetryBackoff = time.Second
+	}
 }
 
 // Validate validates configuration
@@ -144,39 +1105,719 @@ func (c *Config) Validate() error {
 	if c.WebSocket.ServerURL == "" {
 		return fmt.Errorf("websocket.serverUrl is required")
 	}
-	if c.WebSocket.APIToken == "" {
+	if c.WebSocket.APIToken == "" && len(c.Instances) == 0 {
 		return fmt.Errorf("websocket.apiToken is required")
 	}
-	if len(c.EIP712Domains) == 0 {
-		return fmt.Errorf("at least one eip712Domain is required")
+	if c.WebSocket.Standby.ServerURL == c.WebSocket.ServerURL {
+		return fmt.Errorf("websocket.standby.serverUrl must differ from websocket.serverUrl")
 	}
-	for i, domain := range c.EIP712Domains {
-		if domain.ChainID == 0 {
-			return fmt.Errorf("eip712Domains[%d].chainId is required", i)
+	if len(c.WebSocket.TLSPins) > 0 {
+		if _, err := ws.ParsePins(c.WebSocket.TLSPins); err != nil {
+			return fmt.Errorf("websocket.tlsPins: %w", err)
 		}
-		if domain.VerifyingContract == "" {
+		for _, url := range []string{c.WebSocket.ServerURL, c.WebSocket.Standby.ServerURL} {
+			if url != "" && !strings.HasPrefix(url, "wss://") {
+				return fmt.Errorf("websocket.tlsPins requires wss:// server URLs, got %s", url)
+			}
+		}
+	}
+	if d := c.WebSocket.Dialer; d.HandshakeTimeout < 0 || d.TLSSessionCache < 0 || d.DNSRecheckInterval < 0 {
+		return fmt.Errorf("websocket.dialer: handshakeTimeout, tlsSessionCache and dnsRecheckInterval must not be negative")
+	}
+	if c.WebSocket.EventHistory < 0 {
+		return fmt.Errorf("websocket.eventHistory must not be negative")
+	}
+	if storm := c.WebSocket.ReconnectStorm; storm.MaxAttempts < 0 || storm.Window < 0 || storm.CoolOff < 0 {
+		return fmt.Errorf("websocket.reconnectStorm values must not be negative")
+	}
+	if bw := c.WebSocket.Bandwidth; bw.MaxOutboundBytes < 0 || bw.Window < 0 || bw.Reserve < 0 || bw.Reserve >= 1 {
+		return fmt.Errorf("websocket.bandwidth: maxOutboundBytes and window must not be negative, reserve must be in [0, 1)")
+	}
+	if len(c.EIP712Domains) == 0 {
+		return fmt.Errorf("at least one eip712Domain is required")
+	}
+	if c.Depth.MaxBackoff < 0 {
+		return fmt.Errorf("depth.maxBackoff must not be negative")
+	}
+	if c.Depth.Triggers.MidMoveBps > 0 && c.Depth.Triggers.CheckInterval <= 0 {
+		return fmt.Errorf("depth.triggers.checkInterval must be positive")
+	}
+	if c.Depth.Triggers.MinInterval < 0 {
+		return fmt.Errorf("depth.triggers.minInterval must not be negative")
+	}
+	if c.Depth.PriceFormat.SignificantDigits < 0 {
+		return fmt.Errorf("depth.priceFormat.significantDigits must not be negative")
+	}
+	if c.Depth.PriceFormat.KeepTrailingZeros && c.Depth.PriceFormat.SignificantDigits == 0 {
+		return fmt.Errorf("depth.priceFormat.keepTrailingZeros requires significantDigits")
+	}
+	if rest := c.Depth.REST; rest.URL != "" {
+		if !strings.HasPrefix(rest.URL, "http://") && !strings.HasPrefix(rest.URL, "https://") {
+			return fmt.Errorf("depth.rest.url must be an http:// or https:// URL")
+		}
+		if rest.Timeout <= 0 {
+			return fmt.Errorf("depth.rest.timeout must be positive")
+		}
+		if m := rest.Mapping; m.Bids == "" || m.Asks == "" || m.Price == "" || m.Amount == "" {
+			return fmt.Errorf("depth.rest.mapping: bids, asks, price and amount are required")
+		}
+		if u := rest.Mapping.Units; u != UnitsToken && u != UnitsWei {
+			return fmt.Errorf("depth.rest.mapping.units must be %q or %q", UnitsToken, UnitsWei)
+		}
+	}
+	if c.Metrics.Enabled {
+		if _, _, err := net.SplitHostPort(c.Metrics.Listen); err != nil {
+			return fmt.Errorf("metrics.listen: %w", err)
+		}
+	}
+	if c.Query.Enabled {
+		if _, _, err := net.SplitHostPort(c.Query.Listen); err != nil {
+			return fmt.Errorf("query.listen: %w", err)
+		}
+		if c.Metrics.Enabled && c.Query.Listen == c.Metrics.Listen {
+			return fmt.Errorf("query.listen must differ from metrics.listen")
+		}
+	}
+	if c.Admin.Enabled {
+		if _, _, err := net.SplitHostPort(c.Admin.Listen); err != nil {
+			return fmt.Errorf("admin.listen: %w", err)
+		}
+		if (c.Metrics.Enabled && c.Admin.Listen == c.Metrics.Listen) || (c.Query.Enabled && c.Admin.Listen == c.Query.Listen) {
+			return fmt.Errorf("admin.listen must differ from metrics.listen and query.listen")
+		}
+		if c.Admin.Token == "" && c.Admin.TokenEnv == "" {
+			return fmt.Errorf("admin: token or tokenEnv is required")
+		}
+	}
+	for i, domain := range c.EIP712Domains {
+		if domain.ChainID == 0 {
+			return fmt.Errorf("eip712Domains[%d].chainId is required", i)
+		}
+		if domain.VerifyingContract == "" {
 			return fmt.Errorf("eip712Domains[%d].verifyingContract is required", i)
 		}
+		if _, err := address.ParseNonZero(domain.VerifyingContract); err != nil {
+			return fmt.Errorf("eip712Domains[%d].verifyingContract: %w", i, err)
+		}
+		if domain.QuoteType != "" {
+			if _, err := signer.ParseQuoteType(domain.QuoteType); err != nil {
+				return fmt.Errorf("eip712Domains[%d].quoteType: %w", i, err)
+			}
+		}
+		if _, _, err := signer.ParseDomainFields(domain.Fields, domain.Salt); err != nil {
+			return fmt.Errorf("eip712Domains[%d]: %w", i, err)
+		}
+	}
+	if c.Quote.BudgetFraction < 0 || c.Quote.BudgetFraction > 1 {
+		return fmt.Errorf("quote.budgetFraction must be between 0 and 1")
+	}
+	if c.Quote.WrapFeeBps >= 10000 {
+		return fmt.Errorf("quote.wrapFeeBps must be below 10000")
+	}
+	if c.Quote.Rounding != "" && c.Quote.Rounding != RoundDown && c.Quote.Rounding != RoundNearest {
+		return fmt.Errorf("quote.rounding must be %q or %q", RoundDown, RoundNearest)
+	}
+	if c.Quote.DeadlineSkew < 0 || c.Quote.DeadlineSkew > maxDeadlineSkew {
+		return fmt.Errorf("quote.deadlineSkew must be between 0 and %v", maxDeadlineSkew)
+	}
+	switch c.Quote.Nonce.Mode {
+	case "", nonce.ModeRequest, nonce.ModeGuard, nonce.ModeIssue:
+	default:
+		return fmt.Errorf("quote.nonce.mode must be %q, %q or %q", nonce.ModeRequest, nonce.ModeGuard, nonce.ModeIssue)
+	}
+	if scope := c.Quote.Nonce.Scope; scope != "" && scope != nonce.ScopeChain && scope != nonce.ScopePool {
+		return fmt.Errorf("quote.nonce.scope must be %q or %q", nonce.ScopeChain, nonce.ScopePool)
+	}
+	// The guard refuses nonces at or below the highest signed one: forgetting it on restart
+	// would let a replayed request be signed again
+	if c.Quote.Nonce.Mode == nonce.ModeGuard && !store.Persistent(c.Store.Backend) {
+		return fmt.Errorf("quote.nonce.mode %q needs a persistent store.backend, got %q", nonce.ModeGuard, c.Store.Backend)
+	}
+	if rl := c.Quote.RateLimit; rl.PerSecond < 0 || rl.Burst < 0 || rl.BanAfter < 0 || rl.BanWindow < 0 || rl.BanDuration < 0 {
+		return fmt.Errorf("quote.rateLimit values must not be negative")
+	}
+	for i, pair := range c.Pairs {
+		if _, err := address.ParseNonZero(pair.BaseToken); err != nil {
+			return fmt.Errorf("pairs[%d].baseToken: %w", i, err)
+		}
+		if _, err := address.ParseNonZero(pair.QuoteToken); err != nil {
+			return fmt.Errorf("pairs[%d].quoteToken: %w", i, err)
+		}
+		switch pair.ExtraData {
+		case signer.ExtraDataNone:
+		case signer.ExtraDataV3PoolHedge:
+			if _, err := address.ParseNonZero(pair.HedgePool); err != nil {
+				return fmt.Errorf("pairs[%d].hedgePool: %w", i, err)
+			}
+		default:
+			return fmt.Errorf("pairs[%d].extraData must be empty or %q, got %q", i, signer.ExtraDataV3PoolHedge, pair.ExtraData)
+		}
+		if err := validateTick(pair.BaseTick, pair.BaseTokenDecimals); err != nil {
+			return fmt.Errorf("pairs[%d].baseTick: %w", i, err)
+		}
+		if err := validateTick(pair.QuoteTick, pair.QuoteTokenDecimals); err != nil {
+			return fmt.Errorf("pairs[%d].quoteTick: %w", i, err)
+		}
+		params := PairParams{SpreadBps: pair.SpreadBps, MaxBaseIn: pair.MaxBaseIn, MaxQuoteIn: pair.MaxQuoteIn}
+		if err := params.Validate(&c.Pairs[i]); err != nil {
+			return fmt.Errorf("pairs[%d].%w", i, err)
+		}
+		if err := c.Pairs[i].validateBounds(); err != nil {
+			return fmt.Errorf("pairs[%d].%w", i, err)
+		}
+	}
+	if c.Signer.MaxDeadlineHorizon < 0 {
+		return fmt.Errorf("signer.maxDeadlineHorizon must not be negative")
+	}
+	if c.Signer.HealthCheckInterval < 0 {
+		return fmt.Errorf("signer.healthCheckInterval must not be negative")
+	}
+	if err := c.Signer.Pool.validate(); err != nil {
+		return err
+	}
+	if err := c.Signer.validateBackup("signer.backup"); err != nil {
+		return err
+	}
+	if err := c.Signer.Vault.validate(); err != nil {
+		return err
+	}
+	if c.Signer.Ledger.Timeout < 0 {
+		return fmt.Errorf("signer.ledger.timeout must not be negative")
+	}
+	for chainID, key := range c.Signer.ChainKeys {
+		if c.GetEIP712Domain(chainID) == nil {
+			return fmt.Errorf("signer.chainKeys[%d]: chain not configured in eip712Domains", chainID)
+		}
+		if key.PrivateKey == "" && key.PrivateKeyEnv == "" {
+			return fmt.Errorf("signer.chainKeys[%d]: privateKey or privateKeyEnv is required", chainID)
+		}
+	}
+	if err := c.Signer.Remote.validate(); err != nil {
+		return err
+	}
+	if c.Signer.Remote.URL != "" && c.Signer.Ledger.Enabled {
+		return fmt.Errorf("signer.remote and signer.ledger cannot both be used")
+	}
+	if wallet := c.Signer.ContractWallet; wallet.Address != "" {
+		if _, err := address.ParseNonZero(wallet.Address); err != nil {
+			return fmt.Errorf("signer.contractWallet.address: %w", err)
+		}
+		if wallet.Format != "safe" && wallet.Format != "direct" {
+			return fmt.Errorf("signer.contractWallet.format must be safe or direct, got %q", wallet.Format)
+		}
+		if c.Depth.Attest {
+			return fmt.Errorf("depth.attest is not supported with signer.contractWallet: attestations must recover to mm_id")
+		}
+	}
+	if c.Signer.KeystorePassword != "" && c.Signer.PasswordEnv != "" {
+		return fmt.Errorf("signer: set keystorePassword or passwordEnv, not both")
+	}
+	if c.Signer.KeystoreFile == "" && (c.Signer.KeystorePassword != "" || c.Signer.PasswordEnv != "") {
+		return fmt.Errorf("signer: keystorePassword and passwordEnv need signer.keystoreFile")
+	}
+	if c.CoSign.Enabled {
+		if c.CoSign.URL == "" {
+			return fmt.Errorf("cosign.url is required when cosign is enabled")
+		}
+		if _, err := address.ParseNonZero(c.CoSign.Address); err != nil {
+			return fmt.Errorf("cosign.address: %w", err)
+		}
+		for pairID, threshold := range c.CoSign.Thresholds {
+			if !c.hasPair(pairID) {
+				return fmt.Errorf("cosign.thresholds: pair %q not configured", pairID)
+			}
+			if d, err := decimal.Parse(threshold); err != nil || d.Sign() < 0 {
+				return fmt.Errorf("cosign.thresholds[%s]: %q is not a non-negative amount", pairID, threshold)
+			}
+		}
+	}
+	if c.Shadow.Enabled && c.Shadow.Strategy.Name == "" {
+		return fmt.Errorf("shadow.strategy.name is required when shadow is enabled")
+	}
+	if c.Stable.FeeBps >= 10000 {
+		return fmt.Errorf("stable.feeBps must be below 10000")
+	}
+	for i, pairID := range c.Stable.Pairs {
+		if !c.hasPair(pairID) {
+			return fmt.Errorf("stable.pairs[%d]: pair %q not configured", i, pairID)
+		}
+	}
+	if err := c.Schedule.validate(); err != nil {
+		return err
+	}
+	if err := c.validateOracle(); err != nil {
+		return err
+	}
+	if err := c.validateMid(); err != nil {
+		return err
+	}
+	if c.Synthetic.SpreadBps >= 10000 {
+		return fmt.Errorf("synthetic.spreadBps must be below 10000")
+	}
+	for i, route := range c.Synthetic.Routes {
+		if !c.hasPair(route.PairID) {
+			return fmt.Errorf("synthetic.routes[%d]: pair %q not configured", i, route.PairID)
+		}
+		if len(route.Via) == 0 {
+			return fmt.Errorf("synthetic.routes[%d].via is required", i)
+		}
+		for _, token := range route.Via {
+			if _, err := address.ParseNonZero(token); err != nil {
+				return fmt.Errorf("synthetic.routes[%d].via: %w", i, err)
+			}
+		}
+	}
+	if err := c.validateWebhook(); err != nil {
+		return err
+	}
+	if err := c.validateInstances(); err != nil {
+		return err
+	}
+	seenChains := make(map[uint64]bool, len(c.Chains))
+	for i, chain := range c.Chains {
+		if c.GetEIP712Domain(chain.ChainID) == nil {
+			return fmt.Errorf("chains[%d]: chain %d has no eip712Domain", i, chain.ChainID)
+		}
+		if seenChains[chain.ChainID] {
+			return fmt.Errorf("chains[%d]: duplicate chain %d", i, chain.ChainID)
+		}
+		if chain.WrappedNative != "" {
+			if _, err := address.ParseNonZero(chain.WrappedNative); err != nil {
+				return fmt.Errorf("chains[%d].wrappedNative: %w", i, err)
+			}
+		}
+		if chain.Explorer != "" {
+			if u, err := url.Parse(chain.Explorer); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return fmt.Errorf("chains[%d].explorer must be an http(s) URL, got %q", i, chain.Explorer)
+			}
+		}
+		seenChains[chain.ChainID] = true
+	}
+	if err := c.validateLogging(); err != nil {
+		return err
+	}
+	if c.FlowExport.Enabled && (c.FlowExport.SampleRate <= 0 || c.FlowExport.SampleRate > 1) {
+		return fmt.Errorf("flowExport.sampleRate must be above 0 and at most 1, got %v", c.FlowExport.SampleRate)
+	}
+	switch c.Store.Backend {
+	case "", store.BackendMemory, store.BackendFile, store.BackendSQLite, store.BackendBadger:
+	default:
+		return fmt.Errorf("store.backend must be %q, %q, %q or %q, got %q",
+			store.BackendMemory, store.BackendFile, store.BackendSQLite, store.BackendBadger, c.Store.Backend)
+	}
+	return nil
+}
+
+// validateMid checks the sources of the weighted mid prices
+func (c *Config) validateMid() error {
+	seen := make(map[PairRef]bool, len(c.Mid.Pairs))
+	for i, mp := range c.Mid.Pairs {
+		ref := PairRef{ChainID: mp.ChainID, PairID: mp.PairID}
+		if !slices.ContainsFunc(c.Pairs, func(pair PairConfig) bool { return pair.ChainID == mp.ChainID && pair.PairID == mp.PairID }) {
+			return fmt.Errorf("mid.pairs[%d]: pair %q not configured on chain %d", i, mp.PairID, mp.ChainID)
+		}
+		if seen[ref] {
+			return fmt.Errorf("mid.pairs[%d]: duplicate pair %q on chain %d", i, mp.PairID, mp.ChainID)
+		}
+		seen[ref] = true
+		if mp.SpreadBps >= 10000 {
+			return fmt.Errorf("mid.pairs[%d].spreadBps must be below 10000", i)
+		}
+		if len(mp.Sources) == 0 {
+			return fmt.Errorf("mid.pairs[%d].sources is required", i)
+		}
+		if mp.MinSources < 0 || mp.MinSources > len(mp.Sources) {
+			return fmt.Errorf("mid.pairs[%d].minSources must be between 0 and the number of sources", i)
+		}
+		for j, src := range mp.Sources {
+			if src.Name == "" {
+				return fmt.Errorf("mid.pairs[%d].sources[%d].name is required", i, j)
+			}
+			if src.Weight < 0 {
+				return fmt.Errorf("mid.pairs[%d].sources[%d].weight must not be negative", i, j)
+			}
+			switch src.Type {
+			case MidSourceUniswapV2, MidSourceUniswapV3:
+				if _, err := address.ParseNonZero(src.Pool); err != nil {
+					return fmt.Errorf("mid.pairs[%d].sources[%d].pool: %w", i, j, err)
+				}
+				if c.MidRPCURL(mp.ChainID, src) == "" {
+					return fmt.Errorf("mid.pairs[%d].sources[%d]: rpcUrl is required when the eip712Domain of chain %d has none", i, j, mp.ChainID)
+				}
+			case MidSourceStrategy:
+				if src.Weight == 0 {
+					return fmt.Errorf("mid.pairs[%d].sources[%d]: strategy sources need a weight", i, j)
+				}
+			default:
+				return fmt.Errorf("mid.pairs[%d].sources[%d].type must be uniswapV2, uniswapV3 or strategy, got %q", i, j, src.Type)
+			}
+		}
 	}
 	return nil
 }
 
+// MidRPCURL returns the JSON-RPC endpoint a mid source on chainID is read from
+func (c *Config) MidRPCURL(chainID uint64, src MidSource) string {
+	if src.RPCURL != "" {
+		return src.RPCURL
+	}
+	if domain := c.GetEIP712Domain(chainID); domain != nil {
+		return domain.RPCURL
+	}
+	return ""
+}
+
+// validateOracle checks the oracle feeds
+func (c *Config) validateOracle() error {
+	for i, feed := range c.Oracle.Feeds {
+		found := false
+		for _, pair := range c.Pairs {
+			if pair.ChainID == feed.ChainID && pair.PairID == feed.PairID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("oracle.feeds[%d]: pair %q not configured on chain %d", i, feed.PairID, feed.ChainID)
+		}
+		if _, err := address.ParseNonZero(feed.Aggregator); err != nil {
+			return fmt.Errorf("oracle.feeds[%d].aggregator: %w", i, err)
+		}
+		if c.OracleRPCURL(feed) == "" {
+			return fmt.Errorf("oracle.feeds[%d]: rpcUrl is required when the eip712Domain of chain %d has none", i, feed.ChainID)
+		}
+	}
+	return nil
+}
+
+// OracleRPCURL returns the JSON-RPC endpoint a feed is read from
+func (c *Config) OracleRPCURL(feed OracleFeed) string {
+	if feed.RPCURL != "" {
+		return feed.RPCURL
+	}
+	for _, domain := range c.EIP712Domains {
+		if domain.ChainID == feed.ChainID {
+			return domain.RPCURL
+		}
+	}
+	return ""
+}
+
+// weekdays maps the weekday names of ScheduleWindow.Days
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Weekday parses a ScheduleWindow.Days entry
+func Weekday(name string) (time.Weekday, bool) {
+	day, ok := weekdays[strings.ToLower(name)]
+	return day, ok
+}
+
+// validate checks window times, weekdays and multipliers
+func (s *ScheduleConfig) validate() error {
+	for i, w := range s.Windows {
+		if _, err := time.Parse("15:04", w.Start); err != nil {
+			return fmt.Errorf("schedule.windows[%d].start: want HH:MM, got %q", i, w.Start)
+		}
+		if _, err := time.Parse("15:04", w.End); err != nil {
+			return fmt.Errorf("schedule.windows[%d].end: want HH:MM, got %q", i, w.End)
+		}
+		for _, day := range w.Days {
+			if _, ok := Weekday(day); !ok {
+				return fmt.Errorf("schedule.windows[%d].days: unknown weekday %q", i, day)
+			}
+		}
+		if w.SpreadMultiplier < 0 || w.SizeMultiplier < 0 {
+			return fmt.Errorf("schedule.windows[%d]: multipliers must not be negative", i)
+		}
+	}
+	for i, e := range s.Events {
+		if e.At.IsZero() {
+			return fmt.Errorf("schedule.events[%d].at is required", i)
+		}
+		if e.SpreadMultiplier < 0 || e.SizeMultiplier < 0 {
+			return fmt.Errorf("schedule.events[%d]: multipliers must not be negative", i)
+		}
+	}
+	for i, l := range s.Regimes.Levels {
+		if l.SpreadMultiplier < 0 || l.SizeMultiplier < 0 {
+			return fmt.Errorf("schedule.regimes.levels[%d]: multipliers must not be negative", i)
+		}
+	}
+	return nil
+}
+
+// Rounding modes of QuoteConfig.Rounding
+const (
+	RoundDown    = "down"
+	RoundNearest = "nearest"
+)
+
+// TickWei converts a tick in token units to native units
+// Returns nil for an empty tick, and an error unless it is a positive whole number of wei
+func TickWei(tick string, decimals int) (*big.Int, error) {
+	if tick == "" {
+		return nil, nil
+	}
+	d, err := decimal.Parse(tick)
+	if err != nil {
+		return nil, err
+	}
+	wei := d.MulInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	if wei.Sign() <= 0 || wei.Cmp(decimal.NewFromBigInt(wei.Int())) != 0 {
+		return nil, fmt.Errorf("%q is not a positive multiple of 1 wei", tick)
+	}
+	return wei.Int(), nil
+}
+
+// validateTick checks a pair tick with TickWei
+func validateTick(tick string, decimals int) error {
+	_, err := TickWei(tick, decimals)
+	return err
+}
+
+// validateBounds checks the minimum amounts and notional bounds of the pair, and that no
+// minimum is above its maximum
+func (p *PairConfig) validateBounds() error {
+	if _, err := TickWei(p.MinBaseIn, p.BaseTokenDecimals); err != nil {
+		return fmt.Errorf("minBaseIn: %w", err)
+	}
+	if _, err := TickWei(p.MinQuoteIn, p.QuoteTokenDecimals); err != nil {
+		return fmt.Errorf("minQuoteIn: %w", err)
+	}
+	minBase, minQuote := p.MinAmountsIn()
+	maxBase, maxQuote := p.MaxAmountsIn()
+	if minBase != nil && maxBase != nil && minBase.Cmp(maxBase) > 0 {
+		return fmt.Errorf("minBaseIn must not be above maxBaseIn")
+	}
+	if minQuote != nil && maxQuote != nil && minQuote.Cmp(maxQuote) > 0 {
+		return fmt.Errorf("minQuoteIn must not be above maxQuoteIn")
+	}
+	for name, notional := range map[string]string{"minNotional": p.MinNotional, "maxNotional": p.MaxNotional} {
+		if notional != "" && parseNotional(notional) == nil {
+			return fmt.Errorf("%s: %q is not a positive amount", name, notional)
+		}
+	}
+	if lo, hi := p.NotionalBounds(); lo != nil && hi != nil && lo.Cmp(*hi) > 0 {
+		return fmt.Errorf("minNotional must not be above maxNotional")
+	}
+	return nil
+}
+
+// validate checks the key names, policy and assignments of the pool
+func (p *SignerPoolConfig) validate() error {
+	names := map[string]bool{"primary": true}
+	for i, key := range p.Keys {
+		if key.Name == "" {
+			return fmt.Errorf("signer.pool.keys[%d].name is required", i)
+		}
+		if names[key.Name] {
+			return fmt.Errorf("signer.pool.keys[%d]: duplicate key name %q", i, key.Name)
+		}
+		names[key.Name] = true
+		if key.PrivateKey == "" && key.PrivateKeyEnv == "" {
+			return fmt.Errorf("signer.pool.keys[%d]: privateKey or privateKeyEnv is required", i)
+		}
+	}
+	switch p.Policy {
+	case "", "roundRobin", "perPair", "perChain", "failover":
+	default:
+		return fmt.Errorf("signer.pool.policy %q is not roundRobin, perPair, perChain or failover", p.Policy)
+	}
+	for pairID, name := range p.Pairs {
+		if !names[name] {
+			return fmt.Errorf("signer.pool.pairs[%s]: unknown key %q", pairID, name)
+		}
+	}
+	for chainID, name := range p.Chains {
+		if !names[name] {
+			return fmt.Errorf("signer.pool.chains[%d]: unknown key %q", chainID, name)
+		}
+	}
+	return nil
+}
+
+// validate checks that an enabled Vault key has a path and a way to authenticate
+func (v *VaultConfig) validate() error {
+	if !v.Enabled() {
+		return nil
+	}
+	if !strings.HasPrefix(v.Address, "http://") && !strings.HasPrefix(v.Address, "https://") {
+		return fmt.Errorf("signer.vault.address must be an http:// or https:// URL, got %q", v.Address)
+	}
+	if v.Path == "" {
+		return fmt.Errorf("signer.vault.path is required")
+	}
+	if v.Token == "" && v.TokenEnv == "" && (v.AppRole.RoleID == "" || (v.AppRole.SecretID == "" && v.AppRole.SecretIDEnv == "")) {
+		return fmt.Errorf("signer.vault needs token, tokenEnv or appRole.roleId with appRole.secretId or appRole.secretIdEnv")
+	}
+	if v.Timeout < 0 {
+		return fmt.Errorf("signer.vault.timeout must not be negative")
+	}
+	return nil
+}
+
+// validate checks that a remote signer is reached over TLS with a complete client certificate
+func (r *RemoteSignerConfig) validate() error {
+	if r.URL == "" {
+		return nil
+	}
+	if !strings.HasPrefix(r.URL, "https://") {
+		return fmt.Errorf("signer.remote.url must be an https:// URL, got %q", r.URL)
+	}
+	if (r.CertFile == "") != (r.KeyFile == "") {
+		return fmt.Errorf("signer.remote.certFile and keyFile must be set together")
+	}
+	if r.Address != "" {
+		if _, err := address.ParseNonZero(r.Address); err != nil {
+			return fmt.Errorf("signer.remote.address: %w", err)
+		}
+	}
+	if r.Timeout < 0 || r.Retries < 0 {
+		return fmt.Errorf("signer.remote.timeout and retries must not be negative")
+	}
+	return nil
+}
+
+// setBackupDefaults fills in the failover thresholds of a backup key
+func setBackupDefaults(b *SignerBackupConfig) {
+	if b.FailAfter == 0 {
+		b.FailAfter = 3
+	}
+	if b.FailBack == "" {
+		b.FailBack = "auto"
+	}
+	if b.FailBackAfter == 0 {
+		b.FailBackAfter = 3
+	}
+}
+
+// validateBackup checks the backup key of the signer, whose settings are at path
+func (c *SignerConfig) validateBackup(path string) error {
+	b := c.Backup
+	if !b.Enabled() {
+		return nil
+	}
+	if b.FailAfter < 1 {
+		return fmt.Errorf("%s.failAfter must be at least 1", path)
+	}
+	if b.FailBackAfter < 1 {
+		return fmt.Errorf("%s.failBackAfter must be at least 1", path)
+	}
+	if b.FailBack != "auto" && b.FailBack != "manual" {
+		return fmt.Errorf("%s.failBack %q is not auto or manual", path, b.FailBack)
+	}
+	for i, key := range c.Pool.Keys {
+		if key.Name == "backup" {
+			return fmt.Errorf("signer.pool.keys[%d]: the name \"backup\" is taken by %s", i, path)
+		}
+	}
+	return nil
+}
+
+// ChainIDs returns the chains of EIP712Domains, in config order
+func (c *Config) ChainIDs() []uint64 {
+	ids := make([]uint64, 0, len(c.EIP712Domains))
+	for _, domain := range c.EIP712Domains {
+		if !slices.Contains(ids, domain.ChainID) {
+			ids = append(ids, domain.ChainID)
+		}
+	}
+	return ids
+}
+
+// hasPair reports whether a pair with the given ID is configured
+func (c *Config) hasPair(pairID string) bool {
+	for _, pair := range c.Pairs {
+		if pair.PairID == pairID {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildIndex indexes EIP712Domains and Pairs so lookups take constant time
+// Called by Load; call it again after changing EIP712Domains or Pairs of an indexed config.
+// The first entry wins when several match, like the linear lookups.
+// Pairs whose tokens are not hex addresses can never match an RFQ and are not indexed.
+func (c *Config) BuildIndex() {
+	index := &lookupIndex{
+		domains: make(map[uint64]int, len(c.EIP712Domains)),
+		pairs:   make(map[pairKey]int, len(c.Pairs)),
+		chains:  c.newChainRegistry(),
+	}
+	for i, domain := range c.EIP712Domains {
+		if _, ok := index.domains[domain.ChainID]; !ok {
+			index.domains[domain.ChainID] = i
+		}
+	}
+	for i, pair := range c.Pairs {
+		if !common.IsHexAddress(pair.BaseToken) || !common.IsHexAddress(pair.QuoteToken) {
+			continue
+		}
+		key := newPairKey(pair.ChainID, common.HexToAddress(pair.BaseToken), common.HexToAddress(pair.QuoteToken))
+		if _, ok := index.pairs[key]; !ok {
+			index.pairs[key] = i
+		}
+	}
+	c.index = index
+}
+
+// ChainRegistry returns the chain registry: the built-in chains with the chains entries applied
+func (c *Config) ChainRegistry() *chains.Registry {
+	if c.index != nil {
+		return c.index.chains
+	}
+	return c.newChainRegistry()
+}
+
+// newChainRegistry builds the chain registry of the chains entries
+func (c *Config) newChainRegistry() *chains.Registry {
+	overrides := make([]chains.Chain, 0, len(c.Chains))
+	for _, chain := range c.Chains {
+		o := chains.Chain{ID: chain.ChainID, Name: chain.Name, Explorer: chain.Explorer, NativeDecimals: chain.NativeDecimals}
+		if chain.WrappedNative != "" {
+			o.WrappedNative = common.HexToAddress(chain.WrappedNative)
+		}
+		overrides = append(overrides, o)
+	}
+	return chains.New(overrides...)
+}
+
 // GetEIP712Domain gets EIP-712 Domain by chain ID
+// The result points into EIP712Domains and must not be modified
 func (c *Config) GetEIP712Domain(chainID uint64) *EIP712Domain {
-	for _, domain := range c.EIP712Domains {
-		if domain.ChainID == chainID {
-			return &domain
+	if c.index != nil {
+		if i, ok := c.index.domains[chainID]; ok {
+			return &c.EIP712Domains[i]
+		}
+		return nil
+	}
+	for i := range c.EIP712Domains {
+		if c.EIP712Domains[i].ChainID == chainID {
+			return &c.EIP712Domains[i]
 		}
 	}
 	return nil
 }
 
 // GetPairConfig gets trading pair configuration by chain ID and token addresses
+// The result points into Pairs and must not be modified
 func (c *Config) GetPairConfig(chainID uint64, tokenIn, tokenOut string) *PairConfig {
+	if c.index != nil {
+		if !common.IsHexAddress(tokenIn) || !common.IsHexAddress(tokenOut) {
+			return nil
+		}
+		return c.GetPairConfigByAddress(chainID, common.HexToAddress(tokenIn), common.HexToAddress(tokenOut))
+	}
+
 	tokenInLower := strings.ToLower(tokenIn)
 	tokenOutLower := strings.ToLower(tokenOut)
 
-	for _, pair := range c.Pairs {
+	for i := range c.Pairs {
+		pair := &c.Pairs[i]
 		if pair.ChainID != chainID {
 			continue
 		}
@@ -186,8 +1827,180 @@ func (c *Config) GetPairConfig(chainID uint64, tokenIn, tokenOut string) *PairCo
 		// Bidirectional matching
 		if (tokenInLower == baseLower && tokenOutLower == quoteLower) ||
 			(tokenInLower == quoteLower && tokenOutLower == baseLower) {
-			return &pair
+			return pair
 		}
 	}
 	return nil
 }
+
+// GetPairConfigByAddress is GetPairConfig for parsed addresses
+// Allocation-free on an indexed config
+func (c *Config) GetPairConfigByAddress(chainID uint64, tokenIn, tokenOut common.Address) *PairConfig {
+	if c.index == nil {
+		return c.GetPairConfig(chainID, tokenIn.Hex(), tokenOut.Hex())
+	}
+	if i, ok := c.index.pairs[newPairKey(chainID, tokenIn, tokenOut)]; ok {
+		return &c.Pairs[i]
+	}
+	return nil
+}
+
+// validateWebhook validates the webhook endpoints and large quote thresholds
+func (c *Config) validateWebhook() error {
+	for i, endpoint := range c.Webhook.Endpoints {
+		u, err := url.Parse(endpoint.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhook.endpoints[%d].url must be an http(s) URL, got %q", i, endpoint.URL)
+		}
+		if endpoint.Secret == "" && endpoint.SecretEnv == "" {
+			return fmt.Errorf("webhook.endpoints[%d]: secret or secretEnv is required", i)
+		}
+		for _, event := range endpoint.Events {
+			if !slices.Contains(WebhookEvents, event) {
+				return fmt.Errorf("webhook.endpoints[%d].events: unknown event %q", i, event)
+			}
+		}
+	}
+	for pairID, threshold := range c.Webhook.LargeQuotes {
+		if !c.hasPair(pairID) {
+			return fmt.Errorf("webhook.largeQuotes: pair %q not configured", pairID)
+		}
+		if d, err := decimal.Parse(threshold); err != nil || d.Sign() <= 0 {
+			return fmt.Errorf("webhook.largeQuotes[%s]: %q is not a positive amount", pairID, threshold)
+		}
+	}
+	if c.Webhook.Timeout < 0 || c.Webhook.MaxAttempts < 0 || c.Webhook.RetryBackoff < 0 {
+		return fmt.Errorf("webhook: timeout, maxAttempts and retryBackoff must not be negative")
+	}
+	return nil
+}
+
+// validateLogging checks the log sinks
+func (c *Config) validateLogging() error {
+	for i, sink := range c.Logging.Sinks {
+		switch sink.Type {
+		case "syslog":
+			if sink.Address == "" {
+				break
+			}
+			u, err := url.Parse(sink.Address)
+			if err != nil || (u.Scheme != "udp" && u.Scheme != "tcp" && u.Scheme != "tls") || u.Host == "" {
+				return fmt.Errorf("logging.sinks[%d].address must be a udp://, tcp:// or tls:// syslog address, got %q", i, sink.Address)
+			}
+		case "journald":
+		case "tcp":
+			if _, _, err := net.SplitHostPort(sink.Address); err != nil {
+				return fmt.Errorf("logging.sinks[%d].address must be host:port, got %q", i, sink.Address)
+			}
+		default:
+			return fmt.Errorf("logging.sinks[%d].type must be \"syslog\", \"journald\" or \"tcp\", got %q", i, sink.Type)
+		}
+		if sink.TLS && sink.Type != "tcp" {
+			return fmt.Errorf("logging.sinks[%d].tls applies to tcp sinks, use a tls:// syslog address", i)
+		}
+		var level slog.Level
+		if sink.Level != "" && level.UnmarshalText([]byte(sink.Level)) != nil {
+			return fmt.Errorf("logging.sinks[%d].level must be debug, info, warn or error, got %q", i, sink.Level)
+		}
+	}
+	return nil
+}
+
+// validateInstances validates the MM identities of a multi-instance process
+func (c *Config) validateInstances() error {
+	if len(c.Instances) == 0 {
+		return nil
+	}
+	if c.Query.Enabled || c.Admin.Enabled {
+		return fmt.Errorf("query.enabled and admin.enabled are not supported with instances")
+	}
+	if len(c.Signer.ChainKeys) > 0 || c.Signer.ContractWallet.Address != "" {
+		return fmt.Errorf("signer.chainKeys and signer.contractWallet are not supported with instances")
+	}
+	names := make(map[string]bool, len(c.Instances))
+	connections := make(map[string]string, len(c.Instances))
+	for i, inst := range c.Instances {
+		if inst.Name == "" || strings.ContainsAny(inst.Name, `/\`) {
+			return fmt.Errorf("instances[%d].name is required and must not contain path separators", i)
+		}
+		if names[inst.Name] {
+			return fmt.Errorf("instances[%d]: duplicate name %q", i, inst.Name)
+		}
+		names[inst.Name] = true
+		if inst.PrivateKey == "" && inst.PrivateKeyEnv == "" {
+			return fmt.Errorf("instances[%s]: privateKey or privateKeyEnv is required", inst.Name)
+		}
+		if err := inst.Pool.validate(); err != nil {
+			return fmt.Errorf("instances[%s]: %w", inst.Name, err)
+		}
+		instSigner := SignerConfig{Pool: inst.Pool, Backup: inst.Backup}
+		if err := instSigner.validateBackup("backup"); err != nil {
+			return fmt.Errorf("instances[%s]: %w", inst.Name, err)
+		}
+		serverURL, token := inst.ServerURL, inst.APIToken
+		if serverURL == "" {
+			serverURL = c.WebSocket.ServerURL
+		}
+		if token == "" {
+			token = c.WebSocket.APIToken
+		}
+		if token == "" {
+			return fmt.Errorf("instances[%s].apiToken is required without websocket.apiToken", inst.Name)
+		}
+		key := serverURL + " " + token
+		if other, ok := connections[key]; ok {
+			return fmt.Errorf("instances[%s] connects to %s with the API token of instance %s", inst.Name, serverURL, other)
+		}
+		connections[key] = inst.Name
+		for _, pairID := range inst.Pairs {
+			if !c.hasPair(pairID) {
+				return fmt.Errorf("instances[%s].pairs: pair %q not configured", inst.Name, pairID)
+			}
+		}
+	}
+	return nil
+}
+
+// ForInstances returns the config of every MM identity, or c itself without instances
+// Each instance config is a copy of c with the instance's signer, connection and pairs. Its
+// state store and recording are kept apart under the instance name, and metrics are disabled:
+// they are served once for the process.
+func (c *Config) ForInstances() []*Config {
+	if len(c.Instances) == 0 {
+		return []*Config{c}
+	}
+	configs := make([]*Config, 0, len(c.Instances))
+	for _, inst := range c.Instances {
+		ic := *c
+		ic.Instances = nil
+		ic.App.Name = inst.Name
+		ic.Signer.PrivateKey, ic.Signer.PrivateKeyEnv, ic.Signer.Pool = inst.PrivateKey, inst.PrivateKeyEnv, inst.Pool
+		ic.Signer.Backup = inst.Backup
+		if inst.ServerURL != "" {
+			ic.WebSocket.ServerURL = inst.ServerURL
+		}
+		if inst.APIToken != "" {
+			ic.WebSocket.APIToken = inst.APIToken
+		}
+		if len(inst.Pairs) > 0 {
+			ic.Pairs = make([]PairConfig, 0, len(inst.Pairs))
+			for _, pair := range c.Pairs {
+				if slices.Contains(inst.Pairs, pair.PairID) {
+					ic.Pairs = append(ic.Pairs, pair)
+				}
+			}
+		}
+		ic.Store.Path = filepath.Join(ic.Store.Path, inst.Name)
+		ext := filepath.Ext(ic.Recorder.Path)
+		ic.Recorder.Path = strings.TrimSuffix(ic.Recorder.Path, ext) + "-" + inst.Name + ext
+		ext = filepath.Ext(ic.FlowExport.File)
+		ic.FlowExport.File = strings.TrimSuffix(ic.FlowExport.File, ext) + "-" + inst.Name + ext
+		ic.Metrics.Enabled = false
+		ic.index = nil
+		if c.index != nil {
+			ic.BuildIndex()
+		}
+		configs = append(configs, &ic)
+	}
+	return configs
+}
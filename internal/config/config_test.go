@@ -0,0 +1,1017 @@
+package config
+
+import (
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	wbnb = "0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c"
+	usdt = "0x55d398326f99059fF775485246999027B3197955"
+	weth = "0x4200000000000000000000000000000000000006"
+	usdc = "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+)
+
+func testConfig() *Config {
+	return &Config{
+		EIP712Domains: []EIP712Domain{
+			{ChainID: 56, Name: "RFQ Manager", Version: "1", VerifyingContract: "0x28D3a265f6d40867986004029ee91F4C9532fCC5"},
+			{ChainID: 8453, Name: "RFQ Manager", Version: "1", VerifyingContract: "0x1111111111111111111111111111111111111111"},
+		},
+		Pairs: []PairConfig{
+			{ChainID: 56, PairID: "WBNB-USDT", BaseToken: wbnb, QuoteToken: usdt},
+			{ChainID: 8453, PairID: "WETH-USDC", BaseToken: weth, QuoteToken: usdc},
+			{ChainID: 56, PairID: "DUPLICATE", BaseToken: usdt, QuoteToken: wbnb},
+			{ChainID: 56, PairID: "INVALID", BaseToken: "0xbase", QuoteToken: "0xquote"},
+		},
+	}
+}
+
+// validConfig returns testConfig without its invalid pair, ready for Validate
+func validConfig() *Config {
+	cfg := testConfig()
+	cfg.Pairs = cfg.Pairs[:3]
+	cfg.WebSocket = WebSocketConfig{ServerURL: "ws://127.0.0.1/ws", APIToken: "token"}
+	return cfg
+}
+
+func TestConfig_IndexedLookupsMatchLinear(t *testing.T) {
+	linear := testConfig()
+	indexed := testConfig()
+	indexed.BuildIndex()
+
+	pairTests := []struct {
+		chainID           uint64
+		tokenIn, tokenOut string
+	}{
+		{56, wbnb, usdt},
+		{56, usdt, wbnb},
+		{56, "0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c", "0x55d398326f99059ff775485246999027b3197955"},
+		{8453, usdc, weth},
+		{56, weth, usdc},
+		{1, wbnb, usdt},
+		{56, wbnb, wbnb},
+	}
+	for _, tt := range pairTests {
+		want := linear.GetPairConfig(tt.chainID, tt.tokenIn, tt.tokenOut)
+		got := indexed.GetPairConfig(tt.chainID, tt.tokenIn, tt.tokenOut)
+		if (got == nil) != (want == nil) || (got != nil && got.PairID != want.PairID) {
+			t.Errorf("GetPairConfig(%d, %s, %s) = %v, want %v", tt.chainID, tt.tokenIn, tt.tokenOut, got, want)
+		}
+		byAddr := indexed.GetPairConfigByAddress(tt.chainID, common.HexToAddress(tt.tokenIn), common.HexToAddress(tt.tokenOut))
+		if byAddr != got {
+			t.Errorf("GetPairConfigByAddress(%d, %s, %s) = %v, want %v", tt.chainID, tt.tokenIn, tt.tokenOut, byAddr, got)
+		}
+	}
+
+	for _, chainID := range []uint64{56, 8453, 1} {
+		want := linear.GetEIP712Domain(chainID)
+		got := indexed.GetEIP712Domain(chainID)
+		if (got == nil) != (want == nil) || (got != nil && !reflect.DeepEqual(*got, *want)) {
+			t.Errorf("GetEIP712Domain(%d) = %v, want %v", chainID, got, want)
+		}
+	}
+}
+
+func TestConfig_LookupsPointIntoConfig(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		cfg := testConfig()
+		if indexed {
+			cfg.BuildIndex()
+		}
+		if got := cfg.GetEIP712Domain(8453); got != &cfg.EIP712Domains[1] {
+			t.Errorf("indexed=%v: GetEIP712Domain does not point into EIP712Domains", indexed)
+		}
+		if got := cfg.GetPairConfig(56, usdt, wbnb); got != &cfg.Pairs[0] {
+			t.Errorf("indexed=%v: GetPairConfig does not point into Pairs", indexed)
+		}
+	}
+}
+
+func TestConfig_IndexedLookupsDoNotAllocate(t *testing.T) {
+	cfg := testConfig()
+	cfg.BuildIndex()
+	tokenIn, tokenOut := common.HexToAddress(usdc), common.HexToAddress(weth)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if cfg.GetEIP712Domain(8453) == nil || cfg.GetPairConfigByAddress(8453, tokenIn, tokenOut) == nil {
+			t.Fatal("lookup failed")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("allocs = %v, want 0", allocs)
+	}
+}
+
+func TestConfig_ValidateStablePairs(t *testing.T) {
+	cfg := validConfig()
+
+	cfg.Stable = StableConfig{Pairs: []string{"WBNB-USDT"}, FeeBps: 5}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	cfg.Stable.Pairs = []string{"USDT-USDC"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want an error for an unknown stable pair")
+	}
+	cfg.Stable = StableConfig{FeeBps: 10000}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want an error for feeBps 10000")
+	}
+}
+
+func TestConfig_ValidateSyntheticRoutes(t *testing.T) {
+	cfg := validConfig()
+
+	tests := []struct {
+		name    string
+		route   SyntheticRoute
+		wantErr bool
+	}{
+		{"valid", SyntheticRoute{PairID: "WBNB-USDT", Via: []string{usdc}}, false},
+		{"unknown pair", SyntheticRoute{PairID: "WBNB-USDC", Via: []string{usdc}}, true},
+		{"no via", SyntheticRoute{PairID: "WBNB-USDT"}, true},
+		{"invalid via", SyntheticRoute{PairID: "WBNB-USDT", Via: []string{"usdc"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Synthetic.Routes = []SyntheticRoute{tt.route}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateSchedule(t *testing.T) {
+	cfg := validConfig()
+
+	tests := []struct {
+		name    string
+		window  ScheduleWindow
+		wantErr bool
+	}{
+		{"valid", ScheduleWindow{Start: "22:00", End: "02:00", Days: []string{"Fri"}, SpreadMultiplier: 2}, false},
+		{"bad start", ScheduleWindow{Start: "10pm", End: "02:00"}, true},
+		{"unknown day", ScheduleWindow{Start: "22:00", End: "02:00", Days: []string{"friday"}}, true},
+		{"negative multiplier", ScheduleWindow{Start: "22:00", End: "02:00", SizeMultiplier: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Schedule.Windows = []ScheduleWindow{tt.window}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateRounding(t *testing.T) {
+	tests := []struct {
+		name     string
+		rounding string
+		tick     string
+		decimals int
+		wantErr  bool
+	}{
+		{"no tick", RoundDown, "", 18, false},
+		{"token tick", RoundNearest, "0.0001", 18, false},
+		{"one wei", RoundDown, "0.000001", 6, false},
+		{"below one wei", RoundDown, "0.0000001", 6, true},
+		{"zero", RoundDown, "0", 18, true},
+		{"not a number", RoundDown, "tick", 18, true},
+		{"unknown mode", "up", "", 18, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Quote.Rounding = tt.rounding
+			cfg.Pairs[0].QuoteTick = tt.tick
+			cfg.Pairs[0].QuoteTokenDecimals = tt.decimals
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateShadow(t *testing.T) {
+	cfg := validConfig()
+
+	cfg.Shadow = ShadowConfig{Enabled: true}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want an error for a shadow without a candidate strategy")
+	}
+	cfg.Shadow.Strategy.Name = "mock"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
+
+func TestConfig_ValidateSignerPool(t *testing.T) {
+	key := SignerKeyConfig{Name: "hot-1", PrivateKeyEnv: "MM_PRIVATE_KEY_HOT_1"}
+	tests := []struct {
+		name    string
+		pool    SignerPoolConfig
+		wantErr bool
+	}{
+		{"valid", SignerPoolConfig{Keys: []SignerKeyConfig{key}, Policy: "perPair", Pairs: map[string]string{"WBNB-USDT": "hot-1"}}, false},
+		{"primary assignment", SignerPoolConfig{Keys: []SignerKeyConfig{key}, Policy: "perChain", Chains: map[uint64]string{56: "primary"}}, false},
+		{"unknown policy", SignerPoolConfig{Keys: []SignerKeyConfig{key}, Policy: "random"}, true},
+		{"unknown key", SignerPoolConfig{Keys: []SignerKeyConfig{key}, Pairs: map[string]string{"WBNB-USDT": "hot-2"}}, true},
+		{"duplicate name", SignerPoolConfig{Keys: []SignerKeyConfig{key, key}}, true},
+		{"primary name", SignerPoolConfig{Keys: []SignerKeyConfig{{Name: "primary", PrivateKey: "0x01"}}}, true},
+		{"no key", SignerPoolConfig{Keys: []SignerKeyConfig{{Name: "hot-1"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Signer.Pool = tt.pool
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateAddresses(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+	}{
+		{"typo in verifying contract", func(cfg *Config) {
+			cfg.EIP712Domains[0].VerifyingContract = "0x28D3a265f6d40867986004029ee91F4C9532fCC"
+		}},
+		{"zero verifying contract", func(cfg *Config) {
+			cfg.EIP712Domains[0].VerifyingContract = "0x0000000000000000000000000000000000000000"
+		}},
+		{"bad base token checksum", func(cfg *Config) { cfg.Pairs[0].BaseToken = "0xBb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c" }},
+		{"non-hex quote token", func(cfg *Config) { cfg.Pairs[0].QuoteToken = "0x55d398326f99059ff775485246999027b319795z" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+			if err := cfg.Validate(); err == nil {
+				t.Error("Validate() = nil, want an address error")
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateQuoteType(t *testing.T) {
+	cfg := validConfig()
+	cfg.EIP712Domains[0].QuoteType = "MMQuote(uint256 nonce,address from,uint256 amountIn,uint256 amountOut,uint256 deadline)"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	cfg.EIP712Domains[0].QuoteType = "MMQuote(address pool)"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for an unknown quoteType member")
+	}
+}
+
+func TestConfig_ValidateDeadlineHorizon(t *testing.T) {
+	cfg := validConfig()
+	cfg.Signer.MaxDeadlineHorizon = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for a negative deadline horizon")
+	}
+}
+
+func TestConfig_ValidateHealthCheckInterval(t *testing.T) {
+	cfg := validConfig()
+	cfg.Signer.HealthCheckInterval = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for a negative health check interval")
+	}
+}
+
+func TestConfig_ValidateStandby(t *testing.T) {
+	cfg := validConfig()
+	cfg.WebSocket.Standby.ServerURL = cfg.WebSocket.ServerURL
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for a standby on the primary URL")
+	}
+	cfg.WebSocket.Standby.ServerURL = "wss://standby.example.com/ws"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
+
+func TestConfig_ValidateMetrics(t *testing.T) {
+	cfg := validConfig()
+	cfg.Metrics = MetricsConfig{Enabled: true, Listen: "9464"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for a listen address without a port")
+	}
+	cfg.Metrics.Listen = ":9464"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
+
+func TestConfig_ValidateReconnectStorm(t *testing.T) {
+	cfg := validConfig()
+	cfg.WebSocket.ReconnectStorm.MaxAttempts = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for negative reconnectStorm.maxAttempts")
+	}
+}
+
+func TestConfig_ValidateBandwidth(t *testing.T) {
+	cfg := validConfig()
+	cfg.WebSocket.Bandwidth.Reserve = 1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for a reserve of the whole budget")
+	}
+}
+
+func TestConfig_ValidateOracle(t *testing.T) {
+	cfg := validConfig()
+	pair := cfg.Pairs[0]
+	cfg.Oracle.Feeds = []OracleFeed{{ChainID: pair.ChainID, PairID: pair.PairID, Aggregator: "0x0567F2323251f0Aab15c8dFb1967E4e8A7D42aeE"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for a feed without an RPC endpoint")
+	}
+	cfg.Oracle.Feeds[0].RPCURL = "http://127.0.0.1:8545"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+	cfg.Oracle.Feeds[0].PairID = "UNKNOWN"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for an unknown pair")
+	}
+}
+
+func TestConfig_ValidateDepthTriggers(t *testing.T) {
+	cfg := validConfig()
+	cfg.Depth.Triggers = DepthTriggersConfig{MidMoveBps: 20}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for mid move checks without an interval")
+	}
+	cfg.setDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil with the default intervals", err)
+	}
+}
+
+func TestConfig_ValidDurationDefaultsToRequestedDeadline(t *testing.T) {
+	cfg := validConfig()
+	cfg.setDefaults()
+	if cfg.Quote.ValidDuration != 0 {
+		t.Errorf("default quote.validDuration = %v, want 0 (sign the requested deadline)", cfg.Quote.ValidDuration)
+	}
+}
+
+func TestConfig_ValidateRESTDepth(t *testing.T) {
+	cfg := validConfig()
+	cfg.Depth.REST.URL = "http://pricing.internal/books/{chainId}/{pairId}"
+	cfg.setDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil with the default mapping", err)
+	}
+	cfg.Depth.REST.Mapping.Units = "cents"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for unknown units")
+	}
+	cfg.Depth.REST.Mapping.Units = UnitsWei
+	cfg.Depth.REST.URL = "ftp://pricing.internal"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for a non-HTTP URL")
+	}
+}
+
+func TestConfig_ValidateDialer(t *testing.T) {
+	cfg := validConfig()
+	cfg.WebSocket.Dialer.KeepAlive = -1 // Disables keepalive
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil for a negative keepAlive", err)
+	}
+	cfg.WebSocket.Dialer.DNSRecheckInterval = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for a negative dnsRecheckInterval")
+	}
+}
+
+func TestConfig_ValidateEventHistory(t *testing.T) {
+	cfg := validConfig()
+	cfg.WebSocket.EventHistory = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for a negative event history")
+	}
+}
+
+func TestConfig_ValidateTLSPins(t *testing.T) {
+	pin := "sha256/" + strings.Repeat("A", 43) + "="
+	tests := []struct {
+		name    string
+		url     string
+		pins    []string
+		wantErr bool
+	}{
+		{"no pins", "ws://127.0.0.1/ws", nil, false},
+		{"valid", "wss://gateway.example.com/ws", []string{pin}, false},
+		{"plain ws", "ws://127.0.0.1/ws", []string{pin}, true},
+		{"bad pin", "wss://gateway.example.com/ws", []string{"md5/abc"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.WebSocket.ServerURL = tt.url
+			cfg.WebSocket.TLSPins = tt.pins
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateCoSign(t *testing.T) {
+	addr := "0x1111111111111111111111111111111111111111"
+	tests := []struct {
+		name    string
+		cosign  CoSignConfig
+		wantErr bool
+	}{
+		{"disabled", CoSignConfig{Thresholds: map[string]string{"nope": "x"}}, false},
+		{"valid", CoSignConfig{Enabled: true, URL: "http://127.0.0.1/approve", Address: addr, Thresholds: map[string]string{"WBNB-USDT": "50000"}}, false},
+		{"no url", CoSignConfig{Enabled: true, Address: addr}, true},
+		{"bad address", CoSignConfig{Enabled: true, URL: "http://127.0.0.1/approve", Address: "cosigner"}, true},
+		{"unknown pair", CoSignConfig{Enabled: true, URL: "http://127.0.0.1/approve", Address: addr, Thresholds: map[string]string{"ETH-USDT": "1"}}, true},
+		{"negative threshold", CoSignConfig{Enabled: true, URL: "http://127.0.0.1/approve", Address: addr, Thresholds: map[string]string{"WBNB-USDT": "-1"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.CoSign = tt.cosign
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateStore(t *testing.T) {
+	for backend, wantErr := range map[string]bool{"": false, "memory": false, "file": false, "sqlite": false, "badger": false, "disk": true} {
+		cfg := validConfig()
+		cfg.Store.Backend = backend
+		if err := cfg.Validate(); (err != nil) != wantErr {
+			t.Errorf("backend %q: Validate() = %v, wantErr %v", backend, err, wantErr)
+		}
+	}
+}
+
+func TestConfig_ValidateQuery(t *testing.T) {
+	cfg := validConfig()
+	cfg.Query = QueryConfig{Enabled: true, Listen: "9465"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for a listen address without a port")
+	}
+	cfg.Metrics = MetricsConfig{Enabled: true, Listen: "127.0.0.1:9464"}
+	cfg.Query.Listen = cfg.Metrics.Listen
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for the metrics address")
+	}
+	cfg.Query.Listen = "127.0.0.1:9465"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
+
+func TestConfig_ValidateWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook WebhookConfig
+		wantErr bool
+	}{
+		{"none", WebhookConfig{}, false},
+		{"valid", WebhookConfig{
+			Endpoints:   []WebhookEndpoint{{URL: "https://example.com/hook", SecretEnv: "MM_WEBHOOK_SECRET", Events: []string{"large_quote", "risk_breach"}}},
+			LargeQuotes: map[string]string{"WBNB-USDT": "50000"},
+		}, false},
+		{"bad url", WebhookConfig{Endpoints: []WebhookEndpoint{{URL: "example.com/hook", Secret: "s"}}}, true},
+		{"no secret", WebhookConfig{Endpoints: []WebhookEndpoint{{URL: "https://example.com/hook"}}}, true},
+		{"unknown event", WebhookConfig{Endpoints: []WebhookEndpoint{{URL: "https://example.com/hook", Secret: "s", Events: []string{"kill_switch"}}}}, true},
+		{"unknown pair", WebhookConfig{LargeQuotes: map[string]string{"FOO-BAR": "1"}}, true},
+		{"zero threshold", WebhookConfig{LargeQuotes: map[string]string{"WBNB-USDT": "0"}}, true},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Webhook = tt.webhook
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_ValidateLogging(t *testing.T) {
+	tests := []struct {
+		name    string
+		sinks   []LogSinkConfig
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"valid", []LogSinkConfig{
+			{Type: "syslog"},
+			{Type: "syslog", Address: "tls://logs.example.com:6514", Level: "warn"},
+			{Type: "journald"},
+			{Type: "tcp", Address: "vector:9000", TLS: true, Level: "debug"},
+		}, false},
+		{"unknown type", []LogSinkConfig{{Type: "loki"}}, true},
+		{"syslog without scheme", []LogSinkConfig{{Type: "syslog", Address: "logs.example.com:514"}}, true},
+		{"tcp without address", []LogSinkConfig{{Type: "tcp"}}, true},
+		{"tls on syslog", []LogSinkConfig{{Type: "syslog", TLS: true}}, true},
+		{"bad level", []LogSinkConfig{{Type: "journald", Level: "verbose"}}, true},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Logging.Sinks = tt.sinks
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+	if got := (LogSinkConfig{Level: "warn"}).SlogLevel(); got != slog.LevelWarn {
+		t.Errorf("SlogLevel(warn) = %v", got)
+	}
+}
+
+func TestConfig_ValidateFlowExport(t *testing.T) {
+	tests := []struct {
+		name    string
+		export  FlowExportConfig
+		wantErr bool
+	}{
+		{"disabled", FlowExportConfig{}, false},
+		{"sampled", FlowExportConfig{Enabled: true, File: "flow.csv", SampleRate: 0.25}, false},
+		{"all", FlowExportConfig{Enabled: true, File: "flow.csv", SampleRate: 1}, false},
+		{"rate above 1", FlowExportConfig{Enabled: true, File: "flow.csv", SampleRate: 1.5}, true},
+		{"negative rate", FlowExportConfig{Enabled: true, File: "flow.csv", SampleRate: -0.1}, true},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.FlowExport = tt.export
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_ValidateSignerBackup(t *testing.T) {
+	tests := []struct {
+		name    string
+		backup  SignerBackupConfig
+		pool    []SignerKeyConfig
+		wantErr bool
+	}{
+		{"none", SignerBackupConfig{}, nil, false},
+		{"defaults", SignerBackupConfig{PrivateKeyEnv: "MM_BACKUP_KEY"}, nil, false},
+		{"manual", SignerBackupConfig{PrivateKeyEnv: "MM_BACKUP_KEY", FailBack: "manual"}, nil, false},
+		{"unknown policy", SignerBackupConfig{PrivateKeyEnv: "MM_BACKUP_KEY", FailBack: "never"}, nil, true},
+		{"negative threshold", SignerBackupConfig{PrivateKeyEnv: "MM_BACKUP_KEY", FailAfter: -1}, nil, true},
+		{"pool key named backup", SignerBackupConfig{PrivateKeyEnv: "MM_BACKUP_KEY"}, []SignerKeyConfig{{Name: "backup", PrivateKeyEnv: "K"}}, true},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Signer.Backup = tt.backup
+		cfg.Signer.Pool.Keys = tt.pool
+		cfg.setDefaults()
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_ValidateVault(t *testing.T) {
+	tests := []struct {
+		name    string
+		vault   VaultConfig
+		wantErr bool
+	}{
+		{"disabled", VaultConfig{}, false},
+		{"token env", VaultConfig{Address: "https://vault:8200", Path: "mm/signer", TokenEnv: "VAULT_TOKEN"}, false},
+		{"approle", VaultConfig{Address: "https://vault:8200", Path: "mm/signer", AppRole: VaultAppRoleConfig{RoleID: "r", SecretIDEnv: "S"}}, false},
+		{"no path", VaultConfig{Address: "https://vault:8200", Token: "t"}, true},
+		{"no auth", VaultConfig{Address: "https://vault:8200", Path: "mm/signer"}, true},
+		{"role without secret", VaultConfig{Address: "https://vault:8200", Path: "mm/signer", AppRole: VaultAppRoleConfig{RoleID: "r"}}, true},
+		{"not a URL", VaultConfig{Address: "vault:8200", Path: "mm/signer", Token: "t"}, true},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Signer.Vault = tt.vault
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_ValidateKeystore(t *testing.T) {
+	tests := []struct {
+		name                      string
+		file, password, passwdEnv string
+		wantErr                   bool
+	}{
+		{"none", "", "", "", false},
+		{"password", "UTC--key.json", "secret", "", false},
+		{"password env", "UTC--key.json", "", "MM_KEYSTORE_PASSWORD", false},
+		{"empty password", "UTC--key.json", "", "", false},
+		{"both passwords", "UTC--key.json", "secret", "MM_KEYSTORE_PASSWORD", true},
+		{"password without file", "", "secret", "", true},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Signer.KeystoreFile, cfg.Signer.KeystorePassword, cfg.Signer.PasswordEnv = tt.file, tt.password, tt.passwdEnv
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	cfg := SignerConfig{KeystoreFile: "UTC--key.json", PasswordEnv: "MM_TEST_KEYSTORE_PASSWORD"}
+	if _, err := cfg.GetKeystorePassword(); err == nil {
+		t.Error("GetKeystorePassword should fail while the variable is unset")
+	}
+	t.Setenv("MM_TEST_KEYSTORE_PASSWORD", "secret\n")
+	if password, err := cfg.GetKeystorePassword(); err != nil || password != "secret" {
+		t.Errorf("GetKeystorePassword() = %q, %v", password, err)
+	}
+}
+
+func TestConfig_ValidateChainKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    map[uint64]SignerChainKeyConfig
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"configured chain", map[uint64]SignerChainKeyConfig{56: {PrivateKeyEnv: "MM_KEY_BSC"}}, false},
+		{"unknown chain", map[uint64]SignerChainKeyConfig{1: {PrivateKeyEnv: "MM_KEY_ETH"}}, true},
+		{"no key", map[uint64]SignerChainKeyConfig{56: {}}, true},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Signer.ChainKeys = tt.keys
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_ValidateContractWallet(t *testing.T) {
+	safe := "0x3333333333333333333333333333333333333333"
+	tests := []struct {
+		name    string
+		wallet  ContractWalletConfig
+		attest  bool
+		wantErr bool
+	}{
+		{"none", ContractWalletConfig{}, true, false},
+		{"safe", ContractWalletConfig{Address: safe, Format: "safe"}, false, false},
+		{"direct", ContractWalletConfig{Address: safe, Format: "direct"}, false, false},
+		{"unknown format", ContractWalletConfig{Address: safe, Format: "multisig"}, false, true},
+		{"zero address", ContractWalletConfig{Address: "0x0000000000000000000000000000000000000000", Format: "safe"}, false, true},
+		{"attestations", ContractWalletConfig{Address: safe, Format: "safe"}, true, true},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Signer.ContractWallet = tt.wallet
+		cfg.Depth.Attest = tt.attest
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_ValidateRemoteSigner(t *testing.T) {
+	tests := []struct {
+		name    string
+		remote  RemoteSignerConfig
+		wantErr bool
+	}{
+		{"none", RemoteSignerConfig{}, false},
+		{"mtls", RemoteSignerConfig{URL: "https://signer:8443", CertFile: "mm.pem", KeyFile: "mm-key.pem", CAFile: "ca.pem"}, false},
+		{"address", RemoteSignerConfig{URL: "https://signer:8443", Address: "0x3333333333333333333333333333333333333333"}, false},
+		{"plain http", RemoteSignerConfig{URL: "http://signer:8080"}, true},
+		{"cert without key", RemoteSignerConfig{URL: "https://signer:8443", CertFile: "mm.pem"}, true},
+		{"bad address", RemoteSignerConfig{URL: "https://signer:8443", Address: "signer"}, true},
+		{"negative retries", RemoteSignerConfig{URL: "https://signer:8443", Retries: -1}, true},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Signer.Remote = tt.remote
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_ValidateMid(t *testing.T) {
+	pool := MidSource{Name: "pcs", Type: MidSourceUniswapV2, Pool: "0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE", RPCURL: "http://127.0.0.1:8545"}
+	strategy := MidSource{Name: "strategy", Type: MidSourceStrategy, Weight: 1}
+	tests := []struct {
+		name    string
+		pairs   []MidPair
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"pool and strategy", []MidPair{{ChainID: 56, PairID: "WBNB-USDT", MinSources: 2, Sources: []MidSource{pool, strategy}}}, false},
+		{"unknown pair", []MidPair{{ChainID: 56, PairID: "WETH-USDC", Sources: []MidSource{pool}}}, true},
+		{"duplicate pair", []MidPair{{ChainID: 56, PairID: "WBNB-USDT", Sources: []MidSource{pool}}, {ChainID: 56, PairID: "WBNB-USDT", Sources: []MidSource{pool}}}, true},
+		{"no sources", []MidPair{{ChainID: 56, PairID: "WBNB-USDT"}}, true},
+		{"too many required", []MidPair{{ChainID: 56, PairID: "WBNB-USDT", MinSources: 2, Sources: []MidSource{pool}}}, true},
+		{"spread", []MidPair{{ChainID: 56, PairID: "WBNB-USDT", SpreadBps: 10000, Sources: []MidSource{pool}}}, true},
+		{"no rpc", []MidPair{{ChainID: 56, PairID: "WBNB-USDT", Sources: []MidSource{{Name: "pcs", Type: MidSourceUniswapV3, Pool: pool.Pool}}}}, true},
+		{"no pool", []MidPair{{ChainID: 56, PairID: "WBNB-USDT", Sources: []MidSource{{Name: "pcs", Type: MidSourceUniswapV2, RPCURL: pool.RPCURL}}}}, true},
+		{"unweighted strategy", []MidPair{{ChainID: 56, PairID: "WBNB-USDT", Sources: []MidSource{{Name: "strategy", Type: MidSourceStrategy}}}}, true},
+		{"unknown type", []MidPair{{ChainID: 56, PairID: "WBNB-USDT", Sources: []MidSource{{Name: "x", Type: "curve", Weight: 1}}}}, true},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Mid.Pairs = tt.pairs
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfig_ValidateAdmin(t *testing.T) {
+	tests := []struct {
+		name    string
+		admin   AdminConfig
+		wantErr bool
+	}{
+		{"disabled", AdminConfig{}, false},
+		{"valid", AdminConfig{Enabled: true, Listen: "127.0.0.1:9092", TokenEnv: "MM_ADMIN_TOKEN"}, false},
+		{"no token", AdminConfig{Enabled: true, Listen: "127.0.0.1:9092"}, true},
+		{"bad listen", AdminConfig{Enabled: true, Listen: "9092", Token: "t"}, true},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Admin = tt.admin
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+	cfg := validConfig()
+	cfg.Pairs[0].SpreadBps = 10000
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() accepted pairs[0].spreadBps 10000")
+	}
+}
+
+func TestConfig_ValidateChains(t *testing.T) {
+	tests := []struct {
+		name    string
+		chains  []ChainConfig
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"valid", []ChainConfig{{ChainID: 56, PauseQuoting: true}, {ChainID: 8453, PauseDepth: true}}, false},
+		{"unknown chain", []ChainConfig{{ChainID: 1, PauseDepth: true}}, true},
+		{"duplicate", []ChainConfig{{ChainID: 56}, {ChainID: 56}}, true},
+		{"registry entry", []ChainConfig{{ChainID: 56, Name: "bnb", WrappedNative: wbnb, Explorer: "https://bscscan.com"}}, false},
+		{"invalid wrapped native", []ChainConfig{{ChainID: 56, WrappedNative: "0xbb4c"}}, true},
+		{"invalid explorer", []ChainConfig{{ChainID: 56, Explorer: "bscscan.com"}}, true},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Chains = tt.chains
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	cfg := validConfig()
+	cfg.Chains = []ChainConfig{{ChainID: 56, PauseDepth: true}}
+	if flags := cfg.ChainFlags(56); !flags.PauseDepth || flags.PauseQuoting {
+		t.Errorf("ChainFlags(56) = %+v", flags)
+	}
+	cfg.EnableTuning().Set(TuningChange{Chains: map[uint64]ChainFlags{56: {PauseQuoting: true}}})
+	if flags := cfg.ChainFlags(56); flags.PauseDepth || !flags.PauseQuoting {
+		t.Errorf("tuned ChainFlags(56) = %+v", flags)
+	}
+	if e := cfg.Effective(); len(e.Chains) != 1 || !e.Chains[0].PauseQuoting || cfg.Chains[0].PauseQuoting {
+		t.Errorf("Effective().Chains = %+v, loaded %+v", e.Chains, cfg.Chains)
+	}
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	cfg := validConfig()
+	cfg.Signer.PrivateKey = "0xsecret-key"
+	cfg.Signer.Pool.Keys = []SignerKeyConfig{{Name: "hot", PrivateKey: "0xsecret-pool"}}
+	cfg.Signer.Backup.PrivateKey = "0xsecret-backup"
+	cfg.Signer.Vault = VaultConfig{Token: "secret-vault", AppRole: VaultAppRoleConfig{SecretID: "secret-approle"}}
+	cfg.Signer.KeystorePassword = "secret-keystore"
+	cfg.Signer.ChainKeys = map[uint64]SignerChainKeyConfig{56: {PrivateKey: "0xsecret-chain"}}
+	cfg.Admin.Token = "secret-admin"
+	cfg.Webhook.Endpoints = []WebhookEndpoint{{URL: "https://example.com", Secret: "secret-hook"}}
+
+	var out strings.Builder
+	if err := cfg.Print(&out); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+	if strings.Contains(out.String(), "secret-") || strings.Contains(out.String(), "apiToken: "+cfg.WebSocket.APIToken) {
+		t.Errorf("printed config leaks a secret:\n%s", out.String())
+	}
+	if cfg.Signer.PrivateKey != "0xsecret-key" || cfg.Signer.Pool.Keys[0].PrivateKey != "0xsecret-pool" || cfg.Webhook.Endpoints[0].Secret != "secret-hook" {
+		t.Error("Redacted modified the config")
+	}
+}
+
+func TestConfig_ValidateInstances(t *testing.T) {
+	tests := []struct {
+		name      string
+		instances []InstanceConfig
+		wantErr   bool
+	}{
+		{"valid", []InstanceConfig{
+			{Name: "a", PrivateKeyEnv: "MM_KEY_A", APIToken: "token-a", Pairs: []string{"WBNB-USDT"}},
+			{Name: "b", PrivateKeyEnv: "MM_KEY_B", APIToken: "token-b"},
+		}, false},
+		{"no name", []InstanceConfig{{PrivateKeyEnv: "MM_KEY_A"}}, true},
+		{"path name", []InstanceConfig{{Name: "a/b", PrivateKeyEnv: "MM_KEY_A"}}, true},
+		{"duplicate name", []InstanceConfig{
+			{Name: "a", PrivateKeyEnv: "MM_KEY_A", APIToken: "token-a"},
+			{Name: "a", PrivateKeyEnv: "MM_KEY_B", APIToken: "token-b"},
+		}, true},
+		{"no key", []InstanceConfig{{Name: "a"}}, true},
+		{"shared token", []InstanceConfig{
+			{Name: "a", PrivateKeyEnv: "MM_KEY_A"},
+			{Name: "b", PrivateKeyEnv: "MM_KEY_B"},
+		}, true},
+		{"unknown pair", []InstanceConfig{{Name: "a", PrivateKeyEnv: "MM_KEY_A", Pairs: []string{"FOO-BAR"}}}, true},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Instances = tt.instances
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	cfg := validConfig()
+	cfg.Instances = []InstanceConfig{{Name: "a", PrivateKeyEnv: "MM_KEY_A"}}
+	cfg.Query = QueryConfig{Enabled: true, Listen: "127.0.0.1:9465"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for the query API with instances")
+	}
+}
+
+func TestConfig_ForInstances(t *testing.T) {
+	cfg := validConfig()
+	if got := cfg.ForInstances(); len(got) != 1 || got[0] != cfg {
+		t.Fatalf("ForInstances() without instances = %v, want the config itself", got)
+	}
+
+	cfg.Signer = SignerConfig{PrivateKeyEnv: "MM_KEY", MaxDeadlineHorizon: time.Minute}
+	cfg.Store = StoreConfig{Backend: "file", Path: "data/state"}
+	cfg.Recorder.Path = "logs/session.jsonl"
+	cfg.Metrics = MetricsConfig{Enabled: true, Listen: "127.0.0.1:9464"}
+	cfg.Instances = []InstanceConfig{
+		{Name: "a", PrivateKeyEnv: "MM_KEY_A", Pairs: []string{"WETH-USDC"}},
+		{Name: "b", PrivateKeyEnv: "MM_KEY_B", ServerURL: "ws://127.0.0.2/ws", APIToken: "token-b"},
+	}
+	cfg.BuildIndex()
+	got := cfg.ForInstances()
+	if len(got) != 2 {
+		t.Fatalf("ForInstances() returned %d configs, want 2", len(got))
+	}
+
+	a, b := got[0], got[1]
+	if a.App.Name != "a" || a.Signer.PrivateKeyEnv != "MM_KEY_A" || a.Signer.MaxDeadlineHorizon != time.Minute {
+		t.Errorf("instance a: name %q, signer %+v, want a with MM_KEY_A and the shared horizon", a.App.Name, a.Signer)
+	}
+	if a.WebSocket.APIToken != "token" || b.WebSocket.APIToken != "token-b" || b.WebSocket.ServerURL != "ws://127.0.0.2/ws" {
+		t.Errorf("connections = %+v / %+v, want the shared token for a and b's own", a.WebSocket, b.WebSocket)
+	}
+	if len(a.Pairs) != 1 || a.Pairs[0].PairID != "WETH-USDC" || len(b.Pairs) != 3 {
+		t.Errorf("pairs = %d / %d, want only WETH-USDC for a and all for b", len(a.Pairs), len(b.Pairs))
+	}
+	if a.GetPairConfig(56, wbnb, usdt) != nil || a.GetPairConfig(8453, weth, usdc) == nil {
+		t.Error("instance a index does not match its pairs")
+	}
+	if a.Store.Path != "data/state/a" || b.Recorder.Path != "logs/session-b.jsonl" {
+		t.Errorf("paths = %q / %q, want per-instance state and recording", a.Store.Path, b.Recorder.Path)
+	}
+	if a.Metrics.Enabled || len(a.Instances) != 0 {
+		t.Error("instance configs must not serve metrics or nest instances")
+	}
+}
+
+func TestConfig_ValidateNonce(t *testing.T) {
+	cfg := validConfig()
+	cfg.Quote.Nonce = NonceConfig{Mode: "issue", Scope: "pool"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	cfg.Quote.Nonce.Mode = "random"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for an unknown nonce mode")
+	}
+	cfg.Quote.Nonce = NonceConfig{Mode: "guard", Scope: "global"}
+	cfg.Store.Backend = "file"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for an unknown nonce scope")
+	}
+}
+
+func TestConfig_ValidateNonceGuardNeedsPersistentStore(t *testing.T) {
+	for backend, wantErr := range map[string]bool{"": true, "memory": true, "file": false, "sqlite": false, "badger": false} {
+		cfg := validConfig()
+		cfg.Quote.Nonce = NonceConfig{Mode: "guard"}
+		cfg.Store.Backend = backend
+		if err := cfg.Validate(); (err != nil) != wantErr {
+			t.Errorf("backend %q: Validate() = %v, wantErr %v", backend, err, wantErr)
+		}
+	}
+	cfg := validConfig()
+	cfg.Quote.Nonce = NonceConfig{Mode: "issue"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("issue mode with the memory store: Validate() = %v, want nil", err)
+	}
+}
+
+func TestConfig_ValidateDomainFields(t *testing.T) {
+	cfg := validConfig()
+	cfg.EIP712Domains[0].Fields = []string{"name", "chainId", "verifyingContract", "salt"}
+	cfg.EIP712Domains[0].Salt = "0x" + strings.Repeat("ab", 32)
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	cfg.EIP712Domains[0].Salt = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for a salt field without a salt")
+	}
+	cfg.EIP712Domains[0].Fields = []string{"name", "owner"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for an unknown domain field")
+	}
+}
+
+func TestConfig_ChainRegistry(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		cfg := validConfig()
+		cfg.Chains = []ChainConfig{{ChainID: 8453, Name: "base-mainnet", WrappedNative: usdc}}
+		if indexed {
+			cfg.BuildIndex()
+		}
+		registry := cfg.ChainRegistry()
+		if got := registry.Name(8453); got != "base-mainnet" {
+			t.Errorf("indexed %v: Name(8453) = %s, want the configured name", indexed, got)
+		}
+		if got, _ := registry.WrappedNative(8453); got != common.HexToAddress(usdc) {
+			t.Errorf("indexed %v: WrappedNative(8453) = %s, want the configured token", indexed, got.Hex())
+		}
+		if got := registry.Name(56); got != "bsc" {
+			t.Errorf("indexed %v: Name(56) = %s, want the built-in name", indexed, got)
+		}
+	}
+}
+
+func TestConfig_ValidatePairExtraData(t *testing.T) {
+	cfg := validConfig()
+	cfg.Pairs[0].ExtraData = "v3-pool-hedge"
+	cfg.Pairs[0].HedgePool = "0x2222222222222222222222222222222222222222"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	cfg.Pairs[0].HedgePool = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for a hedge without a pool")
+	}
+	cfg.Pairs[0].ExtraData = "v2-hedge"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for an unknown extraData mode")
+	}
+}
+
+func TestConfig_ValidatePairBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(p *PairConfig)
+		wantErr bool
+	}{
+		{"minimums and notional bounds", func(p *PairConfig) {
+			p.BaseTokenDecimals, p.QuoteTokenDecimals = 18, 18
+			p.MinBaseIn, p.MaxBaseIn, p.MinQuoteIn = "0.1", "50", "10"
+			p.MinNotional, p.MaxNotional = "10", "100000"
+		}, false},
+		{"invalid minimum", func(p *PairConfig) { p.MinQuoteIn = "-1" }, true},
+		{"minimum above maximum", func(p *PairConfig) { p.MinBaseIn, p.MaxBaseIn = "2", "1" }, true},
+		{"zero notional", func(p *PairConfig) { p.MaxNotional = "0" }, true},
+		{"notional minimum above maximum", func(p *PairConfig) { p.MinNotional, p.MaxNotional = "100", "10" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(&cfg.Pairs[0])
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"io"
+	"maps"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secrets in printed configs
+const redacted = "<redacted>"
+
+// Print writes c as YAML with its secrets redacted
+// Defaults are filled in, so the output shows the values the MM runs with.
+func (c *Config) Print(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c.Redacted()); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// Redacted returns a copy of c with private keys, API tokens and secrets replaced
+// Environment variable names are kept: they are not secrets.
+func (c *Config) Redacted() *Config {
+	r := *c
+	hide := func(s *string) {
+		if *s != "" {
+			*s = redacted
+		}
+	}
+	hidePool := func(pool *SignerPoolConfig) {
+		pool.Keys = slices.Clone(pool.Keys)
+		for i := range pool.Keys {
+			hide(&pool.Keys[i].PrivateKey)
+		}
+	}
+
+	hide(&r.Signer.PrivateKey)
+	hidePool(&r.Signer.Pool)
+	hide(&r.Signer.Backup.PrivateKey)
+	hide(&r.Signer.Vault.Token)
+	hide(&r.Signer.Vault.AppRole.SecretID)
+	hide(&r.Signer.KeystorePassword)
+	r.Signer.ChainKeys = maps.Clone(r.Signer.ChainKeys)
+	for chainID, key := range r.Signer.ChainKeys {
+		hide(&key.PrivateKey)
+		r.Signer.ChainKeys[chainID] = key
+	}
+	hide(&r.WebSocket.APIToken)
+	hide(&r.WebSocket.Standby.APIToken)
+	hide(&r.Admin.Token)
+	hide(&r.FlowExport.Salt)
+	r.Webhook.Endpoints = slices.Clone(r.Webhook.Endpoints)
+	for i := range r.Webhook.Endpoints {
+		hide(&r.Webhook.Endpoints[i].Secret)
+	}
+	r.Instances = slices.Clone(r.Instances)
+	for i := range r.Instances {
+		hide(&r.Instances[i].PrivateKey)
+		hide(&r.Instances[i].APIToken)
+		hidePool(&r.Instances[i].Pool)
+		hide(&r.Instances[i].Backup.PrivateKey)
+	}
+	return &r
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+)
+
+// PairRef identifies a pair
+type PairRef struct {
+	ChainID uint64
+	PairID  string
+}
+
+// PairParams are the parameters of a pair that can be changed while running
+type PairParams struct {
+	SpreadBps  uint32 `json:"spreadBps"`
+	MaxBaseIn  string `json:"maxBaseIn"`
+	MaxQuoteIn string `json:"maxQuoteIn"`
+}
+
+// Validate checks the parameters against the decimals of pair
+func (p PairParams) Validate(pair *PairConfig) error {
+	if p.SpreadBps >= 10000 {
+		return fmt.Errorf("spreadBps must be below 10000")
+	}
+	if _, err := TickWei(p.MaxBaseIn, pair.BaseTokenDecimals); err != nil {
+		return fmt.Errorf("maxBaseIn: %w", err)
+	}
+	if _, err := TickWei(p.MaxQuoteIn, pair.QuoteTokenDecimals); err != nil {
+		return fmt.Errorf("maxQuoteIn: %w", err)
+	}
+	return nil
+}
+
+// ChainFlags switch features of a chain off
+// Pausing a chain isolates an incident on it (bad RPC, paused contract) from the other chains.
+type ChainFlags struct {
+	PauseQuoting bool `json:"pauseQuoting"` // Reject the chain's quote requests
+	PauseDepth   bool `json:"pauseDepth"`   // Push no depth for the chain's pairs
+}
+
+// Tuning holds the values changed while running, over the loaded ones
+// The quote handler and depth pusher read parameters through the Config methods below, so a
+// change applies to the next quote and push. Tuned values are lost on restart.
+type Tuning struct {
+	mu            sync.RWMutex
+	validDuration time.Duration // 0 = quote.validDuration
+	pairs         map[PairRef]PairParams
+	chains        map[uint64]ChainFlags
+}
+
+// TuningChange is a set of tuned values; a zero ValidDuration and absent entries are unchanged
+type TuningChange struct {
+	ValidDuration time.Duration
+	Pairs         map[PairRef]PairParams
+	Chains        map[uint64]ChainFlags
+}
+
+// EnableTuning attaches a Tuning to c and returns it; call it before c is shared
+// Without one, the Config methods return the loaded values.
+func (c *Config) EnableTuning() *Tuning {
+	if c.tuning == nil {
+		c.tuning = &Tuning{pairs: make(map[PairRef]PairParams), chains: make(map[uint64]ChainFlags)}
+	}
+	return c.tuning
+}
+
+// Set applies change at once
+func (t *Tuning) Set(change TuningChange) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if change.ValidDuration > 0 {
+		t.validDuration = change.ValidDuration
+	}
+	for ref, params := range change.Pairs {
+		t.pairs[ref] = params
+	}
+	for chainID, flags := range change.Chains {
+		t.chains[chainID] = flags
+	}
+}
+
+// ValidDuration returns the longest validity of a signed quote
+func (c *Config) ValidDuration() time.Duration {
+	if t := c.tuning; t != nil {
+		t.mu.RLock()
+		defer t.mu.RUnlock()
+		if t.validDuration > 0 {
+			return t.validDuration
+		}
+	}
+	return c.Quote.ValidDuration
+}
+
+// PairParams returns the current parameters of pair
+func (c *Config) PairParams(pair *PairConfig) PairParams {
+	if t := c.tuning; t != nil {
+		t.mu.RLock()
+		defer t.mu.RUnlock()
+		if params, ok := t.pairs[PairRef{ChainID: pair.ChainID, PairID: pair.PairID}]; ok {
+			return params
+		}
+	}
+	return PairParams{SpreadBps: pair.SpreadBps, MaxBaseIn: pair.MaxBaseIn, MaxQuoteIn: pair.MaxQuoteIn}
+}
+
+// ChainFlags returns the current feature flags of a chain
+func (c *Config) ChainFlags(chainID uint64) ChainFlags {
+	if t := c.tuning; t != nil {
+		t.mu.RLock()
+		defer t.mu.RUnlock()
+		if flags, ok := t.chains[chainID]; ok {
+			return flags
+		}
+	}
+	for _, chain := range c.Chains {
+		if chain.ChainID == chainID {
+			return ChainFlags{PauseQuoting: chain.PauseQuoting, PauseDepth: chain.PauseDepth}
+		}
+	}
+	return ChainFlags{}
+}
+
+// PairMaxAmountsIn returns the current largest accepted amount_in of the base and quote token
+// of pair in native units, nil = unlimited (see PairConfig.MaxAmountsIn)
+func (c *Config) PairMaxAmountsIn(pair *PairConfig) (base, quote *big.Int) {
+	params := c.PairParams(pair)
+	base, _ = TickWei(params.MaxBaseIn, pair.BaseTokenDecimals)
+	quote, _ = TickWei(params.MaxQuoteIn, pair.QuoteTokenDecimals)
+	return base, quote
+}
+
+// Effective returns a copy of c holding the current values of the tuned parameters
+func (c *Config) Effective() *Config {
+	e := *c
+	e.Quote.ValidDuration = c.ValidDuration()
+	e.Pairs = make([]PairConfig, len(c.Pairs))
+	for i := range c.Pairs {
+		pair := c.Pairs[i]
+		params := c.PairParams(&pair)
+		pair.SpreadBps, pair.MaxBaseIn, pair.MaxQuoteIn = params.SpreadBps, params.MaxBaseIn, params.MaxQuoteIn
+		e.Pairs[i] = pair
+	}
+	e.Chains = nil
+	for _, chainID := range c.ChainIDs() {
+		flags := c.ChainFlags(chainID)
+		if i := slices.IndexFunc(c.Chains, func(chain ChainConfig) bool { return chain.ChainID == chainID }); i >= 0 {
+			chain := c.Chains[i]
+			chain.PauseQuoting, chain.PauseDepth = flags.PauseQuoting, flags.PauseDepth
+			e.Chains = append(e.Chains, chain)
+		} else if flags != (ChainFlags{}) {
+			e.Chains = append(e.Chains, ChainConfig{ChainID: chainID, PauseQuoting: flags.PauseQuoting, PauseDepth: flags.PauseDepth})
+		}
+	}
+	e.BuildIndex()
+	return &e
+}
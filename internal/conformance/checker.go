@@ -0,0 +1,288 @@
+package conformance
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"sync"
+	"time"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// Protocol invariant rule names
+const (
+	RuleAckBeforeData  = "ack_before_data"  // No depth or quote traffic before a successful ConnectionAck
+	RulePongDeadline   = "pong_deadline"    // Server ping answered with pong within the deadline
+	RuleQuoteDeadline  = "quote_deadline"   // Quote answered before its deadline and the quote timeout
+	RuleUnknownQuote   = "unknown_quote"    // Quote answer references a quote the server never requested
+	RuleAddressCasing  = "address_casing"   // Addresses are lowercase 0x-prefixed 20-byte hex
+	RuleIntegerAmount  = "integer_amount"   // Amounts are base-10 integers in native decimals
+	RuleDecimalPrice   = "decimal_price"    // Prices are positive plain decimals (no exponent)
+	RuleLevelOrdering  = "level_ordering"   // Asks ascending, bids descending
+	RuleSignatureShape = "signature_length" // Signatures are 65 bytes with v in {27, 28}
+	RuleMessageType    = "message_type"     // Message type matches its payload
+)
+
+var (
+	lowerAddressRe = regexp.MustCompile(`^0x[0-9a-f]{40}$`)
+	integerRe      = regexp.MustCompile(`^[0-9]+$`)
+	plainDecimalRe = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+)
+
+// Violation is a broken protocol invariant
+type Violation struct {
+	Rule   string
+	Detail string
+	At     time.Time
+}
+
+// String returns the string representation of the violation
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Rule, v.Detail)
+}
+
+// Config checker configuration
+type Config struct {
+	PongDeadline time.Duration // Maximum time to answer a server ping
+	QuoteTimeout time.Duration // Maximum time to answer a quote request (0 = deadline only)
+}
+
+// DefaultConfig returns default configuration
+func DefaultConfig() *Config {
+	return &Config{
+		PongDeadline: 5 * time.Second,
+		QuoteTimeout: 0,
+	}
+}
+
+// Checker validates the message stream of one MM session against protocol invariants
+// Feed it every server->MM message with OnServerMessage and every MM->server message
+// with OnClientMessage, then inspect Violations
+type Checker struct {
+	config *Config
+
+	mu           sync.Mutex
+	acked        bool
+	pendingPings []time.Time
+	quotes       map[string]pendingQuote
+	violations   []Violation
+}
+
+// pendingQuote a quote request awaiting an answer
+type pendingQuote struct {
+	receivedAt time.Time
+	deadline   int64
+}
+
+// NewChecker creates a protocol checker
+func NewChecker(config *Config) *Checker {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return &Checker{
+		config: config,
+		quotes: make(map[string]pendingQuote),
+	}
+}
+
+// OnServerMessage records a message sent by the server
+func (c *Checker) OnServerMessage(msg *mmv1.Message) {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch payload := msg.Payload.(type) {
+	case *mmv1.Message_ConnectionAck:
+		c.acked = payload.ConnectionAck.GetSuccess()
+	case *mmv1.Message_Heartbeat:
+		if payload.Heartbeat.GetPing() {
+			c.pendingPings = append(c.pendingPings, now)
+		}
+	case *mmv1.Message_QuoteRequest:
+		req := payload.QuoteRequest
+		c.quotes[req.QuoteId] = pendingQuote{receivedAt: now, deadline: req.Deadline}
+	}
+}
+
+// OnClientMessage validates a message sent by the MM
+func (c *Checker) OnClientMessage(msg *mmv1.Message) {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checkMessageType(msg)
+
+	switch payload := msg.Payload.(type) {
+	case *mmv1.Message_DepthSnapshot:
+		c.requireAck("depth snapshot")
+		c.checkDepthSnapshot(payload.DepthSnapshot)
+	case *mmv1.Message_QuoteResponse:
+		c.requireAck("quote response")
+		resp := payload.QuoteResponse
+		c.checkQuoteAnswer(resp.QuoteId, now)
+		c.checkAddress("quote_response.mm_id", resp.MmId)
+		c.checkSignedOrder(resp.Order)
+	case *mmv1.Message_QuoteReject:
+		c.requireAck("quote reject")
+		reject := payload.QuoteReject
+		c.checkQuoteAnswer(reject.QuoteId, now)
+		c.checkAddress("quote_reject.mm_id", reject.MmId)
+	case *mmv1.Message_Heartbeat:
+		if payload.Heartbeat.GetPong() && len(c.pendingPings) > 0 {
+			c.pendingPings = c.pendingPings[1:]
+		}
+	}
+}
+
+// Finish checks for invariants that can only be broken by silence (unanswered pings and quotes)
+// Call it when the session ends
+func (c *Checker) Finish() {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, sentAt := range c.pendingPings {
+		if now.Sub(sentAt) > c.config.PongDeadline {
+			c.addViolation(RulePongDeadline, fmt.Sprintf("ping sent at %s never answered", sentAt.Format(time.RFC3339Nano)))
+		}
+	}
+	c.pendingPings = nil
+
+	for quoteID, q := range c.quotes {
+		if q.deadline < now.Unix() || (c.config.QuoteTimeout > 0 && now.Sub(q.receivedAt) > c.config.QuoteTimeout) {
+			c.addViolation(RuleQuoteDeadline, fmt.Sprintf("quote %s never answered", quoteID))
+		}
+	}
+	c.quotes = make(map[string]pendingQuote)
+}
+
+// Violations returns all recorded violations
+func (c *Checker) Violations() []Violation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]Violation, len(c.violations))
+	copy(out, c.violations)
+	return out
+}
+
+// checkMessageType checks the message type matches the payload
+func (c *Checker) checkMessageType(msg *mmv1.Message) {
+	var want mmv1.MessageType
+	switch msg.Payload.(type) {
+	case *mmv1.Message_DepthSnapshot:
+		want = mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT
+	case *mmv1.Message_QuoteResponse:
+		want = mmv1.MessageType_MESSAGE_TYPE_QUOTE_RESPONSE
+	case *mmv1.Message_QuoteReject:
+		want = mmv1.MessageType_MESSAGE_TYPE_QUOTE_REJECT
+	case *mmv1.Message_Heartbeat:
+		want = mmv1.MessageType_MESSAGE_TYPE_HEARTBEAT
+	case *mmv1.Message_Error:
+		want = mmv1.MessageType_MESSAGE_TYPE_ERROR
+	default:
+		return
+	}
+	if msg.Type != want {
+		c.addViolation(RuleMessageType, fmt.Sprintf("type %s carries %s payload", msg.Type, want))
+	}
+}
+
+// requireAck checks a successful ConnectionAck was received
+func (c *Checker) requireAck(what string) {
+	if !c.acked {
+		c.addViolation(RuleAckBeforeData, what+" sent before successful ConnectionAck")
+	}
+}
+
+// checkQuoteAnswer checks a quote is answered once, in time
+func (c *Checker) checkQuoteAnswer(quoteID string, now time.Time) {
+	q, ok := c.quotes[quoteID]
+	if !ok {
+		c.addViolation(RuleUnknownQuote, fmt.Sprintf("answer for unknown or already answered quote %q", quoteID))
+		return
+	}
+	delete(c.quotes, quoteID)
+
+	if now.Unix() > q.deadline {
+		c.addViolation(RuleQuoteDeadline, fmt.Sprintf("quote %s answered after deadline %d", quoteID, q.deadline))
+	}
+	if c.config.QuoteTimeout > 0 && now.Sub(q.receivedAt) > c.config.QuoteTimeout {
+		c.addViolation(RuleQuoteDeadline, fmt.Sprintf("quote %s answered after %s (timeout %s)",
+			quoteID, now.Sub(q.receivedAt), c.config.QuoteTimeout))
+	}
+}
+
+// checkSignedOrder checks the signed order fields
+func (c *Checker) checkSignedOrder(order *mmv1.SignedOrder) {
+	if order == nil {
+		return
+	}
+	c.checkAddress("order.signer", order.Signer)
+	c.checkAddress("order.rfq_manager", order.RfqManager)
+	c.checkInteger("order.nonce", order.Nonce)
+	c.checkInteger("order.amount_in", order.AmountIn)
+	c.checkInteger("order.amount_out", order.AmountOut)
+
+	if len(order.Signature) != 65 {
+		c.addViolation(RuleSignatureShape, fmt.Sprintf("signature length %d, want 65", len(order.Signature)))
+	} else if v := order.Signature[64]; v != 27 && v != 28 {
+		c.addViolation(RuleSignatureShape, fmt.Sprintf("signature v = %d, want 27 or 28", v))
+	}
+}
+
+// checkDepthSnapshot checks the depth snapshot fields and level ordering
+func (c *Checker) checkDepthSnapshot(snapshot *mmv1.DepthSnapshot) {
+	c.checkAddress("depth.mm_id", snapshot.MmId)
+	c.checkAddress("depth.token_a", snapshot.TokenA)
+	c.checkAddress("depth.token_b", snapshot.TokenB)
+
+	c.checkLevels(snapshot.PairId, "asks", snapshot.Asks, func(prev, cur *big.Float) bool { return cur.Cmp(prev) >= 0 })
+	c.checkLevels(snapshot.PairId, "bids", snapshot.Bids, func(prev, cur *big.Float) bool { return cur.Cmp(prev) <= 0 })
+}
+
+// checkLevels checks price/amount format and ordering of one side of the book
+func (c *Checker) checkLevels(pairID, side string, levels []*mmv1.PriceLevel, ordered func(prev, cur *big.Float) bool) {
+	var prev *big.Float
+	for i, level := range levels {
+		field := fmt.Sprintf("depth[%s].%s[%d]", pairID, side, i)
+		c.checkInteger(field+".amount", level.Amount)
+
+		if !plainDecimalRe.MatchString(level.Price) {
+			c.addViolation(RuleDecimalPrice, fmt.Sprintf("%s.price = %q is not a plain decimal", field, level.Price))
+			continue
+		}
+		price, _, err := big.ParseFloat(level.Price, 10, 256, big.ToNearestEven)
+		if err != nil || price.Sign() <= 0 {
+			c.addViolation(RuleDecimalPrice, fmt.Sprintf("%s.price = %q is not positive", field, level.Price))
+			continue
+		}
+		if prev != nil && !ordered(prev, price) {
+			c.addViolation(RuleLevelOrdering, fmt.Sprintf("%s.price %s out of order", field, level.Price))
+		}
+		prev = price
+	}
+}
+
+// checkAddress checks an address is lowercase 0x-prefixed hex
+func (c *Checker) checkAddress(field, value string) {
+	if !lowerAddressRe.MatchString(value) {
+		c.addViolation(RuleAddressCasing, fmt.Sprintf("%s = %q is not a lowercase hex address", field, value))
+	}
+}
+
+// checkInteger checks an amount is a base-10 integer string
+func (c *Checker) checkInteger(field, value string) {
+	if !integerRe.MatchString(value) {
+		c.addViolation(RuleIntegerAmount, fmt.Sprintf("%s = %q is not a base-10 integer", field, value))
+	}
+}
+
+// addViolation records a violation (caller holds c.mu)
+func (c *Checker) addViolation(rule, detail string) {
+	c.violations = append(c.violations, Violation{Rule: rule, Detail: detail, At: time.Now()})
+}
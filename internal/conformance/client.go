@@ -0,0 +1,44 @@
+package conformance
+
+import (
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// CheckingClient wraps a WSClient and feeds all traffic through a Checker
+// Used to check a real session, e.g. against a staging endpoint
+type CheckingClient struct {
+	ws.WSClient
+	checker *Checker
+}
+
+// NewCheckingClient creates a checking client
+func NewCheckingClient(inner ws.WSClient, checker *Checker) *CheckingClient {
+	return &CheckingClient{
+		WSClient: inner,
+		checker:  checker,
+	}
+}
+
+// Send checks and sends a Protobuf message
+func (c *CheckingClient) Send(msg *mmv1.Message) error {
+	c.checker.OnClientMessage(msg)
+	return c.WSClient.Send(msg)
+}
+
+// SendBatch checks and sends several Protobuf messages
+func (c *CheckingClient) SendBatch(msgs []*mmv1.Message) error {
+	for _, msg := range msgs {
+		c.checker.OnClientMessage(msg)
+	}
+	return c.WSClient.SendBatch(msgs)
+}
+
+// SetMessageHandler sets the message handler callback
+// Server messages are recorded before the handler runs
+func (c *CheckingClient) SetMessageHandler(handler ws.MessageHandler) {
+	c.WSClient.SetMessageHandler(func(msg *mmv1.Message) error {
+		c.checker.OnServerMessage(msg)
+		return handler(msg)
+	})
+}
@@ -0,0 +1,316 @@
+package conformance
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/runner"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+)
+
+const testMMID = testutil.DefaultMMID
+
+func testConfig(serverURL string) *config.Config {
+	return &config.Config{
+		App: config.AppConfig{Name: "conformance"},
+		Signer: config.SignerConfig{
+			PrivateKey: testutil.DefaultPrivKey,
+		},
+		WebSocket: config.WebSocketConfig{
+			ServerURL:         serverURL,
+			APIToken:          "test-token",
+			ReconnectInterval: time.Second,
+			HeartbeatInterval: 30 * time.Second,
+			ReadTimeout:       2 * time.Second, // Close waits for the pending read to time out
+			WriteTimeout:      5 * time.Second,
+		},
+		EIP712Domains: []config.EIP712Domain{
+			{ChainID: 56, Name: "RFQ Manager", Version: "1", VerifyingContract: "0x28D3a265f6d40867986004029ee91F4C9532fCC5"},
+		},
+		Quote: config.QuoteConfig{ValidDuration: 30 * time.Second, StoreRetention: time.Minute},
+		Depth: config.DepthConfig{Enabled: true, PushInterval: 100 * time.Millisecond},
+		Pairs: []config.PairConfig{
+			{
+				ChainID:            56,
+				PairID:             "WBNB-USDT",
+				BaseToken:          "0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c",
+				QuoteToken:         "0x55d398326f99059fF775485246999027B3197955",
+				BaseTokenDecimals:  18,
+				QuoteTokenDecimals: 18,
+			},
+		},
+	}
+}
+
+func quoteRequest(quoteID, tokenIn, tokenOut string) *mmv1.Message {
+	req := testutil.QuoteRequest()
+	req.QuoteId = quoteID
+	req.TokenIn = tokenIn
+	req.TokenOut = tokenOut
+	return testutil.NewQuoteRequest(req)
+}
+
+func runScript(t *testing.T, steps []Step) (*Checker, *Server) {
+	t.Helper()
+	return runChaosScript(t, steps, nil)
+}
+
+func runChaosScript(t *testing.T, steps []Step, chaos *Chaos) (*Checker, *Server) {
+	t.Helper()
+
+	checker := NewChecker(&Config{PongDeadline: time.Second, QuoteTimeout: time.Second})
+	server := NewServerWithChaos(checker, steps, chaos)
+	t.Cleanup(server.Close)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r, err := runner.New(testConfig(server.URL()), logger)
+	if err != nil {
+		t.Fatalf("runner.New failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx) }()
+
+	select {
+	case <-server.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for script to finish")
+	}
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	checker.Finish()
+	return checker, server
+}
+
+func TestConformance_Session(t *testing.T) {
+	wbnb := "0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c"
+	usdt := "0x55d398326f99059ff775485246999027b3197955"
+
+	checker, server := runScript(t, []Step{
+		WaitStep(200 * time.Millisecond), // Nothing may be sent before the ack
+		SendStep(testutil.NewConnectionAck(testMMID)),
+		WaitStep(250 * time.Millisecond),
+		SendStep(testutil.NewPing()),
+		SendStep(quoteRequest("quote-ok", wbnb, usdt)),
+		SendStep(quoteRequest("quote-reverse", usdt, wbnb)),
+		SendStep(quoteRequest("quote-unsupported", wbnb, "0x0000000000000000000000000000000000000001")),
+		WaitStep(500 * time.Millisecond),
+	})
+
+	for _, v := range checker.Violations() {
+		t.Errorf("Protocol violation: %s", v)
+	}
+
+	counts := make(map[mmv1.MessageType]int)
+	for _, msg := range server.Received() {
+		counts[msg.Type]++
+	}
+	if counts[mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT] == 0 {
+		t.Error("No depth snapshot received")
+	}
+	if counts[mmv1.MessageType_MESSAGE_TYPE_QUOTE_RESPONSE] != 2 {
+		t.Errorf("Quote responses = %d, want 2", counts[mmv1.MessageType_MESSAGE_TYPE_QUOTE_RESPONSE])
+	}
+	if counts[mmv1.MessageType_MESSAGE_TYPE_QUOTE_REJECT] != 1 {
+		t.Errorf("Quote rejects = %d, want 1", counts[mmv1.MessageType_MESSAGE_TYPE_QUOTE_REJECT])
+	}
+}
+
+func TestConformance_Reconnect(t *testing.T) {
+	wbnb := "0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c"
+	usdt := "0x55d398326f99059ff775485246999027b3197955"
+
+	checker, server := runScript(t, []Step{
+		SendStep(testutil.NewConnectionAck(testMMID)),
+		SendStep(quoteRequest("quote-before", wbnb, usdt)),
+		WaitStep(200 * time.Millisecond),
+		DisconnectStep(),
+		// Played on the MM's next connection
+		SendStep(testutil.NewConnectionAck(testMMID)),
+		SendStep(quoteRequest("quote-after", wbnb, usdt)),
+		WaitStep(300 * time.Millisecond),
+	})
+
+	for _, v := range checker.Violations() {
+		t.Errorf("Protocol violation: %s", v)
+	}
+	if got := server.Connections(); got != 2 {
+		t.Errorf("Connections = %d, want 2", got)
+	}
+
+	answered := make(map[string]bool)
+	for _, msg := range server.Received() {
+		if resp := msg.GetQuoteResponse(); resp != nil {
+			answered[resp.QuoteId] = true
+		}
+	}
+	for _, id := range []string{"quote-before", "quote-after"} {
+		if !answered[id] {
+			t.Errorf("Quote %s not answered", id)
+		}
+	}
+}
+
+// playChaos connects a raw client and returns the timestamps of the heartbeats it receives
+func playChaos(t *testing.T, chaos *Chaos, n int) []int64 {
+	t.Helper()
+
+	steps := make([]Step, 0, n+1)
+	for i := 0; i < n; i++ {
+		steps = append(steps, SendStep(&mmv1.Message{
+			Type:      mmv1.MessageType_MESSAGE_TYPE_HEARTBEAT,
+			Timestamp: int64(i),
+			Payload:   &mmv1.Message_Heartbeat{Heartbeat: &mmv1.Heartbeat{}},
+		}))
+	}
+	steps = append(steps, DisconnectStep())
+
+	server := NewServerWithChaos(NewChecker(&Config{}), steps, chaos)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial(server.URL(), nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	var got []int64
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return got
+		}
+		msg := &mmv1.Message{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		got = append(got, msg.Timestamp)
+	}
+}
+
+func TestServer_ChaosDeterministic(t *testing.T) {
+	chaos := &Chaos{Seed: 42, DropRate: 0.2, DuplicateRate: 0.2, ReorderRate: 0.2}
+
+	first := playChaos(t, chaos, 30)
+	second := playChaos(t, chaos, 30)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("Same seed played differently:\n%v\n%v", first, second)
+	}
+
+	ordered := make([]int64, 30)
+	for i := range ordered {
+		ordered[i] = int64(i)
+	}
+	if reflect.DeepEqual(first, ordered) {
+		t.Error("Chaos did not alter the message stream")
+	}
+}
+
+func TestChecker_DetectsViolations(t *testing.T) {
+	checker := NewChecker(&Config{PongDeadline: 0})
+
+	// Depth before ack, uppercase address, scientific notation price, unordered asks
+	checker.OnClientMessage(&mmv1.Message{
+		Type: mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT,
+		Payload: &mmv1.Message_DepthSnapshot{
+			DepthSnapshot: &mmv1.DepthSnapshot{
+				PairId: "WBNB-USDT",
+				MmId:   "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf",
+				TokenA: "0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c",
+				TokenB: "0x55d398326f99059ff775485246999027b3197955",
+				Asks: []*mmv1.PriceLevel{
+					{Price: "601", Amount: "1000"},
+					{Price: "600", Amount: "1000"},
+					{Price: "6e2", Amount: "1.5"},
+				},
+			},
+		},
+	})
+
+	// Unanswered ping, response for unknown quote
+	checker.OnServerMessage(testutil.NewPing())
+	checker.OnClientMessage(&mmv1.Message{
+		Type: mmv1.MessageType_MESSAGE_TYPE_QUOTE_RESPONSE,
+		Payload: &mmv1.Message_QuoteResponse{
+			QuoteResponse: &mmv1.QuoteResponse{QuoteId: "nope", MmId: testMMID},
+		},
+	})
+	time.Sleep(time.Millisecond)
+	checker.Finish()
+
+	found := make(map[string]bool)
+	for _, v := range checker.Violations() {
+		found[v.Rule] = true
+	}
+	for _, rule := range []string{
+		RuleAckBeforeData,
+		RuleAddressCasing,
+		RuleDecimalPrice,
+		RuleIntegerAmount,
+		RuleLevelOrdering,
+		RulePongDeadline,
+		RuleUnknownQuote,
+	} {
+		if !found[rule] {
+			t.Errorf("Violation %s not detected", rule)
+		}
+	}
+}
+
+// TestConformance_Staging checks a live session against a real endpoint
+// Set MM_CONFORMANCE_CONFIG to a config file and optionally MM_CONFORMANCE_DURATION (default 30s)
+func TestConformance_Staging(t *testing.T) {
+	path := os.Getenv("MM_CONFORMANCE_CONFIG")
+	if path == "" {
+		t.Skip("MM_CONFORMANCE_CONFIG not set")
+	}
+
+	duration := 30 * time.Second
+	if v := os.Getenv("MM_CONFORMANCE_DURATION"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			t.Fatalf("Invalid MM_CONFORMANCE_DURATION: %v", err)
+		}
+		duration = d
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	checker := NewChecker(DefaultConfig())
+	client := NewCheckingClient(ws.NewClient(runner.WSConfig(cfg), logger), checker)
+
+	r, err := runner.NewWithClient(cfg, logger, client)
+	if err != nil {
+		t.Fatalf("runner.NewWithClient failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+	if err := r.Run(ctx); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	checker.Finish()
+	for _, v := range checker.Violations() {
+		t.Errorf("Protocol violation: %s", v)
+	}
+}
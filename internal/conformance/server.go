@@ -0,0 +1,294 @@
+package conformance
+
+import (
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// Step is one step of a server script
+// Exactly one of Send, Wait or Disconnect should be set
+type Step struct {
+	Send       *mmv1.Message // Message sent to the MM
+	Wait       time.Duration // Pause before the next step
+	Disconnect bool          // Close the connection; remaining steps play on the MM's next connection
+}
+
+// SendStep returns a step that sends a message
+func SendStep(msg *mmv1.Message) Step {
+	return Step{Send: msg}
+}
+
+// WaitStep returns a step that pauses the script
+func WaitStep(d time.Duration) Step {
+	return Step{Wait: d}
+}
+
+// DisconnectStep returns a step that force-closes the connection
+func DisconnectStep() Step {
+	return Step{Disconnect: true}
+}
+
+// Chaos injects faults into the messages the server sends
+// All random choices come from Seed, so a script with the same chaos plays out identically
+type Chaos struct {
+	Seed            int64
+	Latency         time.Duration // Delay before each server message (pongs included)
+	Jitter          time.Duration // Extra random delay in [0, Jitter) before each script message
+	DropRate        float64       // Probability a script message is silently dropped
+	DuplicateRate   float64       // Probability a script message is sent twice
+	ReorderRate     float64       // Probability a script message is held back and sent after the next one
+	NoPong          bool          // Never answer MM pings (breaks heartbeats)
+	DisconnectEvery int           // Force-close the connection after every N script messages sent (0 = never)
+}
+
+// Observer is called with a message the server sent (sent = true) or received from the MM
+type Observer func(msg *mmv1.Message, sent bool)
+
+// Server is a scripted swap-engine stand-in
+// It plays its script to the MM and checks every message the MM sends. The script
+// position is shared across connections, so after a disconnect the script resumes
+// on the MM's next connection.
+type Server struct {
+	server  *httptest.Server
+	checker *Checker
+	steps   []Step
+	chaos   *Chaos
+
+	mu          sync.Mutex
+	received    []*mmv1.Message
+	observer    Observer
+	connections int
+	done        chan struct{}
+	once        sync.Once
+
+	scriptMu sync.Mutex // One connection plays the script at a time
+	next     int        // Next script step
+	sent     int        // Script messages sent, for DisconnectEvery
+	rng      *rand.Rand
+}
+
+// NewServer starts a scripted server
+func NewServer(checker *Checker, steps []Step) *Server {
+	return NewServerWithChaos(checker, steps, nil)
+}
+
+// NewServerWithChaos starts a scripted server that injects faults (nil chaos = none)
+func NewServerWithChaos(checker *Checker, steps []Step, chaos *Chaos) *Server {
+	s := &Server{
+		checker: checker,
+		steps:   steps,
+		chaos:   chaos,
+		done:    make(chan struct{}),
+	}
+	if chaos != nil {
+		s.rng = rand.New(rand.NewSource(chaos.Seed))
+	}
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		s.serve(conn)
+	}))
+
+	return s
+}
+
+// URL returns the WebSocket URL of the server
+func (s *Server) URL() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http")
+}
+
+// Done is closed when the script has finished playing
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
+
+// Received returns all messages received from the MM
+func (s *Server) Received() []*mmv1.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]*mmv1.Message, len(s.received))
+	copy(out, s.received)
+	return out
+}
+
+// SetObserver sets a function called with every message sent to or received from the MM
+func (s *Server) SetObserver(fn Observer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observer = fn
+}
+
+// Connections returns how many times the MM has connected
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+// Close stops the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// serve plays the rest of the script on one connection
+func (s *Server) serve(conn *websocket.Conn) {
+	s.mu.Lock()
+	s.connections++
+	s.mu.Unlock()
+
+	var writeMu sync.Mutex
+	write := func(msg *mmv1.Message) error {
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		// Observed before the write, so replies are never seen ahead of their request
+		s.checker.OnServerMessage(msg)
+		s.mu.Lock()
+		observer := s.observer
+		s.mu.Unlock()
+		if observer != nil {
+			observer(msg, true)
+		}
+
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteMessage(websocket.BinaryMessage, data)
+	}
+
+	// Read loop: check every MM message, answer MM pings
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			msg := &mmv1.Message{}
+			if err := proto.Unmarshal(data, msg); err != nil {
+				continue
+			}
+			s.checker.OnClientMessage(msg)
+
+			s.mu.Lock()
+			s.received = append(s.received, msg)
+			observer := s.observer
+			s.mu.Unlock()
+			if observer != nil {
+				observer(msg, false)
+			}
+
+			if msg.GetHeartbeat().GetPing() {
+				if s.chaos != nil {
+					if s.chaos.NoPong {
+						continue
+					}
+					time.Sleep(s.chaos.Latency)
+				}
+				_ = write(testutil.NewPong())
+			}
+		}
+	}()
+
+	if s.playScript(write) {
+		s.once.Do(func() { close(s.done) })
+	}
+	_ = conn.Close()
+
+	<-readDone
+}
+
+// playScript plays steps until the script ends (true) or the connection must be closed (false)
+func (s *Server) playScript(write func(*mmv1.Message) error) bool {
+	s.scriptMu.Lock()
+	defer s.scriptMu.Unlock()
+
+	var held *mmv1.Message // Message held back by ReorderRate
+	defer func() {
+		if held != nil {
+			_ = write(held)
+		}
+	}()
+
+	for s.next < len(s.steps) {
+		step := s.steps[s.next]
+		s.next++
+
+		if step.Disconnect {
+			return false
+		}
+		if step.Send != nil {
+			if err := s.deliver(write, step.Send, &held); err != nil {
+				return false
+			}
+			if s.chaos != nil && s.chaos.DisconnectEvery > 0 && s.sent%s.chaos.DisconnectEvery == 0 {
+				return false
+			}
+		}
+		if step.Wait > 0 {
+			time.Sleep(step.Wait)
+		}
+	}
+	return true
+}
+
+// deliver sends one script message, applying chaos
+func (s *Server) deliver(write func(*mmv1.Message) error, msg *mmv1.Message, held **mmv1.Message) error {
+	c := s.chaos
+	if c == nil {
+		s.sent++
+		return write(msg)
+	}
+
+	delay := c.Latency
+	if c.Jitter > 0 {
+		delay += time.Duration(s.rng.Int63n(int64(c.Jitter)))
+	}
+	drop := s.rng.Float64() < c.DropRate
+	reorder := s.rng.Float64() < c.ReorderRate
+	duplicate := s.rng.Float64() < c.DuplicateRate
+	time.Sleep(delay)
+
+	if drop {
+		return nil
+	}
+	if reorder && *held == nil {
+		*held = msg
+		return nil
+	}
+
+	s.sent++
+	if err := write(msg); err != nil {
+		return err
+	}
+	if duplicate {
+		if err := write(msg); err != nil {
+			return err
+		}
+	}
+	if *held != nil {
+		late := *held
+		*held = nil
+		return write(late)
+	}
+	return nil
+}
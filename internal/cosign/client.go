@@ -0,0 +1,139 @@
+// Package cosign requests second-signer approval of large quotes from a co-signing service
+package cosign
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/address"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+// approvalRequest is the JSON body posted to the co-signing service
+type approvalRequest struct {
+	QuoteID     string `json:"quoteId"`
+	ChainID     uint64 `json:"chainId"`
+	PairID      string `json:"pairId"`
+	Notional    string `json:"notional"` // Quote token units
+	RFQManager  string `json:"rfqManager"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	InputToken  string `json:"inputToken"`
+	OutputToken string `json:"outputToken"`
+	AmountIn    string `json:"amountIn"`  // Native decimals
+	AmountOut   string `json:"amountOut"` // Native decimals
+	Deadline    string `json:"deadline"`  // Unix seconds
+	Nonce       string `json:"nonce"`
+	ExtraData   string `json:"extraData"`
+	Digest      string `json:"digest"`      // EIP-712 digest of the order
+	MMSignature string `json:"mmSignature"` // The MM's signature of Digest
+}
+
+// approvalResponse is the co-signing service's answer
+type approvalResponse struct {
+	Approved  bool   `json:"approved"`
+	Signature string `json:"signature"` // Co-signer's signature of the digest, when approved
+	Reason    string `json:"reason"`
+}
+
+// Client is a quote.CoSigner backed by an HTTP co-signing service
+// An approval is accepted only when the returned signature of the order digest recovers to
+// the configured co-signer address.
+type Client struct {
+	url     string
+	address common.Address
+	domains *signer.DomainManager
+	client  *http.Client
+}
+
+// NewClient creates a co-signing client for cfg; domains are the pool domains quotes are signed with
+func NewClient(cfg config.CoSignConfig, domains *signer.DomainManager) *Client {
+	return &Client{
+		url:     cfg.URL,
+		address: common.HexToAddress(cfg.Address),
+		domains: domains,
+		client:  http.DefaultClient,
+	}
+}
+
+// Approve posts the quote to the co-signing service and verifies its signature
+func (c *Client) Approve(ctx context.Context, req *quote.ApprovalRequest) error {
+	digest, err := c.domains.Digest(req.ChainID, req.Quote)
+	if err != nil {
+		return err
+	}
+
+	q := req.Quote
+	body, err := json.Marshal(approvalRequest{
+		QuoteID:     req.QuoteID,
+		ChainID:     req.ChainID,
+		PairID:      req.PairID,
+		Notional:    req.Notional.String(),
+		RFQManager:  address.Normalize(q.RFQManager),
+		From:        address.Normalize(q.From),
+		To:          address.Normalize(q.To),
+		InputToken:  address.Normalize(q.InputToken),
+		OutputToken: address.Normalize(q.OutputToken),
+		AmountIn:    q.AmountIn.String(),
+		AmountOut:   q.AmountOut.String(),
+		Deadline:    q.Deadline.String(),
+		Nonce:       q.Nonce.String(),
+		ExtraData:   hexutil.Encode(q.ExtraData),
+		Digest:      digest.Hex(),
+		MMSignature: hexutil.Encode(req.Signature),
+	})
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("co-signer request failed: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("co-signer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("co-signer returned HTTP %d", resp.StatusCode)
+	}
+
+	var out approvalResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("invalid co-signer response: %w", err)
+	}
+	if !out.Approved {
+		return fmt.Errorf("%w: %s", quote.ErrNotApproved, out.Reason)
+	}
+	return c.verify(digest, out.Signature)
+}
+
+// verify checks that sig is the co-signer's signature of digest (v = 27/28 or 0/1)
+func (c *Client) verify(digest common.Hash, sig string) error {
+	raw, err := hexutil.Decode(sig)
+	if err != nil || len(raw) != 65 {
+		return fmt.Errorf("invalid co-signer signature %q", sig)
+	}
+	if raw[64] >= 27 {
+		raw[64] -= 27
+	}
+	pub, err := crypto.SigToPub(digest[:], raw)
+	if err != nil {
+		return fmt.Errorf("invalid co-signer signature: %w", err)
+	}
+	if addr := crypto.PubkeyToAddress(*pub); addr != c.address {
+		return fmt.Errorf("co-signer signature is from %s, want %s", addr.Hex(), c.address.Hex())
+	}
+	return nil
+}
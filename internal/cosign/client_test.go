@@ -0,0 +1,123 @@
+package cosign
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+const coSignerKey = "0000000000000000000000000000000000000000000000000000000000000003"
+
+func testApproval() *quote.ApprovalRequest {
+	return &quote.ApprovalRequest{
+		QuoteID:  "q-1",
+		ChainID:  56,
+		PairID:   "WBNB-USDT",
+		Notional: decimal.MustParse("600"),
+		Quote: &signer.MMQuote{
+			RFQManager:  common.HexToAddress("0x1111111111111111111111111111111111111111"),
+			From:        common.HexToAddress("0x2222222222222222222222222222222222222222"),
+			To:          common.HexToAddress("0x2222222222222222222222222222222222222222"),
+			InputToken:  common.HexToAddress("0x3333333333333333333333333333333333333333"),
+			OutputToken: common.HexToAddress("0x4444444444444444444444444444444444444444"),
+			AmountIn:    big.NewInt(1e18),
+			AmountOut:   big.NewInt(600e6),
+			Deadline:    big.NewInt(1700000000),
+			Nonce:       big.NewInt(7),
+			ExtraData:   []byte{},
+		},
+		Signature: make([]byte, 65),
+	}
+}
+
+// newTestClient creates a client of a co-signing service answering with respond
+func newTestClient(t *testing.T, respond func(w http.ResponseWriter, digest common.Hash)) *Client {
+	t.Helper()
+	dm := signer.NewDomainManager()
+	dm.AddPoolDomainWithConfig(56, "DarkPool Pool", "1", "0x1111111111111111111111111111111111111111")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body approvalRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		if body.QuoteID != "q-1" || body.Notional != "600" || body.AmountOut != "600000000" {
+			t.Errorf("request = %+v, want the approval of q-1", body)
+		}
+		respond(w, common.HexToHash(body.Digest))
+	}))
+	t.Cleanup(server.Close)
+
+	key, _ := crypto.HexToECDSA(coSignerKey)
+	return NewClient(config.CoSignConfig{
+		URL:     server.URL,
+		Address: crypto.PubkeyToAddress(key.PublicKey).Hex(),
+		Timeout: time.Second,
+	}, dm)
+}
+
+// signDigest signs digest with hexKey, v = 27/28
+func signDigest(t *testing.T, hexKey string, digest common.Hash) string {
+	t.Helper()
+	key, _ := crypto.HexToECDSA(hexKey)
+	sig, err := crypto.Sign(digest[:], key)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	sig[64] += 27
+	return hexutil.Encode(sig)
+}
+
+func TestClient_Approve(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, digest common.Hash) {
+		json.NewEncoder(w).Encode(approvalResponse{Approved: true, Signature: signDigest(t, coSignerKey, digest)})
+	})
+	if err := client.Approve(context.Background(), testApproval()); err != nil {
+		t.Errorf("Approve = %v, want nil", err)
+	}
+}
+
+func TestClient_ApproveRejected(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, _ common.Hash) {
+		json.NewEncoder(w).Encode(approvalResponse{Reason: "over daily limit"})
+	})
+	err := client.Approve(context.Background(), testApproval())
+	if !errors.Is(err, quote.ErrNotApproved) {
+		t.Errorf("Approve = %v, want ErrNotApproved", err)
+	}
+}
+
+func TestClient_ApproveWrongSigner(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, digest common.Hash) {
+		other := "0000000000000000000000000000000000000000000000000000000000000004"
+		json.NewEncoder(w).Encode(approvalResponse{Approved: true, Signature: signDigest(t, other, digest)})
+	})
+	err := client.Approve(context.Background(), testApproval())
+	if err == nil || !strings.Contains(err.Error(), "co-signer signature is from") {
+		t.Errorf("Approve = %v, want a wrong signer error", err)
+	}
+}
+
+func TestClient_ApproveHTTPError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, _ common.Hash) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	if err := client.Approve(context.Background(), testApproval()); err == nil {
+		t.Error("Approve = nil, want an error on HTTP 503")
+	}
+}
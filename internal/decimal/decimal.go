@@ -0,0 +1,234 @@
+// Package decimal provides a fixed-point decimal type for prices
+// Values are exact multiples of 10^-Scale; results that cannot be represented are
+// rounded toward zero, so a price derived from another never moves in the MM's disfavour
+package decimal
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of fractional digits kept
+// 36 digits keep at least 15 significant digits for wei/wei prices down to 1e-21
+const Scale = 36
+
+var (
+	scaleFactor = new(big.Int).Exp(big.NewInt(10), big.NewInt(Scale), nil)
+	ten         = big.NewInt(10)
+
+	// decimalRe accepts plain decimals with an optional exponent of up to 3 digits
+	decimalRe = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]{1,3})?$`)
+)
+
+// Decimal is an immutable fixed-point number
+// The zero value is 0
+type Decimal struct {
+	v *big.Int // value * 10^Scale, nil = 0
+}
+
+// Zero is the decimal 0
+var Zero = Decimal{}
+
+// New returns unscaled * 10^exp
+func New(unscaled int64, exp int) Decimal {
+	return shift(big.NewInt(unscaled), exp)
+}
+
+// NewFromInt returns i
+func NewFromInt(i int64) Decimal {
+	return New(i, 0)
+}
+
+// NewFromBigInt returns i
+func NewFromBigInt(i *big.Int) Decimal {
+	if i == nil {
+		return Zero
+	}
+	return shift(new(big.Int).Set(i), 0)
+}
+
+// NewFromFloat returns the shortest decimal that round-trips to f
+// Panics if f is NaN or infinite
+func NewFromFloat(f float64) Decimal {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		panic(fmt.Sprintf("decimal: cannot convert %v", f))
+	}
+	d, err := Parse(strconv.FormatFloat(f, 'g', -1, 64))
+	if err != nil {
+		panic(fmt.Sprintf("decimal: cannot convert %v: %v", f, err))
+	}
+	return d
+}
+
+// Parse parses a decimal string, with an optional exponent ("600.5", "3.4e-9")
+// Digits beyond Scale are truncated toward zero
+func Parse(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if !decimalRe.MatchString(s) {
+		return Zero, fmt.Errorf("invalid decimal %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Zero, fmt.Errorf("invalid decimal %q", s)
+	}
+	return NewFromRat(r), nil
+}
+
+// MustParse is like Parse but panics on error, for constants
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// NewFromRat returns r truncated toward zero to Scale digits
+func NewFromRat(r *big.Rat) Decimal {
+	num := new(big.Int).Mul(r.Num(), scaleFactor)
+	return Decimal{v: num.Quo(num, r.Denom())}
+}
+
+// shift returns i * 10^exp, truncating toward zero
+func shift(i *big.Int, exp int) Decimal {
+	exp += Scale
+	if exp >= 0 {
+		return Decimal{v: i.Mul(i, new(big.Int).Exp(ten, big.NewInt(int64(exp)), nil))}
+	}
+	return Decimal{v: i.Quo(i, new(big.Int).Exp(ten, big.NewInt(int64(-exp)), nil))}
+}
+
+// unscaled returns the scaled integer, never nil
+func (d Decimal) unscaled() *big.Int {
+	if d.v == nil {
+		return new(big.Int)
+	}
+	return d.v
+}
+
+// Add returns d + e
+func (d Decimal) Add(e Decimal) Decimal {
+	return Decimal{v: new(big.Int).Add(d.unscaled(), e.unscaled())}
+}
+
+// Sub returns d - e
+func (d Decimal) Sub(e Decimal) Decimal {
+	return Decimal{v: new(big.Int).Sub(d.unscaled(), e.unscaled())}
+}
+
+// Mul returns d * e, truncated toward zero
+func (d Decimal) Mul(e Decimal) Decimal {
+	v := new(big.Int).Mul(d.unscaled(), e.unscaled())
+	return Decimal{v: v.Quo(v, scaleFactor)}
+}
+
+// MulInt returns d * i (exact)
+func (d Decimal) MulInt(i *big.Int) Decimal {
+	return Decimal{v: new(big.Int).Mul(d.unscaled(), i)}
+}
+
+// Quo returns d / e, truncated toward zero
+// Panics if e is zero
+func (d Decimal) Quo(e Decimal) Decimal {
+	if e.Sign() == 0 {
+		panic("decimal: division by zero")
+	}
+	v := new(big.Int).Mul(d.unscaled(), scaleFactor)
+	return Decimal{v: v.Quo(v, e.unscaled())}
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{v: new(big.Int).Neg(d.unscaled())}
+}
+
+// Sign returns -1, 0 or 1
+func (d Decimal) Sign() int {
+	return d.unscaled().Sign()
+}
+
+// IsZero reports whether d is 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp compares d and e: -1 if d < e, 0 if equal, 1 if d > e
+func (d Decimal) Cmp(e Decimal) int {
+	return d.unscaled().Cmp(e.unscaled())
+}
+
+// Int returns the integer part of d (truncated toward zero)
+func (d Decimal) Int() *big.Int {
+	return new(big.Int).Quo(d.unscaled(), scaleFactor)
+}
+
+// Rat returns d as an exact rational
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.unscaled(), scaleFactor)
+}
+
+// Float64 returns the nearest float64, for logging and ratios
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// RoundSignificant returns d rounded to digits significant digits
+// Rounds toward zero, or away from zero when up is set; digits <= 0 returns d unchanged
+func (d Decimal) RoundSignificant(digits int, up bool) Decimal {
+	v := new(big.Int).Abs(d.unscaled())
+	drop := len(v.String()) - digits
+	if digits <= 0 || v.Sign() == 0 || drop <= 0 {
+		return d
+	}
+	step := new(big.Int).Exp(ten, big.NewInt(int64(drop)), nil)
+	q, r := new(big.Int).QuoRem(v, step, new(big.Int))
+	if up && r.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	q.Mul(q, step)
+	if d.Sign() < 0 {
+		q.Neg(q)
+	}
+	return Decimal{v: q}
+}
+
+// String formats d as a plain decimal without exponent or trailing zeros
+func (d Decimal) String() string {
+	v := d.unscaled()
+	digits := new(big.Int).Abs(v).String()
+	if len(digits) <= Scale {
+		digits = strings.Repeat("0", Scale-len(digits)+1) + digits
+	}
+	intPart, frac := digits[:len(digits)-Scale], strings.TrimRight(digits[len(digits)-Scale:], "0")
+
+	var b strings.Builder
+	if v.Sign() < 0 {
+		b.WriteByte('-')
+	}
+	b.WriteString(intPart)
+	if frac != "" {
+		b.WriteByte('.')
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,149 @@
+package decimal
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"600", "600"},
+		{"600.500", "600.5"},
+		{"-1.25", "-1.25"},
+		{"3.4e-9", "0.0000000034"},
+		{"1e3", "1000"},
+		{".5", "0.5"},
+		{"0", "0"},
+		{"0.0000000000000000000000000000000000019", "0.000000000000000000000000000000000001"}, // truncated at Scale
+	}
+	for _, tt := range tests {
+		d, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got := d.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "abc", "1/3", "0x10", "1e100000", "1.2.3", "NaN"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestNewFromFloat(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{600, "600"},
+		{0.1, "0.1"},
+		{3.4e-9, "0.0000000034"},
+		{1.0005, "1.0005"},
+		{-2.5, "-2.5"},
+	}
+	for _, tt := range tests {
+		if got := NewFromFloat(tt.in).String(); got != tt.want {
+			t.Errorf("NewFromFloat(%v) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a := MustParse("600")
+	b := MustParse("0.999")
+
+	if got := a.Mul(b).String(); got != "599.4" {
+		t.Errorf("Mul = %s, want 599.4", got)
+	}
+	if got := a.Add(b).String(); got != "600.999" {
+		t.Errorf("Add = %s, want 600.999", got)
+	}
+	if got := a.Sub(b).String(); got != "599.001" {
+		t.Errorf("Sub = %s, want 599.001", got)
+	}
+
+	// 1/3 truncates toward zero, and so do negative results
+	third := NewFromInt(1).Quo(NewFromInt(3))
+	if got := third.String(); got != "0."+repeat('3', Scale) {
+		t.Errorf("1/3 = %s", got)
+	}
+	if got := NewFromInt(-1).Quo(NewFromInt(3)).String(); got != "-0."+repeat('3', Scale) {
+		t.Errorf("-1/3 = %s", got)
+	}
+	if third.Mul(NewFromInt(3)).Cmp(NewFromInt(1)) >= 0 {
+		t.Error("1/3 * 3 should be below 1 after truncation")
+	}
+
+	amount, _ := new(big.Int).SetString("1000000000000000000", 10)
+	if got := a.MulInt(amount).Int().String(); got != "600000000000000000000" {
+		t.Errorf("MulInt.Int = %s, want 600000000000000000000", got)
+	}
+	if got := MustParse("-1.9").Int().String(); got != "-1" {
+		t.Errorf("Int(-1.9) = %s, want -1", got)
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	var d Decimal
+	if !d.IsZero() || d.String() != "0" {
+		t.Errorf("Zero value = %s, want 0", d)
+	}
+	if got := d.Add(NewFromInt(2)).String(); got != "2" {
+		t.Errorf("0 + 2 = %s, want 2", got)
+	}
+	if d.Cmp(Zero) != 0 {
+		t.Error("Zero value != Zero")
+	}
+}
+
+func TestText(t *testing.T) {
+	var d Decimal
+	if err := d.UnmarshalText([]byte("1.5")); err != nil {
+		t.Fatalf("UnmarshalText failed: %v", err)
+	}
+	text, _ := d.MarshalText()
+	if string(text) != "1.5" {
+		t.Errorf("MarshalText = %s, want 1.5", text)
+	}
+	if err := d.UnmarshalText([]byte("x")); err == nil {
+		t.Error("UnmarshalText accepted x")
+	}
+}
+
+func TestRoundSignificant(t *testing.T) {
+	tests := []struct {
+		in     string
+		digits int
+		up     bool
+		want   string
+	}{
+		{"123.456", 4, false, "123.4"},
+		{"123.456", 4, true, "123.5"},
+		{"123.4", 4, true, "123.4"},
+		{"0.000000003456", 2, false, "0.0000000034"},
+		{"0.000000003456", 2, true, "0.0000000035"},
+		{"-1.25", 2, true, "-1.3"},
+		{"1.25", 0, true, "1.25"},
+		{"999.9", 3, true, "1000"},
+	}
+	for _, tt := range tests {
+		if got := MustParse(tt.in).RoundSignificant(tt.digits, tt.up).String(); got != tt.want {
+			t.Errorf("RoundSignificant(%s, %d, %v) = %s, want %s", tt.in, tt.digits, tt.up, got, tt.want)
+		}
+	}
+}
+
+func repeat(c byte, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = c
+	}
+	return string(b)
+}
@@ -0,0 +1,329 @@
+// Package demo runs the market maker against an in-process swap engine
+// It needs no credentials or network access and prints the RFQ to signed-quote flow
+package demo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/conformance"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/runner"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// Demo chain, tokens and RFQ Manager (BSC mainnet addresses, nothing is sent on-chain)
+const (
+	chainID      = uint64(56)
+	pairID       = "WBNB-USDT"
+	wbnb         = "0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c"
+	usdt         = "0x55d398326f99059ff775485246999027b3197955"
+	unsupported  = "0x0000000000000000000000000000000000000001"
+	rfqManager   = "0x28D3a265f6d40867986004029ee91F4C9532fCC5"
+	demoUser     = "0x1234567890123456789012345678901234567890"
+	tokenDecimal = 18
+)
+
+// symbols maps demo token addresses to symbols
+var symbols = map[string]string{
+	wbnb:        "WBNB",
+	usdt:        "USDT",
+	unsupported: "UNKNOWN",
+}
+
+// Options demo settings
+type Options struct {
+	Requests int           // Quote requests to send; the last one is for an unsupported pair
+	Interval time.Duration // Pause between requests
+	Seed     int64         // Seed of the request amounts and the mock strategy
+}
+
+// DefaultOptions returns the default demo settings
+func DefaultOptions() Options {
+	return Options{
+		Requests: 5,
+		Interval: time.Second,
+		Seed:     1,
+	}
+}
+
+// Summary is the outcome of a demo run
+type Summary struct {
+	Requests       int
+	Signed         int // Quote responses received
+	Verified       int // Quote responses whose signature recovers to the MM signer
+	Rejected       int
+	DepthSnapshots int
+	Violations     []conformance.Violation
+}
+
+// Config returns the MM configuration used by the demo
+func Config(serverURL, privateKey string, seed int64) *config.Config {
+	return &config.Config{
+		App:    config.AppConfig{Name: "mm-demo", LogLevel: "info"},
+		Signer: config.SignerConfig{PrivateKey: privateKey},
+		WebSocket: config.WebSocketConfig{
+			ServerURL:         serverURL,
+			APIToken:          "demo-token",
+			ReconnectInterval: time.Second,
+			HeartbeatInterval: 30 * time.Second,
+			ReadTimeout:       2 * time.Second,
+			WriteTimeout:      5 * time.Second,
+		},
+		EIP712Domains: []config.EIP712Domain{
+			{ChainID: chainID, Name: "RFQ Manager", Version: "1", VerifyingContract: rfqManager},
+		},
+		Quote: config.QuoteConfig{ValidDuration: 30 * time.Second, StoreRetention: time.Minute},
+		Depth: config.DepthConfig{Enabled: true, PushInterval: time.Second},
+		Pairs: []config.PairConfig{
+			{
+				ChainID:            chainID,
+				PairID:             pairID,
+				BaseToken:          wbnb,
+				QuoteToken:         usdt,
+				BaseTokenDecimals:  tokenDecimal,
+				QuoteTokenDecimals: tokenDecimal,
+			},
+		},
+		Mock:     config.MockConfig{Seed: seed},
+		Strategy: config.StrategyConfig{Name: "mock"},
+	}
+}
+
+// Run starts an in-process swap engine, connects the MM to it and prints the RFQ flow to out
+// logger receives the MM's own logs
+func Run(ctx context.Context, opts Options, out io.Writer, logger *slog.Logger) (*Summary, error) {
+	if opts.Requests < 1 {
+		return nil, fmt.Errorf("requests must be at least 1")
+	}
+
+	// Throwaway signing key, so the demo needs no credentials
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	mmID := strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+	domain := &signer.EIP712Domain{
+		Name:              "RFQ Manager",
+		Version:           "1",
+		ChainID:           new(big.Int).SetUint64(chainID),
+		VerifyingContract: common.HexToAddress(rfqManager),
+	}
+
+	requests := buildRequests(opts, mmID)
+	steps := []conformance.Step{
+		conformance.SendStep(testutil.NewConnectionAck(mmID)),
+		conformance.WaitStep(opts.Interval),
+	}
+	for _, req := range requests {
+		steps = append(steps,
+			conformance.SendStep(testutil.NewQuoteRequest(req)),
+			conformance.WaitStep(opts.Interval))
+	}
+
+	checker := conformance.NewChecker(conformance.DefaultConfig())
+	server := conformance.NewServer(checker, steps)
+	defer server.Close()
+
+	p := &printer{
+		out:      out,
+		mmID:     common.HexToAddress(mmID),
+		domain:   domain,
+		requests: make(map[string]*mmv1.QuoteRequest),
+		depth:    make(map[string]bool),
+		summary:  &Summary{Requests: len(requests)},
+	}
+	server.SetObserver(p.observe)
+
+	fmt.Fprintf(out, "Swap engine listening on %s\n", server.URL())
+	fmt.Fprintf(out, "MM signer %s (key generated for this demo)\n\n", crypto.PubkeyToAddress(key.PublicKey).Hex())
+
+	r, err := runner.New(Config(server.URL(), hexutil.Encode(crypto.FromECDSA(key)), opts.Seed), logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runner: %w", err)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(runCtx) }()
+
+	select {
+	case <-server.Done():
+	case <-ctx.Done():
+	case err := <-errCh:
+		return nil, fmt.Errorf("market maker stopped: %w", err)
+	}
+	cancel()
+	if err := <-errCh; err != nil {
+		return nil, fmt.Errorf("market maker stopped: %w", err)
+	}
+
+	checker.Finish()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.summary.Violations = checker.Violations()
+	return p.summary, ctx.Err()
+}
+
+// buildRequests builds the demo quote requests: random WBNB/USDT swaps, then one unsupported pair
+func buildRequests(opts Options, mmID string) []*mmv1.QuoteRequest {
+	rng := rand.New(rand.NewSource(opts.Seed))
+	start := time.Now()
+
+	requests := make([]*mmv1.QuoteRequest, opts.Requests)
+	for i := range requests {
+		tokenIn, tokenOut := wbnb, usdt
+		amount := new(big.Int).Mul(big.NewInt(rng.Int63n(100)+1), big.NewInt(1e17)) // 0.1-10 WBNB
+		if rng.Intn(2) == 1 {
+			tokenIn, tokenOut = usdt, wbnb
+			amount.Mul(amount, big.NewInt(500)) // 50-5000 USDT
+		}
+		if i == len(requests)-1 && len(requests) > 1 {
+			tokenOut = unsupported
+		}
+
+		requests[i] = &mmv1.QuoteRequest{
+			QuoteId:   fmt.Sprintf("demo-%d", i+1),
+			ChainId:   chainID,
+			MmId:      mmID,
+			TokenIn:   tokenIn,
+			TokenOut:  tokenOut,
+			AmountIn:  amount.String(),
+			From:      demoUser,
+			Recipient: demoUser,
+			Nonce:     fmt.Sprintf("%d", i+1),
+			Deadline:  start.Add(time.Duration(i+2)*opts.Interval + 30*time.Second).Unix(),
+		}
+	}
+	return requests
+}
+
+// printer prints the message flow and tallies the summary
+type printer struct {
+	mu       sync.Mutex
+	out      io.Writer
+	mmID     common.Address
+	domain   *signer.EIP712Domain
+	requests map[string]*mmv1.QuoteRequest
+	depth    map[string]bool // Pairs whose first snapshot was printed
+	summary  *Summary
+}
+
+// observe is the conformance server observer
+func (p *printer) observe(msg *mmv1.Message, sent bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch payload := msg.Payload.(type) {
+	case *mmv1.Message_ConnectionAck:
+		fmt.Fprintf(p.out, "-> ConnectionAck session=%s\n", payload.ConnectionAck.SessionId)
+	case *mmv1.Message_QuoteRequest:
+		req := payload.QuoteRequest
+		p.requests[req.QuoteId] = req
+		fmt.Fprintf(p.out, "-> RFQ %s: %s %s -> %s\n",
+			req.QuoteId, formatUnits(req.AmountIn), symbols[req.TokenIn], symbols[req.TokenOut])
+	case *mmv1.Message_QuoteResponse:
+		resp := payload.QuoteResponse
+		p.summary.Signed++
+		req := p.requests[resp.QuoteId]
+		status := "signature does not verify"
+		if req != nil {
+			if addr, err := recoverSigner(p.domain, req, resp.Order); err != nil {
+				status = "signature invalid: " + err.Error()
+			} else if addr == p.mmID {
+				p.summary.Verified++
+				status = "signature recovers to MM signer"
+			}
+		}
+		symbol := ""
+		if req != nil {
+			symbol = symbols[req.TokenOut]
+		}
+		fmt.Fprintf(p.out, "<- Quote %s: %s %s, deadline %s, %s\n",
+			resp.QuoteId, formatUnits(resp.Order.GetAmountOut()), symbol,
+			time.Unix(resp.Order.GetDeadline(), 0).Format(time.TimeOnly), status)
+	case *mmv1.Message_QuoteReject:
+		reject := payload.QuoteReject
+		p.summary.Rejected++
+		fmt.Fprintf(p.out, "<- Reject %s: %s (%s)\n", reject.QuoteId, reject.Reason, reject.Message)
+	case *mmv1.Message_DepthSnapshot:
+		snapshot := payload.DepthSnapshot
+		p.summary.DepthSnapshots++
+		if !p.depth[snapshot.PairId] {
+			p.depth[snapshot.PairId] = true
+			fmt.Fprintf(p.out, "<- Depth %s: %d bids, %d asks (further snapshots are counted, not printed)\n",
+				snapshot.PairId, len(snapshot.Bids), len(snapshot.Asks))
+		}
+	}
+}
+
+// recoverSigner recovers the address that signed order for req
+func recoverSigner(domain *signer.EIP712Domain, req *mmv1.QuoteRequest, order *mmv1.SignedOrder) (common.Address, error) {
+	amountIn, ok := new(big.Int).SetString(order.GetAmountIn(), 10)
+	if !ok {
+		return common.Address{}, fmt.Errorf("invalid amount_in")
+	}
+	amountOut, ok := new(big.Int).SetString(order.GetAmountOut(), 10)
+	if !ok {
+		return common.Address{}, fmt.Errorf("invalid amount_out")
+	}
+	nonce, ok := new(big.Int).SetString(order.GetNonce(), 10)
+	if !ok {
+		return common.Address{}, fmt.Errorf("invalid nonce")
+	}
+
+	digest, err := signer.Digest(domain, &signer.MMQuote{
+		RFQManager:  common.HexToAddress(order.GetRfqManager()),
+		From:        common.HexToAddress(req.From),
+		To:          common.HexToAddress(req.Recipient),
+		InputToken:  common.HexToAddress(req.TokenIn),
+		OutputToken: common.HexToAddress(req.TokenOut),
+		AmountIn:    amountIn,
+		AmountOut:   amountOut,
+		Deadline:    big.NewInt(order.GetDeadline()),
+		Nonce:       nonce,
+		ExtraData:   order.GetExtraData(),
+	})
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	sig := append([]byte(nil), order.GetSignature()...)
+	if len(sig) != 65 {
+		return common.Address{}, fmt.Errorf("signature length %d", len(sig))
+	}
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pub, err := crypto.SigToPub(digest.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// formatUnits formats a wei amount of an 18-decimal token, trimmed to at most 6 decimals
+func formatUnits(amount string) string {
+	v, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return amount
+	}
+	v.Quo(v, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(tokenDecimal), nil)))
+	s := v.FloatString(6)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
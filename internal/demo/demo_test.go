@@ -0,0 +1,55 @@
+package demo
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	summary, err := Run(context.Background(), Options{Requests: 3, Interval: 200 * time.Millisecond, Seed: 1}, &out, logger)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if summary.Signed != 2 {
+		t.Errorf("Signed = %d, want 2", summary.Signed)
+	}
+	if summary.Verified != 2 {
+		t.Errorf("Verified = %d, want 2", summary.Verified)
+	}
+	if summary.Rejected != 1 {
+		t.Errorf("Rejected = %d, want 1", summary.Rejected)
+	}
+	for _, v := range summary.Violations {
+		t.Errorf("Protocol violation: %s", v)
+	}
+	if !strings.Contains(out.String(), "-> RFQ demo-1") {
+		t.Errorf("Output missing RFQ line:\n%s", out.String())
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		amount string
+		want   string
+	}{
+		{"1000000000000000000", "1"},
+		{"1500000000000000000", "1.5"},
+		{"912083333333333333", "0.912083"},
+		{"0", "0"},
+		{"abc", "abc"},
+	}
+	for _, tt := range tests {
+		if got := formatUnits(tt.amount); got != tt.want {
+			t.Errorf("formatUnits(%s) = %s, want %s", tt.amount, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package depth
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// Attestation domain of depth snapshots
+// The chain ID and verifying contract are those of the snapshot's chain (its RFQ Manager).
+const (
+	AttestationDomainName    = "DarkPool Depth Attestation"
+	AttestationDomainVersion = "1"
+)
+
+// EIP-712 types of the attestation
+var (
+	depthAttestationTypeHash = crypto.Keccak256Hash([]byte(
+		"DepthAttestation(string pairId,address mm,address tokenA,address tokenB,uint64 sequence,uint64 timestamp,PriceLevel[] bids,PriceLevel[] asks)" +
+			"PriceLevel(string price,string amount)"))
+	priceLevelTypeHash = crypto.Keccak256Hash([]byte("PriceLevel(string price,string amount)"))
+)
+
+// Attestation is the MM's signature over a depth snapshot, sent as DepthSnapshot.attestation
+// Sequence increases with every snapshot of a pair; it starts from the process start time in
+// milliseconds, so it also increases across restarts.
+type Attestation struct {
+	Sequence  uint64
+	Timestamp int64 // Unix milliseconds, the Message timestamp
+	Signature []byte
+}
+
+// attestationDomain returns the attestation domain of a chain
+func attestationDomain(cfg *config.Config, chainID uint64) *signer.EIP712Domain {
+	domain := &signer.EIP712Domain{
+		Name:    AttestationDomainName,
+		Version: AttestationDomainVersion,
+		ChainID: new(big.Int).SetUint64(chainID),
+	}
+	for _, d := range cfg.EIP712Domains {
+		if d.ChainID == chainID {
+			domain.VerifyingContract = common.HexToAddress(d.VerifyingContract)
+			break
+		}
+	}
+	return domain
+}
+
+// AttestationStructHash returns the EIP-712 struct hash of a snapshot's DepthAttestation
+func AttestationStructHash(snapshot *mmv1.DepthSnapshot, sequence uint64, timestamp int64) common.Hash {
+	buf := make([]byte, 0, 9*32)
+	buf = append(buf, depthAttestationTypeHash[:]...)
+	buf = append(buf, crypto.Keccak256([]byte(snapshot.PairId))...)
+	buf = append(buf, common.LeftPadBytes(common.HexToAddress(snapshot.MmId).Bytes(), 32)...)
+	buf = append(buf, common.LeftPadBytes(common.HexToAddress(snapshot.TokenA).Bytes(), 32)...)
+	buf = append(buf, common.LeftPadBytes(common.HexToAddress(snapshot.TokenB).Bytes(), 32)...)
+	buf = appendUint64Word(buf, sequence)
+	buf = appendUint64Word(buf, uint64(timestamp))
+	buf = append(buf, hashLevels(snapshot.Bids)...)
+	buf = append(buf, hashLevels(snapshot.Asks)...)
+	return crypto.Keccak256Hash(buf)
+}
+
+// hashLevels returns the EIP-712 hash of a PriceLevel[] array
+func hashLevels(levels []*mmv1.PriceLevel) []byte {
+	buf := make([]byte, 0, len(levels)*32)
+	for _, level := range levels {
+		buf = append(buf, crypto.Keccak256(
+			priceLevelTypeHash[:],
+			crypto.Keccak256([]byte(level.Price)),
+			crypto.Keccak256([]byte(level.Amount)),
+		)...)
+	}
+	return crypto.Keccak256(buf)
+}
+
+// appendUint64Word appends v as a 32-byte big-endian word
+func appendUint64Word(buf []byte, v uint64) []byte {
+	var word [32]byte
+	binary.BigEndian.PutUint64(word[24:], v)
+	return append(buf, word[:]...)
+}
+
+// attest signs the snapshot of msg and attaches the attestation
+func (p *Pusher) attest(msg *mmv1.Message, chainID uint64) error {
+	ts, ok := p.signer.(signer.TypedDataSigner)
+	if !ok {
+		return fmt.Errorf("signer cannot sign typed data")
+	}
+	snapshot := msg.GetDepthSnapshot()
+	sequence := p.nextSequence(fmt.Sprintf("%d:%s", chainID, snapshot.PairId))
+
+	structHash := AttestationStructHash(snapshot, sequence, msg.Timestamp)
+	sig, err := ts.SignTypedData(attestationDomain(p.cfg, chainID).DomainSeparator(), structHash)
+	if err != nil {
+		return err
+	}
+	SetSnapshotAttestation(snapshot, Attestation{Sequence: sequence, Timestamp: msg.Timestamp, Signature: sig})
+	return nil
+}
+
+// sequenceKey is the state store key of a pair's last attestation sequence
+func sequenceKey(key string) string {
+	return "depth/sequence/" + key
+}
+
+// nextSequence returns the next attestation sequence of a pair
+// The first sequence after a start continues the stored one, or the start time in milliseconds
+// when that is higher, so sequences keep increasing across restarts even if the store is lost.
+func (p *Pusher) nextSequence(key string) uint64 {
+	p.attestMu.Lock()
+	defer p.attestMu.Unlock()
+	seq, ok := p.sequences[key]
+	if !ok {
+		seq = uint64(time.Now().UnixMilli())
+		if p.state != nil {
+			if stored, ok, err := p.state.Get(sequenceKey(key)); err == nil && ok && len(stored) == 8 {
+				seq = max(seq, binary.BigEndian.Uint64(stored))
+			}
+		}
+	}
+	seq++
+	p.sequences[key] = seq
+	if p.state != nil {
+		if err := p.state.Put(sequenceKey(key), binary.BigEndian.AppendUint64(nil, seq)); err != nil {
+			p.logger.Warn("Failed to persist attestation sequence", "pair", key, "error", err)
+		}
+	}
+	return seq
+}
+
+// SetSnapshotAttestation attaches att to snapshot, replacing any attached before
+func SetSnapshotAttestation(snapshot *mmv1.DepthSnapshot, att Attestation) {
+	snapshot.Attestation = &mmv1.DepthAttestation{
+		Sequence:  att.Sequence,
+		Timestamp: uint64(att.Timestamp),
+		Signature: att.Signature,
+	}
+}
+
+// SnapshotAttestation returns the attestation attached to snapshot, false if it carries none
+func SnapshotAttestation(snapshot *mmv1.DepthSnapshot) (Attestation, bool) {
+	att := snapshot.GetAttestation()
+	if att == nil {
+		return Attestation{}, false
+	}
+	return Attestation{
+		Sequence:  att.Sequence,
+		Timestamp: int64(att.Timestamp),
+		Signature: att.Signature,
+	}, true
+}
+
+// RecoverAttester returns the address that signed the attestation of a snapshot
+// verifyingContract is the RFQ Manager of the snapshot's chain. Compare the result with mm_id.
+func RecoverAttester(snapshot *mmv1.DepthSnapshot, verifyingContract common.Address) (common.Address, error) {
+	att, ok := SnapshotAttestation(snapshot)
+	if !ok {
+		return common.Address{}, fmt.Errorf("snapshot carries no attestation")
+	}
+	if len(att.Signature) != 65 {
+		return common.Address{}, fmt.Errorf("signature is %d bytes, want 65", len(att.Signature))
+	}
+	domain := &signer.EIP712Domain{
+		Name:              AttestationDomainName,
+		Version:           AttestationDomainVersion,
+		ChainID:           new(big.Int).SetUint64(snapshot.ChainId),
+		VerifyingContract: verifyingContract,
+	}
+	structHash := AttestationStructHash(snapshot, att.Sequence, att.Timestamp)
+	digest := crypto.Keccak256([]byte("\x19\x01"), domain.DomainSeparator(), structHash[:])
+
+	sig := append([]byte(nil), att.Signature...)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pub, err := crypto.SigToPub(digest, sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
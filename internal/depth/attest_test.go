@@ -0,0 +1,98 @@
+package depth
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/store"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+func TestPusher_AttestsSnapshots(t *testing.T) {
+	p, pair := newBenchPusher(t)
+	p.cfg.Depth.Attest = true
+
+	var last uint64
+	for i := 0; i < 2; i++ {
+		msg, err := p.buildDepthMessage(pair)
+		if err != nil {
+			t.Fatalf("buildDepthMessage failed: %v", err)
+		}
+		snapshot := msg.GetDepthSnapshot()
+
+		att, ok := SnapshotAttestation(snapshot)
+		if !ok {
+			t.Fatal("SnapshotAttestation = false, want an attestation")
+		}
+		if att.Timestamp != msg.Timestamp {
+			t.Errorf("attestation timestamp = %d, want %d", att.Timestamp, msg.Timestamp)
+		}
+		if att.Sequence <= last {
+			t.Errorf("sequence = %d, want above %d", att.Sequence, last)
+		}
+		last = att.Sequence
+
+		// The attestation survives the wire
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		received := &mmv1.Message{}
+		if err := proto.Unmarshal(data, received); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		got, err := RecoverAttester(received.GetDepthSnapshot(), common.Address{})
+		if err != nil {
+			t.Fatalf("RecoverAttester failed: %v", err)
+		}
+		if got != p.signer.GetAddress() {
+			t.Errorf("attester = %s, want %s", got.Hex(), p.signer.GetAddress().Hex())
+		}
+
+		// Any change to the advertised depth changes the recovered address
+		tampered := proto.Clone(received.GetDepthSnapshot()).(*mmv1.DepthSnapshot)
+		tampered.Asks[0].Amount += "0"
+		if got, _ := RecoverAttester(tampered, common.Address{}); got == p.signer.GetAddress() {
+			t.Error("tampered snapshot still recovers the MM address")
+		}
+		putDepthMessage(msg)
+	}
+}
+
+func TestSnapshotAttestation_Absent(t *testing.T) {
+	p, pair := newBenchPusher(t)
+	msg, err := p.buildDepthMessage(pair)
+	if err != nil {
+		t.Fatalf("buildDepthMessage failed: %v", err)
+	}
+	if _, ok := SnapshotAttestation(msg.GetDepthSnapshot()); ok {
+		t.Error("SnapshotAttestation = true, want none")
+	}
+	if _, err := RecoverAttester(msg.GetDepthSnapshot(), common.Address{}); err == nil {
+		t.Error("RecoverAttester succeeded without an attestation")
+	}
+}
+
+func TestPusher_SequencePersisted(t *testing.T) {
+	state := store.NewMemory()
+	// A sequence stored ahead of the clock, e.g. after many snapshots per millisecond
+	ahead := uint64(time.Now().Add(time.Hour).UnixMilli())
+	state.Put(sequenceKey("56:WBNB-USDT"), binary.BigEndian.AppendUint64(nil, ahead))
+
+	p, _ := newBenchPusher(t)
+	p.SetStore(state)
+	if got := p.nextSequence("56:WBNB-USDT"); got != ahead+1 {
+		t.Errorf("first sequence = %d, want %d", got, ahead+1)
+	}
+
+	// A restarted pusher continues from the stored sequence
+	restarted, _ := newBenchPusher(t)
+	restarted.SetStore(state)
+	if got := restarted.nextSequence("56:WBNB-USDT"); got != ahead+2 {
+		t.Errorf("sequence after restart = %d, want %d", got, ahead+2)
+	}
+}
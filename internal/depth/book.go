@@ -0,0 +1,157 @@
+package depth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// ErrInsufficientDepth is returned when a side of the book cannot fill the requested size
+var ErrInsufficientDepth = errors.New("insufficient depth")
+
+// BookFill is the result of consuming one side of an order book
+type BookFill struct {
+	Base       *big.Int        // Base token filled (native decimals)
+	Quote      *big.Int        // Quote token at the level prices, rounded down (native decimals)
+	VWAP       decimal.Decimal // Quote per base over the whole fill (wei/wei)
+	LimitPrice decimal.Decimal // Price of the deepest level touched: the best price filling the whole size
+	Levels     int             // Levels touched, the last one possibly in part
+}
+
+// sideLevels returns the levels of a side (SideBid or SideAsk)
+func (ob *OrderBook) sideLevels(side string) ([]PriceLevel, error) {
+	switch side {
+	case SideBid:
+		return ob.Bids, nil
+	case SideAsk:
+		return ob.Asks, nil
+	}
+	return nil, fmt.Errorf("unknown book side %q", side)
+}
+
+// BestPrice returns the top price of a side, false if the side is empty
+func (ob *OrderBook) BestPrice(side string) (decimal.Decimal, bool) {
+	levels, err := ob.sideLevels(side)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	for _, level := range levels {
+		if level.Amount != nil && level.Amount.Sign() > 0 && level.Price.Sign() > 0 {
+			return level.Price, true
+		}
+	}
+	return decimal.Zero, false
+}
+
+// Mid returns MidPrice, or the middle of the best bid and ask when it is not set
+// Returns false for a book without a mid price
+func (ob *OrderBook) Mid() (decimal.Decimal, bool) {
+	if ob.MidPrice.Sign() > 0 {
+		return ob.MidPrice, true
+	}
+	bid, okBid := ob.BestPrice(SideBid)
+	ask, okAsk := ob.BestPrice(SideAsk)
+	if !okBid || !okAsk {
+		return decimal.Zero, false
+	}
+	return bid.Add(ask).Quo(decimal.NewFromInt(2)), true
+}
+
+// Consume fills base of base token against a side, best level first
+// SideBid is a user selling base token, SideAsk a user buying it. Returns an
+// ErrInsufficientDepth error when the side holds less than base.
+func (ob *OrderBook) Consume(side string, base *big.Int) (BookFill, error) {
+	levels, err := ob.sideLevels(side)
+	if err != nil {
+		return BookFill{}, err
+	}
+	return consume(levels, base, func(level PriceLevel) *big.Int {
+		return level.Amount
+	}, func(level PriceLevel, left *big.Int) *big.Int {
+		return left
+	})
+}
+
+// ConsumeQuote fills the base token that quote of quote token buys from a side
+// This is the ask side of an RFQ paying quote token in; the base filled is rounded down.
+// Returns an ErrInsufficientDepth error when the side is worth less than quote.
+func (ob *OrderBook) ConsumeQuote(side string, quote *big.Int) (BookFill, error) {
+	levels, err := ob.sideLevels(side)
+	if err != nil {
+		return BookFill{}, err
+	}
+	return consume(levels, quote, func(level PriceLevel) *big.Int {
+		return level.Price.MulInt(level.Amount).Int()
+	}, func(level PriceLevel, left *big.Int) *big.Int {
+		return decimal.NewFromBigInt(left).Quo(level.Price).Int()
+	})
+}
+
+// VWAP returns the volume-weighted price of filling base of base token against a side
+func (ob *OrderBook) VWAP(side string, base *big.Int) (decimal.Decimal, error) {
+	fill, err := ob.Consume(side, base)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return fill.VWAP, nil
+}
+
+// DepthWithin returns the base token on a side priced within bps of the mid price
+// Returns zero for a book without a mid price
+func (ob *OrderBook) DepthWithin(side string, bps uint32) *big.Int {
+	total := new(big.Int)
+	levels, err := ob.sideLevels(side)
+	if err != nil {
+		return total
+	}
+	mid, ok := ob.Mid()
+	if !ok {
+		return total
+	}
+	band := mid.Mul(decimal.New(int64(bps), -4))
+	limit := mid.Add(band)
+	if side == SideBid {
+		limit = mid.Sub(band)
+	}
+	for _, level := range levels {
+		if level.Amount == nil || level.Price.Sign() <= 0 {
+			continue
+		}
+		if (side == SideBid && level.Price.Cmp(limit) < 0) || (side == SideAsk && level.Price.Cmp(limit) > 0) {
+			break
+		}
+		total.Add(total, level.Amount)
+	}
+	return total
+}
+
+// consume fills limit against levels, cost and fit as in capLevels
+func consume(levels []PriceLevel, limit *big.Int, cost func(PriceLevel) *big.Int, fit func(PriceLevel, *big.Int) *big.Int) (BookFill, error) {
+	if limit == nil || limit.Sign() <= 0 {
+		return BookFill{}, fmt.Errorf("fill size must be positive")
+	}
+	available := new(big.Int)
+	for _, level := range levels {
+		if level.Amount != nil && level.Price.Sign() > 0 {
+			available.Add(available, cost(level))
+		}
+	}
+	if available.Cmp(limit) < 0 {
+		return BookFill{}, fmt.Errorf("%w: %s available, %s requested", ErrInsufficientDepth, available, limit)
+	}
+
+	filled := capLevels(levels, limit, cost, fit)
+	fill := BookFill{Base: new(big.Int), Quote: new(big.Int), Levels: len(filled)}
+	for _, level := range filled {
+		fill.Base.Add(fill.Base, level.Amount)
+		fill.Quote.Add(fill.Quote, level.Price.MulInt(level.Amount).Int())
+		fill.LimitPrice = level.Price
+	}
+	if fill.Base.Sign() == 0 {
+		return BookFill{}, fmt.Errorf("%w: size below one unit of the top level", ErrInsufficientDepth)
+	}
+	fill.VWAP = decimal.NewFromBigInt(fill.Quote).Quo(decimal.NewFromBigInt(fill.Base))
+	return fill, nil
+}
@@ -0,0 +1,90 @@
+package depth_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+)
+
+func testBook() *depth.OrderBook {
+	ob := depth.NewOrderBook("base", "quote")
+	ob.Bids = []depth.PriceLevel{
+		depth.NewPriceLevel(decimal.NewFromInt(99), big.NewInt(10)),
+		depth.NewPriceLevel(decimal.NewFromInt(98), big.NewInt(20)),
+		depth.NewPriceLevel(decimal.NewFromInt(90), big.NewInt(50)),
+	}
+	ob.Asks = []depth.PriceLevel{
+		depth.NewPriceLevel(decimal.NewFromInt(101), big.NewInt(10)),
+		depth.NewPriceLevel(decimal.NewFromInt(102), big.NewInt(20)),
+	}
+	return ob
+}
+
+func TestOrderBook_Consume(t *testing.T) {
+	ob := testBook()
+
+	fill, err := ob.Consume(depth.SideBid, big.NewInt(20))
+	if err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+	// 10 @ 99 + 10 @ 98
+	if fill.Base.Int64() != 20 || fill.Quote.Int64() != 1970 || fill.Levels != 2 {
+		t.Errorf("fill = %d base, %d quote, %d levels, want 20, 1970, 2", fill.Base, fill.Quote, fill.Levels)
+	}
+	if fill.VWAP.String() != "98.5" || fill.LimitPrice.String() != "98" {
+		t.Errorf("VWAP, LimitPrice = %s, %s, want 98.5, 98", fill.VWAP, fill.LimitPrice)
+	}
+	if ob.Bids[1].Amount.Int64() != 20 {
+		t.Error("Consume modified the book")
+	}
+
+	if _, err := ob.Consume(depth.SideAsk, big.NewInt(31)); !errors.Is(err, depth.ErrInsufficientDepth) {
+		t.Errorf("Consume() error = %v, want ErrInsufficientDepth", err)
+	}
+	if _, err := ob.Consume("middle", big.NewInt(1)); err == nil {
+		t.Error("Consume() error = nil, want error for an unknown side")
+	}
+}
+
+func TestOrderBook_ConsumeQuote(t *testing.T) {
+	ob := testBook()
+
+	// 1010 buys the first ask level, 510 buys 5 of the second
+	fill, err := ob.ConsumeQuote(depth.SideAsk, big.NewInt(1520))
+	if err != nil {
+		t.Fatalf("ConsumeQuote failed: %v", err)
+	}
+	if fill.Base.Int64() != 15 || fill.Quote.Int64() != 1520 || fill.LimitPrice.String() != "102" {
+		t.Errorf("fill = %d base, %d quote at %s, want 15, 1520 at 102", fill.Base, fill.Quote, fill.LimitPrice)
+	}
+	if _, err := ob.ConsumeQuote(depth.SideAsk, big.NewInt(3051)); !errors.Is(err, depth.ErrInsufficientDepth) {
+		t.Errorf("ConsumeQuote() error = %v, want ErrInsufficientDepth", err)
+	}
+}
+
+func TestOrderBook_DepthWithin(t *testing.T) {
+	ob := testBook()
+
+	tests := []struct {
+		side string
+		bps  uint32
+		want int64
+	}{
+		{depth.SideBid, 0, 0},
+		{depth.SideBid, 100, 10},  // Mid 100: 99 is within 1%
+		{depth.SideBid, 200, 30},  // 98 is within 2%
+		{depth.SideAsk, 200, 30},  // 102 is within 2%
+		{depth.SideBid, 5000, 80}, // Everything
+	}
+	for _, tt := range tests {
+		if got := ob.DepthWithin(tt.side, tt.bps); got.Int64() != tt.want {
+			t.Errorf("DepthWithin(%s, %d) = %s, want %d", tt.side, tt.bps, got, tt.want)
+		}
+	}
+	if bid, _ := ob.BestPrice(depth.SideBid); bid.String() != "99" {
+		t.Errorf("BestPrice(bid) = %s, want 99", bid)
+	}
+}
@@ -0,0 +1,79 @@
+package depth
+
+import (
+	"math/big"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// capBook returns ob with its cumulative depth capped at the largest accepted amount_in
+// Users sell base into the bids, so the bids are capped at maxBase of base token; users pay
+// quote for the asks, so the asks are capped at maxQuote of quote token (price x amount).
+// The level crossing a cap is cut, deeper levels are dropped. A nil cap leaves its side as is,
+// and ob itself is never modified: the book is the provider's.
+func capBook(ob *OrderBook, maxBase, maxQuote *big.Int) *OrderBook {
+	if maxBase == nil && maxQuote == nil {
+		return ob
+	}
+	capped := *ob
+	if maxBase != nil {
+		capped.Bids = capLevels(ob.Bids, maxBase, func(level PriceLevel) *big.Int {
+			return level.Amount
+		}, func(level PriceLevel, left *big.Int) *big.Int {
+			return left
+		})
+	}
+	if maxQuote != nil {
+		capped.Asks = capLevels(ob.Asks, maxQuote, func(level PriceLevel) *big.Int {
+			return level.Price.MulInt(level.Amount).Int()
+		}, func(level PriceLevel, left *big.Int) *big.Int {
+			return decimal.NewFromBigInt(left).Quo(level.Price).Int()
+		})
+	}
+	return &capped
+}
+
+// widenBook returns ob with its asks raised and its bids lowered by spreadBps
+// Quotes give spreadBps less output than the strategy price, so the book advertises the same.
+// Like capBook, it never modifies ob.
+func widenBook(ob *OrderBook, spreadBps uint32) *OrderBook {
+	if spreadBps == 0 {
+		return ob
+	}
+	widened := *ob
+	widened.Asks = scaleLevels(ob.Asks, decimal.New(10000+int64(spreadBps), -4))
+	widened.Bids = scaleLevels(ob.Bids, decimal.New(10000-int64(spreadBps), -4))
+	return &widened
+}
+
+// scaleLevels returns levels with their prices multiplied by factor
+func scaleLevels(levels []PriceLevel, factor decimal.Decimal) []PriceLevel {
+	out := make([]PriceLevel, len(levels))
+	for i, level := range levels {
+		out[i] = PriceLevel{Price: level.Price.Mul(factor), Amount: level.Amount}
+	}
+	return out
+}
+
+// capLevels returns the levels whose cumulative cost stays within limit
+// cost is the amount_in a level takes; fit is the part of a level that left buys
+func capLevels(levels []PriceLevel, limit *big.Int, cost func(PriceLevel) *big.Int, fit func(PriceLevel, *big.Int) *big.Int) []PriceLevel {
+	out := make([]PriceLevel, 0, len(levels))
+	left := new(big.Int).Set(limit)
+	for _, level := range levels {
+		if level.Amount == nil || level.Price.Sign() <= 0 {
+			continue
+		}
+		c := cost(level)
+		if c.Cmp(left) <= 0 {
+			out = append(out, level)
+			left.Sub(left, c)
+			continue
+		}
+		if amount := fit(level, left); amount.Sign() > 0 {
+			out = append(out, PriceLevel{Price: level.Price, Amount: amount})
+		}
+		break
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package depth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+func TestCapBook(t *testing.T) {
+	ob := NewOrderBook("0xbase", "0xquote")
+	for i := 0; i < 3; i++ {
+		ob.Bids = append(ob.Bids, NewPriceLevel(decimal.NewFromInt(2), big.NewInt(10)))
+		ob.Asks = append(ob.Asks, NewPriceLevel(decimal.NewFromInt(2), big.NewInt(10)))
+	}
+
+	// 25 base into the bids; 30 quote buys 10 + 5 base from the asks at 2
+	capped := capBook(ob, big.NewInt(25), big.NewInt(30))
+	for _, tc := range []struct {
+		side   string
+		levels []PriceLevel
+		want   []int64
+	}{{"bids", capped.Bids, []int64{10, 10, 5}}, {"asks", capped.Asks, []int64{10, 5}}} {
+		if len(tc.levels) != len(tc.want) {
+			t.Fatalf("%s = %d levels, want %d", tc.side, len(tc.levels), len(tc.want))
+		}
+		for i, want := range tc.want {
+			if tc.levels[i].Amount.Int64() != want {
+				t.Errorf("%s[%d].Amount = %v, want %d", tc.side, i, tc.levels[i].Amount, want)
+			}
+		}
+	}
+	if len(ob.Bids) != 3 || len(ob.Asks) != 3 || ob.Asks[1].Amount.Int64() != 10 {
+		t.Error("capBook modified the provider's book")
+	}
+
+	if got := capBook(ob, nil, nil); got != ob {
+		t.Error("capBook without caps should return the book unchanged")
+	}
+}
+
+func TestWidenBook(t *testing.T) {
+	ob := NewOrderBook("0xbase", "0xquote")
+	ob.Bids = append(ob.Bids, NewPriceLevel(decimal.NewFromInt(2000), big.NewInt(10)))
+	ob.Asks = append(ob.Asks, NewPriceLevel(decimal.NewFromInt(2000), big.NewInt(10)))
+
+	widened := widenBook(ob, 50)
+	if got := widened.Bids[0].Price.String(); got != "1990" {
+		t.Errorf("bid = %s, want 1990", got)
+	}
+	if got := widened.Asks[0].Price.String(); got != "2010" {
+		t.Errorf("ask = %s, want 2010", got)
+	}
+	if widened.Bids[0].Amount.Int64() != 10 || ob.Bids[0].Price.String() != "2000" {
+		t.Error("widenBook changed amounts or the provider's book")
+	}
+	if got := widenBook(ob, 0); got != ob {
+		t.Error("widenBook without a spread should return the book unchanged")
+	}
+}
@@ -0,0 +1,86 @@
+package depth_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// blockingProvider blocks GetDepth of one pair until release is closed
+type blockingProvider struct {
+	*testutil.StaticDepthProvider
+	slowPair string
+	release  chan struct{}
+}
+
+func (p *blockingProvider) GetDepth(chainID uint64, pairID string) (*depth.OrderBook, error) {
+	if pairID == p.slowPair {
+		<-p.release
+	}
+	return p.StaticDepthProvider.GetDepth(chainID, pairID)
+}
+
+func TestPusher_SlowPairDoesNotDelayOthers(t *testing.T) {
+	for _, batch := range []bool{false, true} {
+		t.Run(fmt.Sprintf("batch=%v", batch), func(t *testing.T) {
+			cfg := testutil.Config()
+			fast := cfg.Pairs[0]
+			slow := fast
+			slow.PairID = "SLOW"
+			slowChain := fast
+			slowChain.ChainID, slowChain.PairID = 1, "SLOW"
+			cfg.Pairs = []config.PairConfig{slow, fast}
+			if batch {
+				// Batches wait for the whole chain, so the slow pair goes on another chain
+				cfg.Pairs = []config.PairConfig{slowChain, fast}
+			}
+			cfg.Depth = config.DepthConfig{Enabled: true, PushInterval: 20 * time.Millisecond, BatchWrites: batch, MaxConcurrency: 2}
+
+			book := testutil.LinearBook(common.HexToAddress(fast.BaseToken), common.HexToAddress(fast.QuoteToken), 600, 0.001, 2, big.NewInt(1e18))
+			provider := &blockingProvider{StaticDepthProvider: testutil.NewStaticDepthProvider(), slowPair: "SLOW", release: make(chan struct{})}
+			for _, pair := range cfg.Pairs {
+				provider.SetBook(pair.ChainID, pair.PairID, book)
+			}
+
+			client := testutil.NewFakeWSClient()
+			client.SetState(ws.StateReady)
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			s := testutil.NewFakeSigner(common.HexToAddress(testutil.DefaultMMID))
+			pusher := depth.NewPusher(client, provider, quote.NewHandler(testutil.NewFixedRateStrategy(600, 1), s, cfg, logger), s, cfg, logger)
+			if err := pusher.Start(context.Background()); err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			defer pusher.Stop()
+			defer close(provider.release)
+
+			deadline := time.After(2 * time.Second)
+			for {
+				snapshots := client.SentOfType(mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT)
+				if len(snapshots) > 0 {
+					if got := snapshots[0].GetDepthSnapshot().PairId; got != fast.PairID {
+						t.Fatalf("PairId = %s, want %s", got, fast.PairID)
+					}
+					return
+				}
+				select {
+				case <-deadline:
+					t.Fatal("no snapshot of the fast pair while the slow pair blocks")
+				case <-time.After(5 * time.Millisecond):
+				}
+			}
+		})
+	}
+}
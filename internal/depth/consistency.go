@@ -0,0 +1,196 @@
+package depth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+)
+
+// Book sides checked by the ConsistencyChecker
+const (
+	SideBid = "bid" // User sells base token into the bids
+	SideAsk = "ask" // User buys base token from the asks
+)
+
+// BookSource returns the last published order book of a pair (see Pusher.PublishedBook)
+type BookSource func(chainID uint64, pairID string) (*OrderBook, bool)
+
+// Inconsistency is a quote worse than the published depth it was sampled from
+type Inconsistency struct {
+	ChainID      uint64
+	PairID       string
+	Side         string
+	Level        int      // Deepest level of the sampled amount (0 = top of book)
+	AmountIn     *big.Int // Native decimals of the input token
+	Advertised   *big.Int // Output the book promises for AmountIn
+	Quoted       *big.Int // Output the strategy quotes for AmountIn (nil if it refused)
+	DeviationBps float64  // Shortfall of Quoted against Advertised
+	Err          error    // Strategy error, if it refused to quote
+}
+
+// ConsistencyChecker compares published depth with the quotes of the live strategy
+// A user filling the book down to a level should get at least the output the levels
+// advertise; drift between the depth provider and the strategy shows up as quotes below it.
+type ConsistencyChecker struct {
+	books    BookSource
+	strategy quote.QuoteStrategy
+	cfg      *config.Config
+	logger   *slog.Logger
+	events   *events.Bus // Receives a RiskBreach per inconsistency, nil = none
+
+	alerts atomic.Uint64
+}
+
+// NewConsistencyChecker creates a checker of the books against strategy
+// strategy should be the one the quote handler uses, so the live pricing is checked
+func NewConsistencyChecker(books BookSource, strategy quote.QuoteStrategy, cfg *config.Config, logger *slog.Logger) *ConsistencyChecker {
+	return &ConsistencyChecker{
+		books:    books,
+		strategy: strategy,
+		cfg:      cfg,
+		logger:   logger.With("component", "ConsistencyChecker"),
+	}
+}
+
+// SetEventBus publishes the inconsistencies Run finds to b; call before Run
+func (c *ConsistencyChecker) SetEventBus(b *events.Bus) {
+	c.events = b
+}
+
+// Alerts returns the number of inconsistencies found so far
+func (c *ConsistencyChecker) Alerts() uint64 {
+	return c.alerts.Load()
+}
+
+// Run checks every Consistency.Interval until ctx is done
+func (c *ConsistencyChecker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.cfg.Consistency.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, inc := range c.Check(ctx) {
+				c.logger.Warn("Quote worse than published depth",
+					"chainId", inc.ChainID,
+					"pairId", inc.PairID,
+					"side", inc.Side,
+					"level", inc.Level,
+					"amountIn", inc.AmountIn,
+					"advertised", inc.Advertised,
+					"quoted", inc.Quoted,
+					"deviationBps", inc.DeviationBps,
+					"error", inc.Err)
+				c.events.Publish(events.Event{
+					Kind:    events.RiskBreach,
+					ChainID: inc.ChainID,
+					PairID:  inc.PairID,
+					Detail:  events.RiskConsistency,
+					Data:    inc,
+				})
+			}
+		}
+	}
+}
+
+// Check samples the published books of all pairs and returns the inconsistencies found
+func (c *ConsistencyChecker) Check(ctx context.Context) []Inconsistency {
+	var found []Inconsistency
+	for _, pair := range c.cfg.Pairs {
+		ob, ok := c.books(pair.ChainID, pair.PairID)
+		if !ok || !common.IsHexAddress(pair.BaseToken) || !common.IsHexAddress(pair.QuoteToken) {
+			continue
+		}
+		base, quoteToken := common.HexToAddress(pair.BaseToken), common.HexToAddress(pair.QuoteToken)
+		found = append(found, c.checkSide(ctx, pair, SideBid, ob.Bids, base, quoteToken)...)
+		found = append(found, c.checkSide(ctx, pair, SideAsk, ob.Asks, quoteToken, base)...)
+	}
+	c.alerts.Add(uint64(len(found)))
+	return found
+}
+
+// checkSide quotes the cumulative amounts down to the sampled levels of one side
+// Bids: the user sells base for quote; asks: the user pays quote for base
+func (c *ConsistencyChecker) checkSide(ctx context.Context, pair config.PairConfig, side string, levels []PriceLevel, tokenIn, tokenOut common.Address) []Inconsistency {
+	var found []Inconsistency
+	baseTotal, quoteTotal := new(big.Int), decimal.Zero
+	next := 0
+	samples := sampleLevels(len(levels), c.cfg.Consistency.Samples)
+	for i, level := range levels {
+		if level.Amount == nil {
+			return found
+		}
+		baseTotal.Add(baseTotal, level.Amount)
+		quoteTotal = quoteTotal.Add(level.Price.MulInt(level.Amount))
+		if next >= len(samples) || samples[next] != i {
+			continue
+		}
+		next++
+
+		amountIn, advertised := new(big.Int).Set(baseTotal), quoteTotal.Int()
+		if side == SideAsk {
+			amountIn, advertised = quoteTotal.Int(), new(big.Int).Set(baseTotal)
+		}
+		if amountIn.Sign() <= 0 || advertised.Sign() <= 0 {
+			continue
+		}
+
+		inc := Inconsistency{
+			ChainID:    pair.ChainID,
+			PairID:     pair.PairID,
+			Side:       side,
+			Level:      i,
+			AmountIn:   amountIn,
+			Advertised: advertised,
+		}
+		result, err := c.strategy.CalculateQuote(ctx, &quote.QuoteParams{
+			ChainID:  pair.ChainID,
+			TokenIn:  tokenIn,
+			TokenOut: tokenOut,
+			AmountIn: amountIn,
+		})
+		if err != nil {
+			inc.Err = fmt.Errorf("strategy refused to quote: %w", err)
+			found = append(found, inc)
+			continue
+		}
+		inc.Quoted = result.AmountOut
+
+		shortfall := decimal.NewFromBigInt(new(big.Int).Sub(advertised, result.AmountOut))
+		inc.DeviationBps = shortfall.Quo(decimal.NewFromBigInt(advertised)).Float64() * 10000
+		if inc.DeviationBps > float64(c.cfg.Consistency.ToleranceBps) {
+			found = append(found, inc)
+		}
+	}
+	return found
+}
+
+// sampleLevels returns up to samples level indices spread from the top to the bottom of a book
+func sampleLevels(levels, samples int) []int {
+	if levels == 0 || samples <= 0 {
+		return nil
+	}
+	if samples >= levels {
+		samples = levels
+	}
+	if samples == 1 {
+		return []int{0}
+	}
+	indices := make([]int, samples)
+	for i := range indices {
+		indices[i] = i * (levels - 1) / (samples - 1)
+	}
+	return indices
+}
@@ -0,0 +1,140 @@
+package depth_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
+)
+
+// sidedStrategy quotes selling the base token with sell and buying it with buy
+type sidedStrategy struct {
+	base      common.Address
+	sell, buy quote.QuoteStrategy
+}
+
+func (s *sidedStrategy) CalculateQuote(ctx context.Context, params *quote.QuoteParams) (*quote.QuoteResult, error) {
+	if params.TokenIn == s.base {
+		return s.sell.CalculateQuote(ctx, params)
+	}
+	return s.buy.CalculateQuote(ctx, params)
+}
+
+// levels builds book levels of whole tokens (18 decimals)
+func levels(prices []int64, tokens []int64) []depth.PriceLevel {
+	out := make([]depth.PriceLevel, len(prices))
+	for i := range prices {
+		out[i] = depth.NewPriceLevel(decimal.NewFromInt(prices[i]), new(big.Int).Mul(big.NewInt(tokens[i]), big.NewInt(1e18)))
+	}
+	return out
+}
+
+// publish pushes book through a pusher and waits until it is reported as published
+func publish(t *testing.T, cfg *config.Config, book *depth.OrderBook) *depth.Pusher {
+	t.Helper()
+	pair := cfg.Pairs[0]
+	provider := testutil.NewStaticDepthProvider()
+	provider.SetBook(pair.ChainID, pair.PairID, book)
+
+	client := testutil.NewFakeWSClient()
+	client.SetState(ws.StateReady)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := testutil.NewFakeSigner(common.HexToAddress(testutil.DefaultMMID))
+	pusher := depth.NewPusher(client, provider, quote.NewHandler(testutil.NewFixedRateStrategy(600, 1), s, cfg, logger), s, cfg, logger)
+	if err := pusher.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(func() { pusher.Stop() })
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if got, ok := pusher.PublishedBook(pair.ChainID, pair.PairID); ok {
+			if got != book {
+				t.Fatal("PublishedBook returned a different book")
+			}
+			return pusher
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("book not published")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestConsistencyChecker_FlagsQuotesWorseThanBook(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Depth = config.DepthConfig{Enabled: true, PushInterval: 10 * time.Millisecond, MaxConcurrency: 1}
+	cfg.Consistency = config.ConsistencyConfig{ToleranceBps: 20, Samples: 3}
+	pair := cfg.Pairs[0]
+	base, quoteToken := common.HexToAddress(pair.BaseToken), common.HexToAddress(pair.QuoteToken)
+
+	book := depth.NewOrderBook(pair.BaseToken, pair.QuoteToken)
+	book.Bids = levels([]int64{600, 590, 580}, []int64{1, 1, 2})
+	book.Asks = levels([]int64{610, 620, 630}, []int64{1, 1, 2})
+	pusher := publish(t, cfg, book)
+
+	// Sells at 595 and buys at 615: only the top level of each side is worse than the book
+	strategy := &sidedStrategy{
+		base: base,
+		sell: testutil.NewFixedRateStrategy(595, 1),
+		buy:  testutil.NewFixedRateStrategy(1, 615),
+	}
+	checker := depth.NewConsistencyChecker(pusher.PublishedBook, strategy, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	found := checker.Check(context.Background())
+
+	want := []struct {
+		side    string
+		tokenIn common.Address
+	}{{depth.SideBid, base}, {depth.SideAsk, quoteToken}}
+	if len(found) != len(want) {
+		t.Fatalf("inconsistencies = %+v, want %d", found, len(want))
+	}
+	for i, w := range want {
+		inc := found[i]
+		if inc.Side != w.side || inc.Level != 0 || inc.Err != nil {
+			t.Errorf("inconsistency %d = %+v, want %s level 0", i, inc, w.side)
+		}
+		if inc.DeviationBps < 80 || inc.DeviationBps > 85 {
+			t.Errorf("%s DeviationBps = %v, want about 83", inc.Side, inc.DeviationBps)
+		}
+	}
+	if checker.Alerts() != 2 {
+		t.Errorf("Alerts = %d, want 2", checker.Alerts())
+	}
+}
+
+func TestConsistencyChecker_ReportsRefusedQuotes(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Consistency = config.ConsistencyConfig{ToleranceBps: 20, Samples: 1}
+	pair := cfg.Pairs[0]
+
+	book := depth.NewOrderBook(pair.BaseToken, pair.QuoteToken)
+	book.Bids = levels([]int64{600, 590}, []int64{1, 1})
+	books := func(chainID uint64, pairID string) (*depth.OrderBook, bool) {
+		return book, chainID == pair.ChainID && pairID == pair.PairID
+	}
+
+	strategy := testutil.NewFixedRateStrategy(600, 1)
+	strategy.Err = errors.New("no liquidity")
+	checker := depth.NewConsistencyChecker(books, strategy, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	found := checker.Check(context.Background())
+	if len(found) != 1 || found[0].Err == nil || found[0].Quoted != nil {
+		t.Fatalf("inconsistencies = %+v, want one refused quote", found)
+	}
+	if got := len(strategy.Calls()); got != 1 {
+		t.Errorf("strategy calls = %d, want 1 (one sample of the bid side)", got)
+	}
+}
@@ -0,0 +1,39 @@
+package depth
+
+import (
+	"strings"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// formatPrice formats a wei/wei price of a snapshot as set by depth.priceFormat
+// Asks round up and bids down, so a rounded level is never better than the provider's.
+func (p *Pusher) formatPrice(price decimal.Decimal, ask bool) string {
+	format := p.cfg.Depth.PriceFormat
+	if format.SignificantDigits <= 0 {
+		return price.String()
+	}
+	s := price.RoundSignificant(format.SignificantDigits, ask).String()
+	if format.KeepTrailingZeros {
+		s = padSignificant(s, format.SignificantDigits)
+	}
+	return s
+}
+
+// padSignificant pads a plain decimal with trailing zeros to digits significant digits
+// Padding stops at decimal.Scale fractional digits, the precision prices are kept at.
+func padSignificant(s string, digits int) string {
+	intPart, frac, _ := strings.Cut(s, ".")
+	significant := len(strings.TrimLeft(strings.TrimLeft(intPart, "-")+frac, "0"))
+	if significant == 0 {
+		return s // Zero has no significant digits to pad
+	}
+	pad := digits - significant
+	if max := decimal.Scale - len(frac); pad > max {
+		pad = max
+	}
+	if pad <= 0 {
+		return s
+	}
+	return intPart + "." + frac + strings.Repeat("0", pad)
+}
@@ -0,0 +1,88 @@
+package depth
+
+import (
+	"io"
+	"log/slog"
+	"math/rand"
+	"testing"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+func TestPadSignificant(t *testing.T) {
+	tests := []struct {
+		s      string
+		digits int
+		want   string
+	}{
+		{"600", 5, "600.00"},
+		{"0.0000000034", 4, "0.000000003400"},
+		{"1.2345", 3, "1.2345"},
+		{"0", 4, "0"},
+	}
+	for _, tt := range tests {
+		if got := padSignificant(tt.s, tt.digits); got != tt.want {
+			t.Errorf("padSignificant(%q, %d) = %q, want %q", tt.s, tt.digits, got, tt.want)
+		}
+	}
+}
+
+// TestFormatPrice_RoundTrip checks snapshot prices of 6-18 decimal token pairs parse back to
+// the provider's price, or to a price within the format's precision on the MM's side of it
+func TestFormatPrice_RoundTrip(t *testing.T) {
+	decimalsSet := []int{6, 8, 9, 12, 18}
+	rng := rand.New(rand.NewSource(1))
+	p := NewPusher(nil, nil, nil, nil, &config.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	for _, baseDec := range decimalsSet {
+		for _, quoteDec := range decimalsSet {
+			for i := 0; i < 200; i++ {
+				// A token price of 1-12 significant digits between 1e-6 and 1e6, as wei/wei
+				digits := 1 + rng.Intn(12)
+				mantissa := rng.Int63n(pow10Int64(digits)-pow10Int64(digits-1)) + pow10Int64(digits-1)
+				exp := -6 + rng.Intn(13) - digits + 1
+				price := decimal.New(mantissa, exp+quoteDec-baseDec)
+
+				for _, sig := range []int{0, 6, 12, 15} {
+					p.cfg.Depth.PriceFormat = config.PriceFormatConfig{SignificantDigits: sig, KeepTrailingZeros: sig%2 == 0}
+					for _, ask := range []bool{true, false} {
+						s := p.formatPrice(price, ask)
+						if !plainDecimalRe.MatchString(s) {
+							t.Fatalf("price %s formatted as %q, not a plain decimal", price, s)
+						}
+						got, err := decimal.Parse(s)
+						if err != nil {
+							t.Fatalf("price %s formatted as %q does not parse: %v", price, s, err)
+						}
+						if sig == 0 || sig >= digits {
+							if got.Cmp(price) != 0 {
+								t.Fatalf("price %s (%d digits, %d/%d decimals) formatted with %d digits as %q, want exact",
+									price, digits, baseDec, quoteDec, sig, s)
+							}
+							continue
+						}
+						if (ask && got.Cmp(price) < 0) || (!ask && got.Cmp(price) > 0) {
+							t.Fatalf("price %s rounded to %q (ask=%v) is better than the book", price, s, ask)
+						}
+						diff := got.Sub(price)
+						if diff.Sign() < 0 {
+							diff = diff.Neg()
+						}
+						if diff.Quo(price).Cmp(decimal.New(1, 1-sig)) > 0 {
+							t.Fatalf("price %s rounded to %q loses more than %d significant digits", price, s, sig)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func pow10Int64(n int) int64 {
+	v := int64(1)
+	for i := 0; i < n; i++ {
+		v *= 10
+	}
+	return v
+}
@@ -0,0 +1,57 @@
+package depth_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// FuzzHandleMessage feeds arbitrary bytes through protobuf decoding and the inbound message dispatcher
+func FuzzHandleMessage(f *testing.F) {
+	for _, msg := range []*mmv1.Message{
+		testutil.NewConnectionAck(testutil.DefaultMMID),
+		testutil.NewPing(),
+		testutil.NewPong(),
+		testutil.NewQuoteRequest(testutil.QuoteRequest()),
+		testutil.NewError(mmv1.ErrorCode_ERROR_CODE_TIMEOUT, "timeout", testutil.DefaultQuoteID),
+		{Type: mmv1.MessageType_MESSAGE_TYPE_QUOTE_REQUEST}, // Type without payload
+		{Type: mmv1.MessageType(99)},                        // Unknown type
+	} {
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			f.Fatalf("Marshal failed: %v", err)
+		}
+		f.Add(data)
+	}
+
+	cfg := testutil.Config()
+	cfg.WebSocket.ApplyServerConfig = true
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		msg := &mmv1.Message{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return
+		}
+
+		client := testutil.NewFakeWSClient()
+		fakeSigner := testutil.NewFakeSigner(common.HexToAddress(testutil.DefaultMMID))
+		handler := quote.NewHandler(testutil.NewFixedRateStrategy(600, 1), fakeSigner, cfg, logger)
+		pusher := depth.NewPusher(client, testutil.NewStaticDepthProvider(), handler, fakeSigner, cfg, logger)
+		if err := pusher.Start(context.Background()); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		defer pusher.Stop()
+
+		_ = client.Deliver(msg)
+	})
+}
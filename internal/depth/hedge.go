@@ -0,0 +1,150 @@
+package depth
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// HedgeVenue is a CEX venue the hedger trades on
+// GetBook returns the venue's order book of a pair, converted to the OrderBook units
+// (wei/wei prices, base token native amounts) and the on-chain token addresses.
+type HedgeVenue interface {
+	Name() string
+	GetBook(chainID uint64, pairID string) (*OrderBook, error)
+}
+
+// HedgeSource is a hedge venue and the haircut applied to its displayed size
+type HedgeSource struct {
+	Venue   HedgeVenue
+	Haircut float64 // Fraction of the displayed size not counted (0-1): fees, queue position, size limits
+}
+
+// HedgeDepthProvider publishes the liquidity the hedger can actually reach on its venues
+// The books of all venues are merged level by level and every level is cut by its venue's
+// haircut, so each level of the published book is size a fill could be hedged against, not
+// a fabricated ladder. Venues that fail or are stale are left out; with no usable venue
+// the pair gets no depth rather than depth that cannot be hedged.
+type HedgeDepthProvider struct {
+	sources []HedgeSource
+	maxAge  time.Duration
+	now     func() time.Time
+	logger  *slog.Logger
+}
+
+// NewHedgeDepthProvider creates a provider over sources
+// Venue books older than maxAge are not used (0 = no limit; books without a timestamp are always used)
+func NewHedgeDepthProvider(sources []HedgeSource, maxAge time.Duration, logger *slog.Logger) (*HedgeDepthProvider, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("at least one hedge venue is required")
+	}
+	for _, source := range sources {
+		if source.Haircut < 0 || source.Haircut >= 1 {
+			return nil, fmt.Errorf("hedge venue %s: haircut %v must be in [0, 1)", source.Venue.Name(), source.Haircut)
+		}
+	}
+	return &HedgeDepthProvider{
+		sources: sources,
+		maxAge:  maxAge,
+		now:     time.Now,
+		logger:  logger.With("component", "HedgeDepthProvider"),
+	}, nil
+}
+
+// GetDepth merges the post-haircut books of the usable venues
+func (p *HedgeDepthProvider) GetDepth(chainID uint64, pairID string) (*OrderBook, error) {
+	var merged *OrderBook
+	bids := make(map[string]*PriceLevel)
+	asks := make(map[string]*PriceLevel)
+
+	for _, source := range p.sources {
+		book, err := source.Venue.GetBook(chainID, pairID)
+		if err != nil {
+			p.logger.Warn("Hedge venue book unavailable", "venue", source.Venue.Name(), "pairId", pairID, "error", err)
+			continue
+		}
+		if p.maxAge > 0 && !book.Timestamp.IsZero() && p.now().Sub(book.Timestamp) > p.maxAge {
+			p.logger.Warn("Hedge venue book is stale", "venue", source.Venue.Name(), "pairId", pairID, "age", p.now().Sub(book.Timestamp))
+			continue
+		}
+
+		if merged == nil {
+			merged = NewOrderBook(book.BaseToken, book.QuoteToken)
+			merged.Timestamp = book.Timestamp
+		} else if !strings.EqualFold(book.BaseToken, merged.BaseToken) || !strings.EqualFold(book.QuoteToken, merged.QuoteToken) {
+			p.logger.Warn("Hedge venue book has other tokens, skipped", "venue", source.Venue.Name(), "pairId", pairID)
+			continue
+		}
+		// The merged book is as old as its stalest venue
+		if !book.Timestamp.IsZero() && (merged.Timestamp.IsZero() || book.Timestamp.Before(merged.Timestamp)) {
+			merged.Timestamp = book.Timestamp
+		}
+
+		keep := decimal.NewFromFloat(1 - source.Haircut)
+		addLevels(bids, book.Bids, keep)
+		addLevels(asks, book.Asks, keep)
+	}
+	if merged == nil {
+		return nil, fmt.Errorf("no hedge venue book for chain %d pair %s", chainID, pairID)
+	}
+
+	merged.Bids = sortedLevels(bids, true)
+	merged.Asks = sortedLevels(asks, false)
+	if len(merged.Bids) == 0 || len(merged.Asks) == 0 {
+		return nil, fmt.Errorf("hedge venues have a one-sided book for chain %d pair %s", chainID, pairID)
+	}
+
+	bestBid, bestAsk := merged.Bids[0].Price, merged.Asks[0].Price
+	if bestBid.Cmp(bestAsk) >= 0 {
+		return nil, errCrossedHedgeBook
+	}
+	merged.MidPrice = bestBid.Add(bestAsk).Quo(decimal.NewFromInt(2))
+	if bestBid.Sign() > 0 {
+		merged.Spread = bestAsk.Sub(bestBid).Quo(bestBid).Float64() * 100
+	}
+	return merged, nil
+}
+
+// errCrossedHedgeBook is returned while the venues' books cross each other
+// A crossed merged book is transient venue-to-venue arbitrage, not depth to publish.
+var errCrossedHedgeBook = errors.New("hedge venue books are crossed")
+
+// addLevels adds the levels scaled by keep to the levels by price
+func addLevels(byPrice map[string]*PriceLevel, levels []PriceLevel, keep decimal.Decimal) {
+	for _, level := range levels {
+		if level.Amount == nil || level.Amount.Sign() <= 0 || level.Price.Sign() <= 0 {
+			continue
+		}
+		amount := keep.MulInt(level.Amount).Int()
+		if amount.Sign() <= 0 {
+			continue
+		}
+		key := level.Price.String()
+		if existing, ok := byPrice[key]; ok {
+			existing.Amount.Add(existing.Amount, amount)
+			continue
+		}
+		byPrice[key] = &PriceLevel{Price: level.Price, Amount: amount}
+	}
+}
+
+// sortedLevels returns the levels by price, descending for bids and ascending for asks
+func sortedLevels(byPrice map[string]*PriceLevel, descending bool) []PriceLevel {
+	levels := make([]PriceLevel, 0, len(byPrice))
+	for _, level := range byPrice {
+		levels = append(levels, PriceLevel{Price: level.Price, Amount: new(big.Int).Set(level.Amount)})
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		if descending {
+			return levels[i].Price.Cmp(levels[j].Price) > 0
+		}
+		return levels[i].Price.Cmp(levels[j].Price) < 0
+	})
+	return levels
+}
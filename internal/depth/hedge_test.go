@@ -0,0 +1,100 @@
+package depth_test
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+)
+
+// stubVenue returns a fixed book, or err
+type stubVenue struct {
+	name string
+	book *depth.OrderBook
+	err  error
+}
+
+func (v *stubVenue) Name() string { return v.name }
+func (v *stubVenue) GetBook(uint64, string) (*depth.OrderBook, error) {
+	return v.book, v.err
+}
+
+func venueBook(ts time.Time, bids, asks [][2]int64) *depth.OrderBook {
+	book := depth.NewOrderBook("0xbase", "0xquote")
+	book.Timestamp = ts
+	for _, l := range bids {
+		book.Bids = append(book.Bids, depth.NewPriceLevel(decimal.NewFromInt(l[0]), big.NewInt(l[1])))
+	}
+	for _, l := range asks {
+		book.Asks = append(book.Asks, depth.NewPriceLevel(decimal.NewFromInt(l[0]), big.NewInt(l[1])))
+	}
+	return book
+}
+
+func TestHedgeDepthProvider_MergesPostHaircut(t *testing.T) {
+	now := time.Now()
+	a := &stubVenue{name: "a", book: venueBook(now, [][2]int64{{99, 100}, {98, 100}}, [][2]int64{{101, 100}})}
+	b := &stubVenue{name: "b", book: venueBook(now, [][2]int64{{99, 200}}, [][2]int64{{102, 400}})}
+	down := &stubVenue{name: "down", err: errors.New("timeout")}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	p, err := depth.NewHedgeDepthProvider([]depth.HedgeSource{
+		{Venue: a, Haircut: 0.5},
+		{Venue: b, Haircut: 0.25},
+		{Venue: down},
+	}, time.Minute, logger)
+	if err != nil {
+		t.Fatalf("NewHedgeDepthProvider failed: %v", err)
+	}
+	book, err := p.GetDepth(56, "pair")
+	if err != nil {
+		t.Fatalf("GetDepth failed: %v", err)
+	}
+
+	wantBids := [][2]int64{{99, 50 + 150}, {98, 50}}
+	wantAsks := [][2]int64{{101, 50}, {102, 300}}
+	for _, tc := range []struct {
+		side string
+		got  []depth.PriceLevel
+		want [][2]int64
+	}{{"bids", book.Bids, wantBids}, {"asks", book.Asks, wantAsks}} {
+		if len(tc.got) != len(tc.want) {
+			t.Fatalf("%s = %d levels, want %d", tc.side, len(tc.got), len(tc.want))
+		}
+		for i, want := range tc.want {
+			if tc.got[i].Price.Cmp(decimal.NewFromInt(want[0])) != 0 || tc.got[i].Amount.Int64() != want[1] {
+				t.Errorf("%s[%d] = %v@%v, want %d@%d", tc.side, i, tc.got[i].Amount, tc.got[i].Price, want[1], want[0])
+			}
+		}
+	}
+	if got := book.MidPrice.String(); got != "100" {
+		t.Errorf("MidPrice = %s, want 100", got)
+	}
+}
+
+func TestHedgeDepthProvider_NoUsableVenue(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	stale := &stubVenue{name: "stale", book: venueBook(time.Now().Add(-time.Hour), [][2]int64{{99, 1}}, [][2]int64{{101, 1}})}
+	p, err := depth.NewHedgeDepthProvider([]depth.HedgeSource{{Venue: stale}}, time.Minute, logger)
+	if err != nil {
+		t.Fatalf("NewHedgeDepthProvider failed: %v", err)
+	}
+	if _, err := p.GetDepth(56, "pair"); err == nil {
+		t.Error("GetDepth() error = nil, want error with only a stale venue")
+	}
+
+	crossed := &stubVenue{name: "crossed", book: venueBook(time.Time{}, [][2]int64{{102, 1}}, [][2]int64{{101, 1}})}
+	p, _ = depth.NewHedgeDepthProvider([]depth.HedgeSource{{Venue: crossed}}, 0, logger)
+	if _, err := p.GetDepth(56, "pair"); err == nil {
+		t.Error("GetDepth() error = nil, want error for a crossed book")
+	}
+
+	if _, err := depth.NewHedgeDepthProvider([]depth.HedgeSource{{Venue: crossed, Haircut: 1}}, 0, logger); err == nil {
+		t.Error("NewHedgeDepthProvider() error = nil, want error for a haircut of the whole size")
+	}
+}
@@ -0,0 +1,367 @@
+package depth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/metrics"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+// Selectors of the pool getters
+var (
+	getReservesSelector = crypto.Keccak256([]byte("getReserves()"))[:4] // Uniswap V2 pair
+	slot0Selector       = crypto.Keccak256([]byte("slot0()"))[:4]       // Uniswap V3 pool
+	liquiditySelector   = crypto.Keccak256([]byte("liquidity()"))[:4]   // Uniswap V3 pool
+)
+
+// q96 is 2^96, the fixed-point base of Uniswap V3 square root prices
+var q96 = decimal.NewFromBigInt(new(big.Int).Lsh(big.NewInt(1), 96))
+
+// MidContribution is the reading of one source in the last weighted mid of a pair
+type MidContribution struct {
+	Source       string
+	Price        decimal.Decimal // Quote wei per base wei, zero if the source failed
+	Weight       float64         // Configured weight, or liquidity in quote token units
+	Share        float64         // Weight / total weight of the usable sources
+	DeviationBps float64         // Price distance from the weighted mid
+	Err          error           // Why the source was left out, nil if used
+}
+
+// midSource reads the price and liquidity of a pair on one pool or venue
+type midSource struct {
+	cfg    config.MidSource
+	pool   common.Address
+	client signer.RPCClient // nil for strategy sources
+}
+
+// midPair is a pair whose mid is weighted over its sources
+type midPair struct {
+	cfg     config.MidPair
+	pair    config.PairConfig
+	sources []*midSource
+
+	mu            sync.Mutex // Held while the sources are read, so concurrent callers share a read
+	mid           decimal.Decimal
+	at            time.Time
+	err           error
+	contributions []MidContribution
+	failures      map[string]uint64 // Failed reads by source
+}
+
+// MidFeed is a quote.PriceFeed of liquidity-weighted mid prices
+// Each pair of mid.pairs is priced over its sources: the mid is the sum of the source prices
+// weighted by their configured weight, or by their liquidity in quote token units. Sources that
+// fail are left out; the per-source readings are exported as metrics, so a source that
+// disagrees with the others can be found. Other pairs are priced by the fallback feed.
+type MidFeed struct {
+	pairs    []*midPair
+	fallback quote.PriceFeed
+	refresh  time.Duration
+	now      func() time.Time
+	logger   *slog.Logger
+}
+
+// NewMidFeed creates the feed of cfg.Mid; fallback prices strategy sources and the other pairs
+func NewMidFeed(cfg *config.Config, fallback quote.PriceFeed, logger *slog.Logger) *MidFeed {
+	f := &MidFeed{
+		fallback: fallback,
+		refresh:  cfg.Mid.Refresh,
+		now:      time.Now,
+		logger:   logger.With("component", "MidFeed"),
+	}
+	clients := make(map[string]signer.RPCClient)
+	for _, mp := range cfg.Mid.Pairs {
+		for _, pair := range cfg.Pairs {
+			if pair.ChainID != mp.ChainID || pair.PairID != mp.PairID {
+				continue
+			}
+			p := &midPair{cfg: mp, pair: pair, failures: make(map[string]uint64)}
+			for _, src := range mp.Sources {
+				s := &midSource{cfg: src, pool: common.HexToAddress(src.Pool)}
+				if src.Type != config.MidSourceStrategy {
+					url := cfg.MidRPCURL(mp.ChainID, src)
+					if _, ok := clients[url]; !ok {
+						clients[url] = signer.NewHTTPRPCClient(url)
+					}
+					s.client = clients[url]
+				}
+				p.sources = append(p.sources, s)
+			}
+			f.pairs = append(f.pairs, p)
+			break
+		}
+	}
+	return f
+}
+
+// Price returns the weighted mid of tokenIn/tokenOut, or its inverse for the reverse direction
+func (f *MidFeed) Price(ctx context.Context, chainID uint64, tokenIn, tokenOut common.Address) (decimal.Decimal, time.Time, error) {
+	p, inverse := f.find(chainID, tokenIn, tokenOut)
+	if p == nil {
+		if f.fallback != nil {
+			return f.fallback.Price(ctx, chainID, tokenIn, tokenOut)
+		}
+		return decimal.Zero, time.Time{}, fmt.Errorf("no mid sources for %s/%s on chain %d", tokenIn.Hex(), tokenOut.Hex(), chainID)
+	}
+	mid, at, err := f.mid(ctx, p)
+	if err != nil {
+		return decimal.Zero, time.Time{}, err
+	}
+	if inverse {
+		return decimal.NewFromInt(1).Quo(mid), at, nil
+	}
+	return mid, at, nil
+}
+
+// find returns the pair of tokenIn/tokenOut, and whether tokenIn is its quote token
+func (f *MidFeed) find(chainID uint64, tokenIn, tokenOut common.Address) (*midPair, bool) {
+	for _, p := range f.pairs {
+		if p.pair.ChainID != chainID {
+			continue
+		}
+		base, quoteToken := common.HexToAddress(p.pair.BaseToken), common.HexToAddress(p.pair.QuoteToken)
+		switch {
+		case tokenIn == base && tokenOut == quoteToken:
+			return p, false
+		case tokenIn == quoteToken && tokenOut == base:
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// mid returns the weighted mid of p, reading its sources if the last read is older than refresh
+func (f *MidFeed) mid(ctx context.Context, p *midPair) (decimal.Decimal, time.Time, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := f.now()
+	if !p.at.IsZero() && now.Sub(p.at) < f.refresh {
+		return p.mid, p.at, p.err
+	}
+
+	contributions := make([]MidContribution, len(p.sources))
+	var total float64
+	weighted := decimal.Zero
+	used := 0
+	for i, s := range p.sources {
+		c := MidContribution{Source: s.cfg.Name}
+		price, liquidity, err := f.read(ctx, p, s)
+		if err == nil && s.cfg.Weight == 0 && liquidity <= 0 {
+			err = fmt.Errorf("no liquidity")
+		}
+		if err != nil {
+			c.Err = err
+			p.failures[s.cfg.Name]++
+			f.logger.Warn("Mid source unavailable", "pairId", p.pair.PairID, "source", s.cfg.Name, "error", err)
+		} else {
+			c.Price, c.Weight = price, s.cfg.Weight
+			if c.Weight == 0 {
+				c.Weight = liquidity
+			}
+			total += c.Weight
+			weighted = weighted.Add(price.Mul(decimal.NewFromFloat(c.Weight)))
+			used++
+		}
+		contributions[i] = c
+	}
+
+	p.at, p.contributions = now, contributions
+	if used == 0 || used < p.cfg.MinSources {
+		p.mid, p.err = decimal.Zero, fmt.Errorf("pair %s has %d usable mid sources, need %d", p.pair.PairID, used, max(p.cfg.MinSources, 1))
+		return p.mid, p.at, p.err
+	}
+	p.mid, p.err = weighted.Quo(decimal.NewFromFloat(total)), nil
+	mid := p.mid.Float64()
+	for i := range contributions {
+		c := &contributions[i]
+		if c.Err == nil {
+			c.Share = c.Weight / total
+			c.DeviationBps = (c.Price.Float64() - mid) / mid * 10000
+		}
+	}
+	return p.mid, p.at, nil
+}
+
+// read returns the price of a source in quote wei per base wei and its liquidity in quote token units
+func (f *MidFeed) read(ctx context.Context, p *midPair, s *midSource) (decimal.Decimal, float64, error) {
+	base, quoteToken := common.HexToAddress(p.pair.BaseToken), common.HexToAddress(p.pair.QuoteToken)
+	quoteUnit := pow10(p.pair.QuoteTokenDecimals)
+	// Uniswap orders the tokens of a pool by address
+	baseIsToken0 := bytes.Compare(base.Bytes(), quoteToken.Bytes()) < 0
+
+	switch s.cfg.Type {
+	case config.MidSourceStrategy:
+		if f.fallback == nil {
+			return decimal.Zero, 0, fmt.Errorf("the strategy provides no prices")
+		}
+		price, _, err := f.fallback.Price(ctx, p.pair.ChainID, base, quoteToken)
+		return price, 0, err
+
+	case config.MidSourceUniswapV2:
+		out, err := s.client.CallContract(ctx, s.pool, getReservesSelector)
+		if err != nil {
+			return decimal.Zero, 0, fmt.Errorf("pool %s getReserves(): %w", s.pool.Hex(), err)
+		}
+		if len(out) < 64 {
+			return decimal.Zero, 0, fmt.Errorf("pool %s getReserves(): invalid result %x", s.pool.Hex(), out)
+		}
+		reserveBase, reserveQuote := new(big.Int).SetBytes(out[:32]), new(big.Int).SetBytes(out[32:64])
+		if !baseIsToken0 {
+			reserveBase, reserveQuote = reserveQuote, reserveBase
+		}
+		if reserveBase.Sign() == 0 || reserveQuote.Sign() == 0 {
+			return decimal.Zero, 0, fmt.Errorf("pool %s has no reserves", s.pool.Hex())
+		}
+		price := decimal.NewFromBigInt(reserveQuote).Quo(decimal.NewFromBigInt(reserveBase))
+		// Both sides are worth the same at the pool price
+		liquidity := decimal.NewFromBigInt(reserveQuote).Quo(quoteUnit).Float64() * 2
+		return price, liquidity, nil
+
+	case config.MidSourceUniswapV3:
+		out, err := s.client.CallContract(ctx, s.pool, slot0Selector)
+		if err != nil {
+			return decimal.Zero, 0, fmt.Errorf("pool %s slot0(): %w", s.pool.Hex(), err)
+		}
+		if len(out) < 32 {
+			return decimal.Zero, 0, fmt.Errorf("pool %s slot0(): invalid result %x", s.pool.Hex(), out)
+		}
+		sqrtPrice := decimal.NewFromBigInt(new(big.Int).SetBytes(out[:32])).Quo(q96) // sqrt(token1 wei per token0 wei)
+		if sqrtPrice.Sign() == 0 {
+			return decimal.Zero, 0, fmt.Errorf("pool %s is not initialized", s.pool.Hex())
+		}
+		out, err = s.client.CallContract(ctx, s.pool, liquiditySelector)
+		if err != nil {
+			return decimal.Zero, 0, fmt.Errorf("pool %s liquidity(): %w", s.pool.Hex(), err)
+		}
+		if len(out) < 32 {
+			return decimal.Zero, 0, fmt.Errorf("pool %s liquidity(): invalid result %x", s.pool.Hex(), out)
+		}
+		liquidity := decimal.NewFromBigInt(new(big.Int).SetBytes(out[:32]))
+
+		// In-range liquidity L holds virtual reserves L/sqrtP of token0 and L*sqrtP of token1
+		price, reserveQuote := sqrtPrice.Mul(sqrtPrice), liquidity.Mul(sqrtPrice)
+		if !baseIsToken0 {
+			price, reserveQuote = decimal.NewFromInt(1).Quo(price), liquidity.Quo(sqrtPrice)
+		}
+		return price, reserveQuote.Quo(quoteUnit).Float64() * 2, nil
+	}
+	return decimal.Zero, 0, fmt.Errorf("unknown mid source type %q", s.cfg.Type)
+}
+
+// Contributions returns the source readings of the last weighted mid of a pair
+func (f *MidFeed) Contributions(chainID uint64, pairID string) []MidContribution {
+	for _, p := range f.pairs {
+		if p.pair.ChainID == chainID && p.pair.PairID == pairID {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			return append([]MidContribution(nil), p.contributions...)
+		}
+	}
+	return nil
+}
+
+// Collect exports the weighted mids and the readings of their sources
+func (f *MidFeed) Collect(w *metrics.Writer) {
+	w.Header("mm_mid_price", "gauge", "Liquidity-weighted mid price of a pair, quote wei per base wei")
+	w.Header("mm_mid_source_price", "gauge", "Price of a mid source at the last weighted mid, quote wei per base wei")
+	w.Header("mm_mid_source_share", "gauge", "Share of a mid source in the last weighted mid (0-1), 0 if it was left out")
+	w.Header("mm_mid_source_deviation_bps", "gauge", "Distance of a mid source's price from the weighted mid (basis points)")
+	w.Header("mm_mid_source_failures_total", "counter", "Reads of a mid source that failed")
+	for _, p := range f.pairs {
+		chain := strconv.FormatUint(p.pair.ChainID, 10)
+		p.mu.Lock()
+		if p.err == nil && !p.at.IsZero() {
+			w.Sample("mm_mid_price", p.mid.Float64(), "chain_id", chain, "pair", p.pair.PairID)
+		}
+		for _, c := range p.contributions {
+			labels := []string{"chain_id", chain, "pair", p.pair.PairID, "source", c.Source}
+			w.Sample("mm_mid_source_share", c.Share, labels...)
+			if c.Err == nil {
+				w.Sample("mm_mid_source_price", c.Price.Float64(), labels...)
+				w.Sample("mm_mid_source_deviation_bps", c.DeviationBps, labels...)
+			}
+		}
+		names := make([]string, 0, len(p.sources))
+		for _, s := range p.sources {
+			names = append(names, s.cfg.Name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			w.Sample("mm_mid_source_failures_total", float64(p.failures[name]), "chain_id", chain, "pair", p.pair.PairID, "source", name)
+		}
+		p.mu.Unlock()
+	}
+}
+
+// MidAnchoredProvider recenters the books of mid.pairs on their weighted mid
+// Every level price is scaled by weighted mid / book mid, so the published book has the
+// provider's shape and sizes around the same mid the strategy quotes from.
+type MidAnchoredProvider struct {
+	next DepthProvider
+	feed *MidFeed
+}
+
+// NewMidAnchoredProvider wraps next with the weighted mids of feed
+func NewMidAnchoredProvider(next DepthProvider, feed *MidFeed) *MidAnchoredProvider {
+	return &MidAnchoredProvider{next: next, feed: feed}
+}
+
+// GetDepth returns next's book of the pair, recentered if the pair has mid sources
+// A pair without a usable weighted mid gets no book: depth around another mid than the
+// quotes would advertise prices the MM does not give.
+func (a *MidAnchoredProvider) GetDepth(chainID uint64, pairID string) (*OrderBook, error) {
+	book, err := a.next.GetDepth(chainID, pairID)
+	if err != nil {
+		return nil, err
+	}
+	var p *midPair
+	for _, mp := range a.feed.pairs {
+		if mp.pair.ChainID == chainID && mp.pair.PairID == pairID {
+			p = mp
+			break
+		}
+	}
+	if p == nil {
+		return book, nil
+	}
+	if book.MidPrice.Sign() <= 0 {
+		return nil, fmt.Errorf("book of %s has no mid price to recenter", pairID)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), referenceTimeout)
+	defer cancel()
+	mid, _, err := a.feed.mid(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	factor := mid.Quo(book.MidPrice)
+	recentered := *book
+	recentered.Bids = scalePrices(book.Bids, factor)
+	recentered.Asks = scalePrices(book.Asks, factor)
+	recentered.MidPrice = mid
+	return &recentered, nil
+}
+
+// scalePrices returns a copy of levels with every price multiplied by factor
+func scalePrices(levels []PriceLevel, factor decimal.Decimal) []PriceLevel {
+	scaled := make([]PriceLevel, len(levels))
+	for i, level := range levels {
+		level.Price = level.Price.Mul(factor)
+		scaled[i] = level
+	}
+	return scaled
+}
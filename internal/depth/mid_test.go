@@ -0,0 +1,212 @@
+package depth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/metrics"
+)
+
+// fakePool answers the getters of a Uniswap V2 pair or V3 pool, or fails every call
+type fakePool struct {
+	reserve0, reserve1 *big.Int // V2
+	sqrtPriceX96       *big.Int // V3
+	liquidity          *big.Int // V3
+	err                error
+	calls              int
+}
+
+func (f *fakePool) ChainID(context.Context) (uint64, error) { return 56, nil }
+
+func (f *fakePool) CallContract(_ context.Context, _ common.Address, data []byte) ([]byte, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	word := func(v *big.Int) []byte { return common.LeftPadBytes(v.Bytes(), 32) }
+	switch {
+	case bytes.Equal(data, getReservesSelector):
+		return append(append(word(f.reserve0), word(f.reserve1)...), word(big.NewInt(0))...), nil
+	case bytes.Equal(data, slot0Selector):
+		return append(word(f.sqrtPriceX96), make([]byte, 6*32)...), nil
+	case bytes.Equal(data, liquiditySelector):
+		return word(f.liquidity), nil
+	}
+	return nil, errors.New("unknown selector")
+}
+
+// midTestPair is WBNB-USDC on BSC; USDC sorts first, so it is token0 of the pools
+var midTestPair = config.PairConfig{
+	ChainID:            56,
+	PairID:             "WBNB-USDC",
+	BaseToken:          "0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c",
+	QuoteToken:         "0x8ac76a51cc950d9822d68b83fe1ad97b32cd580d",
+	BaseTokenDecimals:  18,
+	QuoteTokenDecimals: 6,
+}
+
+// newMidTestFeed weighs midTestPair over the pools, named by index
+func newMidTestFeed(now *time.Time, pools map[string]*fakePool, types map[string]string, weights map[string]float64) *MidFeed {
+	f := &MidFeed{refresh: time.Second, now: func() time.Time { return *now }, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	p := &midPair{pair: midTestPair, failures: make(map[string]uint64)}
+	for _, name := range []string{"a", "b", "c"} {
+		if pools[name] == nil {
+			continue
+		}
+		p.sources = append(p.sources, &midSource{cfg: config.MidSource{Name: name, Type: types[name], Weight: weights[name]}, client: pools[name]})
+	}
+	f.pairs = append(f.pairs, p)
+	return f
+}
+
+// usdcPerWBNB converts a quote wei per base wei price of midTestPair to USDC per WBNB
+func usdcPerWBNB(price decimal.Decimal) float64 {
+	return price.Float64() * 1e12
+}
+
+func TestMidFeed_WeighsByLiquidity(t *testing.T) {
+	now := time.Unix(1735084800, 0)
+	e18, e6 := big.NewInt(1e18), big.NewInt(1e6)
+	// 600 USDC per WBNB with 1.2M USDC of liquidity, 610 with 122k
+	a := &fakePool{reserve0: new(big.Int).Mul(big.NewInt(600_000), e6), reserve1: new(big.Int).Mul(big.NewInt(1000), e18)}
+	b := &fakePool{reserve0: new(big.Int).Mul(big.NewInt(61_000), e6), reserve1: new(big.Int).Mul(big.NewInt(100), e18)}
+	c := &fakePool{err: errors.New("rpc down")}
+	f := newMidTestFeed(&now, map[string]*fakePool{"a": a, "b": b, "c": c},
+		map[string]string{"a": config.MidSourceUniswapV2, "b": config.MidSourceUniswapV2, "c": config.MidSourceUniswapV3}, nil)
+
+	base, quoteToken := common.HexToAddress(midTestPair.BaseToken), common.HexToAddress(midTestPair.QuoteToken)
+	mid, _, err := f.Price(context.Background(), 56, base, quoteToken)
+	if err != nil {
+		t.Fatalf("Price failed: %v", err)
+	}
+	want := (600*1.2e6 + 610*1.22e5) / (1.2e6 + 1.22e5)
+	if got := usdcPerWBNB(mid); math.Abs(got-want) > 1e-6 {
+		t.Errorf("mid = %v USDC/WBNB, want %v", got, want)
+	}
+	inverse, _, err := f.Price(context.Background(), 56, quoteToken, base)
+	if err != nil || math.Abs(inverse.Float64()*usdcPerWBNB(mid)-1e12) > 1 {
+		t.Errorf("inverse = %s (%v), want 1/mid", inverse, err)
+	}
+	if a.calls != 1 {
+		t.Errorf("pool read %d times within refresh, want once", a.calls)
+	}
+
+	contributions := f.Contributions(56, "WBNB-USDC")
+	if len(contributions) != 3 || contributions[2].Err == nil || contributions[2].Share != 0 {
+		t.Fatalf("contributions = %+v", contributions)
+	}
+	if share := contributions[0].Share; math.Abs(share-1.2e6/1.322e6) > 1e-9 {
+		t.Errorf("share of a = %v", share)
+	}
+	if dev := contributions[1].DeviationBps; dev < 140 || dev > 160 {
+		t.Errorf("deviation of b = %v bps, want about 150", dev)
+	}
+
+	var buf bytes.Buffer
+	registry := metrics.NewRegistry()
+	registry.Register(f)
+	if _, err := registry.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		`mm_mid_source_share{chain_id="56",pair="WBNB-USDC",source="c"} 0`,
+		`mm_mid_source_failures_total{chain_id="56",pair="WBNB-USDC",source="c"} 1`,
+		`mm_mid_source_deviation_bps{chain_id="56",pair="WBNB-USDC",source="b"}`,
+		`mm_mid_price{chain_id="56",pair="WBNB-USDC"}`,
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("metrics miss %s:\n%s", line, buf.String())
+		}
+	}
+
+	// Fixed weights override liquidity
+	now = now.Add(2 * time.Second)
+	f.pairs[0].sources[0].cfg.Weight, f.pairs[0].sources[1].cfg.Weight = 1, 1
+	mid, _, _ = f.Price(context.Background(), 56, base, quoteToken)
+	if got := usdcPerWBNB(mid); math.Abs(got-605) > 1e-6 {
+		t.Errorf("equal weights: mid = %v, want 605", got)
+	}
+
+	// Fewer usable sources than minSources is no price
+	now = now.Add(2 * time.Second)
+	f.pairs[0].cfg.MinSources = 3
+	if _, _, err := f.Price(context.Background(), 56, base, quoteToken); err == nil {
+		t.Error("price with 2 of 3 required sources")
+	}
+}
+
+func TestMidFeed_UniswapV3(t *testing.T) {
+	now := time.Unix(1735084800, 0)
+	// token1/token0 = WBNB wei per USDC wei = 1e18 / 600e6; sqrtPriceX96 = sqrt(that) * 2^96
+	ratio := new(big.Float).SetPrec(256).Quo(big.NewFloat(1e18), big.NewFloat(600e6))
+	sqrt := new(big.Float).SetPrec(256).Sqrt(ratio)
+	sqrtX96, _ := sqrt.Mul(sqrt, new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), 96))).Int(nil)
+	pool := &fakePool{sqrtPriceX96: sqrtX96, liquidity: big.NewInt(1e15)}
+	f := newMidTestFeed(&now, map[string]*fakePool{"a": pool}, map[string]string{"a": config.MidSourceUniswapV3}, nil)
+
+	mid, _, err := f.Price(context.Background(), 56, common.HexToAddress(midTestPair.BaseToken), common.HexToAddress(midTestPair.QuoteToken))
+	if err != nil {
+		t.Fatalf("Price failed: %v", err)
+	}
+	if got := usdcPerWBNB(mid); math.Abs(got-600) > 1e-6 {
+		t.Errorf("mid = %v USDC/WBNB, want 600", got)
+	}
+	// Virtual USDC reserve L / sqrtP, both sides counted
+	want := 2 * 1e15 / math.Sqrt(1e18/600e6) / 1e6
+	if got := f.Contributions(56, "WBNB-USDC")[0].Weight; math.Abs(got-want)/want > 1e-9 {
+		t.Errorf("liquidity = %v USDC, want %v", got, want)
+	}
+}
+
+// bookProvider returns a copy of one book
+type bookProvider struct{ book *OrderBook }
+
+func (p bookProvider) GetDepth(uint64, string) (*OrderBook, error) {
+	book := *p.book
+	return &book, nil
+}
+
+func TestMidAnchoredProvider_Recenters(t *testing.T) {
+	now := time.Unix(1735084800, 0)
+	e18, e6 := big.NewInt(1e18), big.NewInt(1e6)
+	pool := &fakePool{reserve0: new(big.Int).Mul(big.NewInt(606_000), e6), reserve1: new(big.Int).Mul(big.NewInt(1000), e18)}
+	f := newMidTestFeed(&now, map[string]*fakePool{"a": pool}, map[string]string{"a": config.MidSourceUniswapV2}, nil)
+
+	price := func(usdc string) decimal.Decimal { return decimal.MustParse(usdc).Mul(decimal.New(1, -12)) }
+	book := NewOrderBook(midTestPair.BaseToken, midTestPair.QuoteToken)
+	book.MidPrice = price("600")
+	book.Bids = []PriceLevel{{Price: price("599"), Amount: e18}}
+	book.Asks = []PriceLevel{{Price: price("601"), Amount: e18}}
+	p := NewMidAnchoredProvider(bookProvider{book}, f)
+
+	got, err := p.GetDepth(56, "WBNB-USDC")
+	if err != nil {
+		t.Fatalf("GetDepth failed: %v", err)
+	}
+	if m := usdcPerWBNB(got.MidPrice); math.Abs(m-606) > 1e-9 {
+		t.Errorf("mid = %v, want 606", m)
+	}
+	if bid, ask := usdcPerWBNB(got.Bids[0].Price), usdcPerWBNB(got.Asks[0].Price); math.Abs(bid-604.99) > 1e-9 || math.Abs(ask-607.01) > 1e-9 {
+		t.Errorf("levels = %v / %v, want 604.99 / 607.01", bid, ask)
+	}
+	if usdcPerWBNB(book.Bids[0].Price) != 599 {
+		t.Error("the provider's book was modified")
+	}
+
+	// Pairs without mid sources are passed through
+	if got, err := p.GetDepth(56, "WBNB-USDT"); err != nil || got.MidPrice.Cmp(book.MidPrice) != 0 {
+		t.Errorf("WBNB-USDT: %+v, %v", got, err)
+	}
+}
@@ -2,37 +2,80 @@ package depth
 
 import (
 	"fmt"
+	"hash/fnv"
 	"math/big"
 	"math/rand"
+	"sort"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
 )
 
 // MockProvider is a mock depth data provider
 // For demonstration and testing only, generates random but reasonable depth data
 // Third-party MMs should replace with real data sources (on-chain reads, CEX APIs, etc.)
+//
+// Generated depth is a function of (seed, chain, pair, clock second), so a run
+// can be replayed with the same seed and clock regardless of call order
 type MockProvider struct {
 	// prices stores the base price for each trading pair
 	// key: "chainId:baseToken:quoteToken" (lowercase addresses)
-	prices map[string]*big.Float
+	prices map[string]decimal.Decimal
 	mu     sync.RWMutex
 	rng    *rand.Rand
+	seed   int64
+	now    func() time.Time
+
+	// Schedule multipliers of the level offsets from the mid and of the level sizes
+	spreadMultiplier float64
+	sizeMultiplier   float64
 }
 
-// NewMockProvider creates a mock depth data provider
+// NewMockProvider creates a mock depth data provider with a time-based seed
 func NewMockProvider() *MockProvider {
+	return NewMockProviderWithSeed(time.Now().UnixNano())
+}
+
+// NewMockProviderWithSeed creates a mock depth data provider with a fixed seed
+func NewMockProviderWithSeed(seed int64) *MockProvider {
 	return &MockProvider{
-		prices: make(map[string]*big.Float),
-		rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		prices: make(map[string]decimal.Decimal),
+		rng:    rand.New(rand.NewSource(seed)),
+		seed:   seed,
+		now:    time.Now,
+
+		spreadMultiplier: 1,
+		sizeMultiplier:   1,
 	}
 }
 
+// SetMultipliers widens the book by spread and scales level sizes by size (see schedule.Target)
+func (p *MockProvider) SetMultipliers(spread, size float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.spreadMultiplier = spread
+	p.sizeMultiplier = size
+}
+
+// Seed returns the RNG seed, log it to replay a run
+func (p *MockProvider) Seed() int64 {
+	return p.seed
+}
+
+// SetClock sets the clock used to derive and timestamp generated depth (default time.Now)
+func (p *MockProvider) SetClock(now func() time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.now = now
+}
+
 // SetBasePrice sets the base price
 func (p *MockProvider) SetBasePrice(chainID uint64, baseToken, quoteToken string, price float64) {
 	key := buildPriceKey(chainID, baseToken, quoteToken)
 	p.mu.Lock()
-	p.prices[key] = big.NewFloat(price)
+	p.prices[key] = decimal.NewFromFloat(price)
 	p.mu.Unlock()
 }
 
@@ -45,91 +88,105 @@ func (p *MockProvider) GetDepth(chainID uint64, pairID string) (*OrderBook, erro
 		return nil, fmt.Errorf("pair_id is required")
 	}
 
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+	// Exclusive lock: generation mutates the shared rng
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	// Find matching price configuration
-	var basePrice *big.Float
+	// Find matching price configuration (sorted so the choice is stable)
+	var basePrice decimal.Decimal
 	var baseToken, quoteToken string
+	found := false
+
+	keys := make([]string, 0, len(p.prices))
+	for key := range p.prices {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 
-	for key, price := range p.prices {
+	for _, key := range keys {
 		parts := strings.Split(key, ":")
 		if len(parts) == 3 {
 			keyChainID := parts[0]
 			if keyChainID == fmt.Sprintf("%d", chainID) {
-				basePrice = price
+				basePrice = p.prices[key]
 				baseToken = parts[1]
 				quoteToken = parts[2]
+				found = true
 				break
 			}
 		}
 	}
 
-	if basePrice == nil {
+	if !found {
 		return nil, fmt.Errorf("no price configured for chain %d pair %s", chainID, pairID)
 	}
 
 	// Generate mock order book
+	now := p.now()
+	p.rng.Seed(deriveSeed(p.seed, chainID, pairID, now.Unix()))
 	ob := NewOrderBook(baseToken, quoteToken)
 	ob.MidPrice = basePrice
+	ob.Timestamp = now
 
 	// Generate bids and asks (10 price levels each)
 	ob.Asks = p.generateAsks(basePrice, 10)
 	ob.Bids = p.generateBids(basePrice, 10)
 
 	// Calculate spread
-	if len(ob.Asks) > 0 && len(ob.Bids) > 0 {
-		bestAsk, _ := ob.Asks[0].Price.Float64()
-		bestBid, _ := ob.Bids[0].Price.Float64()
-		if bestBid > 0 {
-			ob.Spread = (bestAsk - bestBid) / bestBid * 100
-		}
+	if len(ob.Asks) > 0 && len(ob.Bids) > 0 && ob.Bids[0].Price.Sign() > 0 {
+		bestAsk, bestBid := ob.Asks[0].Price, ob.Bids[0].Price
+		ob.Spread = bestAsk.Sub(bestBid).Quo(bestBid).Float64() * 100
 	}
 
 	return ob, nil
 }
 
 // generateAsks generates asks (price ascending)
-func (p *MockProvider) generateAsks(midPrice *big.Float, levels int) []PriceLevel {
+func (p *MockProvider) generateAsks(midPrice decimal.Decimal, levels int) []PriceLevel {
 	asks := make([]PriceLevel, levels)
-	midPriceFloat, _ := midPrice.Float64()
 
 	for i := 0; i < levels; i++ {
-		// Price increases: midPrice * (1 + 0.001 * (i+1) + random noise)
-		priceIncrease := 1 + 0.001*float64(i+1) + p.rng.Float64()*0.0005
-		price := big.NewFloat(midPriceFloat * priceIncrease)
+		// Price increases: midPrice * (1 + (0.001 * (i+1) + random noise) * spread multiplier)
+		priceIncrease := 1 + 0.001*float64(i+1)*p.spreadMultiplier + p.rng.Float64()*0.0005*p.spreadMultiplier
+		price := midPrice.Mul(decimal.NewFromFloat(priceIncrease))
 
-		// Random amount (1-100 tokens, in 18 decimals format)
-		// amount = (1 + random * 99) * 1e18
-		amountFloat := (1 + p.rng.Float64()*99)
-		amount := big.NewInt(int64(amountFloat * 1e18))
-
-		asks[i] = NewPriceLevel(price, amount)
+		asks[i] = NewPriceLevel(price, p.randomAmount())
 	}
 
 	return asks
 }
 
 // generateBids generates bids (price descending)
-func (p *MockProvider) generateBids(midPrice *big.Float, levels int) []PriceLevel {
+func (p *MockProvider) generateBids(midPrice decimal.Decimal, levels int) []PriceLevel {
 	bids := make([]PriceLevel, levels)
-	midPriceFloat, _ := midPrice.Float64()
 
 	for i := 0; i < levels; i++ {
-		// Price decreases: midPrice * (1 - 0.001 * (i+1) - random noise)
-		priceDecrease := 1 - 0.001*float64(i+1) - p.rng.Float64()*0.0005
-		price := big.NewFloat(midPriceFloat * priceDecrease)
-
-		// Random amount (1-100 tokens, in 18 decimals format)
-		amountFloat := (1 + p.rng.Float64()*99)
-		amount := big.NewInt(int64(amountFloat * 1e18))
+		// Price decreases: midPrice * (1 - (0.001 * (i+1) + random noise) * spread multiplier)
+		priceDecrease := 1 - 0.001*float64(i+1)*p.spreadMultiplier - p.rng.Float64()*0.0005*p.spreadMultiplier
+		price := midPrice.Mul(decimal.NewFromFloat(priceDecrease))
 
-		bids[i] = NewPriceLevel(price, amount)
+		bids[i] = NewPriceLevel(price, p.randomAmount())
 	}
 
 	return bids
 }
 
+// randomAmount returns a random amount of 1-100 tokens in 18 decimals format, scaled by the size multiplier
+func (p *MockProvider) randomAmount() *big.Int {
+	// amount = (1 + random * 99) * size multiplier * 1e18, exact for the drawn decimal
+	return decimal.NewFromFloat((1 + p.rng.Float64()*99) * p.sizeMultiplier).MulInt(oneToken).Int()
+}
+
+// oneToken is 1 token in 18 decimals format
+var oneToken = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// deriveSeed derives a per-call seed from the base seed, chain, pair and clock second
+func deriveSeed(seed int64, chainID uint64, pairID string, unix int64) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d:%d:%s:%d", seed, chainID, pairID, unix)
+	return int64(h.Sum64())
+}
+
 // buildPriceKey builds the price lookup key
 func buildPriceKey(chainID uint64, baseToken, quoteToken string) string {
 	return fmt.Sprintf("%d:%s:%s", chainID,
@@ -139,7 +196,12 @@ func buildPriceKey(chainID uint64, baseToken, quoteToken string) string {
 
 // DefaultMockProvider creates a mock provider with default prices
 func DefaultMockProvider() *MockProvider {
-	provider := NewMockProvider()
+	return DefaultMockProviderWithSeed(time.Now().UnixNano())
+}
+
+// DefaultMockProviderWithSeed creates a mock provider with default prices and a fixed seed
+func DefaultMockProviderWithSeed(seed int64) *MockProvider {
+	provider := NewMockProviderWithSeed(seed)
 
 	// BSC: WBNB/USDT = 600 USDT
 	provider.SetBasePrice(56,
@@ -0,0 +1,242 @@
+package depth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+// ErrOutsideBand is returned for a snapshot whose mid price is too far from the reference price
+// A provider bug such as an off-by-decimals price would otherwise advertise a book 1000x wrong
+var ErrOutsideBand = errors.New("mid price outside the oracle band")
+
+// ErrNoReference is returned when a pair has no usable reference price
+var ErrNoReference = errors.New("no reference price")
+
+// referenceTimeout bounds the reference price lookup of a snapshot
+const referenceTimeout = 2 * time.Second
+
+// Selectors of the AggregatorV3Interface getters
+var (
+	decimalsSelector        = crypto.Keccak256([]byte("decimals()"))[:4]
+	latestRoundDataSelector = crypto.Keccak256([]byte("latestRoundData()"))[:4]
+)
+
+// latestRoundDataOutputs is the ABI layout of the latestRoundData() result
+var latestRoundDataOutputs = func() abi.Arguments {
+	uint80Ty, _ := abi.NewType("uint80", "", nil)
+	int256Ty, _ := abi.NewType("int256", "", nil)
+	uint256Ty, _ := abi.NewType("uint256", "", nil)
+	return abi.Arguments{
+		{Type: uint80Ty},  // roundId
+		{Type: int256Ty},  // answer
+		{Type: uint256Ty}, // startedAt
+		{Type: uint256Ty}, // updatedAt
+		{Type: uint80Ty},  // answeredInRound
+	}
+}()
+
+// chainlinkAggregator is the Chainlink feed of a pair
+type chainlinkAggregator struct {
+	pair       config.PairConfig
+	aggregator common.Address
+	client     signer.RPCClient
+
+	mu       sync.Mutex
+	decimals int // Answer decimals, -1 until read
+}
+
+// ChainlinkFeed is a quote.PriceFeed reading Chainlink aggregators
+// Each aggregator prices the base token of a pair in its quote token; the answer is converted
+// to the native-unit ratio of the pair's tokens. Token pairs without an aggregator are priced
+// by the fallback feed, if any.
+type ChainlinkFeed struct {
+	aggregators []*chainlinkAggregator
+	fallback    quote.PriceFeed
+}
+
+// NewChainlinkFeed creates a feed of the aggregators in cfg.Oracle.Feeds
+// fallback prices the other pairs, nil = none
+func NewChainlinkFeed(cfg *config.Config, fallback quote.PriceFeed) *ChainlinkFeed {
+	f := &ChainlinkFeed{fallback: fallback}
+	clients := make(map[string]signer.RPCClient)
+	for _, feed := range cfg.Oracle.Feeds {
+		for _, pair := range cfg.Pairs {
+			if pair.ChainID != feed.ChainID || pair.PairID != feed.PairID {
+				continue
+			}
+			url := cfg.OracleRPCURL(feed)
+			if _, ok := clients[url]; !ok {
+				clients[url] = signer.NewHTTPRPCClient(url)
+			}
+			f.add(pair, common.HexToAddress(feed.Aggregator), clients[url])
+			break
+		}
+	}
+	return f
+}
+
+// add registers the aggregator of a pair
+func (f *ChainlinkFeed) add(pair config.PairConfig, aggregator common.Address, client signer.RPCClient) {
+	f.aggregators = append(f.aggregators, &chainlinkAggregator{pair: pair, aggregator: aggregator, client: client, decimals: -1})
+}
+
+// Price returns the latest answer of the aggregator of tokenIn/tokenOut and its update time
+func (f *ChainlinkFeed) Price(ctx context.Context, chainID uint64, tokenIn, tokenOut common.Address) (decimal.Decimal, time.Time, error) {
+	for _, a := range f.aggregators {
+		if a.pair.ChainID == chainID &&
+			strings.EqualFold(a.pair.BaseToken, tokenIn.Hex()) &&
+			strings.EqualFold(a.pair.QuoteToken, tokenOut.Hex()) {
+			return a.latest(ctx)
+		}
+	}
+	if f.fallback != nil {
+		return f.fallback.Price(ctx, chainID, tokenIn, tokenOut)
+	}
+	return decimal.Zero, time.Time{}, fmt.Errorf("no aggregator for %s/%s on chain %d", tokenIn.Hex(), tokenOut.Hex(), chainID)
+}
+
+// latest reads the latest round of the aggregator
+func (a *chainlinkAggregator) latest(ctx context.Context) (decimal.Decimal, time.Time, error) {
+	decimals, err := a.answerDecimals(ctx)
+	if err != nil {
+		return decimal.Zero, time.Time{}, err
+	}
+
+	out, err := a.client.CallContract(ctx, a.aggregator, latestRoundDataSelector)
+	if err != nil {
+		return decimal.Zero, time.Time{}, fmt.Errorf("aggregator %s latestRoundData(): %w", a.aggregator.Hex(), err)
+	}
+	values, err := latestRoundDataOutputs.Unpack(out)
+	if err != nil {
+		return decimal.Zero, time.Time{}, fmt.Errorf("aggregator %s latestRoundData(): %w", a.aggregator.Hex(), err)
+	}
+	answer, _ := values[1].(*big.Int)
+	updatedAt, _ := values[3].(*big.Int)
+	if answer == nil || answer.Sign() <= 0 || updatedAt == nil {
+		return decimal.Zero, time.Time{}, fmt.Errorf("aggregator %s has no valid answer", a.aggregator.Hex())
+	}
+
+	// answer / 10^decimals quote tokens per base token, as quote wei per base wei
+	price := decimal.NewFromBigInt(answer).
+		Mul(pow10(a.pair.QuoteTokenDecimals)).
+		Quo(pow10(decimals + a.pair.BaseTokenDecimals))
+	return price, time.Unix(updatedAt.Int64(), 0), nil
+}
+
+// answerDecimals returns the decimals of the aggregator's answers, read once
+func (a *chainlinkAggregator) answerDecimals(ctx context.Context) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.decimals >= 0 {
+		return a.decimals, nil
+	}
+	out, err := a.client.CallContract(ctx, a.aggregator, decimalsSelector)
+	if err != nil {
+		return 0, fmt.Errorf("aggregator %s decimals(): %w", a.aggregator.Hex(), err)
+	}
+	if len(out) != 32 || new(big.Int).SetBytes(out).Cmp(big.NewInt(77)) > 0 {
+		return 0, fmt.Errorf("aggregator %s decimals(): invalid result %x", a.aggregator.Hex(), out)
+	}
+	a.decimals = int(new(big.Int).SetBytes(out).Int64())
+	return a.decimals, nil
+}
+
+// SetReferenceFeed sets the feed snapshots are checked against when oracle.enabled; call before Start
+func (p *Pusher) SetReferenceFeed(feed quote.PriceFeed) {
+	p.reference = feed
+}
+
+// BandAlerts returns the number of snapshots suppressed for being outside the oracle band
+func (p *Pusher) BandAlerts() uint64 {
+	return p.bandAlerts.Load()
+}
+
+// checkBand returns an ErrOutsideBand error when the mid price of ob is further than
+// Oracle.MaxDeviationBps from the reference price of the pair
+// Without a usable reference the snapshot passes, unless Oracle.RequireReference is set.
+func (p *Pusher) checkBand(pair config.PairConfig, ob *OrderBook) error {
+	if !p.cfg.Oracle.Enabled {
+		return nil
+	}
+	mid, ok := ob.Mid()
+	if !ok {
+		return nil // One-sided or empty: nothing to compare
+	}
+
+	ref, err := p.referencePrice(pair)
+	if err != nil {
+		if p.cfg.Oracle.RequireReference {
+			return err
+		}
+		p.logger.Warn("Reference price unavailable, depth not checked",
+			"chainId", pair.ChainID,
+			"pairId", pair.PairID,
+			"error", err)
+		return nil
+	}
+
+	deviation := mid.Sub(ref)
+	if deviation.Sign() < 0 {
+		deviation = deviation.Neg()
+	}
+	deviationBps := deviation.Quo(ref).Float64() * 10000
+	if deviationBps <= float64(p.cfg.Oracle.MaxDeviationBps) {
+		return nil
+	}
+	p.bandAlerts.Add(1)
+	p.logger.Error("Depth mid price outside the oracle band, snapshot suppressed",
+		"chainId", pair.ChainID,
+		"pairId", pair.PairID,
+		"mid", mid,
+		"reference", ref,
+		"deviationBps", deviationBps,
+		"maxDeviationBps", p.cfg.Oracle.MaxDeviationBps)
+	err = fmt.Errorf("%w: mid %s is %.0f bps from reference %s", ErrOutsideBand, mid, deviationBps, ref)
+	p.events.Publish(events.Event{
+		Kind:    events.RiskBreach,
+		ChainID: pair.ChainID,
+		PairID:  pair.PairID,
+		Detail:  events.RiskOracleBand,
+		Data:    err,
+	})
+	return err
+}
+
+// referencePrice returns the reference price of a pair, checked for age
+func (p *Pusher) referencePrice(pair config.PairConfig) (decimal.Decimal, error) {
+	if p.reference == nil {
+		return decimal.Zero, fmt.Errorf("%w: no reference feed", ErrNoReference)
+	}
+	if !common.IsHexAddress(pair.BaseToken) || !common.IsHexAddress(pair.QuoteToken) {
+		return decimal.Zero, fmt.Errorf("%w: pair tokens are not addresses", ErrNoReference)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), referenceTimeout)
+	defer cancel()
+	ref, at, err := p.reference.Price(ctx, pair.ChainID, common.HexToAddress(pair.BaseToken), common.HexToAddress(pair.QuoteToken))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%w: %v", ErrNoReference, err)
+	}
+	if ref.Sign() <= 0 {
+		return decimal.Zero, fmt.Errorf("%w: reference price %s", ErrNoReference, ref)
+	}
+	if maxAge := p.cfg.Oracle.MaxAge; maxAge > 0 && !at.IsZero() && time.Since(at) > maxAge {
+		return decimal.Zero, fmt.Errorf("%w: reference price is %s old", ErrNoReference, time.Since(at).Round(time.Second))
+	}
+	return ref, nil
+}
@@ -0,0 +1,124 @@
+package depth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// fakeAggregator answers decimals() and latestRoundData() of a Chainlink aggregator
+type fakeAggregator struct {
+	decimals  int64
+	answer    *big.Int
+	updatedAt time.Time
+	calls     int
+}
+
+func (f *fakeAggregator) ChainID(context.Context) (uint64, error) { return 56, nil }
+
+func (f *fakeAggregator) CallContract(_ context.Context, _ common.Address, data []byte) ([]byte, error) {
+	f.calls++
+	if bytes.Equal(data, decimalsSelector) {
+		return common.LeftPadBytes(big.NewInt(f.decimals).Bytes(), 32), nil
+	}
+	one := big.NewInt(1)
+	return latestRoundDataOutputs.Pack(one, f.answer, big.NewInt(f.updatedAt.Unix()), big.NewInt(f.updatedAt.Unix()), one)
+}
+
+// fixedFeed is a reference feed returning one price
+type fixedFeed struct {
+	price decimal.Decimal
+	at    time.Time
+	err   error
+}
+
+func (f fixedFeed) Price(context.Context, uint64, common.Address, common.Address) (decimal.Decimal, time.Time, error) {
+	return f.price, f.at, f.err
+}
+
+func TestChainlinkFeed_Price(t *testing.T) {
+	pair := config.PairConfig{
+		ChainID:            56,
+		PairID:             "WBNB-USDC",
+		BaseToken:          "0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c",
+		QuoteToken:         "0x8ac76a51cc950d9822d68b83fe1ad97b32cd580d",
+		BaseTokenDecimals:  18,
+		QuoteTokenDecimals: 6,
+	}
+	updatedAt := time.Unix(1700000000, 0)
+	agg := &fakeAggregator{decimals: 8, answer: big.NewInt(600_00000000), updatedAt: updatedAt}
+	f := &ChainlinkFeed{fallback: fixedFeed{err: errors.New("fallback used")}}
+	f.add(pair, common.HexToAddress("0x0567F2323251f0Aab15c8dFb1967E4e8A7D42aeE"), agg)
+
+	base, quoteToken := common.HexToAddress(pair.BaseToken), common.HexToAddress(pair.QuoteToken)
+	for i := 0; i < 2; i++ {
+		price, at, err := f.Price(context.Background(), 56, base, quoteToken)
+		if err != nil {
+			t.Fatalf("Price failed: %v", err)
+		}
+		// 600 USDC (6 decimals) per WBNB (18 decimals)
+		if price.String() != "0.0000000006" {
+			t.Errorf("price = %s, want 0.0000000006", price)
+		}
+		if !at.Equal(updatedAt) {
+			t.Errorf("at = %v, want %v", at, updatedAt)
+		}
+	}
+	if agg.calls != 3 {
+		t.Errorf("calls = %d, want 3 (decimals read once)", agg.calls)
+	}
+
+	// The other direction has no aggregator
+	if _, _, err := f.Price(context.Background(), 56, quoteToken, base); err == nil || err.Error() != "fallback used" {
+		t.Errorf("Price(reversed) error = %v, want the fallback's", err)
+	}
+
+	agg.answer = big.NewInt(0)
+	if _, _, err := f.Price(context.Background(), 56, base, quoteToken); err == nil {
+		t.Error("Price() error = nil, want error for a zero answer")
+	}
+}
+
+func TestPusher_OracleBand(t *testing.T) {
+	p, pair := newBenchPusher(t)
+	p.cfg.Oracle = config.OracleConfig{Enabled: true, MaxDeviationBps: 500}
+
+	// The mock book is around 600
+	p.SetReferenceFeed(fixedFeed{price: decimal.NewFromInt(600)})
+	msg, err := p.buildDepthMessage(pair)
+	if err != nil {
+		t.Fatalf("buildDepthMessage failed within the band: %v", err)
+	}
+	putDepthMessage(msg)
+
+	// A book 1000x off the reference is suppressed
+	p.SetReferenceFeed(fixedFeed{price: decimal.MustParse("0.6")})
+	if _, err := p.buildDepthMessage(pair); !errors.Is(err, ErrOutsideBand) {
+		t.Errorf("buildDepthMessage() error = %v, want ErrOutsideBand", err)
+	}
+	if got := p.BandAlerts(); got != 1 {
+		t.Errorf("BandAlerts() = %d, want 1", got)
+	}
+
+	// A stale reference is no reference
+	p.cfg.Oracle.MaxAge = time.Minute
+	p.SetReferenceFeed(fixedFeed{price: decimal.MustParse("0.6"), at: time.Now().Add(-time.Hour)})
+	msg, err = p.buildDepthMessage(pair)
+	if err != nil {
+		t.Fatalf("buildDepthMessage() error = %v, want nil without a usable reference", err)
+	}
+	putDepthMessage(msg)
+
+	p.cfg.Oracle.RequireReference = true
+	if _, err := p.buildDepthMessage(pair); !errors.Is(err, ErrNoReference) {
+		t.Errorf("buildDepthMessage() error = %v, want ErrNoReference", err)
+	}
+}
@@ -0,0 +1,50 @@
+package depth
+
+import (
+	"sync"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// depthMessagePool reuses depth snapshot messages, including their price levels, between pushes
+// A message is returned to the pool once the WSClient has sent it; WSClient does not retain messages
+var depthMessagePool = sync.Pool{
+	New: func() any {
+		return &mmv1.Message{
+			Type: mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT,
+			Payload: &mmv1.Message_DepthSnapshot{
+				DepthSnapshot: &mmv1.DepthSnapshot{},
+			},
+		}
+	},
+}
+
+// getDepthMessage returns a depth snapshot message from the pool
+// Its snapshot keeps the level slices of its previous use; fillDepthSnapshot overwrites them
+func getDepthMessage() *mmv1.Message {
+	return depthMessagePool.Get().(*mmv1.Message)
+}
+
+// putDepthMessage returns a message obtained from getDepthMessage to the pool
+func putDepthMessage(msg *mmv1.Message) {
+	if msg == nil || msg.GetDepthSnapshot() == nil {
+		return
+	}
+	depthMessagePool.Put(msg)
+}
+
+// resizeLevels returns levels with length n, reusing its PriceLevel objects
+func resizeLevels(levels []*mmv1.PriceLevel, n int) []*mmv1.PriceLevel {
+	if cap(levels) < n {
+		grown := make([]*mmv1.PriceLevel, n)
+		copy(grown, levels[:cap(levels)])
+		levels = grown
+	}
+	levels = levels[:n]
+	for i, level := range levels {
+		if level == nil {
+			levels[i] = &mmv1.PriceLevel{}
+		}
+	}
+	return levels
+}
@@ -0,0 +1,103 @@
+package depth
+
+import (
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+func newBenchPusher(tb testing.TB) (*Pusher, config.PairConfig) {
+	tb.Helper()
+
+	s, err := signer.NewSignerFromHex("0x0000000000000000000000000000000000000000000000000000000000000001", signer.NewDomainManager())
+	if err != nil {
+		tb.Fatalf("NewSignerFromHex failed: %v", err)
+	}
+	provider := NewMockProviderWithSeed(1)
+	provider.SetBasePrice(56, "0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c", "0x55d398326f99059ff775485246999027b3197955", 600)
+	provider.SetClock(func() time.Time { return time.Unix(1700000000, 0) })
+	pair := config.PairConfig{
+		ChainID:    56,
+		PairID:     "WBNB-USDT",
+		BaseToken:  "0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c",
+		QuoteToken: "0x55d398326f99059ff775485246999027b3197955",
+	}
+	p := NewPusher(nil, provider, nil, s, &config.Config{Pairs: []config.PairConfig{pair}}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return p, pair
+}
+
+func TestDepthMessagePool_Reuse(t *testing.T) {
+	p, pair := newBenchPusher(t)
+
+	// A reused message with more levels than the next book must shrink to the new book
+	first := getDepthMessage()
+	ob := NewOrderBook(pair.BaseToken, pair.QuoteToken)
+	for i := 0; i < 5; i++ {
+		ob.Asks = append(ob.Asks, NewPriceLevel(decimal.NewFromInt(int64(600+i)), big.NewInt(1)))
+	}
+	p.fillDepthSnapshot(first.GetDepthSnapshot(), ob, pair)
+	putDepthMessage(first)
+
+	msg, err := p.buildDepthMessage(pair)
+	if err != nil {
+		t.Fatalf("buildDepthMessage failed: %v", err)
+	}
+	defer putDepthMessage(msg)
+
+	want := p.buildDepthSnapshot(mustDepth(t, p, pair), pair)
+	if got := msg.GetDepthSnapshot(); !proto.Equal(got, want) {
+		t.Errorf("Pooled snapshot = %v, want %v", got, want)
+	}
+}
+
+func mustDepth(t *testing.T, p *Pusher, pair config.PairConfig) *OrderBook {
+	t.Helper()
+	ob, err := p.provider.GetDepth(pair.ChainID, pair.PairID)
+	if err != nil {
+		t.Fatalf("GetDepth failed: %v", err)
+	}
+	return ob
+}
+
+func TestResizeLevels(t *testing.T) {
+	levels := resizeLevels(nil, 3)
+	if len(levels) != 3 {
+		t.Fatalf("len = %d, want 3", len(levels))
+	}
+	kept := levels[1]
+
+	levels = resizeLevels(levels, 1)
+	levels = resizeLevels(levels, 2)
+	if levels[1] != kept {
+		t.Error("PriceLevel not reused after shrinking and growing")
+	}
+	for i, level := range resizeLevels(levels, 10) {
+		if level == nil {
+			t.Errorf("level %d is nil", i)
+		}
+	}
+}
+
+func BenchmarkBuildDepthMessage(b *testing.B) {
+	p, pair := newBenchPusher(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		msg, err := p.buildDepthMessage(pair)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := proto.Marshal(msg); err != nil {
+			b.Fatal(err)
+		}
+		putDepthMessage(msg)
+	}
+}
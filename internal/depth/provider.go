@@ -2,10 +2,14 @@ package depth
 
 import (
 	"math/big"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
 )
 
 // DepthProvider is the depth data provider interface
 // Third-party MMs need to implement this interface to provide real depth data
+// GetDepth is called concurrently for different pairs (see depth.maxConcurrency)
 type DepthProvider interface {
 	// GetDepth retrieves depth data for a specified pair
 	// chainID: Chain ID
@@ -24,24 +28,25 @@ type DepthProvider interface {
 //   - Price = 3400 * 10^6 / 10^18 = 3.4e-9
 //   - Amount = 3.28e18 (i.e., 3.28 WETH in wei)
 type OrderBook struct {
-	MidPrice   *big.Float   // Mid price (wei/wei format: tokenBWei / tokenAWei)
-	Spread     float64      // Bid-ask spread (percentage)
-	Bids       []PriceLevel // Bids (descending by price) - Amount is tokenA quantity
-	Asks       []PriceLevel // Asks (ascending by price) - Amount is tokenA quantity
-	BaseToken  string       // tokenA address (Amount is denominated in this)
-	QuoteToken string       // tokenB address
+	MidPrice   decimal.Decimal // Mid price (wei/wei format: tokenBWei / tokenAWei)
+	Spread     float64         // Bid-ask spread (percentage)
+	Bids       []PriceLevel    // Bids (descending by price) - Amount is tokenA quantity
+	Asks       []PriceLevel    // Asks (ascending by price) - Amount is tokenA quantity
+	BaseToken  string          // tokenA address (Amount is denominated in this)
+	QuoteToken string          // tokenB address
+	Timestamp  time.Time       // Time the book was observed (zero if unknown)
 }
 
 // PriceLevel represents a price level in the order book
 type PriceLevel struct {
-	Price  *big.Float // Price (wei/wei format: tokenBWei / tokenAWei)
-	Amount *big.Int   // Amount (tokenA native decimals, e.g., WETH is 18 decimals)
+	Price  decimal.Decimal // Price (wei/wei format: tokenBWei / tokenAWei)
+	Amount *big.Int        // Amount (tokenA native decimals, e.g., WETH is 18 decimals)
 }
 
 // NewOrderBook creates a new order book
 func NewOrderBook(baseToken, quoteToken string) *OrderBook {
 	return &OrderBook{
-		MidPrice:   big.NewFloat(0),
+		MidPrice:   decimal.Zero,
 		Spread:     0,
 		Bids:       make([]PriceLevel, 0),
 		Asks:       make([]PriceLevel, 0),
@@ -51,7 +56,7 @@ func NewOrderBook(baseToken, quoteToken string) *OrderBook {
 }
 
 // NewPriceLevel creates a new price level
-func NewPriceLevel(price *big.Float, amount *big.Int) PriceLevel {
+func NewPriceLevel(price decimal.Decimal, amount *big.Int) PriceLevel {
 	return PriceLevel{
 		Price:  price,
 		Amount: amount,
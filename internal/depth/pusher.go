@@ -6,11 +6,19 @@ import (
 	"log/slog"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/address"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/buildinfo"
 	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/logging"
 	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
 	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/store"
 	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
 	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
 )
@@ -25,9 +33,41 @@ type Pusher struct {
 	cfg          *config.Config
 	logger       *slog.Logger
 
+	lastPush   map[string]time.Time  // "chainId:pairId" -> last successful push
+	built      map[string]*OrderBook // "chainId:pairId" -> book of the snapshot being pushed
+	published  map[string]*OrderBook // "chainId:pairId" -> book of the last successful push
+	lastPushMu sync.RWMutex
+	pushes     atomic.Uint64 // Successful snapshot pushes
+
+	unknownTypes   map[int32]uint64 // Count of received messages per unknown type
+	unknownFields  atomic.Uint64    // Count of received messages carrying unknown fields
+	capabilities   ws.Capabilities  // Capabilities from the last successful ConnectionAck
+	protocolInfoMu sync.RWMutex
+
+	budget *ws.Budget // Outbound byte budget, nil = unlimited
+
+	reference  quote.PriceFeed // Reference prices of the oracle band, nil = none
+	bandAlerts atomic.Uint64   // Snapshots suppressed by the oracle band
+
+	subscriber DepthSubscriber       // The provider, when it streams books; nil = polled
+	streamed   map[string]*OrderBook // "chainId:pairId" -> latest streamed book
+	streamMu   sync.RWMutex
+
+	sequences map[string]uint64 // "chainId:pairId" -> last attestation sequence
+	attestMu  sync.Mutex
+	state     store.Store // Persists attestation sequences, nil = not persisted
+
+	events *events.Bus // Receives RiskBreach when the oracle band suppresses a snapshot, nil = none
+
+	pushInterval atomic.Int64   // Current push interval (nanoseconds)
+	workers      []*chainWorker // One per chain, in configured pair order
+
 	ctx    context.Context
 	cancel context.CancelFunc
-	wg     sync.WaitGroup
+	wg     sync.WaitGroup // Workers, streams and the pushes started on connection; Stop waits for them
+
+	stopMu   sync.Mutex
+	stopping bool // Set by Stop: no more pushes are started
 }
 
 // NewPusher creates a new depth pusher
@@ -39,14 +79,40 @@ func NewPusher(
 	cfg *config.Config,
 	logger *slog.Logger,
 ) *Pusher {
-	return &Pusher{
+	p := &Pusher{
 		wsClient:     wsClient,
 		provider:     provider,
 		quoteHandler: quoteHandler,
 		signer:       s,
 		cfg:          cfg,
 		logger:       logger.With("component", "DepthPusher"),
+		workers:      newChainWorkers(cfg),
+		streamed:     make(map[string]*OrderBook),
+		sequences:    make(map[string]uint64),
+		lastPush:     make(map[string]time.Time),
+		built:        make(map[string]*OrderBook),
+		published:    make(map[string]*OrderBook),
+		unknownTypes: make(map[int32]uint64),
+		capabilities: make(ws.Capabilities),
 	}
+	p.subscriber, _ = provider.(DepthSubscriber)
+	p.pushInterval.Store(int64(cfg.Depth.PushInterval))
+	return p
+}
+
+// SetEventBus sets the bus risk breaches are published to; call before Start
+func (p *Pusher) SetEventBus(b *events.Bus) {
+	p.events = b
+}
+
+// SetStore sets the state store that attestation sequences persist in; call before Start
+func (p *Pusher) SetStore(s store.Store) {
+	p.state = s
+}
+
+// SetBudget sets the outbound byte budget that depth pushes are deferred by; call before Start
+func (p *Pusher) SetBudget(b *ws.Budget) {
+	p.budget = b
 }
 
 // Start starts the pusher
@@ -59,10 +125,19 @@ func (p *Pusher) Start(ctx context.Context) error {
 	// Set reconnection callback
 	p.wsClient.SetReconnectedHandler(p.onReconnected)
 
-	// Start periodic push
+	// Start periodic push, one worker per chain
 	if p.cfg.Depth.Enabled {
-		p.wg.Add(1)
-		go p.pushLoop()
+		for _, w := range p.workers {
+			p.wg.Add(1)
+			go p.runWorker(w)
+		}
+		// Streaming providers push their updates; the periodic pushes repeat the latest book
+		if p.subscriber != nil {
+			for _, pair := range p.cfg.Pairs {
+				p.wg.Add(1)
+				go p.streamPair(pair)
+			}
+		}
 	}
 
 	p.logger.Info("Depth pusher started", "enabled", p.cfg.Depth.Enabled)
@@ -70,7 +145,11 @@ func (p *Pusher) Start(ctx context.Context) error {
 }
 
 // Stop stops the pusher
+// It returns once every push has finished, so the connection and the store can be closed after it.
 func (p *Pusher) Stop() error {
+	p.stopMu.Lock()
+	p.stopping = true
+	p.stopMu.Unlock()
 	if p.cancel != nil {
 		p.cancel()
 	}
@@ -79,67 +158,100 @@ func (p *Pusher) Stop() error {
 	return nil
 }
 
-// pushLoop is the periodic push loop
-func (p *Pusher) pushLoop() {
-	defer p.wg.Done()
-
-	ticker := time.NewTicker(p.cfg.Depth.PushInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-p.ctx.Done():
-			return
-		case <-ticker.C:
-			p.pushAllPairs()
-		}
+// pushAllPairs starts a push of every chain, without waiting for it
+// The pushes are tracked by wg; none is started once Stop has been called.
+func (p *Pusher) pushAllPairs() {
+	p.stopMu.Lock()
+	defer p.stopMu.Unlock()
+	if p.stopping {
+		return
+	}
+	for _, w := range p.workers {
+		p.wg.Add(1)
+		go func(w *chainWorker) {
+			defer p.wg.Done()
+			p.pushChain(w)
+		}(w)
 	}
 }
 
-// pushAllPairs pushes depth data for all trading pairs
-func (p *Pusher) pushAllPairs() {
+// readyToPush reports whether depth can be pushed now
+func (p *Pusher) readyToPush() bool {
 	// Only push when in Ready state
 	if p.wsClient.GetState() != ws.StateReady {
 		p.logger.Debug("WebSocket not ready, skipping depth push",
 			"state", p.wsClient.GetState().String())
-		return
+		return false
 	}
 
-	for _, pair := range p.cfg.Pairs {
-		if err := p.pushDepthSnapshot(pair); err != nil {
-			p.logger.Error("Failed to push depth snapshot",
-				"chainId", pair.ChainID,
-				"pairId", pair.PairID,
-				"error", err)
+	// Over the outbound byte budget, depth waits; quotes do not
+	if !p.budget.AllowDepth() {
+		p.logger.Debug("Outbound byte budget exhausted, deferring depth push")
+		return false
+	}
+	return true
+}
+
+// forEachPair calls fn for every pair concurrently, each call holding a slot of sem
+// Returns once all calls have finished
+func forEachPair(pairs []config.PairConfig, sem chan struct{}, fn func(i int, pair config.PairConfig)) {
+	var wg sync.WaitGroup
+	for i, pair := range pairs {
+		wg.Add(1)
+		go func(i int, pair config.PairConfig) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fn(i, pair)
+		}(i, pair)
+	}
+	wg.Wait()
+}
+
+// sendBatch sends the snapshots of one chain and returns the messages to the pool
+func (p *Pusher) sendBatch(chainID uint64, msgs []*mmv1.Message) {
+	defer func() {
+		for _, msg := range msgs {
+			putDepthMessage(msg)
 		}
+	}()
+
+	if err := p.wsClient.SendBatch(msgs); err != nil {
+		p.logger.Error("Failed to push depth batch",
+			"chainId", chainID,
+			"snapshots", len(msgs),
+			"error", err)
+		return
 	}
+	now := time.Now()
+	for _, msg := range msgs {
+		p.markPushed(msg.GetDepthSnapshot(), now)
+	}
+	p.logger.Info("Depth batch sent",
+		"chainId", chainID,
+		"snapshots", len(msgs))
 }
 
 // pushDepthSnapshot pushes depth snapshot for a single trading pair
 func (p *Pusher) pushDepthSnapshot(pair config.PairConfig) error {
-	// Get depth data
-	orderBook, err := p.provider.GetDepth(pair.ChainID, pair.PairID)
+	msg, err := p.buildDepthMessage(pair)
 	if err != nil {
-		return fmt.Errorf("failed to get depth: %w", err)
+		return err
 	}
+	return p.sendDepthMessage(pair, msg)
+}
 
-	// Build depth snapshot
-	snapshot := p.buildDepthSnapshot(orderBook, pair)
-
-	// Build message
-	msg := &mmv1.Message{
-		Type:      mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT,
-		Timestamp: time.Now().UnixMilli(),
-		Payload: &mmv1.Message_DepthSnapshot{
-			DepthSnapshot: snapshot,
-		},
-	}
+// sendDepthMessage sends the depth snapshot of a pair and returns the message to the pool
+func (p *Pusher) sendDepthMessage(pair config.PairConfig, msg *mmv1.Message) error {
+	defer putDepthMessage(msg)
 
 	// Send
 	if err := p.wsClient.Send(msg); err != nil {
 		return fmt.Errorf("failed to send depth snapshot: %w", err)
 	}
 
+	snapshot := msg.GetDepthSnapshot()
+	p.markPushed(snapshot, time.Now())
 	p.logger.Info("Depth snapshot sent",
 		"chainId", pair.ChainID,
 		"pairId", pair.PairID,
@@ -149,7 +261,120 @@ func (p *Pusher) pushDepthSnapshot(pair config.PairConfig) error {
 	return nil
 }
 
-// buildDepthSnapshot builds the depth snapshot message
+// markPushed records a successful snapshot push
+func (p *Pusher) markPushed(snapshot *mmv1.DepthSnapshot, at time.Time) {
+	key := fmt.Sprintf("%d:%s", snapshot.ChainId, snapshot.PairId)
+	p.lastPushMu.Lock()
+	p.lastPush[key] = at
+	if ob, ok := p.built[key]; ok {
+		p.published[key] = ob
+		delete(p.built, key)
+	}
+	p.lastPushMu.Unlock()
+	p.pushes.Add(1)
+}
+
+// Pushes returns the number of successful snapshot pushes
+func (p *Pusher) Pushes() uint64 {
+	return p.pushes.Load()
+}
+
+// PublishedBook returns the order book of the last successful push of a pair
+// The book must not be modified
+func (p *Pusher) PublishedBook(chainID uint64, pairID string) (*OrderBook, bool) {
+	p.lastPushMu.RLock()
+	defer p.lastPushMu.RUnlock()
+
+	ob, ok := p.published[fmt.Sprintf("%d:%s", chainID, pairID)]
+	return ob, ok
+}
+
+// LastPushTimes returns the last successful push time per pair
+// key: "chainId:pairId"
+func (p *Pusher) LastPushTimes() map[string]time.Time {
+	p.lastPushMu.RLock()
+	defer p.lastPushMu.RUnlock()
+
+	times := make(map[string]time.Time, len(p.lastPush))
+	for key, t := range p.lastPush {
+		times[key] = t
+	}
+	return times
+}
+
+// buildDepthMessage gets depth data for a trading pair and wraps it in a message
+func (p *Pusher) buildDepthMessage(pair config.PairConfig) (*mmv1.Message, error) {
+	// Get depth data
+	orderBook, err := p.getDepth(pair)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errDepthUnavailable, err)
+	}
+	return p.buildBookMessage(pair, orderBook)
+}
+
+// Preview builds the snapshot message of a pair from the provider, as a push would, without
+// sending it. Attestation sequences advance as for a push. The message is not pooled.
+func (p *Pusher) Preview(pair config.PairConfig) (*mmv1.Message, error) {
+	orderBook, err := p.provider.GetDepth(pair.ChainID, pair.PairID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errDepthUnavailable, err)
+	}
+	msg, err := p.buildBookMessage(pair, orderBook)
+	if err != nil {
+		return nil, err
+	}
+	defer putDepthMessage(msg)
+	return proto.Clone(msg).(*mmv1.Message), nil
+}
+
+// getDepth returns the current book of a pair: the latest streamed one, or the provider's
+func (p *Pusher) getDepth(pair config.PairConfig) (*OrderBook, error) {
+	if p.subscriber != nil {
+		return p.latestStreamed(pair)
+	}
+	return p.provider.GetDepth(pair.ChainID, pair.PairID)
+}
+
+// buildBookMessage wraps the order book of a pair in a message
+func (p *Pusher) buildBookMessage(pair config.PairConfig, orderBook *OrderBook) (*mmv1.Message, error) {
+	// A book far from the reference price is a provider bug, not depth to advertise
+	if err := p.checkBand(pair, orderBook); err != nil {
+		return nil, err
+	}
+	// Advertise the pair spread quotes take, and never more than a quote would accept
+	orderBook = widenBook(orderBook, p.cfg.PairParams(&pair).SpreadBps)
+	maxBase, maxQuote := p.cfg.PairMaxAmountsIn(&pair)
+	orderBook = capBook(orderBook, maxBase, maxQuote)
+
+	// Kept until sent, then reported by PublishedBook
+	p.lastPushMu.Lock()
+	p.built[fmt.Sprintf("%d:%s", pair.ChainID, pair.PairID)] = orderBook
+	p.lastPushMu.Unlock()
+
+	// Build message (pooled; release with putDepthMessage once sent)
+	msg := getDepthMessage()
+	msg.Timestamp = time.Now().UnixMilli()
+	p.fillDepthSnapshot(msg.GetDepthSnapshot(), orderBook, pair)
+	if p.cfg.Depth.Attest {
+		// An unattested snapshot is still depth; the attestation only backs disputes
+		if err := p.attest(msg, pair.ChainID); err != nil {
+			p.logger.Error("Failed to attest depth snapshot",
+				"chainId", pair.ChainID,
+				"pairId", pair.PairID,
+				"error", err)
+		}
+	}
+	return msg, nil
+}
+
+// buildDepthSnapshot builds a new depth snapshot message
+func (p *Pusher) buildDepthSnapshot(ob *OrderBook, pair config.PairConfig) *mmv1.DepthSnapshot {
+	snapshot := &mmv1.DepthSnapshot{}
+	p.fillDepthSnapshot(snapshot, ob, pair)
+	return snapshot
+}
+
+// fillDepthSnapshot fills a depth snapshot, reusing its price levels
 //
 // SwapEngine expected format:
 // - Price: wei/wei ratio (tokenBWei / tokenAWei, no decimals adjustment)
@@ -158,66 +383,120 @@ func (p *Pusher) pushDepthSnapshot(pair config.PairConfig) error {
 // Example: tokenA = WETH (18 decimals), tokenB = USDC (6 decimals), 1 WETH = 3400 USDC
 //   - Price = 3400 * 10^6 / 10^18 = 3.4e-9 = "0.0000000034"
 //   - Amount = 3.28e18 = "3280000000000000000"
-func (p *Pusher) buildDepthSnapshot(ob *OrderBook, pair config.PairConfig) *mmv1.DepthSnapshot {
+func (p *Pusher) fillDepthSnapshot(snapshot *mmv1.DepthSnapshot, ob *OrderBook, pair config.PairConfig) {
 	// Build asks and bids
 	// Price: wei/wei format, Amount: tokenA native decimals
-	asks := make([]*mmv1.PriceLevel, len(ob.Asks))
+	snapshot.Asks = resizeLevels(snapshot.Asks, len(ob.Asks))
 	for i, level := range ob.Asks {
-		asks[i] = &mmv1.PriceLevel{
-			Price:  level.Price.Text('f', 30), // wei/wei format, requires high precision
-			Amount: level.Amount.String(),     // tokenA native decimals
-		}
+		snapshot.Asks[i].Price = p.formatPrice(level.Price, true) // wei/wei format, plain decimal
+		snapshot.Asks[i].Amount = level.Amount.String()           // tokenA native decimals
 	}
 
-	bids := make([]*mmv1.PriceLevel, len(ob.Bids))
+	snapshot.Bids = resizeLevels(snapshot.Bids, len(ob.Bids))
 	for i, level := range ob.Bids {
-		bids[i] = &mmv1.PriceLevel{
-			Price:  level.Price.Text('f', 30), // wei/wei format, requires high precision
-			Amount: level.Amount.String(),     // tokenA native decimals
-		}
+		snapshot.Bids[i].Price = p.formatPrice(level.Price, false) // wei/wei format, plain decimal
+		snapshot.Bids[i].Amount = level.Amount.String()            // tokenA native decimals
 	}
 
-	return &mmv1.DepthSnapshot{
-		ChainId: pair.ChainID,
-		PairId:  pair.PairID,
-		MmId:    strings.ToLower(p.signer.GetAddress().Hex()),
-		TokenA:  strings.ToLower(pair.BaseToken),
-		TokenB:  strings.ToLower(pair.QuoteToken),
-		Bids:    bids,
-		Asks:    asks,
-	}
+	snapshot.ChainId = pair.ChainID
+	snapshot.PairId = pair.PairID
+	snapshot.MmId = address.Normalize(p.signer.GetAddress())
+	snapshot.TokenA = strings.ToLower(pair.BaseToken)
+	snapshot.TokenB = strings.ToLower(pair.QuoteToken)
+	snapshot.Attestation = nil // A pooled snapshot may carry an earlier attestation
 }
 
 // onReconnected is the reconnection success callback
 func (p *Pusher) onReconnected() {
 	p.logger.Info("WebSocket reconnected, will push depth on next tick")
 	// Push depth data immediately after reconnection (will only send after ConnectionAck)
+
+	// Reconcile quotes signed before the gap so expired ones stop counting as exposure.
+	// The protocol has no quote status query, so outcomes are resolved locally by deadline.
+	store := p.quoteHandler.Store()
+	result := store.Reconcile(time.Now())
+	p.logger.Info("Quote store reconciled after reconnect",
+		"expired", result.Expired,
+		"open", result.Open,
+		"pruned", result.Pruned)
+	for _, rec := range store.Open() {
+		p.logger.Info("Quote still open after reconnect",
+			"quoteId", rec.QuoteID,
+			"chainId", rec.ChainID,
+			"deadline", rec.Deadline,
+			"state", rec.State.String())
+	}
 }
 
 // handleMessage handles received messages
 func (p *Pusher) handleMessage(msg *mmv1.Message) error {
+	// Newer servers may send fields this build does not know; they are preserved and ignored
+	if unknown := ws.UnknownFieldNumbers(msg); len(unknown) > 0 {
+		p.unknownFields.Add(1)
+		p.logger.Debug("Message has unknown fields", "type", ws.EnumName(msg.Type), "fields", unknown)
+	}
+
+	// Every inbound message gets a correlation ID, carried in ctx to all logs of its handling
+	ctx := logging.WithTraceID(p.ctx, logging.NewTraceID())
+
 	switch msg.Type {
 	case mmv1.MessageType_MESSAGE_TYPE_QUOTE_REQUEST:
-		return p.handleQuoteRequest(msg.GetQuoteRequest())
+		return p.handleQuoteRequest(ctx, msg.GetQuoteRequest())
 	case mmv1.MessageType_MESSAGE_TYPE_HEARTBEAT:
 		return p.handleHeartbeat(msg.GetHeartbeat())
 	case mmv1.MessageType_MESSAGE_TYPE_CONNECTION_ACK:
 		return p.handleConnectionAck(msg.GetConnectionAck())
 	case mmv1.MessageType_MESSAGE_TYPE_ERROR:
-		return p.handleError(msg.GetError())
+		return p.handleError(ctx, msg.GetError())
 	default:
-		p.logger.Debug("Received unknown message type", "type", msg.Type)
+		p.recordUnknownType(msg.Type)
 	}
 	return nil
 }
 
+// recordUnknownType counts and logs a message type this build does not handle
+// Logged at Warn level the first time a type is seen, at Debug level afterwards
+func (p *Pusher) recordUnknownType(t mmv1.MessageType) {
+	p.protocolInfoMu.Lock()
+	p.unknownTypes[int32(t)]++
+	count := p.unknownTypes[int32(t)]
+	p.protocolInfoMu.Unlock()
+
+	if count == 1 {
+		p.logger.Warn("Received unhandled message type, ignoring", "type", ws.EnumName(t))
+	} else {
+		p.logger.Debug("Received unhandled message type, ignoring", "type", ws.EnumName(t), "count", count)
+	}
+}
+
+// UnknownMessageCounts returns the number of ignored messages per unknown type name
+// and the number of messages that carried unknown fields
+func (p *Pusher) UnknownMessageCounts() (map[string]uint64, uint64) {
+	p.protocolInfoMu.RLock()
+	defer p.protocolInfoMu.RUnlock()
+
+	counts := make(map[string]uint64, len(p.unknownTypes))
+	for t, count := range p.unknownTypes {
+		counts[ws.EnumName(mmv1.MessageType(t))] = count
+	}
+	return counts, p.unknownFields.Load()
+}
+
+// Capabilities returns the protocol capabilities from the last successful ConnectionAck
+func (p *Pusher) Capabilities() ws.Capabilities {
+	p.protocolInfoMu.RLock()
+	defer p.protocolInfoMu.RUnlock()
+	return p.capabilities
+}
+
 // handleQuoteRequest handles quote requests
-func (p *Pusher) handleQuoteRequest(req *mmv1.QuoteRequest) error {
+// Rejections echo the correlation ID of ctx in their message, the only free-form field of the protocol
+func (p *Pusher) handleQuoteRequest(ctx context.Context, req *mmv1.QuoteRequest) error {
 	if req == nil {
 		return nil
 	}
 
-	p.logger.Info("Received quote request",
+	p.logger.InfoContext(ctx, "Received quote request",
 		"quoteId", req.QuoteId,
 		"chainId", req.ChainId,
 		"tokenIn", req.TokenIn,
@@ -225,19 +504,22 @@ func (p *Pusher) handleQuoteRequest(req *mmv1.QuoteRequest) error {
 		"amountIn", req.AmountIn)
 
 	// Call QuoteHandler to process
-	response, err := p.quoteHandler.HandleQuoteRequest(p.ctx, req)
+	response, err := p.quoteHandler.HandleQuoteRequest(ctx, req)
 	if err != nil {
-		p.logger.Error("Quote handling failed", "error", err)
+		p.logger.ErrorContext(ctx, "Quote handling failed", "error", err)
 		return err
 	}
+	if reject := response.GetQuoteReject(); reject != nil {
+		reject.Message = fmt.Sprintf("%s [%s=%s]", reject.Message, logging.TraceKey, logging.TraceID(ctx))
+	}
 
 	// Send response
 	if err := p.wsClient.Send(response); err != nil {
-		p.logger.Error("Failed to send quote response", "error", err)
+		p.logger.ErrorContext(ctx, "Failed to send quote response", "error", err)
 		return err
 	}
 
-	p.logger.Info("Quote response sent", "quoteId", req.QuoteId, "type", response.Type)
+	p.logger.InfoContext(ctx, "Quote response sent", "quoteId", req.QuoteId, "type", response.Type)
 	return nil
 }
 
@@ -277,12 +559,21 @@ func (p *Pusher) handleConnectionAck(ack *mmv1.ConnectionAck) error {
 	if ack.Success {
 		p.logger.Info("Connection successful",
 			"sessionId", ack.SessionId,
-			"mmId", ack.MmId)
+			"mmId", ack.MmId,
+			"version", buildinfo.Version)
+		caps := ws.CapabilitiesFromAck(ack)
+		p.protocolInfoMu.Lock()
+		p.capabilities = caps
+		p.protocolInfoMu.Unlock()
+		p.logger.Info("Server capabilities", "capabilities", caps.Names())
+
 		// Set to Ready state
 		p.wsClient.SetState(ws.StateReady)
 
+		p.applyServerConfig(ack.Config)
+
 		// Push depth data immediately after successful connection
-		go p.pushAllPairs()
+		p.pushAllPairs()
 	} else {
 		p.logger.Error("Connection failed", "error", ack.ErrorMessage)
 	}
@@ -290,33 +581,64 @@ func (p *Pusher) handleConnectionAck(ack *mmv1.ConnectionAck) error {
 	return nil
 }
 
+// applyServerConfig compares server-suggested settings with local configuration
+// Differences are always logged; the depth push interval is only applied when
+// websocket.applyServerConfig is enabled. Heartbeat and quote timeouts are informational.
+func (p *Pusher) applyServerConfig(sc *mmv1.ConnectionConfig) {
+	if sc == nil {
+		return
+	}
+
+	if sc.DepthPushIntervalMs > 0 {
+		server := time.Duration(sc.DepthPushIntervalMs) * time.Millisecond
+		local := time.Duration(p.pushInterval.Load())
+		if server != local {
+			p.logger.Info("Server config differs from local",
+				"field", "depthPushInterval",
+				"local", local,
+				"server", server,
+				"applied", p.cfg.WebSocket.ApplyServerConfig)
+			if p.cfg.WebSocket.ApplyServerConfig {
+				p.pushInterval.Store(int64(server))
+				for _, w := range p.workers {
+					w.setInterval(server)
+				}
+			}
+		}
+	}
+
+	if sc.HeartbeatIntervalMs > 0 {
+		server := time.Duration(sc.HeartbeatIntervalMs) * time.Millisecond
+		if server != p.cfg.WebSocket.HeartbeatInterval {
+			p.logger.Info("Server config differs from local",
+				"field", "heartbeatInterval",
+				"local", p.cfg.WebSocket.HeartbeatInterval,
+				"server", server)
+		}
+	}
+
+	if sc.QuoteTimeoutMs > 0 {
+		p.logger.Info("Server quote timeout",
+			"quoteTimeout", time.Duration(sc.QuoteTimeoutMs)*time.Millisecond)
+	}
+}
+
 // handleError handles error messages
-func (p *Pusher) handleError(err *mmv1.Error) error {
+func (p *Pusher) handleError(ctx context.Context, err *mmv1.Error) error {
 	if err == nil {
 		return nil
 	}
 
-	p.logger.Error("Received error from server",
-		"code", err.Code,
+	p.logger.ErrorContext(ctx, "Received error from server",
+		"code", ws.EnumName(err.Code),
 		"message", err.Message,
 		"relatedQuoteId", err.RelatedQuoteId)
-	return nil
-}
 
-// getChainName returns the chain name for a given chain ID
-func getChainName(chainID uint64) string {
-	switch chainID {
-	case 1:
-		return "ethereum"
-	case 56:
-		return "bsc"
-	case 8453:
-		return "base"
-	case 42161:
-		return "arbitrum"
-	case 10:
-		return "optimism"
-	default:
-		return fmt.Sprintf("chain_%d", chainID)
+	// Server rejected a quote we sent, it no longer counts as exposure
+	if err.RelatedQuoteId != "" {
+		if setErr := p.quoteHandler.Store().SetState(err.RelatedQuoteId, quote.QuoteStateFailed); setErr != nil {
+			p.logger.DebugContext(ctx, "Related quote not tracked", "quoteId", err.RelatedQuoteId, "error", setErr)
+		}
 	}
+	return nil
 }
@@ -3,6 +3,9 @@ package depth
 import (
 	"math/big"
 	"testing"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
 )
 
 func TestNewOrderBook(t *testing.T) {
@@ -20,8 +23,8 @@ func TestNewOrderBook(t *testing.T) {
 	if ob.QuoteToken != quoteToken {
 		t.Errorf("QuoteToken = %s, want %s", ob.QuoteToken, quoteToken)
 	}
-	if ob.MidPrice == nil {
-		t.Error("MidPrice should not be nil")
+	if !ob.MidPrice.IsZero() {
+		t.Errorf("MidPrice = %v, want 0", ob.MidPrice)
 	}
 	if len(ob.Bids) != 0 {
 		t.Error("Bids should be empty")
@@ -32,7 +35,7 @@ func TestNewOrderBook(t *testing.T) {
 }
 
 func TestNewPriceLevel(t *testing.T) {
-	price := big.NewFloat(600.0)
+	price := decimal.NewFromInt(600)
 	amount := big.NewInt(1000000000000000000)
 
 	level := NewPriceLevel(price, amount)
@@ -59,9 +62,8 @@ func TestMockProvider_SetBasePrice(t *testing.T) {
 		t.Error("Price was not set")
 	}
 
-	priceFloat, _ := price.Float64()
-	if priceFloat != 600.0 {
-		t.Errorf("Price = %f, want 600.0", priceFloat)
+	if price.Cmp(decimal.NewFromInt(600)) != 0 {
+		t.Errorf("Price = %v, want 600", price)
 	}
 }
 
@@ -79,7 +81,7 @@ func TestMockProvider_GetDepth(t *testing.T) {
 	}
 
 	// Verify mid price
-	if ob.MidPrice == nil || ob.MidPrice.Sign() == 0 {
+	if ob.MidPrice.Sign() <= 0 {
 		t.Error("MidPrice should be positive")
 	}
 
@@ -92,9 +94,9 @@ func TestMockProvider_GetDepth(t *testing.T) {
 	}
 
 	// Verify asks price ascending (each price should be >= midPrice)
-	midPriceFloat, _ := ob.MidPrice.Float64()
+	midPriceFloat := ob.MidPrice.Float64()
 	for i, ask := range ob.Asks {
-		askPrice, _ := ask.Price.Float64()
+		askPrice := ask.Price.Float64()
 		if askPrice < midPriceFloat*0.99 { // Allow small margin
 			t.Errorf("Ask[%d] price %f should be >= midPrice %f", i, askPrice, midPriceFloat)
 		}
@@ -102,7 +104,7 @@ func TestMockProvider_GetDepth(t *testing.T) {
 
 	// Verify bids price descending (each price should be <= midPrice)
 	for i, bid := range ob.Bids {
-		bidPrice, _ := bid.Price.Float64()
+		bidPrice := bid.Price.Float64()
 		if bidPrice > midPriceFloat*1.01 { // Allow small margin
 			t.Errorf("Bid[%d] price %f should be <= midPrice %f", i, bidPrice, midPriceFloat)
 		}
@@ -149,32 +151,9 @@ func TestBuildPriceKey(t *testing.T) {
 	}
 }
 
-func TestGetChainName(t *testing.T) {
-	tests := []struct {
-		chainID  uint64
-		expected string
-	}{
-		{1, "ethereum"},
-		{56, "bsc"},
-		{8453, "base"},
-		{42161, "arbitrum"},
-		{10, "optimism"},
-		{999, "chain_999"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.expected, func(t *testing.T) {
-			name := getChainName(tt.chainID)
-			if name != tt.expected {
-				t.Errorf("getChainName(%d) = %s, want %s", tt.chainID, name, tt.expected)
-			}
-		})
-	}
-}
-
 func TestMockProvider_GenerateAsks(t *testing.T) {
 	provider := NewMockProvider()
-	midPrice := big.NewFloat(600.0)
+	midPrice := decimal.NewFromInt(600)
 
 	asks := provider.generateAsks(midPrice, 5)
 
@@ -184,8 +163,8 @@ func TestMockProvider_GenerateAsks(t *testing.T) {
 
 	// Verify price ascending
 	for i := 0; i < len(asks)-1; i++ {
-		current, _ := asks[i].Price.Float64()
-		next, _ := asks[i+1].Price.Float64()
+		current := asks[i].Price.Float64()
+		next := asks[i+1].Price.Float64()
 		if current >= next {
 			t.Errorf("asks[%d] price %f should be < asks[%d] price %f", i, current, i+1, next)
 		}
@@ -201,7 +180,7 @@ func TestMockProvider_GenerateAsks(t *testing.T) {
 
 func TestMockProvider_GenerateBids(t *testing.T) {
 	provider := NewMockProvider()
-	midPrice := big.NewFloat(600.0)
+	midPrice := decimal.NewFromInt(600)
 
 	bids := provider.generateBids(midPrice, 5)
 
@@ -211,8 +190,8 @@ func TestMockProvider_GenerateBids(t *testing.T) {
 
 	// Verify price descending
 	for i := 0; i < len(bids)-1; i++ {
-		current, _ := bids[i].Price.Float64()
-		next, _ := bids[i+1].Price.Float64()
+		current := bids[i].Price.Float64()
+		next := bids[i+1].Price.Float64()
 		if current <= next {
 			t.Errorf("bids[%d] price %f should be > bids[%d] price %f", i, current, i+1, next)
 		}
@@ -241,3 +220,35 @@ func TestOrderBook_Spread(t *testing.T) {
 		t.Errorf("Spread = %f%%, seems too high", ob.Spread)
 	}
 }
+
+func TestMockProvider_Seeded(t *testing.T) {
+	clock := time.Unix(1735084800, 0)
+	newProvider := func(seed int64) *MockProvider {
+		p := DefaultMockProviderWithSeed(seed)
+		p.SetClock(func() time.Time { return clock })
+		return p
+	}
+
+	a, err := newProvider(42).GetDepth(56, "WBNB-USDT")
+	if err != nil {
+		t.Fatalf("GetDepth failed: %v", err)
+	}
+	// Another provider and call order must not matter
+	other := newProvider(42)
+	_, _ = other.GetDepth(8453, "WETH-USDC")
+	b, _ := other.GetDepth(56, "WBNB-USDT")
+
+	if !a.Timestamp.Equal(clock) {
+		t.Errorf("Timestamp = %v, want %v", a.Timestamp, clock)
+	}
+	for i := range a.Asks {
+		if a.Asks[i].Price.Cmp(b.Asks[i].Price) != 0 || a.Asks[i].Amount.Cmp(b.Asks[i].Amount) != 0 {
+			t.Fatalf("asks[%d] differ for the same seed and clock", i)
+		}
+	}
+
+	c, _ := newProvider(43).GetDepth(56, "WBNB-USDT")
+	if a.Asks[0].Amount.Cmp(c.Asks[0].Amount) == 0 {
+		t.Error("Different seeds should generate different depth")
+	}
+}
@@ -0,0 +1,216 @@
+package depth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// maxRESTBody is the largest book response read
+const maxRESTBody = 4 << 20
+
+// RESTProvider fetches depth from an HTTP endpoint serving JSON order books
+// It lets an MM with an existing pricing service publish its books without writing Go:
+// depth.rest.mapping says where the levels, prices and amounts are in the JSON, and in
+// which units. The endpoint is requested once per pair on every depth push.
+type RESTProvider struct {
+	rest   config.RESTDepthConfig
+	pairs  []config.PairConfig
+	client *http.Client
+	logger *slog.Logger
+}
+
+// NewRESTProvider creates the provider of cfg.Depth.REST
+func NewRESTProvider(cfg *config.Config, logger *slog.Logger) *RESTProvider {
+	return &RESTProvider{
+		rest:   cfg.Depth.REST,
+		pairs:  cfg.Pairs,
+		client: &http.Client{Timeout: cfg.Depth.REST.Timeout},
+		logger: logger.With("component", "RESTProvider"),
+	}
+}
+
+// GetDepth requests the book of a pair and maps it to an OrderBook
+func (p *RESTProvider) GetDepth(chainID uint64, pairID string) (*OrderBook, error) {
+	pair := p.pair(chainID, pairID)
+	if pair == nil {
+		return nil, fmt.Errorf("pair %s not configured on chain %d", pairID, chainID)
+	}
+
+	body, err := p.fetch(chainID, pairID)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode book: %w", err)
+	}
+
+	ob := NewOrderBook(strings.ToLower(pair.BaseToken), strings.ToLower(pair.QuoteToken))
+	m := p.rest.Mapping
+	if ob.Bids, err = p.levels(doc, m.Bids, pair); err != nil {
+		return nil, fmt.Errorf("bids: %w", err)
+	}
+	if ob.Asks, err = p.levels(doc, m.Asks, pair); err != nil {
+		return nil, fmt.Errorf("asks: %w", err)
+	}
+	if m.Timestamp != "" {
+		v, err := lookupPath(doc, m.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("timestamp: %w", err)
+		}
+		ms, err := strconv.ParseInt(valueString(v), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("timestamp: %w", err)
+		}
+		ob.Timestamp = time.UnixMilli(ms)
+	}
+
+	if len(ob.Bids) > 0 && len(ob.Asks) > 0 {
+		bestBid, bestAsk := ob.Bids[0].Price, ob.Asks[0].Price
+		ob.MidPrice = bestBid.Add(bestAsk).Quo(decimal.NewFromInt(2))
+		if bestBid.Sign() > 0 {
+			ob.Spread = bestAsk.Sub(bestBid).Quo(bestBid).Float64() * 100
+		}
+	}
+	return ob, nil
+}
+
+// pair returns the configuration of a pair, nil if unknown
+func (p *RESTProvider) pair(chainID uint64, pairID string) *config.PairConfig {
+	for i := range p.pairs {
+		if p.pairs[i].ChainID == chainID && p.pairs[i].PairID == pairID {
+			return &p.pairs[i]
+		}
+	}
+	return nil
+}
+
+// fetch requests the book of a pair
+func (p *RESTProvider) fetch(chainID uint64, pairID string) ([]byte, error) {
+	u := strings.NewReplacer(
+		"{chainId}", strconv.FormatUint(chainID, 10),
+		"{pairId}", url.PathEscape(pairID),
+	).Replace(p.rest.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.rest.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range p.rest.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request book: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request book: status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxRESTBody))
+}
+
+// levels maps the level array at path
+func (p *RESTProvider) levels(doc any, path string, pair *config.PairConfig) ([]PriceLevel, error) {
+	v, err := lookupPath(doc, path)
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an array", path)
+	}
+
+	// Token units: price in quote per base token, amount in base tokens
+	priceScale, amountScale := decimal.NewFromInt(1), decimal.NewFromInt(1)
+	if p.rest.Mapping.Units == config.UnitsToken {
+		priceScale = pow10(pair.QuoteTokenDecimals).Quo(pow10(pair.BaseTokenDecimals))
+		amountScale = pow10(pair.BaseTokenDecimals)
+	}
+
+	levels := make([]PriceLevel, 0, len(raw))
+	for i, level := range raw {
+		price, err := decimalAt(level, p.rest.Mapping.Price)
+		if err != nil {
+			return nil, fmt.Errorf("level %d price: %w", i, err)
+		}
+		amount, err := decimalAt(level, p.rest.Mapping.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("level %d amount: %w", i, err)
+		}
+		wei := amount.Mul(amountScale).Int()
+		if price.Sign() <= 0 || wei.Sign() <= 0 {
+			continue // Empty or malformed levels are not published
+		}
+		levels = append(levels, NewPriceLevel(price.Mul(priceScale), wei))
+	}
+	return levels, nil
+}
+
+// pow10 returns 10^n
+func pow10(n int) decimal.Decimal {
+	return decimal.NewFromBigInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil))
+}
+
+// decimalAt parses the number at path, given as a JSON number or string
+func decimalAt(doc any, path string) (decimal.Decimal, error) {
+	v, err := lookupPath(doc, path)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return decimal.Parse(valueString(v))
+}
+
+// valueString returns a JSON number or string as text
+func valueString(v any) string {
+	switch v := v.(type) {
+	case json.Number:
+		return v.String()
+	case string:
+		return v
+	}
+	return fmt.Sprint(v)
+}
+
+// lookupPath follows a dot-separated path of object keys and array indexes
+func lookupPath(doc any, path string) (any, error) {
+	v := doc
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			next, ok := node[key]
+			if !ok {
+				return nil, fmt.Errorf("%s: no key %q", path, key)
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, fmt.Errorf("%s: no index %q", path, key)
+			}
+			v = node[i]
+		default:
+			return nil, fmt.Errorf("%s: %q is not in an object or array", path, key)
+		}
+	}
+	return v, nil
+}
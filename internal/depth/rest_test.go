@@ -0,0 +1,90 @@
+package depth_test
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+)
+
+func TestRESTProvider_MapsBook(t *testing.T) {
+	var gotPath, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		_, _ = io.WriteString(w, `{"ts": 1700000000000, "data": {
+			"bids": [["600", "1.5"], ["599", "0"]],
+			"asks": [["601", 2]]
+		}}`)
+	}))
+	defer server.Close()
+
+	cfg := testutil.Config()
+	cfg.Pairs[0].QuoteTokenDecimals = 6
+	cfg.Depth.REST = config.RESTDepthConfig{
+		URL:     server.URL + "/books/{chainId}/{pairId}",
+		Timeout: time.Second,
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+		Mapping: config.RESTMappingConfig{
+			Bids: "data.bids", Asks: "data.asks", Price: "0", Amount: "1", Timestamp: "ts", Units: config.UnitsToken,
+		},
+	}
+	pair := cfg.Pairs[0]
+	p := depth.NewRESTProvider(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ob, err := p.GetDepth(pair.ChainID, pair.PairID)
+	if err != nil {
+		t.Fatalf("GetDepth failed: %v", err)
+	}
+	if want := "/books/56/WBNB-USDT"; gotPath != want {
+		t.Errorf("path = %s, want %s", gotPath, want)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want the configured header", gotAuth)
+	}
+	// 600 USDT (6 decimals) per WBNB (18 decimals) = 600e-12 wei/wei; the empty level is dropped
+	if len(ob.Bids) != 1 || ob.Bids[0].Price.String() != "0.0000000006" || ob.Bids[0].Amount.String() != "1500000000000000000" {
+		t.Errorf("Bids = %v, want one level of 1.5 WBNB at 0.0000000006", ob.Bids)
+	}
+	if len(ob.Asks) != 1 || ob.Asks[0].Amount.String() != "2000000000000000000" {
+		t.Errorf("Asks = %v, want one level of 2 WBNB", ob.Asks)
+	}
+	if !ob.Timestamp.Equal(time.UnixMilli(1700000000000)) {
+		t.Errorf("Timestamp = %v, want the book time", ob.Timestamp)
+	}
+}
+
+func TestRESTProvider_Errors(t *testing.T) {
+	status := http.StatusInternalServerError
+	body := `{}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	cfg := testutil.Config()
+	cfg.Depth.REST = config.RESTDepthConfig{
+		URL:     server.URL,
+		Timeout: time.Second,
+		Mapping: config.RESTMappingConfig{Bids: "bids", Asks: "asks", Price: "price", Amount: "amount", Units: config.UnitsWei},
+	}
+	pair := cfg.Pairs[0]
+	p := depth.NewRESTProvider(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if _, err := p.GetDepth(pair.ChainID, pair.PairID); err == nil {
+		t.Error("GetDepth() error = nil, want error for status 500")
+	}
+	status = http.StatusOK
+	if _, err := p.GetDepth(pair.ChainID, pair.PairID); err == nil {
+		t.Error("GetDepth() error = nil, want error for a book without bids")
+	}
+	if _, err := p.GetDepth(pair.ChainID, "UNKNOWN"); err == nil {
+		t.Error("GetDepth() error = nil, want error for an unknown pair")
+	}
+}
@@ -0,0 +1,58 @@
+package depth
+
+import (
+	"io"
+	"log/slog"
+	"math"
+	"math/big"
+	"regexp"
+	"testing"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+var plainDecimalRe = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// FuzzBuildDepthSnapshot checks wei/wei prices and native amounts survive string conversion exactly
+func FuzzBuildDepthSnapshot(f *testing.F) {
+	f.Add(600.0, []byte{0x0d, 0xe0, 0xb6, 0xb3, 0xa7, 0x64, 0x00, 0x00})
+	f.Add(3.4e-9, []byte{0x2d, 0x84, 0x5b, 0x3b, 0x70, 0x68, 0x00, 0x00})
+	f.Add(1e-25, []byte{0x01})
+	f.Add(1e30, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+
+	s, err := signer.NewSignerFromHex("0x0000000000000000000000000000000000000000000000000000000000000001", signer.NewDomainManager())
+	if err != nil {
+		f.Fatalf("NewSignerFromHex failed: %v", err)
+	}
+	p := NewPusher(nil, nil, nil, s, &config.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	pair := config.PairConfig{ChainID: 56, PairID: "A-B", BaseToken: "0xA", QuoteToken: "0xB"}
+
+	f.Fuzz(func(t *testing.T, price float64, amount []byte) {
+		if math.IsNaN(price) || math.IsInf(price, 0) || price <= 0 {
+			return
+		}
+		ob := NewOrderBook(pair.BaseToken, pair.QuoteToken)
+		want := decimal.NewFromFloat(price)
+		ob.Asks = append(ob.Asks, NewPriceLevel(want, new(big.Int).SetBytes(amount)))
+
+		snapshot := p.buildDepthSnapshot(ob, pair)
+		level := snapshot.Asks[0]
+
+		if !plainDecimalRe.MatchString(level.Price) {
+			t.Fatalf("Price %q is not a plain decimal", level.Price)
+		}
+		got, err := decimal.Parse(level.Price)
+		if err != nil {
+			t.Fatalf("Price %q does not parse: %v", level.Price, err)
+		}
+		if got.Cmp(want) != 0 {
+			t.Fatalf("Price %v formatted as %q does not round-trip", want, level.Price)
+		}
+
+		if n, ok := new(big.Int).SetString(level.Amount, 10); !ok || n.Cmp(new(big.Int).SetBytes(amount)) != 0 {
+			t.Fatalf("Amount %q does not round-trip", level.Amount)
+		}
+	})
+}
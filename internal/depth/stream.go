@@ -0,0 +1,120 @@
+package depth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+)
+
+// DepthSubscriber is implemented by providers that publish order books as they change
+// Event-driven sources (CEX websockets, on-chain log subscriptions) implement it next to, or
+// instead of, polling through GetDepth. SubscribeDepth returns the books of a pair; the
+// provider closes the channel when it loses the source, and the pusher subscribes again.
+type DepthSubscriber interface {
+	SubscribeDepth(chainID uint64, pairID string) (<-chan *OrderBook, error)
+}
+
+// StreamOnly wraps a DepthSubscriber that has no GetDepth, for NewPusher
+func StreamOnly(s DepthSubscriber) DepthProvider {
+	return streamOnly{s}
+}
+
+// streamOnly is a DepthSubscriber without polling
+type streamOnly struct {
+	DepthSubscriber
+}
+
+// GetDepth fails: the books of a stream-only provider come from its subscriptions
+func (streamOnly) GetDepth(chainID uint64, pairID string) (*OrderBook, error) {
+	return nil, errors.New("stream-only depth provider")
+}
+
+// streamRetry is the pause before subscribing again after a stream failed or ended
+const streamRetry = time.Second
+
+// latestStreamed returns the last book streamed for a pair
+func (p *Pusher) latestStreamed(pair config.PairConfig) (*OrderBook, error) {
+	p.streamMu.RLock()
+	defer p.streamMu.RUnlock()
+	ob, ok := p.streamed[fmt.Sprintf("%d:%s", pair.ChainID, pair.PairID)]
+	if !ok {
+		return nil, fmt.Errorf("no book streamed for chain %d pair %s", pair.ChainID, pair.PairID)
+	}
+	return ob, nil
+}
+
+// setStreamed records the latest book of a pair, nil removes it
+func (p *Pusher) setStreamed(pair config.PairConfig, ob *OrderBook) {
+	key := fmt.Sprintf("%d:%s", pair.ChainID, pair.PairID)
+	p.streamMu.Lock()
+	defer p.streamMu.Unlock()
+	if ob == nil {
+		delete(p.streamed, key)
+		return
+	}
+	p.streamed[key] = ob
+}
+
+// streamPair keeps a subscription to the books of a pair until the pusher stops
+// Without a stream the pair's book is dropped, so stale depth is not pushed.
+func (p *Pusher) streamPair(pair config.PairConfig) {
+	defer p.wg.Done()
+
+	for {
+		ch, err := p.subscriber.SubscribeDepth(pair.ChainID, pair.PairID)
+		if err != nil {
+			p.logger.Warn("Depth subscription failed",
+				"chainId", pair.ChainID,
+				"pairId", pair.PairID,
+				"error", err)
+		} else {
+			p.consumeStream(pair, ch)
+			p.setStreamed(pair, nil)
+			if p.ctx.Err() == nil {
+				p.logger.Warn("Depth stream ended, subscribing again",
+					"chainId", pair.ChainID,
+					"pairId", pair.PairID)
+			}
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-time.After(streamRetry):
+		}
+	}
+}
+
+// consumeStream records the books of a stream and pushes each update
+// Updates within depth.triggers.minInterval of the pair's last push are coalesced into one push
+// when the interval has passed.
+func (p *Pusher) consumeStream(pair config.PairConfig, ch <-chan *OrderBook) {
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case ob, ok := <-ch:
+			if !ok {
+				return
+			}
+			if ob == nil {
+				continue
+			}
+			p.setStreamed(pair, ob)
+			if pending != nil {
+				continue // A push of the latest book is already scheduled
+			}
+			if wait := p.triggerWait(pair); wait > 0 {
+				pending = time.After(wait)
+				continue
+			}
+			p.Trigger(pair.ChainID, pair.PairID)
+		case <-pending:
+			pending = nil
+			p.Trigger(pair.ChainID, pair.PairID)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package depth_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// chanSubscriber streams the books sent on its channel
+type chanSubscriber struct {
+	books chan *depth.OrderBook
+}
+
+func (s *chanSubscriber) SubscribeDepth(chainID uint64, pairID string) (<-chan *depth.OrderBook, error) {
+	return s.books, nil
+}
+
+func TestPusher_StreamingProvider(t *testing.T) {
+	cfg := testutil.Config()
+	pair := cfg.Pairs[0]
+	cfg.Pairs = cfg.Pairs[:1]
+	// Only stream updates push during the test
+	cfg.Depth = config.DepthConfig{Enabled: true, PushInterval: time.Hour, MaxConcurrency: 1, Triggers: config.DepthTriggersConfig{
+		MinInterval: 100 * time.Millisecond,
+	}}
+
+	base, quoteToken := common.HexToAddress(pair.BaseToken), common.HexToAddress(pair.QuoteToken)
+	book := func(mid float64) *depth.OrderBook {
+		return testutil.LinearBook(base, quoteToken, mid, 0.001, 1, big.NewInt(1e18))
+	}
+	sub := &chanSubscriber{books: make(chan *depth.OrderBook)}
+
+	client := testutil.NewFakeWSClient()
+	client.SetState(ws.StateReady)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := testutil.NewFakeSigner(common.HexToAddress(testutil.DefaultMMID))
+	pusher := depth.NewPusher(client, depth.StreamOnly(sub), quote.NewHandler(testutil.NewFixedRateStrategy(600, 1), s, cfg, logger), s, cfg, logger)
+	if err := pusher.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer pusher.Stop()
+
+	// The first update is pushed at once
+	sub.books <- book(600)
+	waitSnapshots(t, client, 1)
+
+	// Updates within minInterval are coalesced into one push of the latest book
+	for _, mid := range []float64{601, 602, 603} {
+		sub.books <- book(mid)
+	}
+	waitSnapshots(t, client, 2)
+	time.Sleep(150 * time.Millisecond)
+	snapshots := client.SentOfType(mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT)
+	if len(snapshots) != 2 {
+		t.Fatalf("snapshots = %d, want 2", len(snapshots))
+	}
+	published, _ := pusher.PublishedBook(pair.ChainID, pair.PairID)
+	if mid, _ := published.Mid(); mid.String() != "603" {
+		t.Errorf("published mid = %s, want the latest streamed 603", mid)
+	}
+}
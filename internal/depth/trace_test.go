@@ -0,0 +1,62 @@
+package depth_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/logging"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+func TestPusher_TracesQuoteRequests(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(logging.NewTraceHandler(slog.NewTextHandler(&buf, nil)))
+	cfg := testutil.Config()
+
+	client := testutil.NewFakeWSClient()
+	fakeSigner := testutil.NewFakeSigner(common.HexToAddress(testutil.DefaultMMID))
+	handler := quote.NewHandler(testutil.NewFixedRateStrategy(600, 1), fakeSigner, cfg, logger)
+	pusher := depth.NewPusher(client, testutil.NewStaticDepthProvider(), handler, fakeSigner, cfg, logger)
+	if err := pusher.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer pusher.Stop()
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	req := testutil.QuoteRequest()
+	req.TokenOut = "0x9999999999999999999999999999999999999999" // No such pair
+	if err := client.Deliver(testutil.NewQuoteRequest(req)); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+
+	rejects := client.SentOfType(mmv1.MessageType_MESSAGE_TYPE_QUOTE_REJECT)
+	if len(rejects) != 1 {
+		t.Fatalf("sent %d rejects, want 1", len(rejects))
+	}
+	m := regexp.MustCompile(`\[traceId=([0-9a-f]{16})\]$`).FindStringSubmatch(rejects[0].GetQuoteReject().Message)
+	if m == nil {
+		t.Fatalf("reject message = %q, want a trace ID", rejects[0].GetQuoteReject().Message)
+	}
+
+	// Every log line of the request, from the pusher and the quote handler, carries the ID
+	var traced int
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if strings.Contains(line, "traceId="+m[1]) {
+			traced++
+		}
+	}
+	if traced < 3 {
+		t.Errorf("%d log lines carry the trace ID, want at least 3:\n%s", traced, buf.String())
+	}
+}
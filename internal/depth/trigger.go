@@ -0,0 +1,130 @@
+package depth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// triggerQueue is the number of Trigger requests waiting for the push loop
+const triggerQueue = 64
+
+// pairRef identifies a pair to push
+type pairRef struct {
+	chainID uint64
+	pairID  string
+}
+
+// Trigger requests a push of a pair before the next periodic push
+// Call it when the MM's view of the pair changes materially, e.g. after an inventory change.
+// Requests within depth.triggers.minInterval of the pair's last push, or while the push loop
+// is busy, are dropped: the next periodic push covers them.
+func (p *Pusher) Trigger(chainID uint64, pairID string) {
+	w := p.worker(chainID)
+	if w == nil {
+		return
+	}
+	select {
+	case w.triggerCh <- pairRef{chainID: chainID, pairID: pairID}:
+	default:
+	}
+}
+
+// pushTriggered pushes a pair requested by Trigger
+func (p *Pusher) pushTriggered(w *chainWorker, ref pairRef) {
+	var pair *config.PairConfig
+	for i := range w.pairs {
+		if w.pairs[i].PairID == ref.pairID {
+			pair = &w.pairs[i]
+			break
+		}
+	}
+	if pair == nil || !p.triggerAllowed(*pair) || w.backoffLeft() > 0 || p.cfg.ChainFlags(w.chainID).PauseDepth || !p.readyToPush() {
+		return
+	}
+	if err := p.pushDepthSnapshot(*pair); err != nil {
+		p.logger.Error("Failed to push triggered depth snapshot",
+			"chainId", pair.ChainID,
+			"pairId", pair.PairID,
+			"error", err)
+	}
+}
+
+// pushMovedPairs pushes the pairs of a chain whose provider mid price moved more than
+// depth.triggers.midMoveBps from the mid of their last published book
+func (p *Pusher) pushMovedPairs(w *chainWorker) {
+	if w.backoffLeft() > 0 || p.cfg.ChainFlags(w.chainID).PauseDepth || !p.readyToPush() {
+		return
+	}
+	forEachPair(w.pairs, w.sem, func(_ int, pair config.PairConfig) {
+		if !p.triggerAllowed(pair) {
+			return
+		}
+		ob, err := p.getDepth(pair)
+		if err != nil {
+			return // The periodic push reports provider errors
+		}
+		moveBps, moved := p.midMove(pair, ob)
+		if !moved {
+			return
+		}
+
+		msg, err := p.buildBookMessage(pair, ob)
+		if err == nil {
+			err = p.sendDepthMessage(pair, msg)
+		}
+		if err != nil {
+			p.logger.Error("Failed to push depth snapshot after mid move",
+				"chainId", pair.ChainID,
+				"pairId", pair.PairID,
+				"error", err)
+			return
+		}
+		p.logger.Debug("Depth pushed after mid move",
+			"chainId", pair.ChainID,
+			"pairId", pair.PairID,
+			"moveBps", moveBps)
+	})
+}
+
+// midMove returns how far the mid of ob is from the last published book of a pair (basis points)
+// and whether that exceeds depth.triggers.midMoveBps. A pair never published has always moved.
+func (p *Pusher) midMove(pair config.PairConfig, ob *OrderBook) (float64, bool) {
+	mid, ok := ob.Mid()
+	if !ok {
+		return 0, false
+	}
+	published, ok := p.PublishedBook(pair.ChainID, pair.PairID)
+	if !ok {
+		return 0, true
+	}
+	last, ok := published.Mid()
+	if !ok {
+		return 0, true
+	}
+
+	move := mid.Sub(last)
+	if move.Sign() < 0 {
+		move = move.Neg()
+	}
+	moveBps := move.Quo(last).Float64() * 10000
+	return moveBps, move.Quo(last).Cmp(decimal.New(int64(p.cfg.Depth.Triggers.MidMoveBps), -4)) > 0
+}
+
+// triggerAllowed reports whether the last push of a pair is at least depth.triggers.minInterval ago
+func (p *Pusher) triggerAllowed(pair config.PairConfig) bool {
+	return p.triggerWait(pair) <= 0
+}
+
+// triggerWait returns how long until a triggered push of a pair is allowed
+func (p *Pusher) triggerWait(pair config.PairConfig) time.Duration {
+	p.lastPushMu.RLock()
+	last, ok := p.lastPush[fmt.Sprintf("%d:%s", pair.ChainID, pair.PairID)]
+	p.lastPushMu.RUnlock()
+	if !ok {
+		return 0
+	}
+	return p.cfg.Depth.Triggers.MinInterval - time.Since(last)
+}
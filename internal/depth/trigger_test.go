@@ -0,0 +1,75 @@
+package depth_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// waitSnapshots waits until client has sent n depth snapshots
+func waitSnapshots(t *testing.T, client *testutil.FakeWSClient, n int) {
+	t.Helper()
+	deadline := time.After(2 * time.Second)
+	for len(client.SentOfType(mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT)) < n {
+		select {
+		case <-deadline:
+			t.Fatalf("snapshots = %d, want %d", len(client.SentOfType(mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT)), n)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestPusher_TriggeredPushes(t *testing.T) {
+	cfg := testutil.Config()
+	pair := cfg.Pairs[0]
+	cfg.Pairs = cfg.Pairs[:1]
+	// The periodic push never fires during the test
+	cfg.Depth = config.DepthConfig{Enabled: true, PushInterval: time.Hour, MaxConcurrency: 1, Triggers: config.DepthTriggersConfig{
+		MidMoveBps:    50,
+		CheckInterval: 5 * time.Millisecond,
+	}}
+
+	base, quoteToken := common.HexToAddress(pair.BaseToken), common.HexToAddress(pair.QuoteToken)
+	provider := testutil.NewStaticDepthProvider()
+	provider.SetBook(pair.ChainID, pair.PairID, testutil.LinearBook(base, quoteToken, 600, 0.001, 2, big.NewInt(1e18)))
+
+	client := testutil.NewFakeWSClient()
+	client.SetState(ws.StateReady)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := testutil.NewFakeSigner(common.HexToAddress(testutil.DefaultMMID))
+	pusher := depth.NewPusher(client, provider, quote.NewHandler(testutil.NewFixedRateStrategy(600, 1), s, cfg, logger), s, cfg, logger)
+	if err := pusher.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer pusher.Stop()
+
+	// A pair never published is pushed by the first check
+	waitSnapshots(t, client, 1)
+
+	// 0.1% stays within the threshold
+	provider.SetBook(pair.ChainID, pair.PairID, testutil.LinearBook(base, quoteToken, 600.6, 0.001, 2, big.NewInt(1e18)))
+	time.Sleep(50 * time.Millisecond)
+	if got := len(client.SentOfType(mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT)); got != 1 {
+		t.Fatalf("snapshots = %d after a 0.1%% move, want 1", got)
+	}
+
+	// 1% is pushed
+	provider.SetBook(pair.ChainID, pair.PairID, testutil.LinearBook(base, quoteToken, 606, 0.001, 2, big.NewInt(1e18)))
+	waitSnapshots(t, client, 2)
+
+	// Explicit triggers, e.g. on inventory changes
+	pusher.Trigger(pair.ChainID, pair.PairID)
+	waitSnapshots(t, client, 3)
+}
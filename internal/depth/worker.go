@@ -0,0 +1,202 @@
+package depth
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// errDepthUnavailable wraps provider errors, the failures chain workers back off on
+var errDepthUnavailable = errors.New("failed to get depth")
+
+// chainWorker pushes the depth of the pairs of one chain
+// Every chain has its own goroutine, fetch limiter and backoff, so a chain whose provider
+// fails or hangs (e.g. an RPC outage) only delays its own snapshots.
+type chainWorker struct {
+	chainID    uint64
+	pairs      []config.PairConfig
+	sem        chan struct{}      // Bounds concurrent depth fetches to Depth.MaxConcurrency
+	intervalCh chan time.Duration // Push interval changes, latest only
+	triggerCh  chan pairRef       // Pairs to push before the next periodic push (see Trigger)
+
+	mu           sync.Mutex
+	failures     int       // Consecutive rounds in which the provider failed for every pair
+	backoffUntil time.Time // Rounds before this are skipped
+}
+
+// newChainWorkers returns a worker per chain of cfg.Pairs, in configured order
+func newChainWorkers(cfg *config.Config) []*chainWorker {
+	limit := cfg.Depth.MaxConcurrency
+	if limit <= 0 {
+		limit = 1
+	}
+	var workers []*chainWorker
+	byChain := make(map[uint64]*chainWorker)
+	for _, pair := range cfg.Pairs {
+		w, ok := byChain[pair.ChainID]
+		if !ok {
+			w = &chainWorker{
+				chainID:    pair.ChainID,
+				sem:        make(chan struct{}, limit),
+				intervalCh: make(chan time.Duration, 1),
+				triggerCh:  make(chan pairRef, triggerQueue),
+			}
+			byChain[pair.ChainID] = w
+			workers = append(workers, w)
+		}
+		w.pairs = append(w.pairs, pair)
+	}
+	return workers
+}
+
+// worker returns the worker of a chain, nil if no pair is configured on it
+func (p *Pusher) worker(chainID uint64) *chainWorker {
+	for _, w := range p.workers {
+		if w.chainID == chainID {
+			return w
+		}
+	}
+	return nil
+}
+
+// setInterval notifies the worker of a push interval change, replacing any pending one
+func (w *chainWorker) setInterval(interval time.Duration) {
+	select {
+	case <-w.intervalCh:
+	default:
+	}
+	select {
+	case w.intervalCh <- interval:
+	default:
+	}
+}
+
+// backoffLeft returns how long the worker still skips rounds
+func (w *chainWorker) backoffLeft() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return time.Until(w.backoffUntil)
+}
+
+// runWorker is the periodic push loop of a chain
+func (p *Pusher) runWorker(w *chainWorker) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(time.Duration(p.pushInterval.Load()))
+	defer ticker.Stop()
+
+	// Mid price moves are checked between the periodic pushes
+	var checkC <-chan time.Time
+	if p.cfg.Depth.Triggers.MidMoveBps > 0 {
+		check := time.NewTicker(p.cfg.Depth.Triggers.CheckInterval)
+		defer check.Stop()
+		checkC = check.C
+	}
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case interval := <-w.intervalCh:
+			ticker.Reset(interval)
+			p.logger.Info("Depth push interval updated", "chainId", w.chainID, "interval", interval)
+		case <-ticker.C:
+			p.pushChain(w)
+		case <-checkC:
+			p.pushMovedPairs(w)
+		case ref := <-w.triggerCh:
+			p.pushTriggered(w, ref)
+		}
+	}
+}
+
+// pushChain pushes the snapshots of every pair of a chain
+// With Depth.BatchWrites, the snapshots are sent in a single write burst: the protocol has no
+// multi-pair depth message, so they are still individual frames, written back to back.
+// Otherwise each pair is sent as soon as its depth is ready.
+func (p *Pusher) pushChain(w *chainWorker) {
+	if !p.readyToPush() {
+		return
+	}
+	if wait := w.backoffLeft(); wait > 0 {
+		p.logger.Debug("Depth provider backing off, skipping chain", "chainId", w.chainID, "retryIn", wait)
+		return
+	}
+	if p.cfg.ChainFlags(w.chainID).PauseDepth {
+		p.logger.Debug("Depth paused, skipping chain", "chainId", w.chainID)
+		return
+	}
+
+	var fetched atomic.Bool // The provider answered for at least one pair
+	built := make([]*mmv1.Message, len(w.pairs))
+	forEachPair(w.pairs, w.sem, func(i int, pair config.PairConfig) {
+		msg, err := p.buildDepthMessage(pair)
+		if !errors.Is(err, errDepthUnavailable) {
+			fetched.Store(true)
+		}
+		if err != nil {
+			p.logger.Error("Failed to build depth snapshot",
+				"chainId", pair.ChainID,
+				"pairId", pair.PairID,
+				"error", err)
+			return
+		}
+		if p.cfg.Depth.BatchWrites {
+			built[i] = msg
+			return
+		}
+		if err := p.sendDepthMessage(pair, msg); err != nil {
+			p.logger.Error("Failed to push depth snapshot",
+				"chainId", pair.ChainID,
+				"pairId", pair.PairID,
+				"error", err)
+		}
+	})
+	p.recordRound(w, fetched.Load())
+
+	// Batches keep the configured pair order
+	msgs := built[:0]
+	for _, msg := range built {
+		if msg != nil {
+			msgs = append(msgs, msg)
+		}
+	}
+	if len(msgs) > 0 {
+		p.sendBatch(w.chainID, msgs)
+	}
+}
+
+// recordRound updates the backoff of a chain after a push round
+// Rounds in which the provider failed for every pair back off exponentially from the push
+// interval up to Depth.MaxBackoff; the first round reaching the provider again resets it.
+func (p *Pusher) recordRound(w *chainWorker, fetched bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if fetched {
+		if w.failures > 0 {
+			p.logger.Info("Depth provider recovered on chain", "chainId", w.chainID, "failedRounds", w.failures)
+		}
+		w.failures = 0
+		w.backoffUntil = time.Time{}
+		return
+	}
+
+	w.failures++
+	delay := time.Duration(p.pushInterval.Load())
+	for i := 1; i < w.failures && delay < p.cfg.Depth.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.cfg.Depth.MaxBackoff {
+		delay = p.cfg.Depth.MaxBackoff
+	}
+	w.backoffUntil = time.Now().Add(delay)
+	p.logger.Warn("Depth provider failing on chain, backing off",
+		"chainId", w.chainID,
+		"failedRounds", w.failures,
+		"retryIn", delay)
+}
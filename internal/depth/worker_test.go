@@ -0,0 +1,178 @@
+package depth_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// outageProvider hangs on one chain and fails on another, like RPC outages
+type outageProvider struct {
+	*testutil.StaticDepthProvider
+	hungChain, failingChain uint64
+	release                 chan struct{}
+	failures                atomic.Int32
+}
+
+func (p *outageProvider) GetDepth(chainID uint64, pairID string) (*depth.OrderBook, error) {
+	switch chainID {
+	case p.hungChain:
+		<-p.release
+	case p.failingChain:
+		p.failures.Add(1)
+		return nil, errors.New("rpc unavailable")
+	}
+	return p.StaticDepthProvider.GetDepth(chainID, pairID)
+}
+
+func TestPusher_ChainOutageIsolated(t *testing.T) {
+	for _, batch := range []bool{false, true} {
+		cfg := testutil.Config()
+		healthy := cfg.Pairs[0]
+		hung, failing := healthy, healthy
+		hung.ChainID, hung.PairID = 1, "HUNG"
+		failing.ChainID, failing.PairID = 137, "FAILING"
+		cfg.Pairs = []config.PairConfig{hung, failing, healthy}
+		cfg.Depth = config.DepthConfig{Enabled: true, PushInterval: 10 * time.Millisecond, BatchWrites: batch, MaxConcurrency: 1, MaxBackoff: time.Hour}
+
+		provider := &outageProvider{StaticDepthProvider: testutil.NewStaticDepthProvider(), hungChain: 1, failingChain: 137, release: make(chan struct{})}
+		provider.SetBook(healthy.ChainID, healthy.PairID, testutil.LinearBook(common.HexToAddress(healthy.BaseToken), common.HexToAddress(healthy.QuoteToken), 600, 0.001, 2, big.NewInt(1e18)))
+
+		client := testutil.NewFakeWSClient()
+		client.SetState(ws.StateReady)
+		logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+		s := testutil.NewFakeSigner(common.HexToAddress(testutil.DefaultMMID))
+		pusher := depth.NewPusher(client, provider, quote.NewHandler(testutil.NewFixedRateStrategy(600, 1), s, cfg, logger), s, cfg, logger)
+		if err := pusher.Start(context.Background()); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+
+		// The healthy chain keeps its pace while chain 1 hangs and chain 137 fails
+		waitSnapshots(t, client, 10)
+		snapshots := client.SentOfType(mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT)
+		for _, msg := range snapshots {
+			if got := msg.GetDepthSnapshot().ChainId; got != healthy.ChainID {
+				t.Fatalf("batch=%v: snapshot of chain %d, want only chain %d", batch, got, healthy.ChainID)
+			}
+		}
+		// The failing chain backs off exponentially instead of retrying every tick
+		if got := int(provider.failures.Load()); got == 0 || 2*got > len(snapshots) {
+			t.Errorf("batch=%v: failing chain fetched %d times in %d healthy rounds, want at most half", batch, got, len(snapshots))
+		}
+
+		close(provider.release)
+		pusher.Stop()
+	}
+}
+
+func TestPusher_PausedChainPushesNothing(t *testing.T) {
+	cfg := testutil.Config()
+	active := cfg.Pairs[0]
+	paused := active
+	paused.ChainID, paused.PairID = 137, "PAUSED"
+	cfg.Pairs = []config.PairConfig{paused, active}
+	cfg.Chains = []config.ChainConfig{{ChainID: 137, PauseDepth: true}}
+	cfg.Depth = config.DepthConfig{Enabled: true, PushInterval: 10 * time.Millisecond, MaxConcurrency: 1}
+
+	provider := testutil.NewStaticDepthProvider()
+	for _, pair := range cfg.Pairs {
+		provider.SetBook(pair.ChainID, pair.PairID, testutil.LinearBook(common.HexToAddress(pair.BaseToken), common.HexToAddress(pair.QuoteToken), 600, 0.001, 2, big.NewInt(1e18)))
+	}
+	client := testutil.NewFakeWSClient()
+	client.SetState(ws.StateReady)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := testutil.NewFakeSigner(common.HexToAddress(testutil.DefaultMMID))
+	pusher := depth.NewPusher(client, provider, quote.NewHandler(testutil.NewFixedRateStrategy(600, 1), s, cfg, logger), s, cfg, logger)
+	if err := pusher.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer pusher.Stop()
+
+	waitSnapshots(t, client, 3)
+	pusher.Trigger(137, "PAUSED")
+	time.Sleep(20 * time.Millisecond)
+	for _, msg := range client.SentOfType(mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT) {
+		if got := msg.GetDepthSnapshot().ChainId; got != active.ChainID {
+			t.Fatalf("snapshot of paused chain %d", got)
+		}
+	}
+}
+
+// gatedProvider reports each GetDepth on entered and blocks it until release is closed
+type gatedProvider struct {
+	*testutil.StaticDepthProvider
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (p *gatedProvider) GetDepth(chainID uint64, pairID string) (*depth.OrderBook, error) {
+	p.entered <- struct{}{}
+	<-p.release
+	return p.StaticDepthProvider.GetDepth(chainID, pairID)
+}
+
+func TestPusher_StopWaitsForConnectionPush(t *testing.T) {
+	cfg := testutil.Config()
+	pair := cfg.Pairs[0]
+	cfg.Depth = config.DepthConfig{Enabled: true, PushInterval: time.Hour, MaxConcurrency: 1} // Only the push on ConnectionAck
+
+	provider := &gatedProvider{StaticDepthProvider: testutil.NewStaticDepthProvider(), entered: make(chan struct{}, 1), release: make(chan struct{})}
+	provider.SetBook(pair.ChainID, pair.PairID, testutil.LinearBook(common.HexToAddress(pair.BaseToken), common.HexToAddress(pair.QuoteToken), 600, 0.001, 2, big.NewInt(1e18)))
+	client := testutil.NewFakeWSClient()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := testutil.NewFakeSigner(common.HexToAddress(testutil.DefaultMMID))
+	pusher := depth.NewPusher(client, provider, quote.NewHandler(testutil.NewFixedRateStrategy(600, 1), s, cfg, logger), s, cfg, logger)
+	if err := pusher.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	if err := client.Deliver(testutil.NewConnectionAck(testutil.DefaultMMID)); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+	<-provider.entered
+
+	stopped := make(chan struct{})
+	go func() {
+		pusher.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned while the connection push was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(provider.release)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return after the push finished")
+	}
+	if got := len(client.SentOfType(mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT)); got != 1 {
+		t.Errorf("%d snapshots sent before Stop returned, want the connection push", got)
+	}
+
+	// Once stopped, a late ConnectionAck starts no push
+	if err := client.Deliver(testutil.NewConnectionAck(testutil.DefaultMMID)); err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+	select {
+	case <-provider.entered:
+		t.Error("push started after Stop")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
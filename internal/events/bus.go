@@ -0,0 +1,198 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// Kind is the kind of an event
+type Kind string
+
+// Event kinds, with the type of Event.Data each carries
+const (
+	QuoteSigned            Kind = "quote_signed"             // quote.QuoteRecord of the signed quote
+	QuoteRejected          Kind = "quote_rejected"           // mmv1.RejectReason; Detail is the reject message
+	FillObserved           Kind = "fill_observed"            // quote.QuoteRecord of the filled quote
+	ConnectionStateChanged Kind = "connection_state_changed" // ws.ConnectionState entered; Detail is "<server URL>: from -> to"
+	RiskBreach             Kind = "risk_breach"              // Detail names the check; Data is its finding
+	SignerFailover         Kind = "signer_failover"          // signer.FailoverState after the switch; Detail is "from -> to"
+	SignerRotated          Kind = "signer_rotated"           // signer.Rotation; Detail is "<old address> -> <new address>"
+)
+
+// Risk checks named in the Detail of RiskBreach events
+const (
+	RiskOracleBand  = "oracle_band" // Data: error returned by the band check
+	RiskConsistency = "consistency" // Data: depth.Inconsistency
+)
+
+// Event is something that happened in the market maker
+type Event struct {
+	Kind    Kind
+	Time    time.Time
+	ChainID uint64 // 0 when the event is not about a chain
+	PairID  string
+	QuoteID string
+	Detail  string
+	Data    any                // Kind-specific payload, see the kinds
+	Request *mmv1.QuoteRequest // Request of QuoteSigned and QuoteRejected events, nil otherwise; read-only
+}
+
+// DefaultBuffer is the number of events a subscriber may fall behind before events are dropped
+const DefaultBuffer = 256
+
+// Bus delivers published events to the subscribers of their kind
+// Publishing never blocks: a subscriber whose buffer is full misses the event, which is
+// counted in its Dropped. Hot paths (quote handling, depth pushes) publish, so a slow
+// subscriber must not slow them down. A nil Bus publishes nothing.
+type Bus struct {
+	logger *slog.Logger
+
+	mu   sync.RWMutex
+	subs []*Subscription
+
+	countMu sync.Mutex
+	counts  map[Kind]uint64 // Published events by kind
+}
+
+// NewBus creates an event bus
+func NewBus(logger *slog.Logger) *Bus {
+	return &Bus{
+		logger: logger.With("component", "EventBus"),
+		counts: make(map[Kind]uint64),
+	}
+}
+
+// Subscription receives the events of its kinds
+type Subscription struct {
+	bus   *Bus
+	name  string
+	kinds map[Kind]bool // nil = every kind
+	ch    chan Event
+
+	dropped atomic.Uint64
+	once    sync.Once
+}
+
+// Subscribe subscribes to events of the given kinds, or of every kind if none is given
+// name identifies the subscriber in logs and metrics; buffer <= 0 means DefaultBuffer.
+func (b *Bus) Subscribe(name string, buffer int, kinds ...Kind) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	sub := &Subscription{bus: b, name: name, ch: make(chan Event, buffer)}
+	if len(kinds) > 0 {
+		sub.kinds = make(map[Kind]bool, len(kinds))
+		for _, kind := range kinds {
+			sub.kinds[kind] = true
+		}
+	}
+	b.mu.Lock()
+	b.subs = append(b.subs, sub)
+	b.mu.Unlock()
+	return sub
+}
+
+// Publish delivers an event to its subscribers; Time defaults to now
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	b.countMu.Lock()
+	b.counts[e.Kind]++
+	b.countMu.Unlock()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, sub := range b.subs {
+		if sub.kinds != nil && !sub.kinds[e.Kind] {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+			// Log the first drop and then every power of two, not every event of a flood
+			if n := sub.dropped.Add(1); n&(n-1) == 0 {
+				b.logger.Warn("Event subscriber falling behind, events dropped",
+					"subscriber", sub.name,
+					"kind", e.Kind,
+					"dropped", n)
+			}
+		}
+	}
+}
+
+// Published returns the number of events published by kind
+func (b *Bus) Published() map[Kind]uint64 {
+	b.countMu.Lock()
+	defer b.countMu.Unlock()
+	counts := make(map[Kind]uint64, len(b.counts))
+	for kind, n := range b.counts {
+		counts[kind] = n
+	}
+	return counts
+}
+
+// Subscriptions returns the open subscriptions, by name
+func (b *Bus) Subscriptions() []*Subscription {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	subs := append([]*Subscription(nil), b.subs...)
+	sort.Slice(subs, func(i, j int) bool { return subs[i].name < subs[j].name })
+	return subs
+}
+
+// Name returns the subscriber name
+func (s *Subscription) Name() string {
+	return s.name
+}
+
+// Events returns the channel of delivered events, closed by Close
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Dropped returns the number of events missed because the buffer was full
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close unsubscribes and closes the event channel
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		b := s.bus
+		b.mu.Lock()
+		for i, sub := range b.subs {
+			if sub == s {
+				b.subs = append(b.subs[:i], b.subs[i+1:]...)
+				break
+			}
+		}
+		close(s.ch)
+		b.mu.Unlock()
+	})
+}
+
+// Run calls fn with every event until ctx is done, then closes the subscription
+func (s *Subscription) Run(ctx context.Context, fn func(Event)) {
+	defer s.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-s.ch:
+			if !ok {
+				return
+			}
+			fn(e)
+		}
+	}
+}
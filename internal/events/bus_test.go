@@ -0,0 +1,91 @@
+package events
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func newTestBus() *Bus {
+	return NewBus(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestBus_DeliversByKind(t *testing.T) {
+	bus := newTestBus()
+	all := bus.Subscribe("all", 8)
+	quotes := bus.Subscribe("quotes", 8, QuoteSigned, QuoteRejected)
+
+	bus.Publish(Event{Kind: QuoteSigned, QuoteID: "q1"})
+	bus.Publish(Event{Kind: RiskBreach, Detail: RiskOracleBand})
+
+	if got := len(all.Events()); got != 2 {
+		t.Errorf("all received %d events, want 2", got)
+	}
+	if got := len(quotes.Events()); got != 1 {
+		t.Fatalf("quotes received %d events, want 1", got)
+	}
+	e := <-quotes.Events()
+	if e.QuoteID != "q1" || e.Time.IsZero() {
+		t.Errorf("event = %+v, want q1 stamped with the publish time", e)
+	}
+	if got := bus.Published()[QuoteSigned]; got != 1 {
+		t.Errorf("published quote_signed = %d, want 1", got)
+	}
+}
+
+func TestBus_SlowSubscriberDoesNotBlock(t *testing.T) {
+	bus := newTestBus()
+	slow := bus.Subscribe("slow", 1)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			bus.Publish(Event{Kind: FillObserved})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a full subscriber")
+	}
+	if got := slow.Dropped(); got != 9 {
+		t.Errorf("Dropped = %d, want 9", got)
+	}
+}
+
+func TestSubscription_RunAndClose(t *testing.T) {
+	bus := newTestBus()
+	sub := bus.Subscribe("runner", 4)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	got := make(chan Event, 1)
+	stopped := make(chan struct{})
+	go func() {
+		sub.Run(ctx, func(e Event) { got <- e })
+		close(stopped)
+	}()
+	bus.Publish(Event{Kind: ConnectionStateChanged})
+	select {
+	case e := <-got:
+		if e.Kind != ConnectionStateChanged {
+			t.Errorf("kind = %s, want %s", e.Kind, ConnectionStateChanged)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not deliver the event")
+	}
+
+	cancel()
+	<-stopped
+	if subs := bus.Subscriptions(); len(subs) != 0 {
+		t.Errorf("subscriptions after Run returned = %d, want 0", len(subs))
+	}
+	bus.Publish(Event{Kind: ConnectionStateChanged}) // Must not send on the closed channel
+}
+
+func TestBus_NilPublishes(t *testing.T) {
+	var bus *Bus
+	bus.Publish(Event{Kind: QuoteSigned})
+}
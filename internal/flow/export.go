@@ -0,0 +1,212 @@
+// Package flow exports an anonymized sample of the RFQ flow for offline analysis
+package flow
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// Header is the first row of an export file
+var Header = []string{"time", "chain_id", "pair_id", "side", "size_bucket", "outcome", "reject_reason", "spread_bps", "recipient_hash"}
+
+// Outcomes of a quote request
+const (
+	OutcomeSigned   = "signed"
+	OutcomeRejected = "rejected"
+)
+
+// Sides of a quote request on its pair
+const (
+	SideSellBase = "sell_base" // Taker sends the base token
+	SideBuyBase  = "buy_base"  // Taker sends the quote token
+)
+
+// Exporter appends sampled quote requests as CSV rows
+// Rows hold no quote IDs, addresses or exact amounts: the recipient is an HMAC-SHA256 under the
+// configured salt, so one taker's requests can be grouped without revealing who the taker is.
+type Exporter struct {
+	cfg    *config.Config
+	out    io.WriteCloser
+	w      *csv.Writer
+	key    []byte
+	rate   float64
+	logger *slog.Logger
+
+	exported uint64
+}
+
+// NewExporter opens flowExport.file, writing the header if the file is new
+func NewExporter(cfg *config.Config, logger *slog.Logger) (*Exporter, error) {
+	f, err := os.OpenFile(cfg.FlowExport.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	key := []byte(cfg.FlowExport.GetSalt())
+	if len(key) == 0 {
+		// Hashes of one run can still be grouped, but not linked to another run's
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		logger.Warn("flowExport salt not set, recipient hashes change on restart")
+	}
+
+	e := &Exporter{
+		cfg:    cfg,
+		out:    f,
+		w:      csv.NewWriter(f),
+		key:    key,
+		rate:   cfg.FlowExport.SampleRate,
+		logger: logger.With("component", "FlowExport"),
+	}
+	if info.Size() == 0 {
+		if err := e.write(Header); err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// Start subscribes to bus and exports quote requests until ctx is done, then closes the file
+func (e *Exporter) Start(ctx context.Context, bus *events.Bus) {
+	sub := bus.Subscribe("flow-export", 0, events.QuoteSigned, events.QuoteRejected)
+	go func() {
+		sub.Run(ctx, e.export)
+		if err := e.out.Close(); err != nil {
+			e.logger.Error("Failed to close flow export file", "error", err)
+		}
+		e.logger.Info("Flow export closed", "file", e.cfg.FlowExport.File, "rows", e.exported)
+	}()
+}
+
+// export writes the row of an event if its request is sampled
+func (e *Exporter) export(ev events.Event) {
+	req := ev.Request
+	if req == nil || !Sampled(req.QuoteId, e.rate) {
+		return
+	}
+	if err := e.write(e.row(ev)); err != nil {
+		e.logger.Error("Failed to write flow export row", "error", err)
+		return
+	}
+	e.exported++
+}
+
+// row returns the CSV fields of an event
+func (e *Exporter) row(ev events.Event) []string {
+	req := ev.Request
+	tokenIn, tokenOut := e.token(req.ChainId, req.TokenIn), e.token(req.ChainId, req.TokenOut)
+	pair := e.cfg.GetPairConfigByAddress(req.ChainId, tokenIn, tokenOut)
+
+	var pairID, side, bucket string
+	if pair != nil {
+		pairID, side = pair.PairID, SideBuyBase
+		decimals := pair.QuoteTokenDecimals
+		if tokenIn == common.HexToAddress(pair.BaseToken) {
+			side, decimals = SideSellBase, pair.BaseTokenDecimals
+		}
+		if amountIn, ok := new(big.Int).SetString(req.AmountIn, 10); ok {
+			bucket = SizeBucket(amountIn, decimals)
+		}
+	} else if ev.PairID != "" {
+		pairID = ev.PairID // Native/wrapped conversions have no pair config
+	}
+
+	outcome, reason, spread := OutcomeRejected, "", ""
+	switch data := ev.Data.(type) {
+	case quote.QuoteRecord:
+		outcome, spread = OutcomeSigned, strconv.FormatUint(uint64(data.Info.FeeBps), 10)
+	case mmv1.RejectReason:
+		reason = strings.TrimPrefix(data.String(), "REJECT_REASON_")
+	}
+
+	return []string{
+		ev.Time.UTC().Truncate(time.Second).Format(time.RFC3339),
+		strconv.FormatUint(req.ChainId, 10),
+		pairID,
+		side,
+		bucket,
+		outcome,
+		reason,
+		spread,
+		HashAddress(e.key, req.Recipient),
+	}
+}
+
+// token returns the address of a request token, the chain's wrapped token for the native one
+func (e *Exporter) token(chainID uint64, s string) common.Address {
+	token := common.HexToAddress(s)
+	if token == (common.Address{}) {
+		if wrapped, ok := e.cfg.ChainRegistry().WrappedNative(chainID); ok {
+			return wrapped
+		}
+	}
+	return token
+}
+
+// write writes and flushes a row, so the file is complete up to the last request
+func (e *Exporter) write(row []string) error {
+	if err := e.w.Write(row); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// Sampled reports whether the request of quoteID is in a sample of rate
+// The choice is a hash of the quote ID, so it is the same on every run and in every process.
+func Sampled(quoteID string, rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	sum := sha256.Sum256([]byte(quoteID))
+	return float64(binary.BigEndian.Uint64(sum[:8])) < rate*math.MaxUint64
+}
+
+// HashAddress returns the first 16 bytes of the HMAC-SHA256 of an address under key, as hex
+// Addresses are lowercased first, so checksummed and plain forms hash the same; empty stays empty.
+func HashAddress(key []byte, addr string) string {
+	if addr == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.ToLower(addr)))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// SizeBucket returns the order of magnitude of amount in token units, e.g. "1e3" for 1000 to 9999.99
+func SizeBucket(amount *big.Int, decimals int) string {
+	if amount.Sign() <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("1e%d", len(amount.String())-1-decimals)
+}
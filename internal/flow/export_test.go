@@ -0,0 +1,124 @@
+package flow
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+func TestExporter_WritesAnonymizedRows(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.FlowExport.File = filepath.Join(t.TempDir(), "flow.csv")
+	cfg.FlowExport.SampleRate = 1
+	cfg.FlowExport.Salt = "salt"
+	e, err := NewExporter(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewExporter failed: %v", err)
+	}
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 500e6, time.UTC)
+	signed := testutil.QuoteRequest()
+	e.export(events.Event{Kind: events.QuoteSigned, Time: at, PairID: "WBNB-USDT", Request: signed,
+		Data: quote.QuoteRecord{Info: quote.QuoteInfo{FeeBps: 30}}})
+	rejected := testutil.QuoteRequest()
+	rejected.TokenIn, rejected.TokenOut = testutil.DefaultTokenOut, testutil.DefaultTokenIn
+	rejected.AmountIn = "25000000000000000000000"
+	rejected.Recipient = "0x" + strings.ToUpper(testutil.DefaultUser[2:])
+	e.export(events.Event{Kind: events.QuoteRejected, Time: at, Request: rejected,
+		Data: mmv1.RejectReason_REJECT_REASON_AMOUNT_TOO_LARGE})
+	e.export(events.Event{Kind: events.QuoteRejected, Time: at}) // No request, nothing to export
+	if err := e.out.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(cfg.FlowExport.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(rows) != 3 || strings.Join(rows[0], ",") != strings.Join(Header, ",") {
+		t.Fatalf("rows = %q", rows)
+	}
+	hash := HashAddress([]byte("salt"), testutil.DefaultUser)
+	want := []string{
+		"2026-03-01T12:00:00Z,56,WBNB-USDT,sell_base,1e0,signed,,30," + hash,
+		"2026-03-01T12:00:00Z,56,WBNB-USDT,buy_base,1e4,rejected,AMOUNT_TOO_LARGE,," + hash,
+	}
+	for i, w := range want {
+		if got := strings.Join(rows[i+1], ","); got != w {
+			t.Errorf("row %d = %s, want %s", i+1, got, w)
+		}
+	}
+	if strings.Contains(strings.ToLower(strings.Join(rows[1], ",")), strings.ToLower(testutil.DefaultUser[2:])) {
+		t.Error("row contains the recipient address")
+	}
+	if rows[1][8] != rows[2][8] {
+		t.Error("checksummed and plain forms of an address hash differently")
+	}
+
+	// Reopening the file appends without a second header
+	e, err = NewExporter(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = e.out.Close()
+	if data, _ := os.ReadFile(cfg.FlowExport.File); strings.Count(string(data), "recipient_hash") != 1 {
+		t.Errorf("header written again:\n%s", data)
+	}
+}
+
+func TestSampled(t *testing.T) {
+	n := 0
+	for i := 0; i < 10000; i++ {
+		id := fmt.Sprintf("quote-%d", i)
+		if Sampled(id, 0.1) {
+			n++
+			if !Sampled(id, 0.1) {
+				t.Fatalf("%s sampled once, then not", id)
+			}
+		}
+	}
+	if n < 800 || n > 1200 {
+		t.Errorf("sampled %d of 10000 at rate 0.1", n)
+	}
+	if !Sampled("any", 1) {
+		t.Error("rate 1 skipped a request")
+	}
+}
+
+func TestSizeBucket(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals int
+		want     string
+	}{
+		{"1000000000000000000", 18, "1e0"},
+		{"9999999999999999999", 18, "1e0"},
+		{"2500000", 6, "1e0"},
+		{"12345000000", 6, "1e4"},
+		{"5000", 6, "1e-3"},
+		{"0", 6, ""},
+	}
+	for _, tt := range tests {
+		amount, _ := new(big.Int).SetString(tt.amount, 10)
+		if got := SizeBucket(amount, tt.decimals); got != tt.want {
+			t.Errorf("SizeBucket(%s, %d) = %q, want %q", tt.amount, tt.decimals, got, tt.want)
+		}
+	}
+}
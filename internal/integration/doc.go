@@ -0,0 +1,4 @@
+// Package integration holds the end-to-end tests run by test/integration/docker-compose.yml
+// They are behind the "integration" build tag and need an anvil node with a deployed
+// MMQuoteVerifier, see MM_INTEGRATION_RPC and MM_INTEGRATION_VERIFIER
+package integration
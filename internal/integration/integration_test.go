@@ -0,0 +1,248 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/conformance"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/runner"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// verifierABI is the ABI of test/integration/contracts/MMQuoteVerifier.sol (functions used here)
+const verifierABI = `[
+	{"type":"function","name":"DOMAIN_SEPARATOR","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"bytes32"}]},
+	{"type":"function","name":"recover","stateMutability":"view","inputs":[
+		{"name":"q","type":"tuple","components":[
+			{"name":"rfqManager","type":"address"},
+			{"name":"from","type":"address"},
+			{"name":"to","type":"address"},
+			{"name":"inputToken","type":"address"},
+			{"name":"outputToken","type":"address"},
+			{"name":"amountIn","type":"uint256"},
+			{"name":"amountOut","type":"uint256"},
+			{"name":"deadline","type":"uint256"},
+			{"name":"nonce","type":"uint256"},
+			{"name":"extraData","type":"bytes"}
+		]},
+		{"name":"signature","type":"bytes"}
+	],"outputs":[{"name":"","type":"address"}]}
+]`
+
+// mmQuoteTuple mirrors the MMQuote struct of the verifier contract
+type mmQuoteTuple struct {
+	RfqManager  common.Address
+	From        common.Address
+	To          common.Address
+	InputToken  common.Address
+	OutputToken common.Address
+	AmountIn    *big.Int
+	AmountOut   *big.Int
+	Deadline    *big.Int
+	Nonce       *big.Int
+	ExtraData   []byte
+}
+
+// rpcClient is a minimal JSON-RPC client for eth_chainId and eth_call
+type rpcClient struct {
+	url string
+}
+
+// call performs a JSON-RPC call and decodes the result
+func (c *rpcClient) call(method string, result interface{}, params ...interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(c.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", method, err)
+	}
+	if out.Error != nil {
+		return fmt.Errorf("%s: %s", method, out.Error.Message)
+	}
+	return json.Unmarshal(out.Result, result)
+}
+
+// ethCall calls a view function of the verifier
+func (c *rpcClient) ethCall(contract common.Address, parsed abi.ABI, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+
+	var result hexutil.Bytes
+	if err := c.call("eth_call", &result, map[string]string{
+		"to":   contract.Hex(),
+		"data": hexutil.Encode(data),
+	}, "latest"); err != nil {
+		return nil, err
+	}
+	return parsed.Unpack(method, result)
+}
+
+func TestIntegration_EndToEnd(t *testing.T) {
+	rpcURL := os.Getenv("MM_INTEGRATION_RPC")
+	if rpcURL == "" {
+		t.Skip("MM_INTEGRATION_RPC not set, run via make integration")
+	}
+	verifier := common.HexToAddress(os.Getenv("MM_INTEGRATION_VERIFIER"))
+	if verifier == (common.Address{}) {
+		t.Fatal("MM_INTEGRATION_VERIFIER not set")
+	}
+
+	rpc := &rpcClient{url: rpcURL}
+	parsed, err := abi.JSON(strings.NewReader(verifierABI))
+	if err != nil {
+		t.Fatalf("Failed to parse verifier ABI: %v", err)
+	}
+
+	// The mock strategy and provider only price chain 56, so anvil runs with --chain-id 56
+	var chainID hexutil.Uint64
+	if err := rpc.call("eth_chainId", &chainID); err != nil {
+		t.Fatalf("Failed to get chain id: %v", err)
+	}
+	if uint64(chainID) != testutil.DefaultChainID {
+		t.Fatalf("Chain id = %d, want %d", chainID, testutil.DefaultChainID)
+	}
+
+	// Configure the MM with the deployed verifier as verifying contract
+	cfg := testutil.Config()
+	cfg.EIP712Domains[0].VerifyingContract = verifier.Hex()
+	cfg.Depth.Enabled = true
+	cfg.Depth.PushInterval = 100 * time.Millisecond
+
+	// Domain separators must match before any signature can
+	out, err := rpc.ethCall(verifier, parsed, "DOMAIN_SEPARATOR")
+	if err != nil {
+		t.Fatalf("DOMAIN_SEPARATOR call failed: %v", err)
+	}
+	onChain := out[0].([32]byte)
+	domain := &signer.EIP712Domain{
+		Name:              cfg.EIP712Domains[0].Name,
+		Version:           cfg.EIP712Domains[0].Version,
+		ChainID:           new(big.Int).SetUint64(testutil.DefaultChainID),
+		VerifyingContract: verifier,
+	}
+	if !bytes.Equal(onChain[:], domain.DomainSeparator()) {
+		t.Fatalf("Domain separator = %x, on-chain %x", domain.DomainSeparator(), onChain)
+	}
+
+	// Run the full runner against the mock swap engine
+	req := testutil.QuoteRequest()
+	checker := conformance.NewChecker(nil)
+	server := conformance.NewServer(checker, []conformance.Step{
+		conformance.SendStep(testutil.NewConnectionAck(testutil.DefaultMMID)),
+		conformance.WaitStep(300 * time.Millisecond),
+		conformance.SendStep(testutil.NewQuoteRequest(req)),
+		conformance.WaitStep(500 * time.Millisecond),
+	})
+	defer server.Close()
+	cfg.WebSocket.ServerURL = server.URL()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r, err := runner.New(cfg, logger)
+	if err != nil {
+		t.Fatalf("runner.New failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- r.Run(ctx) }()
+
+	select {
+	case <-server.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timeout waiting for script to finish")
+	}
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	checker.Finish()
+	for _, v := range checker.Violations() {
+		t.Errorf("Protocol violation: %s", v)
+	}
+
+	// Depth pushed
+	var depthCount int
+	var response *mmv1.QuoteResponse
+	for _, msg := range server.Received() {
+		switch msg.Type {
+		case mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT:
+			depthCount++
+		case mmv1.MessageType_MESSAGE_TYPE_QUOTE_RESPONSE:
+			response = msg.GetQuoteResponse()
+		}
+	}
+	if depthCount == 0 {
+		t.Error("No depth snapshot pushed")
+	}
+
+	// RFQ answered
+	if response == nil {
+		t.Fatal("Quote request not answered with a QuoteResponse")
+	}
+	order := response.Order
+
+	// Signature verifies on-chain
+	quote := mmQuoteTuple{
+		RfqManager:  common.HexToAddress(order.RfqManager),
+		From:        common.HexToAddress(req.From),
+		To:          common.HexToAddress(req.Recipient),
+		InputToken:  common.HexToAddress(req.TokenIn),
+		OutputToken: common.HexToAddress(req.TokenOut),
+		Deadline:    big.NewInt(order.Deadline),
+		ExtraData:   order.ExtraData,
+	}
+	quote.AmountIn, _ = new(big.Int).SetString(order.AmountIn, 10)
+	quote.AmountOut, _ = new(big.Int).SetString(order.AmountOut, 10)
+	quote.Nonce, _ = new(big.Int).SetString(order.Nonce, 10)
+	if quote.ExtraData == nil {
+		quote.ExtraData = []byte{}
+	}
+
+	out, err = rpc.ethCall(verifier, parsed, "recover", quote, order.Signature)
+	if err != nil {
+		t.Fatalf("recover call failed: %v", err)
+	}
+	recovered := out[0].(common.Address)
+	if recovered != common.HexToAddress(order.Signer) {
+		t.Errorf("On-chain recovered signer = %s, want %s", recovered.Hex(), order.Signer)
+	}
+}
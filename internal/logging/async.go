@@ -0,0 +1,128 @@
+// Package logging provides slog handlers for the MM
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultQueueSize is the default number of records buffered by an AsyncHandler
+const DefaultQueueSize = 4096
+
+// AsyncHandler is an slog.Handler that hands records to a background goroutine
+// Formatting and writes happen off the caller's goroutine, so slow log output never stalls
+// the read loop or the quote handler. When the queue is full, records are dropped and counted
+// instead of blocking; the number dropped is logged once the queue drains.
+type AsyncHandler struct {
+	inner slog.Handler
+	q     *queue
+}
+
+// queue is shared by an AsyncHandler and the handlers derived from it
+type queue struct {
+	entries  chan entry
+	dropped  atomic.Uint64
+	reported uint64 // Drops already logged, owned by the worker
+	mu       sync.RWMutex
+	closed   bool
+	done     chan struct{}
+}
+
+// entry is a queued record with the handler that writes it
+type entry struct {
+	h slog.Handler
+	r slog.Record
+}
+
+// NewAsyncHandler creates an async handler writing to inner with room for size queued records
+// Call Close to flush queued records before exiting
+func NewAsyncHandler(inner slog.Handler, size int) *AsyncHandler {
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+	q := &queue{
+		entries: make(chan entry, size),
+		done:    make(chan struct{}),
+	}
+	go q.run(inner)
+	return &AsyncHandler{inner: inner, q: q}
+}
+
+// Enabled reports whether the inner handler handles records at level
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle queues the record, dropping it if the queue is full
+// After Close, records are written synchronously
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.q.mu.RLock()
+	defer h.q.mu.RUnlock()
+
+	if h.q.closed {
+		return h.inner.Handle(ctx, r)
+	}
+	select {
+	case h.q.entries <- entry{h: h.inner, r: r.Clone()}:
+	default:
+		h.q.dropped.Add(1)
+	}
+	return nil
+}
+
+// WithAttrs returns a handler with the attributes added, sharing the queue
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{inner: h.inner.WithAttrs(attrs), q: h.q}
+}
+
+// WithGroup returns a handler with the group added, sharing the queue
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{inner: h.inner.WithGroup(name), q: h.q}
+}
+
+// Dropped returns the number of records dropped because the queue was full
+func (h *AsyncHandler) Dropped() uint64 {
+	return h.q.dropped.Load()
+}
+
+// Close writes all queued records and stops the background goroutine
+// Safe to call more than once
+func (h *AsyncHandler) Close() error {
+	h.q.mu.Lock()
+	if !h.q.closed {
+		h.q.closed = true
+		close(h.q.entries)
+	}
+	h.q.mu.Unlock()
+
+	<-h.q.done
+	return nil
+}
+
+// run writes queued records until the queue is closed
+func (q *queue) run(inner slog.Handler) {
+	defer close(q.done)
+
+	for e := range q.entries {
+		_ = e.h.Handle(context.Background(), e.r)
+		if len(q.entries) == 0 {
+			q.reportDropped(inner)
+		}
+	}
+	q.reportDropped(inner)
+}
+
+// reportDropped logs the records dropped since the last report
+func (q *queue) reportDropped(inner slog.Handler) {
+	dropped := q.dropped.Load()
+	if dropped == q.reported {
+		return
+	}
+	r := slog.NewRecord(time.Now(), slog.LevelWarn, "log records dropped, queue full", 0)
+	r.AddAttrs(slog.Uint64("dropped", dropped-q.reported), slog.Uint64("droppedTotal", dropped))
+	_ = inner.Handle(context.Background(), r)
+	q.reported = dropped
+}
@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// blockingHandler blocks every Handle call until release is closed
+type blockingHandler struct {
+	slog.Handler
+	release chan struct{}
+}
+
+func (h *blockingHandler) Handle(ctx context.Context, r slog.Record) error {
+	<-h.release
+	return h.Handler.Handle(ctx, r)
+}
+
+func TestAsyncHandler_WritesInOrder(t *testing.T) {
+	var out lockedBuffer
+	h := NewAsyncHandler(slog.NewTextHandler(&out, nil), 0)
+	logger := slog.New(h).With("component", "test")
+
+	for i := 0; i < 100; i++ {
+		logger.Info("record", "i", i)
+	}
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 100 {
+		t.Fatalf("lines = %d, want 100", len(lines))
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, fmt.Sprintf("component=test i=%d", i)) {
+			t.Fatalf("line %d = %q, want component=test i=%d", i, line, i)
+		}
+	}
+	if h.Dropped() != 0 {
+		t.Errorf("Dropped = %d, want 0", h.Dropped())
+	}
+}
+
+func TestAsyncHandler_DropsWhenFull(t *testing.T) {
+	var out lockedBuffer
+	inner := &blockingHandler{Handler: slog.NewTextHandler(&out, nil), release: make(chan struct{})}
+	h := NewAsyncHandler(inner, 4)
+	logger := slog.New(h)
+
+	// The worker holds at most one record, the queue 4; the rest must be dropped without blocking
+	for i := 0; i < 20; i++ {
+		logger.Info("record", "i", i)
+	}
+	if got := h.Dropped(); got < 15 {
+		t.Errorf("Dropped = %d, want >= 15", got)
+	}
+
+	close(inner.release)
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "log records dropped") {
+		t.Errorf("output does not report dropped records:\n%s", out.String())
+	}
+}
+
+func TestAsyncHandler_SynchronousAfterClose(t *testing.T) {
+	var out lockedBuffer
+	h := NewAsyncHandler(slog.NewTextHandler(&out, nil), 0)
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := h.Close(); err != nil {
+		t.Fatalf("second Close failed: %v", err)
+	}
+
+	slog.New(h).WithGroup("g").Info("after close", "k", "v")
+	if !strings.Contains(out.String(), "g.k=v") {
+		t.Errorf("output = %q, want the record written synchronously", out.String())
+	}
+}
@@ -0,0 +1,231 @@
+package logging
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Log sink types
+const (
+	SinkSyslog   = "syslog"   // Local syslog daemon or a remote syslog server
+	SinkJournald = "journald" // systemd journal, native protocol
+	SinkTCP      = "tcp"      // JSON lines over TCP or TLS, e.g. a Vector socket source
+)
+
+// DefaultJournalSocket is the socket of the systemd journal's native protocol
+const DefaultJournalSocket = "/run/systemd/journal/socket"
+
+// localSyslogSockets are the sockets a local syslog daemon listens on, tried in order
+var localSyslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+const (
+	sinkWriteTimeout = 5 * time.Second
+	sinkRedialDelay  = time.Second // Records are dropped for this long after a failed dial
+)
+
+// SinkConfig selects where a log sink ships records
+type SinkConfig struct {
+	Type string // SinkSyslog, SinkJournald or SinkTCP
+
+	// SinkSyslog: "udp://host:514", "tcp://host:514" or "tls://host:6514", empty = local daemon.
+	// SinkJournald: socket path, empty = DefaultJournalSocket. SinkTCP: host:port.
+	Address string
+
+	TLS   bool       // SinkTCP: connect with TLS
+	Tag   string     // SinkSyslog and SinkJournald: program identifier
+	Level slog.Level // Minimum level shipped
+}
+
+// NewSink creates the handler of a log sink
+// Records are written by a background goroutine (see AsyncHandler); call Close to flush them.
+// The connection is made on the first record and remade after a write error. Records that
+// cannot be written are dropped, so an unreachable sink never stalls the MM.
+func NewSink(cfg SinkConfig) (*AsyncHandler, error) {
+	w := &sinkWriter{pid: os.Getpid(), tag: cfg.Tag}
+	if w.tag == "" {
+		w.tag = "mm"
+	}
+	// Syslog and the journal timestamp records themselves
+	opts := &slog.HandlerOptions{Level: cfg.Level, ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 && a.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+		return a
+	}}
+
+	switch cfg.Type {
+	case SinkSyslog:
+		if err := w.syslog(cfg.Address); err != nil {
+			return nil, err
+		}
+	case SinkJournald:
+		path := cfg.Address
+		if path == "" {
+			path = DefaultJournalSocket
+		}
+		w.dial = func() (net.Conn, error) { return net.Dial("unixgram", path) }
+		w.frame = w.journalFrame
+	case SinkTCP:
+		if cfg.Address == "" {
+			return nil, fmt.Errorf("tcp log sink requires an address")
+		}
+		dialer := &net.Dialer{Timeout: sinkWriteTimeout}
+		w.dial = func() (net.Conn, error) { return dialer.Dial("tcp", cfg.Address) }
+		if cfg.TLS {
+			w.dial = func() (net.Conn, error) { return tls.DialWithDialer(dialer, "tcp", cfg.Address, nil) }
+		}
+		w.frame = func(_ slog.Level, msg []byte) []byte { return msg } // JSON lines
+		h := &sinkHandler{w: w}
+		h.inner = slog.NewJSONHandler(w, &slog.HandlerOptions{Level: cfg.Level})
+		return NewAsyncHandler(h, DefaultQueueSize), nil
+	default:
+		return nil, fmt.Errorf("unknown log sink type %q", cfg.Type)
+	}
+
+	h := &sinkHandler{w: w}
+	h.inner = slog.NewTextHandler(w, opts)
+	return NewAsyncHandler(h, DefaultQueueSize), nil
+}
+
+// sinkHandler formats records with inner, which writes them to w
+// w needs the level of the record being written, so records are written one at a time.
+type sinkHandler struct {
+	inner slog.Handler
+	w     *sinkWriter
+}
+
+// Enabled reports whether the inner handler handles records at level
+func (h *sinkHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle writes the record to the sink
+func (h *sinkHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.w.mu.Lock()
+	defer h.w.mu.Unlock()
+	h.w.level = r.Level
+	return h.inner.Handle(ctx, r)
+}
+
+// WithAttrs returns a sink handler with the attributes added, sharing the connection
+func (h *sinkHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &sinkHandler{inner: h.inner.WithAttrs(attrs), w: h.w}
+}
+
+// WithGroup returns a sink handler with the group added, sharing the connection
+func (h *sinkHandler) WithGroup(name string) slog.Handler {
+	return &sinkHandler{inner: h.inner.WithGroup(name), w: h.w}
+}
+
+// sinkWriter frames formatted records and writes them to the sink's connection
+type sinkWriter struct {
+	dial  func() (net.Conn, error)
+	frame func(level slog.Level, msg []byte) []byte
+	tag   string
+	pid   int
+
+	mu       sync.Mutex // Held by sinkHandler.Handle for the whole record
+	level    slog.Level // Of the record being written
+	conn     net.Conn
+	redialAt time.Time
+}
+
+// Write sends one formatted record
+func (w *sinkWriter) Write(p []byte) (int, error) {
+	if w.conn == nil {
+		if time.Now().Before(w.redialAt) {
+			return 0, fmt.Errorf("log sink unavailable")
+		}
+		conn, err := w.dial()
+		if err != nil {
+			w.redialAt = time.Now().Add(sinkRedialDelay)
+			return 0, err
+		}
+		w.conn = conn
+	}
+	_ = w.conn.SetWriteDeadline(time.Now().Add(sinkWriteTimeout))
+	if _, err := w.conn.Write(w.frame(w.level, p)); err != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// syslog sets up w for the syslog server at address, or the local daemon if it is empty
+// Messages follow the formats of the standard library's log/syslog.
+func (w *sinkWriter) syslog(address string) error {
+	if address == "" {
+		w.dial = func() (net.Conn, error) {
+			var err error
+			for _, path := range localSyslogSockets {
+				for _, network := range []string{"unixgram", "unix"} {
+					var conn net.Conn
+					if conn, err = net.Dial(network, path); err == nil {
+						return conn, nil
+					}
+				}
+			}
+			return nil, fmt.Errorf("no local syslog daemon: %w", err)
+		}
+		w.frame = func(level slog.Level, msg []byte) []byte {
+			return fmt.Appendf(nil, "<%d>%s %s[%d]: %s", syslogPriority(level),
+				time.Now().Format(time.Stamp), w.tag, w.pid, msg)
+		}
+		return nil
+	}
+
+	u, err := url.Parse(address)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("syslog address must be udp://, tcp:// or tls://host:port, got %q", address)
+	}
+	dialer := &net.Dialer{Timeout: sinkWriteTimeout}
+	switch u.Scheme {
+	case "udp", "tcp":
+		w.dial = func() (net.Conn, error) { return dialer.Dial(u.Scheme, u.Host) }
+	case "tls":
+		w.dial = func() (net.Conn, error) { return tls.DialWithDialer(dialer, "tcp", u.Host, nil) }
+	default:
+		return fmt.Errorf("syslog address must be udp://, tcp:// or tls://host:port, got %q", address)
+	}
+	hostname, _ := os.Hostname()
+	w.frame = func(level slog.Level, msg []byte) []byte {
+		return fmt.Appendf(nil, "<%d>%s %s %s[%d]: %s", syslogPriority(level),
+			time.Now().Format(time.RFC3339), hostname, w.tag, w.pid, msg)
+	}
+	return nil
+}
+
+// journalFrame builds a native journal protocol datagram
+// Text records have no raw newlines (values are quoted), so the simple KEY=value form applies.
+func (w *sinkWriter) journalFrame(level slog.Level, msg []byte) []byte {
+	return fmt.Appendf(nil, "PRIORITY=%d\nSYSLOG_IDENTIFIER=%s\nSYSLOG_PID=%d\nMESSAGE=%s\n",
+		syslogSeverity(level), w.tag, w.pid, strings.TrimSuffix(string(msg), "\n"))
+}
+
+// syslogSeverity maps a level to a syslog severity
+func syslogSeverity(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 3 // err
+	case level >= slog.LevelWarn:
+		return 4 // warning
+	case level >= slog.LevelInfo:
+		return 6 // info
+	default:
+		return 7 // debug
+	}
+}
+
+// syslogPriority is the priority of a level in the daemon facility
+func syslogPriority(level slog.Level) int {
+	return 3<<3 | syslogSeverity(level)
+}
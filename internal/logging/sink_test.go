@@ -0,0 +1,176 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMultiHandler_PerHandlerLevels(t *testing.T) {
+	var info, debug lockedBuffer
+	logger := slog.New(NewMultiHandler(
+		slog.NewTextHandler(&info, &slog.HandlerOptions{Level: slog.LevelInfo}),
+		slog.NewTextHandler(&debug, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)).With("component", "test")
+
+	logger.Debug("detail")
+	logger.Info("started")
+
+	if got := info.String(); strings.Contains(got, "detail") || !strings.Contains(got, "msg=started component=test") {
+		t.Errorf("info handler output = %q, want only the info record", got)
+	}
+	if got := debug.String(); !strings.Contains(got, "msg=detail component=test") || !strings.Contains(got, "msg=started") {
+		t.Errorf("debug handler output = %q, want both records", got)
+	}
+}
+
+func TestSink_TCPWritesJSONLines(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer ln.Close()
+
+	h, err := NewSink(SinkConfig{Type: SinkTCP, Address: ln.Addr().String(), Level: slog.LevelWarn})
+	if err != nil {
+		t.Fatalf("NewSink failed: %v", err)
+	}
+	logger := slog.New(h).With("component", "test")
+	logger.Info("not shipped")
+	logger.Warn("shipped", "i", 1)
+	logger.Error("shipped", "i", 2)
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	scanner := bufio.NewScanner(conn)
+	for i, wantLevel := range []string{"WARN", "ERROR"} {
+		if !scanner.Scan() {
+			t.Fatalf("line %d missing: %v", i, scanner.Err())
+		}
+		var rec map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("line %d is not JSON: %q", i, scanner.Text())
+		}
+		if rec["level"] != wantLevel || rec["msg"] != "shipped" || rec["component"] != "test" || rec["i"] != float64(i+1) {
+			t.Errorf("line %d = %v", i, rec)
+		}
+	}
+}
+
+func TestSink_SyslogUDPFraming(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket failed: %v", err)
+	}
+	defer conn.Close()
+
+	h, err := NewSink(SinkConfig{Type: SinkSyslog, Address: "udp://" + conn.LocalAddr().String(), Tag: "mm-test"})
+	if err != nil {
+		t.Fatalf("NewSink failed: %v", err)
+	}
+	slog.New(h).Warn("gateway slow", "latency", "2s")
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	buf := make([]byte, 2048)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	got := string(buf[:n])
+	// daemon facility (3) * 8 + warning (4)
+	if !strings.HasPrefix(got, "<28>") || !strings.Contains(got, " mm-test[") ||
+		!strings.Contains(got, `level=WARN msg="gateway slow" latency=2s`) || strings.Contains(got, "time=") {
+		t.Errorf("datagram = %q", got)
+	}
+}
+
+func TestSink_JournaldFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets unavailable: %v", err)
+	}
+	defer conn.Close()
+
+	h, err := NewSink(SinkConfig{Type: SinkJournald, Address: path, Tag: "mm-test"})
+	if err != nil {
+		t.Fatalf("NewSink failed: %v", err)
+	}
+	slog.New(h).Error("signer failed", "key", "primary")
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	buf := make([]byte, 2048)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	fields := strings.Split(strings.TrimSuffix(string(buf[:n]), "\n"), "\n")
+	want := []string{"PRIORITY=3", "SYSLOG_IDENTIFIER=mm-test", `MESSAGE=level=ERROR msg="signer failed" key=primary`}
+	for _, field := range want {
+		found := false
+		for _, f := range fields {
+			found = found || f == field
+		}
+		if !found {
+			t.Errorf("datagram %q lacks %s", fields, field)
+		}
+	}
+}
+
+func TestSink_UnreachableDropsRecords(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	h, err := NewSink(SinkConfig{Type: SinkTCP, Address: addr})
+	if err != nil {
+		t.Fatalf("NewSink failed: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			slog.New(h).Info("record", "i", i)
+		}
+		_ = h.Close()
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("logging to an unreachable sink blocked")
+	}
+}
+
+func TestNewSink_InvalidConfig(t *testing.T) {
+	for _, cfg := range []SinkConfig{
+		{Type: "loki"},
+		{Type: SinkTCP},
+		{Type: SinkSyslog, Address: "host:514"},
+		{Type: SinkSyslog, Address: "http://host:514"},
+	} {
+		if _, err := NewSink(cfg); err == nil {
+			t.Errorf("NewSink(%+v) succeeded, want an error", cfg)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+)
+
+// TraceKey is the log attribute carrying the correlation ID
+const TraceKey = "traceId"
+
+// traceIDKey is the context key of the correlation ID
+type traceIDKey struct{}
+
+// NewTraceID returns a random 16-digit hex correlation ID
+func NewTraceID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// WithTraceID returns a context carrying the correlation ID id
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+// TraceID returns the correlation ID of ctx, empty if it has none
+func TraceID(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+// TraceHandler is an slog.Handler that adds the correlation ID of the record's context
+// Only records logged with a context (InfoContext etc.) carry one. Wrap it around an AsyncHandler,
+// not inside it: the context is not queued with the record.
+type TraceHandler struct {
+	inner slog.Handler
+}
+
+// NewTraceHandler creates a trace handler writing to inner
+func NewTraceHandler(inner slog.Handler) *TraceHandler {
+	return &TraceHandler{inner: inner}
+}
+
+// Enabled reports whether the inner handler handles records at level
+func (h *TraceHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle adds the correlation ID of ctx, if any, and passes the record on
+func (h *TraceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := TraceID(ctx); id != "" {
+		r = r.Clone()
+		r.AddAttrs(slog.String(TraceKey, id))
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+// WithAttrs returns a trace handler with the attributes added
+func (h *TraceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &TraceHandler{inner: h.inner.WithAttrs(attrs)}
+}
+
+// WithGroup returns a trace handler with the group added
+func (h *TraceHandler) WithGroup(name string) slog.Handler {
+	return &TraceHandler{inner: h.inner.WithGroup(name)}
+}
@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestTraceHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewTraceHandler(slog.NewTextHandler(&buf, nil))).With("component", "Test")
+
+	ctx := WithTraceID(context.Background(), "0123456789abcdef")
+	logger.InfoContext(ctx, "traced")
+	logger.Info("untraced")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if !strings.Contains(lines[0], "traceId=0123456789abcdef") {
+		t.Errorf("traced line = %q, want traceId", lines[0])
+	}
+	if strings.Contains(lines[1], "traceId") {
+		t.Errorf("untraced line = %q, want no traceId", lines[1])
+	}
+}
+
+func TestNewTraceID(t *testing.T) {
+	a, b := NewTraceID(), NewTraceID()
+	if len(a) != 16 {
+		t.Errorf("len(NewTraceID()) = %v, want 16", len(a))
+	}
+	if a == b {
+		t.Errorf("NewTraceID() returned %v twice", a)
+	}
+	if got := TraceID(context.Background()); got != "" {
+		t.Errorf("TraceID() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,167 @@
+// Package metrics exposes MM metrics in the Prometheus text exposition format
+// The format is written directly, so there is no client library dependency. Components keep
+// their own counters; collectors read them at scrape time.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Collector writes a component's metrics at scrape time
+type Collector interface {
+	Collect(w *Writer)
+}
+
+// CollectorFunc is a function Collector
+type CollectorFunc func(w *Writer)
+
+// Collect calls f
+func (f CollectorFunc) Collect(w *Writer) { f(w) }
+
+// Registry is the set of collectors served on /metrics
+type Registry struct {
+	mu         sync.RWMutex
+	collectors []Collector
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a collector
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteTo writes the metrics of all collectors to out
+func (r *Registry) WriteTo(out io.Writer) (int64, error) {
+	cw := &countingWriter{w: out}
+	w := &Writer{w: bufio.NewWriter(cw), headers: make(map[string]bool)}
+	r.Collect(w)
+	err := w.w.Flush()
+	return cw.n, err
+}
+
+// Collect writes the metrics of all collectors, so a registry can be nested in another
+func (r *Registry) Collect(w *Writer) {
+	r.mu.RLock()
+	collectors := r.collectors
+	r.mu.RUnlock()
+	for _, c := range collectors {
+		c.Collect(w)
+	}
+}
+
+// WithLabels returns a collector adding the labels (name/value pairs) to every sample of c
+// Used to tell apart the same metrics of several instances
+func WithLabels(c Collector, labels ...string) Collector {
+	return CollectorFunc(func(w *Writer) {
+		outer := w.labels
+		w.labels = append(outer[:len(outer):len(outer)], labels...)
+		c.Collect(w)
+		w.labels = outer
+	})
+}
+
+// ServeHTTP serves the metrics of all collectors
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = r.WriteTo(w)
+}
+
+// Writer writes metric families in the text exposition format
+type Writer struct {
+	w       *bufio.Writer
+	headers map[string]bool
+	labels  []string // Added to every sample, see WithLabels
+}
+
+// Header writes the HELP and TYPE lines of a family, once per scrape
+// typ is counter, gauge or histogram
+func (w *Writer) Header(name, typ, help string) {
+	if w.headers[name] {
+		return
+	}
+	w.headers[name] = true
+	fmt.Fprintf(w.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// Sample writes one sample; labels are name/value pairs
+func (w *Writer) Sample(name string, value float64, labels ...string) {
+	if len(w.labels) > 0 {
+		labels = append(w.labels[:len(w.labels):len(w.labels)], labels...)
+	}
+	w.w.WriteString(name)
+	if len(labels) > 0 {
+		w.w.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.w.WriteByte(',')
+			}
+			fmt.Fprintf(w.w, "%s=\"%s\"", labels[i], escapeLabel(labels[i+1]))
+		}
+		w.w.WriteByte('}')
+	}
+	w.w.WriteByte(' ')
+	w.w.WriteString(formatValue(value))
+	w.w.WriteByte('\n')
+}
+
+// Histogram writes the samples of one histogram
+// counts holds the observations per bucket (not cumulative), one more than bounds for the +Inf bucket
+func (w *Writer) Histogram(name string, bounds []float64, counts []uint64, sum float64, labels ...string) {
+	var cumulative uint64
+	for i, count := range counts {
+		cumulative += count
+		le := math.Inf(1)
+		if i < len(bounds) {
+			le = bounds[i]
+		}
+		w.Sample(name+"_bucket", float64(cumulative), append(labels[:len(labels):len(labels)], "le", formatValue(le))...)
+	}
+	w.Sample(name+"_sum", sum, labels...)
+	w.Sample(name+"_count", float64(cumulative), labels...)
+}
+
+// formatValue formats a sample value
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// labelEscaper escapes label values
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a label value
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
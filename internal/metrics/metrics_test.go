@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(CollectorFunc(func(w *Writer) {
+		w.Header("mm_test_total", "counter", "Test counter")
+		w.Sample("mm_test_total", 3, "type", `a"b`)
+		w.Header("mm_test_total", "counter", "Test counter") // Written once
+		w.Sample("mm_test_total", 1.5, "type", "c")
+		w.Header("mm_test_bytes", "histogram", "Test histogram")
+		w.Histogram("mm_test_bytes", []float64{10, 100}, []uint64{1, 2, 3}, 1234, "dir", "in")
+	}))
+
+	var buf bytes.Buffer
+	if _, err := registry.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	want := `# HELP mm_test_total Test counter
+# TYPE mm_test_total counter
+mm_test_total{type="a\"b"} 3
+mm_test_total{type="c"} 1.5
+# HELP mm_test_bytes Test histogram
+# TYPE mm_test_bytes histogram
+mm_test_bytes_bucket{dir="in",le="10"} 1
+mm_test_bytes_bucket{dir="in",le="100"} 3
+mm_test_bytes_bucket{dir="in",le="+Inf"} 6
+mm_test_bytes_sum{dir="in"} 1234
+mm_test_bytes_count{dir="in"} 6
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteTo() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(CollectorFunc(func(w *Writer) { w.Sample("mm_up", 1) }))
+
+	rec := httptest.NewRecorder()
+	registry.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %v, want text/plain; version=0.0.4", ct)
+	}
+	if got := rec.Body.String(); got != "mm_up 1\n" {
+		t.Errorf("body = %q, want %q", got, "mm_up 1\n")
+	}
+}
+
+func TestWithLabels(t *testing.T) {
+	instance := func(up float64) *Registry {
+		registry := NewRegistry()
+		registry.Register(CollectorFunc(func(w *Writer) {
+			w.Header("mm_up", "gauge", "Up")
+			w.Sample("mm_up", up, "conn", "primary")
+		}))
+		return registry
+	}
+	registry := NewRegistry()
+	registry.Register(WithLabels(instance(1), "instance", "a"))
+	registry.Register(WithLabels(instance(0), "instance", "b"))
+	registry.Register(CollectorFunc(func(w *Writer) { w.Sample("mm_shared", 2) }))
+
+	var buf bytes.Buffer
+	if _, err := registry.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	want := `# HELP mm_up Up
+# TYPE mm_up gauge
+mm_up{instance="a",conn="primary"} 1
+mm_up{instance="b",conn="primary"} 0
+mm_shared 2
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteTo() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,238 @@
+package nonce
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/store"
+)
+
+// Modes of the nonces signed into quotes
+const (
+	ModeRequest = "request" // Sign the request's nonce unchecked; replay protection is left to the server
+	ModeGuard   = "guard"   // Sign the request's nonce only if it is above every nonce signed before
+	ModeIssue   = "issue"   // Sign a nonce issued by the Manager; the request's nonce is ignored
+)
+
+// Scopes of a nonce sequence
+const (
+	ScopeChain = "chain" // One sequence per chain
+	ScopePool  = "pool"  // One sequence per RFQ manager contract
+)
+
+// Dispositions of a signed nonce
+const (
+	Filled  = "filled"  // The quote settled on-chain
+	Expired = "expired" // The deadline passed without a known fill
+	Failed  = "failed"  // The server reported an error for the quote
+)
+
+// ErrReused is returned when a nonce is not above the high-water mark of its sequence
+var ErrReused = errors.New("nonce would be reused")
+
+// maxUint256 is the largest nonce
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// Key identifies a nonce sequence
+type Key struct {
+	ChainID uint64
+	Pool    common.Address // RFQ manager contract under ScopePool, zero under ScopeChain
+}
+
+// String returns the key as "<chainId>" or "<chainId>/<pool>"
+func (k Key) String() string {
+	if k.Pool == (common.Address{}) {
+		return fmt.Sprint(k.ChainID)
+	}
+	return fmt.Sprintf("%d/%s", k.ChainID, strings.ToLower(k.Pool.Hex()))
+}
+
+// Stats are the counters of a nonce sequence
+type Stats struct {
+	Key         string `json:"key"`
+	Mark        string `json:"mark"`        // Highest nonce signed, the high-water mark
+	Outstanding int    `json:"outstanding"` // Nonces of sent quotes without a disposition yet
+	Filled      uint64 `json:"filled"`
+	Expired     uint64 `json:"expired"`
+	Failed      uint64 `json:"failed"`
+}
+
+// sequence is the state of one nonce sequence
+type sequence struct {
+	mark        *big.Int
+	outstanding map[string]struct{}
+	filled      uint64
+	expired     uint64
+	failed      uint64
+}
+
+// Manager issues and checks the nonces of quotes, one increasing sequence per chain or pool
+// The high-water mark of every sequence is persisted in the state store before a nonce is
+// returned or accepted, so a restart never signs a nonce twice. Issued sequences also start
+// above the start time in nanoseconds, so they keep increasing when the store is lost.
+type Manager struct {
+	mode  string
+	scope string
+	state store.Store // nil = not persisted
+	now   func() time.Time
+
+	mu        sync.Mutex
+	sequences map[Key]*sequence
+}
+
+// NewManager creates a nonce manager; state persists the high-water marks, nil = not persisted
+func NewManager(mode, scope string, state store.Store) (*Manager, error) {
+	switch mode {
+	case ModeRequest, ModeGuard, ModeIssue:
+	default:
+		return nil, fmt.Errorf("unknown nonce mode %q", mode)
+	}
+	switch scope {
+	case ScopeChain, ScopePool:
+	default:
+		return nil, fmt.Errorf("unknown nonce scope %q", scope)
+	}
+	return &Manager{
+		mode:      mode,
+		scope:     scope,
+		state:     state,
+		now:       time.Now,
+		sequences: make(map[Key]*sequence),
+	}, nil
+}
+
+// Mode returns the nonce mode
+func (m *Manager) Mode() string {
+	return m.mode
+}
+
+// Key returns the key of the sequence of a quote on chainID for the RFQ manager pool
+func (m *Manager) Key(chainID uint64, pool common.Address) Key {
+	if m.scope == ScopeChain {
+		return Key{ChainID: chainID}
+	}
+	return Key{ChainID: chainID, Pool: pool}
+}
+
+// Sign returns the nonce to sign for a quote whose request carries requested
+// Under ModeIssue it is the next nonce of the sequence; under ModeGuard it is requested, which
+// must be above the high-water mark (ErrReused otherwise); under ModeRequest it is requested.
+func (m *Manager) Sign(key Key, requested *big.Int) (*big.Int, error) {
+	if m.mode == ModeRequest {
+		return requested, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seq, err := m.sequenceLocked(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce *big.Int
+	if m.mode == ModeIssue {
+		nonce = new(big.Int).Add(seq.mark, big.NewInt(1))
+		if nonce.Cmp(maxUint256) > 0 {
+			return nil, fmt.Errorf("nonces of %s are exhausted", key)
+		}
+	} else {
+		if requested.Cmp(seq.mark) <= 0 {
+			return nil, fmt.Errorf("%w: %s is not above %s, the highest nonce signed on %s", ErrReused, requested, seq.mark, key)
+		}
+		nonce = new(big.Int).Set(requested)
+	}
+	if m.state != nil {
+		if err := m.state.Put(markKey(key), nonce.Bytes()); err != nil {
+			return nil, fmt.Errorf("failed to persist the nonce mark of %s: %w", key, err)
+		}
+	}
+	seq.mark = nonce
+	return new(big.Int).Set(nonce), nil
+}
+
+// Sent records that the quote signed with nonce was sent; it is outstanding until disposed
+func (m *Manager) Sent(key Key, nonce string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if seq, ok := m.sequences[key]; ok {
+		seq.outstanding[nonce] = struct{}{}
+	}
+}
+
+// Dispose records how the quote signed with nonce ended: Filled, Expired or Failed
+// Nonces not recorded by Sent, and repeated dispositions, are ignored.
+func (m *Manager) Dispose(key Key, nonce, disposition string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seq, ok := m.sequences[key]
+	if !ok {
+		return
+	}
+	if _, ok := seq.outstanding[nonce]; !ok {
+		return
+	}
+	delete(seq.outstanding, nonce)
+	switch disposition {
+	case Filled:
+		seq.filled++
+	case Expired:
+		seq.expired++
+	case Failed:
+		seq.failed++
+	}
+}
+
+// Stats returns the counters of every sequence used since the start, by key
+func (m *Manager) Stats() []Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Stats, 0, len(m.sequences))
+	for key, seq := range m.sequences {
+		out = append(out, Stats{
+			Key:         key.String(),
+			Mark:        seq.mark.String(),
+			Outstanding: len(seq.outstanding),
+			Filled:      seq.filled,
+			Expired:     seq.expired,
+			Failed:      seq.failed,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// sequenceLocked returns the sequence of key, loading its mark on first use; m.mu must be held
+func (m *Manager) sequenceLocked(key Key) (*sequence, error) {
+	if seq, ok := m.sequences[key]; ok {
+		return seq, nil
+	}
+	mark := new(big.Int)
+	if m.mode == ModeIssue {
+		mark.SetInt64(m.now().UnixNano())
+	}
+	if m.state != nil {
+		stored, ok, err := m.state.Get(markKey(key))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the nonce mark of %s: %w", key, err)
+		}
+		if ok {
+			if v := new(big.Int).SetBytes(stored); v.Cmp(mark) > 0 {
+				mark = v
+			}
+		}
+	}
+	seq := &sequence{mark: mark, outstanding: make(map[string]struct{})}
+	m.sequences[key] = seq
+	return seq, nil
+}
+
+// markKey is the state store key of the high-water mark of a sequence
+func markKey(key Key) string {
+	return "nonce/mark/" + key.String()
+}
@@ -0,0 +1,109 @@
+package nonce
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/store"
+)
+
+var testPool = common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+func TestManager_Guard(t *testing.T) {
+	state := store.NewMemory()
+	m, err := NewManager(ModeGuard, ScopeChain, state)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	key := m.Key(56, testPool)
+	if key != (Key{ChainID: 56}) {
+		t.Errorf("Key() = %v, want the chain key under ScopeChain", key)
+	}
+
+	if got, err := m.Sign(key, big.NewInt(5)); err != nil || got.Int64() != 5 {
+		t.Fatalf("Sign(5) = %v, %v, want 5", got, err)
+	}
+	for _, n := range []int64{5, 3} {
+		if _, err := m.Sign(key, big.NewInt(n)); !errors.Is(err, ErrReused) {
+			t.Errorf("Sign(%d) error = %v, want ErrReused", n, err)
+		}
+	}
+	if _, err := m.Sign(m.Key(97, testPool), big.NewInt(1)); err != nil {
+		t.Errorf("Sign on another chain failed: %v", err)
+	}
+
+	// A restart keeps refusing nonces signed before it
+	restarted, _ := NewManager(ModeGuard, ScopeChain, state)
+	if _, err := restarted.Sign(key, big.NewInt(5)); !errors.Is(err, ErrReused) {
+		t.Errorf("Sign(5) after restart error = %v, want ErrReused", err)
+	}
+	if _, err := restarted.Sign(key, big.NewInt(6)); err != nil {
+		t.Errorf("Sign(6) after restart failed: %v", err)
+	}
+}
+
+func TestManager_Issue(t *testing.T) {
+	state := store.NewMemory()
+	start := time.Unix(1_700_000_000, 0)
+	m, _ := NewManager(ModeIssue, ScopePool, state)
+	m.now = func() time.Time { return start }
+	key := m.Key(56, testPool)
+	if key.String() != "56/0x1111111111111111111111111111111111111111" {
+		t.Errorf("Key().String() = %q", key.String())
+	}
+
+	first, err := m.Sign(key, big.NewInt(1))
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if first.Int64() != start.UnixNano()+1 {
+		t.Errorf("first nonce = %v, want above the start time in nanoseconds", first)
+	}
+	second, _ := m.Sign(key, big.NewInt(1))
+	if second.Cmp(first) <= 0 {
+		t.Errorf("second nonce %v is not above %v", second, first)
+	}
+
+	// A restart with the clock set back continues above the stored mark
+	restarted, _ := NewManager(ModeIssue, ScopePool, state)
+	restarted.now = func() time.Time { return start.Add(-time.Hour) }
+	if third, _ := restarted.Sign(key, big.NewInt(1)); third.Cmp(second) <= 0 {
+		t.Errorf("nonce after restart %v is not above %v", third, second)
+	}
+}
+
+func TestManager_Dispose(t *testing.T) {
+	m, _ := NewManager(ModeGuard, ScopeChain, nil)
+	key := m.Key(56, testPool)
+	for n := int64(1); n <= 4; n++ {
+		signed, err := m.Sign(key, big.NewInt(n))
+		if err != nil {
+			t.Fatalf("Sign(%d) failed: %v", n, err)
+		}
+		m.Sent(key, signed.String())
+	}
+	m.Dispose(key, "1", Filled)
+	m.Dispose(key, "1", Expired) // Repeated
+	m.Dispose(key, "2", Expired)
+	m.Dispose(key, "3", Failed)
+	m.Dispose(key, "9", Filled) // Never sent
+
+	stats := m.Stats()
+	want := Stats{Key: "56", Mark: "4", Outstanding: 1, Filled: 1, Expired: 1, Failed: 1}
+	if len(stats) != 1 || stats[0] != want {
+		t.Errorf("Stats() = %+v, want [%+v]", stats, want)
+	}
+}
+
+func TestNewManager_Invalid(t *testing.T) {
+	if _, err := NewManager("random", ScopeChain, nil); err == nil {
+		t.Error("NewManager accepted an unknown mode")
+	}
+	if _, err := NewManager(ModeGuard, "global", nil); err == nil {
+		t.Error("NewManager accepted an unknown scope")
+	}
+}
@@ -0,0 +1,155 @@
+package profiling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+)
+
+// Capture reasons, part of the profile file names
+const (
+	ReasonLatency = "latency" // Quote p99 latency crossed its threshold
+	ReasonQueue   = "queue"   // Send queue depth crossed its threshold
+)
+
+// timestampFormat names profile files so they sort by capture time
+const timestampFormat = "20060102T150405.000Z"
+
+// Sources are the metrics the watchdog checks; a nil source is not checked
+type Sources struct {
+	LatencyP99     func() time.Duration // Recent quote p99 latency
+	SendQueueDepth func() int           // Outbound messages queued but not yet written
+}
+
+// Watchdog captures CPU, heap and goroutine profiles when a metric crosses its threshold
+// Profiles are written to config.ProfilingConfig.Dir for post-incident analysis with go tool pprof.
+type Watchdog struct {
+	cfg     config.ProfilingConfig
+	sources Sources
+	logger  *slog.Logger
+
+	lastCapture time.Time
+}
+
+// NewWatchdog creates a profiling watchdog
+func NewWatchdog(cfg config.ProfilingConfig, sources Sources, logger *slog.Logger) *Watchdog {
+	return &Watchdog{
+		cfg:     cfg,
+		sources: sources,
+		logger:  logger.With("component", "ProfilingWatchdog"),
+	}
+}
+
+// Run checks the thresholds every CheckInterval until ctx is done
+// At most one capture is taken per Cooldown
+func (w *Watchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !w.lastCapture.IsZero() && time.Since(w.lastCapture) < w.cfg.Cooldown {
+				continue
+			}
+			reason, ok := w.check()
+			if !ok {
+				continue
+			}
+			w.lastCapture = time.Now()
+			files, err := w.Capture(ctx, reason)
+			if err != nil {
+				w.logger.Error("Failed to capture profiles", "reason", reason, "error", err)
+			}
+			if len(files) > 0 {
+				w.logger.Warn("Captured profiles", "reason", reason, "files", files)
+			}
+		}
+	}
+}
+
+// check returns the reason to capture, false if every metric is within its threshold
+func (w *Watchdog) check() (string, bool) {
+	if w.cfg.LatencyP99 > 0 && w.sources.LatencyP99 != nil {
+		if p99 := w.sources.LatencyP99(); p99 >= w.cfg.LatencyP99 {
+			w.logger.Warn("Quote latency above threshold", "p99", p99, "threshold", w.cfg.LatencyP99)
+			return ReasonLatency, true
+		}
+	}
+	if w.cfg.SendQueueDepth > 0 && w.sources.SendQueueDepth != nil {
+		if depth := w.sources.SendQueueDepth(); depth >= w.cfg.SendQueueDepth {
+			w.logger.Warn("Send queue depth above threshold", "depth", depth, "threshold", w.cfg.SendQueueDepth)
+			return ReasonQueue, true
+		}
+	}
+	return "", false
+}
+
+// Capture writes goroutine, heap and CPU profiles named <timestamp>-<reason>-<kind>.pprof
+// Goroutines and heap are taken first so they show the state at the spike; the CPU profile
+// then covers the next CPUDuration, or less if ctx is done. Returns the files written,
+// which may be some of them on error.
+func (w *Watchdog) Capture(ctx context.Context, reason string) ([]string, error) {
+	if err := os.MkdirAll(w.cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	prefix := filepath.Join(w.cfg.Dir, time.Now().UTC().Format(timestampFormat)+"-"+reason)
+
+	var files []string
+	var errs []error
+	for _, kind := range []string{"goroutine", "heap"} {
+		path := prefix + "-" + kind + ".pprof"
+		if err := writeFile(path, func(f *os.File) error {
+			return pprof.Lookup(kind).WriteTo(f, 0)
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write %s profile: %w", kind, err))
+			continue
+		}
+		files = append(files, path)
+	}
+
+	path := prefix + "-cpu.pprof"
+	if err := writeFile(path, func(f *os.File) error {
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return err
+		}
+		timer := time.NewTimer(w.cfg.CPUDuration)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		pprof.StopCPUProfile()
+		return nil
+	}); err != nil {
+		errs = append(errs, fmt.Errorf("failed to write cpu profile: %w", err))
+	} else {
+		files = append(files, path)
+	}
+	return files, errors.Join(errs...)
+}
+
+// writeFile creates path and fills it with write, removing it on failure
+func writeFile(path string, write func(f *os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return err
+}
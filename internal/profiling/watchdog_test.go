@@ -0,0 +1,92 @@
+package profiling
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+)
+
+func testConfig(dir string) config.ProfilingConfig {
+	return config.ProfilingConfig{
+		Enabled:        true,
+		Dir:            dir,
+		CheckInterval:  5 * time.Millisecond,
+		LatencyP99:     100 * time.Millisecond,
+		SendQueueDepth: 512,
+		CPUDuration:    10 * time.Millisecond,
+		Cooldown:       time.Hour,
+	}
+}
+
+func TestWatchdog_Check(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	tests := []struct {
+		name    string
+		sources Sources
+		want    string
+	}{
+		{"below thresholds", Sources{
+			LatencyP99:     func() time.Duration { return 50 * time.Millisecond },
+			SendQueueDepth: func() int { return 10 },
+		}, ""},
+		{"latency", Sources{
+			LatencyP99:     func() time.Duration { return 150 * time.Millisecond },
+			SendQueueDepth: func() int { return 10 },
+		}, ReasonLatency},
+		{"queue", Sources{
+			LatencyP99:     func() time.Duration { return 50 * time.Millisecond },
+			SendQueueDepth: func() int { return 600 },
+		}, ReasonQueue},
+		{"no sources", Sources{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWatchdog(testConfig(t.TempDir()), tt.sources, logger)
+			reason, ok := w.check()
+			if reason != tt.want || ok != (tt.want != "") {
+				t.Errorf("check() = %q, %v, want %q", reason, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchdog_RunCapturesOncePerCooldown(t *testing.T) {
+	dir := t.TempDir()
+	sources := Sources{LatencyP99: func() time.Duration { return time.Second }}
+	w := NewWatchdog(testConfig(dir), sources, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	w.Run(ctx)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if len(names) != 3 {
+		t.Fatalf("files = %v, want one goroutine, heap and cpu profile", names)
+	}
+	for _, kind := range []string{"goroutine", "heap", "cpu"} {
+		found := false
+		for _, name := range names {
+			if strings.HasSuffix(name, "-"+ReasonLatency+"-"+kind+".pprof") {
+				info, err := os.Stat(filepath.Join(dir, name))
+				found = err == nil && info.Size() > 0
+			}
+		}
+		if !found {
+			t.Errorf("no non-empty %s profile in %v", kind, names)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/address"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+)
+
+// recentRejects is the number of rejected quote requests the API keeps
+const recentRejects = 256
+
+// defaultLimit is the number of quotes or rejects returned without a limit parameter
+const defaultLimit = 50
+
+// Sources are the components the API reads; nil sources are reported as empty
+type Sources struct {
+	Pusher      *depth.Pusher
+	Quotes      *quote.Store
+	Consistency *depth.ConsistencyChecker
+}
+
+// API serves read-only MM state as JSON for dashboards and monitors
+// Every endpoint is a GET; nothing the API serves changes MM state.
+type API struct {
+	cfg     *config.Config
+	sources Sources
+	logger  *slog.Logger
+	mux     *http.ServeMux
+
+	rejectsMu sync.Mutex
+	rejects   []Reject // Ring of the recent rejects
+	next      int      // Slot of the next reject
+}
+
+// NewAPI creates the query API
+func NewAPI(cfg *config.Config, sources Sources, logger *slog.Logger) *API {
+	a := &API{
+		cfg:     cfg,
+		sources: sources,
+		logger:  logger.With("component", "QueryAPI"),
+		mux:     http.NewServeMux(),
+	}
+	a.mux.HandleFunc("/api/v1/mids", a.get(a.serveMids))
+	a.mux.HandleFunc("/api/v1/depth", a.get(a.serveDepth))
+	a.mux.HandleFunc("/api/v1/quotes", a.get(a.serveQuotes))
+	a.mux.HandleFunc("/api/v1/rejects", a.get(a.serveRejects))
+	a.mux.HandleFunc("/api/v1/risk", a.get(a.serveRisk))
+	return a
+}
+
+// ServeHTTP implements http.Handler
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mux.ServeHTTP(w, r)
+}
+
+// Mid is the mid price of a pair's last published book
+type Mid struct {
+	ChainID     uint64    `json:"chainId"`
+	PairID      string    `json:"pairId"`
+	Mid         string    `json:"mid"` // wei/wei
+	PublishedAt time.Time `json:"publishedAt"`
+}
+
+// Level is a price level of a published book
+type Level struct {
+	Price  string `json:"price"`  // wei/wei
+	Amount string `json:"amount"` // Base token native decimals
+}
+
+// Book is the last published book of a pair
+type Book struct {
+	ChainID     uint64    `json:"chainId"`
+	PairID      string    `json:"pairId"`
+	BaseToken   string    `json:"baseToken"`
+	QuoteToken  string    `json:"quoteToken"`
+	PublishedAt time.Time `json:"publishedAt"`
+	Bids        []Level   `json:"bids"`
+	Asks        []Level   `json:"asks"`
+}
+
+// Quote is a signed quote tracked by the quote store
+type Quote struct {
+	QuoteID     string    `json:"quoteId"`
+	ChainID     uint64    `json:"chainId"`
+	TokenIn     string    `json:"tokenIn"`
+	TokenOut    string    `json:"tokenOut"`
+	AmountIn    string    `json:"amountIn"`
+	AmountOut   string    `json:"amountOut"`
+	Nonce       string    `json:"nonce"`
+	Deadline    int64     `json:"deadline"`
+	State       string    `json:"state"`
+	SignedAt    time.Time `json:"signedAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	Signer      string    `json:"signer"`
+	PriceSource string    `json:"priceSource,omitempty"`
+	FeeBps      uint32    `json:"feeBps"`
+}
+
+// Reject is a rejected quote request
+type Reject struct {
+	QuoteID string    `json:"quoteId"`
+	ChainID uint64    `json:"chainId"`
+	Reason  string    `json:"reason"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// Risk is the risk state of the MM
+type Risk struct {
+	OpenQuotes        int               `json:"openQuotes"`
+	Exposure          map[string]string `json:"exposure"` // "chainId:tokenOut" -> signed output of open quotes (native decimals)
+	BandAlerts        uint64            `json:"bandAlerts"`
+	ConsistencyAlerts uint64            `json:"consistencyAlerts"`
+}
+
+// get wraps a handler serving a JSON value on GET
+func (a *API) get(serve func(r *http.Request) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		v, err := serve(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(v); err != nil {
+			a.logger.Debug("Failed to write response", "path", r.URL.Path, "error", err)
+		}
+	}
+}
+
+// serveMids serves the mid of every pair's last published book
+func (a *API) serveMids(r *http.Request) (any, error) {
+	mids := make([]Mid, 0, len(a.cfg.Pairs))
+	for _, pair := range a.cfg.Pairs {
+		ob, ok := a.publishedBook(pair)
+		if !ok {
+			continue
+		}
+		mid, ok := ob.Mid()
+		if !ok {
+			continue
+		}
+		mids = append(mids, Mid{ChainID: pair.ChainID, PairID: pair.PairID, Mid: mid.String(), PublishedAt: a.publishedAt(pair)})
+	}
+	return mids, nil
+}
+
+// serveDepth serves the last published books, filtered by the chainId and pairId parameters
+func (a *API) serveDepth(r *http.Request) (any, error) {
+	var chainID uint64
+	if v := r.URL.Query().Get("chainId"); v != "" {
+		var err error
+		if chainID, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid chainId %q", v)
+		}
+	}
+	pairID := r.URL.Query().Get("pairId")
+
+	books := make([]Book, 0, len(a.cfg.Pairs))
+	for _, pair := range a.cfg.Pairs {
+		if (chainID != 0 && pair.ChainID != chainID) || (pairID != "" && pair.PairID != pairID) {
+			continue
+		}
+		ob, ok := a.publishedBook(pair)
+		if !ok {
+			continue
+		}
+		books = append(books, Book{
+			ChainID:     pair.ChainID,
+			PairID:      pair.PairID,
+			BaseToken:   strings.ToLower(pair.BaseToken),
+			QuoteToken:  strings.ToLower(pair.QuoteToken),
+			PublishedAt: a.publishedAt(pair),
+			Bids:        levels(ob.Bids),
+			Asks:        levels(ob.Asks),
+		})
+	}
+	return books, nil
+}
+
+// serveQuotes serves the most recently signed quotes, newest first
+func (a *API) serveQuotes(r *http.Request) (any, error) {
+	limit, err := limitParam(r)
+	if err != nil {
+		return nil, err
+	}
+	quotes := []Quote{}
+	if a.sources.Quotes == nil {
+		return quotes, nil
+	}
+	for _, rec := range a.sources.Quotes.Recent(limit) {
+		quotes = append(quotes, Quote{
+			QuoteID:     rec.QuoteID,
+			ChainID:     rec.ChainID,
+			TokenIn:     address.Normalize(rec.TokenIn),
+			TokenOut:    address.Normalize(rec.TokenOut),
+			AmountIn:    bigString(rec.AmountIn),
+			AmountOut:   bigString(rec.AmountOut),
+			Nonce:       rec.Nonce,
+			Deadline:    rec.Deadline,
+			State:       rec.State.String(),
+			SignedAt:    rec.SignedAt,
+			UpdatedAt:   rec.UpdatedAt,
+			Signer:      address.Normalize(rec.Signer),
+			PriceSource: rec.Info.PriceSource,
+			FeeBps:      rec.Info.FeeBps,
+		})
+	}
+	return quotes, nil
+}
+
+// serveRejects serves the most recent rejects, newest first
+func (a *API) serveRejects(r *http.Request) (any, error) {
+	limit, err := limitParam(r)
+	if err != nil {
+		return nil, err
+	}
+	a.rejectsMu.Lock()
+	defer a.rejectsMu.Unlock()
+	rejects := make([]Reject, 0, min(limit, len(a.rejects)))
+	for i := 1; i <= len(a.rejects) && len(rejects) < limit; i++ {
+		rejects = append(rejects, a.rejects[(a.next-i+len(a.rejects))%len(a.rejects)])
+	}
+	return rejects, nil
+}
+
+// serveRisk serves exposure and risk alert counters
+func (a *API) serveRisk(r *http.Request) (any, error) {
+	risk := Risk{Exposure: map[string]string{}}
+	if a.sources.Quotes != nil {
+		risk.OpenQuotes = len(a.sources.Quotes.Open())
+		for key, amount := range a.sources.Quotes.Exposure() {
+			risk.Exposure[key] = amount.String()
+		}
+	}
+	if a.sources.Pusher != nil {
+		risk.BandAlerts = a.sources.Pusher.BandAlerts()
+	}
+	if a.sources.Consistency != nil {
+		risk.ConsistencyAlerts = a.sources.Consistency.Alerts()
+	}
+	return risk, nil
+}
+
+// Follow records the rejects published on bus from now until ctx is done
+func (a *API) Follow(ctx context.Context, bus *events.Bus) {
+	sub := bus.Subscribe("query-api", 0, events.QuoteRejected)
+	go sub.Run(ctx, a.recordReject)
+}
+
+// recordReject keeps a rejected quote request in the ring of recent rejects
+func (a *API) recordReject(e events.Event) {
+	reject := Reject{QuoteID: e.QuoteID, ChainID: e.ChainID, Message: e.Detail, Time: e.Time}
+	if reason, ok := e.Data.(fmt.Stringer); ok {
+		reject.Reason = reason.String()
+	}
+	a.rejectsMu.Lock()
+	defer a.rejectsMu.Unlock()
+	if len(a.rejects) < recentRejects {
+		a.rejects = append(a.rejects, reject)
+		a.next = len(a.rejects) % recentRejects
+		return
+	}
+	a.rejects[a.next] = reject
+	a.next = (a.next + 1) % recentRejects
+}
+
+// publishedBook returns the last published book of a pair
+func (a *API) publishedBook(pair config.PairConfig) (*depth.OrderBook, bool) {
+	if a.sources.Pusher == nil {
+		return nil, false
+	}
+	return a.sources.Pusher.PublishedBook(pair.ChainID, pair.PairID)
+}
+
+// publishedAt returns when the book of a pair was last pushed
+func (a *API) publishedAt(pair config.PairConfig) time.Time {
+	return a.sources.Pusher.LastPushTimes()[fmt.Sprintf("%d:%s", pair.ChainID, pair.PairID)]
+}
+
+// levels converts book levels to their JSON form
+func levels(in []depth.PriceLevel) []Level {
+	out := make([]Level, len(in))
+	for i, level := range in {
+		out[i] = Level{Price: level.Price.String(), Amount: bigString(level.Amount)}
+	}
+	return out
+}
+
+// bigString formats an amount, "0" for nil
+func bigString(v *big.Int) string {
+	if v == nil {
+		return "0"
+	}
+	return v.String()
+}
+
+// limitParam returns the limit parameter, defaultLimit if absent
+func limitParam(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("limit")
+	if v == "" {
+		return defaultLimit, nil
+	}
+	limit, err := strconv.Atoi(v)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid limit %q", v)
+	}
+	return limit, nil
+}
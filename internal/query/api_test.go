@@ -0,0 +1,118 @@
+package query_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/query"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+func getJSON(t *testing.T, h http.Handler, path string, v any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s: invalid JSON: %v", path, err)
+		}
+	}
+	return rec.Code
+}
+
+func TestAPI(t *testing.T) {
+	cfg := testutil.Config()
+	pair := cfg.Pairs[0]
+	cfg.Pairs = cfg.Pairs[:1]
+	cfg.Depth = config.DepthConfig{Enabled: true, PushInterval: 10 * time.Millisecond, MaxConcurrency: 1}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	provider := testutil.NewStaticDepthProvider()
+	provider.SetBook(pair.ChainID, pair.PairID, testutil.LinearBook(common.HexToAddress(pair.BaseToken), common.HexToAddress(pair.QuoteToken), 600, 0.001, 2, big.NewInt(1e18)))
+	client := testutil.NewFakeWSClient()
+	client.SetState(ws.StateReady)
+	s := testutil.NewFakeSigner(common.HexToAddress(testutil.DefaultMMID))
+	handler := quote.NewHandler(testutil.NewFixedRateStrategy(600, 1), s, cfg, logger)
+	pusher := depth.NewPusher(client, provider, handler, s, cfg, logger)
+	if err := pusher.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer pusher.Stop()
+
+	bus := events.NewBus(logger)
+	api := query.NewAPI(cfg, query.Sources{Pusher: pusher, Quotes: handler.Store()}, logger)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	api.Follow(ctx, bus)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, ok := pusher.PublishedBook(pair.ChainID, pair.PairID); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no depth published")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	var mids []query.Mid
+	if code := getJSON(t, api, "/api/v1/mids", &mids); code != http.StatusOK || len(mids) != 1 || mids[0].Mid != "600" {
+		t.Errorf("mids = %d %+v, want the published mid 600", code, mids)
+	}
+	var books []query.Book
+	if code := getJSON(t, api, "/api/v1/depth?pairId="+pair.PairID, &books); code != http.StatusOK || len(books) != 1 || len(books[0].Asks) != 2 {
+		t.Errorf("depth = %d %+v, want one book with 2 asks", code, books)
+	}
+	if code := getJSON(t, api, "/api/v1/depth?pairId=OTHER", &books); code != http.StatusOK || len(books) != 0 {
+		t.Errorf("depth of an unknown pair = %d %+v, want none", code, books)
+	}
+	if code := getJSON(t, api, "/api/v1/depth?chainId=x", &books); code != http.StatusBadRequest {
+		t.Errorf("depth with an invalid chainId = %d, want 400", code)
+	}
+
+	// Quotes and exposure come from the quote store
+	rec := &quote.QuoteRecord{QuoteID: "q1", ChainID: pair.ChainID, TokenOut: common.HexToAddress(pair.QuoteToken), AmountOut: big.NewInt(600), Deadline: time.Now().Add(time.Minute).Unix()}
+	handler.Store().Add(rec)
+	var quotes []query.Quote
+	if code := getJSON(t, api, "/api/v1/quotes?limit=10", &quotes); code != http.StatusOK || len(quotes) != 1 || quotes[0].State != "Pending" || quotes[0].AmountIn != "0" {
+		t.Errorf("quotes = %d %+v, want q1 pending", code, quotes)
+	}
+	var risk query.Risk
+	if code := getJSON(t, api, "/api/v1/risk", &risk); code != http.StatusOK || risk.OpenQuotes != 1 || len(risk.Exposure) != 1 {
+		t.Errorf("risk = %d %+v, want one open quote", code, risk)
+	}
+
+	// Rejects come from the event bus
+	bus.Publish(events.Event{Kind: events.QuoteRejected, QuoteID: "r1", Detail: "too large", Data: mmv1.RejectReason_REJECT_REASON_AMOUNT_TOO_LARGE})
+	bus.Publish(events.Event{Kind: events.QuoteRejected, QuoteID: "r2", Data: mmv1.RejectReason_REJECT_REASON_PRICE_MOVED})
+	var rejects []query.Reject
+	for deadline := time.Now().Add(time.Second); len(rejects) < 2 && time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		getJSON(t, api, "/api/v1/rejects", &rejects)
+	}
+	if len(rejects) != 2 || rejects[0].QuoteID != "r2" || rejects[1].Reason != "REJECT_REASON_AMOUNT_TOO_LARGE" {
+		t.Errorf("rejects = %+v, want r2 then r1", rejects)
+	}
+
+	// Read-only
+	w := httptest.NewRecorder()
+	api.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/quotes", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST = %d, want 405", w.Code)
+	}
+}
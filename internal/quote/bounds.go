@@ -0,0 +1,34 @@
+package quote
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// checkNotional rejects a quote whose notional is outside the bounds of its pair
+// Returns nil for quotes within the bounds and for wrap conversions (nil pair).
+func (h *Handler) checkNotional(ctx context.Context, req *mmv1.QuoteRequest, pair *config.PairConfig, pairID string,
+	tokenIn common.Address, amountIn, amountOut *big.Int) *mmv1.Message {
+	if pair == nil {
+		return nil
+	}
+	lo, hi := pair.NotionalBounds()
+	if lo == nil && hi == nil {
+		return nil
+	}
+	notional := Notional(pair, tokenIn, amountIn, amountOut)
+	if lo != nil && notional.Cmp(*lo) < 0 {
+		h.logger.WarnContext(ctx, "notional below the pair minimum", "pairId", pairID, "notional", notional, "minimum", lo)
+		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_AMOUNT_TOO_SMALL, "notional below the pair minimum")
+	}
+	if hi != nil && notional.Cmp(*hi) > 0 {
+		h.logger.WarnContext(ctx, "notional above the pair limit", "pairId", pairID, "notional", notional, "limit", hi)
+		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_AMOUNT_TOO_LARGE, "notional above the pair limit")
+	}
+	return nil
+}
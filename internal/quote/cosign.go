@@ -0,0 +1,88 @@
+package quote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// ErrNotApproved is returned by co-signers that decline a quote
+var ErrNotApproved = errors.New("quote not approved")
+
+// ApprovalRequest is a signed quote awaiting a second approval
+type ApprovalRequest struct {
+	QuoteID   string
+	ChainID   uint64
+	PairID    string
+	Notional  decimal.Decimal // Quote token units
+	Quote     *signer.MMQuote
+	Signature []byte // The MM's signature of Quote
+}
+
+// CoSigner approves large quotes before they are released (see config.CoSignConfig)
+type CoSigner interface {
+	// Approve returns nil once the quote is approved; the quote is rejected on any error
+	Approve(ctx context.Context, req *ApprovalRequest) error
+}
+
+// coSignThresholds returns the notional thresholds of cfg.CoSign by pair ID
+// Thresholds that fail config validation are ignored
+func coSignThresholds(cfg *config.Config) map[string]decimal.Decimal {
+	thresholds := make(map[string]decimal.Decimal, len(cfg.CoSign.Thresholds))
+	for pairID, threshold := range cfg.CoSign.Thresholds {
+		if d, err := decimal.Parse(threshold); err == nil {
+			thresholds[pairID] = d
+		}
+	}
+	return thresholds
+}
+
+// Notional returns the quote token side of a quote, in quote token units
+func Notional(pair *config.PairConfig, tokenIn common.Address, amountIn, amountOut *big.Int) decimal.Decimal {
+	amount := amountIn
+	if tokenIn == common.HexToAddress(pair.BaseToken) {
+		amount = amountOut
+	}
+	return decimal.NewFromBigInt(amount).Mul(decimal.New(1, -pair.QuoteTokenDecimals))
+}
+
+// coSign asks the co-signer to approve a signed quote over its pair's threshold
+// Returns nil for quotes that need no approval
+func (h *Handler) coSign(ctx context.Context, req *mmv1.QuoteRequest, pair *config.PairConfig, pairID string,
+	tokenIn common.Address, amountIn, amountOut *big.Int, quote *signer.MMQuote, signature []byte) error {
+	if h.coSigner == nil || pair == nil {
+		return nil
+	}
+	threshold, ok := h.thresholds[pairID]
+	if !ok {
+		return nil
+	}
+	size := Notional(pair, tokenIn, amountIn, amountOut)
+	if size.Cmp(threshold) <= 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.cfg.CoSign.Timeout)
+	defer cancel()
+	err := h.coSigner.Approve(ctx, &ApprovalRequest{
+		QuoteID:   req.QuoteId,
+		ChainID:   req.ChainId,
+		PairID:    pairID,
+		Notional:  size,
+		Quote:     quote,
+		Signature: signature,
+	})
+	if err != nil {
+		return fmt.Errorf("notional %s over threshold %s: %w", size, threshold, err)
+	}
+	h.logger.InfoContext(ctx, "quote co-signed", "quoteId", req.QuoteId, "pair", pairID, "notional", size.String())
+	return nil
+}
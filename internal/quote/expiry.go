@@ -0,0 +1,52 @@
+package quote
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// maxExpiryWait bounds how long the scheduler sleeps, so a jump of the wall clock is noticed
+const maxExpiryWait = time.Minute
+
+// ExpiryScheduler expires every signed quote of a Store as soon as its deadline passes
+// Closing a quote runs the store's CloseHandler, so whatever the quote reserved is released
+// on time rather than at the next reconcile pass.
+type ExpiryScheduler struct {
+	store  *Store
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewExpiryScheduler creates an expiry scheduler for store
+func NewExpiryScheduler(store *Store, logger *slog.Logger) *ExpiryScheduler {
+	return &ExpiryScheduler{
+		store:  store,
+		logger: logger.With("component", "ExpiryScheduler"),
+		now:    time.Now,
+	}
+}
+
+// Run expires quotes at their deadlines until ctx is done
+func (e *ExpiryScheduler) Run(ctx context.Context) {
+	for {
+		now := e.now()
+		if expired := e.store.ExpireDue(now); expired > 0 {
+			e.logger.Debug("Quotes expired", "count", expired)
+		}
+
+		wait := maxExpiryWait
+		if next, ok := e.store.NextExpiry(); ok && next.Sub(now) < wait {
+			wait = next.Sub(now)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		case <-e.store.added:
+			timer.Stop()
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package quote
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+// buildExtraData returns the extraData of a quote of amountIn tokenIn for amountOut tokenOut
+// in the pair's extraData mode; wrap conversions (nil pair) and pairs without a mode sign empty
+// extraData. tokenIn and tokenOut are the pool tokens: native tokens replaced by wrapped ones.
+func (h *Handler) buildExtraData(pair *config.PairConfig, tokenIn, tokenOut common.Address, amountIn, amountOut *big.Int) ([]byte, error) {
+	if pair == nil || pair.ExtraData != signer.ExtraDataV3PoolHedge {
+		return []byte{}, nil
+	}
+	params := signer.V3PoolHedgeParams(common.HexToAddress(pair.HedgePool), h.signer.GetAddress(), tokenIn, tokenOut, amountIn, amountOut)
+	return signer.EncodeExtraData(params)
+}
@@ -0,0 +1,18 @@
+package quote
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// PriceFeed provides the prices a strategy quotes from
+// Strategies implementing it can be composed into synthetic routes (see SyntheticStrategy)
+type PriceFeed interface {
+	// Price returns the price of tokenIn in tokenOut and the time it was observed
+	// Prices are native-unit ratios (tokenOut wei per tokenIn wei), so legs compose by multiplication
+	Price(ctx context.Context, chainID uint64, tokenIn, tokenOut common.Address) (decimal.Decimal, time.Time, error)
+}
@@ -0,0 +1,74 @@
+package quote_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+)
+
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// FuzzHandleQuoteRequest checks hostile AmountIn/Nonce/Deadline values never panic and
+// that every signed response carries exactly the uint256 values that were signed
+func FuzzHandleQuoteRequest(f *testing.F) {
+	f.Add("1000000000000000000", "1", int64(30), testutil.DefaultTokenIn, testutil.DefaultTokenOut)
+	f.Add("600000000000000000000", "18446744073709551617", int64(30), testutil.DefaultTokenOut, testutil.DefaultTokenIn)
+	f.Add("-1", "1", int64(30), testutil.DefaultTokenIn, testutil.DefaultTokenOut)
+	f.Add("1e18", "0x10", int64(-5), testutil.DefaultTokenIn, testutil.DefaultTokenOut)
+	f.Add(maxUint256.String(), maxUint256.String(), int64(30), testutil.DefaultTokenIn, testutil.DefaultTokenOut)
+	f.Add("1", "-1", int64(30), "0x0000000000000000000000000000000000000000", testutil.DefaultTokenOut)
+
+	cfg := testutil.Config()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dm := signer.NewDomainManager()
+	domain := cfg.EIP712Domains[0]
+	dm.AddPoolDomainWithConfig(domain.ChainID, domain.Name, domain.Version, domain.VerifyingContract)
+	s, err := signer.NewSignerFromHex(testutil.DefaultPrivKey, dm)
+	if err != nil {
+		f.Fatalf("NewSignerFromHex failed: %v", err)
+	}
+	handler := quote.NewHandler(quote.DefaultMockStrategy(), s, cfg, logger)
+
+	f.Fuzz(func(t *testing.T, amountIn, nonce string, deadlineOffset int64, tokenIn, tokenOut string) {
+		req := testutil.QuoteRequest()
+		req.AmountIn = amountIn
+		req.Nonce = nonce
+		req.Deadline = time.Now().Unix() + deadlineOffset
+		req.TokenIn = tokenIn
+		req.TokenOut = tokenOut
+
+		msg, err := handler.HandleQuoteRequest(context.Background(), req)
+		if err != nil {
+			t.Fatalf("HandleQuoteRequest failed: %v", err)
+		}
+		resp := msg.GetQuoteResponse()
+		if resp == nil {
+			return
+		}
+
+		order := resp.Order
+		for name, value := range map[string]string{
+			"amountIn":  order.AmountIn,
+			"amountOut": order.AmountOut,
+			"nonce":     order.Nonce,
+		} {
+			n, ok := new(big.Int).SetString(value, 10)
+			if !ok || n.Sign() < 0 || n.Cmp(maxUint256) > 0 || n.String() != value {
+				t.Fatalf("Signed %s = %q is not a canonical uint256", name, value)
+			}
+		}
+		if order.AmountOut == "0" {
+			t.Fatal("Signed quote with zero amountOut")
+		}
+		if order.Deadline < time.Now().Unix() {
+			t.Fatalf("Signed quote with expired deadline %d", order.Deadline)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package quote
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math/big"
@@ -10,106 +11,218 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/address"
 	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/nonce"
 	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
 	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
 )
 
-// WrappedNativeTokens maps chain IDs to their Wrapped Native Token addresses
-// chainId -> wrapped token address
-var WrappedNativeTokens = map[uint64]common.Address{
-	56:   common.HexToAddress("0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c"), // BSC: WBNB
-	8453: common.HexToAddress("0x4200000000000000000000000000000000000006"), // Base: WETH
-	1:    common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"), // Ethereum: WETH
-}
-
 // Handler is the quote handler
 // Receives QuoteRequest, calls QuoteStrategy to calculate quote, signs and returns QuoteResponse
 type Handler struct {
 	strategy QuoteStrategy
 	signer   signer.Signer
 	cfg      *config.Config
+	store    *Store
+	rounding *Rounding
+	stats    *statsCollector
 	logger   *slog.Logger
+	now      func() time.Time
+
+	coSigner   CoSigner
+	thresholds map[string]decimal.Decimal // Co-signing notional thresholds by pair ID
+
+	events *events.Bus // Receives QuoteSigned and QuoteRejected, nil = none
+
+	limiter *RateLimiter // Per-origin request limit, nil = none
+
+	nonces *nonce.Manager // Issues or checks signed nonces, nil = the request's nonce
 }
 
 // NewHandler creates a new quote handler
 func NewHandler(strategy QuoteStrategy, s signer.Signer, cfg *config.Config, logger *slog.Logger) *Handler {
+	store := NewStore(cfg.Quote.StoreRetention)
+	store.SetDeadlineSkew(cfg.Quote.DeadlineSkew)
 	return &Handler{
 		strategy: strategy,
 		signer:   s,
 		cfg:      cfg,
+		store:    store,
+		rounding: NewRounding(cfg),
+		stats:    newStatsCollector(),
 		logger:   logger.With("component", "QuoteHandler"),
+		now:      time.Now,
+		limiter:  NewRateLimiter(cfg.Quote.RateLimit),
 	}
 }
 
+// SetClock sets the clock used for deadline checks and message timestamps (default time.Now)
+// Used to replay recorded sessions
+func (h *Handler) SetClock(now func() time.Time) {
+	h.now = now
+}
+
+// SetCoSigner requires approval from c for quotes over the co-signing thresholds of the config
+func (h *Handler) SetCoSigner(c CoSigner) {
+	h.coSigner = c
+	h.thresholds = coSignThresholds(h.cfg)
+}
+
+// SetNonces sets the nonce manager that issues or checks the nonces of signed quotes
+func (h *Handler) SetNonces(m *nonce.Manager) {
+	h.nonces = m
+}
+
+// SetEventBus publishes signed and rejected quotes to b; call before handling requests
+func (h *Handler) SetEventBus(b *events.Bus) {
+	h.events = b
+}
+
+// Stats returns a snapshot of quote handling counters
+func (h *Handler) Stats() Stats {
+	return h.stats.snapshot()
+}
+
+// RateLimitStats returns the per-origin rate limiter counters
+func (h *Handler) RateLimitStats() RateLimitStats {
+	return h.limiter.Stats(h.now())
+}
+
+// ActivePairs returns the pair IDs quoted within the given window
+func (h *Handler) ActivePairs(window time.Duration) []string {
+	return h.stats.activePairs(window)
+}
+
+// Store returns the local store of signed quotes
+func (h *Handler) Store() *Store {
+	return h.store
+}
+
 // HandleQuoteRequest processes a quote request
 // Returns QuoteResponse or QuoteReject message
+// Requests not answered within their latency budget (see QuoteConfig) are rejected
 func (h *Handler) HandleQuoteRequest(ctx context.Context, req *mmv1.QuoteRequest) (*mmv1.Message, error) {
-	h.logger.Info("received quote request",
+	start := time.Now()
+	defer func() { h.stats.recordLatency(StageTotal, time.Since(start)) }()
+
+	h.logger.InfoContext(ctx, "received quote request",
 		"quoteId", req.QuoteId,
 		"chainId", req.ChainId,
 		"tokenIn", req.TokenIn,
 		"tokenOut", req.TokenOut,
 		"amountIn", req.AmountIn)
+	h.stats.recordRequest()
 
 	// 1. Validate request parameters
 	if err := h.validateRequest(req); err != nil {
-		h.logger.Error("request validation failed", "error", err)
+		h.logger.ErrorContext(ctx, "request validation failed", "error", err)
 		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_INTERNAL_ERROR, err.Error()), nil
 	}
+	if reject := h.rateLimit(ctx, req); reject != nil {
+		return reject, nil
+	}
+	if budget, ok := h.budget(req.Deadline); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
 
 	// 2. Get EIP712 Domain (for signing)
 	domain := h.cfg.GetEIP712Domain(req.ChainId)
 	if domain == nil {
-		h.logger.Error("chain not configured", "chainId", req.ChainId)
+		h.logger.ErrorContext(ctx, "chain not configured", "chainId", req.ChainId)
 		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_PAIR_NOT_SUPPORTED,
 			fmt.Sprintf("chain %d not configured", req.ChainId)), nil
 	}
+	if h.cfg.ChainFlags(req.ChainId).PauseQuoting {
+		h.logger.InfoContext(ctx, "quoting paused on chain", "chainId", req.ChainId)
+		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_PAIR_NOT_SUPPORTED,
+			fmt.Sprintf("quoting paused on chain %d", req.ChainId)), nil
+	}
 
 	// 3. Handle zero address (native token): replace with chain's Wrapped Token
 	tokenIn := common.HexToAddress(req.TokenIn)
 	tokenOut := common.HexToAddress(req.TokenOut)
+	registry := h.cfg.ChainRegistry()
 
 	if tokenIn == (common.Address{}) {
-		wrappedToken, ok := WrappedNativeTokens[req.ChainId]
+		wrappedToken, ok := registry.WrappedNative(req.ChainId)
 		if !ok {
-			h.logger.Error("wrapped token not found for tokenIn", "chainId", req.ChainId)
+			h.logger.ErrorContext(ctx, "wrapped token not found for tokenIn", "chainId", req.ChainId)
 			return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_INTERNAL_ERROR,
 				fmt.Sprintf("wrapped token not configured for chain %d", req.ChainId)), nil
 		}
 		tokenIn = wrappedToken
-		h.logger.Info("tokenIn is zero address, using wrapped token", "wrappedToken", tokenIn.Hex())
+		h.logger.InfoContext(ctx, "tokenIn is zero address, using wrapped token", "wrappedToken", tokenIn.Hex())
 	}
 
 	if tokenOut == (common.Address{}) {
-		wrappedToken, ok := WrappedNativeTokens[req.ChainId]
+		wrappedToken, ok := registry.WrappedNative(req.ChainId)
 		if !ok {
-			h.logger.Error("wrapped token not found for tokenOut", "chainId", req.ChainId)
+			h.logger.ErrorContext(ctx, "wrapped token not found for tokenOut", "chainId", req.ChainId)
 			return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_INTERNAL_ERROR,
 				fmt.Sprintf("wrapped token not configured for chain %d", req.ChainId)), nil
 		}
 		tokenOut = wrappedToken
-		h.logger.Info("tokenOut is zero address, using wrapped token", "wrappedToken", tokenOut.Hex())
+		h.logger.InfoContext(ctx, "tokenOut is zero address, using wrapped token", "wrappedToken", tokenOut.Hex())
 	}
 
 	// 4. Get trading pair configuration
-	if h.cfg.GetPairConfig(req.ChainId, tokenIn.Hex(), tokenOut.Hex()) == nil {
-		h.logger.Error("pair not found", "chainId", req.ChainId, "tokenIn", tokenIn.Hex(), "tokenOut", tokenOut.Hex())
+	// Native <-> wrapped requests have the same token on both sides after substitution
+	// and are converted 1:1 without a pair
+	wrap := isWrapRequest(req, tokenIn, tokenOut)
+	if wrap && !h.cfg.Quote.WrapConversion {
 		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_PAIR_NOT_SUPPORTED,
-			fmt.Sprintf("pair not found for tokens %s-%s", tokenIn.Hex(), tokenOut.Hex())), nil
+			"native/wrapped conversion not enabled"), nil
+	}
+	pairID := wrapPairID
+	var pair *config.PairConfig
+	if !wrap {
+		pair = h.cfg.GetPairConfigByAddress(req.ChainId, tokenIn, tokenOut)
+		if pair == nil {
+			h.logger.ErrorContext(ctx, "pair not found", "chainId", req.ChainId, "tokenIn", tokenIn.Hex(), "tokenOut", tokenOut.Hex())
+			return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_PAIR_NOT_SUPPORTED,
+				fmt.Sprintf("pair not found for tokens %s-%s", tokenIn.Hex(), tokenOut.Hex())), nil
+		}
+		pairID = pair.PairID
 	}
 
 	// 5. Parse input amount (swap-engine sends native decimals)
-	amountIn, ok := new(big.Int).SetString(req.AmountIn, 10)
-	if !ok {
+	amountIn, err := parseUint256(req.AmountIn)
+	if err != nil || amountIn.Sign() == 0 {
 		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_INTERNAL_ERROR, "invalid amount_in"), nil
 	}
 
-	h.logger.Info("amountIn received (native decimals)",
+	if pair != nil {
+		maxBase, maxQuote := h.cfg.PairMaxAmountsIn(pair)
+		limit := maxQuote
+		if tokenIn == common.HexToAddress(pair.BaseToken) {
+			limit = maxBase
+		}
+		if limit != nil && amountIn.Cmp(limit) > 0 {
+			h.logger.WarnContext(ctx, "amount_in above the pair limit", "pairId", pairID, "amountIn", amountIn, "limit", limit)
+			return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_AMOUNT_TOO_LARGE, "amount_in above the pair limit"), nil
+		}
+		minBase, minQuote := pair.MinAmountsIn()
+		minimum := minQuote
+		if tokenIn == common.HexToAddress(pair.BaseToken) {
+			minimum = minBase
+		}
+		if minimum != nil && amountIn.Cmp(minimum) < 0 {
+			h.logger.WarnContext(ctx, "amount_in below the pair minimum", "pairId", pairID, "amountIn", amountIn, "minimum", minimum)
+			return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_AMOUNT_TOO_SMALL, "amount_in below the pair minimum"), nil
+		}
+	}
+
+	h.logger.InfoContext(ctx, "amountIn received (native decimals)",
 		"tokenIn", tokenIn.Hex(),
 		"amountIn", amountIn.String())
+	h.stats.recordLatency(StageValidate, time.Since(start))
 
-	// 6. Call strategy to calculate quote
+	// 6. Call strategy to calculate quote (wrap conversions need no price)
 	quoteParams := &QuoteParams{
 		ChainID:  req.ChainId,
 		TokenIn:  tokenIn,
@@ -117,24 +230,75 @@ func (h *Handler) HandleQuoteRequest(ctx context.Context, req *mmv1.QuoteRequest
 		AmountIn: amountIn,
 	}
 
-	quoteResult, err := h.strategy.CalculateQuote(ctx, quoteParams)
+	var quoteResult *QuoteResult
+	if wrap {
+		quoteResult, err = wrapQuote(amountIn, h.cfg.Quote.WrapFeeBps)
+	} else {
+		strategyStart := time.Now()
+		quoteResult, err = h.calculateQuote(ctx, quoteParams)
+		h.stats.recordLatency(StageStrategy, time.Since(strategyStart))
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return h.rejectOverBudget(ctx, req, StageStrategy, start), nil
+	}
+	if errors.Is(err, ErrPriceMoved) {
+		h.logger.WarnContext(ctx, "quote refused, price moved", "error", err)
+		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_PRICE_MOVED, err.Error()), nil
+	}
 	if err != nil {
-		h.logger.Error("quote calculation failed", "error", err)
+		h.logger.ErrorContext(ctx, "quote calculation failed", "error", err)
 		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_INSUFFICIENT_LIQUIDITY, err.Error()), nil
 	}
+	// The pair spread is taken over the strategy price
+	if pair != nil && !wrap {
+		applyPairSpread(quoteResult, h.cfg.PairParams(pair).SpreadBps)
+	}
+	// Round to the output tick; a quote smaller than one tick cannot be given
+	quoteResult.AmountOut = h.rounding.Round(pair, tokenOut, quoteResult.AmountOut)
+	quoteResult.AmountOutMinimum = h.rounding.Round(pair, tokenOut, quoteResult.AmountOutMinimum)
+	if quoteResult.AmountOutMinimum != nil && quoteResult.AmountOutMinimum.Sign() == 0 {
+		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_AMOUNT_TOO_SMALL, "amount_out below one tick"), nil
+	}
+	// uint256 encoding silently wraps larger values, so an out-of-range amount would sign a different quote
+	if quoteResult.AmountOutMinimum == nil || quoteResult.AmountOutMinimum.Sign() <= 0 || quoteResult.AmountOutMinimum.Cmp(maxUint256) > 0 {
+		h.logger.ErrorContext(ctx, "quote amount out of range", "amountOutMinimum", quoteResult.AmountOutMinimum)
+		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_INTERNAL_ERROR, "amount_out out of range"), nil
+	}
+
+	// The notional is known once the quote is priced
+	if reject := h.checkNotional(ctx, req, pair, pairID, tokenIn, amountIn, quoteResult.AmountOutMinimum); reject != nil {
+		return reject, nil
+	}
+
+	// Strategies that do not describe their route priced the pair directly
+	if len(quoteResult.Info.Route) == 0 {
+		quoteResult.Info.Route = []common.Address{tokenIn, tokenOut}
+	}
 
 	// 7. amountOut uses native decimals (no 18d conversion)
-	h.logger.Info("quote calculated (native decimals)",
+	h.logger.InfoContext(ctx, "quote calculated (native decimals)",
 		"amountOut", quoteResult.AmountOut.String(),
-		"amountOutMinimum", quoteResult.AmountOutMinimum.String())
+		"amountOutMinimum", quoteResult.AmountOutMinimum.String(),
+		"priceSource", quoteResult.Info.PriceSource,
+		"feeBps", quoteResult.Info.FeeBps)
 
-	// 8. ExtraData is optional; demo keeps it empty
-	extraData := []byte{}
+	// 8. ExtraData: empty, or the hedge parameters of the pair's extraData mode
+	extraData, err := h.buildExtraData(pair, tokenIn, tokenOut, amountIn, quoteResult.AmountOutMinimum)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "extraData encoding failed", "pairId", pairID, "error", err)
+		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_INTERNAL_ERROR, "extraData encoding failed"), nil
+	}
 
-	// 9. Parse nonce
-	nonce, ok := new(big.Int).SetString(req.Nonce, 10)
-	if !ok {
-		nonce = big.NewInt(0)
+	// 9. Parse nonce (signing a different nonce than the order carries can never verify)
+	nonce, err := parseUint256(req.Nonce)
+	if err != nil {
+		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_INTERNAL_ERROR, "invalid nonce"), nil
+	}
+	// The nonce manager, when set, issues the nonce or refuses one signed before
+	rfqManager := common.HexToAddress(domain.VerifyingContract)
+	if nonce, err = h.assignNonce(req.ChainId, rfqManager, nonce); err != nil {
+		h.logger.WarnContext(ctx, "nonce refused", "quoteId", req.QuoteId, "nonce", req.Nonce, "error", err)
+		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_INTERNAL_ERROR, "nonce refused"), nil
 	}
 
 	// 10. Build MMQuote (for EIP-712 signing)
@@ -142,53 +306,267 @@ func (h *Handler) HandleQuoteRequest(ctx context.Context, req *mmv1.QuoteRequest
 	from := common.HexToAddress(req.From)
 	to := common.HexToAddress(req.Recipient)
 	mmQuote := &signer.MMQuote{
-		RFQManager:        common.HexToAddress(domain.VerifyingContract),
+		RFQManager:        rfqManager,
 		From:        from,
 		To:          to,
 		InputToken:  common.HexToAddress(req.TokenIn),  // Use original TokenIn
 		OutputToken: common.HexToAddress(req.TokenOut), // Use original TokenOut
 		AmountIn:    amountIn,                          // Native decimals
 		AmountOut:   quoteResult.AmountOutMinimum,      // Native decimals
-		Deadline:    big.NewInt(req.Deadline),
+		Deadline:    big.NewInt(h.signedDeadline(req.Deadline)),
 		Nonce:       nonce,
 		ExtraData:   extraData,
 	}
 
 	// 11. EIP-712 signing
-	signature, err := h.signer.SignMMQuote(req.ChainId, mmQuote)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return h.rejectOverBudget(ctx, req, StageSign, start), nil
+	}
+	// Signer pools assign a key per quote; the order names the key that signed it
+	quoteSigner := h.signer
+	if pool, ok := h.signer.(signer.KeyAssigner); ok {
+		quoteSigner, err = pool.Assign(req.ChainId, pairID)
+		if err != nil {
+			h.logger.ErrorContext(ctx, "no signing key available", "error", err)
+			return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_INTERNAL_ERROR, "no signing key available"), nil
+		}
+	}
+	signStart := time.Now()
+	var signature []byte
+	if clamper, ok := quoteSigner.(signer.DeadlineClamper); ok && h.cfg.Signer.ClampDeadline {
+		signature, err = clamper.SignMMQuoteClamped(req.ChainId, mmQuote)
+	} else {
+		signature, err = quoteSigner.SignMMQuote(req.ChainId, mmQuote)
+	}
+	h.stats.recordLatency(StageSign, time.Since(signStart))
+	if errors.Is(err, signer.ErrDeadlineTooFar) {
+		h.logger.WarnContext(ctx, "signer refused deadline", "quoteId", req.QuoteId, "error", err)
+		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_INTERNAL_ERROR, "deadline too far in the future"), nil
+	}
 	if err != nil {
-		h.logger.Error("signing failed", "error", err)
+		h.logger.ErrorContext(ctx, "signing failed", "error", err)
 		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_INTERNAL_ERROR, "signing failed"), nil
 	}
-	h.logger.Info("quote signed successfully", "quoteId", req.QuoteId)
+	h.logger.InfoContext(ctx, "quote signed successfully", "quoteId", req.QuoteId, "elapsed", time.Since(start))
+	// quote.validDuration or the signer may have shortened the deadline; answer with the signed one
+	deadline := mmQuote.Deadline.Int64()
+	if deadline != req.Deadline {
+		h.logger.InfoContext(ctx, "deadline shortened", "quoteId", req.QuoteId, "requested", req.Deadline, "signed", deadline)
+	}
+
+	// Large quotes are only released once the co-signer approves them
+	if err := h.coSign(ctx, req, pair, pairID, tokenIn, amountIn, quoteResult.AmountOutMinimum, mmQuote, signature); err != nil {
+		h.logger.WarnContext(ctx, "co-signer approval failed", "quoteId", req.QuoteId, "pair", pairID, "error", err)
+		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_AMOUNT_TOO_LARGE, "co-signer approval failed"), nil
+	}
+
+	// Track signed quote until its deadline
+	rec := &QuoteRecord{
+		QuoteID:   req.QuoteId,
+		ChainID:   req.ChainId,
+		TokenIn:   tokenIn,
+		TokenOut:  tokenOut,
+		AmountIn:  amountIn,
+		AmountOut: quoteResult.AmountOutMinimum,
+		Nonce:     nonce.String(),
+		Deadline:  deadline,
+		Signer:    quoteSigner.GetAddress(),
+		Info:      quoteResult.Info,
+	}
+	h.store.Add(rec)
+	if h.nonces != nil {
+		h.nonces.Sent(h.nonces.Key(req.ChainId, rfqManager), rec.Nonce)
+	}
+	h.events.Publish(events.Event{
+		Kind:    events.QuoteSigned,
+		ChainID: req.ChainId,
+		PairID:  pairID,
+		QuoteID: req.QuoteId,
+		Data:    *rec,
+		Request: req,
+	})
 
 	// 12. Build response (using native decimals)
 	response := &mmv1.QuoteResponse{
 		QuoteId: req.QuoteId,
 		ChainId: req.ChainId,
-		MmId:    strings.ToLower(h.signer.GetAddress().Hex()),
+		MmId:    address.Normalize(h.signer.GetAddress()),
 		Status:  mmv1.QuoteStatus_QUOTE_STATUS_SUCCESS,
 		Order: &mmv1.SignedOrder{
-			Signer:     strings.ToLower(h.signer.GetAddress().Hex()),
+			Signer:     address.Normalize(quoteSigner.GetAddress()),
 			RfqManager: strings.ToLower(domain.VerifyingContract),
-			Nonce:      req.Nonce,
+			Nonce:      nonce.String(),
 			AmountIn:   amountIn.String(),                     // Native decimals
 			AmountOut:  quoteResult.AmountOutMinimum.String(), // Native decimals (matches signature)
-			Deadline:   req.Deadline,
+			Deadline:   deadline,
 			ExtraData:  extraData,
 			Signature:  signature,
 		},
 	}
 
+	h.stats.recordResponse(pairID)
+
 	return &mmv1.Message{
 		Type:      mmv1.MessageType_MESSAGE_TYPE_QUOTE_RESPONSE,
-		Timestamp: time.Now().UnixMilli(),
+		Timestamp: h.now().UnixMilli(),
 		Payload: &mmv1.Message_QuoteResponse{
 			QuoteResponse: response,
 		},
 	}, nil
 }
 
+// assignNonce returns the nonce to sign for a quote on chainID: the requested one, or the one
+// the nonce manager issues or accepts
+func (h *Handler) assignNonce(chainID uint64, rfqManager common.Address, requested *big.Int) (*big.Int, error) {
+	if h.nonces == nil {
+		return requested, nil
+	}
+	return h.nonces.Sign(h.nonces.Key(chainID, rfqManager), requested)
+}
+
+// wrapPairID is the pair ID native <-> wrapped conversions are reported under
+const wrapPairID = "native-wrapped"
+
+// isWrapRequest reports whether a request converts the native token to its wrapped token or back
+// tokenIn and tokenOut are the request tokens after native token substitution
+func isWrapRequest(req *mmv1.QuoteRequest, tokenIn, tokenOut common.Address) bool {
+	nativeIn := common.HexToAddress(req.TokenIn) == (common.Address{})
+	nativeOut := common.HexToAddress(req.TokenOut) == (common.Address{})
+	return tokenIn == tokenOut && nativeIn != nativeOut
+}
+
+// wrapQuote quotes a native <-> wrapped conversion: 1:1 minus feeBps, truncated
+func wrapQuote(amountIn *big.Int, feeBps uint32) (*QuoteResult, error) {
+	amountOut := new(big.Int).Mul(amountIn, big.NewInt(10000-int64(feeBps)))
+	amountOut.Quo(amountOut, big.NewInt(10000))
+	if amountOut.Sign() <= 0 {
+		return nil, fmt.Errorf("calculated amount out is zero or negative")
+	}
+	result := NewQuoteResult(amountOut)
+	result.ExecutionPrice = decimal.NewFromInt(1)
+	result.PriceImpact = float64(feeBps) / 100
+	result.Info = QuoteInfo{FeeBps: feeBps, PriceSource: "wrap", MidPrice: result.ExecutionPrice}
+	return result, nil
+}
+
+// signedDeadline returns the deadline to sign for a requested one (unix seconds)
+// A quote is valid for at most quote.validDuration; 0 = the requested deadline
+func (h *Handler) signedDeadline(requested int64) int64 {
+	valid := h.cfg.ValidDuration()
+	if valid <= 0 {
+		return requested
+	}
+	return min(requested, h.now().Add(valid).Unix())
+}
+
+// applyPairSpread takes spreadBps from the output amounts of result
+func applyPairSpread(result *QuoteResult, spreadBps uint32) {
+	if spreadBps == 0 {
+		return
+	}
+	keep := decimal.New(10000-int64(spreadBps), -4)
+	for _, amount := range []**big.Int{&result.AmountOut, &result.AmountOutMinimum} {
+		if *amount != nil {
+			*amount = decimal.NewFromBigInt(*amount).Mul(keep).Int()
+		}
+	}
+	result.Info.FeeBps += spreadBps
+}
+
+// budget returns the latency budget of a request with the given deadline (unix seconds)
+// The smaller of the fixed and the deadline-derived budget applies; false if neither is configured
+func (h *Handler) budget(deadline int64) (time.Duration, bool) {
+	var budget time.Duration
+	bounded := false
+	if h.cfg.Quote.LatencyBudget > 0 {
+		budget, bounded = h.cfg.Quote.LatencyBudget, true
+	}
+	if h.cfg.Quote.BudgetFraction > 0 {
+		remaining := time.Unix(deadline, 0).Sub(h.now())
+		derived := time.Duration(float64(remaining) * h.cfg.Quote.BudgetFraction)
+		if !bounded || derived < budget {
+			budget, bounded = derived, true
+		}
+	}
+	return budget, bounded
+}
+
+// rateLimit returns the rejection of a request over its origin's rate, nil if it may proceed
+// The origin is the recipient: mm_id names this MM, not the requester.
+func (h *Handler) rateLimit(ctx context.Context, req *mmv1.QuoteRequest) *mmv1.Message {
+	origin := address.Normalize(common.HexToAddress(req.Recipient))
+	switch h.limiter.Allow(origin, h.now()) {
+	case Allowed:
+		return nil
+	case NewlyBanned:
+		h.logger.WarnContext(ctx, "origin banned for exceeding its request rate",
+			"origin", origin,
+			"duration", h.cfg.Quote.RateLimit.BanDuration)
+		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_RATE_LIMITED, "origin temporarily banned")
+	case Banned:
+		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_RATE_LIMITED, "origin temporarily banned")
+	default:
+		h.logger.DebugContext(ctx, "quote request rate limited", "quoteId", req.QuoteId, "origin", origin)
+		return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_RATE_LIMITED, "rate limited")
+	}
+}
+
+// calculateQuote calls the strategy and returns as soon as ctx is done
+// A strategy that ignores ctx is abandoned and its result discarded
+func (h *Handler) calculateQuote(ctx context.Context, params *QuoteParams) (*QuoteResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		quote *QuoteResult
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		q, err := h.strategy.CalculateQuote(ctx, params)
+		done <- result{quote: q, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.quote, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// rejectOverBudget builds the rejection of a request that ran out of latency budget in stage
+func (h *Handler) rejectOverBudget(ctx context.Context, req *mmv1.QuoteRequest, stage string, start time.Time) *mmv1.Message {
+	h.stats.recordBudgetExceeded()
+	h.logger.WarnContext(ctx, "quote latency budget exceeded",
+		"quoteId", req.QuoteId,
+		"stage", stage,
+		"elapsed", time.Since(start))
+	return h.buildRejectMessage(req, mmv1.RejectReason_REJECT_REASON_INTERNAL_ERROR, "latency budget exceeded")
+}
+
+// maxUint256 is the largest value of a Solidity uint256
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// parseUint256 parses a base-10 uint256 string
+// Signs, whitespace and values above 2^256-1 are rejected
+func parseUint256(s string) (*big.Int, error) {
+	if s == "" || len(s) > 78 {
+		return nil, fmt.Errorf("invalid uint256 %q", s)
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("invalid uint256 %q", s)
+		}
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok || n.Cmp(maxUint256) > 0 {
+		return nil, fmt.Errorf("invalid uint256 %q", s)
+	}
+	return n, nil
+}
+
 // validateRequest validates quote request parameters
 func (h *Handler) validateRequest(req *mmv1.QuoteRequest) error {
 	if req.QuoteId == "" {
@@ -203,17 +581,34 @@ func (h *Handler) validateRequest(req *mmv1.QuoteRequest) error {
 	if req.TokenOut == "" {
 		return fmt.Errorf("token_out is required")
 	}
+	// The zero address is the native token
+	if _, err := address.Parse(req.TokenIn); err != nil {
+		return fmt.Errorf("token_in: %w", err)
+	}
+	if _, err := address.Parse(req.TokenOut); err != nil {
+		return fmt.Errorf("token_out: %w", err)
+	}
 	if req.AmountIn == "" || req.AmountIn == "0" {
 		return fmt.Errorf("amount_in is required and must be positive")
 	}
 	if req.Recipient == "" {
 		return fmt.Errorf("recipient is required")
 	}
+	if _, err := address.ParseNonZero(req.Recipient); err != nil {
+		return fmt.Errorf("recipient: %w", err)
+	}
+	// from is optional: only a present but malformed address is rejected
+	if req.From != "" {
+		if _, err := address.Parse(req.From); err != nil {
+			return fmt.Errorf("from: %w", err)
+		}
+	}
 	if req.Deadline == 0 {
 		return fmt.Errorf("deadline is required")
 	}
-	// Check if deadline has already expired
-	if req.Deadline < time.Now().Unix() {
+	// Check if deadline has already expired; the deadline second is inclusive, and the skew
+	// tolerance absorbs sub-second clock differences with the server
+	if !h.now().Before(time.Unix(req.Deadline+1, 0).Add(h.cfg.Quote.DeadlineSkew)) {
 		return fmt.Errorf("deadline already expired")
 	}
 	return nil
@@ -221,14 +616,23 @@ func (h *Handler) validateRequest(req *mmv1.QuoteRequest) error {
 
 // buildRejectMessage builds a rejection message
 func (h *Handler) buildRejectMessage(req *mmv1.QuoteRequest, reason mmv1.RejectReason, message string) *mmv1.Message {
+	h.stats.recordReject(reason)
+	h.events.Publish(events.Event{
+		Kind:    events.QuoteRejected,
+		ChainID: req.ChainId,
+		QuoteID: req.QuoteId,
+		Detail:  message,
+		Data:    reason,
+		Request: req,
+	})
 	return &mmv1.Message{
 		Type:      mmv1.MessageType_MESSAGE_TYPE_QUOTE_REJECT,
-		Timestamp: time.Now().UnixMilli(),
+		Timestamp: h.now().UnixMilli(),
 		Payload: &mmv1.Message_QuoteReject{
 			QuoteReject: &mmv1.QuoteReject{
 				QuoteId: req.QuoteId,
 				ChainId: req.ChainId,
-				MmId:    strings.ToLower(h.signer.GetAddress().Hex()),
+				MmId:    address.Normalize(h.signer.GetAddress()),
 				Reason:  reason,
 				Message: message,
 			},
@@ -0,0 +1,760 @@
+package quote_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/nonce"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// slowStrategy blocks until release is closed, ignoring ctx like a misbehaving strategy
+type slowStrategy struct {
+	inner   quote.QuoteStrategy
+	release chan struct{}
+}
+
+func (s *slowStrategy) CalculateQuote(ctx context.Context, params *quote.QuoteParams) (*quote.QuoteResult, error) {
+	<-s.release
+	return s.inner.CalculateQuote(ctx, params)
+}
+
+func newTestHandler(t *testing.T, strategy quote.QuoteStrategy, cfg *config.Config) *quote.Handler {
+	t.Helper()
+
+	dm := signer.NewDomainManager()
+	domain := cfg.EIP712Domains[0]
+	dm.AddPoolDomainWithConfig(domain.ChainID, domain.Name, domain.Version, domain.VerifyingContract)
+	s, err := signer.NewSignerFromHex(testutil.DefaultPrivKey, dm)
+	if err != nil {
+		t.Fatalf("NewSignerFromHex failed: %v", err)
+	}
+	return quote.NewHandler(strategy, s, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestHandler_LatencyBudgetExceeded(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Quote.LatencyBudget = 20 * time.Millisecond
+	strategy := &slowStrategy{inner: testutil.NewFixedRateStrategy(600, 1), release: make(chan struct{})}
+	defer close(strategy.release)
+	handler := newTestHandler(t, strategy, cfg)
+
+	start := time.Now()
+	msg, err := handler.HandleQuoteRequest(context.Background(), testutil.QuoteRequest())
+	if err != nil {
+		t.Fatalf("HandleQuoteRequest failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("HandleQuoteRequest took %v, want about the 20ms budget", elapsed)
+	}
+	reject := msg.GetQuoteReject()
+	if reject == nil {
+		t.Fatalf("message type = %v, want quote reject", msg.Type)
+	}
+	if reject.Message != "latency budget exceeded" {
+		t.Errorf("reject message = %q, want %q", reject.Message, "latency budget exceeded")
+	}
+
+	stats := handler.Stats()
+	if stats.BudgetExceeded != 1 {
+		t.Errorf("BudgetExceeded = %d, want 1", stats.BudgetExceeded)
+	}
+	if got := stats.Latency[quote.StageStrategy].Max; got < cfg.Quote.LatencyBudget {
+		t.Errorf("strategy latency max = %v, want >= %v", got, cfg.Quote.LatencyBudget)
+	}
+}
+
+func TestHandler_LatencyBudgetFromDeadline(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Quote.LatencyBudget = time.Hour
+	cfg.Quote.BudgetFraction = 0.5
+	strategy := &slowStrategy{inner: testutil.NewFixedRateStrategy(600, 1), release: make(chan struct{})}
+	defer close(strategy.release)
+	handler := newTestHandler(t, strategy, cfg)
+
+	// 1s left to the deadline: the deadline-derived budget of 500ms applies, not the hour
+	now := time.Unix(1700000000, 0)
+	handler.SetClock(func() time.Time { return now })
+	req := testutil.QuoteRequest()
+	req.Deadline = now.Unix() + 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := handler.HandleQuoteRequest(ctx, req); err != nil {
+			t.Errorf("HandleQuoteRequest failed: %v", err)
+		}
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("HandleQuoteRequest returned before its budget ran out")
+	case <-time.After(100 * time.Millisecond):
+	}
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("HandleQuoteRequest did not return within the deadline-derived budget")
+	}
+	if got := handler.Stats().BudgetExceeded; got != 1 {
+		t.Errorf("BudgetExceeded = %d, want 1", got)
+	}
+}
+
+func TestHandler_LatencyStats(t *testing.T) {
+	handler := newTestHandler(t, testutil.NewFixedRateStrategy(600, 1), testutil.Config())
+
+	msg, err := handler.HandleQuoteRequest(context.Background(), testutil.QuoteRequest())
+	if err != nil {
+		t.Fatalf("HandleQuoteRequest failed: %v", err)
+	}
+	if msg.GetQuoteResponse() == nil {
+		t.Fatalf("message type = %v, want quote response", msg.Type)
+	}
+
+	stats := handler.Stats()
+	for _, stage := range []string{quote.StageValidate, quote.StageStrategy, quote.StageSign, quote.StageTotal} {
+		if got := stats.Latency[stage].Count; got != 1 {
+			t.Errorf("%s latency count = %d, want 1", stage, got)
+		}
+	}
+	if stats.BudgetExceeded != 0 {
+		t.Errorf("BudgetExceeded = %d, want 0", stats.BudgetExceeded)
+	}
+}
+
+func TestHandler_StableDepegRejectsPriceMoved(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Stable = config.StableConfig{Pairs: []string{"WBNB-USDT"}, MaxDeviationBps: 50}
+	cfg.BuildIndex()
+	// The reference prices the pair at 600, far from parity
+	handler := newTestHandler(t, quote.NewStableStrategy(testutil.NewFixedRateStrategy(600, 1), cfg), cfg)
+
+	msg, err := handler.HandleQuoteRequest(context.Background(), testutil.QuoteRequest())
+	if err != nil {
+		t.Fatalf("HandleQuoteRequest failed: %v", err)
+	}
+	reject := msg.GetQuoteReject()
+	if reject == nil {
+		t.Fatalf("message type = %v, want quote reject", msg.Type)
+	}
+	if reject.Reason != mmv1.RejectReason_REJECT_REASON_PRICE_MOVED {
+		t.Errorf("reject reason = %v, want %v", reject.Reason, mmv1.RejectReason_REJECT_REASON_PRICE_MOVED)
+	}
+}
+
+func TestHandler_WrapConversion(t *testing.T) {
+	const native = "0x0000000000000000000000000000000000000000"
+	tests := []struct {
+		name          string
+		enabled       bool
+		tokenIn       string
+		tokenOut      string
+		wantAmountOut string
+		wantReason    mmv1.RejectReason
+	}{
+		{"wrap", true, native, testutil.DefaultTokenIn, "999000000000000000", 0},
+		{"unwrap", true, testutil.DefaultTokenIn, native, "999000000000000000", 0},
+		{"disabled", false, native, testutil.DefaultTokenIn, "", mmv1.RejectReason_REJECT_REASON_PAIR_NOT_SUPPORTED},
+		{"native to native", true, native, native, "", mmv1.RejectReason_REJECT_REASON_PAIR_NOT_SUPPORTED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testutil.Config()
+			cfg.Quote.WrapConversion = tt.enabled
+			cfg.Quote.WrapFeeBps = 10
+			// The strategy has no price for the conversion; it must not be called
+			handler := newTestHandler(t, &slowStrategy{release: make(chan struct{})}, cfg)
+
+			req := testutil.QuoteRequest()
+			req.TokenIn, req.TokenOut = tt.tokenIn, tt.tokenOut
+			msg, err := handler.HandleQuoteRequest(context.Background(), req)
+			if err != nil {
+				t.Fatalf("HandleQuoteRequest failed: %v", err)
+			}
+			if tt.wantAmountOut == "" {
+				if reject := msg.GetQuoteReject(); reject == nil || reject.Reason != tt.wantReason {
+					t.Fatalf("message = %v, want reject %v", msg, tt.wantReason)
+				}
+				return
+			}
+			response := msg.GetQuoteResponse()
+			if response == nil {
+				t.Fatalf("message = %v, want quote response", msg)
+			}
+			if response.Order.AmountOut != tt.wantAmountOut {
+				t.Errorf("AmountOut = %s, want %s", response.Order.AmountOut, tt.wantAmountOut)
+			}
+		})
+	}
+}
+
+func TestHandler_RecordsQuoteInfo(t *testing.T) {
+	tokenIn, tokenOut := common.HexToAddress(testutil.DefaultTokenIn), common.HexToAddress(testutil.DefaultTokenOut)
+	mock := quote.NewMockStrategy(30)
+	mock.SetPrice(testutil.DefaultChainID, tokenIn, tokenOut, decimal.NewFromInt(600))
+
+	tests := []struct {
+		name     string
+		strategy quote.QuoteStrategy
+		want     quote.QuoteInfo
+	}{
+		{"described by strategy", mock, quote.QuoteInfo{FeeBps: 30, PriceSource: "mock", MidPrice: decimal.NewFromInt(600)}},
+		// Strategies that fill no info still get the direct route recorded
+		{"undescribed", testutil.NewFixedRateStrategy(600, 1), quote.QuoteInfo{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestHandler(t, tt.strategy, testutil.Config())
+			if _, err := handler.HandleQuoteRequest(context.Background(), testutil.QuoteRequest()); err != nil {
+				t.Fatalf("HandleQuoteRequest failed: %v", err)
+			}
+			rec, ok := handler.Store().Get(testutil.DefaultQuoteID)
+			if !ok {
+				t.Fatal("quote not recorded")
+			}
+			info := rec.Info
+			if info.FeeBps != tt.want.FeeBps || info.PriceSource != tt.want.PriceSource || info.MidPrice.Cmp(tt.want.MidPrice) != 0 {
+				t.Errorf("Info = %+v, want %+v", info, tt.want)
+			}
+			if len(info.Route) != 2 || info.Route[0] != tokenIn || info.Route[1] != tokenOut {
+				t.Errorf("Route = %v, want [%s %s]", info.Route, tokenIn.Hex(), tokenOut.Hex())
+			}
+		})
+	}
+}
+
+func TestHandler_RoundsToPairTick(t *testing.T) {
+	tests := []struct {
+		name          string
+		tick          string
+		wantAmountOut string
+		wantReason    mmv1.RejectReason
+	}{
+		// 1 WBNB at 599.999 USDT rounds down to the 0.01 tick
+		{"rounded down", "0.01", "599990000000000000000", 0},
+		{"below one tick", "1000", "", mmv1.RejectReason_REJECT_REASON_AMOUNT_TOO_SMALL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testutil.Config()
+			cfg.Quote.Rounding = config.RoundDown
+			cfg.Pairs[0].QuoteTick = tt.tick
+			handler := newTestHandler(t, testutil.NewFixedRateStrategy(599999, 1000), cfg)
+
+			msg, err := handler.HandleQuoteRequest(context.Background(), testutil.QuoteRequest())
+			if err != nil {
+				t.Fatalf("HandleQuoteRequest failed: %v", err)
+			}
+			if tt.wantAmountOut == "" {
+				if reject := msg.GetQuoteReject(); reject == nil || reject.Reason != tt.wantReason {
+					t.Fatalf("message = %v, want reject %v", msg, tt.wantReason)
+				}
+				return
+			}
+			response := msg.GetQuoteResponse()
+			if response == nil {
+				t.Fatalf("message = %v, want quote response", msg)
+			}
+			if response.Order.AmountOut != tt.wantAmountOut {
+				t.Errorf("AmountOut = %s, want %s", response.Order.AmountOut, tt.wantAmountOut)
+			}
+		})
+	}
+}
+
+func TestHandler_PairBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		pair       config.PairConfig
+		wantReason mmv1.RejectReason // Unspecified = quoted
+	}{
+		// The request sells 1 WBNB (base) for about 600 USDT (quote)
+		{"unbounded", config.PairConfig{}, mmv1.RejectReason_REJECT_REASON_UNSPECIFIED},
+		{"at the base minimum", config.PairConfig{MinBaseIn: "1"}, mmv1.RejectReason_REJECT_REASON_UNSPECIFIED},
+		{"below the base minimum", config.PairConfig{MinBaseIn: "2"}, mmv1.RejectReason_REJECT_REASON_AMOUNT_TOO_SMALL},
+		{"quote minimum only", config.PairConfig{MinQuoteIn: "1000"}, mmv1.RejectReason_REJECT_REASON_UNSPECIFIED},
+		{"within the notional bounds", config.PairConfig{MinNotional: "500", MaxNotional: "700"}, mmv1.RejectReason_REJECT_REASON_UNSPECIFIED},
+		{"below the notional minimum", config.PairConfig{MinNotional: "1000"}, mmv1.RejectReason_REJECT_REASON_AMOUNT_TOO_SMALL},
+		{"above the notional limit", config.PairConfig{MaxNotional: "100"}, mmv1.RejectReason_REJECT_REASON_AMOUNT_TOO_LARGE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testutil.Config()
+			pair := &cfg.Pairs[0]
+			pair.MinBaseIn, pair.MinQuoteIn = tt.pair.MinBaseIn, tt.pair.MinQuoteIn
+			pair.MinNotional, pair.MaxNotional = tt.pair.MinNotional, tt.pair.MaxNotional
+			handler := newTestHandler(t, testutil.NewFixedRateStrategy(600, 1), cfg)
+
+			msg, err := handler.HandleQuoteRequest(context.Background(), testutil.QuoteRequest())
+			if err != nil {
+				t.Fatalf("HandleQuoteRequest failed: %v", err)
+			}
+			var got mmv1.RejectReason
+			if reject := msg.GetQuoteReject(); reject != nil {
+				got = reject.Reason
+			} else if msg.GetQuoteResponse() == nil {
+				t.Fatalf("message = %v, want quote response or reject", msg)
+			}
+			if got != tt.wantReason {
+				t.Errorf("reject reason = %v, want %v", got, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestHandler_PairMaxAmountIn(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxBaseIn  string
+		maxQuoteIn string
+		wantReject bool
+	}{
+		// The request sells 1 WBNB (base)
+		{"unlimited", "", "", false},
+		{"at the limit", "1", "", false},
+		{"above the limit", "0.5", "", true},
+		{"quote limit only", "", "0.5", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testutil.Config()
+			cfg.Pairs[0].MaxBaseIn = tt.maxBaseIn
+			cfg.Pairs[0].MaxQuoteIn = tt.maxQuoteIn
+			handler := newTestHandler(t, testutil.NewFixedRateStrategy(600, 1), cfg)
+
+			msg, err := handler.HandleQuoteRequest(context.Background(), testutil.QuoteRequest())
+			if err != nil {
+				t.Fatalf("HandleQuoteRequest failed: %v", err)
+			}
+			reject := msg.GetQuoteReject()
+			if (reject != nil) != tt.wantReject {
+				t.Fatalf("message = %v, want reject %v", msg, tt.wantReject)
+			}
+			if reject != nil && reject.Reason != mmv1.RejectReason_REJECT_REASON_AMOUNT_TOO_LARGE {
+				t.Errorf("Reason = %v, want AMOUNT_TOO_LARGE", reject.Reason)
+			}
+		})
+	}
+}
+
+func TestHandler_SignerPool(t *testing.T) {
+	cfg := testutil.Config()
+	domain := cfg.EIP712Domains[0]
+	dm := signer.NewDomainManager()
+	dm.AddPoolDomainWithConfig(domain.ChainID, domain.Name, domain.Version, domain.VerifyingContract)
+	primary, err := signer.NewSignerFromHex(testutil.DefaultPrivKey, dm)
+	if err != nil {
+		t.Fatalf("NewSignerFromHex failed: %v", err)
+	}
+	hot, err := signer.NewSignerFromHex("0x0000000000000000000000000000000000000000000000000000000000000002", dm)
+	if err != nil {
+		t.Fatalf("NewSignerFromHex failed: %v", err)
+	}
+	pool, err := signer.NewPool(primary, []signer.PoolKey{{Name: "hot", Signer: hot}},
+		signer.PoolAssignment{Policy: signer.PolicyPerPair, Pairs: map[string]string{cfg.Pairs[0].PairID: "hot"}})
+	if err != nil {
+		t.Fatalf("NewPool failed: %v", err)
+	}
+	handler := quote.NewHandler(testutil.NewFixedRateStrategy(600, 1), pool, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	msg, err := handler.HandleQuoteRequest(context.Background(), testutil.QuoteRequest())
+	if err != nil {
+		t.Fatalf("HandleQuoteRequest failed: %v", err)
+	}
+	response := msg.GetQuoteResponse()
+	if response == nil {
+		t.Fatalf("message = %v, want quote response", msg)
+	}
+	if want := strings.ToLower(hot.GetAddress().Hex()); response.Order.Signer != want {
+		t.Errorf("Order.Signer = %s, want the assigned key %s", response.Order.Signer, want)
+	}
+	if want := strings.ToLower(primary.GetAddress().Hex()); response.MmId != want {
+		t.Errorf("MmId = %s, want the primary key %s", response.MmId, want)
+	}
+	if rec, _ := handler.Store().Get(testutil.DefaultQuoteID); rec.Signer != hot.GetAddress() {
+		t.Errorf("recorded Signer = %s, want %s", rec.Signer.Hex(), hot.GetAddress().Hex())
+	}
+}
+
+// fakeCoSigner records approval requests and answers with err
+type fakeCoSigner struct {
+	err      error
+	requests []*quote.ApprovalRequest
+}
+
+func (f *fakeCoSigner) Approve(_ context.Context, req *quote.ApprovalRequest) error {
+	f.requests = append(f.requests, req)
+	return f.err
+}
+
+func TestHandler_CoSigning(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold string
+		err       error
+		approvals int
+		rejected  bool
+	}{
+		{"under threshold", "1000", nil, 0, false},
+		{"approved", "500", nil, 1, false},
+		{"not approved", "500", quote.ErrNotApproved, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testutil.Config()
+			cfg.CoSign.Timeout = time.Second
+			cfg.CoSign.Thresholds = map[string]string{cfg.Pairs[0].PairID: tt.threshold}
+			handler := newTestHandler(t, testutil.NewFixedRateStrategy(600, 1), cfg)
+			coSigner := &fakeCoSigner{err: tt.err}
+			handler.SetCoSigner(coSigner)
+
+			msg, err := handler.HandleQuoteRequest(context.Background(), testutil.QuoteRequest())
+			if err != nil {
+				t.Fatalf("HandleQuoteRequest failed: %v", err)
+			}
+			if len(coSigner.requests) != tt.approvals {
+				t.Fatalf("approval requests = %d, want %d", len(coSigner.requests), tt.approvals)
+			}
+			if tt.approvals > 0 {
+				req := coSigner.requests[0]
+				if req.Notional.String() != "600" {
+					t.Errorf("Notional = %s, want 600", req.Notional)
+				}
+				if req.PairID != cfg.Pairs[0].PairID || len(req.Signature) != 65 {
+					t.Errorf("approval request = %+v, want the signed %s quote", req, cfg.Pairs[0].PairID)
+				}
+			}
+			if got := msg.GetQuoteReject() != nil; got != tt.rejected {
+				t.Errorf("rejected = %v, want %v", got, tt.rejected)
+			}
+			if _, stored := handler.Store().Get(testutil.DefaultQuoteID); stored == tt.rejected {
+				t.Errorf("stored = %v, want %v", stored, !tt.rejected)
+			}
+		})
+	}
+}
+
+func TestHandler_SignerDeadlineHorizon(t *testing.T) {
+	for _, clamp := range []bool{false, true} {
+		cfg := testutil.Config()
+		cfg.Signer.ClampDeadline = clamp
+		domain := cfg.EIP712Domains[0]
+		dm := signer.NewDomainManager()
+		dm.AddPoolDomainWithConfig(domain.ChainID, domain.Name, domain.Version, domain.VerifyingContract)
+		s, err := signer.NewSignerFromConfig(&signer.SignerConfig{
+			PrivateKey:         testutil.DefaultPrivKey,
+			MaxDeadlineHorizon: 10 * time.Second,
+		}, dm)
+		if err != nil {
+			t.Fatalf("NewSignerFromConfig failed: %v", err)
+		}
+		handler := quote.NewHandler(testutil.NewFixedRateStrategy(600, 1), s, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		req := testutil.QuoteRequest() // Deadline 30s ahead
+		msg, err := handler.HandleQuoteRequest(context.Background(), req)
+		if err != nil {
+			t.Fatalf("HandleQuoteRequest failed: %v", err)
+		}
+		if !clamp {
+			if reject := msg.GetQuoteReject(); reject == nil || reject.Message != "deadline too far in the future" {
+				t.Errorf("message = %v, want a deadline reject", msg)
+			}
+			continue
+		}
+		response := msg.GetQuoteResponse()
+		if response == nil {
+			t.Fatalf("message = %v, want quote response", msg)
+		}
+		if limit := time.Now().Add(10 * time.Second).Unix(); response.Order.Deadline >= req.Deadline || response.Order.Deadline > limit {
+			t.Errorf("Order.Deadline = %d, want clamped to at most %d", response.Order.Deadline, limit)
+		}
+		if rec, _ := handler.Store().Get(testutil.DefaultQuoteID); rec.Deadline != response.Order.Deadline {
+			t.Errorf("recorded Deadline = %d, want %d", rec.Deadline, response.Order.Deadline)
+		}
+	}
+}
+
+func TestHandler_RejectsInvalidAddresses(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(req *mmv1.QuoteRequest)
+		want   string
+	}{
+		{"short token", func(req *mmv1.QuoteRequest) { req.TokenIn = req.TokenIn[:41] }, "token_in"},
+		{"bad checksum", func(req *mmv1.QuoteRequest) { req.TokenOut = "0x55D398326f99059fF775485246999027B3197955" }, "token_out"},
+		{"zero recipient", func(req *mmv1.QuoteRequest) { req.Recipient = "0x0000000000000000000000000000000000000000" }, "recipient"},
+		{"malformed from", func(req *mmv1.QuoteRequest) { req.From = "0x1234" }, "from"},
+	}
+	handler := newTestHandler(t, testutil.NewFixedRateStrategy(600, 1), testutil.Config())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := testutil.QuoteRequest()
+			tt.modify(req)
+			msg, err := handler.HandleQuoteRequest(context.Background(), req)
+			if err != nil {
+				t.Fatalf("HandleQuoteRequest failed: %v", err)
+			}
+			reject := msg.GetQuoteReject()
+			if reject == nil || !strings.HasPrefix(reject.Message, tt.want+":") {
+				t.Errorf("message = %v, want a %s reject", msg, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_AcceptsAbsentOrZeroFrom(t *testing.T) {
+	handler := newTestHandler(t, testutil.NewFixedRateStrategy(600, 1), testutil.Config())
+	for _, from := range []string{"", "0x0000000000000000000000000000000000000000"} {
+		req := testutil.QuoteRequest()
+		req.From = from
+		msg, err := handler.HandleQuoteRequest(context.Background(), req)
+		if err != nil {
+			t.Fatalf("HandleQuoteRequest failed: %v", err)
+		}
+		if msg.GetQuoteResponse() == nil {
+			t.Errorf("from %q: message = %v, want a quote response", from, msg)
+		}
+	}
+}
+
+func TestHandler_PublishesQuoteEvents(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Pairs[0].MaxBaseIn = "2"
+	handler := newTestHandler(t, testutil.NewFixedRateStrategy(600, 1), cfg)
+	bus := events.NewBus(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	sub := bus.Subscribe("test", 4)
+	handler.SetEventBus(bus)
+
+	req := testutil.QuoteRequest()
+	if _, err := handler.HandleQuoteRequest(context.Background(), req); err != nil {
+		t.Fatalf("HandleQuoteRequest failed: %v", err)
+	}
+	signed := <-sub.Events()
+	rec, ok := signed.Data.(quote.QuoteRecord)
+	if signed.Kind != events.QuoteSigned || !ok || rec.QuoteID != req.QuoteId || signed.PairID != cfg.Pairs[0].PairID {
+		t.Errorf("event = %+v, want quote_signed of %s", signed, req.QuoteId)
+	}
+
+	cfg.Pairs[0].MaxBaseIn = "0.5"
+	handler = newTestHandler(t, testutil.NewFixedRateStrategy(600, 1), cfg)
+	handler.SetEventBus(bus)
+	if _, err := handler.HandleQuoteRequest(context.Background(), req); err != nil {
+		t.Fatalf("HandleQuoteRequest failed: %v", err)
+	}
+	rejected := <-sub.Events()
+	if rejected.Kind != events.QuoteRejected || rejected.Data != mmv1.RejectReason_REJECT_REASON_AMOUNT_TOO_LARGE {
+		t.Errorf("event = %+v, want quote_rejected for AMOUNT_TOO_LARGE", rejected)
+	}
+}
+
+func TestHandler_RateLimitsOrigins(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Quote.RateLimit = config.RateLimitConfig{PerSecond: 0.001, Burst: 1}
+	handler := newTestHandler(t, testutil.NewFixedRateStrategy(600, 1), cfg)
+
+	msg, err := handler.HandleQuoteRequest(context.Background(), testutil.QuoteRequest())
+	if err != nil || msg.GetQuoteResponse() == nil {
+		t.Fatalf("first request = %v, %v, want a response", msg, err)
+	}
+	msg, err = handler.HandleQuoteRequest(context.Background(), testutil.QuoteRequest())
+	if err != nil {
+		t.Fatalf("HandleQuoteRequest failed: %v", err)
+	}
+	if reject := msg.GetQuoteReject(); reject == nil || reject.Reason != mmv1.RejectReason_REJECT_REASON_RATE_LIMITED {
+		t.Fatalf("second request = %v, want REJECT_REASON_RATE_LIMITED", msg)
+	}
+	if got := handler.RateLimitStats().Limited; got != 1 {
+		t.Errorf("Limited = %d, want 1", got)
+	}
+}
+
+func TestHandler_DeadlineSkew(t *testing.T) {
+	now := time.Unix(1735084800, 800*int64(time.Millisecond))
+	req := testutil.QuoteRequest()
+	req.Deadline = now.Unix() - 1 // Passed 0.8s ago by the MM's clock
+
+	for _, tt := range []struct {
+		skew   time.Duration
+		reject bool
+	}{
+		{0, true},
+		{time.Second, false},
+	} {
+		cfg := testutil.Config()
+		cfg.Quote.DeadlineSkew = tt.skew
+		cfg.Quote.BudgetFraction = 0
+		handler := newTestHandler(t, testutil.NewFixedRateStrategy(600, 1), cfg)
+		handler.SetClock(func() time.Time { return now })
+
+		msg, err := handler.HandleQuoteRequest(context.Background(), req)
+		if err != nil {
+			t.Fatalf("HandleQuoteRequest failed: %v", err)
+		}
+		reject := msg.GetQuoteReject()
+		if got := reject != nil && strings.Contains(reject.Message, "deadline already expired"); got != tt.reject {
+			t.Errorf("skew %v: rejected for deadline = %v, want %v (%v)", tt.skew, got, tt.reject, msg)
+		}
+	}
+}
+
+func TestHandler_TunedPairSpreadAndValidDuration(t *testing.T) {
+	quoteOnce := func(tune bool) (*mmv1.QuoteResponse, *mmv1.QuoteRequest) {
+		cfg := testutil.Config()
+		if tune {
+			cfg.EnableTuning().Set(config.TuningChange{ValidDuration: 10 * time.Second, Pairs: map[config.PairRef]config.PairParams{
+				{ChainID: cfg.Pairs[0].ChainID, PairID: cfg.Pairs[0].PairID}: {SpreadBps: 100},
+			}})
+		}
+		s := testutil.NewFakeSigner(common.HexToAddress(testutil.DefaultMMID))
+		handler := quote.NewHandler(testutil.NewFixedRateStrategy(600, 1), s, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		req := testutil.QuoteRequest() // Deadline 30s ahead
+		msg, err := handler.HandleQuoteRequest(context.Background(), req)
+		if err != nil || msg.GetQuoteResponse() == nil {
+			t.Fatalf("HandleQuoteRequest = %v, %v; want a quote response", msg, err)
+		}
+		return msg.GetQuoteResponse(), req
+	}
+
+	base, req := quoteOnce(false)
+	if base.Order.Deadline != req.Deadline {
+		t.Errorf("untuned Order.Deadline = %d, want the requested %d", base.Order.Deadline, req.Deadline)
+	}
+	tuned, req := quoteOnce(true)
+
+	// 1% less output than the untuned quote
+	baseOut, _ := new(big.Int).SetString(base.Order.AmountOut, 10)
+	want := new(big.Int).Quo(new(big.Int).Mul(baseOut, big.NewInt(99)), big.NewInt(100))
+	if tuned.Order.AmountOut != want.String() {
+		t.Errorf("tuned AmountOut = %s, want %s", tuned.Order.AmountOut, want)
+	}
+	if limit := time.Now().Add(10 * time.Second).Unix(); tuned.Order.Deadline > limit || tuned.Order.Deadline >= req.Deadline {
+		t.Errorf("tuned Order.Deadline = %d, want at most %d", tuned.Order.Deadline, limit)
+	}
+}
+
+func TestHandler_SignsRequestedDeadlineByDefault(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Quote.ValidDuration = 0
+	s := testutil.NewFakeSigner(common.HexToAddress(testutil.DefaultMMID))
+	handler := quote.NewHandler(testutil.NewFixedRateStrategy(600, 1), s, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	req := testutil.QuoteRequest()
+	req.Deadline = time.Now().Add(10 * time.Minute).Unix() // Far beyond any default cap
+	msg, err := handler.HandleQuoteRequest(context.Background(), req)
+	if err != nil || msg.GetQuoteResponse() == nil {
+		t.Fatalf("HandleQuoteRequest = %v, %v; want a quote response", msg, err)
+	}
+	if got := msg.GetQuoteResponse().Order.Deadline; got != req.Deadline {
+		t.Errorf("Order.Deadline = %d, want the requested %d", got, req.Deadline)
+	}
+}
+
+func TestHandler_PausedChainRejects(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Chains = []config.ChainConfig{{ChainID: testutil.DefaultChainID, PauseQuoting: true}}
+	s := testutil.NewFakeSigner(common.HexToAddress(testutil.DefaultMMID))
+	handler := quote.NewHandler(testutil.NewFixedRateStrategy(600, 1), s, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	msg, err := handler.HandleQuoteRequest(context.Background(), testutil.QuoteRequest())
+	if err != nil {
+		t.Fatalf("HandleQuoteRequest failed: %v", err)
+	}
+	reject := msg.GetQuoteReject()
+	if reject == nil || reject.Reason != mmv1.RejectReason_REJECT_REASON_PAIR_NOT_SUPPORTED || !strings.Contains(reject.Message, "quoting paused") {
+		t.Fatalf("message = %v, want a quoting paused reject", msg)
+	}
+
+	// Resumed through the admin API's tuning
+	cfg.EnableTuning().Set(config.TuningChange{Chains: map[uint64]config.ChainFlags{testutil.DefaultChainID: {}}})
+	if msg, _ := handler.HandleQuoteRequest(context.Background(), testutil.QuoteRequest()); msg.GetQuoteResponse() == nil {
+		t.Errorf("message = %v after resuming, want a quote response", msg)
+	}
+}
+
+func TestHandler_NonceGuard(t *testing.T) {
+	handler := newTestHandler(t, testutil.NewFixedRateStrategy(600, 1), testutil.Config())
+	nonces, err := nonce.NewManager(nonce.ModeGuard, nonce.ScopeChain, nil)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	handler.SetNonces(nonces)
+
+	req := testutil.QuoteRequest()
+	if msg, err := handler.HandleQuoteRequest(context.Background(), req); err != nil || msg.GetQuoteResponse() == nil {
+		t.Fatalf("first request = %v, %v, want a response", msg, err)
+	}
+	req.QuoteId = "replayed"
+	msg, err := handler.HandleQuoteRequest(context.Background(), req)
+	if err != nil {
+		t.Fatalf("HandleQuoteRequest failed: %v", err)
+	}
+	if reject := msg.GetQuoteReject(); reject == nil || reject.Message != "nonce refused" {
+		t.Fatalf("request with a signed nonce = %v, want a nonce refused reject", msg)
+	}
+	if stats := nonces.Stats(); len(stats) != 1 || stats[0].Mark != req.Nonce || stats[0].Outstanding != 1 {
+		t.Errorf("Stats() = %+v, want one outstanding nonce %s", stats, req.Nonce)
+	}
+}
+
+func TestHandler_NonceIssue(t *testing.T) {
+	handler := newTestHandler(t, testutil.NewFixedRateStrategy(600, 1), testutil.Config())
+	nonces, _ := nonce.NewManager(nonce.ModeIssue, nonce.ScopeChain, nil)
+	handler.SetNonces(nonces)
+
+	var issued []string
+	for _, id := range []string{"q1", "q2"} {
+		req := testutil.QuoteRequest()
+		req.QuoteId = id
+		if msg, err := handler.HandleQuoteRequest(context.Background(), req); err != nil || msg.GetQuoteResponse() == nil {
+			t.Fatalf("request %s = %v, %v, want a response", id, msg, err)
+		}
+		rec, _ := handler.Store().Get(id)
+		issued = append(issued, rec.Nonce)
+	}
+	first, _ := new(big.Int).SetString(issued[0], 10)
+	second, _ := new(big.Int).SetString(issued[1], 10)
+	if first == nil || second == nil || first.Cmp(big.NewInt(1)) <= 0 || second.Cmp(first) <= 0 {
+		t.Errorf("issued nonces = %v, want increasing nonces ignoring the request's", issued)
+	}
+}
+
+func TestHandler_V3PoolHedgeExtraData(t *testing.T) {
+	cfg := testutil.Config()
+	pool := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	cfg.Pairs[0].ExtraData = signer.ExtraDataV3PoolHedge
+	cfg.Pairs[0].HedgePool = pool.Hex()
+	handler := newTestHandler(t, testutil.NewFixedRateStrategy(600, 1), cfg)
+
+	msg, err := handler.HandleQuoteRequest(context.Background(), testutil.QuoteRequest())
+	if err != nil || msg.GetQuoteResponse() == nil {
+		t.Fatalf("HandleQuoteRequest = %v, %v, want a response", msg, err)
+	}
+	order := msg.GetQuoteResponse().Order
+	tokenIn, tokenOut := common.HexToAddress(testutil.DefaultTokenIn), common.HexToAddress(testutil.DefaultTokenOut)
+	amountIn, _ := new(big.Int).SetString(order.AmountIn, 10)
+	amountOut, _ := new(big.Int).SetString(order.AmountOut, 10)
+	want, err := signer.EncodeExtraData(signer.V3PoolHedgeParams(pool, common.HexToAddress(testutil.DefaultMMID), tokenIn, tokenOut, amountIn, amountOut))
+	if err != nil {
+		t.Fatalf("EncodeExtraData failed: %v", err)
+	}
+	if !bytes.Equal(order.ExtraData, want) {
+		t.Errorf("extraData = %x, want %x", order.ExtraData, want)
+	}
+}
@@ -0,0 +1,65 @@
+package quote
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// MidStrategy quotes the pairs of mid.pairs at their liquidity-weighted mid
+// Feed weighs the pools and venues of each pair (see depth.MidFeed); the pair's spread is
+// taken from the output. Other pairs are passed to Next.
+type MidStrategy struct {
+	Next QuoteStrategy // Required
+	Feed PriceFeed     // Required
+
+	cfg     *config.Config
+	spreads map[config.PairRef]uint32 // Spread of each weighted pair
+}
+
+// NewMidStrategy creates a weighted-mid strategy for the pairs of cfg.Mid
+func NewMidStrategy(next QuoteStrategy, feed PriceFeed, cfg *config.Config) *MidStrategy {
+	spreads := make(map[config.PairRef]uint32, len(cfg.Mid.Pairs))
+	for _, mp := range cfg.Mid.Pairs {
+		spreads[config.PairRef{ChainID: mp.ChainID, PairID: mp.PairID}] = mp.SpreadBps
+	}
+	return &MidStrategy{Next: next, Feed: feed, cfg: cfg, spreads: spreads}
+}
+
+// CalculateQuote quotes weighted pairs from the feed's mid and passes other pairs to Next
+func (s *MidStrategy) CalculateQuote(ctx context.Context, params *QuoteParams) (*QuoteResult, error) {
+	pair := s.cfg.GetPairConfigByAddress(params.ChainID, params.TokenIn, params.TokenOut)
+	if pair == nil {
+		return s.Next.CalculateQuote(ctx, params)
+	}
+	spreadBps, ok := s.spreads[config.PairRef{ChainID: pair.ChainID, PairID: pair.PairID}]
+	if !ok {
+		return s.Next.CalculateQuote(ctx, params)
+	}
+
+	price, _, err := s.Feed.Price(ctx, params.ChainID, params.TokenIn, params.TokenOut)
+	if err != nil {
+		return nil, fmt.Errorf("weighted mid for %s: %w", pair.PairID, err)
+	}
+
+	// amountOut = amountIn * price * (10000 - spread) / 10000, truncated once
+	amountOut := price.MulInt(params.AmountIn).Mul(decimal.New(10000-int64(spreadBps), -4)).Int()
+	if amountOut.Sign() <= 0 {
+		return nil, fmt.Errorf("calculated amount out is zero or negative")
+	}
+
+	result := NewQuoteResult(amountOut)
+	result.ExecutionPrice = price
+	result.PriceImpact = float64(spreadBps) / 100
+	result.Info = QuoteInfo{
+		FeeBps:      spreadBps,
+		Route:       []common.Address{params.TokenIn, params.TokenOut},
+		PriceSource: "mid",
+		MidPrice:    price,
+	}
+	return result, nil
+}
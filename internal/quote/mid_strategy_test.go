@@ -0,0 +1,48 @@
+package quote
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+func TestMidStrategy_QuotesWeightedPairs(t *testing.T) {
+	cfg := &config.Config{
+		Pairs: []config.PairConfig{
+			{ChainID: 56, PairID: "WBNB-USDC", BaseToken: stableWBNB.Hex(), QuoteToken: stableUSDC.Hex()},
+			{ChainID: 56, PairID: "WBNB-USDT", BaseToken: stableWBNB.Hex(), QuoteToken: stableUSDT.Hex()},
+		},
+		Mid: config.MidConfig{Pairs: []config.MidPair{{ChainID: 56, PairID: "WBNB-USDC", SpreadBps: 20}}},
+	}
+	cfg.BuildIndex()
+	next := NewMockStrategyWithSeed(0, 1)
+	next.SetPrice(56, stableWBNB, stableUSDT, decimal.NewFromInt(600))
+	feed := fakeFeed{
+		{stableWBNB, stableUSDC}: {price: decimal.NewFromInt(605)},
+	}
+	s := NewMidStrategy(next, feed, cfg)
+
+	result, err := s.CalculateQuote(context.Background(), &QuoteParams{ChainID: 56, TokenIn: stableWBNB, TokenOut: stableUSDC, AmountIn: big.NewInt(1000)})
+	if err != nil {
+		t.Fatalf("CalculateQuote failed: %v", err)
+	}
+	// 1000 * 605 * (1 - 0.002)
+	if result.AmountOut.Int64() != 603790 || result.Info.PriceSource != "mid" || result.Info.FeeBps != 20 {
+		t.Errorf("result = %s, info %+v", result.AmountOut, result.Info)
+	}
+
+	// Other pairs are priced by Next
+	result, err = s.CalculateQuote(context.Background(), &QuoteParams{ChainID: 56, TokenIn: stableWBNB, TokenOut: stableUSDT, AmountIn: big.NewInt(1000)})
+	if err != nil || result.Info.PriceSource != "mock" {
+		t.Errorf("WBNB-USDT: %+v, %v", result, err)
+	}
+
+	// Without a mid there is no quote, not a quote at Next's price
+	s.Feed = fakeFeed{}
+	if _, err := s.CalculateQuote(context.Background(), &QuoteParams{ChainID: 56, TokenIn: stableWBNB, TokenOut: stableUSDC, AmountIn: big.NewInt(1000)}); err == nil {
+		t.Errorf("err = %v, want a missing mid error", err)
+	}
+}
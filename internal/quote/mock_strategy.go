@@ -3,10 +3,16 @@ package quote
 import (
 	"context"
 	"fmt"
-	"math/big"
+	"hash/fnv"
+	"math"
+	"math/rand"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
 )
 
 // MockStrategy is a mock quote strategy
@@ -19,19 +25,35 @@ type MockStrategy struct {
 	// Prices is the mock price configuration
 	// key: "chainId:tokenIn:tokenOut" (lowercase addresses)
 	// value: price (outputToken/inputToken)
-	Prices map[string]*big.Float
+	// Derived prices and amounts round toward zero, so rounding never makes the MM pay more
+	Prices map[string]decimal.Decimal
+
+	// JitterBps is the maximum random price deviation (basis points), 0 = fixed prices
+	// The deviation is a function of (Seed, pair, Now second), so a run replays exactly
+	JitterBps uint32
+	Seed      int64
+	Now       func() time.Time
+
+	spreadMultiplier atomic.Uint64 // math.Float64bits of the schedule's spread multiplier, 0 = 1
 }
 
-// NewMockStrategy creates a mock quote strategy
+// NewMockStrategy creates a mock quote strategy with a time-based seed
 func NewMockStrategy(spreadBps uint32) *MockStrategy {
+	return NewMockStrategyWithSeed(spreadBps, time.Now().UnixNano())
+}
+
+// NewMockStrategyWithSeed creates a mock quote strategy with a fixed seed
+func NewMockStrategyWithSeed(spreadBps uint32, seed int64) *MockStrategy {
 	return &MockStrategy{
 		SpreadBps: spreadBps,
-		Prices:    make(map[string]*big.Float),
+		Prices:    make(map[string]decimal.Decimal),
+		Seed:      seed,
+		Now:       time.Now,
 	}
 }
 
 // SetPrice sets a mock price
-func (s *MockStrategy) SetPrice(chainID uint64, tokenIn, tokenOut common.Address, price *big.Float) {
+func (s *MockStrategy) SetPrice(chainID uint64, tokenIn, tokenOut common.Address, price decimal.Decimal) {
 	key := s.buildPriceKey(chainID, tokenIn, tokenOut)
 	s.Prices[key] = price
 }
@@ -47,24 +69,18 @@ func (s *MockStrategy) buildPriceKey(chainID uint64, tokenIn, tokenOut common.Ad
 // CalculateQuote calculates a mock quote
 func (s *MockStrategy) CalculateQuote(ctx context.Context, params *QuoteParams) (*QuoteResult, error) {
 	// Look up price
-	price := s.getPrice(params.ChainID, params.TokenIn, params.TokenOut)
-	if price == nil {
+	price, ok := s.getPrice(params.ChainID, params.TokenIn, params.TokenOut)
+	if !ok {
 		return nil, fmt.Errorf("price not found for %s -> %s on chain %d",
 			params.TokenIn.Hex(), params.TokenOut.Hex(), params.ChainID)
 	}
 
-	// Calculate output amount
-	// amountOut = amountIn * price * (1 - spread/10000)
-	amountInFloat := new(big.Float).SetInt(params.AmountIn)
-	amountOutFloat := new(big.Float).Mul(amountInFloat, price)
-
-	// Apply spread
-	spreadFactor := new(big.Float).SetFloat64(float64(10000-s.SpreadBps) / 10000)
-	amountOutFloat.Mul(amountOutFloat, spreadFactor)
+	price = s.applyJitter(price, params)
 
-	// Convert to integer
-	amountOut := new(big.Int)
-	amountOutFloat.Int(amountOut)
+	// Calculate output amount
+	// amountOut = amountIn * price * (10000 - spread) / 10000, exact until the final truncation
+	spreadBps := s.spreadBps()
+	amountOut := price.MulInt(params.AmountIn).Mul(decimal.New(10000-int64(spreadBps), -4)).Int()
 
 	if amountOut.Sign() <= 0 {
 		return nil, fmt.Errorf("calculated amount out is zero or negative")
@@ -73,45 +89,106 @@ func (s *MockStrategy) CalculateQuote(ctx context.Context, params *QuoteParams)
 	// Build result
 	result := NewQuoteResult(amountOut)
 	result.ExecutionPrice = price
-	result.PriceImpact = float64(s.SpreadBps) / 100 // Simplified: spread equals price impact
+	result.PriceImpact = float64(spreadBps) / 100 // Simplified: spread equals price impact
+	result.Info = QuoteInfo{
+		FeeBps:      spreadBps,
+		Route:       []common.Address{params.TokenIn, params.TokenOut},
+		PriceSource: "mock",
+		MidPrice:    price,
+	}
 
 	return result, nil
 }
 
+// SetMultipliers scales SpreadBps by spread (see schedule.Target)
+// The mock quotes any size, so size is ignored
+func (s *MockStrategy) SetMultipliers(spread, size float64) {
+	s.spreadMultiplier.Store(math.Float64bits(spread))
+}
+
+// spreadBps returns SpreadBps scaled by the schedule, below 10000
+func (s *MockStrategy) spreadBps() uint32 {
+	bits := s.spreadMultiplier.Load()
+	if bits == 0 {
+		return s.SpreadBps
+	}
+	return uint32(math.Min(math.Round(float64(s.SpreadBps)*math.Float64frombits(bits)), 9999))
+}
+
+// Price returns the mock price of a pair, with jitter, observed now
+func (s *MockStrategy) Price(ctx context.Context, chainID uint64, tokenIn, tokenOut common.Address) (decimal.Decimal, time.Time, error) {
+	price, ok := s.getPrice(chainID, tokenIn, tokenOut)
+	if !ok {
+		return decimal.Zero, time.Time{}, fmt.Errorf("price not found for %s -> %s on chain %d",
+			tokenIn.Hex(), tokenOut.Hex(), chainID)
+	}
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	price = s.applyJitter(price, &QuoteParams{ChainID: chainID, TokenIn: tokenIn, TokenOut: tokenOut})
+	return price, now(), nil
+}
+
+// applyJitter moves the price by up to ±JitterBps, deterministically for a given seed, pair and second
+func (s *MockStrategy) applyJitter(price decimal.Decimal, params *QuoteParams) decimal.Decimal {
+	if s.JitterBps == 0 {
+		return price
+	}
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d:%s", s.Seed, s.buildPriceKey(params.ChainID, params.TokenIn, params.TokenOut))
+	fmt.Fprintf(h, ":%d", now().Unix())
+	rng := rand.New(rand.NewSource(int64(h.Sum64())))
+
+	// factor = (10000 + jitter) / 10000, jitter in [-JitterBps, JitterBps]
+	jitter := rng.Int63n(2*int64(s.JitterBps)+1) - int64(s.JitterBps)
+	return price.Mul(decimal.New(10000+jitter, -4))
+}
+
 // getPrice gets price (supports bidirectional lookup)
-func (s *MockStrategy) getPrice(chainID uint64, tokenIn, tokenOut common.Address) *big.Float {
+func (s *MockStrategy) getPrice(chainID uint64, tokenIn, tokenOut common.Address) (decimal.Decimal, bool) {
 	// Forward lookup
 	key := s.buildPriceKey(chainID, tokenIn, tokenOut)
 	if price, ok := s.Prices[key]; ok {
-		return price
+		return price, true
 	}
 
 	// Reverse lookup
 	reverseKey := s.buildPriceKey(chainID, tokenOut, tokenIn)
-	if reversePrice, ok := s.Prices[reverseKey]; ok {
-		// Return reciprocal
-		one := big.NewFloat(1)
-		return new(big.Float).Quo(one, reversePrice)
+	if reversePrice, ok := s.Prices[reverseKey]; ok && reversePrice.Sign() > 0 {
+		// Return reciprocal (truncated toward zero)
+		return decimal.NewFromInt(1).Quo(reversePrice), true
 	}
 
-	return nil
+	return decimal.Zero, false
 }
 
 // DefaultMockStrategy creates a mock strategy with default prices
 func DefaultMockStrategy() *MockStrategy {
-	strategy := NewMockStrategy(50) // 0.5% spread
+	return DefaultMockStrategyWithSeed(time.Now().UnixNano())
+}
+
+// DefaultMockStrategyWithSeed creates a mock strategy with default prices and a fixed seed
+func DefaultMockStrategyWithSeed(seed int64) *MockStrategy {
+	strategy := NewMockStrategyWithSeed(50, seed) // 0.5% spread
 
 	// BSC: WBNB/USDT = 600 USDT
 	strategy.SetPrice(56,
 		common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c"), // WBNB
 		common.HexToAddress("0x55d398326f99059fF775485246999027B3197955"), // USDT
-		big.NewFloat(600))
+		decimal.NewFromInt(600))
 
 	// Base: WETH/USDC = 3500 USDC
 	strategy.SetPrice(8453,
 		common.HexToAddress("0x4200000000000000000000000000000000000006"), // WETH
 		common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"), // USDC
-		big.NewFloat(3500))
+		decimal.NewFromInt(3500))
 
 	return strategy
 }
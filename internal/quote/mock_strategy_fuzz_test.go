@@ -0,0 +1,63 @@
+package quote
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// FuzzMockStrategy_CalculateQuote checks the amount math for overflow and precision loss
+func FuzzMockStrategy_CalculateQuote(f *testing.F) {
+	f.Add([]byte{0x0d, 0xe0, 0xb6, 0xb3, 0xa7, 0x64, 0x00, 0x00}, 600.0, uint32(50), false)
+	f.Add([]byte{0x01}, 3.4e-9, uint32(0), true)
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 1e30, uint32(9999), false)
+
+	tokenA := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	tokenB := common.HexToAddress("0x0000000000000000000000000000000000000002")
+
+	f.Fuzz(func(t *testing.T, amount []byte, price float64, spreadBps uint32, reverse bool) {
+		if math.IsNaN(price) || math.IsInf(price, 0) || price <= 0 || spreadBps > 10000 {
+			return
+		}
+		amountIn := new(big.Int).SetBytes(amount)
+		if amountIn.Sign() == 0 {
+			return
+		}
+
+		s := NewMockStrategy(spreadBps)
+		p := decimal.NewFromFloat(price)
+		if p.IsZero() {
+			return
+		}
+		s.SetPrice(1, tokenA, tokenB, p)
+		params := &QuoteParams{ChainID: 1, TokenIn: tokenA, TokenOut: tokenB, AmountIn: amountIn}
+		effective := p.Rat()
+		if reverse {
+			params.TokenIn, params.TokenOut = tokenB, tokenA
+			effective.Inv(effective)
+		}
+
+		result, err := s.CalculateQuote(context.Background(), params)
+		if err != nil {
+			return
+		}
+		if result.AmountOut.Sign() <= 0 {
+			t.Fatalf("AmountOut = %v, want positive", result.AmountOut)
+		}
+		if result.AmountOutMinimum.Cmp(result.AmountOut) > 0 {
+			t.Fatalf("AmountOutMinimum %v > AmountOut %v", result.AmountOutMinimum, result.AmountOut)
+		}
+
+		// amountOut must never exceed amountIn * price (the MM never pays more than its price)
+		upper := new(big.Rat).SetInt(amountIn)
+		upper.Mul(upper, effective)
+		if new(big.Rat).SetInt(result.AmountOut).Cmp(upper) > 0 {
+			t.Fatalf("AmountOut %v exceeds amountIn*price %s", result.AmountOut, upper.FloatString(40))
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package quote
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestMockStrategy_Jitter(t *testing.T) {
+	wbnb := common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+	usdt := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	params := &QuoteParams{ChainID: 56, TokenIn: wbnb, TokenOut: usdt, AmountIn: big.NewInt(1e18)}
+	clock := time.Unix(1735084800, 0)
+
+	quoteWith := func(seed int64, jitterBps uint32) *big.Int {
+		s := DefaultMockStrategyWithSeed(seed)
+		s.JitterBps = jitterBps
+		s.Now = func() time.Time { return clock }
+		result, err := s.CalculateQuote(context.Background(), params)
+		if err != nil {
+			t.Fatalf("CalculateQuote failed: %v", err)
+		}
+		return result.AmountOut
+	}
+
+	// 1 WBNB * 600 * 0.995
+	fixed := quoteWith(1, 0)
+	want, _ := new(big.Int).SetString("597000000000000000000", 10)
+	if fixed.Cmp(want) != 0 {
+		t.Errorf("AmountOut = %v, want %v", fixed, want)
+	}
+
+	a, b := quoteWith(7, 100), quoteWith(7, 100)
+	if a.Cmp(b) != 0 {
+		t.Errorf("AmountOut = %v and %v for the same seed and clock", a, b)
+	}
+
+	// Within ±1%
+	low := new(big.Int).Div(new(big.Int).Mul(fixed, big.NewInt(99)), big.NewInt(100))
+	high := new(big.Int).Div(new(big.Int).Mul(fixed, big.NewInt(101)), big.NewInt(100))
+	if a.Cmp(low) < 0 || a.Cmp(high) > 0 {
+		t.Errorf("AmountOut = %v, want within [%v, %v]", a, low, high)
+	}
+}
+
+func TestMockStrategy_SpreadMultiplier(t *testing.T) {
+	wbnb := common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+	usdt := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	params := &QuoteParams{ChainID: 56, TokenIn: wbnb, TokenOut: usdt, AmountIn: big.NewInt(1e18)}
+	s := DefaultMockStrategyWithSeed(1)
+
+	// 50 bps doubled: 1 WBNB * 600 * 0.99
+	s.SetMultipliers(2, 0.5)
+	result, err := s.CalculateQuote(context.Background(), params)
+	if err != nil {
+		t.Fatalf("CalculateQuote failed: %v", err)
+	}
+	want, _ := new(big.Int).SetString("594000000000000000000", 10)
+	if result.AmountOut.Cmp(want) != 0 {
+		t.Errorf("AmountOut = %v, want %v", result.AmountOut, want)
+	}
+}
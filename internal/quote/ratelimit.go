@@ -0,0 +1,128 @@
+package quote
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+)
+
+// maxOrigins is the number of tracked origins above which idle ones are dropped
+const maxOrigins = 10000
+
+// Verdict is the rate limiter's decision on a quote request
+type Verdict int
+
+const (
+	Allowed     Verdict = iota // Within the origin's rate
+	Limited                    // Over the origin's rate
+	NewlyBanned                // Over the rate once too often; the origin is banned from now on
+	Banned                     // The origin is banned
+)
+
+// RateLimitStats summarizes the rate limiter
+type RateLimitStats struct {
+	Limited uint64 // Requests rejected over their origin's rate, bans included
+	Bans    uint64 // Bans imposed
+	Banned  int    // Origins banned now
+}
+
+// RateLimiter limits quote requests per origin with token buckets and temporary bans
+// Spam is rejected before the strategy and the signer see it, so it neither loads them nor
+// pollutes the pricing telemetry. A nil RateLimiter allows every request.
+type RateLimiter struct {
+	cfg config.RateLimitConfig
+
+	mu      sync.Mutex
+	origins map[string]*origin
+	limited uint64
+	bans    uint64
+}
+
+// origin is the rate limiting state of one requesting identity
+type origin struct {
+	tokens      float64
+	last        time.Time
+	strikes     int       // Limited requests since strikeStart
+	strikeStart time.Time // Start of the current ban window
+	bannedUntil time.Time
+}
+
+// NewRateLimiter creates a rate limiter, nil if cfg sets no rate
+func NewRateLimiter(cfg config.RateLimitConfig) *RateLimiter {
+	if cfg.PerSecond <= 0 {
+		return nil
+	}
+	return &RateLimiter{cfg: cfg, origins: make(map[string]*origin)}
+}
+
+// Allow charges a request of key at now and returns the verdict
+func (l *RateLimiter) Allow(key string, now time.Time) Verdict {
+	if l == nil {
+		return Allowed
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	o, ok := l.origins[key]
+	if !ok {
+		if len(l.origins) >= maxOrigins {
+			l.prune(now)
+		}
+		o = &origin{tokens: float64(l.cfg.Burst), last: now}
+		l.origins[key] = o
+	}
+	if now.Before(o.bannedUntil) {
+		l.limited++
+		return Banned
+	}
+
+	o.tokens = min(float64(l.cfg.Burst), o.tokens+now.Sub(o.last).Seconds()*l.cfg.PerSecond)
+	o.last = now
+	if o.tokens >= 1 {
+		o.tokens--
+		return Allowed
+	}
+	l.limited++
+
+	if l.cfg.BanAfter <= 0 {
+		return Limited
+	}
+	if now.Sub(o.strikeStart) > l.cfg.BanWindow {
+		o.strikes, o.strikeStart = 0, now
+	}
+	o.strikes++
+	if o.strikes < l.cfg.BanAfter {
+		return Limited
+	}
+	o.strikes = 0
+	o.bannedUntil = now.Add(l.cfg.BanDuration)
+	l.bans++
+	return NewlyBanned
+}
+
+// prune drops origins that are neither banned nor short of tokens; the caller must hold l.mu
+func (l *RateLimiter) prune(now time.Time) {
+	for key, o := range l.origins {
+		refilled := o.tokens + now.Sub(o.last).Seconds()*l.cfg.PerSecond
+		if !now.Before(o.bannedUntil) && refilled >= float64(l.cfg.Burst) {
+			delete(l.origins, key)
+		}
+	}
+}
+
+// Stats returns the rate limiter counters at now
+func (l *RateLimiter) Stats(now time.Time) RateLimitStats {
+	if l == nil {
+		return RateLimitStats{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := RateLimitStats{Limited: l.limited, Bans: l.bans}
+	for _, o := range l.origins {
+		if now.Before(o.bannedUntil) {
+			stats.Banned++
+		}
+	}
+	return stats
+}
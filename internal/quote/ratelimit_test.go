@@ -0,0 +1,66 @@
+package quote
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+)
+
+func TestRateLimiter_TokenBucket(t *testing.T) {
+	l := NewRateLimiter(config.RateLimitConfig{PerSecond: 2, Burst: 2})
+	now := time.Unix(1700000000, 0)
+
+	for i := 0; i < 2; i++ {
+		if got := l.Allow("a", now); got != Allowed {
+			t.Fatalf("request %d = %v, want Allowed", i, got)
+		}
+	}
+	if got := l.Allow("a", now); got != Limited {
+		t.Errorf("request over burst = %v, want Limited", got)
+	}
+	if got := l.Allow("b", now); got != Allowed {
+		t.Errorf("other origin = %v, want Allowed", got)
+	}
+	if got := l.Allow("a", now.Add(500*time.Millisecond)); got != Allowed {
+		t.Errorf("request after refill = %v, want Allowed", got)
+	}
+	if stats := l.Stats(now); stats.Limited != 1 || stats.Bans != 0 {
+		t.Errorf("Stats() = %+v, want 1 limited, no bans", stats)
+	}
+}
+
+func TestRateLimiter_Ban(t *testing.T) {
+	l := NewRateLimiter(config.RateLimitConfig{PerSecond: 1, Burst: 1, BanAfter: 3, BanWindow: time.Minute, BanDuration: time.Minute})
+	now := time.Unix(1700000000, 0)
+
+	l.Allow("a", now)
+	for i := 0; i < 2; i++ {
+		if got := l.Allow("a", now); got != Limited {
+			t.Fatalf("strike %d = %v, want Limited", i, got)
+		}
+	}
+	if got := l.Allow("a", now); got != NewlyBanned {
+		t.Fatalf("third strike = %v, want NewlyBanned", got)
+	}
+	// Refilled tokens do not lift a ban
+	if got := l.Allow("a", now.Add(30*time.Second)); got != Banned {
+		t.Errorf("request during ban = %v, want Banned", got)
+	}
+	if stats := l.Stats(now); stats.Bans != 1 || stats.Banned != 1 {
+		t.Errorf("Stats() = %+v, want 1 ban, 1 banned", stats)
+	}
+	if got := l.Allow("a", now.Add(time.Minute)); got != Allowed {
+		t.Errorf("request after ban = %v, want Allowed", got)
+	}
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	l := NewRateLimiter(config.RateLimitConfig{})
+	if l != nil {
+		t.Fatal("NewRateLimiter(no rate) != nil")
+	}
+	if got := l.Allow("a", time.Now()); got != Allowed {
+		t.Errorf("nil limiter = %v, want Allowed", got)
+	}
+}
@@ -0,0 +1,162 @@
+package quote
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Revocation is a signed quote the operator has revoked
+// A signed quote cannot be recalled from the taker; until its nonce is consumed on-chain or its
+// deadline passes it may still settle. The revocation list makes such fills visible.
+type Revocation struct {
+	Nonce   string
+	QuoteID string
+	Reason  string
+}
+
+// NonceCanceller consumes the nonce of a revoked quote on-chain, so it can no longer settle
+// The RFQ Manager has no cancellation entry point yet; a RevocationList without one only alerts.
+type NonceCanceller interface {
+	CancelNonce(ctx context.Context, rev Revocation) error
+}
+
+// RevocationList is the local list of revoked quotes
+// A quote is revoked when its nonce or its quote ID is listed.
+type RevocationList struct {
+	mu        sync.RWMutex
+	byNonce   map[string]Revocation
+	byQuoteID map[string]Revocation
+	cancelled map[string]bool // Nonces consumed on-chain
+	canceller NonceCanceller
+	logger    *slog.Logger
+}
+
+// NewRevocationList creates an empty revocation list
+func NewRevocationList(logger *slog.Logger) *RevocationList {
+	return &RevocationList{
+		byNonce:   make(map[string]Revocation),
+		byQuoteID: make(map[string]Revocation),
+		cancelled: make(map[string]bool),
+		logger:    logger.With("component", "RevocationList"),
+	}
+}
+
+// SetCanceller sets the on-chain nonce canceller used by Cancel
+func (l *RevocationList) SetCanceller(c NonceCanceller) {
+	l.mu.Lock()
+	l.canceller = c
+	l.mu.Unlock()
+}
+
+// Set replaces the list with revs and returns the entries that were not listed before
+func (l *RevocationList) Set(revs []Revocation) []Revocation {
+	byNonce := make(map[string]Revocation, len(revs))
+	byQuoteID := make(map[string]Revocation, len(revs))
+	for _, rev := range revs {
+		byNonce[rev.Nonce] = rev
+		byQuoteID[rev.QuoteID] = rev
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var added []Revocation
+	for _, rev := range revs {
+		if _, ok := l.byNonce[rev.Nonce]; !ok {
+			added = append(added, rev)
+		}
+	}
+	l.byNonce, l.byQuoteID = byNonce, byQuoteID
+	return added
+}
+
+// Revoked returns the revocation of a quote, false if it is not revoked
+func (l *RevocationList) Revoked(nonce, quoteID string) (Revocation, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if rev, ok := l.byNonce[nonce]; ok {
+		return rev, true
+	}
+	rev, ok := l.byQuoteID[quoteID]
+	return rev, ok
+}
+
+// Len returns the number of revoked quotes
+func (l *RevocationList) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.byNonce)
+}
+
+// OnClose alerts when a revoked quote is filled; chain it into the Store's CloseHandler
+func (l *RevocationList) OnClose(rec QuoteRecord) {
+	if rec.State != QuoteStateFilled {
+		return
+	}
+	if rev, ok := l.Revoked(rec.Nonce, rec.QuoteID); ok {
+		l.logger.Error("Revoked quote filled",
+			"quoteId", rec.QuoteID,
+			"nonce", rec.Nonce,
+			"chainId", rec.ChainID,
+			"signer", rec.Signer.Hex(),
+			"reason", rev.Reason)
+	}
+}
+
+// Cancel consumes the nonces of revoked quotes that are not cancelled yet
+// Does nothing without a canceller; failed cancellations are retried by the next call.
+func (l *RevocationList) Cancel(ctx context.Context) {
+	l.mu.RLock()
+	canceller := l.canceller
+	var pending []Revocation
+	for nonce, rev := range l.byNonce {
+		if !l.cancelled[nonce] {
+			pending = append(pending, rev)
+		}
+	}
+	l.mu.RUnlock()
+	if canceller == nil {
+		return
+	}
+
+	for _, rev := range pending {
+		if err := canceller.CancelNonce(ctx, rev); err != nil {
+			l.logger.Warn("Failed to cancel revoked nonce", "nonce", rev.Nonce, "quoteId", rev.QuoteID, "error", err)
+			continue
+		}
+		l.logger.Info("Revoked nonce cancelled", "nonce", rev.Nonce, "quoteId", rev.QuoteID)
+		l.mu.Lock()
+		l.cancelled[rev.Nonce] = true
+		l.mu.Unlock()
+	}
+}
+
+// ParseRevocations reads "<nonce> <quoteId> [reason]" lines, skipping blank lines and # comments
+func ParseRevocations(r io.Reader) ([]Revocation, error) {
+	var revs []Revocation
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: want \"<nonce> <quoteId> [reason]\"", n)
+		}
+		nonce, err := parseUint256(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid nonce %q", n, fields[0])
+		}
+		revs = append(revs, Revocation{
+			Nonce:   nonce.String(),
+			QuoteID: fields[1],
+			Reason:  strings.Join(fields[2:], " "),
+		})
+	}
+	return revs, scanner.Err()
+}
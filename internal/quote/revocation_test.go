@@ -0,0 +1,124 @@
+package quote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRevocations(t *testing.T) {
+	revs, err := ParseRevocations(strings.NewReader(`
+# nonce quoteId reason
+0042 q-1 leaked to a third party
+7 q-2
+`))
+	if err != nil {
+		t.Fatalf("ParseRevocations failed: %v", err)
+	}
+	want := []Revocation{{Nonce: "42", QuoteID: "q-1", Reason: "leaked to a third party"}, {Nonce: "7", QuoteID: "q-2"}}
+	if len(revs) != len(want) {
+		t.Fatalf("revocations = %+v, want %+v", revs, want)
+	}
+	for i := range want {
+		if revs[i] != want[i] {
+			t.Errorf("revocation %d = %+v, want %+v", i, revs[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"42", "0x2a q-1", "-1 q-1"} {
+		if _, err := ParseRevocations(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseRevocations(%q) = nil error, want error", bad)
+		}
+	}
+}
+
+func TestRevocationList_Set(t *testing.T) {
+	l := NewRevocationList(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	added := l.Set([]Revocation{{Nonce: "1", QuoteID: "q-1"}})
+	if len(added) != 1 {
+		t.Errorf("added = %d, want 1", len(added))
+	}
+	added = l.Set([]Revocation{{Nonce: "1", QuoteID: "q-1"}, {Nonce: "2", QuoteID: "q-2"}})
+	if len(added) != 1 || added[0].Nonce != "2" {
+		t.Errorf("added = %+v, want only nonce 2", added)
+	}
+
+	if _, ok := l.Revoked("2", "other"); !ok {
+		t.Error("Revoked by nonce = false, want true")
+	}
+	if _, ok := l.Revoked("99", "q-1"); !ok {
+		t.Error("Revoked by quote ID = false, want true")
+	}
+
+	l.Set(nil)
+	if _, ok := l.Revoked("1", "q-1"); ok || l.Len() != 0 {
+		t.Errorf("Revoked after removal = %v, Len = %d, want false, 0", ok, l.Len())
+	}
+}
+
+func TestRevocationList_AlertsOnFill(t *testing.T) {
+	var logs bytes.Buffer
+	l := NewRevocationList(slog.New(slog.NewTextHandler(&logs, nil)))
+	l.Set([]Revocation{{Nonce: "1", QuoteID: "q-1", Reason: "test"}})
+
+	store := NewStore(time.Minute)
+	store.SetCloseHandler(l.OnClose)
+	deadline := time.Now().Add(time.Minute).Unix()
+	store.Add(&QuoteRecord{QuoteID: "q-1", Nonce: "1", Deadline: deadline})
+	store.Add(&QuoteRecord{QuoteID: "q-2", Nonce: "2", Deadline: deadline})
+	store.Add(&QuoteRecord{QuoteID: "q-3", Nonce: "1", Deadline: deadline})
+
+	for _, id := range []string{"q-1", "q-2"} {
+		if err := store.SetState(id, QuoteStateFilled); err != nil {
+			t.Fatalf("SetState failed: %v", err)
+		}
+	}
+	if err := store.SetState("q-3", QuoteStateFailed); err != nil {
+		t.Fatalf("SetState failed: %v", err)
+	}
+	if got := strings.Count(logs.String(), "Revoked quote filled"); got != 1 {
+		t.Errorf("alerts = %d, want 1 for the filled revoked quote\n%s", got, logs.String())
+	}
+	if !strings.Contains(logs.String(), "quoteId=q-1") {
+		t.Errorf("alert does not name q-1:\n%s", logs.String())
+	}
+}
+
+// fakeCanceller records cancelled nonces and fails while err is set
+type fakeCanceller struct {
+	err       error
+	cancelled []string
+}
+
+func (f *fakeCanceller) CancelNonce(_ context.Context, rev Revocation) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.cancelled = append(f.cancelled, rev.Nonce)
+	return nil
+}
+
+func TestRevocationList_Cancel(t *testing.T) {
+	l := NewRevocationList(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	l.Set([]Revocation{{Nonce: "1", QuoteID: "q-1"}})
+	l.Cancel(context.Background()) // No canceller: alert only
+
+	canceller := &fakeCanceller{err: errors.New("rpc down")}
+	l.SetCanceller(canceller)
+	l.Cancel(context.Background())
+	if len(canceller.cancelled) != 0 {
+		t.Fatalf("cancelled = %v, want none while failing", canceller.cancelled)
+	}
+
+	canceller.err = nil
+	l.Cancel(context.Background())
+	l.Cancel(context.Background())
+	if len(canceller.cancelled) != 1 || canceller.cancelled[0] != "1" {
+		t.Errorf("cancelled = %v, want nonce 1 once", canceller.cancelled)
+	}
+}
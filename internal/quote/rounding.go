@@ -0,0 +1,64 @@
+package quote
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+)
+
+// tickKey identifies the tick of one token of a pair
+type tickKey struct {
+	chainID uint64
+	pairID  string
+	token   common.Address
+}
+
+// Rounding rounds quoted output amounts to the tick of the output token
+// Strategies already truncate to whole wei; ticks coarsen that to a step per pair, and the
+// mode decides which way a partial tick goes. Tokens without a tick are left unchanged.
+type Rounding struct {
+	mode  string
+	ticks map[tickKey]*big.Int // Native units
+}
+
+// NewRounding creates the rounding policy of cfg.Quote.Rounding and the pair ticks
+// Ticks that fail config validation are ignored
+func NewRounding(cfg *config.Config) *Rounding {
+	r := &Rounding{
+		mode:  cfg.Quote.Rounding,
+		ticks: make(map[tickKey]*big.Int),
+	}
+	for _, pair := range cfg.Pairs {
+		if !common.IsHexAddress(pair.BaseToken) || !common.IsHexAddress(pair.QuoteToken) {
+			continue
+		}
+		if tick, err := config.TickWei(pair.BaseTick, pair.BaseTokenDecimals); err == nil && tick != nil {
+			r.ticks[tickKey{pair.ChainID, pair.PairID, common.HexToAddress(pair.BaseToken)}] = tick
+		}
+		if tick, err := config.TickWei(pair.QuoteTick, pair.QuoteTokenDecimals); err == nil && tick != nil {
+			r.ticks[tickKey{pair.ChainID, pair.PairID, common.HexToAddress(pair.QuoteToken)}] = tick
+		}
+	}
+	return r
+}
+
+// Round returns amount of tokenOut rounded to its tick in pair
+// Rounds down unless the mode is nearest; pair may be nil (no tick)
+func (r *Rounding) Round(pair *config.PairConfig, tokenOut common.Address, amount *big.Int) *big.Int {
+	if pair == nil || amount == nil {
+		return amount
+	}
+	tick, ok := r.ticks[tickKey{pair.ChainID, pair.PairID, tokenOut}]
+	if !ok {
+		return amount
+	}
+
+	rounded := new(big.Int).Set(amount)
+	if r.mode == config.RoundNearest {
+		rounded.Add(rounded, new(big.Int).Rsh(tick, 1))
+	}
+	rounded.Quo(rounded, tick)
+	return rounded.Mul(rounded, tick)
+}
@@ -0,0 +1,47 @@
+package quote
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+)
+
+func TestRounding_Round(t *testing.T) {
+	pair := config.PairConfig{
+		ChainID:            56,
+		PairID:             "WBNB-USDT",
+		BaseToken:          stableWBNB.Hex(),
+		QuoteToken:         stableUSDT.Hex(),
+		BaseTokenDecimals:  18,
+		QuoteTokenDecimals: 18,
+		QuoteTick:          "0.01",
+	}
+
+	tests := []struct {
+		name   string
+		mode   string
+		token  common.Address
+		amount string
+		want   string
+	}{
+		{"down", config.RoundDown, stableUSDT, "599999999999999999999", "599990000000000000000"},
+		{"down exact", config.RoundDown, stableUSDT, "600000000000000000000", "600000000000000000000"},
+		{"nearest up", config.RoundNearest, stableUSDT, "599995000000000000000", "600000000000000000000"},
+		{"nearest down", config.RoundNearest, stableUSDT, "599994999999999999999", "599990000000000000000"},
+		{"below one tick", config.RoundDown, stableUSDT, "9999999999999999", "0"},
+		{"token without tick", config.RoundDown, stableWBNB, "1234567890123456789", "1234567890123456789"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Quote: config.QuoteConfig{Rounding: tt.mode}, Pairs: []config.PairConfig{pair}}
+			amount, _ := new(big.Int).SetString(tt.amount, 10)
+			got := NewRounding(cfg).Round(&cfg.Pairs[0], tt.token, amount)
+			if got.String() != tt.want {
+				t.Errorf("Round(%s) = %s, want %s", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package quote
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// ShadowStats summarizes how a candidate strategy's quotes diverge from the live ones
+// Divergence is (candidate - live) / live of AmountOut in basis points: positive means the
+// candidate would have given the taker more
+type ShadowStats struct {
+	Compared        uint64 // RFQs quoted by both strategies
+	CandidateErrors uint64 // RFQs the live strategy quoted and the candidate refused or timed out on
+	LiveErrors      uint64 // RFQs the candidate quoted and the live strategy refused
+	Skipped         uint64 // RFQs not shadowed because MaxInFlight candidate quotes were running
+	CandidateBetter uint64 // Compared RFQs where the candidate gave more output
+
+	MeanDivergenceBps float64
+	MaxDivergenceBps  float64 // Largest absolute divergence
+}
+
+// shadowQuote is the outcome of one candidate quote
+type shadowQuote struct {
+	result   *QuoteResult
+	err      error
+	duration time.Duration
+}
+
+// ShadowStrategy quotes with Live and runs Candidate on the same request in parallel
+// Only the live quote is returned. The candidate runs with its own Timeout, detached from the
+// request, so it never delays the response; both quotes are logged and compared once known.
+type ShadowStrategy struct {
+	Live      QuoteStrategy // Required
+	Candidate QuoteStrategy // Required
+	Timeout   time.Duration
+
+	slots  chan struct{} // Candidate quotes in flight
+	logger *slog.Logger
+
+	mu            sync.Mutex
+	stats         ShadowStats
+	divergenceSum float64
+}
+
+// NewShadowStrategy creates a shadow comparison of candidate against live
+func NewShadowStrategy(live, candidate QuoteStrategy, cfg config.ShadowConfig, logger *slog.Logger) *ShadowStrategy {
+	return &ShadowStrategy{
+		Live:      live,
+		Candidate: candidate,
+		Timeout:   cfg.Timeout,
+		slots:     make(chan struct{}, max(cfg.MaxInFlight, 1)),
+		logger:    logger.With("component", "ShadowStrategy"),
+	}
+}
+
+// CalculateQuote returns the live quote and starts the candidate quote of the same request
+func (s *ShadowStrategy) CalculateQuote(ctx context.Context, params *QuoteParams) (*QuoteResult, error) {
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		s.mu.Lock()
+		s.stats.Skipped++
+		s.mu.Unlock()
+		return s.Live.CalculateQuote(ctx, params)
+	}
+
+	candidate := make(chan shadowQuote, 1)
+	go func() {
+		cctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.Timeout)
+		defer cancel()
+		start := time.Now()
+		result, err := s.Candidate.CalculateQuote(cctx, params)
+		candidate <- shadowQuote{result: result, err: err, duration: time.Since(start)}
+	}()
+
+	result, err := s.Live.CalculateQuote(ctx, params)
+	go s.compare(ctx, params, result, err, candidate)
+	return result, err
+}
+
+// Stats returns a snapshot of the comparison so far
+func (s *ShadowStrategy) Stats() ShadowStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.stats
+	if stats.Compared > 0 {
+		stats.MeanDivergenceBps = s.divergenceSum / float64(stats.Compared)
+	}
+	return stats
+}
+
+// compare waits for the candidate quote, then logs and records it against the live one
+func (s *ShadowStrategy) compare(ctx context.Context, params *QuoteParams, live *QuoteResult, liveErr error, candidate <-chan shadowQuote) {
+	c := <-candidate
+	<-s.slots
+
+	liveOK := liveErr == nil && live != nil && live.AmountOut != nil && live.AmountOut.Sign() > 0
+	candidateOK := c.err == nil && c.result != nil && c.result.AmountOut != nil
+	logger := s.logger.With(
+		"chainId", params.ChainID,
+		"tokenIn", params.TokenIn.Hex(),
+		"tokenOut", params.TokenOut.Hex(),
+		"amountIn", params.AmountIn.String(),
+		"candidateDuration", c.duration)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case liveOK && candidateOK:
+		divergence := decimal.NewFromBigInt(c.result.AmountOut).Sub(decimal.NewFromBigInt(live.AmountOut)).
+			Quo(decimal.NewFromBigInt(live.AmountOut)).Float64() * 10000
+		s.stats.Compared++
+		s.divergenceSum += divergence
+		s.stats.MaxDivergenceBps = max(s.stats.MaxDivergenceBps, math.Abs(divergence))
+		if divergence > 0 {
+			s.stats.CandidateBetter++
+		}
+		logger.InfoContext(ctx, "Shadow quote",
+			"liveAmountOut", live.AmountOut.String(),
+			"candidateAmountOut", c.result.AmountOut.String(),
+			"divergenceBps", divergence)
+	case liveOK:
+		s.stats.CandidateErrors++
+		logger.InfoContext(ctx, "Shadow quote refused",
+			"liveAmountOut", live.AmountOut.String(),
+			"error", c.err)
+	case candidateOK:
+		s.stats.LiveErrors++
+		logger.InfoContext(ctx, "Shadow quote without live quote",
+			"candidateAmountOut", c.result.AmountOut.String(),
+			"liveError", liveErr)
+	}
+}
@@ -0,0 +1,98 @@
+package quote_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+)
+
+// blockingStrategy blocks until its context is done
+type blockingStrategy struct{}
+
+func (blockingStrategy) CalculateQuote(ctx context.Context, params *quote.QuoteParams) (*quote.QuoteResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// waitShadowStats polls the stats of s until done accepts them
+func waitShadowStats(t *testing.T, s *quote.ShadowStrategy, done func(quote.ShadowStats) bool) quote.ShadowStats {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		stats := s.Stats()
+		if done(stats) {
+			return stats
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("shadow stats = %+v, comparison not recorded", stats)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func newTestShadow(live, candidate quote.QuoteStrategy, timeout time.Duration, maxInFlight int) *quote.ShadowStrategy {
+	cfg := config.ShadowConfig{Timeout: timeout, MaxInFlight: maxInFlight}
+	return quote.NewShadowStrategy(live, candidate, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestShadowStrategy_ComparesWithLive(t *testing.T) {
+	s := newTestShadow(testutil.NewFixedRateStrategy(600, 1), testutil.NewFixedRateStrategy(603, 1), time.Second, 4)
+
+	result, err := s.CalculateQuote(context.Background(), &quote.QuoteParams{AmountIn: big.NewInt(1e18)})
+	if err != nil {
+		t.Fatalf("CalculateQuote failed: %v", err)
+	}
+	if want := "600000000000000000000"; result.AmountOut.String() != want {
+		t.Errorf("AmountOut = %v, want the live %v", result.AmountOut, want)
+	}
+
+	stats := waitShadowStats(t, s, func(st quote.ShadowStats) bool { return st.Compared == 1 })
+	if stats.CandidateBetter != 1 {
+		t.Errorf("CandidateBetter = %d, want 1", stats.CandidateBetter)
+	}
+	// 603 against 600 is 50 bps more output
+	if stats.MeanDivergenceBps != 50 || stats.MaxDivergenceBps != 50 {
+		t.Errorf("divergence mean %v max %v, want 50 bps", stats.MeanDivergenceBps, stats.MaxDivergenceBps)
+	}
+}
+
+func TestShadowStrategy_SlowCandidateDoesNotDelayLive(t *testing.T) {
+	s := newTestShadow(testutil.NewFixedRateStrategy(600, 1), blockingStrategy{}, 20*time.Millisecond, 4)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := s.CalculateQuote(ctx, &quote.QuoteParams{AmountIn: big.NewInt(1e18)}); err != nil {
+		t.Fatalf("CalculateQuote failed: %v", err)
+	}
+	// Cancelling the request does not cancel the candidate; its own timeout does
+	cancel()
+
+	stats := waitShadowStats(t, s, func(st quote.ShadowStats) bool { return st.CandidateErrors == 1 })
+	if stats.Compared != 0 {
+		t.Errorf("Compared = %d, want 0", stats.Compared)
+	}
+}
+
+func TestShadowStrategy_SkipsWhenFull(t *testing.T) {
+	release := make(chan struct{})
+	candidate := &slowStrategy{inner: testutil.NewFixedRateStrategy(600, 1), release: release}
+	s := newTestShadow(testutil.NewFixedRateStrategy(600, 1), candidate, time.Second, 1)
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.CalculateQuote(context.Background(), &quote.QuoteParams{AmountIn: big.NewInt(1e18)}); err != nil {
+			t.Fatalf("CalculateQuote failed: %v", err)
+		}
+	}
+	close(release)
+
+	stats := waitShadowStats(t, s, func(st quote.ShadowStats) bool { return st.Compared == 1 })
+	if stats.Skipped != 1 {
+		t.Errorf("Skipped = %d, want 1", stats.Skipped)
+	}
+}
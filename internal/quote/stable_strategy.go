@@ -0,0 +1,109 @@
+package quote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// ErrPriceMoved is returned by strategies that refuse to quote because the market moved
+// The handler rejects such quotes with REJECT_REASON_PRICE_MOVED
+var ErrPriceMoved = errors.New("price moved")
+
+// parity is the 1:1 price of stable pairs
+var parity = decimal.NewFromInt(1)
+
+// StableStrategy quotes stable and correlated pairs (USDT/USDC, WBNB/BNB) near 1:1
+// Other pairs are passed to Next. Stable pairs need no price feed: the output is the input
+// adjusted for token decimals, minus FeeBps. With MaxDeviationBps set, Next's price of the
+// pair is the depeg reference, and quotes are rejected while it is too far from parity.
+type StableStrategy struct {
+	Next            QuoteStrategy // Required
+	FeeBps          uint32
+	MaxDeviationBps uint32 // 0 = no depeg guard
+
+	cfg   *config.Config
+	pairs map[string]struct{} // Stable pair IDs
+}
+
+// NewStableStrategy creates a stable-pair strategy for the pairs of cfg.Stable
+func NewStableStrategy(next QuoteStrategy, cfg *config.Config) *StableStrategy {
+	pairs := make(map[string]struct{}, len(cfg.Stable.Pairs))
+	for _, pairID := range cfg.Stable.Pairs {
+		pairs[pairID] = struct{}{}
+	}
+	return &StableStrategy{
+		Next:            next,
+		FeeBps:          cfg.Stable.FeeBps,
+		MaxDeviationBps: cfg.Stable.MaxDeviationBps,
+		cfg:             cfg,
+		pairs:           pairs,
+	}
+}
+
+// CalculateQuote quotes stable pairs at parity and passes other pairs to Next
+func (s *StableStrategy) CalculateQuote(ctx context.Context, params *QuoteParams) (*QuoteResult, error) {
+	pair := s.cfg.GetPairConfigByAddress(params.ChainID, params.TokenIn, params.TokenOut)
+	if pair == nil {
+		return s.Next.CalculateQuote(ctx, params)
+	}
+	if _, ok := s.pairs[pair.PairID]; !ok {
+		return s.Next.CalculateQuote(ctx, params)
+	}
+
+	if err := s.checkPeg(ctx, params); err != nil {
+		return nil, err
+	}
+
+	decIn, decOut := pair.BaseTokenDecimals, pair.QuoteTokenDecimals
+	if params.TokenIn != common.HexToAddress(pair.BaseToken) {
+		decIn, decOut = decOut, decIn
+	}
+
+	// amountOut = amountIn * 10^(decOut-decIn) * (10000 - fee) / 10000, truncated once
+	amountOut := decimal.New(10000-int64(s.FeeBps), decOut-decIn-4).MulInt(params.AmountIn).Int()
+	if amountOut.Sign() <= 0 {
+		return nil, fmt.Errorf("calculated amount out is zero or negative")
+	}
+
+	result := NewQuoteResult(amountOut)
+	result.ExecutionPrice = parity
+	result.PriceImpact = float64(s.FeeBps) / 100
+	result.Info = QuoteInfo{
+		FeeBps:      s.FeeBps,
+		Route:       []common.Address{params.TokenIn, params.TokenOut},
+		PriceSource: "stable",
+		MidPrice:    parity,
+	}
+	return result, nil
+}
+
+// checkPeg returns an ErrPriceMoved error when Next's price is more than MaxDeviationBps from parity
+// Without a reference price the peg cannot be verified, so the quote is refused
+func (s *StableStrategy) checkPeg(ctx context.Context, params *QuoteParams) error {
+	if s.MaxDeviationBps == 0 {
+		return nil
+	}
+	ref, err := s.Next.CalculateQuote(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to get reference price: %w", err)
+	}
+	if ref.ExecutionPrice.Sign() <= 0 {
+		return fmt.Errorf("reference quote has no execution price")
+	}
+
+	deviation := ref.ExecutionPrice.Sub(parity)
+	if deviation.Sign() < 0 {
+		deviation = deviation.Neg()
+	}
+	if deviation.Cmp(decimal.New(int64(s.MaxDeviationBps), -4)) > 0 {
+		return fmt.Errorf("%w: reference price %s is more than %d bps from parity",
+			ErrPriceMoved, ref.ExecutionPrice, s.MaxDeviationBps)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package quote
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+var (
+	stableUSDT = common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	stableUSDC = common.HexToAddress("0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580d")
+	stableWBNB = common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+)
+
+// newStableTestStrategy quotes USDT(18)-USDC(6) at parity and WBNB-USDT through a mock strategy
+func newStableTestStrategy(maxDeviationBps uint32, usdtPrice decimal.Decimal) *StableStrategy {
+	cfg := &config.Config{
+		Pairs: []config.PairConfig{
+			{ChainID: 56, PairID: "USDT-USDC", BaseToken: stableUSDT.Hex(), QuoteToken: stableUSDC.Hex(), BaseTokenDecimals: 18, QuoteTokenDecimals: 6},
+			{ChainID: 56, PairID: "WBNB-USDT", BaseToken: stableWBNB.Hex(), QuoteToken: stableUSDT.Hex(), BaseTokenDecimals: 18, QuoteTokenDecimals: 18},
+		},
+		Stable: config.StableConfig{Pairs: []string{"USDT-USDC"}, FeeBps: 5, MaxDeviationBps: maxDeviationBps},
+	}
+	cfg.BuildIndex()
+
+	next := NewMockStrategyWithSeed(0, 1)
+	next.SetPrice(56, stableWBNB, stableUSDT, decimal.NewFromInt(600))
+	next.SetPrice(56, stableUSDT, stableUSDC, usdtPrice)
+	return NewStableStrategy(next, cfg)
+}
+
+func TestStableStrategy_QuotesAtParity(t *testing.T) {
+	s := newStableTestStrategy(0, decimal.MustParse("0.9"))
+	tests := []struct {
+		name     string
+		in, out  common.Address
+		amountIn *big.Int
+		want     string
+	}{
+		{"18 to 6 decimals", stableUSDT, stableUSDC, big.NewInt(1e18), "999500"},
+		{"6 to 18 decimals", stableUSDC, stableUSDT, big.NewInt(1e6), "999500000000000000"},
+		{"other pair uses Next", stableWBNB, stableUSDT, big.NewInt(1e18), "600000000000000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.CalculateQuote(context.Background(), &QuoteParams{ChainID: 56, TokenIn: tt.in, TokenOut: tt.out, AmountIn: tt.amountIn})
+			if err != nil {
+				t.Fatalf("CalculateQuote failed: %v", err)
+			}
+			if result.AmountOut.String() != tt.want {
+				t.Errorf("AmountOut = %v, want %v", result.AmountOut, tt.want)
+			}
+		})
+	}
+}
+
+func TestStableStrategy_DepegGuard(t *testing.T) {
+	params := &QuoteParams{ChainID: 56, TokenIn: stableUSDT, TokenOut: stableUSDC, AmountIn: big.NewInt(1e18)}
+	tests := []struct {
+		name      string
+		reference string
+		wantMoved bool
+	}{
+		{"within guard", "0.998", false},
+		{"at guard", "1.005", false},
+		{"depegged below", "0.99", true},
+		{"depegged above", "1.0051", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStableTestStrategy(50, decimal.MustParse(tt.reference))
+			result, err := s.CalculateQuote(context.Background(), params)
+			if got := errors.Is(err, ErrPriceMoved); got != tt.wantMoved {
+				t.Fatalf("CalculateQuote error = %v, want ErrPriceMoved %v", err, tt.wantMoved)
+			}
+			// The reference only guards: the quote stays at parity
+			if !tt.wantMoved && result.AmountOut.String() != "999500" {
+				t.Errorf("AmountOut = %v, want 999500", result.AmountOut)
+			}
+		})
+	}
+
+	// Without a reference price the peg cannot be verified
+	s := newStableTestStrategy(50, decimal.MustParse("1"))
+	delete(s.Next.(*MockStrategy).Prices, s.Next.(*MockStrategy).buildPriceKey(56, stableUSDT, stableUSDC))
+	if _, err := s.CalculateQuote(context.Background(), params); err == nil || errors.Is(err, ErrPriceMoved) {
+		t.Errorf("CalculateQuote error = %v, want a reference error", err)
+	}
+}
@@ -0,0 +1,197 @@
+package quote
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// Stats is a snapshot of quote handling counters
+type Stats struct {
+	Requests        uint64            // Quote requests received
+	Responses       uint64            // Signed quote responses built
+	Rejects         uint64            // Rejections built
+	RejectsByReason map[string]uint64 // Rejections by RejectReason name
+	BudgetExceeded  uint64            // Requests rejected for exceeding their latency budget
+
+	// Latency by stage (StageValidate, StageStrategy, StageSign, StageTotal)
+	Latency map[string]LatencyStats
+}
+
+// Quote handling stages timed in Stats.Latency
+const (
+	StageValidate = "validate" // Request validation and pair lookup
+	StageStrategy = "strategy" // QuoteStrategy.CalculateQuote
+	StageSign     = "sign"     // EIP-712 signing
+	StageTotal    = "total"    // Whole request, including rejects
+)
+
+// latencyWindow is the number of recent durations per stage that P99 is computed over
+const latencyWindow = 1024
+
+// LatencyStats summarizes the durations of a stage
+type LatencyStats struct {
+	Count uint64
+	Total time.Duration
+	Max   time.Duration
+	P95   time.Duration // Over the most recent requests only, like P99
+	P99   time.Duration // Over the most recent requests only, so it recovers after a spike
+}
+
+// Mean returns the mean duration
+func (l LatencyStats) Mean() time.Duration {
+	if l.Count == 0 {
+		return 0
+	}
+	return l.Total / time.Duration(l.Count)
+}
+
+// RejectRate returns the share of requests that were rejected (0-1)
+func (s Stats) RejectRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Rejects) / float64(s.Requests)
+}
+
+// statsCollector collects quote handling counters and per-pair activity
+type statsCollector struct {
+	mu              sync.Mutex
+	requests        uint64
+	responses       uint64
+	rejects         uint64
+	rejectsByReason map[string]uint64
+	budgetExceeded  uint64
+	latency         map[string]LatencyStats
+	recent          map[string]*recentDurations
+	lastQuoted      map[string]time.Time // pairId -> last signed quote time
+}
+
+// newStatsCollector creates a stats collector
+func newStatsCollector() *statsCollector {
+	return &statsCollector{
+		rejectsByReason: make(map[string]uint64),
+		latency:         make(map[string]LatencyStats),
+		recent:          make(map[string]*recentDurations),
+		lastQuoted:      make(map[string]time.Time),
+	}
+}
+
+// recordRequest counts a received quote request
+func (c *statsCollector) recordRequest() {
+	c.mu.Lock()
+	c.requests++
+	c.mu.Unlock()
+}
+
+// recordResponse counts a signed response for a pair
+func (c *statsCollector) recordResponse(pairID string) {
+	c.mu.Lock()
+	c.responses++
+	c.lastQuoted[pairID] = time.Now()
+	c.mu.Unlock()
+}
+
+// recordReject counts a rejection
+func (c *statsCollector) recordReject(reason mmv1.RejectReason) {
+	c.mu.Lock()
+	c.rejects++
+	c.rejectsByReason[reason.String()]++
+	c.mu.Unlock()
+}
+
+// recordLatency records the duration of a stage
+func (c *statsCollector) recordLatency(stage string, d time.Duration) {
+	c.mu.Lock()
+	l := c.latency[stage]
+	l.Count++
+	l.Total += d
+	if d > l.Max {
+		l.Max = d
+	}
+	c.latency[stage] = l
+
+	recent := c.recent[stage]
+	if recent == nil {
+		recent = &recentDurations{}
+		c.recent[stage] = recent
+	}
+	recent.add(d)
+	c.mu.Unlock()
+}
+
+// recordBudgetExceeded counts a request that ran out of latency budget
+func (c *statsCollector) recordBudgetExceeded() {
+	c.mu.Lock()
+	c.budgetExceeded++
+	c.mu.Unlock()
+}
+
+// snapshot returns a copy of the counters
+func (c *statsCollector) snapshot() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	byReason := make(map[string]uint64, len(c.rejectsByReason))
+	for reason, count := range c.rejectsByReason {
+		byReason[reason] = count
+	}
+	latency := make(map[string]LatencyStats, len(c.latency))
+	for stage, l := range c.latency {
+		l.P95 = c.recent[stage].percentile(0.95)
+		l.P99 = c.recent[stage].percentile(0.99)
+		latency[stage] = l
+	}
+	return Stats{
+		Requests:        c.requests,
+		Responses:       c.responses,
+		Rejects:         c.rejects,
+		RejectsByReason: byReason,
+		BudgetExceeded:  c.budgetExceeded,
+		Latency:         latency,
+	}
+}
+
+// activePairs returns pairs with a signed quote within the window
+func (c *statsCollector) activePairs(window time.Duration) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cutoff := time.Now().Add(-window)
+	pairs := make([]string, 0, len(c.lastQuoted))
+	for pairID, t := range c.lastQuoted {
+		if t.After(cutoff) {
+			pairs = append(pairs, pairID)
+		}
+	}
+	return pairs
+}
+
+// recentDurations is a ring of the last latencyWindow durations of a stage
+type recentDurations struct {
+	samples [latencyWindow]time.Duration
+	n       int // Samples held, up to latencyWindow
+	next    int // Slot the next sample is written to
+}
+
+// add records a duration, replacing the oldest one when full
+func (r *recentDurations) add(d time.Duration) {
+	r.samples[r.next] = d
+	r.next = (r.next + 1) % latencyWindow
+	if r.n < latencyWindow {
+		r.n++
+	}
+}
+
+// percentile returns the q quantile (0-1) of the held durations, 0 if there are none
+func (r *recentDurations) percentile(q float64) time.Duration {
+	if r == nil || r.n == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, r.n)
+	copy(sorted, r.samples[:r.n])
+	slices.Sort(sorted)
+	return sorted[int(q*float64(r.n-1))]
+}
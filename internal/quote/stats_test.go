@@ -0,0 +1,31 @@
+package quote
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsCollector_P99OverRecentWindow(t *testing.T) {
+	c := newStatsCollector()
+	for i := 1; i <= 100; i++ {
+		c.recordLatency(StageTotal, time.Duration(i)*time.Millisecond)
+	}
+	if got := c.snapshot().Latency[StageTotal].P99; got != 99*time.Millisecond {
+		t.Fatalf("P99 = %v, want 99ms", got)
+	}
+	if got := c.snapshot().Latency[StageTotal].P95; got != 95*time.Millisecond {
+		t.Errorf("P95 = %v, want 95ms", got)
+	}
+
+	// A full window of fast requests pushes the spike out of P99, but not out of Max
+	for i := 0; i < latencyWindow; i++ {
+		c.recordLatency(StageTotal, time.Millisecond)
+	}
+	l := c.snapshot().Latency[StageTotal]
+	if l.P99 != time.Millisecond {
+		t.Errorf("P99 = %v, want 1ms", l.P99)
+	}
+	if l.Max != 100*time.Millisecond {
+		t.Errorf("Max = %v, want 100ms", l.Max)
+	}
+}
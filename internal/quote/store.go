@@ -0,0 +1,432 @@
+package quote
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/store"
+)
+
+// QuoteState is the local lifecycle state of a signed quote
+type QuoteState int
+
+const (
+	QuoteStatePending  QuoteState = iota // Signed and sent, outcome unknown
+	QuoteStateAccepted                   // Accepted by the server for settlement
+	QuoteStateFilled                     // Settled on-chain
+	QuoteStateExpired                    // Deadline passed without a known fill
+	QuoteStateFailed                     // Server reported an error for this quote
+)
+
+// String returns the string representation of the state
+func (s QuoteState) String() string {
+	switch s {
+	case QuoteStatePending:
+		return "Pending"
+	case QuoteStateAccepted:
+		return "Accepted"
+	case QuoteStateFilled:
+		return "Filled"
+	case QuoteStateExpired:
+		return "Expired"
+	case QuoteStateFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+// IsOpen reports whether the quote still counts towards risk exposure
+func (s QuoteState) IsOpen() bool {
+	return s == QuoteStatePending || s == QuoteStateAccepted
+}
+
+// QuoteRecord is a signed quote tracked by the Store
+type QuoteRecord struct {
+	QuoteID   string
+	ChainID   uint64
+	TokenIn   common.Address
+	TokenOut  common.Address
+	AmountIn  *big.Int // Native decimals
+	AmountOut *big.Int // Native decimals (signed amount)
+	Nonce     string
+	Deadline  int64 // Unix seconds
+	SignedAt  time.Time
+	State     QuoteState
+	UpdatedAt time.Time
+	Signer    common.Address // Key that signed the quote
+	Info      QuoteInfo      // How the quote was priced
+}
+
+// ReconcileResult summarizes a Store.Reconcile pass
+type ReconcileResult struct {
+	Expired int // Quotes moved to Expired in this pass
+	Open    int // Quotes still open after the pass
+	Pruned  int // Closed quotes dropped after the retention period
+}
+
+// CloseHandler is called once when an open quote closes (expired, filled or failed)
+// It receives a copy of the record in its final state, and releases whatever the quote reserved
+type CloseHandler func(rec QuoteRecord)
+
+// Store keeps recently signed quotes in memory
+// The protocol has no quote status query, so the store is reconciled locally:
+// open quotes whose deadline has passed are expired, and server errors referencing
+// a quote mark it as failed. Exposure is computed only from open quotes.
+type Store struct {
+	mu        sync.RWMutex
+	quotes    map[string]*QuoteRecord
+	retention time.Duration
+	skew      time.Duration // Deadline tolerance for clock differences with the server
+
+	expiries expiryHeap    // Deadlines of added quotes, soonest first
+	added    chan struct{} // Wakes the ExpiryScheduler when a quote is added
+	onClose  CloseHandler
+
+	journal store.Store // Receives every signed quote and state change, nil = not journaled
+	logger  *slog.Logger
+}
+
+// JournalLog is the state store log of signed quotes and their state changes
+// It is the audit trail of what the MM signed, and lets a restarted MM restore open quotes.
+const JournalLog = "quotes"
+
+// journalEntry is one record of the quote journal
+type journalEntry struct {
+	Quote *QuoteRecord `json:"quote,omitempty"` // A newly signed quote
+	ID    string       `json:"id,omitempty"`    // Or the quote whose state changed
+	State QuoteState   `json:"state"`
+	At    time.Time    `json:"at"`
+}
+
+// NewStore creates a quote store
+// retention controls how long closed quotes are kept after their last update
+func NewStore(retention time.Duration) *Store {
+	if retention <= 0 {
+		retention = 10 * time.Minute
+	}
+	return &Store{
+		quotes:    make(map[string]*QuoteRecord),
+		retention: retention,
+		added:     make(chan struct{}, 1),
+	}
+}
+
+// SetDeadlineSkew keeps quotes open for d past their deadline, so a server clock running
+// behind the MM's does not see a quote the MM already counts as expired; call before use
+func (s *Store) SetDeadlineSkew(d time.Duration) {
+	s.mu.Lock()
+	s.skew = d
+	s.mu.Unlock()
+}
+
+// pastDeadline reports whether deadline (inclusive, unix seconds) has passed at now, beyond
+// the skew tolerance; the caller must hold s.mu
+func (s *Store) pastDeadline(deadline int64, now time.Time) bool {
+	return !now.Before(time.Unix(deadline+1, 0).Add(s.skew))
+}
+
+// SetCloseHandler sets the callback run when an open quote closes
+// The callback runs outside the store lock and may call the store
+func (s *Store) SetCloseHandler(handler CloseHandler) {
+	s.mu.Lock()
+	s.onClose = handler
+	s.mu.Unlock()
+}
+
+// SetJournal writes every signed quote and state change to the JournalLog of j
+// Journal writes happen under the store lock, so the journal orders events as the store did.
+// Write failures are logged; the quote store keeps working from memory.
+func (s *Store) SetJournal(j store.Store, logger *slog.Logger) {
+	s.mu.Lock()
+	s.journal = j
+	s.logger = logger.With("component", "QuoteStore")
+	s.mu.Unlock()
+}
+
+// Restore rebuilds the store from the JournalLog of j, after a restart
+// Open quotes and closed quotes still within the retention period are restored; quotes whose
+// deadline passed while the MM was down are expired by the next ExpireDue or Reconcile.
+// Returns the number of open quotes restored.
+func (s *Store) Restore(j store.Store, now time.Time) (int, error) {
+	quotes := make(map[string]*QuoteRecord)
+	err := j.Replay(JournalLog, func(record []byte) error {
+		var entry journalEntry
+		if err := json.Unmarshal(record, &entry); err != nil {
+			return fmt.Errorf("invalid journal entry: %w", err)
+		}
+		if entry.Quote != nil {
+			quotes[entry.Quote.QuoteID] = entry.Quote
+			return nil
+		}
+		if rec, ok := quotes[entry.ID]; ok {
+			rec.State = entry.State
+			rec.UpdatedAt = entry.At
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	open := 0
+	for id, rec := range quotes {
+		if !rec.State.IsOpen() && now.Sub(rec.UpdatedAt) > s.retention {
+			continue
+		}
+		if _, ok := s.quotes[id]; ok {
+			continue
+		}
+		s.quotes[id] = rec
+		if rec.State.IsOpen() {
+			heap.Push(&s.expiries, expiryEntry{deadline: rec.Deadline, quoteID: id})
+			open++
+		}
+	}
+	return open, nil
+}
+
+// record writes an entry to the journal
+// Must be called with the lock held
+func (s *Store) record(entry journalEntry) {
+	if s.journal == nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err == nil {
+		err = s.journal.Append(JournalLog, data)
+	}
+	if err != nil {
+		s.logger.Error("Failed to journal quote", "quoteId", entry.ID, "error", err)
+	}
+}
+
+// Add records a newly signed quote
+func (s *Store) Add(rec *QuoteRecord) {
+	if rec.SignedAt.IsZero() {
+		rec.SignedAt = time.Now()
+	}
+	rec.UpdatedAt = rec.SignedAt
+
+	s.mu.Lock()
+	s.quotes[rec.QuoteID] = rec
+	s.record(journalEntry{Quote: rec, ID: rec.QuoteID, State: rec.State, At: rec.UpdatedAt})
+	heap.Push(&s.expiries, expiryEntry{deadline: rec.Deadline, quoteID: rec.QuoteID})
+	s.mu.Unlock()
+
+	select {
+	case s.added <- struct{}{}:
+	default:
+	}
+}
+
+// Get returns a copy of the quote record
+func (s *Store) Get(quoteID string) (QuoteRecord, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.quotes[quoteID]
+	if !ok {
+		return QuoteRecord{}, false
+	}
+	return *rec, true
+}
+
+// SetState updates the state of a tracked quote
+// Closed quotes are never reopened
+func (s *Store) SetState(quoteID string, state QuoteState) error {
+	s.mu.Lock()
+	rec, ok := s.quotes[quoteID]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("quote %s not found", quoteID)
+	}
+	if !rec.State.IsOpen() && state.IsOpen() {
+		s.mu.Unlock()
+		return fmt.Errorf("quote %s already %s", quoteID, rec.State)
+	}
+	closing := rec.State.IsOpen() && !state.IsOpen()
+	rec.State = state
+	rec.UpdatedAt = time.Now()
+	s.record(journalEntry{ID: quoteID, State: state, At: rec.UpdatedAt})
+	closed, onClose := *rec, s.onClose
+	s.mu.Unlock()
+
+	if closing && onClose != nil {
+		onClose(closed)
+	}
+	return nil
+}
+
+// Reconcile expires open quotes past their deadline and prunes old closed quotes
+func (s *Store) Reconcile(now time.Time) ReconcileResult {
+	var result ReconcileResult
+	var closed []QuoteRecord
+	defer func() { s.notifyClosed(closed) }()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, rec := range s.quotes {
+		if rec.State.IsOpen() && s.pastDeadline(rec.Deadline, now) {
+			rec.State = QuoteStateExpired
+			rec.UpdatedAt = now
+			s.record(journalEntry{ID: id, State: rec.State, At: now})
+			result.Expired++
+			closed = append(closed, *rec)
+		}
+		if !rec.State.IsOpen() && now.Sub(rec.UpdatedAt) > s.retention {
+			delete(s.quotes, id)
+			result.Pruned++
+			continue
+		}
+		if rec.State.IsOpen() {
+			result.Open++
+		}
+	}
+	return result
+}
+
+// ExpireDue expires open quotes whose deadline has passed, soonest deadline first
+// Returns the number of quotes expired
+func (s *Store) ExpireDue(now time.Time) int {
+	var closed []QuoteRecord
+	s.mu.Lock()
+	for len(s.expiries) > 0 && s.pastDeadline(s.expiries[0].deadline, now) {
+		entry := heap.Pop(&s.expiries).(expiryEntry)
+		// Quotes closed or pruned before their deadline need nothing more
+		rec, ok := s.quotes[entry.quoteID]
+		if !ok || !rec.State.IsOpen() || rec.Deadline != entry.deadline {
+			continue
+		}
+		rec.State = QuoteStateExpired
+		rec.UpdatedAt = now
+		s.record(journalEntry{ID: entry.quoteID, State: rec.State, At: now})
+		closed = append(closed, *rec)
+	}
+	s.mu.Unlock()
+
+	s.notifyClosed(closed)
+	return len(closed)
+}
+
+// NextExpiry returns when the soonest tracked deadline passes, false if none is tracked
+// A deadline is inclusive, so the quote expires once the following second starts, plus the
+// deadline skew
+func (s *Store) NextExpiry() (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.expiries) == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(s.expiries[0].deadline+1, 0).Add(s.skew), true
+}
+
+// notifyClosed runs the close handler for quotes closed under the lock
+// Must be called without holding the lock
+func (s *Store) notifyClosed(closed []QuoteRecord) {
+	if len(closed) == 0 {
+		return
+	}
+	s.mu.RLock()
+	onClose := s.onClose
+	s.mu.RUnlock()
+	if onClose == nil {
+		return
+	}
+	for _, rec := range closed {
+		onClose(rec)
+	}
+}
+
+// Open returns copies of all open quotes
+func (s *Store) Open() []QuoteRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	open := make([]QuoteRecord, 0)
+	for _, rec := range s.quotes {
+		if rec.State.IsOpen() {
+			open = append(open, *rec)
+		}
+	}
+	return open
+}
+
+// Recent returns copies of the tracked quotes, most recently signed first
+// Closed quotes stay tracked for the retention period. limit <= 0 returns all of them.
+func (s *Store) Recent(limit int) []QuoteRecord {
+	s.mu.RLock()
+	recent := make([]QuoteRecord, 0, len(s.quotes))
+	for _, rec := range s.quotes {
+		recent = append(recent, *rec)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(recent, func(i, j int) bool { return recent[i].SignedAt.After(recent[j].SignedAt) })
+	if limit > 0 && len(recent) > limit {
+		recent = recent[:limit]
+	}
+	return recent
+}
+
+// Exposure returns the total signed output amount of open quotes per token
+// key: "chainId:tokenOut" (lowercase address)
+func (s *Store) Exposure() map[string]*big.Int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	exposure := make(map[string]*big.Int)
+	for _, rec := range s.quotes {
+		if !rec.State.IsOpen() || rec.AmountOut == nil {
+			continue
+		}
+		key := fmt.Sprintf("%d:%s", rec.ChainID, strings.ToLower(rec.TokenOut.Hex()))
+		total, ok := exposure[key]
+		if !ok {
+			total = new(big.Int)
+			exposure[key] = total
+		}
+		total.Add(total, rec.AmountOut)
+	}
+	return exposure
+}
+
+// Len returns the number of tracked quotes
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.quotes)
+}
+
+// expiryEntry is a quote deadline tracked by the Store
+type expiryEntry struct {
+	deadline int64
+	quoteID  string
+}
+
+// expiryHeap is a min-heap of deadlines (container/heap)
+type expiryHeap []expiryEntry
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].deadline < h[j].deadline }
+func (h expiryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *expiryHeap) Push(x any)        { *h = append(*h, x.(expiryEntry)) }
+func (h *expiryHeap) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
+}
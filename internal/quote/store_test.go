@@ -0,0 +1,230 @@
+package quote
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/store"
+)
+
+func newTestRecord(quoteID string, deadline int64) *QuoteRecord {
+	return &QuoteRecord{
+		QuoteID:   quoteID,
+		ChainID:   56,
+		TokenIn:   common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c"),
+		TokenOut:  common.HexToAddress("0x55d398326f99059fF775485246999027B3197955"),
+		AmountIn:  big.NewInt(1000),
+		AmountOut: big.NewInt(600),
+		Nonce:     "1",
+		Deadline:  deadline,
+	}
+}
+
+func TestStore_AddAndGet(t *testing.T) {
+	store := NewStore(time.Minute)
+	store.Add(newTestRecord("q1", time.Now().Add(time.Minute).Unix()))
+
+	rec, ok := store.Get("q1")
+	if !ok {
+		t.Fatal("Get returned false for tracked quote")
+	}
+	if rec.State != QuoteStatePending {
+		t.Errorf("State = %v, want %v", rec.State, QuoteStatePending)
+	}
+	if _, ok := store.Get("missing"); ok {
+		t.Error("Get should return false for unknown quote")
+	}
+}
+
+func TestStore_Reconcile(t *testing.T) {
+	store := NewStore(time.Minute)
+	now := time.Now()
+
+	store.Add(newTestRecord("expired", now.Add(-time.Second).Unix()))
+	store.Add(newTestRecord("open", now.Add(time.Minute).Unix()))
+
+	result := store.Reconcile(now)
+	if result.Expired != 1 {
+		t.Errorf("Expired = %d, want 1", result.Expired)
+	}
+	if result.Open != 1 {
+		t.Errorf("Open = %d, want 1", result.Open)
+	}
+
+	rec, _ := store.Get("expired")
+	if rec.State != QuoteStateExpired {
+		t.Errorf("State = %v, want %v", rec.State, QuoteStateExpired)
+	}
+
+	// Closed quotes are pruned after the retention period
+	result = store.Reconcile(now.Add(2 * time.Minute))
+	if result.Pruned != 1 {
+		t.Errorf("Pruned = %d, want 1", result.Pruned)
+	}
+	if store.Len() != 1 {
+		t.Errorf("Len = %d, want 1", store.Len())
+	}
+}
+
+func TestStore_Exposure(t *testing.T) {
+	store := NewStore(time.Minute)
+	deadline := time.Now().Add(time.Minute).Unix()
+
+	store.Add(newTestRecord("q1", deadline))
+	store.Add(newTestRecord("q2", deadline))
+	store.Add(newTestRecord("q3", deadline))
+
+	if err := store.SetState("q3", QuoteStateFailed); err != nil {
+		t.Fatalf("SetState failed: %v", err)
+	}
+
+	exposure := store.Exposure()
+	key := "56:0x55d398326f99059ff775485246999027b3197955"
+	if exposure[key] == nil || exposure[key].Int64() != 1200 {
+		t.Errorf("Exposure[%s] = %v, want 1200", key, exposure[key])
+	}
+
+	// Closed quotes cannot be reopened
+	if err := store.SetState("q3", QuoteStatePending); err == nil {
+		t.Error("SetState should not reopen a closed quote")
+	}
+}
+
+func TestStore_ExpireDueInDeadlineOrder(t *testing.T) {
+	store := NewStore(time.Minute)
+	var closed []string
+	store.SetCloseHandler(func(rec QuoteRecord) {
+		closed = append(closed, rec.QuoteID+":"+rec.State.String())
+	})
+
+	base := time.Unix(1735084800, 0)
+	store.Add(newTestRecord("late", base.Unix()+20))
+	store.Add(newTestRecord("soon", base.Unix()+5))
+	store.Add(newTestRecord("filled", base.Unix()+10))
+	store.Add(newTestRecord("mid", base.Unix()+10))
+
+	if next, ok := store.NextExpiry(); !ok || !next.Equal(time.Unix(base.Unix()+6, 0)) {
+		t.Fatalf("NextExpiry = %v, %v, want the second after the soonest deadline", next, ok)
+	}
+
+	// A fill closes the quote before its deadline; the scheduler must not expire it again
+	if err := store.SetState("filled", QuoteStateFilled); err != nil {
+		t.Fatalf("SetState failed: %v", err)
+	}
+
+	// Deadlines are inclusive: nothing expires at the deadline second itself
+	if got := store.ExpireDue(base.Add(5 * time.Second)); got != 0 {
+		t.Errorf("ExpireDue at the deadline = %d, want 0", got)
+	}
+	if got := store.ExpireDue(base.Add(11 * time.Second)); got != 2 {
+		t.Errorf("ExpireDue = %d, want 2", got)
+	}
+
+	want := []string{"filled:Filled", "soon:Expired", "mid:Expired"}
+	if len(closed) != len(want) {
+		t.Fatalf("closed = %v, want %v", closed, want)
+	}
+	for i := range want {
+		if closed[i] != want[i] {
+			t.Errorf("closed[%d] = %s, want %s", i, closed[i], want[i])
+		}
+	}
+	if open := store.Open(); len(open) != 1 || open[0].QuoteID != "late" {
+		t.Errorf("Open = %v, want only late", open)
+	}
+}
+
+func TestStore_DeadlineSkew(t *testing.T) {
+	store := NewStore(time.Minute)
+	store.SetDeadlineSkew(500 * time.Millisecond)
+
+	base := time.Unix(1735084800, 0)
+	store.Add(newTestRecord("q1", base.Unix()))
+
+	if next, ok := store.NextExpiry(); !ok || !next.Equal(base.Add(1500*time.Millisecond)) {
+		t.Fatalf("NextExpiry = %v, %v, want the second after the deadline plus the skew", next, ok)
+	}
+	if got := store.ExpireDue(base.Add(1200 * time.Millisecond)); got != 0 {
+		t.Errorf("ExpireDue within the skew = %d, want 0", got)
+	}
+	if result := store.Reconcile(base.Add(1200 * time.Millisecond)); result.Expired != 0 {
+		t.Errorf("Reconcile within the skew expired %d, want 0", result.Expired)
+	}
+	if got := store.ExpireDue(base.Add(1500 * time.Millisecond)); got != 1 {
+		t.Errorf("ExpireDue after the skew = %d, want 1", got)
+	}
+}
+
+func TestExpiryScheduler_ExpiresAddedQuote(t *testing.T) {
+	store := NewStore(time.Minute)
+	closed := make(chan QuoteRecord, 1)
+	store.SetCloseHandler(func(rec QuoteRecord) { closed <- rec })
+
+	// The clock is past every deadline, so a quote expires as soon as the scheduler sees it
+	scheduler := NewExpiryScheduler(store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	scheduler.now = func() time.Time { return time.Unix(1735084800, 0) }
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go scheduler.Run(ctx)
+
+	store.Add(newTestRecord("q1", 1735084700))
+	select {
+	case rec := <-closed:
+		if rec.QuoteID != "q1" || rec.State != QuoteStateExpired {
+			t.Errorf("closed %s as %v, want q1 as Expired", rec.QuoteID, rec.State)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("quote not expired by the scheduler")
+	}
+}
+
+func TestStore_JournalRestore(t *testing.T) {
+	journal := store.NewMemory()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	now := time.Now()
+
+	before := NewStore(time.Minute)
+	before.SetJournal(journal, logger)
+	open := newTestRecord("open", now.Add(time.Minute).Unix())
+	open.Info = QuoteInfo{FeeBps: 5, PriceSource: "mock", MidPrice: decimal.MustParse("600.5")}
+	before.Add(open)
+	before.Add(newTestRecord("filled", now.Add(time.Minute).Unix()))
+	before.Add(newTestRecord("lapsed", now.Add(-time.Second).Unix()))
+	before.SetState("open", QuoteStateAccepted)
+	before.SetState("filled", QuoteStateFilled)
+	before.ExpireDue(now)
+
+	// A restarted MM gets its open quotes, and their exposure, back
+	after := NewStore(time.Minute)
+	restored, err := after.Restore(journal, now)
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if restored != 1 {
+		t.Errorf("restored = %d, want 1 open quote", restored)
+	}
+	rec, ok := after.Get("open")
+	if !ok || rec.State != QuoteStateAccepted || rec.AmountOut.Int64() != 600 || rec.Info.MidPrice.String() != "600.5" {
+		t.Errorf("restored open quote = %+v, %v", rec, ok)
+	}
+	if rec, _ := after.Get("filled"); rec.State != QuoteStateFilled {
+		t.Errorf("filled quote state = %v, want %v", rec.State, QuoteStateFilled)
+	}
+	if rec, _ := after.Get("lapsed"); rec.State != QuoteStateExpired {
+		t.Errorf("lapsed quote state = %v, want %v", rec.State, QuoteStateExpired)
+	}
+	if got := after.Exposure()["56:"+strings.ToLower(open.TokenOut.Hex())]; got == nil || got.Int64() != 600 {
+		t.Errorf("exposure = %v, want 600", got)
+	}
+	if next, ok := after.NextExpiry(); !ok || next.Unix() != open.Deadline+1 {
+		t.Errorf("NextExpiry = %v, %v, want the open quote's deadline", next, ok)
+	}
+}
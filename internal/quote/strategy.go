@@ -5,6 +5,8 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
 )
 
 // QuoteStrategy is the quote strategy interface
@@ -26,10 +28,20 @@ type QuoteParams struct {
 
 // QuoteResult represents the quote result
 type QuoteResult struct {
-	AmountOut        *big.Int   // Output amount (native decimals)
-	AmountOutMinimum *big.Int   // Minimum output amount (native decimals)
-	ExecutionPrice   *big.Float // Execution price (outputToken/inputToken)
-	PriceImpact      float64    // Price impact (percentage, e.g., 0.05 means 0.05%)
+	AmountOut        *big.Int        // Output amount (native decimals)
+	AmountOutMinimum *big.Int        // Minimum output amount (native decimals)
+	ExecutionPrice   decimal.Decimal // Execution price (outputToken/inputToken)
+	PriceImpact      float64         // Price impact (percentage, e.g., 0.05 means 0.05%)
+	Info             QuoteInfo       // How the price was built (optional)
+}
+
+// QuoteInfo describes how a quote was priced, for takers and internal analytics
+// The protocol has no fields for it yet, so it is recorded in the quote store only
+type QuoteInfo struct {
+	FeeBps      uint32           // Effective fee or spread taken from the output
+	Route       []common.Address // Tokens the price was composed through, input to output
+	PriceSource string           // Strategy or feed the price came from
+	MidPrice    decimal.Decimal  // Price before fees at quote time (outputToken/inputToken)
 }
 
 // NewQuoteResult creates a quote result
@@ -37,7 +49,7 @@ func NewQuoteResult(amountOut *big.Int) *QuoteResult {
 	return &QuoteResult{
 		AmountOut:        amountOut,
 		AmountOutMinimum: amountOut, // No slippage deduction
-		ExecutionPrice:   big.NewFloat(0),
+		ExecutionPrice:   decimal.Zero,
 		PriceImpact:      0,
 	}
 }
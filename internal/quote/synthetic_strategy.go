@@ -0,0 +1,130 @@
+package quote
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// SyntheticStrategy prices pairs without a direct feed by composing the prices of their legs
+// Example: WBNB-USDC via USDT is priced WBNB/USDT x USDT/USDC from Feed. Pairs without a
+// route are passed to Next. A composed price is only as fresh as its oldest leg: it is
+// rejected when that leg is older than MaxAge, or when the legs were observed more than
+// MaxSkew apart.
+type SyntheticStrategy struct {
+	Next      QuoteStrategy // Required
+	Feed      PriceFeed     // Required
+	SpreadBps uint32
+	MaxAge    time.Duration // 0 = no check
+	MaxSkew   time.Duration // 0 = no check
+	Now       func() time.Time
+
+	cfg    *config.Config
+	routes map[string][]common.Address // Pair ID -> intermediate tokens, base to quote
+}
+
+// NewSyntheticStrategy creates a synthetic strategy for the routes of cfg.Synthetic
+func NewSyntheticStrategy(next QuoteStrategy, feed PriceFeed, cfg *config.Config) *SyntheticStrategy {
+	routes := make(map[string][]common.Address, len(cfg.Synthetic.Routes))
+	for _, route := range cfg.Synthetic.Routes {
+		via := make([]common.Address, len(route.Via))
+		for i, token := range route.Via {
+			via[i] = common.HexToAddress(token)
+		}
+		routes[route.PairID] = via
+	}
+	return &SyntheticStrategy{
+		Next:      next,
+		Feed:      feed,
+		SpreadBps: cfg.Synthetic.SpreadBps,
+		MaxAge:    cfg.Synthetic.MaxAge,
+		MaxSkew:   cfg.Synthetic.MaxSkew,
+		Now:       time.Now,
+		cfg:       cfg,
+		routes:    routes,
+	}
+}
+
+// CalculateQuote quotes routed pairs from the composed price and passes other pairs to Next
+func (s *SyntheticStrategy) CalculateQuote(ctx context.Context, params *QuoteParams) (*QuoteResult, error) {
+	pair := s.cfg.GetPairConfigByAddress(params.ChainID, params.TokenIn, params.TokenOut)
+	if pair == nil {
+		return s.Next.CalculateQuote(ctx, params)
+	}
+	via, ok := s.routes[pair.PairID]
+	if !ok {
+		return s.Next.CalculateQuote(ctx, params)
+	}
+
+	// Walk the route in the direction of the request
+	path := make([]common.Address, 0, len(via)+2)
+	path = append(path, params.TokenIn)
+	if params.TokenIn == common.HexToAddress(pair.BaseToken) {
+		path = append(path, via...)
+	} else {
+		for i := len(via) - 1; i >= 0; i-- {
+			path = append(path, via[i])
+		}
+	}
+	path = append(path, params.TokenOut)
+
+	price, err := s.composePrice(ctx, params.ChainID, path)
+	if err != nil {
+		return nil, fmt.Errorf("synthetic price for %s: %w", pair.PairID, err)
+	}
+
+	// amountOut = amountIn * price * (10000 - spread) / 10000, truncated once
+	amountOut := price.MulInt(params.AmountIn).Mul(decimal.New(10000-int64(s.SpreadBps), -4)).Int()
+	if amountOut.Sign() <= 0 {
+		return nil, fmt.Errorf("calculated amount out is zero or negative")
+	}
+
+	result := NewQuoteResult(amountOut)
+	result.ExecutionPrice = price
+	result.PriceImpact = float64(s.SpreadBps) / 100
+	result.Info = QuoteInfo{
+		FeeBps:      s.SpreadBps,
+		Route:       path,
+		PriceSource: "synthetic",
+		MidPrice:    price,
+	}
+	return result, nil
+}
+
+// composePrice multiplies the feed prices of consecutive tokens of path
+// and checks the compounded staleness of the legs
+func (s *SyntheticStrategy) composePrice(ctx context.Context, chainID uint64, path []common.Address) (decimal.Decimal, error) {
+	price := decimal.NewFromInt(1)
+	times := make([]time.Time, 0, len(path)-1)
+	for i := 0; i+1 < len(path); i++ {
+		leg, observed, err := s.Feed.Price(ctx, chainID, path[i], path[i+1])
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("leg %s -> %s: %w", path[i].Hex(), path[i+1].Hex(), err)
+		}
+		if leg.Sign() <= 0 {
+			return decimal.Zero, fmt.Errorf("leg %s -> %s: non-positive price %s", path[i].Hex(), path[i+1].Hex(), leg)
+		}
+		price = price.Mul(leg)
+		times = append(times, observed)
+	}
+
+	oldest := slices.MinFunc(times, time.Time.Compare)
+	newest := slices.MaxFunc(times, time.Time.Compare)
+	if s.MaxAge > 0 {
+		if age := s.Now().Sub(oldest); age > s.MaxAge {
+			return decimal.Zero, fmt.Errorf("stale leg price: %v old, max %v", age, s.MaxAge)
+		}
+	}
+	if s.MaxSkew > 0 {
+		if skew := newest.Sub(oldest); skew > s.MaxSkew {
+			return decimal.Zero, fmt.Errorf("leg prices %v apart, max %v", skew, s.MaxSkew)
+		}
+	}
+	return price, nil
+}
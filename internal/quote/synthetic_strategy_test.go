@@ -0,0 +1,144 @@
+package quote
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// feedPrice is a price of fakeFeed with its observation time
+type feedPrice struct {
+	price decimal.Decimal
+	time  time.Time
+}
+
+// fakeFeed serves fixed prices per direction
+type fakeFeed map[[2]common.Address]feedPrice
+
+func (f fakeFeed) Price(ctx context.Context, chainID uint64, tokenIn, tokenOut common.Address) (decimal.Decimal, time.Time, error) {
+	p, ok := f[[2]common.Address{tokenIn, tokenOut}]
+	if !ok {
+		return decimal.Zero, time.Time{}, errors.New("no price")
+	}
+	return p.price, p.time, nil
+}
+
+// newSyntheticTestStrategy routes WBNB-USDC via USDT, and passes WBNB-USDT to a mock strategy
+func newSyntheticTestStrategy(feed fakeFeed, now time.Time) *SyntheticStrategy {
+	cfg := &config.Config{
+		Pairs: []config.PairConfig{
+			{ChainID: 56, PairID: "WBNB-USDC", BaseToken: stableWBNB.Hex(), QuoteToken: stableUSDC.Hex()},
+			{ChainID: 56, PairID: "WBNB-USDT", BaseToken: stableWBNB.Hex(), QuoteToken: stableUSDT.Hex()},
+		},
+		Synthetic: config.SyntheticConfig{
+			SpreadBps: 50,
+			MaxAge:    10 * time.Second,
+			MaxSkew:   2 * time.Second,
+			Routes:    []config.SyntheticRoute{{PairID: "WBNB-USDC", Via: []string{stableUSDT.Hex()}}},
+		},
+	}
+	cfg.BuildIndex()
+
+	next := NewMockStrategyWithSeed(0, 1)
+	next.SetPrice(56, stableWBNB, stableUSDT, decimal.NewFromInt(600))
+	s := NewSyntheticStrategy(next, feed, cfg)
+	s.Now = func() time.Time { return now }
+	return s
+}
+
+func TestSyntheticStrategy_ComposesLegs(t *testing.T) {
+	now := time.Unix(1735084800, 0)
+	feed := fakeFeed{
+		{stableWBNB, stableUSDT}: {decimal.NewFromInt(600), now},
+		{stableUSDT, stableUSDC}: {decimal.MustParse("0.999"), now},
+		{stableUSDC, stableUSDT}: {decimal.MustParse("1.25"), now},
+		{stableUSDT, stableWBNB}: {decimal.MustParse("0.002"), now},
+	}
+	s := newSyntheticTestStrategy(feed, now)
+
+	tests := []struct {
+		name     string
+		in, out  common.Address
+		amountIn *big.Int
+		want     string
+	}{
+		// 1 WBNB * 600 * 0.999 * 0.995
+		{"base to quote", stableWBNB, stableUSDC, big.NewInt(1e18), "596403000000000000000"},
+		// 1000 USDC * 1.25 * 0.002 * 0.995, legs walked in reverse
+		{"quote to base", stableUSDC, stableWBNB, new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18)), "2487500000000000000"},
+		// No route: the mock strategy's 600 minus its zero spread
+		{"direct pair uses Next", stableWBNB, stableUSDT, big.NewInt(1e18), "600000000000000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.CalculateQuote(context.Background(), &QuoteParams{ChainID: 56, TokenIn: tt.in, TokenOut: tt.out, AmountIn: tt.amountIn})
+			if err != nil {
+				t.Fatalf("CalculateQuote failed: %v", err)
+			}
+			if result.AmountOut.String() != tt.want {
+				t.Errorf("AmountOut = %v, want %v", result.AmountOut, tt.want)
+			}
+			if tt.name == "quote to base" && (len(result.Info.Route) != 3 || result.Info.Route[1] != stableUSDT) {
+				t.Errorf("Route = %v, want USDC -> USDT -> WBNB", result.Info.Route)
+			}
+		})
+	}
+}
+
+func TestSyntheticStrategy_CompoundedStaleness(t *testing.T) {
+	now := time.Unix(1735084800, 0)
+	params := &QuoteParams{ChainID: 56, TokenIn: stableWBNB, TokenOut: stableUSDC, AmountIn: big.NewInt(1e18)}
+	tests := []struct {
+		name       string
+		age1, age2 time.Duration
+		wantErr    string
+	}{
+		{"fresh", time.Second, 2 * time.Second, ""},
+		{"one stale leg", 9 * time.Second, 11 * time.Second, "stale leg price"},
+		{"legs apart", 0, 5 * time.Second, "apart"},
+		{"missing leg", 0, -1, "leg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := fakeFeed{{stableWBNB, stableUSDT}: {decimal.NewFromInt(600), now.Add(-tt.age1)}}
+			if tt.age2 >= 0 {
+				feed[[2]common.Address{stableUSDT, stableUSDC}] = feedPrice{decimal.NewFromInt(1), now.Add(-tt.age2)}
+			}
+			_, err := newSyntheticTestStrategy(feed, now).CalculateQuote(context.Background(), params)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CalculateQuote failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CalculateQuote error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMockStrategy_PriceFeed(t *testing.T) {
+	clock := time.Unix(1735084800, 0)
+	s := DefaultMockStrategyWithSeed(1)
+	s.Now = func() time.Time { return clock }
+
+	price, observed, err := s.Price(context.Background(), 56, stableWBNB, stableUSDT)
+	if err != nil {
+		t.Fatalf("Price failed: %v", err)
+	}
+	if price.Cmp(decimal.NewFromInt(600)) != 0 || !observed.Equal(clock) {
+		t.Errorf("Price = %v at %v, want 600 at %v", price, observed, clock)
+	}
+	if _, _, err := s.Price(context.Background(), 56, stableWBNB, stableUSDC); err == nil {
+		t.Error("Price succeeded for a pair without a price")
+	}
+}
@@ -0,0 +1,70 @@
+package recorder
+
+import (
+	"log/slog"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// RecordingClient wraps a WSClient and records all traffic
+// Recording failures are logged and never affect the session
+type RecordingClient struct {
+	ws.WSClient
+	recorder *Recorder
+	logger   *slog.Logger
+}
+
+// NewRecordingClient creates a recording client
+func NewRecordingClient(inner ws.WSClient, recorder *Recorder, logger *slog.Logger) *RecordingClient {
+	return &RecordingClient{
+		WSClient: inner,
+		recorder: recorder,
+		logger:   logger.With("component", "Recorder"),
+	}
+}
+
+// Send records and sends a Protobuf message
+func (c *RecordingClient) Send(msg *mmv1.Message) error {
+	c.record(DirectionOut, msg)
+	return c.WSClient.Send(msg)
+}
+
+// SendBatch records and sends several Protobuf messages
+func (c *RecordingClient) SendBatch(msgs []*mmv1.Message) error {
+	for _, msg := range msgs {
+		c.record(DirectionOut, msg)
+	}
+	return c.WSClient.SendBatch(msgs)
+}
+
+// SetMessageHandler sets the message handler callback
+// Server messages are recorded before the handler runs
+func (c *RecordingClient) SetMessageHandler(handler ws.MessageHandler) {
+	c.WSClient.SetMessageHandler(func(msg *mmv1.Message) error {
+		c.record(DirectionIn, msg)
+		return handler(msg)
+	})
+}
+
+// QueueDepth returns the outbound queue depth of the wrapped client, 0 if it does not queue sends
+func (c *RecordingClient) QueueDepth() int {
+	depth, _ := ws.QueueDepth(c.WSClient)
+	return depth
+}
+
+// record writes one message, logging failures
+func (c *RecordingClient) record(direction string, msg *mmv1.Message) {
+	if err := c.recorder.Record(direction, msg); err != nil {
+		c.logger.Warn("Failed to record message", "direction", direction, "error", err)
+	}
+}
+
+// Close closes the connection, then the recorder
+func (c *RecordingClient) Close() error {
+	err := c.WSClient.Close()
+	if closeErr := c.recorder.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,122 @@
+package recorder
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// Message directions
+const (
+	DirectionIn  = "in"  // Server -> MM
+	DirectionOut = "out" // MM -> server
+)
+
+// Entry is one recorded message
+type Entry struct {
+	Time      time.Time
+	Direction string
+	Message   *mmv1.Message
+}
+
+// line is the on-disk JSON lines format of an entry
+type line struct {
+	Time      time.Time       `json:"time"`
+	Direction string          `json:"dir"`
+	Message   json.RawMessage `json:"msg"`
+}
+
+// Recorder writes session messages as JSON lines
+// Messages use protojson so recordings stay readable and survive proto field additions
+type Recorder struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewRecorder creates a recorder writing to w
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// OpenFile creates a recorder appending to the file at path
+func OpenFile(path string) (*Recorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	return &Recorder{w: f, closer: f}, nil
+}
+
+// Record writes one message
+func (r *Recorder) Record(direction string, msg *mmv1.Message) error {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	out, err := json.Marshal(line{Time: time.Now(), Direction: direction, Message: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode entry: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.w.Write(append(out, '\n'))
+	return err
+}
+
+// Close closes the underlying file, if any
+func (r *Recorder) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+// Read reads all entries from a recording
+func Read(rd io.Reader) ([]Entry, error) {
+	entries := make([]Entry, 0)
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var l line
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		msg := &mmv1.Message{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(l.Message, msg); err != nil {
+			return nil, fmt.Errorf("line %d: invalid message: %w", n, err)
+		}
+		entries = append(entries, Entry{Time: l.Time, Direction: l.Direction, Message: msg})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	return entries, nil
+}
+
+// ReadFile reads all entries from a recording file
+func ReadFile(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer f.Close()
+	return Read(f)
+}
@@ -0,0 +1,62 @@
+package recorder
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+func TestRecorder_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf)
+
+	msgs := []*mmv1.Message{
+		{
+			Type: mmv1.MessageType_MESSAGE_TYPE_QUOTE_REQUEST,
+			Payload: &mmv1.Message_QuoteRequest{
+				QuoteRequest: &mmv1.QuoteRequest{QuoteId: "q1", ChainId: 56, AmountIn: "1000000000000000000"},
+			},
+		},
+		{
+			Type: mmv1.MessageType_MESSAGE_TYPE_QUOTE_RESPONSE,
+			Payload: &mmv1.Message_QuoteResponse{
+				QuoteResponse: &mmv1.QuoteResponse{QuoteId: "q1", Order: &mmv1.SignedOrder{Signature: []byte{1, 2, 3}}},
+			},
+		},
+	}
+	if err := rec.Record(DirectionIn, msgs[0]); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := rec.Record(DirectionOut, msgs[1]); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	entries, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(entries))
+	}
+	for i, want := range []string{DirectionIn, DirectionOut} {
+		if entries[i].Direction != want {
+			t.Errorf("entries[%d].Direction = %s, want %s", i, entries[i].Direction, want)
+		}
+		if !proto.Equal(entries[i].Message, msgs[i]) {
+			t.Errorf("entries[%d].Message = %v, want %v", i, entries[i].Message, msgs[i])
+		}
+		if entries[i].Time.IsZero() {
+			t.Errorf("entries[%d].Time is zero", i)
+		}
+	}
+}
+
+func TestRead_InvalidLine(t *testing.T) {
+	_, err := Read(bytes.NewBufferString("{\"time\":\"2025-01-01T00:00:00Z\",\"dir\":\"in\",\"msg\":{}}\nnot json\n"))
+	if err == nil {
+		t.Error("Read should fail on invalid line")
+	}
+}
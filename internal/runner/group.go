@@ -0,0 +1,102 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/buildinfo"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/metrics"
+)
+
+// Group runs several MM identities, configured under instances, in one process
+// Every instance has its own runner: signer, connection, quote handler and depth pusher. The
+// strategy, depth provider, price feeds and chain clients are built once and shared, and the
+// metrics of all instances are served once on metrics.listen, labelled by instance.
+type Group struct {
+	cfg     *config.Config
+	logger  *slog.Logger
+	prices  *pricing // Shared by the runners; the group runs its scheduler
+	names   []string
+	runners []*Runner
+}
+
+// NewGroup creates the runners of every instance of cfg
+func NewGroup(cfg *config.Config, logger *slog.Logger) (*Group, error) {
+	prices, err := newPricing(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	g := &Group{cfg: cfg, logger: logger, prices: prices}
+	for _, instanceCfg := range cfg.ForInstances() {
+		name := instanceCfg.App.Name
+		r, err := newRunner(instanceCfg, logger.With("instance", name), nil, prices)
+		if err != nil {
+			return nil, fmt.Errorf("instance %s: %w", name, err)
+		}
+		g.names = append(g.names, name)
+		g.runners = append(g.runners, r)
+	}
+	return g, nil
+}
+
+// Runners returns the runners of the instances, in config order
+func (g *Group) Runners() []*Runner {
+	return g.runners
+}
+
+// Metrics returns the registry of the metrics of all instances, labelled by instance, and the build info
+func (g *Group) Metrics() *metrics.Registry {
+	registry := metrics.NewRegistry()
+	registry.Register(buildInfoCollector(buildinfo.Get()))
+	if g.prices.mid != nil {
+		registry.Register(g.prices.mid)
+	}
+	for i, r := range g.runners {
+		registry.Register(metrics.WithLabels(r.Metrics(), "instance", g.names[i]))
+	}
+	return registry
+}
+
+// Run runs every instance until ctx is done or a signal arrives
+// An instance that fails stops the others; the errors of all instances are returned.
+func (g *Group) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if g.cfg.Metrics.Enabled {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", g.Metrics())
+		mux.HandleFunc("/buildinfo", serveBuildInfo)
+		if err := g.runners[0].serveHTTP(ctx, g.cfg.Metrics.Listen, mux, "Metrics endpoint"); err != nil {
+			return fmt.Errorf("failed to start metrics endpoint: %w", err)
+		}
+	}
+	if g.prices.scheduler != nil {
+		go g.prices.scheduler.Run(ctx)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for i, r := range g.runners {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.Run(ctx); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("instance %s: %w", g.names[i], err))
+				mu.Unlock()
+			}
+			cancel()
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,162 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/buildinfo"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/metrics"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/query"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
+)
+
+// Metrics returns the registry of the metrics served on metrics.listen
+func (r *Runner) Metrics() *metrics.Registry {
+	registry := metrics.NewRegistry()
+	if r.traffic != nil {
+		registry.Register(trafficCollector(r.traffic))
+	}
+	if r.budget != nil {
+		budget := r.budget
+		registry.Register(metrics.CollectorFunc(func(w *metrics.Writer) {
+			w.Header("mm_depth_pushes_deferred_total", "counter", "Depth pushes deferred by the outbound byte budget")
+			w.Sample("mm_depth_pushes_deferred_total", float64(budget.Deferred()))
+		}))
+	}
+	registry.Register(eventCollector(r.bus))
+	if r.mid != nil {
+		registry.Register(r.mid)
+	}
+	if r.backup != nil {
+		failover := r.backup
+		registry.Register(metrics.CollectorFunc(func(w *metrics.Writer) {
+			state := failover.State()
+			backup := 0.0
+			if state.Active == signer.BackupKeyName {
+				backup = 1
+			}
+			w.Header("mm_signer_backup_active", "gauge", "1 while quotes are signed with the backup key")
+			w.Sample("mm_signer_backup_active", backup)
+			w.Header("mm_signer_failovers_total", "counter", "Failovers from the primary to the backup signing key")
+			w.Sample("mm_signer_failovers_total", float64(state.Failovers))
+		}))
+	}
+	if r.stormGuard != nil {
+		guard := r.stormGuard
+		registry.Register(metrics.CollectorFunc(func(w *metrics.Writer) {
+			w.Header("mm_ws_reconnect_storms_total", "counter", "Reconnect storm cool-offs started")
+			w.Sample("mm_ws_reconnect_storms_total", float64(guard.Storms()))
+		}))
+	}
+	return registry
+}
+
+// buildInfoCollector exports the build of the binary as labels of a constant 1
+func buildInfoCollector(info buildinfo.Info) metrics.Collector {
+	return metrics.CollectorFunc(func(w *metrics.Writer) {
+		w.Header("mm_build_info", "gauge", "Build of the running MM, always 1")
+		w.Sample("mm_build_info", 1, "version", info.Version, "commit", info.Commit, "build_time", info.BuildTime, "go_version", info.GoVersion)
+	})
+}
+
+// eventCollector exports the events published by kind and the events each subscriber missed
+func eventCollector(bus *events.Bus) metrics.Collector {
+	return metrics.CollectorFunc(func(w *metrics.Writer) {
+		published := bus.Published()
+		kinds := make([]string, 0, len(published))
+		for kind := range published {
+			kinds = append(kinds, string(kind))
+		}
+		sort.Strings(kinds)
+		w.Header("mm_events_published_total", "counter", "Events published on the internal event bus by kind")
+		for _, kind := range kinds {
+			w.Sample("mm_events_published_total", float64(published[events.Kind(kind)]), "kind", kind)
+		}
+		w.Header("mm_events_dropped_total", "counter", "Events a bus subscriber missed because it fell behind")
+		for _, sub := range bus.Subscriptions() {
+			w.Sample("mm_events_dropped_total", float64(sub.Dropped()), "subscriber", sub.Name())
+		}
+	})
+}
+
+// trafficCollector exports the WebSocket traffic by direction and message type
+func trafficCollector(traffic *ws.Traffic) metrics.Collector {
+	bounds := make([]float64, len(ws.TrafficSizeBuckets))
+	for i, b := range ws.TrafficSizeBuckets {
+		bounds[i] = float64(b)
+	}
+	return metrics.CollectorFunc(func(w *metrics.Writer) {
+		w.Header("mm_ws_message_size_bytes", "histogram", "Size of WebSocket messages by direction and message type")
+		for _, c := range traffic.Snapshot() {
+			w.Histogram("mm_ws_message_size_bytes", bounds, c.Sizes, float64(c.Bytes), "direction", c.Direction, "type", c.Type)
+		}
+	})
+}
+
+// serveConnections serves the recent connection events as JSON, oldest first
+func (r *Runner) serveConnections(w http.ResponseWriter, _ *http.Request) {
+	events := r.events.Events()
+	if events == nil {
+		events = []ws.ConnectionEvent{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(events)
+}
+
+// serveBuildInfo serves the build of the binary as JSON
+func serveBuildInfo(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(buildinfo.Get())
+}
+
+// serveQuery serves the read-only query API on query.listen until ctx is done
+func (r *Runner) serveQuery(ctx context.Context) error {
+	api := query.NewAPI(r.cfg, query.Sources{
+		Pusher:      r.depthPusher,
+		Quotes:      r.quoteHandler.Store(),
+		Consistency: r.consistency,
+	}, r.logger)
+	api.Follow(ctx, r.bus)
+	return r.serveHTTP(ctx, r.cfg.Query.Listen, api, "Query API")
+}
+
+// serveMetrics serves /metrics, /connections and /buildinfo on metrics.listen until ctx is done
+func (r *Runner) serveMetrics(ctx context.Context) error {
+	mux := http.NewServeMux()
+	registry := r.Metrics()
+	registry.Register(buildInfoCollector(buildinfo.Get()))
+	mux.Handle("/metrics", registry)
+	mux.HandleFunc("/connections", r.serveConnections)
+	mux.HandleFunc("/buildinfo", serveBuildInfo)
+	return r.serveHTTP(ctx, r.cfg.Metrics.Listen, mux, "Metrics endpoint")
+}
+
+// serveHTTP serves handler on listen until ctx is done
+func (r *Runner) serveHTTP(ctx context.Context, listen string, handler http.Handler, name string) error {
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			r.logger.Error(name+" failed", "error", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	r.logger.Info(name+" started", "addr", ln.Addr().String())
+	return nil
+}
@@ -0,0 +1,28 @@
+package runner
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/nonce"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+)
+
+// nonceDispositions maps the closed quote states to nonce dispositions
+var nonceDispositions = map[quote.QuoteState]string{
+	quote.QuoteStateFilled:  nonce.Filled,
+	quote.QuoteStateExpired: nonce.Expired,
+	quote.QuoteStateFailed:  nonce.Failed,
+}
+
+// disposeNonce records in the nonce manager how the quote of rec ended
+func (r *Runner) disposeNonce(rec quote.QuoteRecord) {
+	disposition, ok := nonceDispositions[rec.State]
+	if !ok {
+		return
+	}
+	var rfqManager common.Address
+	if domain := r.cfg.GetEIP712Domain(rec.ChainID); domain != nil {
+		rfqManager = common.HexToAddress(domain.VerifyingContract)
+	}
+	r.nonces.Dispose(r.nonces.Key(rec.ChainID, rfqManager), rec.Nonce, disposition)
+}
@@ -0,0 +1,58 @@
+package runner
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+// NewQuoteHandler builds the quote pipeline of cfg without a connection: the configured strategy,
+// the request checks and the signer, as the runner quotes. Co-signing, shadowing and the
+// signing key pool are left out. Used to quote offline, e.g. by mm quote simulate.
+func NewQuoteHandler(cfg *config.Config, logger *slog.Logger) (*quote.Handler, *signer.DomainManager, error) {
+	domains, s, err := offlineSigner(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	prices, err := newPricing(cfg, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	return quote.NewHandler(prices.strategy, s, cfg, logger), domains, nil
+}
+
+// NewDepthPusher builds the depth pusher of cfg without a connection: the configured depth
+// provider, oracle band and signer, as the runner pushes. It must not be started; use
+// Preview to build snapshots, e.g. for mm depth preview.
+func NewDepthPusher(cfg *config.Config, logger *slog.Logger) (*depth.Pusher, error) {
+	_, s, err := offlineSigner(cfg)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := newPricing(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	pusher := depth.NewPusher(nil, prices.depth, nil, s, cfg, logger)
+	if prices.reference != nil {
+		pusher.SetReferenceFeed(prices.reference)
+	}
+	return pusher, nil
+}
+
+// offlineSigner builds the EIP-712 domains and the primary signer of cfg
+func offlineSigner(cfg *config.Config) (*signer.DomainManager, signer.Signer, error) {
+	domains, err := DomainManager(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := newPrimarySigner(cfg, domains)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create signer: %w", err)
+	}
+	return domains, s, nil
+}
@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+)
+
+// revocationCheckInterval is how often the revocation file is re-read
+const revocationCheckInterval = 5 * time.Second
+
+// revocationLoop applies the revocation file every revocationCheckInterval until ctx is done
+// A missing file revokes nothing. A file that cannot be parsed is rejected as a whole and
+// reported once, until it changes or is accepted again; the current revocations stay in force.
+func (r *Runner) revocationLoop(ctx context.Context) {
+	ticker := time.NewTicker(revocationCheckInterval)
+	defer ticker.Stop()
+
+	var rejected string // Error of the last rejected file
+	for {
+		if err := r.applyRevocationFile(); err != nil {
+			if err.Error() != rejected {
+				r.logger.Error("Revocation file rejected, keeping the current revocations",
+					"path", r.cfg.Quote.RevocationFile, "error", err)
+			}
+			rejected = err.Error()
+		} else {
+			rejected = ""
+		}
+		r.revocations.Cancel(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// applyRevocationFile replaces the revocation list with the entries of the revocation file
+// Newly revoked quotes are logged with their local state; open ones may still be filled
+func (r *Runner) applyRevocationFile() error {
+	revs, err := readRevocationFile(r.cfg.Quote.RevocationFile)
+	if err != nil {
+		return err
+	}
+	for _, rev := range r.revocations.Set(revs) {
+		rec, ok := r.quoteHandler.Store().Get(rev.QuoteID)
+		if !ok {
+			r.logger.Warn("Quote revoked", "quoteId", rev.QuoteID, "nonce", rev.Nonce, "reason", rev.Reason, "state", "unknown")
+			continue
+		}
+		r.logger.Warn("Quote revoked",
+			"quoteId", rev.QuoteID,
+			"nonce", rev.Nonce,
+			"reason", rev.Reason,
+			"state", rec.State,
+			"deadline", rec.Deadline)
+	}
+	return nil
+}
+
+// readRevocationFile returns the revocations listed in path
+func readRevocationFile(path string) ([]quote.Revocation, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return quote.ParseRevocations(f)
+}
@@ -6,12 +6,28 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/admin"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/buildinfo"
 	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/cosign"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
 	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/flow"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/nonce"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/profiling"
 	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/recorder"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/schedule"
 	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/store"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/webhook"
 	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
 )
 
@@ -21,19 +37,367 @@ type Runner struct {
 	cfg          *config.Config
 	logger       *slog.Logger
 	wsClient     ws.WSClient
+	failover     *ws.FailoverClient // nil without websocket.standby
+	traffic      *ws.Traffic        // Messages on the wire, nil with an injected client
+	stormGuard   *ws.StormGuard     // nil without websocket.reconnectStorm.maxAttempts
+	budget       *ws.Budget         // nil without websocket.bandwidth.maxOutboundBytes
+	events       *ws.EventLog       // nil without websocket.eventHistory
+	bus          *events.Bus        // Quote, connection and risk events of every module
 	signer       signer.Signer
+	signerPool   *signer.Pool     // nil unless signer.pool has keys
+	backup       *signer.Failover // nil without signer.backup
+	state        store.Store      // Quote journal and persisted counters
 	quoteHandler *quote.Handler
 	depthPusher  *depth.Pusher
+	scheduler    *schedule.Scheduler // nil unless schedule.enabled
+	domains      *signer.DomainManager
+	consistency  *depth.ConsistencyChecker // nil unless consistency.enabled
+	shadow       *quote.ShadowStrategy     // nil unless shadow.enabled
+	revocations  *quote.RevocationList     // nil without quote.revocationFile
+	nonces       *nonce.Manager            // nil under quote.nonce.mode "request"
+	webhooks     *webhook.Dispatcher       // nil without webhook.endpoints
+	mid          *depth.MidFeed            // Weighted mids, nil without mid.pairs or when shared by a group
+	flowExport   *flow.Exporter            // nil unless flowExport.enabled
+	admin        *admin.API                // nil unless admin.enabled
+	session      *session                  // Started by Run, summarized by Shutdown
+
+	healthMu     sync.Mutex
+	signerHealth SignerHealth
+
+	rotateMu sync.Mutex // Serializes key rotations
 }
 
 // New creates a service runner
 func New(cfg *config.Config, logger *slog.Logger) (*Runner, error) {
+	return NewWithClient(cfg, logger, nil)
+}
+
+// NewWithClient creates a service runner using the given WebSocket client
+// A nil client creates the default client from cfg.WebSocket
+// Used to wrap or replace the transport, e.g. by the conformance tests
+func NewWithClient(cfg *config.Config, logger *slog.Logger, wsClient ws.WSClient) (*Runner, error) {
+	return newRunner(cfg, logger, wsClient, nil)
+}
+
+// newRunner creates a service runner quoting with prices; nil prices are built from cfg
+func newRunner(cfg *config.Config, logger *slog.Logger, wsClient ws.WSClient, prices *pricing) (*Runner, error) {
 	r := &Runner{
 		cfg:    cfg,
 		logger: logger,
+		bus:    events.NewBus(logger),
 	}
 
 	// 1. Initialize EIP-712 Domain Manager
+	domainManager, err := DomainManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+	registry := cfg.ChainRegistry()
+	for _, domain := range cfg.EIP712Domains {
+		logger.Info("Registered EIP-712 domain",
+			"chainId", domain.ChainID,
+			"chain", registry.Name(domain.ChainID),
+			"verifyingContract", domain.VerifyingContract,
+			"explorer", registry.AddressURL(domain.ChainID, common.HexToAddress(domain.VerifyingContract)),
+			"quoteTypeHash", domainManager.GetQuoteType(domain.ChainID).TypeHash.Hex())
+	}
+	r.domains = domainManager
+
+	// 2. Initialize signer
+	s, err := newPrimarySigner(cfg, domainManager)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signer: %w", err)
+	}
+	logger.Info("Signer initialized", "address", s.GetAddress().Hex())
+	if wallet := contractWallet(s); wallet != nil {
+		logger.Info("Signing for contract wallet", "wallet", wallet.GetAddress().Hex(),
+			"owner", wallet.Owner().GetAddress().Hex(), "format", cfg.Signer.ContractWallet.Format)
+	}
+	if multi, ok := s.(*signer.MultiSigner); ok {
+		for _, key := range multi.Chains() {
+			logger.Info("Chain signing key initialized", "chainId", key.ChainID, "address", key.Signer.GetAddress().Hex())
+		}
+	}
+	if cfg.Signer.Backup.Enabled() {
+		failover, err := r.newSignerFailover(s, domainManager)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create backup signer: %w", err)
+		}
+		s, r.backup = failover, failover
+		logger.Info("Backup signer initialized",
+			"address", failover.Backup().GetAddress().Hex(),
+			"failAfter", cfg.Signer.Backup.FailAfter,
+			"failBack", cfg.Signer.Backup.FailBack)
+	}
+	r.signer = s
+	if len(cfg.Signer.Pool.Keys) > 0 {
+		pool, err := newSignerPool(cfg, s, domainManager)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create signer pool: %w", err)
+		}
+		r.signer, r.signerPool = pool, pool
+		for _, key := range pool.Keys() {
+			logger.Info("Signing key added to pool", "name", key.Name, "address", key.Address.Hex(), "drained", key.Drained)
+		}
+	}
+
+	// 3. Initialize WebSocket client
+	if wsClient != nil {
+		r.wsClient = wsClient
+	} else {
+		r.traffic = ws.NewTraffic()
+		if storm := cfg.WebSocket.ReconnectStorm; storm.MaxAttempts > 0 {
+			r.stormGuard = ws.NewStormGuard(storm.MaxAttempts, storm.Window, storm.CoolOff, logger)
+		}
+		if bw := cfg.WebSocket.Bandwidth; bw.MaxOutboundBytes > 0 {
+			r.budget = ws.NewBudget(bw.MaxOutboundBytes, bw.Window, bw.Reserve)
+			logger.Info("Outbound byte budget enabled", "bytes", bw.MaxOutboundBytes, "window", bw.Window)
+		}
+		if cfg.WebSocket.EventHistory > 0 {
+			r.events = ws.NewEventLog(cfg.WebSocket.EventHistory)
+		}
+		wsCfg := WSConfig(cfg)
+		wsCfg.Events = r.events
+		wsCfg.Traffic = r.traffic
+		wsCfg.StormGuard = r.stormGuard
+		wsCfg.Budget = r.budget
+		wsCfg.Bus = r.bus
+		if cfg.WebSocket.HeartbeatHealth {
+			wsCfg.Health = r.clientHealth
+		}
+		r.wsClient = ws.NewClient(wsCfg, logger)
+	}
+	if cfg.WebSocket.Standby.ServerURL != "" {
+		standbyCfg := StandbyWSConfig(cfg)
+		standbyCfg.Events = r.events
+		standbyCfg.Traffic = r.traffic
+		standbyCfg.StormGuard = r.stormGuard
+		standbyCfg.Budget = r.budget
+		standbyCfg.Bus = r.bus
+		if cfg.WebSocket.HeartbeatHealth {
+			standbyCfg.Health = r.clientHealth
+		}
+		r.failover = ws.NewFailoverClient(r.wsClient, ws.NewClient(standbyCfg, logger.With("connection", "standby")),
+			cfg.WebSocket.HeartbeatInterval, logger)
+		r.wsClient = r.failover
+		logger.Info("Standby connection enabled", "url", cfg.WebSocket.Standby.ServerURL)
+	}
+	if cfg.Recorder.Enabled {
+		rec, err := recorder.OpenFile(cfg.Recorder.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create recorder: %w", err)
+		}
+		r.wsClient = recorder.NewRecordingClient(r.wsClient, rec, logger)
+		logger.Info("Session recording enabled", "path", cfg.Recorder.Path)
+	}
+
+	// 4. Initialize quote strategy and depth data provider, unless shared by a group
+	if prices == nil {
+		var err error
+		if prices, err = newPricing(cfg, logger); err != nil {
+			return nil, err
+		}
+		r.scheduler = prices.scheduler
+		r.mid = prices.mid
+	}
+	strategy := prices.strategy
+
+	// 5. Initialize quote handler (shadowing RFQs with the candidate strategy)
+	live := strategy
+	if cfg.Shadow.Enabled {
+		candidate, err := newShadowCandidate(cfg, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create shadow strategy: %w", err)
+		}
+		r.shadow = quote.NewShadowStrategy(strategy, candidate, cfg.Shadow, logger)
+		live = r.shadow
+		logger.Info("Shadow strategy enabled",
+			"candidate", cfg.Shadow.Strategy.Name,
+			"timeout", cfg.Shadow.Timeout,
+			"maxInFlight", cfg.Shadow.MaxInFlight)
+	}
+	r.quoteHandler = quote.NewHandler(live, r.signer, cfg, logger)
+	r.quoteHandler.SetEventBus(r.bus)
+	if cfg.CoSign.Enabled {
+		r.quoteHandler.SetCoSigner(cosign.NewClient(cfg.CoSign, domainManager))
+		logger.Info("Co-signing enabled",
+			"address", cfg.CoSign.Address,
+			"pairs", len(cfg.CoSign.Thresholds),
+			"timeout", cfg.CoSign.Timeout)
+	}
+	if cfg.Quote.RevocationFile != "" {
+		r.revocations = quote.NewRevocationList(logger)
+	}
+	if err := r.openState(); err != nil {
+		return nil, err
+	}
+	if mode := cfg.Quote.Nonce.Mode; mode != "" && mode != nonce.ModeRequest {
+		nonces, err := nonce.NewManager(mode, cfg.Quote.Nonce.Scope, r.state)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create nonce manager: %w", err)
+		}
+		r.nonces = nonces
+		r.quoteHandler.SetNonces(nonces)
+		logger.Info("Nonce manager enabled", "mode", mode, "scope", cfg.Quote.Nonce.Scope)
+		if !store.Persistent(cfg.Store.Backend) {
+			logger.Warn("Nonce marks are kept in memory: signed nonces are forgotten on restart", "storeBackend", cfg.Store.Backend)
+		}
+	}
+	r.quoteHandler.Store().SetCloseHandler(func(rec quote.QuoteRecord) {
+		logger.Debug("Quote closed",
+			"quoteId", rec.QuoteID,
+			"nonce", rec.Nonce,
+			"state", rec.State,
+			"deadline", rec.Deadline)
+		if rec.State == quote.QuoteStateFilled {
+			r.bus.Publish(events.Event{Kind: events.FillObserved, ChainID: rec.ChainID, QuoteID: rec.QuoteID, Data: rec})
+		}
+		if r.revocations != nil {
+			r.revocations.OnClose(rec)
+		}
+		if r.nonces != nil {
+			r.disposeNonce(rec)
+		}
+	})
+
+	// 6. Initialize depth pusher
+	r.depthPusher = depth.NewPusher(r.wsClient, prices.depth, r.quoteHandler, r.signer, cfg, logger)
+	r.depthPusher.SetBudget(r.budget)
+	r.depthPusher.SetStore(r.state)
+	r.depthPusher.SetEventBus(r.bus)
+	if prices.reference != nil {
+		r.depthPusher.SetReferenceFeed(prices.reference)
+	}
+
+	// 7. Initialize depth/quote consistency checker (checks the strategy the handler uses)
+	if cfg.Consistency.Enabled {
+		r.consistency = depth.NewConsistencyChecker(r.depthPusher.PublishedBook, strategy, cfg, logger)
+		r.consistency.SetEventBus(r.bus)
+	}
+
+	// 8. Initialize webhook delivery of bus events
+	if len(cfg.Webhook.Endpoints) > 0 {
+		webhooks, err := webhook.NewDispatcher(cfg, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create webhook dispatcher: %w", err)
+		}
+		r.webhooks = webhooks
+		logger.Info("Webhooks enabled",
+			"endpoints", len(cfg.Webhook.Endpoints),
+			"largeQuotePairs", len(cfg.Webhook.LargeQuotes))
+	}
+
+	// 9. Initialize the anonymized RFQ flow export
+	if cfg.FlowExport.Enabled {
+		exporter, err := flow.NewExporter(cfg, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open flow export: %w", err)
+		}
+		r.flowExport = exporter
+		logger.Info("Flow export enabled", "file", cfg.FlowExport.File, "sampleRate", cfg.FlowExport.SampleRate)
+	}
+
+	// 10. Initialize the admin API (it makes quote and depth parameters adjustable)
+	if cfg.Admin.Enabled {
+		api, err := admin.NewAPI(cfg, admin.Sources{Pusher: r.depthPusher, Failover: r.backup, Rotate: r.RotateSigner}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create admin API: %w", err)
+		}
+		r.admin = api
+	}
+
+	return r, nil
+}
+
+// pricing is the quote strategy, depth provider and price feeds of a runner
+// The instances of a Group share one pricing, so price feeds and chain clients are not duplicated.
+type pricing struct {
+	strategy  quote.QuoteStrategy
+	depth     depth.DepthProvider
+	reference quote.PriceFeed     // Oracle band reference, nil unless oracle.enabled
+	scheduler *schedule.Scheduler // nil unless schedule.enabled
+	mid       *depth.MidFeed      // Weighted mids of mid.pairs, nil without any
+}
+
+// newPricing builds the strategy selected by cfg and wraps it for synthetic and stable pairs
+func newPricing(cfg *config.Config, logger *slog.Logger) (*pricing, error) {
+	strategy, depthProvider, err := newStrategy(cfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create strategy: %w", err)
+	}
+	p := &pricing{}
+	strategyFeed, _ := strategy.(quote.PriceFeed) // Before wrapping: the oracle band's fallback reference
+	if cfg.Depth.REST.URL != "" {
+		depthProvider = depth.NewRESTProvider(cfg, logger)
+		logger.Info("Depth provider initialized (REST)", "url", cfg.Depth.REST.URL, "units", cfg.Depth.REST.Mapping.Units)
+	}
+	if cfg.Schedule.Enabled {
+		p.scheduler = newScheduler(cfg, strategy, depthProvider, logger)
+	}
+	if len(cfg.Mid.Pairs) > 0 {
+		p.mid = depth.NewMidFeed(cfg, strategyFeed, logger)
+		strategy = quote.NewMidStrategy(strategy, p.mid, cfg)
+		depthProvider = depth.NewMidAnchoredProvider(depthProvider, p.mid)
+		logger.Info("Weighted mid pricing enabled", "pairs", len(cfg.Mid.Pairs), "refresh", cfg.Mid.Refresh)
+	}
+	if len(cfg.Synthetic.Routes) > 0 {
+		feed, ok := strategy.(quote.PriceFeed)
+		if !ok {
+			return nil, fmt.Errorf("strategy %q does not provide prices for synthetic routes", cfg.Strategy.Name)
+		}
+		strategy = quote.NewSyntheticStrategy(strategy, feed, cfg)
+		logger.Info("Synthetic pricing enabled",
+			"routes", len(cfg.Synthetic.Routes),
+			"maxAge", cfg.Synthetic.MaxAge,
+			"maxSkew", cfg.Synthetic.MaxSkew)
+	}
+	if len(cfg.Stable.Pairs) > 0 {
+		strategy = quote.NewStableStrategy(strategy, cfg)
+		logger.Info("Stable-pair quoting enabled",
+			"pairs", cfg.Stable.Pairs,
+			"feeBps", cfg.Stable.FeeBps,
+			"maxDeviationBps", cfg.Stable.MaxDeviationBps)
+	}
+	if cfg.Oracle.Enabled {
+		p.reference = depth.NewChainlinkFeed(cfg, strategyFeed)
+		logger.Info("Oracle band enabled",
+			"maxDeviationBps", cfg.Oracle.MaxDeviationBps,
+			"chainlinkFeeds", len(cfg.Oracle.Feeds),
+			"strategyFallback", strategyFeed != nil)
+	}
+	p.strategy, p.depth = strategy, depthProvider
+	return p, nil
+}
+
+// openState opens the state store and restores the quotes journaled before a restart
+func (r *Runner) openState() error {
+	state, err := store.Open(r.cfg.Store.Backend, r.cfg.Store.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open state store: %w", err)
+	}
+	r.state = state
+
+	quotes := r.quoteHandler.Store()
+	restored, err := quotes.Restore(state, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to restore quotes: %w", err)
+	}
+	quotes.SetJournal(state, r.logger)
+	r.logger.Info("State store opened",
+		"backend", r.cfg.Store.Backend,
+		"path", r.cfg.Store.Path,
+		"openQuotesRestored", restored)
+	return nil
+}
+
+// Events returns the event bus modules publish to
+// Subscribers such as alerting, webhooks or a hedger subscribe to it before Run.
+func (r *Runner) Events() *events.Bus {
+	return r.bus
+}
+
+// DomainManager builds the EIP-712 domains of the application configuration
+func DomainManager(cfg *config.Config) (*signer.DomainManager, error) {
 	domainManager := signer.NewDomainManager()
 	for _, domain := range cfg.EIP712Domains {
 		domainManager.AddPoolDomainWithConfig(
@@ -42,24 +406,83 @@ func New(cfg *config.Config, logger *slog.Logger) (*Runner, error) {
 			domain.Version,
 			domain.VerifyingContract,
 		)
-		logger.Info("Registered EIP-712 domain",
+		if err := domainManager.SetQuoteType(domain.ChainID, domain.QuoteType); err != nil {
+			return nil, fmt.Errorf("eip712Domains chain %d quoteType: %w", domain.ChainID, err)
+		}
+		if err := domainManager.SetDomainFields(domain.ChainID, domain.Fields, domain.Salt); err != nil {
+			return nil, fmt.Errorf("eip712Domains chain %d: %w", domain.ChainID, err)
+		}
+	}
+	return domainManager, nil
+}
+
+// domainCheckTimeout bounds the on-chain check of one domain
+const domainCheckTimeout = 10 * time.Second
+
+// chainIDCheckTimeout bounds the chain ID check of one RPC endpoint
+const chainIDCheckTimeout = 5 * time.Second
+
+// VerifyChainIDs checks that every configured RPC endpoint serves the chain it is configured for
+// Returns the first failure; chains without an RPC URL are skipped
+func VerifyChainIDs(ctx context.Context, cfg *config.Config, logger *slog.Logger) error {
+	for _, domain := range cfg.EIP712Domains {
+		if domain.RPCURL == "" {
+			continue
+		}
+		checkCtx, cancel := context.WithTimeout(ctx, chainIDCheckTimeout)
+		err := signer.VerifyChainID(checkCtx, signer.NewHTTPRPCClient(domain.RPCURL), domain.ChainID)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("eip712Domains chain %d: %w", domain.ChainID, err)
+		}
+		logger.Info("RPC chain ID verified", "chainId", domain.ChainID)
+	}
+	return nil
+}
+
+// VerifyDomains checks the domains that have an RPC URL against their verifying contracts
+// Returns the first failure; domains without an RPC URL are skipped
+func VerifyDomains(ctx context.Context, cfg *config.Config, domains *signer.DomainManager, logger *slog.Logger) error {
+	for _, domain := range cfg.EIP712Domains {
+		if domain.RPCURL == "" {
+			continue
+		}
+		checkCtx, cancel := context.WithTimeout(ctx, domainCheckTimeout)
+		err := domains.VerifyDomainAgainstChain(checkCtx, domain.ChainID, signer.NewHTTPRPCClient(domain.RPCURL))
+		cancel()
+		if err != nil {
+			return fmt.Errorf("eip712Domains chain %d: %w", domain.ChainID, err)
+		}
+		logger.Info("EIP-712 domain verified against chain",
 			"chainId", domain.ChainID,
 			"verifyingContract", domain.VerifyingContract)
 	}
+	return nil
+}
 
-	// 2. Initialize signer
-	s, err := signer.NewSignerFromConfig(&signer.SignerConfig{
-		PrivateKey:    cfg.Signer.PrivateKey,
-		PrivateKeyEnv: cfg.Signer.PrivateKeyEnv,
-	}, domainManager)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create signer: %w", err)
+// VerifyContractWallet checks that the wallet accepts the signatures of its owner on every chain
+// with an RPC URL, with a canary quote passed to its isValidSignature
+// Returns the first failure; chains without an RPC URL are skipped
+func VerifyContractWallet(ctx context.Context, cfg *config.Config, domains *signer.DomainManager, wallet *signer.ContractWallet, logger *slog.Logger) error {
+	for _, domain := range cfg.EIP712Domains {
+		if domain.RPCURL == "" {
+			continue
+		}
+		checkCtx, cancel := context.WithTimeout(ctx, domainCheckTimeout)
+		err := signer.CheckContractWallet(checkCtx, wallet, domains, domain.ChainID, signer.NewHTTPRPCClient(domain.RPCURL))
+		cancel()
+		if err != nil {
+			return fmt.Errorf("chain %d: %w", domain.ChainID, err)
+		}
+		logger.Info("Contract wallet signature verified", "chainId", domain.ChainID, "wallet", wallet.GetAddress().Hex())
 	}
-	r.signer = s
-	logger.Info("Signer initialized", "address", s.GetAddress().Hex())
+	return nil
+}
 
-	// 3. Initialize WebSocket client
-	wsConfig := &ws.Config{
+// WSConfig builds the WebSocket client configuration from the application configuration
+func WSConfig(cfg *config.Config) *ws.Config {
+	pins, _ := ws.ParsePins(cfg.WebSocket.TLSPins) // Checked by Validate
+	return &ws.Config{
 		ServerURL:            cfg.WebSocket.ServerURL,
 		APIToken:             cfg.WebSocket.APIToken,
 		ReconnectInterval:    cfg.WebSocket.ReconnectInterval,
@@ -67,30 +490,130 @@ func New(cfg *config.Config, logger *slog.Logger) (*Runner, error) {
 		HeartbeatInterval:    cfg.WebSocket.HeartbeatInterval,
 		ReadTimeout:          cfg.WebSocket.ReadTimeout,
 		WriteTimeout:         cfg.WebSocket.WriteTimeout,
+		TLSPins:              pins,
+		HandshakeTimeout:     cfg.WebSocket.Dialer.HandshakeTimeout,
+		KeepAlive:            cfg.WebSocket.Dialer.KeepAlive,
+		TLSSessionCache:      cfg.WebSocket.Dialer.TLSSessionCache,
+		DNSRecheckInterval:   cfg.WebSocket.Dialer.DNSRecheckInterval,
+		UserAgent:            buildinfo.Get().UserAgent(),
 	}
-	r.wsClient = ws.NewClient(wsConfig, logger)
-
-	// 4. Initialize quote strategy (using mock strategy)
-	strategy := quote.DefaultMockStrategy()
-	logger.Info("Quote strategy initialized (mock)")
+}
 
-	// 5. Initialize quote handler
-	r.quoteHandler = quote.NewHandler(strategy, s, cfg, logger)
+// newPrimarySigner creates the primary signer of cfg: the Ledger device of signer.ledger when
+// enabled, the service of signer.remote when set, the private key otherwise (rotatable), signing for signer.contractWallet when set. With
+// signer.chainKeys, it is the default key of a MultiSigner signing the quotes of those chains
+// with their own keys.
+func newPrimarySigner(cfg *config.Config, domains *signer.DomainManager) (signer.Signer, error) {
+	var primary signer.Signer
+	if ledger := cfg.Signer.Ledger; ledger.Enabled {
+		device, err := signer.OpenLedger(ledger.DerivationPath)
+		if err != nil {
+			return nil, err
+		}
+		primary = signer.NewHardwareSigner(device, domains, ledger.Timeout, cfg.Signer.MaxDeadlineHorizon)
+	} else if remote := cfg.Signer.Remote; remote.URL != "" {
+		rc := signer.RemoteConfig{
+			URL:                remote.URL,
+			CertFile:           remote.CertFile,
+			KeyFile:            remote.KeyFile,
+			CAFile:             remote.CAFile,
+			Timeout:            remote.Timeout,
+			Retries:            remote.Retries,
+			MaxDeadlineHorizon: cfg.Signer.MaxDeadlineHorizon,
+		}
+		if remote.Address != "" {
+			rc.Address = common.HexToAddress(remote.Address)
+		}
+		s, err := signer.NewRemoteSigner(context.Background(), rc, domains)
+		if err != nil {
+			return nil, err
+		}
+		primary = s
+	} else {
+		sc, err := SignerConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		key, err := signer.NewSignerFromConfig(sc, domains)
+		if err != nil {
+			return nil, err
+		}
+		primary = signer.NewRotatingSigner(key)
+	}
+	if wallet := cfg.Signer.ContractWallet; wallet.Address != "" {
+		w, err := signer.NewContractWallet(primary, common.HexToAddress(wallet.Address), wallet.Format,
+			domains, cfg.Signer.MaxDeadlineHorizon)
+		if err != nil {
+			return nil, fmt.Errorf("contract wallet: %w", err)
+		}
+		primary = w
+	}
+	if len(cfg.Signer.ChainKeys) == 0 {
+		return primary, nil
+	}
 
-	// 6. Initialize depth data provider (using mock provider)
-	depthProvider := depth.DefaultMockProvider()
-	logger.Info("Depth provider initialized (mock)")
+	chains := make(map[uint64]signer.Signer, len(cfg.Signer.ChainKeys))
+	for chainID, key := range cfg.Signer.ChainKeys {
+		s, err := signer.NewSignerFromConfig(&signer.SignerConfig{
+			PrivateKey:         key.PrivateKey,
+			PrivateKeyEnv:      key.PrivateKeyEnv,
+			MaxDeadlineHorizon: cfg.Signer.MaxDeadlineHorizon,
+		}, domains)
+		if err != nil {
+			return nil, fmt.Errorf("chain %d key: %w", chainID, err)
+		}
+		chains[chainID] = s
+	}
+	return signer.NewMultiSigner(primary, chains), nil
+}
 
-	// 7. Initialize depth pusher
-	r.depthPusher = depth.NewPusher(r.wsClient, depthProvider, r.quoteHandler, s, cfg, logger)
+// SignerConfig builds the configuration of the primary signer of cfg
+func SignerConfig(cfg *config.Config) (*signer.SignerConfig, error) {
+	sc := &signer.SignerConfig{
+		PrivateKey:         cfg.Signer.PrivateKey,
+		PrivateKeyEnv:      cfg.Signer.PrivateKeyEnv,
+		KeystoreFile:       cfg.Signer.KeystoreFile,
+		MaxDeadlineHorizon: cfg.Signer.MaxDeadlineHorizon,
+	}
+	if sc.KeystoreFile != "" {
+		password, err := cfg.Signer.GetKeystorePassword()
+		if err != nil {
+			return nil, fmt.Errorf("keystore password: %w", err)
+		}
+		sc.KeystorePassword = password
+	}
+	if vault := cfg.Signer.Vault; vault.Enabled() {
+		sc.Vault = &signer.VaultConfig{
+			Address:      vault.Address,
+			Namespace:    vault.Namespace,
+			Mount:        vault.Mount,
+			Path:         vault.Path,
+			Field:        vault.Field,
+			Token:        vault.GetToken(),
+			RoleID:       vault.AppRole.RoleID,
+			SecretID:     vault.AppRole.GetSecretID(),
+			AppRoleMount: vault.AppRole.Mount,
+			Timeout:      vault.Timeout,
+		}
+	}
+	return sc, nil
+}
 
-	return r, nil
+// StandbyWSConfig builds the WebSocket client configuration of the standby region
+func StandbyWSConfig(cfg *config.Config) *ws.Config {
+	wsCfg := WSConfig(cfg)
+	wsCfg.ServerURL = cfg.WebSocket.Standby.ServerURL
+	if cfg.WebSocket.Standby.APIToken != "" {
+		wsCfg.APIToken = cfg.WebSocket.Standby.APIToken
+	}
+	return wsCfg
 }
 
 // Run runs the service
 func (r *Runner) Run(ctx context.Context) error {
 	r.logger.Info("Starting Market Maker service",
 		"app", r.cfg.App.Name,
+		"version", buildinfo.Version,
 		"wsServer", r.cfg.WebSocket.ServerURL)
 
 	// Create cancellable context
@@ -101,6 +624,59 @@ func (r *Runner) Run(ctx context.Context) error {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
+	// Self-check: a signer that cannot sign now would fail every quote request
+	if health := r.checkSigners(); !health.Healthy {
+		return fmt.Errorf("signer health check failed: %v", health.Failing)
+	}
+	r.logger.Info("Signer health check passed", "latency", r.SignerHealth().Latency)
+
+	// Self-check: a backup key that cannot sign on every chain would fail over into rejections
+	if err := r.checkBackupSigner(); err != nil {
+		return fmt.Errorf("backup signer check failed: %w", err)
+	}
+
+	// Self-check: an RPC endpoint of another chain would verify the wrong contracts
+	if err := VerifyChainIDs(ctx, r.cfg, r.logger); err != nil {
+		return fmt.Errorf("RPC chain ID check failed: %w", err)
+	}
+
+	// Self-check: quotes signed with a domain the contract does not use can never settle
+	if err := VerifyDomains(ctx, r.cfg, r.domains, r.logger); err != nil {
+		return fmt.Errorf("domain self-check failed: %w", err)
+	}
+
+	// Self-check: quotes the contract wallet does not accept can never settle
+	if wallet := contractWallet(r.signer); wallet != nil && !isHardware(wallet.Owner()) {
+		if err := VerifyContractWallet(ctx, r.cfg, r.domains, wallet, r.logger); err != nil {
+			return fmt.Errorf("contract wallet self-check failed: %w", err)
+		}
+	}
+
+	// Serve metrics before connecting, so the handshake is visible
+	if r.cfg.Metrics.Enabled {
+		if err := r.serveMetrics(ctx); err != nil {
+			return fmt.Errorf("failed to start metrics endpoint: %w", err)
+		}
+	}
+	if r.cfg.Query.Enabled {
+		if err := r.serveQuery(ctx); err != nil {
+			return fmt.Errorf("failed to start query API: %w", err)
+		}
+	}
+	if r.admin != nil {
+		if err := r.serveHTTP(ctx, r.cfg.Admin.Listen, r.admin, "Admin API"); err != nil {
+			return fmt.Errorf("failed to start admin API: %w", err)
+		}
+	}
+	if r.webhooks != nil {
+		r.webhooks.Start(ctx, r.bus)
+	}
+	if r.flowExport != nil {
+		r.flowExport.Start(ctx, r.bus)
+	}
+	r.session = newSession(time.Now())
+	r.session.follow(ctx, r.bus)
+
 	// Start WebSocket connection
 	r.logger.Info("Connecting to WebSocket server...")
 	if err := r.wsClient.Connect(ctx); err != nil {
@@ -112,6 +688,51 @@ func (r *Runner) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to start depth pusher: %w", err)
 	}
 
+	// Expire signed quotes at their deadlines
+	go quote.NewExpiryScheduler(r.quoteHandler.Store(), r.logger).Run(ctx)
+
+	// Drain signing keys listed in the drain file
+	if r.signerPool != nil && r.cfg.Signer.Pool.DrainFile != "" {
+		go r.drainLoop(ctx)
+	}
+
+	// Check the signing keys periodically
+	if r.cfg.Signer.HealthCheckInterval > 0 {
+		go r.signerHealthLoop(ctx)
+	}
+
+	// Rotate the signing key on SIGHUP
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+	go r.rotateOnSignal(ctx, hupCh)
+
+	// Track quotes revoked in the revocation file
+	if r.revocations != nil {
+		go r.revocationLoop(ctx)
+	}
+
+	// Start status report
+	if r.cfg.Status.Enabled {
+		go r.statusLoop(ctx)
+	}
+
+	// Start spread schedule
+	if r.scheduler != nil {
+		go r.scheduler.Run(ctx)
+	}
+
+	// Start depth/quote consistency checker
+	if r.consistency != nil {
+		go r.consistency.Run(ctx)
+	}
+
+	// Start profiling watchdog
+	if r.cfg.Profiling.Enabled {
+		go r.newWatchdog().Run(ctx)
+		r.logger.Info("Profiling watchdog enabled", "dir", r.cfg.Profiling.Dir)
+	}
+
 	r.logger.Info("Market Maker service started successfully")
 	r.logger.Info("Waiting for messages...")
 
@@ -127,10 +748,72 @@ func (r *Runner) Run(ctx context.Context) error {
 	return r.Shutdown()
 }
 
+// newScheduler creates the spread schedule of the strategy and depth provider that support it
+// Prices for regime detection are sampled from the strategy when it is a quote.PriceFeed
+func newScheduler(cfg *config.Config, strategy quote.QuoteStrategy, provider depth.DepthProvider, logger *slog.Logger) *schedule.Scheduler {
+	var targets []schedule.Target
+	for _, component := range []any{strategy, provider} {
+		if target, ok := component.(schedule.Target); ok {
+			targets = append(targets, target)
+		}
+	}
+	if len(targets) == 0 {
+		logger.Warn("Schedule enabled, but neither the strategy nor the depth provider supports it")
+	}
+
+	var prices schedule.PriceSource
+	if feed, ok := strategy.(quote.PriceFeed); ok {
+		prices = func(ctx context.Context) map[string]decimal.Decimal {
+			out := make(map[string]decimal.Decimal, len(cfg.Pairs))
+			for _, pair := range cfg.Pairs {
+				if !common.IsHexAddress(pair.BaseToken) || !common.IsHexAddress(pair.QuoteToken) {
+					continue
+				}
+				price, _, err := feed.Price(ctx, pair.ChainID,
+					common.HexToAddress(pair.BaseToken), common.HexToAddress(pair.QuoteToken))
+				if err != nil {
+					continue
+				}
+				out[fmt.Sprintf("%d:%s", pair.ChainID, pair.PairID)] = price
+			}
+			return out
+		}
+	} else if len(cfg.Schedule.Regimes.Levels) > 0 {
+		logger.Warn("Volatility regimes configured, but the strategy does not provide prices")
+	}
+
+	logger.Info("Spread schedule enabled",
+		"windows", len(cfg.Schedule.Windows),
+		"events", len(cfg.Schedule.Events),
+		"regimeLevels", len(cfg.Schedule.Regimes.Levels))
+	return schedule.NewScheduler(cfg.Schedule, prices, logger, targets...)
+}
+
+// newWatchdog creates the profiling watchdog over the quote latency and the send queue
+func (r *Runner) newWatchdog() *profiling.Watchdog {
+	sources := profiling.Sources{
+		LatencyP99: func() time.Duration {
+			return r.quoteHandler.Stats().Latency[quote.StageTotal].P99
+		},
+	}
+	if _, ok := ws.QueueDepth(r.wsClient); ok {
+		sources.SendQueueDepth = func() int {
+			depth, _ := ws.QueueDepth(r.wsClient)
+			return depth
+		}
+	}
+	return profiling.NewWatchdog(r.cfg.Profiling, sources, r.logger)
+}
+
 // Shutdown gracefully shuts down the service
 func (r *Runner) Shutdown() error {
 	r.logger.Info("Shutting down Market Maker service...")
 
+	// Summarize the session before the components stop
+	if r.session != nil {
+		r.reportSession()
+	}
+
 	// Stop depth pusher
 	if r.depthPusher != nil {
 		if err := r.depthPusher.Stop(); err != nil {
@@ -145,6 +828,20 @@ func (r *Runner) Shutdown() error {
 		}
 	}
 
+	// Release the signing device
+	if hw, ok := r.primarySigner().(*signer.HardwareSigner); ok {
+		if err := hw.Close(); err != nil {
+			r.logger.Error("Failed to close hardware wallet", "error", err)
+		}
+	}
+
+	// Close state store (after the pusher and the connection, the last writers)
+	if r.state != nil {
+		if err := r.state.Close(); err != nil {
+			r.logger.Error("Failed to close state store", "error", err)
+		}
+	}
+
 	r.logger.Info("Market Maker service stopped")
 	return nil
 }
@@ -0,0 +1,162 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/buildinfo"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
+)
+
+// SessionSummary summarizes a run of the MM; it is logged on graceful shutdown
+type SessionSummary struct {
+	Version         string            `json:"version"`
+	Started         time.Time         `json:"started"`
+	Uptime          string            `json:"uptime"`
+	Requests        uint64            `json:"requests"`
+	Quoted          uint64            `json:"quoted"`
+	Rejected        uint64            `json:"rejected"`
+	RejectsByReason map[string]uint64 `json:"rejectsByReason"`
+	DepthPushes     uint64            `json:"depthPushes"`
+	Reconnects      int               `json:"reconnects"` // Connections that became ready again after their first handshake
+	Fills           int               `json:"fills"`
+
+	// Estimated PnL of the fills: amountIn valued at the quote-time mid, minus amountOut.
+	// "chainId:tokenOut" -> tokenOut native units. Fills of quotes priced without a mid are Unpriced.
+	PnL      map[string]string `json:"pnl"`
+	Unpriced int               `json:"unpriced"`
+}
+
+// session follows the bus events the session summary needs
+type session struct {
+	started time.Time
+
+	mu         sync.Mutex
+	ready      map[string]bool // Server URLs that were ready before
+	reconnects int
+	fills      int
+	unpriced   int
+	pnl        map[string]*big.Int
+}
+
+// newSession starts a session at started
+func newSession(started time.Time) *session {
+	return &session{started: started, ready: make(map[string]bool), pnl: make(map[string]*big.Int)}
+}
+
+// follow records fills and connection changes published on bus until ctx is done
+func (s *session) follow(ctx context.Context, bus *events.Bus) {
+	sub := bus.Subscribe("session", 0, events.FillObserved, events.ConnectionStateChanged)
+	go sub.Run(ctx, s.observe)
+}
+
+// observe records one event
+func (s *session) observe(e events.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch e.Kind {
+	case events.ConnectionStateChanged:
+		if state, ok := e.Data.(ws.ConnectionState); !ok || state != ws.StateReady {
+			return
+		}
+		url, _, _ := strings.Cut(e.Detail, ": ") // Detail is "<server URL>: <from> -> <to>"
+		if s.ready[url] {
+			s.reconnects++
+		}
+		s.ready[url] = true
+	case events.FillObserved:
+		rec, ok := e.Data.(quote.QuoteRecord)
+		if !ok {
+			return
+		}
+		s.fills++
+		if rec.Info.MidPrice.Sign() <= 0 || rec.AmountIn == nil || rec.AmountOut == nil {
+			s.unpriced++
+			return
+		}
+		// MidPrice is tokenOut wei per tokenIn wei: the MM receives amountIn*mid and pays amountOut
+		edge := rec.Info.MidPrice.MulInt(rec.AmountIn).Sub(decimal.NewFromBigInt(rec.AmountOut)).Int()
+		key := fmt.Sprintf("%d:%s", rec.ChainID, rec.TokenOut.Hex())
+		if s.pnl[key] == nil {
+			s.pnl[key] = new(big.Int)
+		}
+		s.pnl[key].Add(s.pnl[key], edge)
+	}
+}
+
+// summary returns the summary of the session at now, with the given handler and push counts
+func (s *session) summary(now time.Time, stats quote.Stats, pushes uint64) SessionSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pnl := make(map[string]string, len(s.pnl))
+	for key, v := range s.pnl {
+		pnl[key] = v.String()
+	}
+	return SessionSummary{
+		Version:         buildinfo.Version,
+		Started:         s.started,
+		Uptime:          now.Sub(s.started).Round(time.Second).String(),
+		Requests:        stats.Requests,
+		Quoted:          stats.Responses,
+		Rejected:        stats.Rejects,
+		RejectsByReason: stats.RejectsByReason,
+		DepthPushes:     pushes,
+		Reconnects:      s.reconnects,
+		Fills:           s.fills,
+		PnL:             pnl,
+		Unpriced:        s.unpriced,
+	}
+}
+
+// reportSession logs the session summary and writes it to status.sessionReport
+func (r *Runner) reportSession() {
+	summary := r.session.summary(time.Now(), r.quoteHandler.Stats(), r.depthPusher.Pushes())
+
+	reasons := make([]string, 0, len(summary.RejectsByReason))
+	for reason, n := range summary.RejectsByReason {
+		reasons = append(reasons, fmt.Sprintf("%s=%d", reason, n))
+	}
+	sort.Strings(reasons)
+	r.logger.Info("Session summary",
+		"uptime", summary.Uptime,
+		"requests", summary.Requests,
+		"quoted", summary.Quoted,
+		"rejected", summary.Rejected,
+		"rejectsByReason", reasons,
+		"depthPushes", summary.DepthPushes,
+		"reconnects", summary.Reconnects,
+		"fills", summary.Fills,
+		"pnl", summary.PnL,
+		"unpriced", summary.Unpriced)
+
+	if path := r.cfg.Status.SessionReport; path != "" {
+		if err := writeSessionReport(path, summary); err != nil {
+			r.logger.Error("Failed to write session report", "path", path, "error", err)
+		}
+	}
+}
+
+// writeSessionReport writes summary to path as indented JSON, replacing the file
+func writeSessionReport(path string, summary SessionSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
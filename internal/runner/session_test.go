@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
+)
+
+func TestSession_Summary(t *testing.T) {
+	started := time.Unix(1700000000, 0)
+	s := newSession(started)
+
+	ready := func(url string) events.Event {
+		return events.Event{Kind: events.ConnectionStateChanged, Detail: url + ": Connected -> Ready", Data: ws.StateReady}
+	}
+	s.observe(ready("ws://primary"))
+	s.observe(ready("ws://standby"))
+	s.observe(events.Event{Kind: events.ConnectionStateChanged, Detail: "ws://primary: Ready -> Disconnected", Data: ws.StateDisconnected})
+	s.observe(ready("ws://primary"))
+
+	tokenOut := common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	fill := func(mid string, amountOut int64) events.Event {
+		return events.Event{Kind: events.FillObserved, Data: quote.QuoteRecord{
+			ChainID:   56,
+			TokenOut:  tokenOut,
+			AmountIn:  big.NewInt(1000),
+			AmountOut: big.NewInt(amountOut),
+			State:     quote.QuoteStateFilled,
+			Info:      quote.QuoteInfo{MidPrice: decimal.MustParse(mid)},
+		}}
+	}
+	s.observe(fill("600", 599000))
+	s.observe(fill("600", 599500))
+	s.observe(events.Event{Kind: events.FillObserved, Data: quote.QuoteRecord{ChainID: 56, AmountIn: big.NewInt(1), AmountOut: big.NewInt(1)}})
+
+	stats := quote.Stats{Requests: 5, Responses: 3, Rejects: 2, RejectsByReason: map[string]uint64{"REJECT_REASON_RATE_LIMITED": 2}}
+	got := s.summary(started.Add(90*time.Second), stats, 7)
+	if got.Uptime != "1m30s" || got.Requests != 5 || got.Quoted != 3 || got.Rejected != 2 || got.DepthPushes != 7 {
+		t.Errorf("summary = %+v, want 1m30s uptime, 5 requests, 3 quoted, 2 rejected, 7 pushes", got)
+	}
+	if got.Reconnects != 1 {
+		t.Errorf("Reconnects = %d, want 1", got.Reconnects)
+	}
+	if got.Fills != 3 || got.Unpriced != 1 {
+		t.Errorf("Fills = %d, Unpriced = %d, want 3 and 1", got.Fills, got.Unpriced)
+	}
+	if pnl := got.PnL["56:"+tokenOut.Hex()]; pnl != "1500" {
+		t.Errorf("PnL = %q, want 1500", pnl)
+	}
+}
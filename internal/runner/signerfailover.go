@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+// newSignerFailover creates the backup key of cfg.Signer.Backup and the failover around primary
+func (r *Runner) newSignerFailover(primary signer.Signer, domains *signer.DomainManager) (*signer.Failover, error) {
+	backupCfg := r.cfg.Signer.Backup
+	backup, err := signer.NewSignerFromConfig(&signer.SignerConfig{
+		PrivateKey:         backupCfg.PrivateKey,
+		PrivateKeyEnv:      backupCfg.PrivateKeyEnv,
+		MaxDeadlineHorizon: r.cfg.Signer.MaxDeadlineHorizon,
+	}, domains)
+	if err != nil {
+		return nil, err
+	}
+	if backup.GetAddress() == primary.GetAddress() {
+		return nil, fmt.Errorf("backup key is the primary key %s", primary.GetAddress().Hex())
+	}
+	return signer.NewFailover(primary, backup, signer.FailoverConfig{
+		FailAfter:     backupCfg.FailAfter,
+		FailBack:      backupCfg.FailBack,
+		FailBackAfter: backupCfg.FailBackAfter,
+		OnChange:      r.onSignerFailover,
+	}), nil
+}
+
+// onSignerFailover alerts on a switch between the primary and backup keys
+// A failover is logged as an error and published as a signer_failover event; a fail-back as a warning.
+func (r *Runner) onSignerFailover(state signer.FailoverState) {
+	from := signer.BackupKeyName
+	if state.Active == signer.BackupKeyName {
+		from = signer.PrimaryKeyName
+		r.logger.Error("Signer failed over to the backup key",
+			"primary", state.Primary.Hex(),
+			"backup", state.Backup.Hex(),
+			"failures", state.Failures,
+			"lastError", state.LastError)
+	} else {
+		r.logger.Warn("Signer failed back to the primary key", "primary", state.Primary.Hex())
+	}
+	r.bus.Publish(events.Event{
+		Kind:   events.SignerFailover,
+		Time:   state.Since,
+		Detail: from + " -> " + state.Active,
+		Data:   state,
+	})
+}
+
+// checkBackupSigner signs and verifies a canary quote with the backup key on every chain
+// The backup is only used in an outage, so a key that cannot sign on some chain must be found at startup.
+func (r *Runner) checkBackupSigner() error {
+	if r.backup == nil {
+		return nil
+	}
+	for _, chainID := range r.cfg.ChainIDs() {
+		if err := signer.CheckSigner(r.backup.Backup(), r.domains, chainID); err != nil {
+			return fmt.Errorf("chain %d: %w", chainID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+// SignerHealth is the result of the latest signer health check
+type SignerHealth struct {
+	Healthy   bool
+	CheckedAt time.Time
+	Latency   time.Duration     // Slowest canary signature
+	Failing   map[string]string // Key name -> error, for keys that failed
+}
+
+// checkSigners signs and verifies a canary quote with every signing key and records the result
+// The canary is signed on the first configured chain, and chain keys on their own chain. With a
+// backup key, the primary and backup keys are checked apart, and the primary's result drives
+// the failover.
+func (r *Runner) checkSigners() SignerHealth {
+	keys := []signer.PoolKey{{Name: signer.PrimaryKeyName, Signer: r.signer}}
+	if r.signerPool != nil {
+		keys = r.signerPool.Signers()
+	}
+	if r.backup != nil {
+		keys[0].Signer = r.backup.Primary()
+		keys = append(keys, signer.PoolKey{Name: signer.BackupKeyName, Signer: r.backup.Backup()})
+	}
+	chainID := r.cfg.EIP712Domains[0].ChainID
+	chains := make(map[string]uint64) // Key name -> chain of its canary, if not chainID
+	if multi, ok := keys[0].Signer.(*signer.MultiSigner); ok {
+		keys[0].Signer = multi.Default()
+		for _, key := range multi.Chains() {
+			name := fmt.Sprintf("chain-%d", key.ChainID)
+			keys = append(keys, signer.PoolKey{Name: name, Signer: key.Signer})
+			chains[name] = key.ChainID
+		}
+	}
+
+	health := SignerHealth{Healthy: true, CheckedAt: time.Now()}
+	var primaryChecked bool
+	var primaryErr error // Of the primary key and the chain keys, which fail over together
+	for _, key := range keys {
+		if isHardware(key.Signer) {
+			continue // A canary would wait for a confirmation on the device
+		}
+		canaryChain, chainKey := chains[key.Name]
+		if !chainKey {
+			canaryChain = chainID
+		}
+		start := time.Now()
+		err := signer.CheckSigner(key.Signer, r.domains, canaryChain)
+		health.Latency = max(health.Latency, time.Since(start))
+		if key.Name == signer.PrimaryKeyName || chainKey {
+			primaryChecked, primaryErr = true, errors.Join(primaryErr, err)
+		}
+		if err != nil {
+			if health.Failing == nil {
+				health.Failing = make(map[string]string)
+			}
+			health.Failing[key.Name] = err.Error()
+			health.Healthy = false
+		}
+	}
+	if r.backup != nil && primaryChecked {
+		r.backup.ReportCheck(primaryErr)
+	}
+
+	r.healthMu.Lock()
+	r.signerHealth = health
+	r.healthMu.Unlock()
+	return health
+}
+
+// SignerHealth returns the latest signer health check
+func (r *Runner) SignerHealth() SignerHealth {
+	r.healthMu.Lock()
+	defer r.healthMu.Unlock()
+	return r.signerHealth
+}
+
+// signerHealthLoop checks the signing keys every signer.healthCheckInterval until ctx is done
+// Failures are logged as errors on every check, recovery once
+func (r *Runner) signerHealthLoop(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Signer.HealthCheckInterval)
+	defer ticker.Stop()
+
+	healthy := true
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		health := r.checkSigners()
+		if !health.Healthy {
+			names := make([]string, 0, len(health.Failing))
+			for name := range health.Failing {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				r.logger.Error("Signer health check failed", "key", name, "error", health.Failing[name])
+			}
+		} else if !healthy {
+			r.logger.Info("Signer health check recovered", "latency", health.Latency)
+		}
+		healthy = health.Healthy
+	}
+}
+
+// primarySigner returns the primary key, unwrapped from a pool, a failover, chain keys and a
+// contract wallet
+func (r *Runner) primarySigner() signer.Signer {
+	s := r.signer
+	if r.signerPool != nil {
+		s = r.signerPool.Signers()[0].Signer
+	}
+	if r.backup != nil {
+		s = r.backup.Primary()
+	}
+	if multi, ok := s.(*signer.MultiSigner); ok {
+		s = multi.Default()
+	}
+	if wallet, ok := s.(*signer.ContractWallet); ok {
+		s = wallet.Owner()
+	}
+	return s
+}
+
+// contractWallet returns the contract wallet that s signs for, nil if none
+// s is the runner's signer before or after it is wrapped in a failover and a pool.
+func contractWallet(s signer.Signer) *signer.ContractWallet {
+	if pool, ok := s.(*signer.Pool); ok {
+		s = pool.Signers()[0].Signer
+	}
+	if failover, ok := s.(*signer.Failover); ok {
+		s = failover.Primary()
+	}
+	if multi, ok := s.(*signer.MultiSigner); ok {
+		s = multi.Default()
+	}
+	wallet, _ := s.(*signer.ContractWallet)
+	return wallet
+}
+
+// isHardware reports whether s signs on a hardware wallet, directly or as a contract wallet owner
+func isHardware(s signer.Signer) bool {
+	if wallet, ok := s.(*signer.ContractWallet); ok {
+		s = wallet.Owner()
+	}
+	_, ok := s.(*signer.HardwareSigner)
+	return ok
+}
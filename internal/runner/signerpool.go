@@ -0,0 +1,150 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+// drainCheckInterval is how often the drain file is re-read
+const drainCheckInterval = 5 * time.Second
+
+// newSignerPool creates the signing key pool of cfg.Signer.Pool around the primary signer
+func newSignerPool(cfg *config.Config, primary signer.Signer, domains *signer.DomainManager) (*signer.Pool, error) {
+	poolCfg := cfg.Signer.Pool
+	keys := make([]signer.PoolKey, 0, len(poolCfg.Keys))
+	for _, key := range poolCfg.Keys {
+		s, err := signer.NewSignerFromConfig(&signer.SignerConfig{
+			PrivateKey:         key.PrivateKey,
+			PrivateKeyEnv:      key.PrivateKeyEnv,
+			MaxDeadlineHorizon: cfg.Signer.MaxDeadlineHorizon,
+		}, domains)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", key.Name, err)
+		}
+		keys = append(keys, signer.PoolKey{Name: key.Name, Signer: s})
+	}
+
+	pool, err := signer.NewPool(primary, keys, signer.PoolAssignment{
+		Policy: poolCfg.Policy,
+		Pairs:  poolCfg.Pairs,
+		Chains: poolCfg.Chains,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range poolCfg.Keys {
+		if key.Drained {
+			_ = pool.SetDrained(key.Name, true)
+		}
+	}
+	return pool, nil
+}
+
+// drainLoop applies the drain file every drainCheckInterval until ctx is done
+// Keys are drained while listed in the file or configured as drained; a missing file drains none.
+// A rejected file is reported once, until it changes or is accepted again.
+func (r *Runner) drainLoop(ctx context.Context) {
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+
+	var rejected string // Error of the last rejected file
+	for {
+		if err := r.applyDrainFile(); err != nil {
+			if err.Error() != rejected {
+				r.logger.Error("Drain file rejected, keeping the current key rotation",
+					"path", r.cfg.Signer.Pool.DrainFile, "error", err)
+			}
+			rejected = err.Error()
+		} else {
+			rejected = ""
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// applyDrainFile drains the keys listed in the drain file and restores the others
+// The file is applied entirely or not at all: a file naming an unknown key or draining every
+// key is rejected, and a failed change rolls back the changes made before it.
+func (r *Runner) applyDrainFile() error {
+	listed, err := readDrainFile(r.cfg.Signer.Pool.DrainFile)
+	if err != nil {
+		return err
+	}
+	configured := make(map[string]bool, len(r.cfg.Signer.Pool.Keys))
+	for _, key := range r.cfg.Signer.Pool.Keys {
+		configured[key.Name] = key.Drained
+	}
+
+	keys := r.signerPool.Keys()
+	known := make(map[string]bool, len(keys))
+	active := 0
+	for _, key := range keys {
+		known[key.Name] = true
+		if !listed[key.Name] && !configured[key.Name] {
+			active++
+		}
+	}
+	for name := range listed {
+		if !known[name] {
+			return fmt.Errorf("unknown signing key %q", name)
+		}
+	}
+	if active == 0 {
+		return fmt.Errorf("the file would drain all %d signing keys", len(keys))
+	}
+
+	var changed []signer.PoolKeyStatus // Keys changed so far, in their previous state
+	for _, key := range keys {
+		drained := listed[key.Name] || configured[key.Name]
+		if drained == key.Drained {
+			continue
+		}
+		if err := r.signerPool.SetDrained(key.Name, drained); err != nil {
+			for _, prev := range changed {
+				_ = r.signerPool.SetDrained(prev.Name, prev.Drained)
+			}
+			return err
+		}
+		changed = append(changed, key)
+	}
+	for _, key := range changed {
+		r.logger.Warn("Signing key rotation changed", "name", key.Name, "address", key.Address.Hex(), "drained", !key.Drained)
+	}
+	return nil
+}
+
+// readDrainFile returns the key names listed in path, skipping blank lines and # comments
+func readDrainFile(path string) (map[string]bool, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	names := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		names[line] = true
+	}
+	return names, scanner.Err()
+}
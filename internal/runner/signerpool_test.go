@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+)
+
+func TestRunner_ApplyDrainFile(t *testing.T) {
+	pool, err := signer.NewPool(testutil.NewFakeSigner(common.HexToAddress("0x01")), []signer.PoolKey{
+		{Name: "hot-1", Signer: testutil.NewFakeSigner(common.HexToAddress("0x02"))},
+	}, signer.PoolAssignment{})
+	if err != nil {
+		t.Fatalf("NewPool failed: %v", err)
+	}
+	drainFile := filepath.Join(t.TempDir(), "drain")
+	cfg := testutil.Config()
+	cfg.Signer.Pool = config.SignerPoolConfig{Keys: []config.SignerKeyConfig{{Name: "hot-1"}}, DrainFile: drainFile}
+	r := &Runner{cfg: cfg, logger: slog.New(slog.NewTextHandler(io.Discard, nil)), signerPool: pool}
+
+	drained := func() map[string]bool {
+		out := make(map[string]bool)
+		for _, key := range pool.Keys() {
+			out[key.Name] = key.Drained
+		}
+		return out
+	}
+	write := func(content string) {
+		if err := os.WriteFile(drainFile, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("hot-1\n")
+	if err := r.applyDrainFile(); err != nil {
+		t.Fatalf("applyDrainFile() = %v", err)
+	}
+	if got := drained(); !got["hot-1"] || got[signer.PrimaryKeyName] {
+		t.Fatalf("drained = %v, want only hot-1", got)
+	}
+
+	// Rejected files keep the current rotation
+	for _, content := range []string{"primary\nhot-1\n", "primary\nhot-2\n"} {
+		write(content)
+		if err := r.applyDrainFile(); err == nil {
+			t.Errorf("applyDrainFile(%q) = nil, want error", content)
+		}
+		if got := drained(); !got["hot-1"] || got[signer.PrimaryKeyName] {
+			t.Errorf("after %q: drained = %v, want the previous rotation", content, got)
+		}
+	}
+
+	write("primary\n")
+	if err := r.applyDrainFile(); err != nil {
+		t.Fatalf("applyDrainFile() = %v", err)
+	}
+	if got := drained(); got["hot-1"] || !got[signer.PrimaryKeyName] {
+		t.Errorf("drained = %v, want only primary", got)
+	}
+}
@@ -0,0 +1,96 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+// RotateSigner loads the primary key from its source again and rotates to it
+// Only keys read from privateKey, privateKeyEnv, vault or keystoreFile can be rotated; Vault and
+// the keystore file are read again, so the new key must be stored there first. The new key signs
+// a canary quote on every chain before it replaces the old one. Quotes assigned the old key
+// finish with it, and the old key is logged once its last quote has expired.
+func (r *Runner) RotateSigner() (signer.Rotation, error) {
+	r.rotateMu.Lock()
+	defer r.rotateMu.Unlock()
+
+	rotating, ok := r.primarySigner().(*signer.RotatingSigner)
+	if !ok {
+		return signer.Rotation{}, fmt.Errorf("the primary key cannot be rotated: it is not a private key, Vault or keystore key")
+	}
+	sc, err := SignerConfig(r.cfg)
+	if err != nil {
+		return signer.Rotation{}, err
+	}
+	next, err := signer.NewSignerFromConfig(sc, r.domains)
+	if err != nil {
+		return signer.Rotation{}, fmt.Errorf("failed to load the new key: %w", err)
+	}
+	if next.GetAddress() == rotating.GetAddress() {
+		return signer.Rotation{}, fmt.Errorf("the key source still holds the current key %s", next.GetAddress().Hex())
+	}
+	if r.backup != nil && next.GetAddress() == r.backup.Backup().GetAddress() {
+		return signer.Rotation{}, fmt.Errorf("new key is the backup key %s", next.GetAddress().Hex())
+	}
+	for _, chainID := range r.cfg.ChainIDs() {
+		if err := signer.CheckSigner(next, r.domains, chainID); err != nil {
+			return signer.Rotation{}, fmt.Errorf("new key failed on chain %d: %w", chainID, err)
+		}
+	}
+
+	rotation, err := rotating.Rotate(next)
+	if err != nil {
+		return signer.Rotation{}, err
+	}
+	r.logger.Warn("Signing key rotated",
+		"from", rotation.From.Hex(),
+		"to", rotation.To.Hex(),
+		"oldKeyNeededUntil", rotation.Until)
+	r.bus.Publish(events.Event{
+		Kind:   events.SignerRotated,
+		Time:   rotation.Time,
+		Detail: rotation.From.Hex() + " -> " + rotation.To.Hex(),
+		Data:   rotation,
+	})
+	go r.watchRetired(rotating, rotation)
+	return rotation, nil
+}
+
+// rotateOnSignal rotates the primary key on every signal of sigCh (SIGHUP)
+func (r *Runner) rotateOnSignal(ctx context.Context, sigCh <-chan os.Signal) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-sigCh:
+			r.logger.Info("Received signal, rotating the signing key", "signal", sig)
+			if _, err := r.RotateSigner(); err != nil {
+				r.logger.Error("Signing key rotation failed", "error", err)
+			}
+		}
+	}
+}
+
+// watchRetired logs when the last quote signed by the key retired in rotation has expired
+// Until then the RFQ managers must keep accepting the key. Quotes assigned the key before the
+// rotation may still be signed after it, so the deadline is checked again when it passes.
+func (r *Runner) watchRetired(rotating *signer.RotatingSigner, rotation signer.Rotation) {
+	for {
+		var until time.Time
+		for _, key := range rotating.Retired() {
+			if key.Address == rotation.From {
+				until = key.Until
+			}
+		}
+		if until.IsZero() {
+			r.logger.Info("Retired signing key has no quotes left in flight", "address", rotation.From.Hex())
+			return
+		}
+		time.Sleep(time.Until(until) + time.Second)
+	}
+}
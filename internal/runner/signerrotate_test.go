@@ -0,0 +1,44 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+)
+
+func TestRunner_RotateSigner(t *testing.T) {
+	t.Setenv("MM_TEST_ROTATE_KEY", fmt.Sprintf("%064x", 1))
+	cfg := testutil.Config()
+	cfg.Signer.PrivateKey, cfg.Signer.PrivateKeyEnv = "", "MM_TEST_ROTATE_KEY"
+	domains, err := DomainManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := newPrimarySigner(cfg, domains)
+	if err != nil {
+		t.Fatalf("newPrimarySigner failed: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r := &Runner{cfg: cfg, logger: logger, bus: events.NewBus(logger), signer: s, domains: domains}
+	old := s.GetAddress()
+
+	if _, err := r.RotateSigner(); err == nil {
+		t.Error("rotation to the unchanged key succeeded")
+	}
+	t.Setenv("MM_TEST_ROTATE_KEY", fmt.Sprintf("%064x", 2))
+	rotation, err := r.RotateSigner()
+	if err != nil {
+		t.Fatalf("RotateSigner failed: %v", err)
+	}
+	if rotation.From != old || rotation.To != s.GetAddress() || rotation.To == old {
+		t.Errorf("rotation = %+v, signer now %s", rotation, s.GetAddress().Hex())
+	}
+	if err := signer.CheckSigner(s, domains, cfg.EIP712Domains[0].ChainID); err != nil {
+		t.Errorf("rotated signer: %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package runner
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/buildinfo"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/nonce"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
+)
+
+// Status is the application-level health of the MM
+type Status struct {
+	Version       string
+	State         string
+	ActivePairs   []string                 // Pairs with a signed quote within the report interval
+	DepthAge      map[string]time.Duration // "chainId:pairId" -> time since last successful push
+	Requests      uint64
+	Rejects       uint64
+	RejectRate    float64
+	OpenQuotes    int
+	StaleDepthMax time.Duration // Oldest depth age across pairs
+
+	QuoteLatency   map[string]quote.LatencyStats // Quote handling latency by stage
+	BudgetExceeded uint64                        // Quote requests rejected for exceeding their latency budget
+	RateLimit      quote.RateLimitStats          // Quote requests rejected over their origin's rate
+
+	Shadow      *quote.ShadowStats     // Candidate strategy comparison, nil unless shadow.enabled
+	SigningKeys []signer.PoolKeyStatus // Signing key pool, nil without one
+	Revoked     int                    // Quotes listed in the revocation file
+	Nonces      []nonce.Stats          // Nonce sequences, nil under quote.nonce.mode "request"
+
+	SignerHealth   SignerHealth          // Latest canary check of the signing keys
+	SignerFailover *signer.FailoverState // Primary/backup key state, nil without signer.backup
+
+	Connection string // Active gateway connection, "primary" or "standby"; empty without a standby
+	Failovers  uint64 // Failovers to the standby connection
+
+	ReconnectCoolOff time.Duration // Remaining reconnect storm cool-off, 0 = reconnects allowed
+
+	BytesIn       uint64 // Received during the report interval
+	BytesOut      uint64 // Sent during the report interval
+	DepthDeferred uint64 // Depth pushes deferred by the outbound byte budget
+}
+
+// buildStatus collects the current status from all components
+func (r *Runner) buildStatus(window time.Duration) Status {
+	stats := r.quoteHandler.Stats()
+	activePairs := r.quoteHandler.ActivePairs(window)
+	sort.Strings(activePairs)
+
+	now := time.Now()
+	depthAge := make(map[string]time.Duration)
+	var staleMax time.Duration
+	for key, t := range r.depthPusher.LastPushTimes() {
+		age := now.Sub(t)
+		depthAge[key] = age
+		if age > staleMax {
+			staleMax = age
+		}
+	}
+
+	var shadow *quote.ShadowStats
+	if r.shadow != nil {
+		stats := r.shadow.Stats()
+		shadow = &stats
+	}
+
+	var keys []signer.PoolKeyStatus
+	if r.signerPool != nil {
+		keys = r.signerPool.Keys()
+	}
+	var signerFailover *signer.FailoverState
+	if r.backup != nil {
+		state := r.backup.State()
+		signerFailover = &state
+	}
+	var connection string
+	var failovers uint64
+	if r.failover != nil {
+		connection, failovers = r.failover.Active(), r.failover.Failovers()
+	}
+	var revoked int
+	if r.revocations != nil {
+		revoked = r.revocations.Len()
+	}
+	var nonces []nonce.Stats
+	if r.nonces != nil {
+		nonces = r.nonces.Stats()
+	}
+
+	return Status{
+		Version:       buildinfo.Version,
+		State:         r.wsClient.GetState().String(),
+		ActivePairs:   activePairs,
+		DepthAge:      depthAge,
+		Requests:      stats.Requests,
+		Rejects:       stats.Rejects,
+		RejectRate:    stats.RejectRate(),
+		OpenQuotes:    len(r.quoteHandler.Store().Open()),
+		StaleDepthMax: staleMax,
+
+		QuoteLatency:   stats.Latency,
+		BudgetExceeded: stats.BudgetExceeded,
+		RateLimit:      r.quoteHandler.RateLimitStats(),
+
+		Shadow:      shadow,
+		SigningKeys: keys,
+		Revoked:     revoked,
+		Nonces:      nonces,
+
+		SignerHealth:   r.SignerHealth(),
+		SignerFailover: signerFailover,
+
+		Connection: connection,
+		Failovers:  failovers,
+
+		ReconnectCoolOff: r.stormGuard.CoolOff(),
+
+		DepthDeferred: r.budget.Deferred(),
+	}
+}
+
+// clientHealth is the status summary attached to heartbeat pings (websocket.heartbeatHealth)
+func (r *Runner) clientHealth() ws.ClientHealth {
+	now := time.Now()
+	var staleMax time.Duration
+	for _, t := range r.depthPusher.LastPushTimes() {
+		staleMax = max(staleMax, now.Sub(t))
+	}
+	stats := r.quoteHandler.Stats()
+	return ws.ClientHealth{
+		DepthPushAge: staleMax,
+		QuoteP95:     stats.Latency[quote.StageTotal].P95,
+		OpenQuotes:   len(r.quoteHandler.Store().Open()),
+		RejectRate:   stats.RejectRate(),
+	}
+}
+
+// statusLoop periodically logs the MM status
+// The full report goes to the log; the server gets its summary on heartbeat pings
+// (Heartbeat.health) when websocket.heartbeatHealth is set.
+func (r *Runner) statusLoop(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Status.Interval)
+	defer ticker.Stop()
+
+	var lastIn, lastOut uint64 // Traffic totals at the previous report
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			status := r.buildStatus(r.cfg.Status.Interval)
+			if r.traffic != nil {
+				in, out := r.traffic.Totals()
+				status.BytesIn, status.BytesOut = in-lastIn, out-lastOut
+				lastIn, lastOut = in, out
+			}
+			r.logger.Info("MM status",
+				"version", status.Version,
+				"state", status.State,
+				"activePairs", status.ActivePairs,
+				"requests", status.Requests,
+				"rejects", status.Rejects,
+				"rejectRate", status.RejectRate,
+				"openQuotes", status.OpenQuotes,
+				"staleDepthMax", status.StaleDepthMax,
+				"quoteLatencyMean", status.QuoteLatency[quote.StageTotal].Mean(),
+				"quoteLatencyMax", status.QuoteLatency[quote.StageTotal].Max,
+				"strategyLatencyMax", status.QuoteLatency[quote.StageStrategy].Max,
+				"budgetExceeded", status.BudgetExceeded,
+				"rateLimited", status.RateLimit.Limited,
+				"bannedOrigins", status.RateLimit.Banned,
+				"revoked", status.Revoked,
+				"signerHealthy", status.SignerHealth.Healthy,
+				"signerCheckLatency", status.SignerHealth.Latency,
+				"connection", status.Connection,
+				"failovers", status.Failovers,
+				"reconnectCoolOff", status.ReconnectCoolOff,
+				"bytesIn", status.BytesIn,
+				"bytesOut", status.BytesOut,
+				"depthDeferred", status.DepthDeferred)
+			if sf := status.SignerFailover; sf != nil {
+				r.logger.Info("Signer failover",
+					"active", sf.Active,
+					"since", sf.Since,
+					"failovers", sf.Failovers,
+					"failures", sf.Failures)
+			}
+			for _, seq := range status.Nonces {
+				r.logger.Info("Nonce sequence",
+					"key", seq.Key,
+					"mark", seq.Mark,
+					"outstanding", seq.Outstanding,
+					"filled", seq.Filled,
+					"expired", seq.Expired,
+					"failed", seq.Failed)
+			}
+			for _, key := range status.SigningKeys {
+				r.logger.Info("Signing key",
+					"name", key.Name,
+					"address", key.Address.Hex(),
+					"drained", key.Drained,
+					"signed", key.Signed)
+			}
+			if shadow := status.Shadow; shadow != nil {
+				r.logger.Info("Shadow strategy comparison",
+					"compared", shadow.Compared,
+					"candidateErrors", shadow.CandidateErrors,
+					"liveErrors", shadow.LiveErrors,
+					"skipped", shadow.Skipped,
+					"candidateBetter", shadow.CandidateBetter,
+					"meanDivergenceBps", shadow.MeanDivergenceBps,
+					"maxDivergenceBps", shadow.MaxDivergenceBps)
+			}
+		}
+	}
+}
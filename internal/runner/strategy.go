@@ -0,0 +1,89 @@
+package runner
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+)
+
+// StrategyFactory builds the quote strategy and depth provider of a registered strategy
+type StrategyFactory func(cfg *config.Config, logger *slog.Logger) (quote.QuoteStrategy, depth.DepthProvider, error)
+
+var (
+	strategiesMu sync.RWMutex
+	strategies   = map[string]StrategyFactory{
+		"mock": newMockStrategy,
+	}
+)
+
+// RegisterStrategy registers a strategy selectable with strategy.name
+// Usually called from the init function of the strategy package; panics on a duplicate name
+func RegisterStrategy(name string, factory StrategyFactory) {
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+
+	if _, ok := strategies[name]; ok {
+		panic(fmt.Sprintf("strategy %q already registered", name))
+	}
+	strategies[name] = factory
+}
+
+// Strategies returns the registered strategy names, sorted
+func Strategies() []string {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newStrategy builds the strategy selected by cfg.Strategy.Name
+func newStrategy(cfg *config.Config, logger *slog.Logger) (quote.QuoteStrategy, depth.DepthProvider, error) {
+	name := cfg.Strategy.Name
+	if name == "" {
+		name = "mock"
+	}
+
+	strategiesMu.RLock()
+	factory, ok := strategies[name]
+	strategiesMu.RUnlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown strategy %q (registered: %v)", name, Strategies())
+	}
+	return factory(cfg, logger)
+}
+
+// newShadowCandidate builds the candidate strategy of cfg.Shadow like the live one
+// The candidate's depth provider is not used
+func newShadowCandidate(cfg *config.Config, logger *slog.Logger) (quote.QuoteStrategy, error) {
+	candidateCfg := *cfg
+	candidateCfg.Strategy = cfg.Shadow.Strategy
+	strategy, _, err := newStrategy(&candidateCfg, logger.With("shadow", true))
+	return strategy, err
+}
+
+// newMockStrategy builds the mock strategy and depth provider
+func newMockStrategy(cfg *config.Config, logger *slog.Logger) (quote.QuoteStrategy, depth.DepthProvider, error) {
+	seed := cfg.Mock.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	strategy := quote.DefaultMockStrategyWithSeed(seed)
+	strategy.JitterBps = cfg.Mock.JitterBps
+	logger.Info("Quote strategy initialized (mock)", "seed", seed, "jitterBps", strategy.JitterBps)
+
+	provider := depth.DefaultMockProviderWithSeed(seed)
+	logger.Info("Depth provider initialized (mock)", "seed", seed)
+
+	return strategy, provider, nil
+}
@@ -0,0 +1,73 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// sample is a price observed at a time
+type sample struct {
+	at    time.Time
+	price decimal.Decimal
+}
+
+// regimeDetector tracks recent prices per pair to measure volatility
+// Only used by the Scheduler goroutine
+type regimeDetector struct {
+	window  time.Duration
+	samples map[string][]sample
+}
+
+// newRegimeDetector creates a detector over the given lookback window
+func newRegimeDetector(window time.Duration) *regimeDetector {
+	return &regimeDetector{
+		window:  window,
+		samples: make(map[string][]sample),
+	}
+}
+
+// observe records a price of pair, dropping samples older than the window
+func (d *regimeDetector) observe(pair string, at time.Time, price decimal.Decimal) {
+	if price.Sign() <= 0 {
+		return
+	}
+	d.samples[pair] = append(d.prune(d.samples[pair], at), sample{at: at, price: price})
+}
+
+// volatilityBps returns the largest price range of any pair within the window,
+// as (max-min)/min in basis points
+func (d *regimeDetector) volatilityBps(now time.Time) float64 {
+	var highest float64
+	for pair, samples := range d.samples {
+		samples = d.prune(samples, now)
+		d.samples[pair] = samples
+		if len(samples) < 2 {
+			continue
+		}
+
+		low, high := samples[0].price, samples[0].price
+		for _, s := range samples[1:] {
+			if s.price.Cmp(low) < 0 {
+				low = s.price
+			}
+			if s.price.Cmp(high) > 0 {
+				high = s.price
+			}
+		}
+		if bps := high.Sub(low).Quo(low).Float64() * 10000; bps > highest {
+			highest = bps
+		}
+	}
+	return highest
+}
+
+// prune drops samples older than the window
+func (d *regimeDetector) prune(samples []sample, now time.Time) []sample {
+	cutoff := now.Add(-d.window)
+	i := 0
+	for i < len(samples) && samples[i].at.Before(cutoff) {
+		i++
+	}
+	return samples[i:]
+}
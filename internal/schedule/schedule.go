@@ -0,0 +1,190 @@
+package schedule
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// Target receives the multipliers of the current schedule
+// Implemented by strategies and depth providers whose spread and sizes can be scaled
+type Target interface {
+	// SetMultipliers scales the spread and the quoted or advertised sizes; 1 = unchanged
+	SetMultipliers(spread, size float64)
+}
+
+// PriceSource returns the current price of each tracked pair, keyed by pair
+// Used to detect the volatility regime; pairs without a price are omitted
+type PriceSource func(ctx context.Context) map[string]decimal.Decimal
+
+// Adjustment is the combined effect of the active schedule entries
+type Adjustment struct {
+	SpreadMultiplier float64
+	SizeMultiplier   float64
+	Active           []string // Names of the active windows, events and regime
+}
+
+// Scheduler evaluates the configured schedule and feeds the result to its targets
+type Scheduler struct {
+	cfg     config.ScheduleConfig
+	prices  PriceSource
+	targets []Target
+	regime  *regimeDetector
+	logger  *slog.Logger
+	now     func() time.Time
+
+	current Adjustment
+}
+
+// NewScheduler creates a scheduler; prices may be nil when no regime levels are configured
+func NewScheduler(cfg config.ScheduleConfig, prices PriceSource, logger *slog.Logger, targets ...Target) *Scheduler {
+	return &Scheduler{
+		cfg:     cfg,
+		prices:  prices,
+		targets: targets,
+		regime:  newRegimeDetector(cfg.Regimes.Window),
+		logger:  logger.With("component", "Scheduler"),
+		now:     time.Now,
+		current: Adjustment{SpreadMultiplier: 1, SizeMultiplier: 1},
+	}
+}
+
+// Run evaluates the schedule every Interval until ctx is done
+// Targets are updated whenever the adjustment changes
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	s.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+// tick samples prices, evaluates the schedule and applies a changed adjustment
+func (s *Scheduler) tick(ctx context.Context) {
+	now := s.now()
+	if s.prices != nil && len(s.cfg.Regimes.Levels) > 0 {
+		for pair, price := range s.prices(ctx) {
+			s.regime.observe(pair, now, price)
+		}
+	}
+
+	adj := s.Evaluate(now)
+	if adj.SpreadMultiplier == s.current.SpreadMultiplier && adj.SizeMultiplier == s.current.SizeMultiplier {
+		return
+	}
+	s.current = adj
+	for _, target := range s.targets {
+		target.SetMultipliers(adj.SpreadMultiplier, adj.SizeMultiplier)
+	}
+	s.logger.Info("Schedule adjustment changed",
+		"spreadMultiplier", adj.SpreadMultiplier,
+		"sizeMultiplier", adj.SizeMultiplier,
+		"active", adj.Active)
+}
+
+// Evaluate returns the adjustment of the windows, events and regime active at now
+func (s *Scheduler) Evaluate(now time.Time) Adjustment {
+	adj := Adjustment{SpreadMultiplier: 1, SizeMultiplier: 1}
+	apply := func(name string, spread, size float64) {
+		adj.SpreadMultiplier *= multiplier(spread)
+		adj.SizeMultiplier *= multiplier(size)
+		adj.Active = append(adj.Active, name)
+	}
+
+	for _, w := range s.cfg.Windows {
+		if windowActive(w, now) {
+			apply(w.Name, w.SpreadMultiplier, w.SizeMultiplier)
+		}
+	}
+	for _, e := range s.cfg.Events {
+		if !now.Before(e.At.Add(-e.Before)) && !now.After(e.At.Add(e.After)) {
+			apply(e.Name, e.SpreadMultiplier, e.SizeMultiplier)
+		}
+	}
+	if level, ok := s.regimeLevel(now); ok {
+		apply("regime", level.SpreadMultiplier, level.SizeMultiplier)
+	}
+	return adj
+}
+
+// regimeLevel returns the highest regime level reached by the current volatility
+func (s *Scheduler) regimeLevel(now time.Time) (config.RegimeLevel, bool) {
+	if len(s.cfg.Regimes.Levels) == 0 {
+		return config.RegimeLevel{}, false
+	}
+	volatility := s.regime.volatilityBps(now)
+
+	var best config.RegimeLevel
+	found := false
+	for _, level := range s.cfg.Regimes.Levels {
+		if volatility >= float64(level.MinVolatilityBps) && (!found || level.MinVolatilityBps > best.MinVolatilityBps) {
+			best, found = level, true
+		}
+	}
+	return best, found
+}
+
+// multiplier treats an unset (0) multiplier as 1
+func multiplier(m float64) float64 {
+	if m == 0 {
+		return 1
+	}
+	return m
+}
+
+// windowActive reports whether the daily window w contains now (UTC)
+// A window whose end is not after its start wraps past midnight; Days are the days it starts on
+func windowActive(w config.ScheduleWindow, now time.Time) bool {
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse("15:04", w.End)
+	if err != nil {
+		return false
+	}
+
+	now = now.UTC()
+	sinceMidnight := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute +
+		time.Duration(now.Second())*time.Second
+	startAt := time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute
+	endAt := time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute
+
+	day := now.Weekday()
+	switch {
+	case startAt < endAt:
+		if sinceMidnight < startAt || sinceMidnight >= endAt {
+			return false
+		}
+	case sinceMidnight >= startAt:
+		// Evening part of a wrapping window
+	case sinceMidnight < endAt:
+		// Morning part: the window started the day before
+		day = (day + 6) % 7
+	default:
+		return false
+	}
+	return startsOn(w.Days, day)
+}
+
+// startsOn reports whether a window with the given days starts on day
+func startsOn(days []string, day time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(days, func(name string) bool {
+		d, ok := config.Weekday(name)
+		return ok && d == day
+	})
+}
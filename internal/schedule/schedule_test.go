@@ -0,0 +1,137 @@
+package schedule
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+)
+
+// recordingTarget records the multipliers it receives
+type recordingTarget struct {
+	spread, size []float64
+}
+
+func (t *recordingTarget) SetMultipliers(spread, size float64) {
+	t.spread = append(t.spread, spread)
+	t.size = append(t.size, size)
+}
+
+func TestWindowActive(t *testing.T) {
+	// 2026-10-16 is a Friday
+	at := func(day int, clock string) time.Time {
+		c, _ := time.Parse("15:04", clock)
+		return time.Date(2026, 10, day, c.Hour(), c.Minute(), 0, 0, time.UTC)
+	}
+	daily := config.ScheduleWindow{Start: "09:00", End: "17:00"}
+	fridayNight := config.ScheduleWindow{Start: "22:00", End: "02:00", Days: []string{"fri"}}
+
+	tests := []struct {
+		name   string
+		window config.ScheduleWindow
+		now    time.Time
+		want   bool
+	}{
+		{"daily inside", daily, at(16, "12:00"), true},
+		{"daily at start", daily, at(16, "09:00"), true},
+		{"daily at end", daily, at(16, "17:00"), false},
+		{"wrap evening of start day", fridayNight, at(16, "23:00"), true},
+		{"wrap morning after start day", fridayNight, at(17, "01:00"), true},
+		{"wrap evening of other day", fridayNight, at(17, "23:00"), false},
+		{"wrap morning of start day", fridayNight, at(16, "01:00"), false},
+		{"wrap between end and start", fridayNight, at(16, "12:00"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := windowActive(tt.window, tt.now); got != tt.want {
+				t.Errorf("windowActive = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduler_EvaluateCombinesActiveEntries(t *testing.T) {
+	event := time.Date(2026, 11, 4, 18, 0, 0, 0, time.UTC)
+	cfg := config.ScheduleConfig{
+		Windows: []config.ScheduleWindow{
+			{Name: "evening", Start: "17:00", End: "23:00", SpreadMultiplier: 2},
+			{Name: "morning", Start: "06:00", End: "09:00", SpreadMultiplier: 5},
+		},
+		Events: []config.ScheduleEvent{
+			{Name: "FOMC", At: event, Before: 30 * time.Minute, After: time.Hour, SpreadMultiplier: 1.5, SizeMultiplier: 0.5},
+		},
+	}
+	s := NewScheduler(cfg, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		now          time.Time
+		spread, size float64
+		active       []string
+	}{
+		{event.Add(-31 * time.Minute), 2, 1, []string{"evening"}},
+		{event, 3, 0.5, []string{"evening", "FOMC"}},
+		{event.Add(time.Hour), 3, 0.5, []string{"evening", "FOMC"}},
+		{event.Add(6 * time.Hour), 1, 1, nil},
+	}
+	for _, tt := range tests {
+		adj := s.Evaluate(tt.now)
+		if adj.SpreadMultiplier != tt.spread || adj.SizeMultiplier != tt.size || !slices.Equal(adj.Active, tt.active) {
+			t.Errorf("Evaluate(%v) = %+v, want spread %v size %v active %v", tt.now, adj, tt.spread, tt.size, tt.active)
+		}
+	}
+}
+
+func TestScheduler_VolatilityRegimeFeedsTargets(t *testing.T) {
+	cfg := config.ScheduleConfig{
+		Regimes: config.RegimeConfig{
+			Window: time.Minute,
+			Levels: []config.RegimeLevel{
+				{MinVolatilityBps: 50, SpreadMultiplier: 1.5},
+				{MinVolatilityBps: 200, SpreadMultiplier: 3, SizeMultiplier: 0.5},
+			},
+		},
+	}
+	price := decimal.NewFromInt(100)
+	prices := func(ctx context.Context) map[string]decimal.Decimal {
+		return map[string]decimal.Decimal{"56:WBNB-USDT": price}
+	}
+	target := &recordingTarget{}
+	s := NewScheduler(cfg, prices, slog.New(slog.NewTextHandler(io.Discard, nil)), target)
+	now := time.Unix(1735084800, 0)
+	s.now = func() time.Time { return now }
+
+	steps := []struct {
+		price  int64
+		spread float64 // Multiplier applied after the step, 0 = unchanged
+	}{
+		{100, 0},   // Calm: no change from the initial adjustment
+		{101, 1.5}, // 100 bps range
+		{103, 3},   // 300 bps range
+		{103, 0},
+	}
+	for i, step := range steps {
+		price = decimal.NewFromInt(step.price)
+		before := len(target.spread)
+		s.tick(context.Background())
+		now = now.Add(10 * time.Second)
+
+		switch {
+		case step.spread == 0 && len(target.spread) != before:
+			t.Errorf("step %d: targets updated to %v, want unchanged", i, target.spread[len(target.spread)-1])
+		case step.spread != 0 && (len(target.spread) != before+1 || target.spread[before] != step.spread):
+			t.Errorf("step %d: spread updates = %v, want %v", i, target.spread, step.spread)
+		}
+	}
+
+	// Once the spike leaves the window, the calm regime returns
+	now = now.Add(2 * time.Minute)
+	s.tick(context.Background())
+	if got := target.spread[len(target.spread)-1]; got != 1 {
+		t.Errorf("spread multiplier after the window = %v, want 1", got)
+	}
+}
@@ -0,0 +1,149 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signature formats of a contract wallet
+const (
+	WalletFormatSafe   = "safe"   // The owner signs the Safe's SafeMessage of the digest (Safe 1.3.0+ fallback handler)
+	WalletFormatDirect = "direct" // The owner signs the digest itself, for wallets that recover it against an owner
+)
+
+// Safe message hashing (CompatibilityFallbackHandler.getMessageHashForSafe)
+var (
+	safeDomainTypeHash  = crypto.Keccak256Hash([]byte("EIP712Domain(uint256 chainId,address verifyingContract)"))
+	safeMessageTypeHash = crypto.Keccak256Hash([]byte("SafeMessage(bytes message)"))
+)
+
+// ERC-1271 isValidSignature(bytes32,bytes), also its magic return value
+var isValidSignatureSelector = crypto.Keccak256([]byte("isValidSignature(bytes32,bytes)"))[:4]
+
+// isValidSignatureInputs is the ABI layout of the isValidSignature arguments
+var isValidSignatureInputs = func() abi.Arguments {
+	bytes32Ty, _ := abi.NewType("bytes32", "", nil)
+	bytesTy, _ := abi.NewType("bytes", "", nil)
+	return abi.Arguments{{Type: bytes32Ty}, {Type: bytesTy}}
+}()
+
+// ContractWallet signs quotes for a smart contract wallet (ERC-1271) with an owner key
+// The wallet is the MM identity: GetAddress returns it, for the mm_id of messages and the order's
+// signer field, and the RFQ manager verifies quotes with the wallet's isValidSignature. A single
+// owner signature is produced, so a Safe must accept one owner (threshold 1).
+type ContractWallet struct {
+	owner         Signer
+	wallet        common.Address
+	format        string
+	domainManager *DomainManager
+
+	maxHorizon time.Duration // Deadlines further ahead are refused (0 = unlimited)
+	now        func() time.Time
+}
+
+// NewContractWallet creates a signer of wallet signing with owner in format
+// The safe format needs an owner that can sign typed data.
+func NewContractWallet(owner Signer, wallet common.Address, format string, domainManager *DomainManager, maxHorizon time.Duration) (*ContractWallet, error) {
+	switch format {
+	case WalletFormatSafe:
+		if _, ok := owner.(TypedDataSigner); !ok {
+			return nil, fmt.Errorf("owner key cannot sign typed data, as the %s format needs", format)
+		}
+	case WalletFormatDirect:
+	default:
+		return nil, fmt.Errorf("unknown contract wallet format %q", format)
+	}
+	return &ContractWallet{
+		owner:         owner,
+		wallet:        wallet,
+		format:        format,
+		domainManager: domainManager,
+		maxHorizon:    maxHorizon,
+		now:           time.Now,
+	}, nil
+}
+
+// GetAddress returns the address of the wallet contract, the MM identity
+func (w *ContractWallet) GetAddress() common.Address {
+	return w.wallet
+}
+
+// Owner returns the owner key that signs for the wallet
+func (w *ContractWallet) Owner() Signer {
+	return w.owner
+}
+
+// SignMMQuote signs an MMQuote for the wallet; the signature is valid for its isValidSignature
+func (w *ContractWallet) SignMMQuote(chainID uint64, quote *MMQuote) ([]byte, error) {
+	if w.format == WalletFormatDirect {
+		return w.owner.SignMMQuote(chainID, quote)
+	}
+	if err := checkHorizon(quote, w.maxHorizon, w.now()); err != nil {
+		return nil, err
+	}
+	digest, err := w.domainManager.Digest(chainID, quote)
+	if err != nil {
+		return nil, err
+	}
+	return w.owner.(TypedDataSigner).SignTypedData(SafeDomainSeparator(chainID, w.wallet), safeMessageStructHash(digest))
+}
+
+// SignMMQuoteClamped signs quote with its deadline clamped to the maximum horizon
+func (w *ContractWallet) SignMMQuoteClamped(chainID uint64, quote *MMQuote) ([]byte, error) {
+	if clamper, ok := w.owner.(DeadlineClamper); ok && w.format == WalletFormatDirect {
+		return clamper.SignMMQuoteClamped(chainID, quote)
+	}
+	clampHorizon(quote, w.maxHorizon, w.now())
+	return w.SignMMQuote(chainID, quote)
+}
+
+// OwnerHash returns the hash the owner signs for digest on chainID
+func (w *ContractWallet) OwnerHash(chainID uint64, digest common.Hash) common.Hash {
+	if w.format == WalletFormatDirect {
+		return digest
+	}
+	return SafeMessageHash(chainID, w.wallet, digest)
+}
+
+// SafeDomainSeparator returns the EIP-712 domain separator of a Safe (1.3.0+)
+func SafeDomainSeparator(chainID uint64, safe common.Address) []byte {
+	buf := make([]byte, 96)
+	copy(buf[0:32], safeDomainTypeHash[:])
+	new(big.Int).SetUint64(chainID).FillBytes(buf[32:64])
+	copy(buf[76:96], safe[:])
+	return crypto.Keccak256(buf)
+}
+
+// SafeMessageHash returns the hash a Safe owner signs so that the Safe accepts digest
+// It is getMessageHashForSafe(safe, abi.encode(digest)) of the CompatibilityFallbackHandler.
+func SafeMessageHash(chainID uint64, safe common.Address, digest common.Hash) common.Hash {
+	return typedDataHash(SafeDomainSeparator(chainID, safe), safeMessageStructHash(digest))
+}
+
+// safeMessageStructHash returns the struct hash of SafeMessage(bytes message) for message = digest
+func safeMessageStructHash(digest common.Hash) common.Hash {
+	return crypto.Keccak256Hash(safeMessageTypeHash[:], crypto.Keccak256(digest[:]))
+}
+
+// VerifyContractSignature checks sig of digest with the wallet's ERC-1271 isValidSignature
+func VerifyContractSignature(ctx context.Context, client RPCClient, wallet common.Address, digest common.Hash, sig []byte) error {
+	args, err := isValidSignatureInputs.Pack(digest, sig)
+	if err != nil {
+		return err
+	}
+	out, err := client.CallContract(ctx, wallet, append(bytes.Clone(isValidSignatureSelector), args...))
+	if err != nil {
+		return fmt.Errorf("isValidSignature call failed: %w", err)
+	}
+	if len(out) < 4 || !bytes.Equal(out[:4], isValidSignatureSelector) {
+		return fmt.Errorf("wallet %s rejected the signature (isValidSignature returned %x)", wallet.Hex(), out)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// fakeSafe answers isValidSignature like a Safe of one owner with the fallback handler
+type fakeSafe struct {
+	chainID uint64
+	safe    common.Address
+	owner   common.Address
+}
+
+func (f *fakeSafe) ChainID(ctx context.Context) (uint64, error) {
+	return f.chainID, nil
+}
+
+func (f *fakeSafe) CallContract(ctx context.Context, to common.Address, data []byte) ([]byte, error) {
+	if to != f.safe || !bytes.HasPrefix(data, isValidSignatureSelector) {
+		return nil, fmt.Errorf("execution reverted")
+	}
+	args, err := isValidSignatureInputs.Unpack(data[4:])
+	if err != nil {
+		return nil, err
+	}
+	digest, sig := common.Hash(args[0].([32]byte)), args[1].([]byte)
+	out := make([]byte, 32)
+	if signer, err := recoverSigner(SafeMessageHash(f.chainID, f.safe, digest), sig); err == nil && signer == f.owner {
+		copy(out, isValidSignatureSelector)
+	}
+	return out, nil
+}
+
+func TestSafeMessageHash(t *testing.T) {
+	// Type hashes of the Safe contracts
+	if safeDomainTypeHash != common.HexToHash("0x47e79534a245952e8b16893a336b85a3d9ea9fa8c573f3d803afb92a79469218") {
+		t.Errorf("domain type hash %s", safeDomainTypeHash.Hex())
+	}
+	if safeMessageTypeHash != common.HexToHash("0x60b3cbf8b4a223d68d641b3b6ddf9a298e7f33710cf3d3a9d1146b5a6150fbca") {
+		t.Errorf("message type hash %s", safeMessageTypeHash.Hex())
+	}
+
+	// getMessageHashForSafe(safe, abi.encode(digest)), spelled out with the ABI encoder
+	uint256Ty, _ := abi.NewType("uint256", "", nil)
+	addressTy, _ := abi.NewType("address", "", nil)
+	bytes32Ty, _ := abi.NewType("bytes32", "", nil)
+	safe := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	digest := crypto.Keccak256Hash([]byte("quote"))
+	domain, _ := abi.Arguments{{Type: bytes32Ty}, {Type: uint256Ty}, {Type: addressTy}}.Pack(safeDomainTypeHash, big.NewInt(8453), safe)
+	message, _ := abi.Arguments{{Type: bytes32Ty}, {Type: bytes32Ty}}.Pack(safeMessageTypeHash, crypto.Keccak256Hash(digest[:]))
+	want := crypto.Keccak256Hash([]byte{0x19, 0x01}, crypto.Keccak256(domain), crypto.Keccak256(message))
+	if got := SafeMessageHash(8453, safe, digest); got != want {
+		t.Errorf("SafeMessageHash = %s, want %s", got.Hex(), want.Hex())
+	}
+}
+
+func TestContractWallet_SignsForTheWallet(t *testing.T) {
+	domains := NewDomainManager()
+	domains.AddPoolDomain(8453, common.HexToAddress("0x1111111111111111111111111111111111111111"))
+	owner, err := NewSignerFromHex(fmt.Sprintf("0x%064x", 9), domains)
+	if err != nil {
+		t.Fatal(err)
+	}
+	safe := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	w, err := NewContractWallet(owner, safe, WalletFormatSafe, domains, 0)
+	if err != nil {
+		t.Fatalf("NewContractWallet failed: %v", err)
+	}
+	if w.GetAddress() != safe {
+		t.Errorf("GetAddress() = %s, want the wallet", w.GetAddress().Hex())
+	}
+	if err := CheckSigner(w, domains, 8453); err != nil {
+		t.Errorf("CheckSigner failed: %v", err)
+	}
+
+	// The Safe accepts the signature; another Safe (or chain) does not
+	if err := CheckContractWallet(context.Background(), w, domains, 8453, &fakeSafe{chainID: 8453, safe: safe, owner: owner.GetAddress()}); err != nil {
+		t.Errorf("CheckContractWallet failed: %v", err)
+	}
+	other := &fakeSafe{chainID: 8453, safe: safe, owner: common.HexToAddress("0x04")}
+	if err := CheckContractWallet(context.Background(), w, domains, 8453, other); err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Errorf("wrong owner: %v", err)
+	}
+
+	// The direct format passes the owner's own signature
+	direct, err := NewContractWallet(owner, safe, WalletFormatDirect, domains, 0)
+	if err != nil {
+		t.Fatalf("NewContractWallet failed: %v", err)
+	}
+	quote := canaryQuote(domains.GetPoolDomain(8453))
+	got, _ := direct.SignMMQuote(8453, quote)
+	want, _ := owner.SignMMQuote(8453, quote)
+	if !bytes.Equal(got, want) {
+		t.Error("direct format changed the owner signature")
+	}
+	if _, err := NewContractWallet(owner, safe, "multisig", domains, 0); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
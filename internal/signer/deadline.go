@@ -0,0 +1,57 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// ErrDeadlineTooFar is returned for quotes whose deadline is beyond the signer's horizon
+// Checked by the signer itself, independent of request validation in the quote handler
+var ErrDeadlineTooFar = errors.New("deadline too far in the future")
+
+// DeadlineClamper is implemented by signers that can shorten a deadline instead of refusing it
+type DeadlineClamper interface {
+	// SignMMQuoteClamped signs quote, first lowering quote.Deadline to the signing horizon if it
+	// is further ahead. Callers must answer with the (possibly modified) quote.Deadline
+	SignMMQuoteClamped(chainID uint64, quote *MMQuote) ([]byte, error)
+}
+
+// SignMMQuoteClamped signs quote with its deadline clamped to the maximum horizon
+func (s *signer) SignMMQuoteClamped(chainID uint64, quote *MMQuote) ([]byte, error) {
+	if limit, ok := s.deadlineLimit(); ok && quote.Deadline != nil && quote.Deadline.Cmp(limit) > 0 {
+		quote.Deadline = limit
+	}
+	return s.SignMMQuote(chainID, quote)
+}
+
+// deadlineLimit returns the latest signable deadline (Unix seconds), false if unlimited
+func (s *signer) deadlineLimit() (*big.Int, bool) {
+	if s.maxHorizon <= 0 {
+		return nil, false
+	}
+	return big.NewInt(s.now().Add(s.maxHorizon).Unix()), true
+}
+
+// checkHorizon returns an ErrDeadlineTooFar error when quote's deadline is more than maxHorizon
+// after now; used by signers that do not sign with a local key
+func checkHorizon(quote *MMQuote, maxHorizon time.Duration, now time.Time) error {
+	if maxHorizon <= 0 || quote.Deadline == nil {
+		return nil
+	}
+	if limit := big.NewInt(now.Add(maxHorizon).Unix()); quote.Deadline.Cmp(limit) > 0 {
+		return fmt.Errorf("%w: deadline %s is after %s (max horizon %s)", ErrDeadlineTooFar, quote.Deadline, limit, maxHorizon)
+	}
+	return nil
+}
+
+// clampHorizon lowers quote's deadline to maxHorizon after now if it is further ahead
+func clampHorizon(quote *MMQuote, maxHorizon time.Duration, now time.Time) {
+	if maxHorizon <= 0 || quote.Deadline == nil {
+		return
+	}
+	if limit := big.NewInt(now.Add(maxHorizon).Unix()); quote.Deadline.Cmp(limit) > 0 {
+		quote.Deadline = limit
+	}
+}
@@ -0,0 +1,72 @@
+package signer
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// newHorizonSigner creates a signer with a 10 minute deadline horizon and a fixed clock
+func newHorizonSigner(t *testing.T, now time.Time) *signer {
+	t.Helper()
+	dm := NewDomainManager()
+	dm.AddPoolDomain(56, common.HexToAddress("0x1111111111111111111111111111111111111111"))
+	s, err := NewSignerFromConfig(&SignerConfig{
+		PrivateKey:         "0x0000000000000000000000000000000000000000000000000000000000000001",
+		MaxDeadlineHorizon: 10 * time.Minute,
+	}, dm)
+	if err != nil {
+		t.Fatalf("NewSignerFromConfig failed: %v", err)
+	}
+	s.(*signer).now = func() time.Time { return now }
+	return s.(*signer)
+}
+
+func horizonQuote(deadline int64) *MMQuote {
+	return &MMQuote{
+		AmountIn:  big.NewInt(1),
+		AmountOut: big.NewInt(1),
+		Deadline:  big.NewInt(deadline),
+		Nonce:     big.NewInt(1),
+	}
+}
+
+func TestSigner_DeadlineHorizon(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := newHorizonSigner(t, now)
+	limit := now.Add(10 * time.Minute).Unix()
+
+	if _, err := s.SignMMQuote(56, horizonQuote(limit)); err != nil {
+		t.Errorf("SignMMQuote at the horizon = %v, want nil", err)
+	}
+	if _, err := s.SignMMQuote(56, horizonQuote(limit+1)); !errors.Is(err, ErrDeadlineTooFar) {
+		t.Errorf("SignMMQuote past the horizon = %v, want ErrDeadlineTooFar", err)
+	}
+}
+
+func TestSigner_SignMMQuoteClamped(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := newHorizonSigner(t, now)
+	limit := now.Add(10 * time.Minute).Unix()
+
+	quote := horizonQuote(limit + 3600)
+	sig, err := s.SignMMQuoteClamped(56, quote)
+	if err != nil {
+		t.Fatalf("SignMMQuoteClamped failed: %v", err)
+	}
+	if quote.Deadline.Int64() != limit {
+		t.Errorf("Deadline = %d, want %d", quote.Deadline.Int64(), limit)
+	}
+	want, _ := s.SignMMQuote(56, horizonQuote(limit))
+	if string(sig) != string(want) {
+		t.Error("clamped signature differs from the signature of the clamped quote")
+	}
+
+	quote = horizonQuote(limit - 60)
+	if _, err := s.SignMMQuoteClamped(56, quote); err != nil || quote.Deadline.Int64() != limit-60 {
+		t.Errorf("SignMMQuoteClamped within horizon: Deadline = %d, err = %v, want %d unchanged", quote.Deadline.Int64(), err, limit-60)
+	}
+}
@@ -1,10 +1,15 @@
 package signer
 
 import (
+	"fmt"
 	"math/big"
+	"slices"
+	"strings"
+	"sync"
 
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -20,53 +25,171 @@ type EIP712Domain struct {
 	Version           string         // Domain version
 	ChainID           *big.Int       // Chain ID
 	VerifyingContract common.Address // Verifying contract address
+	Salt              common.Hash    // Domain salt, a member only with DomainSalt in Fields
+
+	// Fields are the members of the domain type; 0 = StandardDomainFields
+	Fields DomainFields
 }
 
-// DomainSeparator calculates the EIP-712 Domain Separator
-// Reference: https://eips.ethereum.org/EIPS/eip-712
-func (d *EIP712Domain) DomainSeparator() []byte {
-	// EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)
-	typeHash := crypto.Keccak256Hash([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
-	nameHash := crypto.Keccak256Hash([]byte(d.Name))
-	versionHash := crypto.Keccak256Hash([]byte(d.Version))
+// DomainFields is a set of EIP712Domain members, as the fields bitmap of eip712Domain() (EIP-5267)
+type DomainFields uint8
+
+// EIP712Domain members
+const (
+	DomainName DomainFields = 1 << iota
+	DomainVersion
+	DomainChainID
+	DomainVerifyingContract
+	DomainSalt
+)
+
+// StandardDomainFields are the members of the RFQ Manager domain
+const StandardDomainFields = DomainName | DomainVersion | DomainChainID | DomainVerifyingContract
+
+// domainMember is a member of the EIP712Domain type
+type domainMember struct {
+	field DomainFields
+	name  string
+	typ   string
+}
+
+// domainMembers are the EIP712Domain members, in the order EIP-712 encodes them
+var domainMembers = []domainMember{
+	{DomainName, "name", "string"},
+	{DomainVersion, "version", "string"},
+	{DomainChainID, "chainId", "uint256"},
+	{DomainVerifyingContract, "verifyingContract", "address"},
+	{DomainSalt, "salt", "bytes32"},
+}
+
+// ParseDomainFields parses the members of a domain type and its salt
+// No names select StandardDomainFields, with the salt member when salt is set. A salt is a
+// 0x-prefixed bytes32; it must be set exactly when the salt member is listed.
+func ParseDomainFields(names []string, salt string) (DomainFields, common.Hash, error) {
+	var fields DomainFields
+	for _, name := range names {
+		i := slices.IndexFunc(domainMembers, func(m domainMember) bool { return m.name == name })
+		if i < 0 {
+			return 0, common.Hash{}, fmt.Errorf("unknown domain field %q", name)
+		}
+		if fields&domainMembers[i].field != 0 {
+			return 0, common.Hash{}, fmt.Errorf("duplicate domain field %q", name)
+		}
+		fields |= domainMembers[i].field
+	}
+	if len(names) == 0 {
+		fields = StandardDomainFields
+		if salt != "" {
+			fields |= DomainSalt
+		}
+	}
+
+	if salt == "" {
+		if fields&DomainSalt != 0 {
+			return 0, common.Hash{}, fmt.Errorf("domain field salt requires a salt")
+		}
+		return fields, common.Hash{}, nil
+	}
+	if fields&DomainSalt == 0 {
+		return 0, common.Hash{}, fmt.Errorf("salt is set but salt is not a domain field")
+	}
+	b, err := hexutil.Decode(salt)
+	if err != nil || len(b) != common.HashLength {
+		return 0, common.Hash{}, fmt.Errorf("salt %q is not a 0x-prefixed bytes32", salt)
+	}
+	return fields, common.BytesToHash(b), nil
+}
 
-	// ABI encode parameters
-	bytes32Ty, _ := abi.NewType("bytes32", "", nil)
-	uint256Ty, _ := abi.NewType("uint256", "", nil)
-	addressTy, _ := abi.NewType("address", "", nil)
+// String returns the member names, comma-separated
+func (f DomainFields) String() string {
+	var names []string
+	for _, m := range domainMembers {
+		if f&m.field != 0 {
+			names = append(names, m.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// fields returns the members of the domain type
+func (d *EIP712Domain) fields() DomainFields {
+	if d.Fields == 0 {
+		return StandardDomainFields
+	}
+	return d.Fields
+}
 
-	args := abi.Arguments{
-		{Type: bytes32Ty},
-		{Type: bytes32Ty},
-		{Type: bytes32Ty},
-		{Type: uint256Ty},
-		{Type: addressTy},
+// EncodeType returns the EIP-712 encoded domain type, e.g.
+// "EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"
+func (d *EIP712Domain) EncodeType() string {
+	fields := d.fields()
+	var members []string
+	for _, m := range domainMembers {
+		if fields&m.field != 0 {
+			members = append(members, m.typ+" "+m.name)
+		}
 	}
+	return "EIP712Domain(" + strings.Join(members, ",") + ")"
+}
 
-	encoded, _ := args.Pack(typeHash, nameHash, versionHash, d.ChainID, d.VerifyingContract)
+// DomainSeparator calculates the EIP-712 Domain Separator
+// Reference: https://eips.ethereum.org/EIPS/eip-712
+// DomainManager caches the result per chain; call this directly only for ad-hoc domains
+func (d *EIP712Domain) DomainSeparator() []byte {
+	fields := d.fields()
+	encoded := make([]byte, 0, (len(domainMembers)+1)*32)
+	encoded = append(encoded, crypto.Keccak256([]byte(d.EncodeType()))...)
+	for _, m := range domainMembers {
+		if fields&m.field == 0 {
+			continue
+		}
+		switch m.field {
+		case DomainName:
+			encoded = append(encoded, crypto.Keccak256([]byte(d.Name))...)
+		case DomainVersion:
+			encoded = append(encoded, crypto.Keccak256([]byte(d.Version))...)
+		case DomainChainID:
+			chainID := new(big.Int)
+			if d.ChainID != nil {
+				chainID.Set(d.ChainID)
+			}
+			encoded = append(encoded, math.U256Bytes(chainID)...)
+		case DomainVerifyingContract:
+			encoded = append(encoded, common.LeftPadBytes(d.VerifyingContract.Bytes(), 32)...)
+		case DomainSalt:
+			encoded = append(encoded, d.Salt.Bytes()...)
+		}
+	}
 	return crypto.Keccak256(encoded)
 }
 
 // DomainManager manages multi-chain DarkPool RFQ Manager EIP-712 Domains
+// It is safe for concurrent use: domains can be updated or removed while quotes are signed.
+// A quote is hashed under one consistent domain, separator and MMQuote version.
 type DomainManager struct {
+	mu                sync.RWMutex
 	rfqManagerDomains map[uint64]*EIP712Domain // chainId -> DarkPool RFQ Manager domain
+	separators        map[uint64][]byte        // chainId -> cached domain separator
+	quoteTypes        map[uint64]*QuoteType    // chainId -> MMQuote version, DefaultQuoteType if unset
 }
 
 // NewDomainManager creates a Domain manager
 func NewDomainManager() *DomainManager {
 	return &DomainManager{
 		rfqManagerDomains: make(map[uint64]*EIP712Domain),
+		separators:        make(map[uint64][]byte),
+		quoteTypes:        make(map[uint64]*QuoteType),
 	}
 }
 
 // AddPoolDomain adds a DarkPool RFQ Manager Domain configuration
 func (m *DomainManager) AddPoolDomain(chainID uint64, poolAddr common.Address) {
-	m.rfqManagerDomains[chainID] = &EIP712Domain{
+	m.setDomain(chainID, &EIP712Domain{
 		Name:              DefaultDomainName,
 		Version:           DefaultDomainVersion,
 		ChainID:           big.NewInt(int64(chainID)),
 		VerifyingContract: poolAddr,
-	}
+	})
 }
 
 // AddPoolDomainWithConfig adds a DarkPool RFQ Manager Domain with full configuration
@@ -78,36 +201,173 @@ func (m *DomainManager) AddPoolDomainWithConfig(chainID uint64, name, version, p
 	if version == "" {
 		version = DefaultDomainVersion
 	}
-	m.rfqManagerDomains[chainID] = &EIP712Domain{
+	m.setDomain(chainID, &EIP712Domain{
 		Name:              name,
 		Version:           version,
 		ChainID:           big.NewInt(int64(chainID)),
 		VerifyingContract: common.HexToAddress(poolAddr),
+	})
+}
+
+// setDomain stores a domain and caches its separator
+func (m *DomainManager) setDomain(chainID uint64, domain *EIP712Domain) {
+	separator := domain.DomainSeparator()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rfqManagerDomains[chainID] = domain
+	m.separators[chainID] = separator
+}
+
+// UpdatePoolDomain replaces the domain of a configured chain, keeping its MMQuote version
+// The domain is copied; a nil ChainID is chainID. Quotes being signed finish under the old
+// domain, later quotes are signed under the new one.
+func (m *DomainManager) UpdatePoolDomain(chainID uint64, domain *EIP712Domain) error {
+	updated := *domain
+	if updated.ChainID == nil {
+		updated.ChainID = new(big.Int).SetUint64(chainID)
+	} else if !updated.ChainID.IsUint64() || updated.ChainID.Uint64() != chainID {
+		return fmt.Errorf("domain chainId %s is not %d", updated.ChainID, chainID)
+	}
+	updated.ChainID = new(big.Int).Set(updated.ChainID)
+	separator := updated.DomainSeparator()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.rfqManagerDomains[chainID]; !ok {
+		return fmt.Errorf("RFQ Manager not configured for chainId %d", chainID)
+	}
+	m.rfqManagerDomains[chainID] = &updated
+	m.separators[chainID] = separator
+	return nil
+}
+
+// RemovePoolDomain removes the domain and MMQuote version of a chain; its quotes can no longer be signed
+func (m *DomainManager) RemovePoolDomain(chainID uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.rfqManagerDomains[chainID]; !ok {
+		return fmt.Errorf("RFQ Manager not configured for chainId %d", chainID)
 	}
+	delete(m.rfqManagerDomains, chainID)
+	delete(m.separators, chainID)
+	delete(m.quoteTypes, chainID)
+	return nil
+}
+
+// SetDomainFields sets the members of the domain type of a chain and its salt (ParseDomainFields)
+// Call it after the domain is added; no names and no salt keep StandardDomainFields.
+func (m *DomainManager) SetDomainFields(chainID uint64, names []string, salt string) error {
+	domain := m.GetPoolDomain(chainID)
+	if domain == nil {
+		return fmt.Errorf("RFQ Manager not configured for chainId %d", chainID)
+	}
+	fields, saltHash, err := ParseDomainFields(names, salt)
+	if err != nil {
+		return err
+	}
+	updated := *domain
+	updated.Fields, updated.Salt = fields, saltHash
+	return m.UpdatePoolDomain(chainID, &updated)
 }
 
 // GetPoolDomain gets the DarkPool RFQ Manager Domain for a specified chain
+// Updates replace the domain rather than change it; callers must not modify it
 func (m *DomainManager) GetPoolDomain(chainID uint64) *EIP712Domain {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	return m.rfqManagerDomains[chainID]
 }
 
 // GetPoolDomainSeparator gets the DarkPool RFQ Manager Domain Separator for a specified chain
+// The separator is cached when the domain is added; callers must not modify it
 func (m *DomainManager) GetPoolDomainSeparator(chainID uint64) ([]byte, bool) {
-	domain := m.rfqManagerDomains[chainID]
-	if domain == nil {
-		return nil, false
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	separator, ok := m.separators[chainID]
+	return separator, ok
+}
+
+// SetQuoteType sets the MMQuote version signed on a chain, an EIP-712 encoded type
+// An empty type selects the current layout (MMQuoteType)
+func (m *DomainManager) SetQuoteType(chainID uint64, encoded string) error {
+	var t *QuoteType
+	if encoded != "" {
+		var err error
+		if t, err = ParseQuoteType(encoded); err != nil {
+			return err
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t == nil {
+		delete(m.quoteTypes, chainID)
+	} else {
+		m.quoteTypes[chainID] = t
+	}
+	return nil
+}
+
+// GetQuoteType returns the MMQuote version signed on a chain
+func (m *DomainManager) GetQuoteType(chainID uint64) *QuoteType {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.quoteTypeLocked(chainID)
+}
+
+// quoteTypeLocked returns the MMQuote version of a chain; m.mu must be held
+func (m *DomainManager) quoteTypeLocked(chainID uint64) *QuoteType {
+	if t, ok := m.quoteTypes[chainID]; ok {
+		return t
+	}
+	return DefaultQuoteType
+}
+
+// chainDomain returns the domain, separator and MMQuote version of a chain, read together
+func (m *DomainManager) chainDomain(chainID uint64) (*EIP712Domain, []byte, *QuoteType, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	domain, ok := m.rfqManagerDomains[chainID]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("RFQ Manager not configured for chainId %d", chainID)
+	}
+	return domain, m.separators[chainID], m.quoteTypeLocked(chainID), nil
+}
+
+// hashQuote returns the domain separator of a chain and the struct hash of a quote in the
+// chain's MMQuote version, both from the same domain update
+func (m *DomainManager) hashQuote(chainID uint64, quote *MMQuote) ([]byte, common.Hash, error) {
+	_, separator, quoteType, err := m.chainDomain(chainID)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	structHash, err := quoteType.hash(quote)
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("failed to hash MMQuote: %w", err)
+	}
+	return separator, structHash, nil
+}
+
+// Digest calculates the EIP-712 digest of a quote on a chain, in the chain's MMQuote version
+func (m *DomainManager) Digest(chainID uint64, quote *MMQuote) (common.Hash, error) {
+	separator, structHash, err := m.hashQuote(chainID, quote)
+	if err != nil {
+		return common.Hash{}, err
 	}
-	return domain.DomainSeparator(), true
+	return typedDataHash(separator, structHash), nil
 }
 
 // HasRFQManagerDomain checks if a DarkPool RFQ Manager Domain is configured for a specified chain
 func (m *DomainManager) HasRFQManagerDomain(chainID uint64) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	_, ok := m.rfqManagerDomains[chainID]
 	return ok
 }
 
 // ChainIDs returns all configured chain IDs
 func (m *DomainManager) ChainIDs() []uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	ids := make([]uint64, 0, len(m.rfqManagerDomains))
 	for id := range m.rfqManagerDomains {
 		ids = append(ids, id)
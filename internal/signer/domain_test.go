@@ -0,0 +1,143 @@
+package signer
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseDomainFields(t *testing.T) {
+	salt := "0x" + strings.Repeat("01", 32)
+	tests := []struct {
+		name    string
+		names   []string
+		salt    string
+		want    DomainFields
+		wantErr string
+	}{
+		{"standard", nil, "", StandardDomainFields, ""},
+		{"standard with salt", nil, salt, StandardDomainFields | DomainSalt, ""},
+		{"without version", []string{"name", "chainId", "verifyingContract"}, "", DomainName | DomainChainID | DomainVerifyingContract, ""},
+		{"any order", []string{"salt", "verifyingContract"}, salt, DomainVerifyingContract | DomainSalt, ""},
+		{"unknown", []string{"name", "owner"}, "", 0, `unknown domain field "owner"`},
+		{"duplicate", []string{"name", "name"}, "", 0, "duplicate"},
+		{"salt field without salt", []string{"name", "salt"}, "", 0, "requires a salt"},
+		{"salt without salt field", []string{"name"}, salt, 0, "not a domain field"},
+		{"short salt", nil, "0x01", 0, "not a 0x-prefixed bytes32"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := ParseDomainFields(tt.names, tt.salt)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseDomainFields() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseDomainFields() = %s, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDomainManager_SetDomainFields(t *testing.T) {
+	dm := NewDomainManager()
+	dm.AddPoolDomain(56, common.HexToAddress(verifyContract))
+	standard, _ := dm.GetPoolDomainSeparator(56)
+
+	if err := dm.SetDomainFields(56, nil, ""); err != nil {
+		t.Fatalf("SetDomainFields failed: %v", err)
+	}
+	if got, _ := dm.GetPoolDomainSeparator(56); !bytes.Equal(got, standard) {
+		t.Error("no fields and no salt changed the separator")
+	}
+
+	if err := dm.SetDomainFields(56, []string{"name", "chainId", "verifyingContract"}, ""); err != nil {
+		t.Fatalf("SetDomainFields failed: %v", err)
+	}
+	domain := dm.GetPoolDomain(56)
+	if want := "EIP712Domain(string name,uint256 chainId,address verifyingContract)"; domain.EncodeType() != want {
+		t.Errorf("EncodeType() = %q, want %q", domain.EncodeType(), want)
+	}
+	if got, _ := dm.GetPoolDomainSeparator(56); bytes.Equal(got, standard) || !bytes.Equal(got, domain.DomainSeparator()) {
+		t.Error("separator not recomputed for the domain without version")
+	}
+
+	if err := dm.SetDomainFields(1, nil, ""); err == nil {
+		t.Error("SetDomainFields on an unconfigured chain succeeded")
+	}
+}
+
+func TestDomainManager_UpdateAndRemove(t *testing.T) {
+	dm := NewDomainManager()
+	dm.AddPoolDomain(56, common.HexToAddress(verifyContract))
+	if err := dm.SetQuoteType(56, legacyQuoteType); err != nil {
+		t.Fatal(err)
+	}
+	old := dm.GetPoolDomain(56)
+
+	next := &EIP712Domain{Name: "RFQ Manager", Version: "2", VerifyingContract: common.HexToAddress("0x2222222222222222222222222222222222222222")}
+	if err := dm.UpdatePoolDomain(56, next); err != nil {
+		t.Fatalf("UpdatePoolDomain failed: %v", err)
+	}
+	got := dm.GetPoolDomain(56)
+	if got.Version != "2" || got.ChainID.Uint64() != 56 || old.Version != DefaultDomainVersion {
+		t.Errorf("domain = %+v, old domain = %+v", got, old)
+	}
+	if separator, _ := dm.GetPoolDomainSeparator(56); !bytes.Equal(separator, got.DomainSeparator()) {
+		t.Error("separator not recomputed")
+	}
+	if dm.GetQuoteType(56).TypeHash == DefaultQuoteType.TypeHash {
+		t.Error("update dropped the chain's MMQuote version")
+	}
+
+	if err := dm.UpdatePoolDomain(56, &EIP712Domain{ChainID: big.NewInt(1)}); err == nil {
+		t.Error("UpdatePoolDomain with another chainId succeeded")
+	}
+	if err := dm.UpdatePoolDomain(1, next); err == nil {
+		t.Error("UpdatePoolDomain of an unconfigured chain succeeded")
+	}
+
+	if err := dm.RemovePoolDomain(56); err != nil {
+		t.Fatalf("RemovePoolDomain failed: %v", err)
+	}
+	if dm.HasRFQManagerDomain(56) || len(dm.ChainIDs()) != 0 || dm.GetQuoteType(56) != DefaultQuoteType {
+		t.Error("chain 56 still configured after RemovePoolDomain")
+	}
+	if _, err := dm.Digest(56, benchQuote()); err == nil {
+		t.Error("Digest on a removed chain succeeded")
+	}
+	if err := dm.RemovePoolDomain(56); err == nil {
+		t.Error("RemovePoolDomain of a removed chain succeeded")
+	}
+}
+
+func TestDomainManager_ConcurrentUpdates(t *testing.T) {
+	dm := NewDomainManager()
+	dm.AddPoolDomain(56, common.HexToAddress(verifyContract))
+	s, err := NewSignerFromHex(fmt.Sprintf("0x%064x", 1), dm)
+	if err != nil {
+		t.Fatalf("NewSignerFromHex failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			_ = dm.UpdatePoolDomain(56, &EIP712Domain{Name: DefaultDomainName, Version: fmt.Sprint(i), VerifyingContract: common.HexToAddress(verifyContract)})
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		if _, err := s.SignMMQuote(56, benchQuote()); err != nil {
+			t.Fatalf("SignMMQuote during updates failed: %v", err)
+		}
+	}
+	wg.Wait()
+}
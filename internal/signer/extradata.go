@@ -0,0 +1,137 @@
+package signer
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ExtraData modes of a pair
+const (
+	ExtraDataNone        = ""              // Empty extraData
+	ExtraDataV3PoolHedge = "v3-pool-hedge" // abi.encode(v3Pool, zeroForOne, sqrtPriceLimit, callbackData)
+)
+
+// Bounds of a Uniswap V3 sqrtPriceX96 (TickMath.MIN_SQRT_RATIO and MAX_SQRT_RATIO); a swap's
+// price limit must lie strictly between them
+var (
+	MinSqrtRatio, _ = new(big.Int).SetString("4295128739", 10)
+	MaxSqrtRatio, _ = new(big.Int).SetString("1461446703485210103287273052203988822858510927", 10)
+)
+
+// ExtraDataParams are the fields of a "v3-pool-hedge" extraData
+type ExtraDataParams struct {
+	Pool              common.Address // Uniswap V3 pool the quote is hedged on
+	ZeroForOne        bool           // Swap direction in the pool: token0 -> token1
+	SqrtPriceLimitX96 *big.Int       // Price limit of the swap, a uint160
+	CallbackData      []byte         // Passed to the pool's swap callback, see BuildCallbackData
+}
+
+// extraDataArgs is the ABI layout of a "v3-pool-hedge" extraData
+var extraDataArgs = func() abi.Arguments {
+	addressTy, _ := abi.NewType("address", "", nil)
+	boolTy, _ := abi.NewType("bool", "", nil)
+	uint160Ty, _ := abi.NewType("uint160", "", nil)
+	bytesTy, _ := abi.NewType("bytes", "", nil)
+	return abi.Arguments{{Type: addressTy}, {Type: boolTy}, {Type: uint160Ty}, {Type: bytesTy}}
+}()
+
+// callbackDataArgs is the ABI layout of the callback data: payer, tokenIn, tokenOut
+var callbackDataArgs = func() abi.Arguments {
+	addressTy, _ := abi.NewType("address", "", nil)
+	return abi.Arguments{{Type: addressTy}, {Type: addressTy}, {Type: addressTy}}
+}()
+
+// EncodeExtraData encodes p as abi.encode(v3Pool, zeroForOne, sqrtPriceLimit, callbackData)
+// The price limit must lie strictly between MinSqrtRatio and MaxSqrtRatio.
+func EncodeExtraData(p *ExtraDataParams) ([]byte, error) {
+	if p.SqrtPriceLimitX96 == nil || p.SqrtPriceLimitX96.Cmp(MinSqrtRatio) <= 0 || p.SqrtPriceLimitX96.Cmp(MaxSqrtRatio) >= 0 {
+		return nil, fmt.Errorf("sqrtPriceLimitX96 %v is outside the V3 price range", p.SqrtPriceLimitX96)
+	}
+	callbackData := p.CallbackData
+	if callbackData == nil {
+		callbackData = []byte{}
+	}
+	return extraDataArgs.Pack(p.Pool, p.ZeroForOne, p.SqrtPriceLimitX96, callbackData)
+}
+
+// DecodeExtraData decodes a "v3-pool-hedge" extraData, the inverse of EncodeExtraData
+// Empty extraData decodes to nil. Data that is not exactly the canonical encoding of its
+// fields, such as dirty padding or trailing bytes, is an error; the price limit is not checked,
+// so extraData built elsewhere can be inspected even when its limit is out of range.
+func DecodeExtraData(data []byte) (*ExtraDataParams, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	values, err := extraDataArgs.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid extraData: %w", err)
+	}
+	p := &ExtraDataParams{
+		Pool:              values[0].(common.Address),
+		ZeroForOne:        values[1].(bool),
+		SqrtPriceLimitX96: values[2].(*big.Int),
+		CallbackData:      values[3].([]byte),
+	}
+	canonical, err := extraDataArgs.Pack(p.Pool, p.ZeroForOne, p.SqrtPriceLimitX96, p.CallbackData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid extraData: %w", err)
+	}
+	if !bytes.Equal(canonical, data) {
+		return nil, fmt.Errorf("invalid extraData: %d bytes are not the canonical %d byte encoding of its fields", len(data), len(canonical))
+	}
+	return p, nil
+}
+
+// DetermineZeroForOne reports whether swapping tokenIn for tokenOut in a V3 pool is a
+// token0 -> token1 swap; a pool orders its tokens by address
+func DetermineZeroForOne(tokenIn, tokenOut common.Address) bool {
+	return bytes.Compare(tokenIn.Bytes(), tokenOut.Bytes()) < 0
+}
+
+// BuildCallbackData encodes the swap callback data: abi.encode(payer, tokenIn, tokenOut)
+// payer is the MM address paying tokenIn to the pool in the callback.
+func BuildCallbackData(payer, tokenIn, tokenOut common.Address) []byte {
+	data, _ := callbackDataArgs.Pack(payer, tokenIn, tokenOut)
+	return data
+}
+
+// QuoteSqrtPriceLimit returns the V3 price limit at the price of a quote: the hedge swap of
+// amountIn stops before the pool price is worse than amountOut for amountIn
+// The limit is sqrt(token1/token0) in Q64.96, clamped inside the V3 price range.
+func QuoteSqrtPriceLimit(zeroForOne bool, amountIn, amountOut *big.Int) *big.Int {
+	num, den := amountOut, amountIn // token1 per token0 when selling token0
+	if !zeroForOne {
+		num, den = amountIn, amountOut
+	}
+	limit := new(big.Int)
+	if den.Sign() > 0 {
+		limit.Lsh(num, 192)
+		limit.Quo(limit, den)
+		limit.Sqrt(limit)
+	}
+	lowest := new(big.Int).Add(MinSqrtRatio, big.NewInt(1))
+	highest := new(big.Int).Sub(MaxSqrtRatio, big.NewInt(1))
+	if limit.Cmp(lowest) < 0 {
+		return lowest
+	}
+	if limit.Cmp(highest) > 0 {
+		return highest
+	}
+	return limit
+}
+
+// V3PoolHedgeParams returns the "v3-pool-hedge" extraData fields of a quote of amountIn tokenIn
+// for amountOut tokenOut, hedged on pool and paid by payer
+func V3PoolHedgeParams(pool, payer, tokenIn, tokenOut common.Address, amountIn, amountOut *big.Int) *ExtraDataParams {
+	zeroForOne := DetermineZeroForOne(tokenIn, tokenOut)
+	return &ExtraDataParams{
+		Pool:              pool,
+		ZeroForOne:        zeroForOne,
+		SqrtPriceLimitX96: QuoteSqrtPriceLimit(zeroForOne, amountIn, amountOut),
+		CallbackData:      BuildCallbackData(payer, tokenIn, tokenOut),
+	}
+}
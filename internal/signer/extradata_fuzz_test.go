@@ -0,0 +1,42 @@
+package signer
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FuzzExtraDataRoundTrip checks DecodeExtraData returns the fields of every extraData
+// EncodeExtraData produces, and that the decoded fields encode to the same bytes
+func FuzzExtraDataRoundTrip(f *testing.F) {
+	f.Add(testPool.Bytes(), true, MinSqrtRatio.Bytes(), BuildCallbackData(testMM, testUSDT, testWBNB))
+	f.Add(testPool.Bytes(), false, new(big.Int).Lsh(big.NewInt(1), 96).Bytes(), []byte{})
+	f.Add([]byte{}, false, MaxSqrtRatio.Bytes(), []byte{0x01, 0x02, 0x03})
+
+	f.Fuzz(func(t *testing.T, pool []byte, zeroForOne bool, limit, callbackData []byte) {
+		params := &ExtraDataParams{
+			Pool:              common.BytesToAddress(pool),
+			ZeroForOne:        zeroForOne,
+			SqrtPriceLimitX96: new(big.Int).SetBytes(limit),
+			CallbackData:      callbackData,
+		}
+		data, err := EncodeExtraData(params)
+		if err != nil {
+			return // Price limit outside the V3 price range
+		}
+		got, err := DecodeExtraData(data)
+		if err != nil {
+			t.Fatalf("DecodeExtraData(EncodeExtraData(%+v)) failed: %v", params, err)
+		}
+		if got.Pool != params.Pool || got.ZeroForOne != params.ZeroForOne || got.SqrtPriceLimitX96.Cmp(params.SqrtPriceLimitX96) != 0 ||
+			!bytes.Equal(got.CallbackData, params.CallbackData) {
+			t.Fatalf("round trip = %+v, want %+v", got, params)
+		}
+		again, err := EncodeExtraData(got)
+		if err != nil || !bytes.Equal(again, data) {
+			t.Fatalf("re-encoding the decoded fields = %x, %v, want %x", again, err, data)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package signer
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	testWBNB = common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c")
+	testUSDT = common.HexToAddress("0x55d398326f99059fF775485246999027B3197955")
+	testPool = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	testMM   = common.HexToAddress("0x1111111111111111111111111111111111111111")
+)
+
+func TestDetermineZeroForOne(t *testing.T) {
+	// USDT sorts before WBNB, so USDT is token0 of the pool
+	if !DetermineZeroForOne(testUSDT, testWBNB) {
+		t.Error("USDT -> WBNB is not zeroForOne")
+	}
+	if DetermineZeroForOne(testWBNB, testUSDT) {
+		t.Error("WBNB -> USDT is zeroForOne")
+	}
+}
+
+func TestQuoteSqrtPriceLimit(t *testing.T) {
+	q96 := new(big.Int).Lsh(big.NewInt(1), 96)
+	one := big.NewInt(1e18)
+	if got := QuoteSqrtPriceLimit(true, one, one); got.Cmp(q96) != 0 {
+		t.Errorf("1:1 limit = %s, want 2^96", got)
+	}
+	// Selling token1 for token0 at 4 token1 each: token1/token0 = 4, sqrt = 2
+	if got := QuoteSqrtPriceLimit(false, big.NewInt(4e18), one); got.Cmp(new(big.Int).Lsh(q96, 1)) != 0 {
+		t.Errorf("4:1 limit = %s, want 2 * 2^96", got)
+	}
+	if got := QuoteSqrtPriceLimit(true, one, big.NewInt(0)); got.Cmp(new(big.Int).Add(MinSqrtRatio, big.NewInt(1))) != 0 {
+		t.Errorf("zero output limit = %s, want MinSqrtRatio+1", got)
+	}
+}
+
+func TestEncodeExtraData(t *testing.T) {
+	params := V3PoolHedgeParams(testPool, testMM, testWBNB, testUSDT, big.NewInt(1e18), new(big.Int).Mul(big.NewInt(600), big.NewInt(1e18)))
+	data, err := EncodeExtraData(params)
+	if err != nil {
+		t.Fatalf("EncodeExtraData failed: %v", err)
+	}
+
+	// Head: pool, zeroForOne, sqrtPriceLimit, offset of callbackData; tail: length, 3 words
+	word := func(i int) []byte { return data[32*i : 32*(i+1)] }
+	if len(data) != 32*8 {
+		t.Fatalf("extraData is %d bytes, want %d", len(data), 32*8)
+	}
+	if !bytes.Equal(word(0), common.LeftPadBytes(testPool.Bytes(), 32)) {
+		t.Errorf("word 0 = %x, want the pool", word(0))
+	}
+	if new(big.Int).SetBytes(word(1)).Sign() != 0 {
+		t.Errorf("word 1 = %x, want false: WBNB -> USDT is oneForZero", word(1))
+	}
+	if new(big.Int).SetBytes(word(2)).Cmp(params.SqrtPriceLimitX96) != 0 {
+		t.Errorf("word 2 = %x, want the price limit", word(2))
+	}
+	if new(big.Int).SetBytes(word(3)).Uint64() != 128 || new(big.Int).SetBytes(word(4)).Uint64() != 96 {
+		t.Errorf("callbackData offset %x, length %x", word(3), word(4))
+	}
+	if !bytes.Equal(data[32*5:], BuildCallbackData(testMM, testWBNB, testUSDT)) {
+		t.Error("tail is not the callback data")
+	}
+
+	params.SqrtPriceLimitX96 = new(big.Int).Set(MaxSqrtRatio)
+	if _, err := EncodeExtraData(params); err == nil {
+		t.Error("EncodeExtraData accepted a limit outside the V3 price range")
+	}
+}
+
+func TestDecodeExtraData(t *testing.T) {
+	params := V3PoolHedgeParams(testPool, testMM, testUSDT, testWBNB, big.NewInt(600e6), big.NewInt(1e18))
+	data, err := EncodeExtraData(params)
+	if err != nil {
+		t.Fatalf("EncodeExtraData failed: %v", err)
+	}
+	got, err := DecodeExtraData(data)
+	if err != nil {
+		t.Fatalf("DecodeExtraData failed: %v", err)
+	}
+	if got.Pool != params.Pool || got.ZeroForOne != params.ZeroForOne ||
+		got.SqrtPriceLimitX96.Cmp(params.SqrtPriceLimitX96) != 0 || !bytes.Equal(got.CallbackData, params.CallbackData) {
+		t.Errorf("DecodeExtraData = %+v, want %+v", got, params)
+	}
+
+	if got, err := DecodeExtraData(nil); got != nil || err != nil {
+		t.Errorf("DecodeExtraData(nil) = %v, %v, want nil, nil", got, err)
+	}
+	dirty := bytes.Clone(data)
+	dirty[0] = 0xff // Padding of the pool address
+	trailing := append(bytes.Clone(data), 0)
+	for name, bad := range map[string][]byte{
+		"truncated":     data[:100],
+		"dirty padding": dirty,
+		"trailing byte": trailing,
+	} {
+		if _, err := DecodeExtraData(bad); err == nil {
+			t.Errorf("DecodeExtraData accepted %s extraData", name)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BackupKeyName names the backup signer of a Failover
+const BackupKeyName = "backup"
+
+// Fail-back policies of a Failover
+const (
+	FailBackAuto   = "auto"   // Return to the primary once it passes FailBackAfter health checks in a row
+	FailBackManual = "manual" // Stay on the backup until FailBack is called
+)
+
+// FailoverConfig configures when a Failover switches keys
+type FailoverConfig struct {
+	FailAfter     int    // Consecutive primary errors before failing over (default 3)
+	FailBack      string // FailBackAuto (default) or FailBackManual
+	FailBackAfter int    // Passed primary health checks in a row before an automatic fail-back (default 3)
+
+	// OnChange is called after every switch, outside the lock; nil = none
+	OnChange func(FailoverState)
+}
+
+// FailoverState is the state of a Failover
+type FailoverState struct {
+	Active    string         `json:"active"` // PrimaryKeyName or BackupKeyName
+	Primary   common.Address `json:"primary"`
+	Backup    common.Address `json:"backup"`
+	Since     time.Time      `json:"since"`               // Last switch, or creation
+	Failovers uint64         `json:"failovers"`           // Switches to the backup
+	Failures  int            `json:"failures"`            // Consecutive primary errors
+	LastError string         `json:"lastError,omitempty"` // Latest primary error
+}
+
+// Failover signs with a primary key and switches to a backup key while the primary fails
+// The primary stays the MM identity: GetAddress returns it, for the mm_id of messages, and the
+// order's signer field names the key that signed. Quotes signed while the primary fails are
+// rejected by the caller; the switch happens after FailAfter errors in a row, counting signing
+// errors and failed health checks. Deadline refusals are not backend errors and do not count.
+type Failover struct {
+	primary, backup Signer
+	cfg             FailoverConfig
+	now             func() time.Time
+
+	mu        sync.Mutex
+	onBackup  bool
+	failures  int // Consecutive primary errors
+	passes    int // Consecutive primary health checks passed while on the backup
+	failovers uint64
+	since     time.Time
+	lastErr   error
+}
+
+// NewFailover creates a failover signer of primary and backup, starting on the primary
+func NewFailover(primary, backup Signer, cfg FailoverConfig) *Failover {
+	if cfg.FailAfter <= 0 {
+		cfg.FailAfter = 3
+	}
+	if cfg.FailBack == "" {
+		cfg.FailBack = FailBackAuto
+	}
+	if cfg.FailBackAfter <= 0 {
+		cfg.FailBackAfter = 3
+	}
+	return &Failover{primary: primary, backup: backup, cfg: cfg, now: time.Now, since: time.Now()}
+}
+
+// GetAddress returns the address of the primary key, the MM identity
+func (f *Failover) GetAddress() common.Address {
+	return f.primary.GetAddress()
+}
+
+// Primary returns the primary signer
+func (f *Failover) Primary() Signer {
+	return f.primary
+}
+
+// Backup returns the backup signer
+func (f *Failover) Backup() Signer {
+	return f.backup
+}
+
+// Assign returns the active key; its signing errors are reported to the failover
+// A primary of several keys (a MultiSigner) assigns the key that signs.
+func (f *Failover) Assign(chainID uint64, pairID string) (Signer, error) {
+	f.mu.Lock()
+	onBackup := f.onBackup
+	f.mu.Unlock()
+	if onBackup {
+		return f.backup, nil
+	}
+	key := f.primary
+	if assigner, ok := key.(KeyAssigner); ok {
+		var err error
+		if key, err = assigner.Assign(chainID, pairID); err != nil {
+			return nil, err
+		}
+	}
+	return &failoverKey{Signer: key, f: f}, nil
+}
+
+// SignMMQuote signs with the active key
+func (f *Failover) SignMMQuote(chainID uint64, quote *MMQuote) ([]byte, error) {
+	s, err := f.Assign(chainID, "")
+	if err != nil {
+		return nil, err
+	}
+	return s.SignMMQuote(chainID, quote)
+}
+
+// SignTypedData signs with the primary key, the MM identity
+// Typed data (depth attestations, permits) must recover to mm_id, so it never fails over.
+func (f *Failover) SignTypedData(domainSeparator []byte, structHash common.Hash) ([]byte, error) {
+	s, ok := f.primary.(TypedDataSigner)
+	if !ok {
+		return nil, fmt.Errorf("primary key cannot sign typed data")
+	}
+	return s.SignTypedData(domainSeparator, structHash)
+}
+
+// ReportCheck records the result of a health check of the primary key
+// Failed checks count towards a failover like signing errors. While on the backup, FailBackAfter
+// passed checks in a row switch back to the primary under the automatic policy.
+func (f *Failover) ReportCheck(err error) {
+	f.record(err, true)
+}
+
+// FailBack switches back to the primary key
+func (f *Failover) FailBack() error {
+	f.mu.Lock()
+	if !f.onBackup {
+		f.mu.Unlock()
+		return fmt.Errorf("already signing with the primary key")
+	}
+	state := f.switchLocked(false)
+	f.mu.Unlock()
+	f.notify(state)
+	return nil
+}
+
+// FailOver switches to the backup key, e.g. ahead of planned primary maintenance
+func (f *Failover) FailOver() error {
+	f.mu.Lock()
+	if f.onBackup {
+		f.mu.Unlock()
+		return fmt.Errorf("already signing with the backup key")
+	}
+	state := f.switchLocked(true)
+	f.mu.Unlock()
+	f.notify(state)
+	return nil
+}
+
+// State returns the current state
+func (f *Failover) State() FailoverState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stateLocked()
+}
+
+// record counts a primary result, switching keys when a threshold is reached
+func (f *Failover) record(err error, check bool) {
+	if errors.Is(err, ErrDeadlineTooFar) {
+		return
+	}
+	f.mu.Lock()
+	var state *FailoverState
+	switch {
+	case err != nil:
+		f.failures++
+		f.passes = 0
+		f.lastErr = err
+		if !f.onBackup && f.failures >= f.cfg.FailAfter {
+			s := f.switchLocked(true)
+			state = &s
+		}
+	case f.onBackup:
+		// Quotes go to the backup, so only health checks show the primary has recovered
+		if check {
+			f.passes++
+			if f.cfg.FailBack == FailBackAuto && f.passes >= f.cfg.FailBackAfter {
+				s := f.switchLocked(false)
+				state = &s
+			}
+		}
+	default:
+		f.failures = 0
+	}
+	f.mu.Unlock()
+	if state != nil {
+		f.notify(*state)
+	}
+}
+
+// switchLocked activates the backup or the primary and returns the new state
+func (f *Failover) switchLocked(backup bool) FailoverState {
+	f.onBackup = backup
+	f.since = f.now()
+	f.passes = 0
+	if backup {
+		f.failovers++
+	} else {
+		f.failures = 0
+	}
+	return f.stateLocked()
+}
+
+// stateLocked returns the current state; f.mu must be held
+func (f *Failover) stateLocked() FailoverState {
+	state := FailoverState{
+		Active:    PrimaryKeyName,
+		Primary:   f.primary.GetAddress(),
+		Backup:    f.backup.GetAddress(),
+		Since:     f.since,
+		Failovers: f.failovers,
+		Failures:  f.failures,
+	}
+	if f.onBackup {
+		state.Active = BackupKeyName
+	}
+	if f.lastErr != nil {
+		state.LastError = f.lastErr.Error()
+	}
+	return state
+}
+
+// notify calls OnChange with the state after a switch
+func (f *Failover) notify(state FailoverState) {
+	if f.cfg.OnChange != nil {
+		f.cfg.OnChange(state)
+	}
+}
+
+// failoverKey is the primary key as assigned to one quote, reporting its signing errors
+type failoverKey struct {
+	Signer
+	f *Failover
+}
+
+// SignMMQuote signs with the primary key and records the result
+func (k *failoverKey) SignMMQuote(chainID uint64, quote *MMQuote) ([]byte, error) {
+	sig, err := k.Signer.SignMMQuote(chainID, quote)
+	k.f.record(err, false)
+	return sig, err
+}
+
+// SignMMQuoteClamped signs with the deadline clamped when the primary key supports it
+func (k *failoverKey) SignMMQuoteClamped(chainID uint64, quote *MMQuote) ([]byte, error) {
+	clamper, ok := k.Signer.(DeadlineClamper)
+	if !ok {
+		return k.SignMMQuote(chainID, quote)
+	}
+	sig, err := clamper.SignMMQuoteClamped(chainID, quote)
+	k.f.record(err, false)
+	return sig, err
+}
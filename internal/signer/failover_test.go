@@ -0,0 +1,133 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// flakySigner is a signer whose SignMMQuote fails while err is set
+type flakySigner struct {
+	Signer
+	err error
+}
+
+func (s *flakySigner) SignMMQuote(chainID uint64, quote *MMQuote) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.Signer.SignMMQuote(chainID, quote)
+}
+
+// newTestFailover creates a failover of a flaky primary key and a backup key
+func newTestFailover(t *testing.T, cfg FailoverConfig) (*Failover, *flakySigner, common.Address) {
+	t.Helper()
+	domains := NewDomainManager()
+	domains.AddPoolDomain(56, common.HexToAddress("0x1111111111111111111111111111111111111111"))
+	var keys []Signer
+	for i := 1; i <= 2; i++ {
+		s, err := NewSignerFromHex(fmt.Sprintf("0x%064x", i), domains)
+		if err != nil {
+			t.Fatalf("NewSignerFromHex failed: %v", err)
+		}
+		keys = append(keys, s)
+	}
+	primary := &flakySigner{Signer: keys[0]}
+	return NewFailover(primary, keys[1], cfg), primary, keys[1].GetAddress()
+}
+
+// signWith signs a quote through Assign and returns the address of the assigned key
+func signWith(t *testing.T, f *Failover) (common.Address, error) {
+	t.Helper()
+	s, err := f.Assign(56, "WBNB-USDT")
+	if err != nil {
+		t.Fatalf("Assign failed: %v", err)
+	}
+	_, err = s.SignMMQuote(56, canaryQuote(&EIP712Domain{}))
+	return s.GetAddress(), err
+}
+
+func TestFailover_FailsOverAndBack(t *testing.T) {
+	var changes []FailoverState
+	f, primary, backup := newTestFailover(t, FailoverConfig{FailAfter: 2, FailBackAfter: 2, OnChange: func(s FailoverState) { changes = append(changes, s) }})
+	identity := primary.GetAddress()
+
+	primary.err = errors.New("kms unavailable")
+	for i := 0; i < 2; i++ {
+		if addr, err := signWith(t, f); addr != identity || err == nil {
+			t.Fatalf("signature %d: %s, %v; want a primary error", i, addr.Hex(), err)
+		}
+	}
+	if addr, err := signWith(t, f); addr != backup || err != nil {
+		t.Fatalf("after 2 errors: %s, %v; want the backup", addr.Hex(), err)
+	}
+	if f.GetAddress() != identity {
+		t.Error("GetAddress changed with the failover, want the primary identity")
+	}
+	if len(changes) != 1 || changes[0].Active != BackupKeyName || changes[0].Failovers != 1 || changes[0].LastError != "kms unavailable" {
+		t.Fatalf("changes = %+v", changes)
+	}
+
+	// Recovery is seen through health checks only; a failed check restarts the count
+	primary.err = nil
+	f.ReportCheck(nil)
+	f.ReportCheck(errors.New("still down"))
+	f.ReportCheck(nil)
+	if f.State().Active != BackupKeyName {
+		t.Fatal("failed back without 2 passed checks in a row")
+	}
+	f.ReportCheck(nil)
+	if state := f.State(); state.Active != PrimaryKeyName || state.Failures != 0 || len(changes) != 2 {
+		t.Fatalf("state = %+v, changes = %d; want back on the primary", state, len(changes))
+	}
+	if addr, err := signWith(t, f); addr != identity || err != nil {
+		t.Errorf("after fail-back: %s, %v", addr.Hex(), err)
+	}
+}
+
+func TestFailover_ManualFailBack(t *testing.T) {
+	f, primary, _ := newTestFailover(t, FailoverConfig{FailAfter: 1, FailBack: FailBackManual})
+	if err := f.FailBack(); err == nil {
+		t.Error("FailBack on the primary succeeded")
+	}
+
+	// Deadline refusals are not backend errors
+	primary.err = fmt.Errorf("%w: too far", ErrDeadlineTooFar)
+	_, _ = signWith(t, f)
+	if f.State().Active != PrimaryKeyName {
+		t.Fatal("failed over on a deadline refusal")
+	}
+
+	primary.err = errors.New("hsm unreachable")
+	_, _ = signWith(t, f)
+	for i := 0; i < 5; i++ {
+		f.ReportCheck(nil)
+	}
+	if f.State().Active != BackupKeyName {
+		t.Fatal("failed back automatically under the manual policy")
+	}
+	if err := f.FailBack(); err != nil || f.State().Active != PrimaryKeyName {
+		t.Errorf("FailBack() = %v, active %s", err, f.State().Active)
+	}
+}
+
+func TestPool_AssignsThroughFailover(t *testing.T) {
+	f, primary, backup := newTestFailover(t, FailoverConfig{FailAfter: 1})
+	pool, err := NewPool(f, nil, PoolAssignment{Policy: PolicyFailover})
+	if err != nil {
+		t.Fatal(err)
+	}
+	primary.err = errors.New("kms unavailable")
+	if _, err := pool.SignMMQuote(56, canaryQuote(&EIP712Domain{})); err == nil {
+		t.Fatal("pool signed with a failing primary")
+	}
+	s, err := pool.Assign(56, "")
+	if err != nil || s.GetAddress() != backup {
+		t.Errorf("Assign() = %v, %v; want the backup key", s, err)
+	}
+	if pool.GetAddress() != primary.GetAddress() {
+		t.Error("pool identity is not the primary key")
+	}
+}
@@ -0,0 +1,128 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrSigningTimeout is returned when a hardware wallet does not answer within the signing timeout
+// e.g. because nobody confirmed the signature on the device
+var ErrSigningTimeout = errors.New("hardware wallet signing timed out")
+
+// HardwareWallet is a device holding one key that signs EIP-712 messages
+type HardwareWallet interface {
+	// Address returns the address of the key
+	Address() common.Address
+	// SignTypedData signs "\x19\x01" || domainSeparator || structHash (66 bytes), v = 27/28
+	// It blocks until the device answers, which may need a confirmation on the device.
+	SignTypedData(message []byte) ([]byte, error)
+	// Close releases the device
+	Close() error
+}
+
+// HardwareSigner signs quotes on a hardware wallet
+// Requests are sent to the device one at a time; a request waits for the device at most the
+// signing timeout, including the wait for earlier requests. A timed-out request keeps the device
+// busy until it is confirmed or rejected there. Signatures are verified before they are returned.
+type HardwareSigner struct {
+	device        HardwareWallet
+	domainManager *DomainManager
+	timeout       time.Duration
+	busy          chan struct{} // Holds a token while a request is on the device
+
+	maxHorizon time.Duration // Deadlines further ahead are refused (0 = unlimited)
+	now        func() time.Time
+}
+
+// NewHardwareSigner creates a signer of device; timeout 0 waits for the device indefinitely
+func NewHardwareSigner(device HardwareWallet, domainManager *DomainManager, timeout, maxHorizon time.Duration) *HardwareSigner {
+	return &HardwareSigner{
+		device:        device,
+		domainManager: domainManager,
+		timeout:       timeout,
+		busy:          make(chan struct{}, 1),
+		maxHorizon:    maxHorizon,
+		now:           time.Now,
+	}
+}
+
+// GetAddress returns the address of the device key
+func (s *HardwareSigner) GetAddress() common.Address {
+	return s.device.Address()
+}
+
+// SignMMQuote signs an MMQuote using EIP-712 (with verifying contract domain)
+func (s *HardwareSigner) SignMMQuote(chainID uint64, quote *MMQuote) ([]byte, error) {
+	if err := checkHorizon(quote, s.maxHorizon, s.now()); err != nil {
+		return nil, err
+	}
+	domainSeparator, structHash, err := s.domainManager.hashQuote(chainID, quote)
+	if err != nil {
+		return nil, err
+	}
+	return s.SignTypedData(domainSeparator, structHash)
+}
+
+// SignMMQuoteClamped signs quote with its deadline clamped to the maximum horizon
+func (s *HardwareSigner) SignMMQuoteClamped(chainID uint64, quote *MMQuote) ([]byte, error) {
+	clampHorizon(quote, s.maxHorizon, s.now())
+	return s.SignMMQuote(chainID, quote)
+}
+
+// SignTypedData signs the EIP-712 digest of a struct hash under a domain separator on the device
+func (s *HardwareSigner) SignTypedData(domainSeparator []byte, structHash common.Hash) ([]byte, error) {
+	message := make([]byte, 66)
+	message[0], message[1] = 0x19, 0x01
+	copy(message[2:34], domainSeparator)
+	copy(message[34:], structHash[:])
+
+	var expired <-chan time.Time
+	if s.timeout > 0 {
+		timer := time.NewTimer(s.timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case s.busy <- struct{}{}:
+	case <-expired:
+		return nil, fmt.Errorf("%w: device busy with an earlier request", ErrSigningTimeout)
+	}
+
+	type result struct {
+		sig []byte
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		sig, err := s.device.SignTypedData(message)
+		<-s.busy // Free the device only once it has answered
+		done <- result{sig, err}
+	}()
+	var res result
+	select {
+	case res = <-done:
+	case <-expired:
+		return nil, fmt.Errorf("%w after %s", ErrSigningTimeout, s.timeout)
+	}
+	if res.err != nil {
+		return nil, fmt.Errorf("hardware wallet: %w", res.err)
+	}
+
+	// A device signing with another key (wrong derivation path, other seed) fails here, not on-chain
+	recovered, err := recoverSigner(typedDataHash(domainSeparator, structHash), res.sig)
+	if err != nil {
+		return nil, fmt.Errorf("hardware wallet signature invalid: %w", err)
+	}
+	if recovered != s.device.Address() {
+		return nil, fmt.Errorf("hardware wallet signed with %s, want %s", recovered.Hex(), s.device.Address().Hex())
+	}
+	return res.sig, nil
+}
+
+// Close releases the device
+func (s *HardwareSigner) Close() error {
+	return s.device.Close()
+}
@@ -0,0 +1,99 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// fakeDevice signs with a private key after confirm releases it, or at once if confirm is nil
+type fakeDevice struct {
+	key     *ecdsa.PrivateKey
+	address common.Address // Claimed address, may differ from the key's
+	confirm chan struct{}
+}
+
+func newFakeDevice(t *testing.T, n int) *fakeDevice {
+	t.Helper()
+	key, err := crypto.HexToECDSA(fmt.Sprintf("%064x", n))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &fakeDevice{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+func (d *fakeDevice) Address() common.Address { return d.address }
+func (d *fakeDevice) Close() error            { return nil }
+
+func (d *fakeDevice) SignTypedData(message []byte) ([]byte, error) {
+	if len(message) != 66 || message[0] != 0x19 || message[1] != 0x01 {
+		return nil, errors.New("not an EIP-712 message")
+	}
+	if d.confirm != nil {
+		<-d.confirm
+	}
+	sig, err := crypto.Sign(crypto.Keccak256(message), d.key)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+func TestHardwareSigner_SignsLikeAKey(t *testing.T) {
+	domains := NewDomainManager()
+	domains.AddPoolDomain(56, common.HexToAddress("0x1111111111111111111111111111111111111111"))
+	hw := NewHardwareSigner(newFakeDevice(t, 5), domains, time.Second, 0)
+	if err := CheckSigner(hw, domains, 56); err != nil {
+		t.Fatalf("CheckSigner failed: %v", err)
+	}
+	key, _ := NewSignerFromHex(fmt.Sprintf("%064x", 5), domains)
+	quote := canaryQuote(domains.GetPoolDomain(56))
+	want, _ := key.SignMMQuote(56, quote)
+	got, err := hw.SignMMQuote(56, quote)
+	if err != nil || common.Bytes2Hex(got) != common.Bytes2Hex(want) {
+		t.Errorf("signature %x (%v), want %x", got, err, want)
+	}
+
+	// A device signing with another key than it claims is caught
+	wrong := newFakeDevice(t, 5)
+	wrong.address = common.HexToAddress("0x02")
+	if _, err := NewHardwareSigner(wrong, domains, time.Second, 0).SignMMQuote(56, quote); err == nil || !strings.Contains(err.Error(), "signed with") {
+		t.Errorf("wrong key: %v", err)
+	}
+}
+
+func TestHardwareSigner_Timeout(t *testing.T) {
+	domains := NewDomainManager()
+	domains.AddPoolDomain(56, common.HexToAddress("0x1111111111111111111111111111111111111111"))
+	device := newFakeDevice(t, 5)
+	device.confirm = make(chan struct{})
+	hw := NewHardwareSigner(device, domains, 20*time.Millisecond, 0)
+	quote := canaryQuote(domains.GetPoolDomain(56))
+
+	if _, err := hw.SignMMQuote(56, quote); !errors.Is(err, ErrSigningTimeout) {
+		t.Fatalf("unconfirmed: %v, want ErrSigningTimeout", err)
+	}
+	// The device is still waiting for the first confirmation
+	if _, err := hw.SignMMQuote(56, quote); !errors.Is(err, ErrSigningTimeout) || !strings.Contains(err.Error(), "busy") {
+		t.Fatalf("while busy: %v, want a busy timeout", err)
+	}
+	device.confirm <- struct{}{}
+	close(device.confirm)
+	deadline := time.Now().Add(time.Second)
+	for {
+		_, err := hw.SignMMQuote(56, quote)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("device not released after the confirmation: %v", err)
+		}
+	}
+}
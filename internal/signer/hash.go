@@ -0,0 +1,72 @@
+package signer
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// mmQuoteWords is the number of 32-byte words in the ABI encoding of an MMQuote struct hash input
+// All fields are static, so the encoding is fixed-size and needs no abi.Arguments
+const mmQuoteWords = 11
+
+// hasher is a keccak state with scratch space, pooled so the signing path does not allocate
+// Buffers passed to the keccak interface escape, so they live here rather than on the stack
+type hasher struct {
+	state crypto.KeccakState
+	buf   [mmQuoteWords * 32]byte
+	out   common.Hash
+}
+
+var hasherPool = sync.Pool{
+	New: func() any { return &hasher{state: crypto.NewKeccakState()} },
+}
+
+// getHasher returns a reset hasher from the pool
+func getHasher() *hasher {
+	h := hasherPool.Get().(*hasher)
+	h.state.Reset()
+	return h
+}
+
+// sum hashes b (a slice of h.buf or caller data) and returns h to the pool
+func (h *hasher) sum(b []byte) common.Hash {
+	h.state.Write(b)
+	h.state.Read(h.out[:])
+	out := h.out
+	hasherPool.Put(h)
+	return out
+}
+
+// putAddress writes an address as a left-padded ABI word
+func putAddress(word []byte, addr common.Address) {
+	clear(word[:12])
+	copy(word[12:], addr[:])
+}
+
+// putUint256 writes n as an ABI uint256 word
+// Like abi.Pack, values outside [0, 2^256) are reduced modulo 2^256
+func putUint256(word []byte, n *big.Int) error {
+	if n == nil {
+		return fmt.Errorf("nil uint256")
+	}
+	if n.Sign() < 0 || n.BitLen() > 256 {
+		copy(word, math.U256Bytes(new(big.Int).Set(n)))
+		return nil
+	}
+	n.FillBytes(word)
+	return nil
+}
+
+// typedDataHash calculates the EIP-712 digest: keccak256("\x19\x01" || domainSeparator || structHash)
+func typedDataHash(domainSeparator []byte, structHash common.Hash) common.Hash {
+	h := getHasher()
+	h.buf[0], h.buf[1] = 0x19, 0x01
+	copy(h.buf[2:34], domainSeparator)
+	copy(h.buf[34:66], structHash[:])
+	return h.sum(h.buf[:66])
+}
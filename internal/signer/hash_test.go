@@ -0,0 +1,136 @@
+package signer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// abiHashMMQuote is the abi.Arguments reference encoding of hashMMQuote
+func abiHashMMQuote(t *testing.T, quote *MMQuote) common.Hash {
+	t.Helper()
+
+	bytes32Ty, _ := abi.NewType("bytes32", "", nil)
+	addressTy, _ := abi.NewType("address", "", nil)
+	uint256Ty, _ := abi.NewType("uint256", "", nil)
+	args := abi.Arguments{
+		{Type: bytes32Ty}, {Type: addressTy}, {Type: addressTy}, {Type: addressTy}, {Type: addressTy}, {Type: addressTy},
+		{Type: uint256Ty}, {Type: uint256Ty}, {Type: uint256Ty}, {Type: uint256Ty}, {Type: bytes32Ty},
+	}
+	encoded, err := args.Pack(MMQuoteTypeHash, quote.RFQManager, quote.From, quote.To, quote.InputToken, quote.OutputToken,
+		quote.AmountIn, quote.AmountOut, quote.Deadline, quote.Nonce, crypto.Keccak256Hash(quote.ExtraData))
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	return crypto.Keccak256Hash(encoded)
+}
+
+func TestHashMMQuote_MatchesABI(t *testing.T) {
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	tests := []struct {
+		name   string
+		modify func(q *MMQuote)
+	}{
+		{"default", func(q *MMQuote) {}},
+		{"zero", func(q *MMQuote) { q.AmountIn = big.NewInt(0) }},
+		{"max uint256", func(q *MMQuote) { q.AmountOut = maxUint256 }},
+		{"negative", func(q *MMQuote) { q.Nonce = big.NewInt(-1) }},
+		{"over 256 bits", func(q *MMQuote) { q.Deadline = new(big.Int).Lsh(big.NewInt(3), 256) }},
+		{"extra data", func(q *MMQuote) { q.ExtraData = []byte{1, 2, 3} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := benchQuote()
+			tt.modify(quote)
+			got, err := hashMMQuote(quote)
+			if err != nil {
+				t.Fatalf("hashMMQuote failed: %v", err)
+			}
+			if want := abiHashMMQuote(t, quote); got != want {
+				t.Errorf("hashMMQuote = %s, want %s", got.Hex(), want.Hex())
+			}
+		})
+	}
+
+	quote := benchQuote()
+	quote.Nonce = nil
+	if _, err := hashMMQuote(quote); err == nil {
+		t.Error("hashMMQuote accepted a nil nonce")
+	}
+}
+
+func TestDomainManager_CachedSeparator(t *testing.T) {
+	dm := NewDomainManager()
+	dm.AddPoolDomainWithConfig(56, "RFQ Manager", "1", "0x28D3a265f6d40867986004029ee91F4C9532fCC5")
+
+	got, ok := dm.GetPoolDomainSeparator(56)
+	if !ok {
+		t.Fatal("GetPoolDomainSeparator not found")
+	}
+	if want := dm.GetPoolDomain(56).DomainSeparator(); string(got) != string(want) {
+		t.Errorf("Cached separator = %x, want %x", got, want)
+	}
+
+	// Replacing the domain refreshes the cache
+	dm.AddPoolDomainWithConfig(56, "RFQ Manager", "2", "0x28D3a265f6d40867986004029ee91F4C9532fCC5")
+	if again, _ := dm.GetPoolDomainSeparator(56); string(again) == string(got) {
+		t.Error("Separator not refreshed after the domain changed")
+	}
+}
+
+func benchQuote() *MMQuote {
+	amountOut, _ := new(big.Int).SetString("600000000000000000000", 10)
+	return &MMQuote{
+		RFQManager:  common.HexToAddress("0x28D3a265f6d40867986004029ee91F4C9532fCC5"),
+		From:        common.HexToAddress("0x1234567890123456789012345678901234567890"),
+		To:          common.HexToAddress("0x1234567890123456789012345678901234567890"),
+		InputToken:  common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c"),
+		OutputToken: common.HexToAddress("0x55d398326f99059fF775485246999027B3197955"),
+		AmountIn:    big.NewInt(1000000000000000000),
+		AmountOut:   amountOut,
+		Deadline:    big.NewInt(1735084800),
+		Nonce:       big.NewInt(1),
+		ExtraData:   []byte{},
+	}
+}
+
+func BenchmarkHashMMQuote(b *testing.B) {
+	quote := benchQuote()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := hashMMQuote(quote); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDomainSeparator(b *testing.B) {
+	dm := NewDomainManager()
+	dm.AddPoolDomain(56, common.HexToAddress("0x28D3a265f6d40867986004029ee91F4C9532fCC5"))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, ok := dm.GetPoolDomainSeparator(56); !ok {
+			b.Fatal("domain not found")
+		}
+	}
+}
+
+func BenchmarkSignMMQuote(b *testing.B) {
+	dm := NewDomainManager()
+	dm.AddPoolDomain(56, common.HexToAddress("0x28D3a265f6d40867986004029ee91F4C9532fCC5"))
+	s, err := NewSignerFromHex("0x0000000000000000000000000000000000000000000000000000000000000001", dm)
+	if err != nil {
+		b.Fatal(err)
+	}
+	quote := benchQuote()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.SignMMQuote(56, quote); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// canaryQuote is the quote signed by health checks
+// Zero amounts and a deadline at the Unix epoch: the signature can never settle an order
+func canaryQuote(domain *EIP712Domain) *MMQuote {
+	return &MMQuote{
+		RFQManager: domain.VerifyingContract,
+		AmountIn:   new(big.Int),
+		AmountOut:  new(big.Int),
+		Deadline:   new(big.Int),
+		Nonce:      new(big.Int),
+	}
+}
+
+// CheckSigner signs a canary quote on chainID with s and verifies that it recovers to s
+// Catches signers that fail or sign with another key (e.g. a remote signer outage or expired
+// credentials) before a quote request needs them.
+func CheckSigner(s Signer, domains *DomainManager, chainID uint64) error {
+	domain := domains.GetPoolDomain(chainID)
+	if domain == nil {
+		return fmt.Errorf("no domain configured for chain %d", chainID)
+	}
+	quote := canaryQuote(domain)
+	sig, err := s.SignMMQuote(chainID, quote)
+	if err != nil {
+		return fmt.Errorf("canary signing failed: %w", err)
+	}
+	digest, err := domains.Digest(chainID, quote)
+	if err != nil {
+		return err
+	}
+	want := s.GetAddress()
+	if w, ok := s.(*ContractWallet); ok {
+		// The wallet's signature is its owner's; isValidSignature is checked by CheckContractWallet
+		digest, want = w.OwnerHash(chainID, digest), w.Owner().GetAddress()
+	}
+	signer, err := recoverSigner(digest, sig)
+	if err != nil {
+		return fmt.Errorf("canary signature invalid: %w", err)
+	}
+	if signer != want {
+		return fmt.Errorf("canary signature recovers to %s, want %s", signer.Hex(), want.Hex())
+	}
+	return nil
+}
+
+// CheckContractWallet signs a canary quote on chainID with w and verifies it with the wallet's
+// isValidSignature through client
+// Catches a wallet that does not accept the owner (not an owner, threshold above 1, no ERC-1271
+// fallback handler) before a quote is signed for it.
+func CheckContractWallet(ctx context.Context, w *ContractWallet, domains *DomainManager, chainID uint64, client RPCClient) error {
+	domain := domains.GetPoolDomain(chainID)
+	if domain == nil {
+		return fmt.Errorf("no domain configured for chain %d", chainID)
+	}
+	quote := canaryQuote(domain)
+	sig, err := w.SignMMQuote(chainID, quote)
+	if err != nil {
+		return fmt.Errorf("canary signing failed: %w", err)
+	}
+	digest, err := domains.Digest(chainID, quote)
+	if err != nil {
+		return err
+	}
+	return VerifyContractSignature(ctx, client, w.GetAddress(), digest, sig)
+}
+
+// recoverSigner returns the address that signed digest (v = 27/28)
+func recoverSigner(digest common.Hash, sig []byte) (common.Address, error) {
+	if len(sig) != 65 {
+		return common.Address{}, fmt.Errorf("signature is %d bytes, want 65", len(sig))
+	}
+	raw := append([]byte(nil), sig...)
+	if raw[64] >= 27 {
+		raw[64] -= 27
+	}
+	pub, err := crypto.SigToPub(digest[:], raw)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
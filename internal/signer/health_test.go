@@ -0,0 +1,52 @@
+package signer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// impostorSigner signs with its key but claims another address
+type impostorSigner struct {
+	Signer
+	addr common.Address
+}
+
+func (s impostorSigner) GetAddress() common.Address { return s.addr }
+
+// failingSigner fails every signature, like a remote signer that is down
+type failingSigner struct{ Signer }
+
+func (failingSigner) SignMMQuote(uint64, *MMQuote) ([]byte, error) {
+	return nil, errors.New("signer unavailable")
+}
+
+func TestCheckSigner(t *testing.T) {
+	dm := NewDomainManager()
+	dm.AddPoolDomain(56, common.HexToAddress("0x1111111111111111111111111111111111111111"))
+	s, err := NewSignerFromHex("0x0000000000000000000000000000000000000000000000000000000000000001", dm)
+	if err != nil {
+		t.Fatalf("NewSignerFromHex failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		signer  Signer
+		chainID uint64
+		wantErr bool
+	}{
+		{"healthy", s, 56, false},
+		{"other key", impostorSigner{s, common.HexToAddress("0x2222222222222222222222222222222222222222")}, 56, true},
+		{"signing fails", failingSigner{s}, 56, true},
+		{"unknown chain", s, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSigner(tt.signer, dm, tt.chainID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckSigner() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package signer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrKeystorePassword is returned when a keystore file does not decrypt with the password
+var ErrKeystorePassword = errors.New("keystore password is wrong (MAC mismatch)")
+
+// keystoreFile is an encrypted key in the Web3 Secret Storage format, version 3 (a geth "UTC--" file)
+type keystoreFile struct {
+	Address string `json:"address"`
+	Crypto  struct {
+		Cipher       string `json:"cipher"`
+		CipherText   string `json:"ciphertext"`
+		CipherParams struct {
+			IV string `json:"iv"`
+		} `json:"cipherparams"`
+		KDF       string          `json:"kdf"`
+		KDFParams json.RawMessage `json:"kdfparams"`
+		MAC       string          `json:"mac"`
+	} `json:"crypto"`
+	Version int `json:"version"`
+}
+
+// LoadKeystore reads and decrypts the geth keystore file at path
+func LoadKeystore(path, password string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore file: %w", err)
+	}
+	key, err := DecryptKeystore(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("keystore file %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// DecryptKeystore decrypts a version 3 keystore with password
+// Supports the scrypt and pbkdf2 key derivations with the aes-128-ctr cipher, as written by
+// geth, clef and most wallets. A wrong password is reported as ErrKeystorePassword.
+func DecryptKeystore(data []byte, password string) (*ecdsa.PrivateKey, error) {
+	var ks keystoreFile
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("not a keystore JSON file: %w", err)
+	}
+	if ks.Version != 3 {
+		return nil, fmt.Errorf("unsupported keystore version %d, want 3", ks.Version)
+	}
+	if ks.Crypto.Cipher != "aes-128-ctr" {
+		return nil, fmt.Errorf("unsupported keystore cipher %q, want aes-128-ctr", ks.Crypto.Cipher)
+	}
+	cipherText, err := hex.DecodeString(ks.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore ciphertext: %w", err)
+	}
+	iv, err := hex.DecodeString(ks.Crypto.CipherParams.IV)
+	if err != nil || len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid keystore cipher IV %q", ks.Crypto.CipherParams.IV)
+	}
+	mac, err := hex.DecodeString(ks.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore MAC: %w", err)
+	}
+
+	derived, err := deriveKeystoreKey(ks.Crypto.KDF, ks.Crypto.KDFParams, password)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(crypto.Keccak256(derived[16:32], cipherText), mac) != 1 {
+		return nil, ErrKeystorePassword
+	}
+
+	block, err := aes.NewCipher(derived[:16])
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(cipherText))
+	cipher.NewCTR(block, iv).XORKeyStream(plain, cipherText)
+	key, err := crypto.ToECDSA(common.LeftPadBytes(plain, 32))
+	if err != nil {
+		return nil, fmt.Errorf("keystore holds an invalid private key: %w", err)
+	}
+
+	// The address field is optional, but a mismatch means the file was edited or mixed up
+	if ks.Address != "" {
+		want := common.HexToAddress(ks.Address)
+		if got := crypto.PubkeyToAddress(key.PublicKey); got != want {
+			return nil, fmt.Errorf("keystore key is for %s, but the file names %s", got.Hex(), want.Hex())
+		}
+	}
+	return key, nil
+}
+
+// deriveKeystoreKey derives the 32-byte decryption and MAC key of a keystore from password
+func deriveKeystoreKey(kdf string, raw json.RawMessage, password string) ([]byte, error) {
+	var params struct {
+		DKLen int    `json:"dklen"`
+		Salt  string `json:"salt"`
+		N     int    `json:"n"` // scrypt
+		R     int    `json:"r"`
+		P     int    `json:"p"`
+		C     int    `json:"c"` // pbkdf2
+		PRF   string `json:"prf"`
+	}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, fmt.Errorf("invalid keystore kdfparams: %w", err)
+	}
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore salt: %w", err)
+	}
+	if params.DKLen < 32 {
+		return nil, fmt.Errorf("keystore dklen %d is shorter than 32", params.DKLen)
+	}
+
+	switch strings.ToLower(kdf) {
+	case "scrypt":
+		key, err := scrypt.Key([]byte(password), salt, params.N, params.R, params.P, params.DKLen)
+		if err != nil {
+			return nil, fmt.Errorf("invalid keystore scrypt parameters: %w", err)
+		}
+		return key, nil
+	case "pbkdf2":
+		if params.PRF != "hmac-sha256" {
+			return nil, fmt.Errorf("unsupported keystore pbkdf2 prf %q, want hmac-sha256", params.PRF)
+		}
+		if params.C <= 0 {
+			return nil, fmt.Errorf("invalid keystore pbkdf2 iteration count %d", params.C)
+		}
+		return pbkdf2.Key([]byte(password), salt, params.C, params.DKLen, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("unsupported keystore kdf %q, want scrypt or pbkdf2", kdf)
+	}
+}
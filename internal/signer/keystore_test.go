@@ -0,0 +1,77 @@
+package signer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Test vectors of the Web3 Secret Storage definition, as shipped with go-ethereum
+const (
+	keystoreLightScrypt = `{"address":"45dea0fb0bba44f4fcf290bba71fd57d7117cbb8","crypto":{"cipher":"aes-128-ctr","ciphertext":"b87781948a1befd247bff51ef4063f716cf6c2d3481163e9a8f42e1f9bb74145","cipherparams":{"iv":"dc4926b48a105133d2f16b96833abf1e"},"kdf":"scrypt","kdfparams":{"dklen":32,"n":2,"p":1,"r":8,"salt":"004244bbdc51cadda545b1cfa43cff9ed2ae88e08c61f1479dbb45410722f8f0"},"mac":"39990c1684557447940d4c69e06b1b82b2aceacb43f284df65c956daf3046b85"},"id":"ce541d8d-c79b-40f8-9f8c-20f59616faba","version":3}`
+	keystorePBKDF2      = `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+)
+
+func TestDecryptKeystore(t *testing.T) {
+	key, err := DecryptKeystore([]byte(keystoreLightScrypt), "")
+	if err != nil {
+		t.Fatalf("scrypt: %v", err)
+	}
+	if got := crypto.PubkeyToAddress(key.PublicKey); got != common.HexToAddress("45dea0fb0bba44f4fcf290bba71fd57d7117cbb8") {
+		t.Errorf("scrypt: address %s", got.Hex())
+	}
+
+	key, err = DecryptKeystore([]byte(keystorePBKDF2), "testpassword")
+	if err != nil {
+		t.Fatalf("pbkdf2: %v", err)
+	}
+	if got := common.Bytes2Hex(crypto.FromECDSA(key)); got != "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d" {
+		t.Errorf("pbkdf2: key %s", got)
+	}
+
+	if _, err := DecryptKeystore([]byte(keystoreLightScrypt), "wrong"); !errors.Is(err, ErrKeystorePassword) {
+		t.Errorf("wrong password: %v, want ErrKeystorePassword", err)
+	}
+	for name, tt := range map[string]struct {
+		json string
+		want string
+	}{
+		"not json":   {`0x01`, "not a keystore JSON file"},
+		"version":    {strings.Replace(keystoreLightScrypt, `"version":3`, `"version":1`, 1), "version 1"},
+		"cipher":     {strings.Replace(keystoreLightScrypt, "aes-128-ctr", "aes-128-cbc", 1), `cipher "aes-128-cbc"`},
+		"kdf":        {strings.Replace(keystoreLightScrypt, `"scrypt"`, `"argon2"`, 1), `kdf "argon2"`},
+		"other file": {strings.Replace(keystoreLightScrypt, "45dea0fb", "00000000", 1), "but the file names"},
+	} {
+		if _, err := DecryptKeystore([]byte(tt.json), ""); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: %v, want %q", name, err, tt.want)
+		}
+	}
+}
+
+func TestNewSignerFromConfig_Keystore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "UTC--2024-01-01T00-00-00.000000000Z--45dea0fb0bba44f4fcf290bba71fd57d7117cbb8")
+	if err := os.WriteFile(path, []byte(keystoreLightScrypt), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSignerFromConfig(&SignerConfig{KeystoreFile: path}, NewDomainManager())
+	if err != nil {
+		t.Fatalf("NewSignerFromConfig failed: %v", err)
+	}
+	if s.GetAddress() != common.HexToAddress("45dea0fb0bba44f4fcf290bba71fd57d7117cbb8") {
+		t.Errorf("address %s", s.GetAddress().Hex())
+	}
+
+	// Failures name the file and the cause
+	_, err = NewSignerFromConfig(&SignerConfig{KeystoreFile: path, KeystorePassword: "wrong"}, NewDomainManager())
+	if !errors.Is(err, ErrKeystorePassword) || !strings.Contains(err.Error(), path) {
+		t.Errorf("wrong password: %v", err)
+	}
+	if _, err := NewSignerFromConfig(&SignerConfig{KeystoreFile: path + ".missing"}, NewDomainManager()); err == nil || !strings.Contains(err.Error(), "failed to read keystore file") {
+		t.Errorf("missing file: %v", err)
+	}
+}
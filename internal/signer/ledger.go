@@ -0,0 +1,70 @@
+//go:build ledger
+
+package signer
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/usbwallet"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LedgerSupported reports whether the binary was built with Ledger support
+const LedgerSupported = true
+
+// ledgerWallet is the account at one derivation path of a Ledger device
+type ledgerWallet struct {
+	hub     *usbwallet.Hub
+	wallet  accounts.Wallet
+	account accounts.Account
+}
+
+// OpenLedger opens the first Ledger device and derives the account at derivationPath
+// The Ethereum app must be open on the device, and EIP-712 signing needs app version 1.5.0 or later.
+func OpenLedger(derivationPath string) (HardwareWallet, error) {
+	path, err := accounts.ParseDerivationPath(derivationPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid derivation path %q: %w", derivationPath, err)
+	}
+	hub, err := usbwallet.NewLedgerHub()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Ledger hub: %w", err)
+	}
+	wallets := hub.Wallets()
+	if len(wallets) == 0 {
+		return nil, fmt.Errorf("no Ledger device found")
+	}
+	wallet := wallets[0]
+	if err := wallet.Open(""); err != nil {
+		return nil, fmt.Errorf("failed to open Ledger %s: %w", wallet.URL(), err)
+	}
+	account, err := wallet.Derive(path, true)
+	if err != nil {
+		_ = wallet.Close()
+		return nil, fmt.Errorf("failed to derive %s on Ledger: %w", derivationPath, err)
+	}
+	return &ledgerWallet{hub: hub, wallet: wallet, account: account}, nil
+}
+
+// Address returns the address of the derived account
+func (l *ledgerWallet) Address() common.Address {
+	return l.account.Address
+}
+
+// SignTypedData sends the EIP-712 message to the device and waits for the confirmation
+func (l *ledgerWallet) SignTypedData(message []byte) ([]byte, error) {
+	return l.wallet.SignData(l.account, accounts.MimetypeTypedData, message)
+}
+
+// Close closes the device and releases the hub; closing twice is a no-op
+// usbwallet.Hub has no Close of its own: it holds no handle besides the wallets it opened and
+// runs no goroutine unless subscribed to, so the hub is released with the wallet it opened.
+func (l *ledgerWallet) Close() error {
+	if l.hub == nil {
+		return nil
+	}
+	err := l.wallet.Close()
+	l.hub = nil
+	return err
+}
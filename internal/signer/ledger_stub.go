@@ -0,0 +1,13 @@
+//go:build !ledger
+
+package signer
+
+import "fmt"
+
+// LedgerSupported reports whether the binary was built with Ledger support
+const LedgerSupported = false
+
+// OpenLedger fails: Ledger support needs the USB HID driver, built in with the ledger build tag
+func OpenLedger(string) (HardwareWallet, error) {
+	return nil, fmt.Errorf("built without Ledger support, rebuild with -tags ledger")
+}
@@ -0,0 +1,97 @@
+package signer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ChainKey is the signing key of one chain in a MultiSigner
+type ChainKey struct {
+	ChainID uint64
+	Signer  Signer
+}
+
+// MultiSigner signs quotes of each chain with the key configured for it
+// Chains without a key of their own use the default key. The default key stays the MM identity:
+// GetAddress returns it, for the mm_id of messages, and the order's signer field names the chain
+// key, so each RFQ manager must authorize the key of its chain.
+type MultiSigner struct {
+	def    Signer
+	chains map[uint64]Signer
+}
+
+// NewMultiSigner creates a signer routing chains to their keys and other chains to def
+func NewMultiSigner(def Signer, chains map[uint64]Signer) *MultiSigner {
+	m := &MultiSigner{def: def, chains: make(map[uint64]Signer, len(chains))}
+	for chainID, s := range chains {
+		m.chains[chainID] = s
+	}
+	return m
+}
+
+// GetAddress returns the address of the default key, the MM identity
+func (m *MultiSigner) GetAddress() common.Address {
+	return m.def.GetAddress()
+}
+
+// AddressFor returns the address that signs quotes of chainID
+func (m *MultiSigner) AddressFor(chainID uint64) common.Address {
+	return m.ForChain(chainID).GetAddress()
+}
+
+// ForChain returns the key of chainID, the default key for chains without one
+func (m *MultiSigner) ForChain(chainID uint64) Signer {
+	if s, ok := m.chains[chainID]; ok {
+		return s
+	}
+	return m.def
+}
+
+// Default returns the default key
+func (m *MultiSigner) Default() Signer {
+	return m.def
+}
+
+// Chains returns the chain keys, by chain ID
+func (m *MultiSigner) Chains() []ChainKey {
+	out := make([]ChainKey, 0, len(m.chains))
+	for chainID, s := range m.chains {
+		out = append(out, ChainKey{ChainID: chainID, Signer: s})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ChainID < out[j].ChainID })
+	return out
+}
+
+// Assign returns the key of chainID, or the key it assigns when it holds several
+func (m *MultiSigner) Assign(chainID uint64, pairID string) (Signer, error) {
+	s := m.ForChain(chainID)
+	if assigner, ok := s.(KeyAssigner); ok {
+		return assigner.Assign(chainID, pairID)
+	}
+	return s, nil
+}
+
+// SignMMQuote signs with the key of chainID
+func (m *MultiSigner) SignMMQuote(chainID uint64, quote *MMQuote) ([]byte, error) {
+	return m.ForChain(chainID).SignMMQuote(chainID, quote)
+}
+
+// SignMMQuoteClamped signs with the key of chainID, clamping the deadline when the key supports it
+func (m *MultiSigner) SignMMQuoteClamped(chainID uint64, quote *MMQuote) ([]byte, error) {
+	s := m.ForChain(chainID)
+	if clamper, ok := s.(DeadlineClamper); ok {
+		return clamper.SignMMQuoteClamped(chainID, quote)
+	}
+	return s.SignMMQuote(chainID, quote)
+}
+
+// SignTypedData signs with the default key, the MM identity
+func (m *MultiSigner) SignTypedData(domainSeparator []byte, structHash common.Hash) ([]byte, error) {
+	s, ok := m.def.(TypedDataSigner)
+	if !ok {
+		return nil, fmt.Errorf("default key cannot sign typed data")
+	}
+	return s.SignTypedData(domainSeparator, structHash)
+}
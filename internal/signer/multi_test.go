@@ -0,0 +1,54 @@
+package signer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestMultiSigner_RoutesChains(t *testing.T) {
+	domains := NewDomainManager()
+	domains.AddPoolDomain(56, common.HexToAddress("0x1111111111111111111111111111111111111111"))
+	domains.AddPoolDomain(8453, common.HexToAddress("0x2222222222222222222222222222222222222222"))
+	var keys []Signer
+	for i := 1; i <= 2; i++ {
+		s, err := NewSignerFromHex(fmt.Sprintf("0x%064x", i), domains)
+		if err != nil {
+			t.Fatalf("NewSignerFromHex failed: %v", err)
+		}
+		keys = append(keys, s)
+	}
+	m := NewMultiSigner(keys[0], map[uint64]Signer{8453: keys[1]})
+
+	if m.GetAddress() != keys[0].GetAddress() {
+		t.Errorf("GetAddress() = %s, want the default key", m.GetAddress().Hex())
+	}
+	for chainID, want := range map[uint64]Signer{56: keys[0], 8453: keys[1]} {
+		if got := m.AddressFor(chainID); got != want.GetAddress() {
+			t.Errorf("AddressFor(%d) = %s, want %s", chainID, got.Hex(), want.GetAddress().Hex())
+		}
+		if s, _ := m.Assign(chainID, "WBNB-USDT"); s != want {
+			t.Errorf("Assign(%d) = %s, want %s", chainID, s.GetAddress().Hex(), want.GetAddress().Hex())
+		}
+		// The quote of a chain recovers to its key
+		if err := CheckSigner(want, domains, chainID); err != nil {
+			t.Errorf("chain %d: %v", chainID, err)
+		}
+		quote := canaryQuote(domains.GetPoolDomain(chainID))
+		got, err := m.SignMMQuote(chainID, quote)
+		if err != nil {
+			t.Fatalf("SignMMQuote(%d) failed: %v", chainID, err)
+		}
+		if direct, _ := want.SignMMQuote(chainID, quote); common.Bytes2Hex(got) != common.Bytes2Hex(direct) {
+			t.Errorf("chain %d signed with the wrong key", chainID)
+		}
+	}
+
+	// Behind a failover, the chain key signs and names itself as the order signer
+	f := NewFailover(m, keys[0], FailoverConfig{})
+	s, err := f.Assign(8453, "")
+	if err != nil || s.GetAddress() != keys[1].GetAddress() {
+		t.Errorf("failover Assign(8453) = %v, %v; want the chain key", s, err)
+	}
+}
@@ -0,0 +1,97 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TypedDataSigner signs arbitrary EIP-712 structs
+// Implemented by the signers of NewSigner; used to sign messages other than quotes
+type TypedDataSigner interface {
+	// SignTypedData signs keccak256("\x19\x01" || domainSeparator || structHash), v = 27/28
+	SignTypedData(domainSeparator []byte, structHash common.Hash) ([]byte, error)
+	// GetAddress returns the signer address
+	GetAddress() common.Address
+}
+
+// Permit is an ERC-2612 approval of Spender to move Value of the owner's tokens
+type Permit struct {
+	Owner    common.Address // Token holder, must be the signer
+	Spender  common.Address // e.g. the vault or RFQ Manager settling quotes
+	Value    *big.Int       // Allowance (native decimals)
+	Nonce    *big.Int       // Current nonces(owner) of the token (see PermitNonce)
+	Deadline *big.Int       // Expiration timestamp (Unix seconds)
+}
+
+// PermitTypeHash is the keccak256 hash of the ERC-2612 Permit type
+var PermitTypeHash = crypto.Keccak256Hash([]byte(
+	"Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)"))
+
+// noncesSelector is the selector of ERC-2612 nonces(address)
+var noncesSelector = crypto.Keccak256([]byte("nonces(address)"))[:4]
+
+// SignPermit signs permit for the token of domain
+// domain is the token's own EIP-712 domain: its name, version, chain and address, which
+// differ per token (USDC uses version "2", for example)
+func SignPermit(s TypedDataSigner, domain *EIP712Domain, permit *Permit) ([]byte, error) {
+	if permit.Owner != s.GetAddress() {
+		return nil, fmt.Errorf("permit owner %s is not the signer %s", permit.Owner.Hex(), s.GetAddress().Hex())
+	}
+	structHash, err := hashPermit(permit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash Permit: %w", err)
+	}
+	return s.SignTypedData(domain.DomainSeparator(), structHash)
+}
+
+// hashPermit calculates the struct hash of a Permit
+func hashPermit(permit *Permit) (common.Hash, error) {
+	h := getHasher()
+	buf := h.buf[:6*32]
+	copy(buf[0:32], PermitTypeHash[:])
+	putAddress(buf[32:64], permit.Owner)
+	putAddress(buf[64:96], permit.Spender)
+	uints := [...]struct {
+		name  string
+		value *big.Int
+	}{
+		{"value", permit.Value},
+		{"nonce", permit.Nonce},
+		{"deadline", permit.Deadline},
+	}
+	for i, u := range uints {
+		if err := putUint256(buf[96+32*i:128+32*i], u.value); err != nil {
+			hasherPool.Put(h)
+			return common.Hash{}, fmt.Errorf("%s: %w", u.name, err)
+		}
+	}
+	return h.sum(buf), nil
+}
+
+// SplitSignature splits a 65-byte signature into the v, r, s arguments of permit()
+func SplitSignature(sig []byte) (v uint8, r, s common.Hash, err error) {
+	if len(sig) != 65 {
+		return 0, common.Hash{}, common.Hash{}, fmt.Errorf("signature is %d bytes, want 65", len(sig))
+	}
+	return sig[64], common.BytesToHash(sig[0:32]), common.BytesToHash(sig[32:64]), nil
+}
+
+// PermitNonce reads the current ERC-2612 nonce of owner from token
+func PermitNonce(ctx context.Context, client RPCClient, token, owner common.Address) (*big.Int, error) {
+	data := make([]byte, 36)
+	copy(data, noncesSelector)
+	putAddress(data[4:], owner)
+
+	out, err := client.CallContract(ctx, token, data)
+	if err != nil {
+		return nil, fmt.Errorf("nonces(%s) failed: %w", owner.Hex(), err)
+	}
+	if len(out) != 32 {
+		return nil, fmt.Errorf("nonces(%s): invalid result of %d bytes", owner.Hex(), len(out))
+	}
+	return new(big.Int).SetBytes(out), nil
+}
@@ -0,0 +1,104 @@
+package signer
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// abiHashPermit is the abi.Arguments reference encoding of hashPermit
+func abiHashPermit(t *testing.T, permit *Permit) common.Hash {
+	t.Helper()
+
+	bytes32Ty, _ := abi.NewType("bytes32", "", nil)
+	addressTy, _ := abi.NewType("address", "", nil)
+	uint256Ty, _ := abi.NewType("uint256", "", nil)
+	args := abi.Arguments{{Type: bytes32Ty}, {Type: addressTy}, {Type: addressTy}, {Type: uint256Ty}, {Type: uint256Ty}, {Type: uint256Ty}}
+	encoded, err := args.Pack(PermitTypeHash, permit.Owner, permit.Spender, permit.Value, permit.Nonce, permit.Deadline)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	return crypto.Keccak256Hash(encoded)
+}
+
+func TestSignPermit(t *testing.T) {
+	s, err := NewSignerFromHex("0x0000000000000000000000000000000000000000000000000000000000000001", NewDomainManager())
+	if err != nil {
+		t.Fatalf("NewSignerFromHex failed: %v", err)
+	}
+	typed := s.(TypedDataSigner)
+	usdc := &EIP712Domain{
+		Name:              "USD Coin",
+		Version:           "2",
+		ChainID:           big.NewInt(8453),
+		VerifyingContract: common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"),
+	}
+	permit := &Permit{
+		Owner:    s.GetAddress(),
+		Spender:  common.HexToAddress("0x2F46232bC664356BB38AA556Fe1aC939B2Cc7c74"),
+		Value:    big.NewInt(1_000_000_000),
+		Nonce:    big.NewInt(3),
+		Deadline: big.NewInt(1735084800),
+	}
+
+	structHash, err := hashPermit(permit)
+	if err != nil {
+		t.Fatalf("hashPermit failed: %v", err)
+	}
+	if want := abiHashPermit(t, permit); structHash != want {
+		t.Fatalf("hashPermit = %s, want %s", structHash.Hex(), want.Hex())
+	}
+
+	sig, err := SignPermit(typed, usdc, permit)
+	if err != nil {
+		t.Fatalf("SignPermit failed: %v", err)
+	}
+	v, r, sv, err := SplitSignature(sig)
+	if err != nil {
+		t.Fatalf("SplitSignature failed: %v", err)
+	}
+	if v != 27 && v != 28 {
+		t.Errorf("v = %d, want 27 or 28", v)
+	}
+
+	// Recover the owner from the digest the token computes
+	digest := crypto.Keccak256(append(append([]byte{0x19, 0x01}, usdc.DomainSeparator()...), structHash[:]...))
+	recoverable := append(append(r.Bytes(), sv.Bytes()...), v-27)
+	pub, err := crypto.SigToPub(digest, recoverable)
+	if err != nil {
+		t.Fatalf("SigToPub failed: %v", err)
+	}
+	if got := crypto.PubkeyToAddress(*pub); got != permit.Owner {
+		t.Errorf("recovered %s, want owner %s", got.Hex(), permit.Owner.Hex())
+	}
+
+	permit.Owner = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	if _, err := SignPermit(typed, usdc, permit); err == nil || !strings.Contains(err.Error(), "not the signer") {
+		t.Errorf("SignPermit() = %v, want an owner error", err)
+	}
+}
+
+// nonceRPC answers nonces(address) with the owner's nonce
+type nonceRPC map[common.Address]int64
+
+func (n nonceRPC) ChainID(ctx context.Context) (uint64, error) { return 56, nil }
+
+func (n nonceRPC) CallContract(ctx context.Context, to common.Address, data []byte) ([]byte, error) {
+	return common.BigToHash(big.NewInt(n[common.BytesToAddress(data[4:36])])).Bytes(), nil
+}
+
+func TestPermitNonce(t *testing.T) {
+	owner := common.HexToAddress("0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf")
+	nonce, err := PermitNonce(context.Background(), nonceRPC{owner: 7}, common.Address{}, owner)
+	if err != nil {
+		t.Fatalf("PermitNonce failed: %v", err)
+	}
+	if nonce.Int64() != 7 {
+		t.Errorf("PermitNonce = %v, want 7", nonce)
+	}
+}
@@ -0,0 +1,189 @@
+package signer
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Key assignment policies of a Pool
+const (
+	PolicyRoundRobin = "roundRobin" // Rotate over the active keys
+	PolicyPerPair    = "perPair"    // Key assigned to the pair, round-robin for other pairs
+	PolicyPerChain   = "perChain"   // Key assigned to the chain, round-robin for other chains
+	PolicyFailover   = "failover"   // First active key, in pool order
+)
+
+// PrimaryKeyName names the primary signer in a Pool
+const PrimaryKeyName = "primary"
+
+// KeyAssigner is implemented by signers that sign with one of several keys
+// The quote handler signs with the assigned key and reports its address as the order signer
+type KeyAssigner interface {
+	// Assign returns the signer of a quote for pairID on chainID
+	Assign(chainID uint64, pairID string) (Signer, error)
+}
+
+// PoolKey is a named key of a Pool
+type PoolKey struct {
+	Name   string
+	Signer Signer
+}
+
+// PoolAssignment configures how a Pool assigns keys
+type PoolAssignment struct {
+	Policy string            // One of the Policy constants (default: round-robin)
+	Pairs  map[string]string // Pair ID -> key name (PolicyPerPair)
+	Chains map[uint64]string // Chain ID -> key name (PolicyPerChain)
+}
+
+// PoolKeyStatus is the state of a Pool key
+type PoolKeyStatus struct {
+	Name    string
+	Address common.Address
+	Drained bool
+	Signed  uint64 // Quotes assigned to the key
+}
+
+// poolKey is a key with its rotation state
+type poolKey struct {
+	PoolKey
+	drained atomic.Bool
+	signed  atomic.Uint64
+}
+
+// Pool spreads quote signing over several keys
+// The primary key is the MM identity: GetAddress returns it, for the mm_id of messages.
+// Drained keys are taken out of rotation at runtime; assignments to a drained key fall
+// back to round-robin over the active keys.
+type Pool struct {
+	keys       []*poolKey
+	byName     map[string]*poolKey
+	assignment PoolAssignment
+	next       atomic.Uint64
+}
+
+// NewPool creates a pool of primary and keys; names must be unique
+func NewPool(primary Signer, keys []PoolKey, assignment PoolAssignment) (*Pool, error) {
+	p := &Pool{
+		byName:     make(map[string]*poolKey, len(keys)+1),
+		assignment: assignment,
+	}
+	for _, key := range append([]PoolKey{{Name: PrimaryKeyName, Signer: primary}}, keys...) {
+		if _, ok := p.byName[key.Name]; ok {
+			return nil, fmt.Errorf("duplicate signing key %q", key.Name)
+		}
+		k := &poolKey{PoolKey: key}
+		p.keys = append(p.keys, k)
+		p.byName[key.Name] = k
+	}
+	return p, nil
+}
+
+// GetAddress returns the address of the primary key
+func (p *Pool) GetAddress() common.Address {
+	return p.keys[0].Signer.GetAddress()
+}
+
+// SignTypedData signs with the primary key, the MM identity
+func (p *Pool) SignTypedData(domainSeparator []byte, structHash common.Hash) ([]byte, error) {
+	s, ok := p.keys[0].Signer.(TypedDataSigner)
+	if !ok {
+		return nil, fmt.Errorf("primary key cannot sign typed data")
+	}
+	return s.SignTypedData(domainSeparator, structHash)
+}
+
+// SignMMQuote signs with the key assigned to the chain
+func (p *Pool) SignMMQuote(chainID uint64, quote *MMQuote) ([]byte, error) {
+	s, err := p.Assign(chainID, "")
+	if err != nil {
+		return nil, err
+	}
+	return s.SignMMQuote(chainID, quote)
+}
+
+// Assign returns the key of a quote following the assignment policy
+func (p *Pool) Assign(chainID uint64, pairID string) (Signer, error) {
+	var k *poolKey
+	switch p.assignment.Policy {
+	case PolicyPerPair:
+		k = p.active(p.assignment.Pairs[pairID])
+	case PolicyPerChain:
+		k = p.active(p.assignment.Chains[chainID])
+	case PolicyFailover:
+		for _, candidate := range p.keys {
+			if !candidate.drained.Load() {
+				k = candidate
+				break
+			}
+		}
+	}
+	if k == nil {
+		k = p.roundRobin()
+	}
+	if k == nil {
+		return nil, fmt.Errorf("all %d signing keys are drained", len(p.keys))
+	}
+	k.signed.Add(1)
+	// A key that is itself several keys (a Failover) picks the one that signs
+	if assigner, ok := k.Signer.(KeyAssigner); ok {
+		return assigner.Assign(chainID, pairID)
+	}
+	return k.Signer, nil
+}
+
+// active returns the named key unless it is unknown or drained
+func (p *Pool) active(name string) *poolKey {
+	k, ok := p.byName[name]
+	if !ok || k.drained.Load() {
+		return nil
+	}
+	return k
+}
+
+// roundRobin returns the next active key, nil if all are drained
+func (p *Pool) roundRobin() *poolKey {
+	start := p.next.Add(1) - 1
+	for i := range p.keys {
+		k := p.keys[(start+uint64(i))%uint64(len(p.keys))]
+		if !k.drained.Load() {
+			return k
+		}
+	}
+	return nil
+}
+
+// SetDrained takes the named key out of rotation (drained) or puts it back
+func (p *Pool) SetDrained(name string, drained bool) error {
+	k, ok := p.byName[name]
+	if !ok {
+		return fmt.Errorf("unknown signing key %q", name)
+	}
+	k.drained.Store(drained)
+	return nil
+}
+
+// Signers returns every key of the pool, drained or not, in pool order
+func (p *Pool) Signers() []PoolKey {
+	out := make([]PoolKey, len(p.keys))
+	for i, k := range p.keys {
+		out[i] = k.PoolKey
+	}
+	return out
+}
+
+// Keys returns the state of every key, in pool order
+func (p *Pool) Keys() []PoolKeyStatus {
+	out := make([]PoolKeyStatus, len(p.keys))
+	for i, k := range p.keys {
+		out[i] = PoolKeyStatus{
+			Name:    k.Name,
+			Address: k.Signer.GetAddress(),
+			Drained: k.drained.Load(),
+			Signed:  k.signed.Load(),
+		}
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+package signer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// newTestPool creates a pool of the primary key and keys "a" and "b"
+func newTestPool(t *testing.T, assignment PoolAssignment) (*Pool, map[string]common.Address) {
+	t.Helper()
+	addrs := make(map[string]common.Address)
+	var keys []PoolKey
+	var primary Signer
+	for i, name := range []string{PrimaryKeyName, "a", "b"} {
+		s, err := NewSignerFromHex(fmt.Sprintf("0x%064x", i+1), NewDomainManager())
+		if err != nil {
+			t.Fatalf("NewSignerFromHex failed: %v", err)
+		}
+		addrs[name] = s.GetAddress()
+		if name == PrimaryKeyName {
+			primary = s
+			continue
+		}
+		keys = append(keys, PoolKey{Name: name, Signer: s})
+	}
+	pool, err := NewPool(primary, keys, assignment)
+	if err != nil {
+		t.Fatalf("NewPool failed: %v", err)
+	}
+	return pool, addrs
+}
+
+// assigned returns the address of the key assigned to a quote
+func assigned(t *testing.T, pool *Pool, chainID uint64, pairID string) common.Address {
+	t.Helper()
+	s, err := pool.Assign(chainID, pairID)
+	if err != nil {
+		t.Fatalf("Assign failed: %v", err)
+	}
+	return s.GetAddress()
+}
+
+func TestPool_Policies(t *testing.T) {
+	t.Run("round robin skips drained keys", func(t *testing.T) {
+		pool, addrs := newTestPool(t, PoolAssignment{Policy: PolicyRoundRobin})
+		if err := pool.SetDrained("a", true); err != nil {
+			t.Fatalf("SetDrained failed: %v", err)
+		}
+		want := []string{PrimaryKeyName, "b", "b", PrimaryKeyName}
+		for i, name := range want {
+			if got := assigned(t, pool, 56, ""); got != addrs[name] {
+				t.Errorf("assignment %d = %s, want %s", i, got.Hex(), name)
+			}
+		}
+	})
+
+	t.Run("per pair", func(t *testing.T) {
+		pool, addrs := newTestPool(t, PoolAssignment{Policy: PolicyPerPair, Pairs: map[string]string{"WBNB-USDT": "b"}})
+		if got := assigned(t, pool, 56, "WBNB-USDT"); got != addrs["b"] {
+			t.Errorf("WBNB-USDT assigned %s, want b", got.Hex())
+		}
+		// A drained assignment falls back to rotation
+		_ = pool.SetDrained("b", true)
+		if got := assigned(t, pool, 56, "WBNB-USDT"); got == addrs["b"] {
+			t.Error("WBNB-USDT assigned the drained key b")
+		}
+	})
+
+	t.Run("per chain", func(t *testing.T) {
+		pool, addrs := newTestPool(t, PoolAssignment{Policy: PolicyPerChain, Chains: map[uint64]string{8453: "a"}})
+		if got := assigned(t, pool, 8453, "WETH-USDC"); got != addrs["a"] {
+			t.Errorf("chain 8453 assigned %s, want a", got.Hex())
+		}
+	})
+
+	t.Run("failover", func(t *testing.T) {
+		pool, addrs := newTestPool(t, PoolAssignment{Policy: PolicyFailover})
+		if got := assigned(t, pool, 56, ""); got != addrs[PrimaryKeyName] {
+			t.Errorf("assigned %s, want primary", got.Hex())
+		}
+		_ = pool.SetDrained(PrimaryKeyName, true)
+		if got := assigned(t, pool, 56, ""); got != addrs["a"] {
+			t.Errorf("assigned %s after draining primary, want a", got.Hex())
+		}
+		// The identity stays the primary key
+		if pool.GetAddress() != addrs[PrimaryKeyName] {
+			t.Errorf("GetAddress() = %s, want primary", pool.GetAddress().Hex())
+		}
+	})
+}
+
+func TestPool_AllDrained(t *testing.T) {
+	pool, _ := newTestPool(t, PoolAssignment{})
+	for _, key := range pool.Keys() {
+		_ = pool.SetDrained(key.Name, true)
+	}
+	if _, err := pool.Assign(56, ""); err == nil {
+		t.Error("Assign() = nil error, want an error with every key drained")
+	}
+	if err := pool.SetDrained("unknown", true); err == nil {
+		t.Error("SetDrained(unknown) = nil, want an error")
+	}
+}
+
+func TestPool_CountsAssignments(t *testing.T) {
+	pool, _ := newTestPool(t, PoolAssignment{Policy: PolicyRoundRobin})
+	for i := 0; i < 6; i++ {
+		assigned(t, pool, 56, "")
+	}
+	for _, key := range pool.Keys() {
+		if key.Signed != 2 {
+			t.Errorf("key %s signed %d, want 2", key.Name, key.Signed)
+		}
+	}
+}
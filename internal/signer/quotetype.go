@@ -0,0 +1,144 @@
+package signer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// quoteField is an MMQuote field that a struct version can encode
+type quoteField int
+
+const (
+	fieldRFQManager quoteField = iota
+	fieldFrom
+	fieldTo
+	fieldInputToken
+	fieldOutputToken
+	fieldAmountIn
+	fieldAmountOut
+	fieldDeadline
+	fieldNonce
+	fieldExtraDataHash
+)
+
+// quoteMembers maps EIP-712 member names to MMQuote fields and their Solidity types
+var quoteMembers = map[string]struct {
+	field quoteField
+	typ   string
+}{
+	"rfq_manager":   {fieldRFQManager, "address"},
+	"rfqManager":    {fieldRFQManager, "address"},
+	"from":          {fieldFrom, "address"},
+	"to":            {fieldTo, "address"},
+	"inputToken":    {fieldInputToken, "address"},
+	"outputToken":   {fieldOutputToken, "address"},
+	"amountIn":      {fieldAmountIn, "uint256"},
+	"amountOut":     {fieldAmountOut, "uint256"},
+	"deadline":      {fieldDeadline, "uint256"},
+	"nonce":         {fieldNonce, "uint256"},
+	"extraDataHash": {fieldExtraDataHash, "bytes32"},
+}
+
+// quoteTypePattern matches an EIP-712 encoded struct type: Name(type member,...)
+var quoteTypePattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\((.*)\)$`)
+
+// QuoteType is a version of the MMQuote struct: its EIP-712 type and the layout of its fields
+// RFQ Manager upgrades may change the layout; each domain is signed with the version of its contract
+type QuoteType struct {
+	Type     string      // EIP-712 encoded type, e.g. MMQuoteType
+	TypeHash common.Hash // keccak256(Type)
+
+	name   string // Struct name, e.g. MMQuote
+	fields []quoteField
+	names  []string // Member names
+}
+
+// DefaultQuoteType is the current MMQuote layout (MMQuoteType)
+var DefaultQuoteType = func() *QuoteType {
+	t, err := ParseQuoteType(MMQuoteType)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}()
+
+// ParseQuoteType parses an EIP-712 encoded MMQuote type
+// Members must be MMQuote fields with their usual Solidity types; extraData is signed as extraDataHash
+func ParseQuoteType(encoded string) (*QuoteType, error) {
+	m := quoteTypePattern.FindStringSubmatch(encoded)
+	if m == nil {
+		return nil, fmt.Errorf("invalid struct type %q", encoded)
+	}
+	if m[2] == "" {
+		return nil, fmt.Errorf("struct type %s has no members", m[1])
+	}
+
+	t := &QuoteType{Type: encoded, TypeHash: crypto.Keccak256Hash([]byte(encoded)), name: m[1]}
+	seen := make(map[quoteField]bool)
+	for _, member := range strings.Split(m[2], ",") {
+		parts := strings.Split(member, " ")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid member %q", member)
+		}
+		typ, name := parts[0], parts[1]
+		known, ok := quoteMembers[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown member %q", name)
+		}
+		if typ != known.typ {
+			return nil, fmt.Errorf("member %s has type %s, want %s", name, typ, known.typ)
+		}
+		if seen[known.field] {
+			return nil, fmt.Errorf("duplicate member %q", name)
+		}
+		seen[known.field] = true
+		t.fields = append(t.fields, known.field)
+		t.names = append(t.names, name)
+	}
+	return t, nil
+}
+
+// hash calculates the struct hash of quote in this layout
+// The encoding is written into a pooled buffer; only the extraData hash touches caller memory
+func (t *QuoteType) hash(quote *MMQuote) (common.Hash, error) {
+	extraDataHash := getHasher().sum(quote.ExtraData)
+
+	h := getHasher()
+	buf := h.buf[:32*(1+len(t.fields))]
+	copy(buf[0:32], t.TypeHash[:])
+	for i, field := range t.fields {
+		word := buf[32+32*i : 64+32*i]
+		var err error
+		switch field {
+		case fieldRFQManager:
+			putAddress(word, quote.RFQManager)
+		case fieldFrom:
+			putAddress(word, quote.From)
+		case fieldTo:
+			putAddress(word, quote.To)
+		case fieldInputToken:
+			putAddress(word, quote.InputToken)
+		case fieldOutputToken:
+			putAddress(word, quote.OutputToken)
+		case fieldAmountIn:
+			err = putUint256(word, quote.AmountIn)
+		case fieldAmountOut:
+			err = putUint256(word, quote.AmountOut)
+		case fieldDeadline:
+			err = putUint256(word, quote.Deadline)
+		case fieldNonce:
+			err = putUint256(word, quote.Nonce)
+		case fieldExtraDataHash:
+			copy(word, extraDataHash[:])
+		}
+		if err != nil {
+			hasherPool.Put(h)
+			return common.Hash{}, fmt.Errorf("%s: %w", t.names[i], err)
+		}
+	}
+	return h.sum(buf), nil
+}
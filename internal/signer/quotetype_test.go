@@ -0,0 +1,97 @@
+package signer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// legacyQuoteType is a layout without the RFQ Manager member and with the nonce first
+const legacyQuoteType = "MMQuote(uint256 nonce,address from,address to,address inputToken,address outputToken," +
+	"uint256 amountIn,uint256 amountOut,uint256 deadline,bytes32 extraDataHash)"
+
+func TestQuoteType_Hash(t *testing.T) {
+	quote := benchQuote()
+	if got, _ := DefaultQuoteType.hash(quote); got != abiHashMMQuote(t, quote) {
+		t.Errorf("DefaultQuoteType hash = %s, want the MMQuoteType encoding", got.Hex())
+	}
+	if DefaultQuoteType.TypeHash != MMQuoteTypeHash {
+		t.Errorf("DefaultQuoteType.TypeHash = %s, want MMQuoteTypeHash", DefaultQuoteType.TypeHash.Hex())
+	}
+
+	legacy, err := ParseQuoteType(legacyQuoteType)
+	if err != nil {
+		t.Fatalf("ParseQuoteType failed: %v", err)
+	}
+	bytes32Ty, _ := abi.NewType("bytes32", "", nil)
+	addressTy, _ := abi.NewType("address", "", nil)
+	uint256Ty, _ := abi.NewType("uint256", "", nil)
+	args := abi.Arguments{
+		{Type: bytes32Ty}, {Type: uint256Ty}, {Type: addressTy}, {Type: addressTy}, {Type: addressTy}, {Type: addressTy},
+		{Type: uint256Ty}, {Type: uint256Ty}, {Type: uint256Ty}, {Type: bytes32Ty},
+	}
+	encoded, err := args.Pack(crypto.Keccak256Hash([]byte(legacyQuoteType)), quote.Nonce, quote.From, quote.To,
+		quote.InputToken, quote.OutputToken, quote.AmountIn, quote.AmountOut, quote.Deadline, crypto.Keccak256Hash(quote.ExtraData))
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	if got, _ := legacy.hash(quote); got != crypto.Keccak256Hash(encoded) {
+		t.Errorf("legacy hash = %s, want %s", got.Hex(), crypto.Keccak256Hash(encoded).Hex())
+	}
+}
+
+func TestParseQuoteType_Errors(t *testing.T) {
+	tests := map[string]string{
+		"MMQuote":                              "invalid struct type",
+		"MMQuote()":                            "no members",
+		"MMQuote(address pool)":                "unknown member",
+		"MMQuote(uint256 from)":                "has type uint256",
+		"MMQuote(uint256 nonce,uint256 nonce)": "duplicate member",
+		"MMQuote(address rfq_manager,address rfqManager)": "duplicate member",
+		"MMQuote(uint256 nonce, address from)":            "invalid member",
+	}
+	for encoded, want := range tests {
+		if _, err := ParseQuoteType(encoded); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseQuoteType(%q) = %v, want error containing %q", encoded, err, want)
+		}
+	}
+}
+
+func TestDomainManager_QuoteTypePerChain(t *testing.T) {
+	dm := NewDomainManager()
+	dm.AddPoolDomainWithConfig(56, "RFQ Manager", "1", "0x28D3a265f6d40867986004029ee91F4C9532fCC5")
+	dm.AddPoolDomainWithConfig(8453, "RFQ Manager", "2", "0x28D3a265f6d40867986004029ee91F4C9532fCC5")
+	if err := dm.SetQuoteType(8453, legacyQuoteType); err != nil {
+		t.Fatalf("SetQuoteType failed: %v", err)
+	}
+	if dm.GetQuoteType(56) != DefaultQuoteType {
+		t.Error("chain 56 quote type changed, want DefaultQuoteType")
+	}
+
+	s, err := NewSignerFromHex("0x0000000000000000000000000000000000000000000000000000000000000001", dm)
+	if err != nil {
+		t.Fatalf("NewSignerFromHex failed: %v", err)
+	}
+	for _, chainID := range []uint64{56, 8453} {
+		quote := benchQuote()
+		sig, err := s.SignMMQuote(chainID, quote)
+		if err != nil {
+			t.Fatalf("SignMMQuote(%d) failed: %v", chainID, err)
+		}
+		digest, err := dm.Digest(chainID, quote)
+		if err != nil {
+			t.Fatalf("Digest(%d) failed: %v", chainID, err)
+		}
+		sig[64] -= 27
+		pub, err := crypto.SigToPub(digest[:], sig)
+		if err != nil || crypto.PubkeyToAddress(*pub) != s.GetAddress() {
+			t.Errorf("chain %d: signature does not recover to the signer over its quote type digest", chainID)
+		}
+	}
+
+	if err := dm.SetQuoteType(8453, ""); err != nil || dm.GetQuoteType(8453) != DefaultQuoteType {
+		t.Errorf("SetQuoteType(\"\") = %v, want DefaultQuoteType restored", err)
+	}
+}
@@ -0,0 +1,302 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// RemoteConfig configures a RemoteSigner
+type RemoteConfig struct {
+	URL     string         // Signing service base URL, e.g. https://signer.internal:8443
+	Address common.Address // Expected key address; zero = the address the service reports
+
+	CertFile string // Client certificate for mutual TLS
+	KeyFile  string // Private key of CertFile
+	CAFile   string // CA certificates of the service; empty = system roots
+
+	Timeout      time.Duration // Per attempt (default 2s)
+	Retries      int           // Further attempts after a failed one (0 = none)
+	RetryBackoff time.Duration // Wait before the first retry, doubled per retry (default 100ms)
+
+	MaxDeadlineHorizon time.Duration // Deadlines further ahead are refused (0 = unlimited)
+
+	Client *http.Client // nil = built from the TLS files
+}
+
+// remoteSignRequest is the JSON body posted to <url>/v1/sign
+// The service signs Digest = keccak256("\x19\x01" || DomainSeparator || StructHash) with the key
+// of Address. Quote is set for quotes so the service can apply its own policy.
+type remoteSignRequest struct {
+	Address         string       `json:"address"`
+	ChainID         uint64       `json:"chainId,omitempty"` // Set for quotes
+	Digest          string       `json:"digest"`
+	DomainSeparator string       `json:"domainSeparator"`
+	StructHash      string       `json:"structHash"`
+	Quote           *remoteQuote `json:"quote,omitempty"`
+}
+
+// remoteQuote is the quote of a remoteSignRequest, amounts in native decimals
+type remoteQuote struct {
+	RFQManager  string `json:"rfqManager"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	InputToken  string `json:"inputToken"`
+	OutputToken string `json:"outputToken"`
+	AmountIn    string `json:"amountIn"`
+	AmountOut   string `json:"amountOut"`
+	Deadline    string `json:"deadline"` // Unix seconds
+	Nonce       string `json:"nonce"`
+	ExtraData   string `json:"extraData"`
+}
+
+// remoteResponse is the service's answer to a sign request or a health probe
+type remoteResponse struct {
+	Signature string `json:"signature,omitempty"` // 65 bytes, v = 27/28 or 0/1
+	Address   string `json:"address,omitempty"`   // Health probe: the key the service signs with
+	Error     string `json:"error,omitempty"`
+}
+
+// errRemoteRefused marks answers that retrying cannot change (4xx)
+var errRemoteRefused = errors.New("refused")
+
+// RemoteSigner signs quotes with a key held by a remote signing service, over mutual TLS
+// The process never holds key material. Signatures are verified against the key address before
+// they are used, so a misrouted or compromised service cannot make the MM sign for another key.
+// Failed requests are retried on transport errors and 5xx/429 answers, each attempt bounded by
+// the timeout; refusals (other 4xx answers) are returned at once.
+type RemoteSigner struct {
+	cfg           RemoteConfig
+	client        *http.Client
+	address       common.Address
+	domainManager *DomainManager
+	now           func() time.Time
+}
+
+// NewRemoteSigner creates a remote signer and probes the service
+// The probe learns the key address when cfg.Address is zero, and fails when the service
+// signs with another key than cfg.Address.
+func NewRemoteSigner(ctx context.Context, cfg RemoteConfig, domainManager *DomainManager) (*RemoteSigner, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 2 * time.Second
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = 100 * time.Millisecond
+	}
+	client := cfg.Client
+	if client == nil {
+		tlsConfig, err := remoteTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		client = &http.Client{Transport: &http.Transport{
+			TLSClientConfig:     tlsConfig,
+			MaxIdleConnsPerHost: 16,
+			IdleConnTimeout:     90 * time.Second,
+		}}
+	}
+	s := &RemoteSigner{cfg: cfg, client: client, address: cfg.Address, domainManager: domainManager, now: time.Now}
+
+	address, err := s.probe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.address == (common.Address{}) {
+		s.address = address
+	} else if address != s.address {
+		return nil, fmt.Errorf("remote signer signs with %s, want %s", address.Hex(), s.address.Hex())
+	}
+	return s, nil
+}
+
+// remoteTLSConfig loads the client certificate and service CAs of cfg
+func remoteTLSConfig(cfg RemoteConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("remote signer client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("remote signer CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("remote signer CA: no certificates in %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// GetAddress returns the address of the remote key
+func (s *RemoteSigner) GetAddress() common.Address {
+	return s.address
+}
+
+// SignMMQuote signs an MMQuote using EIP-712 (with verifying contract domain)
+func (s *RemoteSigner) SignMMQuote(chainID uint64, quote *MMQuote) ([]byte, error) {
+	if err := checkHorizon(quote, s.cfg.MaxDeadlineHorizon, s.now()); err != nil {
+		return nil, err
+	}
+	domainSeparator, structHash, err := s.domainManager.hashQuote(chainID, quote)
+	if err != nil {
+		return nil, err
+	}
+	return s.sign(remoteSignRequest{
+		ChainID: chainID,
+		Quote: &remoteQuote{
+			RFQManager:  quote.RFQManager.Hex(),
+			From:        quote.From.Hex(),
+			To:          quote.To.Hex(),
+			InputToken:  quote.InputToken.Hex(),
+			OutputToken: quote.OutputToken.Hex(),
+			AmountIn:    quote.AmountIn.String(),
+			AmountOut:   quote.AmountOut.String(),
+			Deadline:    quote.Deadline.String(),
+			Nonce:       quote.Nonce.String(),
+			ExtraData:   hexutil.Encode(quote.ExtraData),
+		},
+	}, domainSeparator, structHash)
+}
+
+// SignMMQuoteClamped signs quote with its deadline clamped to the maximum horizon
+func (s *RemoteSigner) SignMMQuoteClamped(chainID uint64, quote *MMQuote) ([]byte, error) {
+	clampHorizon(quote, s.cfg.MaxDeadlineHorizon, s.now())
+	return s.SignMMQuote(chainID, quote)
+}
+
+// SignTypedData signs the EIP-712 digest of a struct hash under a domain separator remotely
+func (s *RemoteSigner) SignTypedData(domainSeparator []byte, structHash common.Hash) ([]byte, error) {
+	return s.sign(remoteSignRequest{}, domainSeparator, structHash)
+}
+
+// Health probes the signing service; it fails when the service is down or uses another key
+func (s *RemoteSigner) Health(ctx context.Context) error {
+	address, err := s.probe(ctx)
+	if err != nil {
+		return err
+	}
+	if address != s.address {
+		return fmt.Errorf("remote signer signs with %s, want %s", address.Hex(), s.address.Hex())
+	}
+	return nil
+}
+
+// probe returns the key address reported by GET <url>/v1/health
+func (s *RemoteSigner) probe(ctx context.Context) (common.Address, error) {
+	var out remoteResponse
+	if err := s.do(ctx, http.MethodGet, "/v1/health", nil, &out); err != nil {
+		return common.Address{}, fmt.Errorf("remote signer health probe: %w", err)
+	}
+	if !common.IsHexAddress(out.Address) {
+		return common.Address{}, fmt.Errorf("remote signer health probe: invalid address %q", out.Address)
+	}
+	return common.HexToAddress(out.Address), nil
+}
+
+// sign posts req for the digest of domainSeparator and structHash and verifies the signature
+func (s *RemoteSigner) sign(req remoteSignRequest, domainSeparator []byte, structHash common.Hash) ([]byte, error) {
+	digest := typedDataHash(domainSeparator, structHash)
+	req.Address = s.address.Hex()
+	req.Digest = digest.Hex()
+	req.DomainSeparator = hexutil.Encode(domainSeparator)
+	req.StructHash = structHash.Hex()
+
+	// Quote signing has no caller context; the attempts bound the total time
+	var out remoteResponse
+	if err := s.do(context.Background(), http.MethodPost, "/v1/sign", req, &out); err != nil {
+		return nil, fmt.Errorf("remote signer: %w", err)
+	}
+	sig, err := hexutil.Decode(out.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned an invalid signature: %w", err)
+	}
+	if len(sig) == 65 && sig[64] < 27 {
+		sig[64] += 27
+	}
+	signer, err := recoverSigner(digest, sig)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned an invalid signature: %w", err)
+	}
+	if signer != s.address {
+		return nil, fmt.Errorf("remote signer signed with %s, want %s", signer.Hex(), s.address.Hex())
+	}
+	return sig, nil
+}
+
+// do sends a request to the service, retrying failed attempts, and decodes the answer into out
+func (s *RemoteSigner) do(ctx context.Context, method, path string, body, out any) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	backoff := s.cfg.RetryBackoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = s.attempt(ctx, method, path, data, out); err == nil || errors.Is(err, errRemoteRefused) || attempt >= s.cfg.Retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// attempt sends one request within the timeout
+func (s *RemoteSigner) attempt(ctx context.Context, method, path string, data []byte, out any) error {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	var reader io.Reader
+	if data != nil {
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(s.cfg.URL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var failure remoteResponse
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&failure)
+		err := fmt.Errorf("%s %s: HTTP %d %s", method, path, resp.StatusCode, failure.Error)
+		if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			err = fmt.Errorf("%w: %w", errRemoteRefused, err)
+		}
+		return err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s %s: invalid response: %w", method, path, err)
+	}
+	return nil
+}
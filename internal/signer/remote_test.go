@@ -0,0 +1,208 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// fakeSigningService signs digests with key; status, when set, answers sign requests instead
+type fakeSigningService struct {
+	key      *ecdsa.PrivateKey
+	status   []int // Statuses of the next sign requests, 200 once used up
+	requests atomic.Int32
+	delay    time.Duration
+}
+
+func (f *fakeSigningService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/v1/health":
+		_ = json.NewEncoder(w).Encode(remoteResponse{Address: crypto.PubkeyToAddress(f.key.PublicKey).Hex()})
+	case "/v1/sign":
+		n := int(f.requests.Add(1)) - 1
+		time.Sleep(f.delay)
+		if n < len(f.status) && f.status[n] != http.StatusOK {
+			w.WriteHeader(f.status[n])
+			_ = json.NewEncoder(w).Encode(remoteResponse{Error: "policy"})
+			return
+		}
+		var req remoteSignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sig, _ := crypto.Sign(common.HexToHash(req.Digest).Bytes(), f.key)
+		_ = json.NewEncoder(w).Encode(remoteResponse{Signature: hexutil.Encode(sig)})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newRemoteTestDomains() *DomainManager {
+	domains := NewDomainManager()
+	domains.AddPoolDomain(56, common.HexToAddress("0x1111111111111111111111111111111111111111"))
+	return domains
+}
+
+func TestRemoteSigner_SignsAndRetries(t *testing.T) {
+	key, _ := crypto.HexToECDSA(fmt.Sprintf("%064x", 11))
+	service := &fakeSigningService{key: key}
+	srv := httptest.NewServer(service)
+	defer srv.Close()
+	domains := newRemoteTestDomains()
+
+	s, err := NewRemoteSigner(context.Background(), RemoteConfig{URL: srv.URL, Retries: 2, RetryBackoff: time.Millisecond}, domains)
+	if err != nil {
+		t.Fatalf("NewRemoteSigner failed: %v", err)
+	}
+	if s.GetAddress() != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("address %s not learned from the health probe", s.GetAddress().Hex())
+	}
+	if err := CheckSigner(s, domains, 56); err != nil {
+		t.Errorf("CheckSigner failed: %v", err)
+	}
+
+	// Unavailable answers are retried, refusals are not
+	service.requests.Store(0)
+	service.status = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}
+	if err := CheckSigner(s, domains, 56); err != nil || service.requests.Load() != 3 {
+		t.Errorf("after 2 retries: %v, %d requests", err, service.requests.Load())
+	}
+	service.requests.Store(0)
+	service.status = []int{http.StatusForbidden}
+	if err := CheckSigner(s, domains, 56); err == nil || !strings.Contains(err.Error(), "HTTP 403 policy") || service.requests.Load() != 1 {
+		t.Errorf("refusal: %v, %d requests", err, service.requests.Load())
+	}
+
+	// Each attempt is bounded by the timeout
+	service.status, service.delay = nil, 50*time.Millisecond
+	slow, err := NewRemoteSigner(context.Background(), RemoteConfig{URL: srv.URL, Timeout: 10 * time.Millisecond, RetryBackoff: time.Millisecond}, domains)
+	if err != nil {
+		t.Fatalf("NewRemoteSigner failed: %v", err)
+	}
+	if err := CheckSigner(slow, domains, 56); err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Errorf("slow service: %v", err)
+	}
+
+	// A service holding another key is caught at startup
+	other := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	if _, err := NewRemoteSigner(context.Background(), RemoteConfig{URL: srv.URL, Address: other}, domains); err == nil || !strings.Contains(err.Error(), "signs with") {
+		t.Errorf("other key: %v", err)
+	}
+}
+
+func TestRemoteSigner_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := newTestCA(t)
+	writePEM(t, filepath.Join(dir, "ca.pem"), "CERTIFICATE", ca.Raw)
+	clientCert, clientKey := newTestLeaf(t, ca, caKey, x509.ExtKeyUsageClientAuth)
+	writePEM(t, filepath.Join(dir, "client.pem"), "CERTIFICATE", clientCert.Raw)
+	keyDER, _ := x509.MarshalECPrivateKey(clientKey)
+	writePEM(t, filepath.Join(dir, "client-key.pem"), "EC PRIVATE KEY", keyDER)
+
+	serverCert, serverKey := newTestLeaf(t, ca, caKey, x509.ExtKeyUsageServerAuth)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	key, _ := crypto.HexToECDSA(fmt.Sprintf("%064x", 11))
+	srv := httptest.NewUnstartedServer(&fakeSigningService{key: key})
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.Raw}, PrivateKey: serverKey}},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    roots,
+	}
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0) // The rejected handshake is expected
+	srv.StartTLS()
+	defer srv.Close()
+
+	cfg := RemoteConfig{
+		URL:      srv.URL,
+		CertFile: filepath.Join(dir, "client.pem"),
+		KeyFile:  filepath.Join(dir, "client-key.pem"),
+		CAFile:   filepath.Join(dir, "ca.pem"),
+	}
+	s, err := NewRemoteSigner(context.Background(), cfg, newRemoteTestDomains())
+	if err != nil {
+		t.Fatalf("NewRemoteSigner with a client certificate failed: %v", err)
+	}
+	if err := CheckSigner(s, newRemoteTestDomains(), 56); err != nil {
+		t.Errorf("CheckSigner failed: %v", err)
+	}
+
+	cfg.CertFile, cfg.KeyFile = "", ""
+	if _, err := NewRemoteSigner(context.Background(), cfg, newRemoteTestDomains()); err == nil {
+		t.Error("service accepted a client without certificate")
+	}
+}
+
+// newTestCA creates a self-signed CA certificate
+func newTestCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return cert, key
+}
+
+// newTestLeaf creates a certificate for 127.0.0.1 issued by ca
+func newTestLeaf(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, usage x509.ExtKeyUsage) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "mm"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return cert, key
+}
+
+// writePEM writes a PEM block to path
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
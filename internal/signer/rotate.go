@@ -0,0 +1,179 @@
+package signer
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Rotation is the result of a key rotation
+type Rotation struct {
+	From common.Address `json:"from"` // Retired key
+	To   common.Address `json:"to"`   // Key signing from now on
+	Time time.Time      `json:"time"`
+
+	// Until is the deadline of the latest quote the retired key signed: the RFQ managers must
+	// keep accepting the key until then, or its quotes in flight fail to settle
+	Until time.Time `json:"until"`
+}
+
+// RetiredKey is a key replaced by a rotation whose signed quotes have not all expired
+type RetiredKey struct {
+	Address   common.Address `json:"address"`
+	RetiredAt time.Time      `json:"retiredAt"`
+	Until     time.Time      `json:"until"` // Deadline of the latest quote it signed
+}
+
+// RotatingSigner signs with a key that can be replaced without a restart
+// The current key is the MM identity: GetAddress returns it. A quote keeps the key it was
+// assigned, so quotes being signed during a rotation finish with the old key; the old key is
+// then kept as retired until the latest deadline it signed has passed.
+type RotatingSigner struct {
+	mu      sync.Mutex
+	current *rotatingKey
+	retired []*rotatingKey
+	now     func() time.Time
+}
+
+// rotatingKey is a key of a RotatingSigner, tracking the latest deadline it signed
+type rotatingKey struct {
+	Signer
+	lastDeadline atomic.Int64 // Unix seconds
+	retiredAt    time.Time
+}
+
+// NewRotatingSigner creates a signer signing with s until it is rotated
+func NewRotatingSigner(s Signer) *RotatingSigner {
+	return &RotatingSigner{current: &rotatingKey{Signer: s}, now: time.Now}
+}
+
+// GetAddress returns the address of the current key, the MM identity
+func (r *RotatingSigner) GetAddress() common.Address {
+	return r.Current().GetAddress()
+}
+
+// Current returns the current key
+func (r *RotatingSigner) Current() Signer {
+	return r.key().Signer
+}
+
+// key returns the current key
+func (r *RotatingSigner) key() *rotatingKey {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Rotate makes next the current key and retires the old one
+// Fails when next is the current key.
+func (r *RotatingSigner) Rotate(next Signer) (Rotation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old := r.current
+	if next.GetAddress() == old.GetAddress() {
+		return Rotation{}, fmt.Errorf("already signing with %s", next.GetAddress().Hex())
+	}
+	now := r.now()
+	old.retiredAt = now
+	r.retired = append(r.retired, old)
+	r.current = &rotatingKey{Signer: next}
+	return Rotation{
+		From:  old.GetAddress(),
+		To:    next.GetAddress(),
+		Time:  now,
+		Until: time.Unix(old.lastDeadline.Load(), 0),
+	}, nil
+}
+
+// Retired returns the retired keys whose latest signed quote has not expired yet
+// Keys whose quotes have all expired are dropped.
+func (r *RotatingSigner) Retired() []RetiredKey {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now().Unix()
+	kept := r.retired[:0]
+	var out []RetiredKey
+	for _, k := range r.retired {
+		until := k.lastDeadline.Load()
+		if until < now {
+			continue
+		}
+		kept = append(kept, k)
+		out = append(out, RetiredKey{Address: k.GetAddress(), RetiredAt: k.retiredAt, Until: time.Unix(until, 0)})
+	}
+	clear(r.retired[len(kept):])
+	r.retired = kept
+	return out
+}
+
+// Assign returns the current key; the quote is signed with it even if a rotation follows
+func (r *RotatingSigner) Assign(chainID uint64, pairID string) (Signer, error) {
+	return r.key(), nil
+}
+
+// SignMMQuote signs with the current key
+func (r *RotatingSigner) SignMMQuote(chainID uint64, quote *MMQuote) ([]byte, error) {
+	return r.key().SignMMQuote(chainID, quote)
+}
+
+// SignMMQuoteClamped signs with the current key, clamping the deadline when the key supports it
+func (r *RotatingSigner) SignMMQuoteClamped(chainID uint64, quote *MMQuote) ([]byte, error) {
+	return r.key().SignMMQuoteClamped(chainID, quote)
+}
+
+// SignTypedData signs with the current key
+func (r *RotatingSigner) SignTypedData(domainSeparator []byte, structHash common.Hash) ([]byte, error) {
+	s, ok := r.Current().(TypedDataSigner)
+	if !ok {
+		return nil, fmt.Errorf("signing key cannot sign typed data")
+	}
+	return s.SignTypedData(domainSeparator, structHash)
+}
+
+// SignMMQuote signs with the key and records the deadline
+func (k *rotatingKey) SignMMQuote(chainID uint64, quote *MMQuote) ([]byte, error) {
+	sig, err := k.Signer.SignMMQuote(chainID, quote)
+	if err == nil {
+		k.record(quote)
+	}
+	return sig, err
+}
+
+// SignMMQuoteClamped signs with the deadline clamped when the key supports it
+func (k *rotatingKey) SignMMQuoteClamped(chainID uint64, quote *MMQuote) ([]byte, error) {
+	clamper, ok := k.Signer.(DeadlineClamper)
+	if !ok {
+		return k.SignMMQuote(chainID, quote)
+	}
+	sig, err := clamper.SignMMQuoteClamped(chainID, quote)
+	if err == nil {
+		k.record(quote)
+	}
+	return sig, err
+}
+
+// SignTypedData signs with the key
+func (k *rotatingKey) SignTypedData(domainSeparator []byte, structHash common.Hash) ([]byte, error) {
+	s, ok := k.Signer.(TypedDataSigner)
+	if !ok {
+		return nil, fmt.Errorf("signing key cannot sign typed data")
+	}
+	return s.SignTypedData(domainSeparator, structHash)
+}
+
+// record raises the latest signed deadline to the deadline of quote
+func (k *rotatingKey) record(quote *MMQuote) {
+	if quote.Deadline == nil || !quote.Deadline.IsInt64() {
+		return
+	}
+	deadline := quote.Deadline.Int64()
+	for {
+		last := k.lastDeadline.Load()
+		if deadline <= last || k.lastDeadline.CompareAndSwap(last, deadline) {
+			return
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package signer
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRotatingSigner_Rotate(t *testing.T) {
+	domains := NewDomainManager()
+	domains.AddPoolDomain(56, common.HexToAddress("0x1111111111111111111111111111111111111111"))
+	var keys []Signer
+	for i := 1; i <= 2; i++ {
+		s, err := NewSignerFromHex(fmt.Sprintf("0x%064x", i), domains)
+		if err != nil {
+			t.Fatalf("NewSignerFromHex failed: %v", err)
+		}
+		keys = append(keys, s)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	r := NewRotatingSigner(keys[0])
+	r.now = func() time.Time { return now }
+
+	// A quote assigned before the rotation is signed with the old key after it
+	inFlight, _ := r.Assign(56, "WBNB-USDT")
+	quote := canaryQuote(domains.GetPoolDomain(56))
+	quote.Deadline = big.NewInt(now.Unix() + 30)
+	if _, err := r.SignMMQuote(56, quote); err != nil {
+		t.Fatalf("SignMMQuote failed: %v", err)
+	}
+
+	rotation, err := r.Rotate(keys[1])
+	if err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	if rotation.From != keys[0].GetAddress() || rotation.To != keys[1].GetAddress() || rotation.Until.Unix() != now.Unix()+30 {
+		t.Errorf("rotation = %+v", rotation)
+	}
+	if r.GetAddress() != keys[1].GetAddress() {
+		t.Errorf("GetAddress() = %s, want the new key", r.GetAddress().Hex())
+	}
+	if err := CheckSigner(r, domains, 56); err != nil {
+		t.Errorf("new key: %v", err)
+	}
+
+	late := canaryQuote(domains.GetPoolDomain(56))
+	late.Deadline = big.NewInt(now.Unix() + 60)
+	sig, err := inFlight.SignMMQuote(56, late)
+	if err != nil {
+		t.Fatalf("in-flight SignMMQuote failed: %v", err)
+	}
+	digest, _ := domains.Digest(56, late)
+	if got, _ := recoverSigner(digest, sig); got != keys[0].GetAddress() {
+		t.Errorf("in-flight quote signed by %s, want the old key", got.Hex())
+	}
+
+	// The old key stays retired until its latest quote expires
+	if retired := r.Retired(); len(retired) != 1 || retired[0].Until.Unix() != now.Unix()+60 {
+		t.Errorf("Retired() = %+v, want the old key until its in-flight deadline", retired)
+	}
+	now = now.Add(61 * time.Second)
+	if retired := r.Retired(); len(retired) != 0 {
+		t.Errorf("Retired() after the deadline = %+v", retired)
+	}
+
+	if _, err := r.Rotate(keys[1]); err == nil {
+		t.Error("rotating to the current key succeeded")
+	}
+}
@@ -0,0 +1,111 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// RPCClient reads chain state needed to verify domains
+type RPCClient interface {
+	// ChainID returns the chain ID of the node (eth_chainId)
+	ChainID(ctx context.Context) (uint64, error)
+	// CallContract runs a read-only call at the latest block (eth_call)
+	CallContract(ctx context.Context, to common.Address, data []byte) ([]byte, error)
+}
+
+// ErrChainIDMismatch is returned when an RPC endpoint serves another chain than configured
+// Reading state for one chain from another chain's node makes every check built on it meaningless
+var ErrChainIDMismatch = errors.New("chain ID mismatch")
+
+// VerifyChainID checks that client is connected to chainID (eth_chainId)
+// Returns an ErrChainIDMismatch error when the node is on another chain
+func VerifyChainID(ctx context.Context, client RPCClient, chainID uint64) error {
+	nodeChainID, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if nodeChainID != chainID {
+		return fmt.Errorf("%w: RPC endpoint is on chain %d, want %d", ErrChainIDMismatch, nodeChainID, chainID)
+	}
+	return nil
+}
+
+// httpRPCClient is a minimal JSON-RPC client over HTTP
+type httpRPCClient struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPRPCClient creates an RPCClient for a JSON-RPC endpoint
+func NewHTTPRPCClient(url string) RPCClient {
+	return &httpRPCClient{url: url, client: http.DefaultClient}
+}
+
+// ChainID returns the chain ID of the node
+func (c *httpRPCClient) ChainID(ctx context.Context) (uint64, error) {
+	var chainID hexutil.Uint64
+	if err := c.call(ctx, "eth_chainId", &chainID); err != nil {
+		return 0, err
+	}
+	return uint64(chainID), nil
+}
+
+// CallContract runs eth_call against to at the latest block
+func (c *httpRPCClient) CallContract(ctx context.Context, to common.Address, data []byte) ([]byte, error) {
+	var result hexutil.Bytes
+	if err := c.call(ctx, "eth_call", &result, map[string]string{
+		"to":   to.Hex(),
+		"data": hexutil.Encode(data),
+	}, "latest"); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// call performs a JSON-RPC call and decodes the result
+func (c *httpRPCClient) call(ctx context.Context, method string, result any, params ...any) error {
+	if params == nil {
+		params = []any{}
+	}
+	body, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("%s: invalid response (HTTP %d): %w", method, resp.StatusCode, err)
+	}
+	if out.Error != nil {
+		return fmt.Errorf("%s: %s", method, out.Error.Message)
+	}
+	return json.Unmarshal(out.Result, result)
+}
@@ -1,12 +1,13 @@
 package signer
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
@@ -21,8 +22,14 @@ type Signer interface {
 
 // SignerConfig is the signer configuration
 type SignerConfig struct {
-	PrivateKey    string `json:"privateKey"`    // Private key (hexadecimal, highest priority)
-	PrivateKeyEnv string `json:"privateKeyEnv"` // Private key environment variable name (fallback)
+	PrivateKey    string       `json:"privateKey"`    // Private key (hexadecimal, highest priority)
+	PrivateKeyEnv string       `json:"privateKeyEnv"` // Private key environment variable name (fallback)
+	Vault         *VaultConfig `json:"vault"`         // Private key in Vault (used without the above)
+
+	KeystoreFile     string `json:"keystoreFile"` // Encrypted geth keystore file (used without the above)
+	KeystorePassword string `json:"-"`            // Password of KeystoreFile
+
+	MaxDeadlineHorizon time.Duration `json:"maxDeadlineHorizon"` // Max time from now to a signed deadline (0 = unlimited)
 }
 
 // signer is the signer implementation
@@ -30,6 +37,9 @@ type signer struct {
 	privateKey    *ecdsa.PrivateKey
 	address       common.Address
 	domainManager *DomainManager
+
+	maxHorizon time.Duration // Deadlines further ahead are refused or clamped (0 = unlimited)
+	now        func() time.Time
 }
 
 // NewSigner creates a signer
@@ -39,6 +49,7 @@ func NewSigner(privateKey *ecdsa.PrivateKey, domainManager *DomainManager) Signe
 		privateKey:    privateKey,
 		address:       address,
 		domainManager: domainManager,
+		now:           time.Now,
 	}
 }
 
@@ -61,7 +72,7 @@ func NewSignerFromEnv(envName string, domainManager *DomainManager) (Signer, err
 	return NewSignerFromHex(hexKey, domainManager)
 }
 
-// NewSignerFromConfig creates a signer from config (prefers config file private key, falls back to environment variable)
+// NewSignerFromConfig creates a signer from config (prefers config file private key, falls back to environment variable, then Vault, then keystore file)
 func NewSignerFromConfig(config *SignerConfig, domainManager *DomainManager) (Signer, error) {
 	var hexKey string
 
@@ -74,11 +85,32 @@ func NewSignerFromConfig(config *SignerConfig, domainManager *DomainManager) (Si
 		if hexKey == "" {
 			return nil, fmt.Errorf("environment variable %s is not set and no privateKey in config", config.PrivateKeyEnv)
 		}
+	} else if config.Vault != nil && config.Vault.Address != "" {
+		// 3. Fetch from Vault
+		key, err := FetchVaultKey(context.Background(), config.Vault)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch private key from vault: %w", err)
+		}
+		hexKey = key
+	} else if config.KeystoreFile != "" {
+		// 4. Decrypt a keystore file
+		key, err := LoadKeystore(config.KeystoreFile, config.KeystorePassword)
+		if err != nil {
+			return nil, err
+		}
+		s := NewSigner(key, domainManager)
+		s.(*signer).maxHorizon = config.MaxDeadlineHorizon
+		return s, nil
 	} else {
-		return nil, fmt.Errorf("neither privateKey nor privateKeyEnv is configured")
+		return nil, fmt.Errorf("neither privateKey, privateKeyEnv, vault nor keystoreFile is configured")
 	}
 
-	return NewSignerFromHex(hexKey, domainManager)
+	s, err := NewSignerFromHex(hexKey, domainManager)
+	if err != nil {
+		return nil, err
+	}
+	s.(*signer).maxHorizon = config.MaxDeadlineHorizon
+	return s, nil
 }
 
 // GetAddress returns the signer address
@@ -88,24 +120,23 @@ func (s *signer) GetAddress() common.Address {
 
 // SignMMQuote signs an MMQuote using EIP-712 (with verifying contract domain)
 func (s *signer) SignMMQuote(chainID uint64, quote *MMQuote) ([]byte, error) {
-	// Get verifying contract domain separator
-	domainSeparator, ok := s.domainManager.GetPoolDomainSeparator(chainID)
-	if !ok {
-		return nil, fmt.Errorf("RFQ Manager not configured for chainId %d", chainID)
+	if limit, ok := s.deadlineLimit(); ok && quote.Deadline != nil && quote.Deadline.Cmp(limit) > 0 {
+		return nil, fmt.Errorf("%w: deadline %s is after %s (max horizon %s)",
+			ErrDeadlineTooFar, quote.Deadline, limit, s.maxHorizon)
 	}
-
-	// Calculate struct hash
-	structHash, err := hashMMQuote(quote)
+	// Get the verifying contract domain separator and the struct hash in its MMQuote version
+	domainSeparator, structHash, err := s.domainManager.hashQuote(chainID, quote)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash MMQuote: %w", err)
+		return nil, err
 	}
 
+	return s.SignTypedData(domainSeparator, structHash)
+}
+
+// SignTypedData signs the EIP-712 digest of a struct hash under a domain separator
+func (s *signer) SignTypedData(domainSeparator []byte, structHash common.Hash) ([]byte, error) {
 	// Calculate EIP-712 digest: keccak256("\x19\x01" || domainSeparator || structHash)
-	digest := crypto.Keccak256Hash(
-		[]byte{0x19, 0x01},
-		domainSeparator,
-		structHash,
-	)
+	digest := typedDataHash(domainSeparator, structHash)
 
 	// ECDSA signing
 	sig, err := crypto.Sign(digest.Bytes(), s.privateKey)
@@ -121,51 +152,10 @@ func (s *signer) SignMMQuote(chainID uint64, quote *MMQuote) ([]byte, error) {
 	return sig, nil
 }
 
-// hashMMQuote calculates the struct hash of MMQuote
+// hashMMQuote calculates the struct hash of MMQuote in the current layout (DefaultQuoteType)
 // Field order matches contract MMQUOTE_SIGNATURE_HASH
-func hashMMQuote(quote *MMQuote) ([]byte, error) {
-	// ABI encoding types
-	bytes32Ty, _ := abi.NewType("bytes32", "", nil)
-	addressTy, _ := abi.NewType("address", "", nil)
-	uint256Ty, _ := abi.NewType("uint256", "", nil)
-
-	// Build arguments (order matches contract MMQUOTE_SIGNATURE_HASH fields)
-	args := abi.Arguments{
-		{Type: bytes32Ty}, // typeHash
-		{Type: addressTy}, // rfqManager
-		{Type: addressTy}, // from
-		{Type: addressTy}, // to
-		{Type: addressTy}, // inputToken
-		{Type: addressTy}, // outputToken
-		{Type: uint256Ty}, // amountIn
-		{Type: uint256Ty}, // amountOut
-		{Type: uint256Ty}, // deadline
-		{Type: uint256Ty}, // nonce
-		{Type: bytes32Ty}, // extraDataHash
-	}
-
-	// Calculate keccak256 hash of extraData
-	extraDataHash := crypto.Keccak256Hash(quote.ExtraData)
-
-	// Pack encoding
-	encoded, err := args.Pack(
-		MMQuoteTypeHash,
-		quote.RFQManager,
-		quote.From,
-		quote.To,
-		quote.InputToken,
-		quote.OutputToken,
-		quote.AmountIn,
-		quote.AmountOut,
-		quote.Deadline,
-		quote.Nonce,
-		extraDataHash,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	return crypto.Keccak256(encoded), nil
+func hashMMQuote(quote *MMQuote) (common.Hash, error) {
+	return DefaultQuoteType.hash(quote)
 }
 
 // HashExtraData calculates the keccak256 hash of extraData
@@ -0,0 +1,113 @@
+package signer
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// ToTypedData returns the typed data payload of quote under domain, in the layout of quoteType
+// The payload is go-ethereum's apitypes.TypedData, as taken by eth_signTypedData_v4 and signed by
+// external wallets (MetaMask, Fireblocks, clef); message values are strings, uint256 in decimal.
+// Signing it gives the signature SignMMQuote would; extraData is carried as its extraDataHash,
+// as the struct signs it. A nil quoteType is DefaultQuoteType.
+func (q *MMQuote) ToTypedData(domain *EIP712Domain, quoteType *QuoteType) (*apitypes.TypedData, error) {
+	if quoteType == nil {
+		quoteType = DefaultQuoteType
+	}
+	members := make([]apitypes.Type, len(quoteType.fields))
+	message := make(apitypes.TypedDataMessage, len(quoteType.fields))
+	for i, field := range quoteType.fields {
+		name := quoteType.names[i]
+		members[i] = apitypes.Type{Name: name, Type: quoteMembers[name].typ}
+		var value string
+		switch field {
+		case fieldRFQManager:
+			value = q.RFQManager.Hex()
+		case fieldFrom:
+			value = q.From.Hex()
+		case fieldTo:
+			value = q.To.Hex()
+		case fieldInputToken:
+			value = q.InputToken.Hex()
+		case fieldOutputToken:
+			value = q.OutputToken.Hex()
+		case fieldAmountIn:
+			value = uint256String(q.AmountIn)
+		case fieldAmountOut:
+			value = uint256String(q.AmountOut)
+		case fieldDeadline:
+			value = uint256String(q.Deadline)
+		case fieldNonce:
+			value = uint256String(q.Nonce)
+		case fieldExtraDataHash:
+			value = crypto.Keccak256Hash(q.ExtraData).Hex()
+		}
+		if value == "" {
+			return nil, fmt.Errorf("%s: value is not a uint256", name)
+		}
+		message[name] = value
+	}
+
+	domainFields, typedDomain := domain.typedData()
+	return &apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": domainFields,
+			quoteType.name: members,
+		},
+		PrimaryType: quoteType.name,
+		Domain:      typedDomain,
+		Message:     message,
+	}, nil
+}
+
+// typedData returns the members of the domain type and the domain values of a typed data payload
+// Members not in the domain type are left empty.
+func (d *EIP712Domain) typedData() ([]apitypes.Type, apitypes.TypedDataDomain) {
+	fields := d.fields()
+	var members []apitypes.Type
+	var values apitypes.TypedDataDomain
+	for _, m := range domainMembers {
+		if fields&m.field == 0 {
+			continue
+		}
+		members = append(members, apitypes.Type{Name: m.name, Type: m.typ})
+		switch m.field {
+		case DomainName:
+			values.Name = d.Name
+		case DomainVersion:
+			values.Version = d.Version
+		case DomainChainID:
+			chainID := new(big.Int)
+			if d.ChainID != nil {
+				chainID.Set(d.ChainID)
+			}
+			values.ChainId = (*math.HexOrDecimal256)(chainID)
+		case DomainVerifyingContract:
+			values.VerifyingContract = d.VerifyingContract.Hex()
+		case DomainSalt:
+			values.Salt = d.Salt.Hex()
+		}
+	}
+	return members, values
+}
+
+// uint256String returns v in decimal, or "" when v is nil or out of the uint256 range
+func uint256String(v *big.Int) string {
+	if v == nil || v.Sign() < 0 || v.BitLen() > 256 {
+		return ""
+	}
+	return v.String()
+}
+
+// TypedData returns the typed data payload of a quote on a chain, in the chain's MMQuote version
+func (m *DomainManager) TypedData(chainID uint64, quote *MMQuote) (*apitypes.TypedData, error) {
+	domain, _, quoteType, err := m.chainDomain(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return quote.ToTypedData(domain, quoteType)
+}
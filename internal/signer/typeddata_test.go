@@ -0,0 +1,122 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// hashTypedJSON hashes a decoded eth_signTypedData_v4 payload as a wallet does
+// Only the atomic types of the MMQuote and domain structs are supported.
+func hashTypedJSON(t *testing.T, payload map[string]any) common.Hash {
+	t.Helper()
+	types := payload["types"].(map[string]any)
+	structHash := func(name string, values map[string]any) []byte {
+		var encType strings.Builder
+		var data []byte
+		encType.WriteString(name + "(")
+		for i, m := range types[name].([]any) {
+			member := m.(map[string]any)
+			typ, field := member["type"].(string), member["name"].(string)
+			if i > 0 {
+				encType.WriteString(",")
+			}
+			encType.WriteString(typ + " " + field)
+			word := make([]byte, 32)
+			switch v := values[field]; typ {
+			case "string":
+				copy(word, crypto.Keccak256([]byte(v.(string))))
+			case "address":
+				copy(word[12:], common.HexToAddress(v.(string)).Bytes())
+			case "bytes32":
+				copy(word, common.HexToHash(v.(string)).Bytes())
+			case "uint256":
+				n, ok := new(big.Int).SetString(fmt.Sprint(v), 0) // A decimal or hex string, or a JSON number
+				if !ok {
+					t.Fatalf("%s: invalid uint256 %v", field, v)
+				}
+				n.FillBytes(word)
+			default:
+				t.Fatalf("unsupported type %s", typ)
+			}
+			data = append(data, word...)
+		}
+		encType.WriteString(")")
+		return crypto.Keccak256(crypto.Keccak256([]byte(encType.String())), data)
+	}
+	domain := structHash("EIP712Domain", payload["domain"].(map[string]any))
+	message := structHash(payload["primaryType"].(string), payload["message"].(map[string]any))
+	return crypto.Keccak256Hash([]byte("\x19\x01"), domain, message)
+}
+
+func TestMMQuote_ToTypedData(t *testing.T) {
+	domains := NewDomainManager()
+	domains.AddPoolDomain(56, common.HexToAddress("0x1111111111111111111111111111111111111111"))
+	domains.AddPoolDomain(8453, common.HexToAddress("0x2222222222222222222222222222222222222222"))
+	if err := domains.SetQuoteType(8453, legacyQuoteType); err != nil {
+		t.Fatal(err)
+	}
+	// A salted domain without version
+	domains.AddPoolDomain(97, common.HexToAddress("0x3333333333333333333333333333333333333333"))
+	if err := domains.SetDomainFields(97, []string{"name", "chainId", "verifyingContract", "salt"}, "0x"+strings.Repeat("ab", 32)); err != nil {
+		t.Fatal(err)
+	}
+	quote := benchQuote()
+
+	for _, chainID := range []uint64{56, 8453, 97} {
+		td, err := domains.TypedData(chainID, quote)
+		if err != nil {
+			t.Fatalf("chain %d: TypedData failed: %v", chainID, err)
+		}
+		if td.PrimaryType != "MMQuote" || len(td.Types["MMQuote"]) != len(domains.GetQuoteType(chainID).fields) {
+			t.Errorf("chain %d: primary type %s with %d members", chainID, td.PrimaryType, len(td.Types["MMQuote"]))
+		}
+		data, err := json.Marshal(td)
+		if err != nil {
+			t.Fatalf("chain %d: JSON failed: %v", chainID, err)
+		}
+
+		// Decode the payload as a wallet would and hash it from scratch
+		dec := json.NewDecoder(strings.NewReader(string(data)))
+		dec.UseNumber()
+		var payload map[string]any
+		if err := dec.Decode(&payload); err != nil {
+			t.Fatalf("chain %d: payload is not JSON: %v", chainID, err)
+		}
+		want, _ := domains.Digest(chainID, quote)
+		if got := hashTypedJSON(t, payload); got != want {
+			t.Errorf("chain %d: typed data hashes to %s, want the quote digest %s", chainID, got.Hex(), want.Hex())
+		}
+
+		// go-ethereum's own EIP-712 hashing, as clef signs
+		hash, _, err := apitypes.TypedDataAndHash(*td)
+		if err != nil {
+			t.Fatalf("chain %d: TypedDataAndHash failed: %v", chainID, err)
+		}
+		if got := common.BytesToHash(hash); got != want {
+			t.Errorf("chain %d: TypedDataAndHash = %s, want the quote digest %s", chainID, got.Hex(), want.Hex())
+		}
+		// A wallet decodes the JSON payload into the same struct
+		var decoded apitypes.TypedData
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("chain %d: payload does not decode: %v", chainID, err)
+		}
+		if hash, _, err := apitypes.TypedDataAndHash(decoded); err != nil || common.BytesToHash(hash) != want {
+			t.Errorf("chain %d: decoded payload hashes to %x (%v), want the quote digest %s", chainID, hash, err, want.Hex())
+		}
+	}
+
+	if _, err := domains.TypedData(1, quote); err == nil {
+		t.Error("TypedData of an unconfigured chain succeeded")
+	}
+	quote.Nonce = nil
+	if _, err := domains.TypedData(56, quote); err == nil || !strings.Contains(err.Error(), "nonce") {
+		t.Errorf("nil nonce: %v", err)
+	}
+}
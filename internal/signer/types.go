@@ -23,22 +23,11 @@ type MMQuote struct {
 	ExtraData   []byte         // Optional opaque bytes (used to calculate extraDataHash)
 }
 
+// MMQuoteType is the EIP-712 encoded type of the current MMQuote struct (see QuoteType for other versions)
+const MMQuoteType = "MMQuote(address rfq_manager,address from,address to,address inputToken,address outputToken," +
+	"uint256 amountIn,uint256 amountOut,uint256 deadline,uint256 nonce,bytes32 extraDataHash)"
+
 // MMQuoteTypeHash is the keccak256 hash of MMQuote type
 // Corresponds to contract MMQUOTE_SIGNATURE_HASH
-var MMQuoteTypeHash = crypto.Keccak256Hash([]byte(
-	"MMQuote(address rfq_manager,address from,address to,address inputToken,address outputToken," +
-		"uint256 amountIn,uint256 amountOut,uint256 deadline,uint256 nonce,bytes32 extraDataHash)"))
-
-// WrappedNativeTokens maps chain IDs to their Wrapped Native Token addresses
-// chainId -> wrapped token address
-var WrappedNativeTokens = map[uint64]common.Address{
-	56:   common.HexToAddress("0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c"), // BSC: WBNB
-	8453: common.HexToAddress("0x4200000000000000000000000000000000000006"), // Base: WETH
-	1:    common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"), // Ethereum: WETH
-}
+var MMQuoteTypeHash = crypto.Keccak256Hash([]byte(MMQuoteType))
 
-// GetWrappedToken gets the Wrapped Native Token address for a specified chain
-func GetWrappedToken(chainID uint64) (common.Address, bool) {
-	addr, ok := WrappedNativeTokens[chainID]
-	return addr, ok
-}
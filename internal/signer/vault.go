@@ -0,0 +1,151 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// VaultConfig locates a private key in a HashiCorp Vault KV version 2 secrets engine
+// Vault is authenticated with Token, or with an AppRole login of RoleID and SecretID.
+type VaultConfig struct {
+	Address   string // Vault server, e.g. https://vault.internal:8200
+	Namespace string // Vault Enterprise namespace, empty = none
+	Mount     string // KV v2 mount path (default: secret)
+	Path      string // Secret path under the mount
+	Field     string // Secret field holding the hexadecimal key (default: privateKey)
+
+	Token        string // Vault token; used when set
+	RoleID       string // AppRole role ID, used without a token
+	SecretID     string // AppRole secret ID
+	AppRoleMount string // AppRole auth mount path (default: approle)
+
+	Timeout time.Duration // Per Vault request (default: 10s)
+	Client  *http.Client  // nil = http.DefaultClient
+}
+
+// FetchVaultKey reads the hexadecimal private key of cfg from Vault
+// Called at startup and again on key rotation, so a key rotated in Vault is picked up.
+func FetchVaultKey(ctx context.Context, cfg *VaultConfig) (string, error) {
+	v := vaultClient{cfg: cfg, client: cfg.Client}
+	if v.client == nil {
+		v.client = http.DefaultClient
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	token := cfg.Token
+	if token == "" {
+		loginCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		var err error
+		if token, err = v.appRoleLogin(loginCtx); err != nil {
+			return "", err
+		}
+	}
+
+	readCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	mount, field := cfg.Mount, cfg.Field
+	if mount == "" {
+		mount = "secret"
+	}
+	if field == "" {
+		field = "privateKey"
+	}
+	var secret struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	path := strings.Trim(mount, "/") + "/data/" + strings.Trim(cfg.Path, "/")
+	if err := v.do(readCtx, http.MethodGet, path, token, nil, &secret); err != nil {
+		return "", err
+	}
+	value, ok := secret.Data.Data[field].(string)
+	if !ok || strings.TrimSpace(value) == "" {
+		return "", fmt.Errorf("vault secret %s has no string field %q", path, field)
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// vaultClient calls the Vault HTTP API
+type vaultClient struct {
+	cfg    *VaultConfig
+	client *http.Client
+}
+
+// appRoleLogin exchanges the AppRole credentials for a client token
+func (v *vaultClient) appRoleLogin(ctx context.Context) (string, error) {
+	if v.cfg.RoleID == "" || v.cfg.SecretID == "" {
+		return "", fmt.Errorf("vault: a token or an AppRole role ID and secret ID are required")
+	}
+	mount := v.cfg.AppRoleMount
+	if mount == "" {
+		mount = "approle"
+	}
+	var login struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	body := map[string]string{"role_id": v.cfg.RoleID, "secret_id": v.cfg.SecretID}
+	if err := v.do(ctx, http.MethodPost, "auth/"+strings.Trim(mount, "/")+"/login", "", body, &login); err != nil {
+		return "", fmt.Errorf("AppRole login: %w", err)
+	}
+	if login.Auth.ClientToken == "" {
+		return "", fmt.Errorf("vault: AppRole login returned no token")
+	}
+	return login.Auth.ClientToken, nil
+}
+
+// do sends a request to /v1/<path> and decodes the JSON response into out
+func (v *vaultClient) do(ctx context.Context, method, path, token string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	url := strings.TrimRight(v.cfg.Address, "/") + "/v1/" + path
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("vault: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if v.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.cfg.Namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// Vault explains failures in {"errors":[...]}; it never echoes secrets there
+		var failure struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&failure)
+		return fmt.Errorf("vault: %s %s: HTTP %d %s", method, path, resp.StatusCode, strings.Join(failure.Errors, "; "))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("vault: %s %s: invalid response: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeVault serves an AppRole login and a KV v2 secret
+func fakeVault(t *testing.T, key string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			var login map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&login)
+			if login["role_id"] != "role" || login["secret_id"] != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"errors":["invalid role or secret ID"]}`)
+				return
+			}
+			fmt.Fprint(w, `{"auth":{"client_token":"approle-token"}}`)
+		case "/v1/kv/data/mm/signer":
+			if token := r.Header.Get("X-Vault-Token"); token != "root-token" && token != "approle-token" {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"errors":["permission denied"]}`)
+				return
+			}
+			fmt.Fprintf(w, `{"data":{"data":{"privateKey":%q},"metadata":{"version":3}}}`, key)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[]}`)
+		}
+	}))
+}
+
+func TestNewSignerFromConfig_Vault(t *testing.T) {
+	key := fmt.Sprintf("0x%064x", 7)
+	want, err := NewSignerFromHex(key, NewDomainManager())
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := fakeVault(t, key)
+	defer srv.Close()
+
+	for name, vault := range map[string]VaultConfig{
+		"token":   {Address: srv.URL, Mount: "kv", Path: "mm/signer", Token: "root-token"},
+		"approle": {Address: srv.URL + "/", Mount: "kv", Path: "/mm/signer", RoleID: "role", SecretID: "secret"},
+	} {
+		s, err := NewSignerFromConfig(&SignerConfig{Vault: &vault}, NewDomainManager())
+		if err != nil {
+			t.Errorf("%s: NewSignerFromConfig failed: %v", name, err)
+			continue
+		}
+		if s.GetAddress() != want.GetAddress() {
+			t.Errorf("%s: address %s, want %s", name, s.GetAddress().Hex(), want.GetAddress().Hex())
+		}
+	}
+
+	// Failures name the cause, never the key
+	for name, tt := range map[string]struct {
+		vault VaultConfig
+		want  string
+	}{
+		"bad token":   {VaultConfig{Address: srv.URL, Mount: "kv", Path: "mm/signer", Token: "wrong"}, "HTTP 403 permission denied"},
+		"bad secret":  {VaultConfig{Address: srv.URL, Mount: "kv", Path: "mm/signer", RoleID: "role", SecretID: "x"}, "AppRole login"},
+		"no field":    {VaultConfig{Address: srv.URL, Mount: "kv", Path: "mm/signer", Field: "key", Token: "root-token"}, `no string field "key"`},
+		"no auth":     {VaultConfig{Address: srv.URL, Mount: "kv", Path: "mm/signer"}, "token or an AppRole"},
+		"wrong mount": {VaultConfig{Address: srv.URL, Path: "mm/signer", Token: "root-token"}, "HTTP 404"},
+	} {
+		_, err := FetchVaultKey(context.Background(), &tt.vault)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", name, err, tt.want)
+		}
+		if err != nil && strings.Contains(err.Error(), key[2:]) {
+			t.Errorf("%s: error contains the key", name)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package signer
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// VectorPrivateKey is the well-known private key (1) used to generate golden vectors
+// Never use it for anything else
+const VectorPrivateKey = "0x0000000000000000000000000000000000000000000000000000000000000001"
+
+// Vector is a golden MMQuote -> digest -> signature test vector
+// Numbers are decimal strings and bytes are 0x-prefixed hex so the file can be checked with cast
+type Vector struct {
+	Name string `json:"name"`
+
+	// Domain
+	DomainName        string `json:"domainName"`
+	DomainVersion     string `json:"domainVersion"`
+	ChainID           uint64 `json:"chainId"`
+	VerifyingContract string `json:"verifyingContract"`
+
+	// MMQuote
+	From        string `json:"from"`
+	To          string `json:"to"`
+	InputToken  string `json:"inputToken"`
+	OutputToken string `json:"outputToken"`
+	AmountIn    string `json:"amountIn"`
+	AmountOut   string `json:"amountOut"`
+	Deadline    string `json:"deadline"`
+	Nonce       string `json:"nonce"`
+	ExtraData   string `json:"extraData"`
+
+	// Expected results
+	DomainSeparator string `json:"domainSeparator"`
+	StructHash      string `json:"structHash"`
+	Digest          string `json:"digest"`
+	Signer          string `json:"signer"`
+	Signature       string `json:"signature"`
+}
+
+// Domain returns the EIP-712 domain of the vector
+func (v *Vector) Domain() *EIP712Domain {
+	return &EIP712Domain{
+		Name:              v.DomainName,
+		Version:           v.DomainVersion,
+		ChainID:           new(big.Int).SetUint64(v.ChainID),
+		VerifyingContract: common.HexToAddress(v.VerifyingContract),
+	}
+}
+
+// Quote returns the MMQuote of the vector
+func (v *Vector) Quote() (*MMQuote, error) {
+	quote := &MMQuote{
+		RFQManager:  common.HexToAddress(v.VerifyingContract),
+		From:        common.HexToAddress(v.From),
+		To:          common.HexToAddress(v.To),
+		InputToken:  common.HexToAddress(v.InputToken),
+		OutputToken: common.HexToAddress(v.OutputToken),
+	}
+
+	var ok bool
+	for _, f := range []struct {
+		dst   **big.Int
+		name  string
+		value string
+	}{
+		{&quote.AmountIn, "amountIn", v.AmountIn},
+		{&quote.AmountOut, "amountOut", v.AmountOut},
+		{&quote.Deadline, "deadline", v.Deadline},
+		{&quote.Nonce, "nonce", v.Nonce},
+	} {
+		if *f.dst, ok = new(big.Int).SetString(f.value, 10); !ok {
+			return nil, fmt.Errorf("vector %s: invalid %s %q", v.Name, f.name, f.value)
+		}
+	}
+
+	extraData, err := hexutil.Decode(v.ExtraData)
+	if err != nil {
+		return nil, fmt.Errorf("vector %s: invalid extraData: %w", v.Name, err)
+	}
+	quote.ExtraData = extraData
+	return quote, nil
+}
+
+// Digest calculates the EIP-712 digest of a quote under a domain
+func Digest(domain *EIP712Domain, quote *MMQuote) (common.Hash, error) {
+	structHash, err := hashMMQuote(quote)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to hash MMQuote: %w", err)
+	}
+	return typedDataHash(domain.DomainSeparator(), structHash), nil
+}
+
+// vectorCase is an input of a golden vector
+type vectorCase struct {
+	name        string
+	domainName  string
+	chainID     uint64
+	contract    string
+	from        string
+	to          string
+	inputToken  string
+	outputToken string
+	amountIn    string
+	amountOut   string
+	deadline    string
+	nonce       string
+	extraData   string
+}
+
+// vectorCases cover BSC, Base and Ethereum, both decimal layouts, native tokens,
+// extraData, a non-default domain name and uint256 edge values
+var vectorCases = []vectorCase{
+	{
+		name: "bsc-wbnb-usdt", chainID: 56, contract: "0x28D3a265f6d40867986004029ee91F4C9532fCC5",
+		from: "0x1234567890123456789012345678901234567890", to: "0x1234567890123456789012345678901234567890",
+		inputToken: "0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c", outputToken: "0x55d398326f99059fF775485246999027B3197955",
+		amountIn: "1000000000000000000", amountOut: "600000000000000000000", deadline: "1735084800", nonce: "1",
+	},
+	{
+		name: "base-weth-usdc-6-decimals", chainID: 8453, contract: "0x1111111111111111111111111111111111111111",
+		from: "0xaAaAaAaaAaAaAaaAaAAAAAAAAaaaAaAaAaaAaaAa", to: "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB",
+		inputToken: "0x4200000000000000000000000000000000000006", outputToken: "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913",
+		amountIn: "3280000000000000000", amountOut: "11152000000", deadline: "1735084830", nonce: "42",
+	},
+	{
+		name: "eth-native-in", chainID: 1, contract: "0x2222222222222222222222222222222222222222",
+		from: "0x1234567890123456789012345678901234567890", to: "0x1234567890123456789012345678901234567890",
+		inputToken: "0x0000000000000000000000000000000000000000", outputToken: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		amountIn: "500000000000000000", amountOut: "1700000000", deadline: "1735084860", nonce: "18446744073709551617",
+	},
+	{
+		name: "bsc-extra-data", chainID: 56, contract: "0x28D3a265f6d40867986004029ee91F4C9532fCC5",
+		from: "0x1234567890123456789012345678901234567890", to: "0x1234567890123456789012345678901234567890",
+		inputToken: "0x55d398326f99059fF775485246999027B3197955", outputToken: "0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c",
+		amountIn: "600000000000000000000", amountOut: "999000000000000000", deadline: "1735084800", nonce: "7",
+		extraData: "0xdeadbeef",
+	},
+	{
+		name: "custom-domain-name", domainName: "DarkPool RFQ Manager", chainID: 56, contract: "0x28D3a265f6d40867986004029ee91F4C9532fCC5",
+		from: "0x1234567890123456789012345678901234567890", to: "0x1234567890123456789012345678901234567890",
+		inputToken: "0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c", outputToken: "0x55d398326f99059fF775485246999027B3197955",
+		amountIn: "1000000000000000000", amountOut: "600000000000000000000", deadline: "1735084800", nonce: "1",
+	},
+	{
+		name: "uint256-max", chainID: 56, contract: "0x28D3a265f6d40867986004029ee91F4C9532fCC5",
+		from: "0x1234567890123456789012345678901234567890", to: "0x1234567890123456789012345678901234567890",
+		inputToken: "0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c", outputToken: "0x55d398326f99059fF775485246999027B3197955",
+		amountIn:  "115792089237316195423570985008687907853269984665640564039457584007913129639935",
+		amountOut: "1", deadline: "115792089237316195423570985008687907853269984665640564039457584007913129639935",
+		nonce: "0",
+	},
+}
+
+// GenerateVectors computes the golden vectors with VectorPrivateKey
+func GenerateVectors() ([]Vector, error) {
+	privateKey, err := crypto.HexToECDSA(VectorPrivateKey[2:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid vector private key: %w", err)
+	}
+	signerAddr := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	vectors := make([]Vector, 0, len(vectorCases))
+	for _, c := range vectorCases {
+		domainName := c.domainName
+		if domainName == "" {
+			domainName = DefaultDomainName
+		}
+		extraData := c.extraData
+		if extraData == "" {
+			extraData = "0x"
+		}
+
+		v := Vector{
+			Name:              c.name,
+			DomainName:        domainName,
+			DomainVersion:     DefaultDomainVersion,
+			ChainID:           c.chainID,
+			VerifyingContract: common.HexToAddress(c.contract).Hex(),
+			From:              common.HexToAddress(c.from).Hex(),
+			To:                common.HexToAddress(c.to).Hex(),
+			InputToken:        common.HexToAddress(c.inputToken).Hex(),
+			OutputToken:       common.HexToAddress(c.outputToken).Hex(),
+			AmountIn:          c.amountIn,
+			AmountOut:         c.amountOut,
+			Deadline:          c.deadline,
+			Nonce:             c.nonce,
+			ExtraData:         extraData,
+			Signer:            signerAddr.Hex(),
+		}
+
+		quote, err := v.Quote()
+		if err != nil {
+			return nil, err
+		}
+		structHash, err := hashMMQuote(quote)
+		if err != nil {
+			return nil, fmt.Errorf("vector %s: failed to hash MMQuote: %w", c.name, err)
+		}
+
+		dm := NewDomainManager()
+		dm.AddPoolDomainWithConfig(c.chainID, domainName, DefaultDomainVersion, c.contract)
+		sig, err := NewSigner(privateKey, dm).SignMMQuote(c.chainID, quote)
+		if err != nil {
+			return nil, fmt.Errorf("vector %s: %w", c.name, err)
+		}
+		digest, err := Digest(v.Domain(), quote)
+		if err != nil {
+			return nil, fmt.Errorf("vector %s: %w", c.name, err)
+		}
+
+		v.DomainSeparator = hexutil.Encode(v.Domain().DomainSeparator())
+		v.StructHash = structHash.Hex()
+		v.Digest = digest.Hex()
+		v.Signature = hexutil.Encode(sig)
+		vectors = append(vectors, v)
+	}
+	return vectors, nil
+}
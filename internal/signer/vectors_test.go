@@ -0,0 +1,148 @@
+package signer
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Regenerate with: go run ./cmd/mm vectors
+const vectorsFile = "testdata/eip712_vectors.json"
+
+func loadVectors(t *testing.T) []Vector {
+	t.Helper()
+	data, err := os.ReadFile(vectorsFile)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", vectorsFile, err)
+	}
+	var vectors []Vector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatalf("Failed to parse %s: %v", vectorsFile, err)
+	}
+	if len(vectors) == 0 {
+		t.Fatal("No vectors loaded")
+	}
+	return vectors
+}
+
+// TestGoldenVectors fails if hashing, the domain separator or signing drift from the golden file
+func TestGoldenVectors(t *testing.T) {
+	for _, v := range loadVectors(t) {
+		t.Run(v.Name, func(t *testing.T) {
+			quote, err := v.Quote()
+			if err != nil {
+				t.Fatalf("Quote failed: %v", err)
+			}
+
+			if got := hexutil.Encode(v.Domain().DomainSeparator()); got != v.DomainSeparator {
+				t.Errorf("DomainSeparator = %s, want %s", got, v.DomainSeparator)
+			}
+			structHash, err := hashMMQuote(quote)
+			if err != nil {
+				t.Fatalf("hashMMQuote failed: %v", err)
+			}
+			if got := structHash.Hex(); got != v.StructHash {
+				t.Errorf("StructHash = %s, want %s", got, v.StructHash)
+			}
+			digest, err := Digest(v.Domain(), quote)
+			if err != nil {
+				t.Fatalf("Digest failed: %v", err)
+			}
+			if digest.Hex() != v.Digest {
+				t.Errorf("Digest = %s, want %s", digest.Hex(), v.Digest)
+			}
+
+			dm := NewDomainManager()
+			dm.AddPoolDomainWithConfig(v.ChainID, v.DomainName, v.DomainVersion, v.VerifyingContract)
+			s, err := NewSignerFromHex(VectorPrivateKey, dm)
+			if err != nil {
+				t.Fatalf("NewSignerFromHex failed: %v", err)
+			}
+			sig, err := s.SignMMQuote(v.ChainID, quote)
+			if err != nil {
+				t.Fatalf("SignMMQuote failed: %v", err)
+			}
+			if got := hexutil.Encode(sig); got != v.Signature {
+				t.Errorf("Signature = %s, want %s", got, v.Signature)
+			}
+
+			// ecrecover as the contract does
+			wantSig := hexutil.MustDecode(v.Signature)
+			wantSig[64] -= 27
+			pub, err := crypto.SigToPub(hexutil.MustDecode(v.Digest), wantSig)
+			if err != nil {
+				t.Fatalf("SigToPub failed: %v", err)
+			}
+			if got := crypto.PubkeyToAddress(*pub).Hex(); got != v.Signer {
+				t.Errorf("Recovered signer = %s, want %s", got, v.Signer)
+			}
+		})
+	}
+}
+
+// TestGoldenVectors_IndependentEncoding recomputes the hashes with plain 32-byte word
+// concatenation instead of the abi package, as abi.encode does for static types in Solidity
+func TestGoldenVectors_IndependentEncoding(t *testing.T) {
+	word := func(b []byte) []byte { return common.LeftPadBytes(b, 32) }
+	uint256 := func(s string) []byte {
+		n, _ := new(big.Int).SetString(s, 10)
+		return word(n.Bytes())
+	}
+	address := func(s string) []byte { return word(common.HexToAddress(s).Bytes()) }
+	keccak := func(s string) []byte { return crypto.Keccak256([]byte(s)) }
+
+	for _, v := range loadVectors(t) {
+		t.Run(v.Name, func(t *testing.T) {
+			domainSeparator := crypto.Keccak256(
+				keccak("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"),
+				keccak(v.DomainName),
+				keccak(v.DomainVersion),
+				word(new(big.Int).SetUint64(v.ChainID).Bytes()),
+				address(v.VerifyingContract),
+			)
+			if got := hexutil.Encode(domainSeparator); got != v.DomainSeparator {
+				t.Errorf("DomainSeparator = %s, want %s", got, v.DomainSeparator)
+			}
+
+			structHash := crypto.Keccak256(
+				keccak("MMQuote(address rfq_manager,address from,address to,address inputToken,address outputToken,"+
+					"uint256 amountIn,uint256 amountOut,uint256 deadline,uint256 nonce,bytes32 extraDataHash)"),
+				address(v.VerifyingContract),
+				address(v.From),
+				address(v.To),
+				address(v.InputToken),
+				address(v.OutputToken),
+				uint256(v.AmountIn),
+				uint256(v.AmountOut),
+				uint256(v.Deadline),
+				uint256(v.Nonce),
+				crypto.Keccak256(hexutil.MustDecode(v.ExtraData)),
+			)
+			if got := hexutil.Encode(structHash); got != v.StructHash {
+				t.Errorf("StructHash = %s, want %s", got, v.StructHash)
+			}
+
+			digest := crypto.Keccak256([]byte{0x19, 0x01}, domainSeparator, structHash)
+			if got := hexutil.Encode(digest); got != v.Digest {
+				t.Errorf("Digest = %s, want %s", got, v.Digest)
+			}
+		})
+	}
+}
+
+// TestGoldenVectors_UpToDate fails if the vector cases changed without regenerating the file
+func TestGoldenVectors_UpToDate(t *testing.T) {
+	generated, err := GenerateVectors()
+	if err != nil {
+		t.Fatalf("GenerateVectors failed: %v", err)
+	}
+	if !reflect.DeepEqual(generated, loadVectors(t)) {
+		t.Errorf("%s is stale, regenerate with: go run ./cmd/mm vectors", vectorsFile)
+	}
+}
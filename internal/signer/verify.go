@@ -0,0 +1,132 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrDomainMismatch is returned when the on-chain domain differs from the configured one
+// Quotes signed with a mismatched domain can never be verified by the contract
+var ErrDomainMismatch = errors.New("domain mismatch")
+
+// Selectors of the contract's domain getters
+var (
+	domainSeparatorSelector = crypto.Keccak256([]byte("DOMAIN_SEPARATOR()"))[:4]
+	eip712DomainSelector    = crypto.Keccak256([]byte("eip712Domain()"))[:4] // EIP-5267
+)
+
+// eip712DomainOutputs is the ABI layout of the eip712Domain() result
+var eip712DomainOutputs = func() abi.Arguments {
+	bytes1Ty, _ := abi.NewType("bytes1", "", nil)
+	stringTy, _ := abi.NewType("string", "", nil)
+	uint256Ty, _ := abi.NewType("uint256", "", nil)
+	addressTy, _ := abi.NewType("address", "", nil)
+	bytes32Ty, _ := abi.NewType("bytes32", "", nil)
+	uint256ArrTy, _ := abi.NewType("uint256[]", "", nil)
+	return abi.Arguments{
+		{Type: bytes1Ty},
+		{Type: stringTy},
+		{Type: stringTy},
+		{Type: uint256Ty},
+		{Type: addressTy},
+		{Type: bytes32Ty},
+		{Type: uint256ArrTy},
+	}
+}()
+
+// VerifyDomainAgainstChain checks the configured domain of chainID against its verifying contract
+// The contract's DOMAIN_SEPARATOR() is compared with the local separator. Contracts without it
+// are checked through eip712Domain() (EIP-5267), which also names the mismatched fields.
+// Returns an ErrDomainMismatch error when they differ.
+func (m *DomainManager) VerifyDomainAgainstChain(ctx context.Context, chainID uint64, client RPCClient) error {
+	domain := m.GetPoolDomain(chainID)
+	if domain == nil {
+		return fmt.Errorf("no domain configured for chain %d", chainID)
+	}
+	local, _ := m.GetPoolDomainSeparator(chainID)
+
+	if err := VerifyChainID(ctx, client, chainID); err != nil {
+		return err
+	}
+
+	onChain, separatorErr := client.CallContract(ctx, domain.VerifyingContract, domainSeparatorSelector)
+	if separatorErr == nil && len(onChain) == 32 && bytes.Equal(onChain, local) {
+		return nil
+	}
+
+	// Explain a mismatch, or verify without DOMAIN_SEPARATOR(), from the domain fields
+	remote, err := fetchEIP712Domain(ctx, client, domain.VerifyingContract)
+	if err != nil {
+		if separatorErr != nil {
+			return fmt.Errorf("contract %s exposes neither DOMAIN_SEPARATOR() (%v) nor eip712Domain() (%v)",
+				domain.VerifyingContract.Hex(), separatorErr, err)
+		}
+		return fmt.Errorf("%w on chain %d: contract separator 0x%x, local 0x%x",
+			ErrDomainMismatch, chainID, onChain, local)
+	}
+	if diff := domainDiff(domain, remote); diff != "" {
+		return fmt.Errorf("%w on chain %d: %s", ErrDomainMismatch, chainID, diff)
+	}
+	if separatorErr == nil && !bytes.Equal(onChain, local) {
+		// Same fields, different separator: the contract hashes another domain type
+		return fmt.Errorf("%w on chain %d: contract separator 0x%x, local 0x%x (fields match)",
+			ErrDomainMismatch, chainID, onChain, local)
+	}
+	return nil
+}
+
+// fetchEIP712Domain calls eip712Domain() on contract
+func fetchEIP712Domain(ctx context.Context, client RPCClient, contract common.Address) (*EIP712Domain, error) {
+	out, err := client.CallContract(ctx, contract, eip712DomainSelector)
+	if err != nil {
+		return nil, err
+	}
+	values, err := eip712DomainOutputs.Unpack(out)
+	if err != nil {
+		return nil, fmt.Errorf("invalid eip712Domain() result: %w", err)
+	}
+	fields := values[0].([1]byte)
+	return &EIP712Domain{
+		Name:              values[1].(string),
+		Version:           values[2].(string),
+		ChainID:           values[3].(*big.Int),
+		VerifyingContract: values[4].(common.Address),
+		Salt:              values[5].([32]byte),
+		Fields:            DomainFields(fields[0]),
+	}, nil
+}
+
+// domainDiff describes the fields of local that differ from remote, empty if none
+// Members outside the domain type of local are not compared.
+func domainDiff(local, remote *EIP712Domain) string {
+	var diffs []string
+	fields := local.fields()
+	if fields != remote.fields() {
+		diffs = append(diffs, fmt.Sprintf("fields %s, contract %s", fields, remote.fields()))
+	}
+	if fields&DomainName != 0 && local.Name != remote.Name {
+		diffs = append(diffs, fmt.Sprintf("name %q, contract %q", local.Name, remote.Name))
+	}
+	if fields&DomainVersion != 0 && local.Version != remote.Version {
+		diffs = append(diffs, fmt.Sprintf("version %q, contract %q", local.Version, remote.Version))
+	}
+	if fields&DomainChainID != 0 && local.ChainID.Cmp(remote.ChainID) != 0 {
+		diffs = append(diffs, fmt.Sprintf("chainId %s, contract %s", local.ChainID, remote.ChainID))
+	}
+	if fields&DomainVerifyingContract != 0 && local.VerifyingContract != remote.VerifyingContract {
+		diffs = append(diffs, fmt.Sprintf("verifyingContract %s, contract %s",
+			local.VerifyingContract.Hex(), remote.VerifyingContract.Hex()))
+	}
+	if fields&DomainSalt != 0 && local.Salt != remote.Salt {
+		diffs = append(diffs, fmt.Sprintf("salt %s, contract %s", local.Salt.Hex(), remote.Salt.Hex()))
+	}
+	return strings.Join(diffs, "; ")
+}
@@ -0,0 +1,137 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const verifyContract = "0x28D3a265f6d40867986004029ee91F4C9532fCC5"
+
+// fakeRPC answers the domain getters of one contract; a nil result reverts
+type fakeRPC struct {
+	chainID         uint64
+	domainSeparator []byte
+	eip712Domain    []byte
+}
+
+func (f *fakeRPC) ChainID(ctx context.Context) (uint64, error) {
+	return f.chainID, nil
+}
+
+func (f *fakeRPC) CallContract(ctx context.Context, to common.Address, data []byte) ([]byte, error) {
+	var out []byte
+	switch {
+	case bytes.Equal(data, domainSeparatorSelector):
+		out = f.domainSeparator
+	case bytes.Equal(data, eip712DomainSelector):
+		out = f.eip712Domain
+	}
+	if out == nil {
+		return nil, errors.New("execution reverted")
+	}
+	return out, nil
+}
+
+// packEIP712Domain encodes an eip712Domain() result for d
+func packEIP712Domain(t *testing.T, d *EIP712Domain) []byte {
+	t.Helper()
+	out, err := eip712DomainOutputs.Pack([1]byte{byte(d.fields())}, d.Name, d.Version, d.ChainID, d.VerifyingContract, [32]byte(d.Salt), []*big.Int{})
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	return out
+}
+
+func TestVerifyDomainAgainstChain(t *testing.T) {
+	dm := NewDomainManager()
+	dm.AddPoolDomainWithConfig(56, "RFQ Manager", "1", verifyContract)
+	local := dm.GetPoolDomain(56)
+	separator, _ := dm.GetPoolDomainSeparator(56)
+	otherVersion := &EIP712Domain{Name: local.Name, Version: "2", ChainID: local.ChainID, VerifyingContract: local.VerifyingContract}
+	salted := *local
+	salted.Fields, salted.Salt = StandardDomainFields|DomainSalt, common.HexToHash("0x01")
+
+	tests := []struct {
+		name         string
+		rpc          *fakeRPC
+		wantMismatch bool
+		wantErr      string
+	}{
+		{"matching separator", &fakeRPC{chainID: 56, domainSeparator: separator}, false, ""},
+		{"eip712Domain only", &fakeRPC{chainID: 56, eip712Domain: packEIP712Domain(t, local)}, false, ""},
+		{"mismatched version", &fakeRPC{chainID: 56, domainSeparator: otherVersion.DomainSeparator(), eip712Domain: packEIP712Domain(t, otherVersion)}, true, `version "1", contract "2"`},
+		{"mismatched separator", &fakeRPC{chainID: 56, domainSeparator: make([]byte, 32)}, true, "contract separator"},
+		{"wrong chain", &fakeRPC{chainID: 1, domainSeparator: separator}, false, "RPC endpoint is on chain 1, want 56"},
+		{"no getters", &fakeRPC{chainID: 56}, false, "neither"},
+		{"salted contract", &fakeRPC{chainID: 56, eip712Domain: packEIP712Domain(t, &salted)}, true, "fields name,version,chainId,verifyingContract, contract name,version,chainId,verifyingContract,salt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dm.VerifyDomainAgainstChain(context.Background(), 56, tt.rpc)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("VerifyDomainAgainstChain() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("VerifyDomainAgainstChain() = %v, want error containing %q", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrDomainMismatch) != tt.wantMismatch {
+				t.Errorf("errors.Is(err, ErrDomainMismatch) = %v, want %v", !tt.wantMismatch, tt.wantMismatch)
+			}
+		})
+	}
+}
+
+func TestHTTPRPCClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch req.Method {
+		case "eth_chainId":
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":"0x38"}`)
+		case "eth_call":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":%q}`, hexutil.Encode([]byte{1, 2, 3}))
+		default:
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewHTTPRPCClient(server.URL)
+	chainID, err := client.ChainID(context.Background())
+	if err != nil || chainID != 56 {
+		t.Fatalf("ChainID() = %d, %v, want 56", chainID, err)
+	}
+	out, err := client.CallContract(context.Background(), common.HexToAddress(verifyContract), domainSeparatorSelector)
+	if err != nil || !bytes.Equal(out, []byte{1, 2, 3}) {
+		t.Errorf("CallContract() = %x, %v, want 010203", out, err)
+	}
+}
+
+func TestVerifyChainID(t *testing.T) {
+	if err := VerifyChainID(context.Background(), &fakeRPC{chainID: 56}, 56); err != nil {
+		t.Errorf("VerifyChainID() = %v, want nil", err)
+	}
+	err := VerifyChainID(context.Background(), &fakeRPC{chainID: 8453}, 56)
+	if !errors.Is(err, ErrChainIDMismatch) {
+		t.Errorf("VerifyChainID() = %v, want ErrChainIDMismatch", err)
+	}
+}
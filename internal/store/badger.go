@@ -0,0 +1,188 @@
+package store
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// Badger key layout: values are "v/<key>"; the records of a log are "l/<log>/" followed by
+// their sequence number as 8 big-endian bytes, so iterating the prefix replays them in order.
+// Log names cannot contain "/", so the prefix of one log never matches another's records.
+const (
+	badgerValuePrefix = "v/"
+	badgerLogPrefix   = "l/"
+)
+
+// Badger is a Store in a Badger database
+// Writes are committed before they return but synced to disk in the background; Close syncs them.
+type Badger struct {
+	db *badger.DB
+
+	mu     sync.RWMutex
+	closed bool
+	next   map[string]uint64 // Sequence number of the next record of each log appended to
+}
+
+// OpenBadger opens the store in the directory dir, creating it if needed
+func OpenBadger(dir string) (*Badger, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("store directory is required")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+	db, err := badger.Open(badger.DefaultOptions(dir).WithLogger(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open badger store: %w", err)
+	}
+	return &Badger{db: db, next: make(map[string]uint64)}, nil
+}
+
+// Get returns the value of key, false if it is not set
+func (b *Badger) Get(key string) ([]byte, bool, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return nil, false, ErrClosed
+	}
+	var value []byte
+	err := b.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(badgerValuePrefix + key))
+		if err != nil {
+			return err
+		}
+		value, err = item.ValueCopy(nil)
+		return err
+	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return value, true, nil
+}
+
+// Put sets the value of key
+func (b *Badger) Put(key string, value []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return ErrClosed
+	}
+	if err := b.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(badgerValuePrefix+key), append([]byte(nil), value...))
+	}); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes key
+func (b *Badger) Delete(key string) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return ErrClosed
+	}
+	if err := b.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(badgerValuePrefix + key))
+	}); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// Append adds a record to the end of the named log
+func (b *Badger) Append(log string, record []byte) error {
+	if err := checkLog(log); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	seq, ok := b.next[log]
+	if !ok {
+		var err error
+		if seq, err = b.lastSeq(log); err != nil {
+			return fmt.Errorf("failed to open log %s: %w", log, err)
+		}
+		seq++
+	}
+	if err := b.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(badgerRecordKey(log, seq), append([]byte(nil), record...))
+	}); err != nil {
+		return fmt.Errorf("failed to append to %s: %w", log, err)
+	}
+	b.next[log] = seq + 1
+	return nil
+}
+
+// lastSeq returns the sequence number of the last record of log, 0 if it has none
+func (b *Badger) lastSeq(log string) (uint64, error) {
+	var seq uint64
+	err := b.db.View(func(txn *badger.Txn) error {
+		prefix := []byte(badgerLogPrefix + log + "/")
+		it := txn.NewIterator(badger.IteratorOptions{Reverse: true, Prefix: prefix})
+		defer it.Close()
+		// Seeking in reverse finds the largest key at or below the given one
+		it.Seek(badgerRecordKey(log, ^uint64(0)))
+		if it.ValidForPrefix(prefix) {
+			seq = binary.BigEndian.Uint64(it.Item().Key()[len(prefix):])
+		}
+		return nil
+	})
+	return seq, err
+}
+
+// Replay calls fn with every record of the named log, oldest first
+func (b *Badger) Replay(log string, fn func(record []byte) error) error {
+	if err := checkLog(log); err != nil {
+		return err
+	}
+	b.mu.RLock()
+	closed := b.closed
+	b.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+
+	return b.db.View(func(txn *badger.Txn) error {
+		prefix := []byte(badgerLogPrefix + log + "/")
+		it := txn.NewIterator(badger.IteratorOptions{PrefetchValues: true, PrefetchSize: 100, Prefix: prefix})
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			record, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return fmt.Errorf("failed to read log %s: %w", log, err)
+			}
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close syncs and closes the database
+func (b *Badger) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	return errors.Join(b.db.Sync(), b.db.Close())
+}
+
+// badgerRecordKey returns the key of record seq of log
+func badgerRecordKey(log string, seq uint64) []byte {
+	return binary.BigEndian.AppendUint64([]byte(badgerLogPrefix+log+"/"), seq)
+}
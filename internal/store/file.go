@@ -0,0 +1,329 @@
+package store
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// File layout: values live in memory and in state.kv, a log of puts and deletes that is
+// compacted when the store opens; each append log is <name>.log. Every file is a sequence of
+// frames (uvarint length, payload, CRC-32 of the payload), so a frame torn by a crash is
+// detected and cut off instead of corrupting what follows.
+const (
+	kvFile    = "state.kv"
+	logSuffix = ".log"
+
+	opPut    byte = 'p'
+	opDelete byte = 'd'
+)
+
+// maxFrame bounds a frame's payload, so a corrupt length is not allocated
+const maxFrame = 64 << 20
+
+// File is a Store of append-only files in a directory
+// Writes reach the operating system before they return; Close syncs them to disk.
+type File struct {
+	dir string
+
+	mu     sync.RWMutex
+	values map[string][]byte
+	kv     *os.File
+	logs   map[string]*os.File // Logs opened for appending
+	closed bool
+}
+
+// OpenFile opens the store in dir, creating it if needed
+func OpenFile(dir string) (*File, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("store directory is required")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+	f := &File{
+		dir:    dir,
+		values: make(map[string][]byte),
+		logs:   make(map[string]*os.File),
+	}
+	if err := f.loadValues(); err != nil {
+		return nil, err
+	}
+	if err := f.compactValues(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// loadValues replays state.kv into memory
+func (f *File) loadValues() error {
+	file, err := os.Open(filepath.Join(f.dir, kvFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", kvFile, err)
+	}
+	defer file.Close()
+
+	_, err = readFrames(file, func(payload []byte) error {
+		key, value, op, err := decodeValue(payload)
+		if err != nil {
+			return err
+		}
+		if op == opDelete {
+			delete(f.values, key)
+		} else {
+			f.values[key] = value
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", kvFile, err)
+	}
+	return nil
+}
+
+// compactValues rewrites state.kv with one put per key and opens it for appending
+func (f *File) compactValues() error {
+	path := filepath.Join(f.dir, kvFile)
+	tmp, err := os.CreateTemp(f.dir, kvFile+".*")
+	if err != nil {
+		return fmt.Errorf("failed to compact %s: %w", kvFile, err)
+	}
+	defer os.Remove(tmp.Name()) // Fails harmlessly once renamed
+
+	w := bufio.NewWriter(tmp)
+	for key, value := range f.values {
+		if _, err := w.Write(frame(encodeValue(opPut, key, value))); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to compact %s: %w", kvFile, err)
+		}
+	}
+	if err := w.Flush(); err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to compact %s: %w", kvFile, err)
+	}
+
+	f.kv, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", kvFile, err)
+	}
+	return nil
+}
+
+// Get returns the value of key, false if it is not set
+func (f *File) Get(key string) ([]byte, bool, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.closed {
+		return nil, false, ErrClosed
+	}
+	value, ok := f.values[key]
+	if !ok {
+		return nil, false, nil
+	}
+	return append([]byte(nil), value...), true, nil
+}
+
+// Put sets the value of key
+func (f *File) Put(key string, value []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return ErrClosed
+	}
+	if _, err := f.kv.Write(frame(encodeValue(opPut, key, value))); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	f.values[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete removes key
+func (f *File) Delete(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return ErrClosed
+	}
+	if _, ok := f.values[key]; !ok {
+		return nil
+	}
+	if _, err := f.kv.Write(frame(encodeValue(opDelete, key, nil))); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	delete(f.values, key)
+	return nil
+}
+
+// Append adds a record to the end of the named log
+func (f *File) Append(log string, record []byte) error {
+	if err := checkLog(log); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return ErrClosed
+	}
+	file, ok := f.logs[log]
+	if !ok {
+		var err error
+		if file, err = f.openLog(log); err != nil {
+			return err
+		}
+		f.logs[log] = file
+	}
+	if _, err := file.Write(frame(record)); err != nil {
+		return fmt.Errorf("failed to append to %s: %w", log, err)
+	}
+	return nil
+}
+
+// openLog opens a log for appending, cutting off a frame torn by an earlier crash
+func (f *File) openLog(log string) (*os.File, error) {
+	file, err := os.OpenFile(filepath.Join(f.dir, log+logSuffix), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log %s: %w", log, err)
+	}
+	valid, err := readFrames(file, func([]byte) error { return nil })
+	if err == nil {
+		err = file.Truncate(valid)
+	}
+	if err == nil {
+		_, err = file.Seek(valid, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to open log %s: %w", log, err)
+	}
+	return file, nil
+}
+
+// Replay calls fn with every record of the named log, oldest first
+// A torn frame at the end of the log, left by a crash during Append, is skipped.
+func (f *File) Replay(log string, fn func(record []byte) error) error {
+	if err := checkLog(log); err != nil {
+		return err
+	}
+	f.mu.RLock()
+	closed := f.closed
+	f.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+
+	file, err := os.Open(filepath.Join(f.dir, log+logSuffix))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open log %s: %w", log, err)
+	}
+	defer file.Close()
+	_, err = readFrames(file, fn)
+	return err
+}
+
+// Close syncs and closes the store's files
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+
+	var errs []error
+	for _, file := range append([]*os.File{f.kv}, logFiles(f.logs)...) {
+		if err := file.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+		if err := file.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// logFiles returns the open log files
+func logFiles(logs map[string]*os.File) []*os.File {
+	files := make([]*os.File, 0, len(logs))
+	for _, file := range logs {
+		files = append(files, file)
+	}
+	return files
+}
+
+// frame encodes a payload as a frame
+func frame(payload []byte) []byte {
+	buf := binary.AppendUvarint(make([]byte, 0, len(payload)+binary.MaxVarintLen64+4), uint64(len(payload)))
+	buf = append(buf, payload...)
+	return binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(payload))
+}
+
+// readFrames calls fn with the payload of every frame of r
+// Reading stops at the first torn or corrupt frame; it returns the length of the valid frames.
+func readFrames(r io.Reader, fn func(payload []byte) error) (int64, error) {
+	br := bufio.NewReader(r)
+	var valid int64
+	for {
+		size, err := binary.ReadUvarint(br)
+		if err != nil || size > maxFrame {
+			return valid, nil // End of the file, or a torn length
+		}
+		buf := make([]byte, size+4)
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return valid, nil
+		}
+		payload := buf[:size]
+		if binary.BigEndian.Uint32(buf[size:]) != crc32.ChecksumIEEE(payload) {
+			return valid, nil
+		}
+		if err := fn(payload); err != nil {
+			return valid, err
+		}
+		valid += int64(uvarintLen(size)) + int64(size) + 4
+	}
+}
+
+// uvarintLen returns the encoded length of v
+func uvarintLen(v uint64) int {
+	var buf [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(buf[:], v)
+}
+
+// encodeValue encodes a put or delete of state.kv
+func encodeValue(op byte, key string, value []byte) []byte {
+	buf := append([]byte{op}, binary.AppendUvarint(nil, uint64(len(key)))...)
+	buf = append(buf, key...)
+	return append(buf, value...)
+}
+
+// decodeValue decodes a put or delete of state.kv
+func decodeValue(payload []byte) (key string, value []byte, op byte, err error) {
+	if len(payload) == 0 || (payload[0] != opPut && payload[0] != opDelete) {
+		return "", nil, 0, fmt.Errorf("invalid value entry")
+	}
+	size, n := binary.Uvarint(payload[1:])
+	if n <= 0 || uint64(len(payload)-1-n) < size {
+		return "", nil, 0, fmt.Errorf("invalid value entry")
+	}
+	rest := payload[1+n:]
+	return string(rest[:size]), append([]byte(nil), rest[size:]...), payload[0], nil
+}
@@ -0,0 +1,99 @@
+package store
+
+import "sync"
+
+// Memory is a Store kept in memory, for tests and deployments without persistence
+type Memory struct {
+	mu     sync.RWMutex
+	values map[string][]byte
+	logs   map[string][][]byte
+	closed bool
+}
+
+// NewMemory creates an empty in-memory store
+func NewMemory() *Memory {
+	return &Memory{
+		values: make(map[string][]byte),
+		logs:   make(map[string][][]byte),
+	}
+}
+
+// Get returns the value of key, false if it is not set
+func (m *Memory) Get(key string) ([]byte, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return nil, false, ErrClosed
+	}
+	value, ok := m.values[key]
+	if !ok {
+		return nil, false, nil
+	}
+	return append([]byte(nil), value...), true, nil
+}
+
+// Put sets the value of key
+func (m *Memory) Put(key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+	m.values[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete removes key
+func (m *Memory) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+	delete(m.values, key)
+	return nil
+}
+
+// Append adds a record to the end of the named log
+func (m *Memory) Append(log string, record []byte) error {
+	if err := checkLog(log); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrClosed
+	}
+	m.logs[log] = append(m.logs[log], append([]byte(nil), record...))
+	return nil
+}
+
+// Replay calls fn with every record of the named log, oldest first
+func (m *Memory) Replay(log string, fn func(record []byte) error) error {
+	if err := checkLog(log); err != nil {
+		return err
+	}
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return ErrClosed
+	}
+	records := m.logs[log]
+	m.mu.RUnlock()
+
+	// Records are never modified once appended, so fn runs without the lock
+	for _, record := range records {
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close releases the store
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return nil
+}
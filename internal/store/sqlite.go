@@ -0,0 +1,166 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	_ "modernc.org/sqlite" // Pure Go driver, registered as "sqlite"
+)
+
+// sqliteFile is the database file of the SQLite backend in its directory
+const sqliteFile = "state.db"
+
+// sqliteSchema creates the tables of values and log records; a record's id orders its log
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS kv (key TEXT PRIMARY KEY, value BLOB NOT NULL);
+CREATE TABLE IF NOT EXISTS logs (id INTEGER PRIMARY KEY AUTOINCREMENT, log TEXT NOT NULL, record BLOB NOT NULL);
+CREATE INDEX IF NOT EXISTS logs_by_name ON logs (log, id);
+`
+
+// SQLite is a Store in a SQLite database
+// The database runs in WAL mode with synchronous=NORMAL: a write is durable once the WAL is
+// checkpointed or synced, and a crash loses at most the last transactions, never the file.
+type SQLite struct {
+	db *sql.DB
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// OpenSQLite opens the store in dir/state.db, creating it if needed
+func OpenSQLite(dir string) (*SQLite, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("store directory is required")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+	dsn := "file:" + filepath.Join(dir, sqliteFile) +
+		"?_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=busy_timeout(5000)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", sqliteFile, err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create the tables of %s: %w", sqliteFile, err)
+	}
+	return &SQLite{db: db}, nil
+}
+
+// Get returns the value of key, false if it is not set
+func (s *SQLite) Get(key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, false, ErrClosed
+	}
+	var value []byte
+	err := s.db.QueryRow(`SELECT value FROM kv WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return value, true, nil
+}
+
+// Put sets the value of key
+func (s *SQLite) Put(key string, value []byte) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrClosed
+	}
+	if _, err := s.db.Exec(`INSERT INTO kv (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value`, key, nonNil(value)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes key
+func (s *SQLite) Delete(key string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrClosed
+	}
+	if _, err := s.db.Exec(`DELETE FROM kv WHERE key = ?`, key); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// Append adds a record to the end of the named log
+func (s *SQLite) Append(log string, record []byte) error {
+	if err := checkLog(log); err != nil {
+		return err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrClosed
+	}
+	if _, err := s.db.Exec(`INSERT INTO logs (log, record) VALUES (?, ?)`, log, nonNil(record)); err != nil {
+		return fmt.Errorf("failed to append to %s: %w", log, err)
+	}
+	return nil
+}
+
+// Replay calls fn with every record of the named log, oldest first
+func (s *SQLite) Replay(log string, fn func(record []byte) error) error {
+	if err := checkLog(log); err != nil {
+		return err
+	}
+	s.mu.RLock()
+	closed := s.closed
+	s.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+
+	rows, err := s.db.Query(`SELECT record FROM logs WHERE log = ? ORDER BY id`, log)
+	if err != nil {
+		return fmt.Errorf("failed to read log %s: %w", log, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var record []byte
+		if err := rows.Scan(&record); err != nil {
+			return fmt.Errorf("failed to read log %s: %w", log, err)
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read log %s: %w", log, err)
+	}
+	return nil
+}
+
+// Close checkpoints and closes the database
+func (s *SQLite) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	_, err := s.db.Exec(`PRAGMA wal_checkpoint(TRUNCATE)`)
+	return errors.Join(err, s.db.Close())
+}
+
+// nonNil returns b, or an empty slice for nil: a nil []byte is stored as NULL
+func nonNil(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// Store is the embedded state of the market maker
+// It holds small values by key (counters, cursors) and append-only logs (journals, audit
+// trails), so modules that persist state share one backend instead of each writing files.
+// Implementations are safe for concurrent use.
+type Store interface {
+	// Get returns the value of key, false if it is not set
+	Get(key string) ([]byte, bool, error)
+	// Put sets the value of key
+	Put(key string, value []byte) error
+	// Delete removes key; deleting a missing key is not an error
+	Delete(key string) error
+	// Append adds a record to the end of the named log
+	Append(log string, record []byte) error
+	// Replay calls fn with every record of the named log, oldest first
+	// A log never appended to replays nothing. An error from fn stops the replay and is returned.
+	Replay(log string, fn func(record []byte) error) error
+	// Close flushes and releases the store
+	Close() error
+}
+
+// Backends
+const (
+	BackendMemory = "memory" // State is lost on restart
+	BackendFile   = "file"   // Append-only files in a directory
+	BackendSQLite = "sqlite" // SQLite database state.db in a directory
+	BackendBadger = "badger" // Badger database in a directory
+)
+
+// Persistent reports whether a backend keeps its state across restarts
+func Persistent(backend string) bool {
+	return backend == BackendFile || backend == BackendSQLite || backend == BackendBadger
+}
+
+// ErrClosed is returned by a store after Close
+var ErrClosed = errors.New("store closed")
+
+// logName is the allowed form of log names, which are also file names
+var logName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// checkLog validates a log name
+func checkLog(log string) error {
+	if !logName.MatchString(log) {
+		return fmt.Errorf("invalid log name %q", log)
+	}
+	return nil
+}
+
+// Open opens the store of a backend
+// path is the directory of the persistent backends and is ignored by the memory backend.
+func Open(backend, path string) (Store, error) {
+	switch backend {
+	case BackendMemory, "":
+		return NewMemory(), nil
+	case BackendFile:
+		return OpenFile(path)
+	case BackendSQLite:
+		return OpenSQLite(path)
+	case BackendBadger:
+		return OpenBadger(path)
+	default:
+		return nil, fmt.Errorf("unknown store backend %q", backend)
+	}
+}
@@ -0,0 +1,191 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore_Backends(t *testing.T) {
+	backends := map[string]func(t *testing.T) Store{
+		BackendMemory: func(t *testing.T) Store { return NewMemory() },
+		BackendFile: func(t *testing.T) Store {
+			s, err := OpenFile(t.TempDir())
+			if err != nil {
+				t.Fatalf("OpenFile failed: %v", err)
+			}
+			return s
+		},
+		BackendSQLite: func(t *testing.T) Store {
+			s, err := OpenSQLite(t.TempDir())
+			if err != nil {
+				t.Fatalf("OpenSQLite failed: %v", err)
+			}
+			return s
+		},
+		BackendBadger: func(t *testing.T) Store {
+			s, err := OpenBadger(t.TempDir())
+			if err != nil {
+				t.Fatalf("OpenBadger failed: %v", err)
+			}
+			return s
+		},
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			s := open(t)
+			defer s.Close()
+
+			if _, ok, err := s.Get("missing"); ok || err != nil {
+				t.Errorf("Get(missing) = %v, %v, want unset", ok, err)
+			}
+			if err := s.Put("k", []byte("v1")); err != nil {
+				t.Fatalf("Put failed: %v", err)
+			}
+			if err := s.Put("k", []byte("v2")); err != nil {
+				t.Fatalf("Put failed: %v", err)
+			}
+			if got, ok, _ := s.Get("k"); !ok || string(got) != "v2" {
+				t.Errorf("Get(k) = %q, %v, want v2", got, ok)
+			}
+			if err := s.Delete("k"); err != nil {
+				t.Fatalf("Delete failed: %v", err)
+			}
+			if _, ok, _ := s.Get("k"); ok {
+				t.Error("Get(k) after Delete is set")
+			}
+
+			for _, record := range []string{"a", "", "c"} {
+				if err := s.Append("events", []byte(record)); err != nil {
+					t.Fatalf("Append failed: %v", err)
+				}
+			}
+			if got := replay(t, s, "events"); len(got) != 3 || got[0] != "a" || got[1] != "" || got[2] != "c" {
+				t.Errorf("Replay = %q, want [a  c]", got)
+			}
+			if got := replay(t, s, "other"); len(got) != 0 {
+				t.Errorf("Replay of an empty log = %q, want none", got)
+			}
+			if err := s.Append("../escape", nil); err == nil {
+				t.Error("Append accepted a path as log name")
+			}
+
+			stop := errors.New("stop")
+			if err := s.Replay("events", func([]byte) error { return stop }); !errors.Is(err, stop) {
+				t.Errorf("Replay error = %v, want %v", err, stop)
+			}
+
+			s.Close()
+			if err := s.Put("k", nil); !errors.Is(err, ErrClosed) {
+				t.Errorf("Put after Close = %v, want %v", err, ErrClosed)
+			}
+		})
+	}
+}
+
+func TestFile_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenFile(dir)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	s.Put("kept", []byte("1"))
+	s.Put("deleted", []byte("2"))
+	s.Delete("deleted")
+	s.Append("events", []byte("first"))
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// A crash during Append leaves a torn frame at the end of the log
+	f, err := os.OpenFile(filepath.Join(dir, "events.log"), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("open log: %v", err)
+	}
+	f.Write(frame([]byte("torn"))[:3])
+	f.Close()
+
+	s, err = OpenFile(dir)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	defer s.Close()
+	if got, ok, _ := s.Get("kept"); !ok || string(got) != "1" {
+		t.Errorf("Get(kept) = %q, %v, want 1", got, ok)
+	}
+	if _, ok, _ := s.Get("deleted"); ok {
+		t.Error("deleted key is set after reopening")
+	}
+	if got := replay(t, s, "events"); len(got) != 1 || got[0] != "first" {
+		t.Errorf("Replay = %q, want [first]", got)
+	}
+
+	// Appending after the torn frame keeps the log readable
+	s.Append("events", []byte("second"))
+	if got := replay(t, s, "events"); len(got) != 2 || got[1] != "second" {
+		t.Errorf("Replay = %q, want [first second]", got)
+	}
+}
+
+// TestStore_ReopenDatabases checks the database backends keep values and logs across a reopen,
+// and that appends after it continue the logs
+func TestStore_ReopenDatabases(t *testing.T) {
+	for _, backend := range []string{BackendSQLite, BackendBadger} {
+		t.Run(backend, func(t *testing.T) {
+			dir := t.TempDir()
+			s, err := Open(backend, dir)
+			if err != nil {
+				t.Fatalf("Open failed: %v", err)
+			}
+			s.Put("kept", []byte("1"))
+			s.Append("events", []byte("first"))
+			s.Append("events", []byte("second"))
+			if err := s.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			s, err = Open(backend, dir)
+			if err != nil {
+				t.Fatalf("reopen failed: %v", err)
+			}
+			defer s.Close()
+			if got, ok, _ := s.Get("kept"); !ok || string(got) != "1" {
+				t.Errorf("Get(kept) = %q, %v, want 1", got, ok)
+			}
+			s.Append("events", []byte("third"))
+			s.Append("event", []byte("other log")) // Prefix of "events"
+			if got := replay(t, s, "events"); len(got) != 3 || got[0] != "first" || got[2] != "third" {
+				t.Errorf("Replay = %q, want [first second third]", got)
+			}
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if _, err := Open("leveldb", ""); err == nil {
+		t.Error("Open accepted an unknown backend")
+	}
+	for _, backend := range []string{BackendFile, BackendSQLite, BackendBadger} {
+		if _, err := Open(backend, ""); err == nil {
+			t.Errorf("Open(%s) accepted an empty path", backend)
+		}
+		s, err := Open(backend, t.TempDir())
+		if err != nil {
+			t.Fatalf("Open(%s) failed: %v", backend, err)
+		}
+		s.Close()
+	}
+}
+
+func replay(t *testing.T, s Store, log string) []string {
+	t.Helper()
+	var records []string
+	if err := s.Replay(log, func(record []byte) error {
+		records = append(records, string(record))
+		return nil
+	}); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	return records
+}
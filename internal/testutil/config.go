@@ -0,0 +1,41 @@
+package testutil
+
+import (
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+)
+
+// Config returns a minimal valid config for BSC WBNB-USDT that matches the message builder defaults
+// Depth pushing is disabled so tests control every message sent
+func Config() *config.Config {
+	return &config.Config{
+		App: config.AppConfig{Name: "test"},
+		Signer: config.SignerConfig{
+			PrivateKey: DefaultPrivKey,
+		},
+		WebSocket: config.WebSocketConfig{
+			ServerURL:         "ws://127.0.0.1:0/ws",
+			APIToken:          "test-token",
+			ReconnectInterval: time.Second,
+			HeartbeatInterval: 30 * time.Second,
+			ReadTimeout:       2 * time.Second,
+			WriteTimeout:      5 * time.Second,
+		},
+		EIP712Domains: []config.EIP712Domain{
+			{ChainID: DefaultChainID, Name: "RFQ Manager", Version: "1", VerifyingContract: "0x28D3a265f6d40867986004029ee91F4C9532fCC5"},
+		},
+		Quote: config.QuoteConfig{ValidDuration: 30 * time.Second, StoreRetention: time.Minute, LatencyBudget: 200 * time.Millisecond},
+		Depth: config.DepthConfig{Enabled: false, PushInterval: time.Second},
+		Pairs: []config.PairConfig{
+			{
+				ChainID:            DefaultChainID,
+				PairID:             "WBNB-USDT",
+				BaseToken:          DefaultTokenIn,
+				QuoteToken:         DefaultTokenOut,
+				BaseTokenDecimals:  18,
+				QuoteTokenDecimals: 18,
+			},
+		},
+	}
+}
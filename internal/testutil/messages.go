@@ -0,0 +1,101 @@
+package testutil
+
+import (
+	"time"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// Default addresses used by the message builders
+const (
+	DefaultMMID      = "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf" // Address of private key 1
+	DefaultPrivKey   = "0x0000000000000000000000000000000000000000000000000000000000000001"
+	DefaultChainID   = uint64(56)
+	DefaultTokenIn   = "0xbb4cdb9cbd36b01bd1cbaebf2de08d9173bc095c" // WBNB
+	DefaultTokenOut  = "0x55d398326f99059ff775485246999027b3197955" // USDT
+	DefaultUser      = "0x1234567890123456789012345678901234567890"
+	DefaultAmountIn  = "1000000000000000000"
+	DefaultQuoteID   = "quote-1"
+	DefaultSessionID = "test-session"
+)
+
+// NewConnectionAck builds a successful ConnectionAck
+func NewConnectionAck(mmID string) *mmv1.Message {
+	return &mmv1.Message{
+		Type:      mmv1.MessageType_MESSAGE_TYPE_CONNECTION_ACK,
+		Timestamp: time.Now().UnixMilli(),
+		Payload: &mmv1.Message_ConnectionAck{
+			ConnectionAck: &mmv1.ConnectionAck{
+				Success:    true,
+				SessionId:  DefaultSessionID,
+				ServerTime: time.Now().UnixMilli(),
+				MmId:       mmID,
+			},
+		},
+	}
+}
+
+// NewPing builds a heartbeat ping
+func NewPing() *mmv1.Message {
+	return &mmv1.Message{
+		Type:      mmv1.MessageType_MESSAGE_TYPE_HEARTBEAT,
+		Timestamp: time.Now().UnixMilli(),
+		Payload: &mmv1.Message_Heartbeat{
+			Heartbeat: &mmv1.Heartbeat{Ping: true},
+		},
+	}
+}
+
+// NewPong builds a heartbeat pong
+func NewPong() *mmv1.Message {
+	return &mmv1.Message{
+		Type:      mmv1.MessageType_MESSAGE_TYPE_HEARTBEAT,
+		Timestamp: time.Now().UnixMilli(),
+		Payload: &mmv1.Message_Heartbeat{
+			Heartbeat: &mmv1.Heartbeat{Pong: true},
+		},
+	}
+}
+
+// NewQuoteRequest wraps a quote request in a message
+func NewQuoteRequest(req *mmv1.QuoteRequest) *mmv1.Message {
+	return &mmv1.Message{
+		Type:      mmv1.MessageType_MESSAGE_TYPE_QUOTE_REQUEST,
+		Timestamp: time.Now().UnixMilli(),
+		Payload: &mmv1.Message_QuoteRequest{
+			QuoteRequest: req,
+		},
+	}
+}
+
+// QuoteRequest returns a valid quote request with default fields and a 30s deadline
+// Callers override fields as needed before wrapping it with NewQuoteRequest
+func QuoteRequest() *mmv1.QuoteRequest {
+	return &mmv1.QuoteRequest{
+		QuoteId:   DefaultQuoteID,
+		ChainId:   DefaultChainID,
+		MmId:      DefaultMMID,
+		TokenIn:   DefaultTokenIn,
+		TokenOut:  DefaultTokenOut,
+		AmountIn:  DefaultAmountIn,
+		From:      DefaultUser,
+		Recipient: DefaultUser,
+		Nonce:     "1",
+		Deadline:  time.Now().Add(30 * time.Second).Unix(),
+	}
+}
+
+// NewError builds a server error message
+func NewError(code mmv1.ErrorCode, message, relatedQuoteID string) *mmv1.Message {
+	return &mmv1.Message{
+		Type:      mmv1.MessageType_MESSAGE_TYPE_ERROR,
+		Timestamp: time.Now().UnixMilli(),
+		Payload: &mmv1.Message_Error{
+			Error: &mmv1.Error{
+				Code:           code,
+				Message:        message,
+				RelatedQuoteId: relatedQuoteID,
+			},
+		},
+	}
+}
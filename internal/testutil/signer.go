@@ -0,0 +1,70 @@
+package testutil
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/signer"
+)
+
+// SignCall is one recorded FakeSigner.SignMMQuote call
+type SignCall struct {
+	ChainID uint64
+	Quote   signer.MMQuote
+}
+
+// FakeSigner is a scriptable signer.Signer
+// By default it returns a fixed 65-byte signature; set SignFunc to script results
+type FakeSigner struct {
+	Address common.Address
+
+	// SignFunc, when set, replaces the default signature
+	SignFunc func(chainID uint64, quote *signer.MMQuote) ([]byte, error)
+
+	mu    sync.Mutex
+	calls []SignCall
+}
+
+// NewFakeSigner creates a fake signer with the given address
+func NewFakeSigner(address common.Address) *FakeSigner {
+	return &FakeSigner{Address: address}
+}
+
+// SignMMQuote records the call and returns the scripted signature
+func (s *FakeSigner) SignMMQuote(chainID uint64, quote *signer.MMQuote) ([]byte, error) {
+	s.mu.Lock()
+	s.calls = append(s.calls, SignCall{ChainID: chainID, Quote: *quote})
+	signFunc := s.SignFunc
+	s.mu.Unlock()
+
+	if signFunc != nil {
+		return signFunc(chainID, quote)
+	}
+	return FixedSignature(), nil
+}
+
+// GetAddress returns the signer address
+func (s *FakeSigner) GetAddress() common.Address {
+	return s.Address
+}
+
+// Calls returns all recorded sign calls
+func (s *FakeSigner) Calls() []SignCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]SignCall, len(s.calls))
+	copy(out, s.calls)
+	return out
+}
+
+// FixedSignature returns a well-formed 65-byte signature (r = s = 0x01..., v = 27)
+func FixedSignature() []byte {
+	sig := make([]byte, 65)
+	for i := 0; i < 64; i++ {
+		sig[i] = 0x01
+	}
+	sig[64] = 27
+	return sig
+}
@@ -0,0 +1,116 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+)
+
+// FixedRateStrategy is a deterministic quote.QuoteStrategy
+// amountOut = amountIn * Numerator / Denominator using integer math, for every pair
+type FixedRateStrategy struct {
+	Numerator   *big.Int
+	Denominator *big.Int
+	Err         error // Returned instead of a quote when set
+
+	mu    sync.Mutex
+	calls []quote.QuoteParams
+}
+
+// NewFixedRateStrategy creates a fixed rate strategy
+func NewFixedRateStrategy(numerator, denominator int64) *FixedRateStrategy {
+	return &FixedRateStrategy{
+		Numerator:   big.NewInt(numerator),
+		Denominator: big.NewInt(denominator),
+	}
+}
+
+// CalculateQuote returns amountIn * Numerator / Denominator
+func (s *FixedRateStrategy) CalculateQuote(ctx context.Context, params *quote.QuoteParams) (*quote.QuoteResult, error) {
+	s.mu.Lock()
+	s.calls = append(s.calls, *params)
+	s.mu.Unlock()
+
+	if s.Err != nil {
+		return nil, s.Err
+	}
+
+	amountOut := new(big.Int).Mul(params.AmountIn, s.Numerator)
+	amountOut.Quo(amountOut, s.Denominator)
+	if amountOut.Sign() <= 0 {
+		return nil, fmt.Errorf("calculated amount out is zero or negative")
+	}
+
+	result := quote.NewQuoteResult(amountOut)
+	result.ExecutionPrice = decimal.NewFromBigInt(s.Numerator).Quo(decimal.NewFromBigInt(s.Denominator))
+	return result, nil
+}
+
+// Calls returns all recorded quote params
+func (s *FixedRateStrategy) Calls() []quote.QuoteParams {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]quote.QuoteParams, len(s.calls))
+	copy(out, s.calls)
+	return out
+}
+
+// StaticDepthProvider is a deterministic depth.DepthProvider
+// It returns the order book registered for a chain/pair
+type StaticDepthProvider struct {
+	mu    sync.RWMutex
+	books map[string]*depth.OrderBook
+	Err   error // Returned for every request when set
+}
+
+// NewStaticDepthProvider creates an empty static provider
+func NewStaticDepthProvider() *StaticDepthProvider {
+	return &StaticDepthProvider{books: make(map[string]*depth.OrderBook)}
+}
+
+// SetBook registers the order book returned for a chain/pair
+func (p *StaticDepthProvider) SetBook(chainID uint64, pairID string, ob *depth.OrderBook) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.books[fmt.Sprintf("%d:%s", chainID, pairID)] = ob
+}
+
+// GetDepth returns the registered order book
+func (p *StaticDepthProvider) GetDepth(chainID uint64, pairID string) (*depth.OrderBook, error) {
+	if p.Err != nil {
+		return nil, p.Err
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	ob, ok := p.books[fmt.Sprintf("%d:%s", chainID, pairID)]
+	if !ok {
+		return nil, fmt.Errorf("no book configured for chain %d pair %s", chainID, pairID)
+	}
+	return ob, nil
+}
+
+// LinearBook builds an order book with evenly spaced levels around mid
+// step is the relative distance between levels (e.g. 0.001 = 10 bps), amount is per level
+func LinearBook(baseToken, quoteToken common.Address, mid float64, step float64, levels int, amount *big.Int) *depth.OrderBook {
+	ob := depth.NewOrderBook(strings.ToLower(baseToken.Hex()), strings.ToLower(quoteToken.Hex()))
+	ob.MidPrice = decimal.NewFromFloat(mid)
+	one := decimal.NewFromInt(1)
+	for i := 1; i <= levels; i++ {
+		offset := decimal.NewFromFloat(step).Mul(decimal.NewFromInt(int64(i)))
+		ob.Asks = append(ob.Asks, depth.NewPriceLevel(ob.MidPrice.Mul(one.Add(offset)), new(big.Int).Set(amount)))
+		ob.Bids = append(ob.Bids, depth.NewPriceLevel(ob.MidPrice.Mul(one.Sub(offset)), new(big.Int).Set(amount)))
+	}
+	ob.Spread = 2 * step * 100
+	return ob
+}
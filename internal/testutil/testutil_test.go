@@ -0,0 +1,91 @@
+package testutil
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+func TestFakes_QuotePipeline(t *testing.T) {
+	cfg := Config()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	client := NewFakeWSClient()
+	fakeSigner := NewFakeSigner(common.HexToAddress(DefaultMMID))
+	strategy := NewFixedRateStrategy(600, 1)
+	handler := quote.NewHandler(strategy, fakeSigner, cfg, logger)
+	pusher := depth.NewPusher(client, NewStaticDepthProvider(), handler, fakeSigner, cfg, logger)
+
+	if err := pusher.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer pusher.Stop()
+
+	if err := client.Deliver(NewConnectionAck(DefaultMMID)); err != nil {
+		t.Fatalf("Deliver ack failed: %v", err)
+	}
+	if err := client.Deliver(NewQuoteRequest(QuoteRequest())); err != nil {
+		t.Fatalf("Deliver quote request failed: %v", err)
+	}
+
+	responses := client.SentOfType(mmv1.MessageType_MESSAGE_TYPE_QUOTE_RESPONSE)
+	if len(responses) != 1 {
+		t.Fatalf("Quote responses = %d, want 1", len(responses))
+	}
+	order := responses[0].GetQuoteResponse().GetOrder()
+	if order.AmountOut != "600000000000000000000" {
+		t.Errorf("AmountOut = %s, want 600000000000000000000", order.AmountOut)
+	}
+	if len(order.Signature) != 65 {
+		t.Errorf("Signature length = %d, want 65", len(order.Signature))
+	}
+	if len(fakeSigner.Calls()) != 1 {
+		t.Errorf("Sign calls = %d, want 1", len(fakeSigner.Calls()))
+	}
+	if len(strategy.Calls()) != 1 {
+		t.Errorf("Strategy calls = %d, want 1", len(strategy.Calls()))
+	}
+}
+
+func TestFixedRateStrategy(t *testing.T) {
+	strategy := NewFixedRateStrategy(3, 2)
+	result, err := strategy.CalculateQuote(context.Background(), &quote.QuoteParams{AmountIn: big.NewInt(1000)})
+	if err != nil {
+		t.Fatalf("CalculateQuote failed: %v", err)
+	}
+	if result.AmountOut.Cmp(big.NewInt(1500)) != 0 {
+		t.Errorf("AmountOut = %v, want 1500", result.AmountOut)
+	}
+}
+
+func TestStaticDepthProvider(t *testing.T) {
+	provider := NewStaticDepthProvider()
+	if _, err := provider.GetDepth(56, "WBNB-USDT"); err == nil {
+		t.Error("Expected error for unregistered pair")
+	}
+
+	book := LinearBook(common.HexToAddress(DefaultTokenIn), common.HexToAddress(DefaultTokenOut), 600, 0.001, 3, big.NewInt(1e18))
+	provider.SetBook(56, "WBNB-USDT", book)
+
+	ob, err := provider.GetDepth(56, "WBNB-USDT")
+	if err != nil {
+		t.Fatalf("GetDepth failed: %v", err)
+	}
+	if len(ob.Asks) != 3 || len(ob.Bids) != 3 {
+		t.Errorf("Levels = %d/%d, want 3/3", len(ob.Asks), len(ob.Bids))
+	}
+	if ob.Asks[0].Price.Cmp(ob.Asks[1].Price) >= 0 {
+		t.Error("Asks should be ascending")
+	}
+	if ob.Bids[0].Price.Cmp(ob.Bids[1].Price) <= 0 {
+		t.Error("Bids should be descending")
+	}
+}
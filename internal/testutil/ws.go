@@ -0,0 +1,164 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/ws"
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// FakeWSClient is an in-memory ws.WSClient
+// Sent messages are recorded, and server messages are injected with Deliver
+type FakeWSClient struct {
+	mu                 sync.Mutex
+	state              ws.ConnectionState
+	sent               []*mmv1.Message
+	handler            ws.MessageHandler
+	reconnectedHandler ws.ReconnectedHandler
+	reconnects         int
+
+	// SendErr, when set, is returned by Send and SendBatch instead of recording
+	SendErr error
+}
+
+// NewFakeWSClient creates an in-memory client in Disconnected state
+func NewFakeWSClient() *FakeWSClient {
+	return &FakeWSClient{state: ws.StateDisconnected}
+}
+
+// Connect moves the client to Connected state
+func (c *FakeWSClient) Connect(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state != ws.StateDisconnected {
+		return fmt.Errorf("client already connected or connecting")
+	}
+	c.state = ws.StateConnected
+	return nil
+}
+
+// Close moves the client to Disconnected state
+func (c *FakeWSClient) Close() error {
+	c.SetState(ws.StateDisconnected)
+	return nil
+}
+
+// Send records a message
+func (c *FakeWSClient) Send(msg *mmv1.Message) error {
+	return c.SendBatch([]*mmv1.Message{msg})
+}
+
+// SendBatch records several messages
+func (c *FakeWSClient) SendBatch(msgs []*mmv1.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.SendErr != nil {
+		return c.SendErr
+	}
+	if c.state != ws.StateConnected && c.state != ws.StateReady {
+		return fmt.Errorf("websocket not connected")
+	}
+	// Clone: like the real client, callers may reuse a message once it is sent
+	for _, msg := range msgs {
+		c.sent = append(c.sent, proto.Clone(msg).(*mmv1.Message))
+	}
+	return nil
+}
+
+// SetMessageHandler sets the message handler callback
+func (c *FakeWSClient) SetMessageHandler(handler ws.MessageHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handler = handler
+}
+
+// SetReconnectedHandler sets the reconnection success callback
+func (c *FakeWSClient) SetReconnectedHandler(handler ws.ReconnectedHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reconnectedHandler = handler
+}
+
+// IsConnected checks if connected
+func (c *FakeWSClient) IsConnected() bool {
+	state := c.GetState()
+	return state == ws.StateConnected || state == ws.StateReady
+}
+
+// GetState gets current connection state
+func (c *FakeWSClient) GetState() ws.ConnectionState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// SetState sets connection state
+func (c *FakeWSClient) SetState(state ws.ConnectionState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state = state
+}
+
+// TriggerReconnect counts the request and immediately invokes the reconnected handler
+func (c *FakeWSClient) TriggerReconnect() {
+	c.mu.Lock()
+	c.reconnects++
+	handler := c.reconnectedHandler
+	c.state = ws.StateConnected
+	c.mu.Unlock()
+
+	if handler != nil {
+		handler()
+	}
+}
+
+// Deliver passes a server message to the message handler, as the read loop would
+func (c *FakeWSClient) Deliver(msg *mmv1.Message) error {
+	c.mu.Lock()
+	handler := c.handler
+	c.mu.Unlock()
+
+	if handler == nil {
+		return fmt.Errorf("no message handler set")
+	}
+	return handler(msg)
+}
+
+// Sent returns all recorded messages
+func (c *FakeWSClient) Sent() []*mmv1.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]*mmv1.Message, len(c.sent))
+	copy(out, c.sent)
+	return out
+}
+
+// SentOfType returns recorded messages of the given type
+func (c *FakeWSClient) SentOfType(t mmv1.MessageType) []*mmv1.Message {
+	out := make([]*mmv1.Message, 0)
+	for _, msg := range c.Sent() {
+		if msg.Type == t {
+			out = append(out, msg)
+		}
+	}
+	return out
+}
+
+// Reset drops all recorded messages
+func (c *FakeWSClient) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = nil
+}
+
+// Reconnects returns how many times TriggerReconnect was called
+func (c *FakeWSClient) Reconnects() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reconnects
+}
@@ -0,0 +1,352 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/address"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/decimal"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/depth"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+)
+
+// LargeQuote is the event of a signed quote at or above its pair's webhook.largeQuotes notional
+// It is delivered in addition to quote_signed.
+const LargeQuote = "large_quote"
+
+// Request headers
+const (
+	HeaderEvent     = "X-MM-Event"     // Event kind
+	HeaderDelivery  = "X-MM-Delivery"  // Delivery ID, the same on every retry of an event
+	HeaderTimestamp = "X-MM-Timestamp" // Unix seconds of the attempt
+	HeaderSignature = "X-MM-Signature" // "sha256=" + hex HMAC-SHA256 of "<timestamp>.<body>"
+)
+
+// endpointQueue is the number of events waiting for delivery to one endpoint
+const endpointQueue = 256
+
+// maxRetryBackoff caps the wait between retries
+const maxRetryBackoff = time.Minute
+
+// Payload is the JSON body of a webhook request
+type Payload struct {
+	ID      string    `json:"id"`
+	Event   string    `json:"event"`
+	Time    time.Time `json:"time"`
+	ChainID uint64    `json:"chainId,omitempty"`
+	PairID  string    `json:"pairId,omitempty"`
+	QuoteID string    `json:"quoteId,omitempty"`
+	Detail  string    `json:"detail,omitempty"`
+	Data    any       `json:"data,omitempty"`
+}
+
+// Quote is the data of quote_signed, large_quote and fill_observed payloads
+type Quote struct {
+	TokenIn   string `json:"tokenIn"`
+	TokenOut  string `json:"tokenOut"`
+	AmountIn  string `json:"amountIn"`
+	AmountOut string `json:"amountOut"`
+	Nonce     string `json:"nonce"`
+	Deadline  int64  `json:"deadline"`
+	State     string `json:"state"`
+	Signer    string `json:"signer"`
+	Notional  string `json:"notional,omitempty"` // Quote token units, for large_quote
+}
+
+// Inconsistency is the data of consistency risk_breach payloads
+type Inconsistency struct {
+	Side         string  `json:"side"`
+	Level        int     `json:"level"`
+	AmountIn     string  `json:"amountIn"`
+	Advertised   string  `json:"advertised"`
+	Quoted       string  `json:"quoted,omitempty"`
+	DeviationBps float64 `json:"deviationBps"`
+	Error        string  `json:"error,omitempty"`
+}
+
+// Sign returns the X-MM-Signature value of a request body
+// Receivers recompute it with their copy of the secret and reject stale timestamps.
+func Sign(secret []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// endpoint is a webhook URL and its delivery queue
+type endpoint struct {
+	url    string
+	secret []byte
+	events []string // Empty = all
+	queue  chan Payload
+}
+
+// wants reports whether the endpoint subscribed to an event
+func (e *endpoint) wants(event string) bool {
+	return len(e.events) == 0 || slices.Contains(e.events, event)
+}
+
+// Dispatcher delivers bus events to the webhook endpoints
+// Each endpoint has its own queue and delivery goroutine, so a slow or failing endpoint
+// delays only its own events. Events are dropped when an endpoint's queue is full.
+type Dispatcher struct {
+	cfg       *config.Config
+	endpoints []*endpoint
+	large     map[string]decimal.Decimal // Large quote notional by pair ID
+	client    *http.Client
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// NewDispatcher creates a dispatcher of the webhook configuration
+func NewDispatcher(cfg *config.Config, logger *slog.Logger) (*Dispatcher, error) {
+	d := &Dispatcher{
+		cfg:    cfg,
+		large:  make(map[string]decimal.Decimal, len(cfg.Webhook.LargeQuotes)),
+		client: &http.Client{Timeout: cfg.Webhook.Timeout},
+		logger: logger.With("component", "Webhook"),
+		now:    time.Now,
+	}
+	for i, e := range cfg.Webhook.Endpoints {
+		secret := e.Secret
+		if secret == "" {
+			secret = strings.TrimSpace(os.Getenv(e.SecretEnv))
+		}
+		if secret == "" {
+			return nil, fmt.Errorf("webhook.endpoints[%d]: environment variable %s is not set", i, e.SecretEnv)
+		}
+		d.endpoints = append(d.endpoints, &endpoint{
+			url:    e.URL,
+			secret: []byte(secret),
+			events: e.Events,
+			queue:  make(chan Payload, endpointQueue),
+		})
+	}
+	for pairID, threshold := range cfg.Webhook.LargeQuotes {
+		if t, err := decimal.Parse(threshold); err == nil {
+			d.large[pairID] = t
+		}
+	}
+	return d, nil
+}
+
+// Start subscribes to bus and delivers events until ctx is done
+func (d *Dispatcher) Start(ctx context.Context, bus *events.Bus) {
+	sub := bus.Subscribe("webhook", 0)
+	go sub.Run(ctx, d.dispatch)
+	for _, e := range d.endpoints {
+		go d.deliverLoop(ctx, e)
+	}
+}
+
+// dispatch queues an event, and the large_quote it implies, for the endpoints subscribed to it
+func (d *Dispatcher) dispatch(e events.Event) {
+	payload := Payload{
+		ID:      newDeliveryID(),
+		Event:   string(e.Kind),
+		Time:    e.Time,
+		ChainID: e.ChainID,
+		PairID:  e.PairID,
+		QuoteID: e.QuoteID,
+		Detail:  e.Detail,
+		Data:    payloadData(e.Data),
+	}
+	d.enqueue(payload)
+
+	if e.Kind != events.QuoteSigned {
+		return
+	}
+	rec, ok := e.Data.(quote.QuoteRecord)
+	if !ok {
+		return
+	}
+	if size, ok := d.largeQuote(e.PairID, rec); ok {
+		large := payload
+		large.ID, large.Event = newDeliveryID(), LargeQuote
+		data := quoteData(rec)
+		data.Notional = size.String()
+		large.Data = data
+		d.enqueue(large)
+	}
+}
+
+// largeQuote returns the notional of a signed quote if it reaches its pair's threshold
+func (d *Dispatcher) largeQuote(pairID string, rec quote.QuoteRecord) (decimal.Decimal, bool) {
+	threshold, ok := d.large[pairID]
+	if !ok {
+		return decimal.Zero, false
+	}
+	for i := range d.cfg.Pairs {
+		pair := &d.cfg.Pairs[i]
+		if pair.PairID != pairID || pair.ChainID != rec.ChainID {
+			continue
+		}
+		size := quote.Notional(pair, rec.TokenIn, rec.AmountIn, rec.AmountOut)
+		return size, size.Cmp(threshold) >= 0
+	}
+	return decimal.Zero, false
+}
+
+// enqueue queues a payload for the endpoints subscribed to its event
+func (d *Dispatcher) enqueue(p Payload) {
+	for _, e := range d.endpoints {
+		if !e.wants(p.Event) {
+			continue
+		}
+		select {
+		case e.queue <- p:
+		default:
+			d.logger.Warn("Webhook queue full, event dropped", "url", e.url, "event", p.Event, "id", p.ID)
+		}
+	}
+}
+
+// deliverLoop delivers the queued events of an endpoint in order
+func (d *Dispatcher) deliverLoop(ctx context.Context, e *endpoint) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case p := <-e.queue:
+			if err := d.deliver(ctx, e, p); err != nil && ctx.Err() == nil {
+				d.logger.Error("Webhook delivery failed",
+					"url", e.url,
+					"event", p.Event,
+					"id", p.ID,
+					"error", err)
+			}
+		}
+	}
+}
+
+// deliver posts a payload, retrying network errors, 429 and 5xx responses with backoff
+func (d *Dispatcher) deliver(ctx context.Context, e *endpoint, p Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+	backoff := d.cfg.Webhook.RetryBackoff
+	attempts := max(d.cfg.Webhook.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		retry, err := d.post(ctx, e, p, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= attempts {
+			return fmt.Errorf("attempt %d: %w", attempt, err)
+		}
+		d.logger.Warn("Webhook delivery failed, retrying",
+			"url", e.url,
+			"event", p.Event,
+			"attempt", attempt,
+			"backoff", backoff,
+			"error", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxRetryBackoff)
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth retrying
+func (d *Dispatcher) post(ctx context.Context, e *endpoint, p Payload, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, p.Event)
+	req.Header.Set(HeaderDelivery, p.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(e.secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("status %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("status %d", resp.StatusCode)
+	}
+}
+
+// payloadData converts the data of a bus event to its JSON form
+func payloadData(data any) any {
+	switch v := data.(type) {
+	case quote.QuoteRecord:
+		return quoteData(v)
+	case depth.Inconsistency:
+		inc := Inconsistency{
+			Side:         v.Side,
+			Level:        v.Level,
+			AmountIn:     v.AmountIn.String(),
+			Advertised:   v.Advertised.String(),
+			DeviationBps: v.DeviationBps,
+		}
+		if v.Quoted != nil {
+			inc.Quoted = v.Quoted.String()
+		}
+		if v.Err != nil {
+			inc.Error = v.Err.Error()
+		}
+		return inc
+	case error:
+		return map[string]string{"error": v.Error()}
+	case fmt.Stringer:
+		return v.String() // Reject reasons, connection states
+	default:
+		return v
+	}
+}
+
+// quoteData converts a quote record to its JSON form
+func quoteData(rec quote.QuoteRecord) Quote {
+	q := Quote{
+		TokenIn:  address.Normalize(rec.TokenIn),
+		TokenOut: address.Normalize(rec.TokenOut),
+		Nonce:    rec.Nonce,
+		Deadline: rec.Deadline,
+		State:    rec.State.String(),
+		Signer:   address.Normalize(rec.Signer),
+	}
+	if rec.AmountIn != nil {
+		q.AmountIn = rec.AmountIn.String()
+	}
+	if rec.AmountOut != nil {
+		q.AmountOut = rec.AmountOut.String()
+	}
+	return q
+}
+
+// newDeliveryID returns a random delivery ID
+func newDeliveryID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,156 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/config"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/quote"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/testutil"
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/webhook"
+)
+
+// receiver records the webhook requests it accepts and fails the first failures
+type receiver struct {
+	mu       sync.Mutex
+	failures int
+	payloads []webhook.Payload
+	got      chan struct{}
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.failures > 0 {
+		rc.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	ts, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+	if r.Header.Get(webhook.HeaderSignature) != webhook.Sign([]byte("secret"), ts, body) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var p webhook.Payload
+	if err := json.Unmarshal(body, &p); err != nil || r.Header.Get(webhook.HeaderEvent) != p.Event {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	rc.payloads = append(rc.payloads, p)
+	rc.got <- struct{}{}
+}
+
+func (rc *receiver) wait(t *testing.T, n int) []webhook.Payload {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-rc.got:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("received %d webhooks, want %d", i, n)
+		}
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]webhook.Payload(nil), rc.payloads...)
+}
+
+func newDispatcher(t *testing.T, url string, events ...string) (*webhook.Dispatcher, *config.Config) {
+	t.Helper()
+	cfg := testutil.Config()
+	cfg.Webhook = config.WebhookConfig{
+		Endpoints:    []config.WebhookEndpoint{{URL: url, Secret: "secret", Events: events}},
+		LargeQuotes:  map[string]string{"WBNB-USDT": "1000"},
+		Timeout:      time.Second,
+		MaxAttempts:  3,
+		RetryBackoff: time.Millisecond,
+	}
+	d, err := webhook.NewDispatcher(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewDispatcher failed: %v", err)
+	}
+	return d, cfg
+}
+
+func signedQuote(amountOut int64) quote.QuoteRecord {
+	return quote.QuoteRecord{
+		QuoteID:   "q1",
+		ChainID:   testutil.DefaultChainID,
+		TokenIn:   common.HexToAddress(testutil.DefaultTokenIn),
+		TokenOut:  common.HexToAddress(testutil.DefaultTokenOut),
+		AmountIn:  big.NewInt(1e18),
+		AmountOut: new(big.Int).Mul(big.NewInt(amountOut), big.NewInt(1e18)),
+		State:     quote.QuoteStatePending,
+	}
+}
+
+func TestDispatcher_SignsAndRetries(t *testing.T) {
+	rc := &receiver{failures: 2, got: make(chan struct{}, 8)}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	d, _ := newDispatcher(t, srv.URL)
+	bus := events.NewBus(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d.Start(ctx, bus)
+
+	bus.Publish(events.Event{Kind: events.RiskBreach, ChainID: testutil.DefaultChainID, PairID: "WBNB-USDT", Detail: events.RiskOracleBand})
+	got := rc.wait(t, 1)
+	if got[0].Event != string(events.RiskBreach) || got[0].Detail != events.RiskOracleBand || got[0].ID == "" {
+		t.Errorf("payload = %+v, want a risk_breach of %s", got[0], events.RiskOracleBand)
+	}
+}
+
+func TestDispatcher_LargeQuote(t *testing.T) {
+	rc := &receiver{got: make(chan struct{}, 8)}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	d, _ := newDispatcher(t, srv.URL, webhook.LargeQuote)
+	bus := events.NewBus(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d.Start(ctx, bus)
+
+	// Only the second quote reaches 1000 USDT; the endpoint does not subscribe to quote_signed
+	bus.Publish(events.Event{Kind: events.QuoteSigned, ChainID: testutil.DefaultChainID, PairID: "WBNB-USDT", QuoteID: "small", Data: signedQuote(600)})
+	bus.Publish(events.Event{Kind: events.QuoteSigned, ChainID: testutil.DefaultChainID, PairID: "WBNB-USDT", QuoteID: "large", Data: signedQuote(1200)})
+	got := rc.wait(t, 1)
+	select {
+	case <-rc.got:
+		t.Fatal("received a second webhook, want only the large quote")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if got[0].Event != webhook.LargeQuote || got[0].QuoteID != "large" {
+		t.Fatalf("payload = %+v, want large_quote of quote large", got[0])
+	}
+	data, _ := json.Marshal(got[0].Data)
+	var q webhook.Quote
+	if err := json.Unmarshal(data, &q); err != nil {
+		t.Fatalf("invalid quote data: %v", err)
+	}
+	if q.Notional != "1200" {
+		t.Errorf("Notional = %q, want 1200", q.Notional)
+	}
+}
+
+func TestNewDispatcher_MissingSecretEnv(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Webhook.Endpoints = []config.WebhookEndpoint{{URL: "https://example.com/hook", SecretEnv: "MM_TEST_WEBHOOK_SECRET_UNSET"}}
+	if _, err := webhook.NewDispatcher(cfg, slog.New(slog.NewTextHandler(io.Discard, nil))); err == nil {
+		t.Error("NewDispatcher() = nil error, want error for an unset secret variable")
+	}
+}
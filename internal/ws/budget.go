@@ -0,0 +1,81 @@
+package ws
+
+import (
+	"sync"
+	"time"
+)
+
+// Budget is an outbound byte budget for metered or constrained links
+// It is a token bucket refilled at Bytes per Window. Every frame written spends from it, and
+// quotes and heartbeats are always sent, even over budget. Depth pushes are the part of the
+// traffic that can wait: they are deferred while the bucket is at or below its reserve, so a
+// tight budget lowers the depth frequency before anything else.
+type Budget struct {
+	rate     float64 // Bytes per second
+	capacity float64
+	reserve  float64 // Kept for quotes and heartbeats
+	now      func() time.Time
+
+	mu       sync.Mutex
+	tokens   float64
+	last     time.Time
+	deferred uint64
+}
+
+// NewBudget creates a budget of bytes per window, keeping the reserve fraction (0-1) of it for
+// traffic other than depth
+func NewBudget(bytes int64, window time.Duration, reserve float64) *Budget {
+	b := &Budget{
+		rate:     float64(bytes) / window.Seconds(),
+		capacity: float64(bytes),
+		reserve:  float64(bytes) * reserve,
+		now:      time.Now,
+	}
+	b.tokens = b.capacity
+	b.last = b.now()
+	return b
+}
+
+// refill adds the tokens accrued since the last call; the caller must hold b.mu
+func (b *Budget) refill() {
+	now := b.now()
+	b.tokens = min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// spend records n bytes written; a nil budget records nothing
+func (b *Budget) spend(n int) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	b.tokens -= float64(n) // May go negative: quotes are never held back
+}
+
+// AllowDepth reports whether a depth push fits the budget now, counting deferred pushes
+// A nil budget allows every push.
+func (b *Budget) AllowDepth() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	if b.tokens > b.reserve {
+		return true
+	}
+	b.deferred++
+	return false
+}
+
+// Deferred returns how many depth pushes were deferred
+func (b *Budget) Deferred() uint64 {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.deferred
+}
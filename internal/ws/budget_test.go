@@ -0,0 +1,47 @@
+package ws
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBudget_DefersDepthFirst(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	b := NewBudget(1000, 10*time.Second, 0.2)
+	b.now = func() time.Time { return now }
+	b.last = now
+
+	if !b.AllowDepth() {
+		t.Fatal("AllowDepth() = false with a full budget")
+	}
+	b.spend(900)
+	if b.AllowDepth() {
+		t.Error("AllowDepth() = true within the reserve")
+	}
+	if b.Deferred() != 1 {
+		t.Errorf("Deferred() = %v, want 1", b.Deferred())
+	}
+
+	// 100 bytes per second refill
+	now = now.Add(2 * time.Second)
+	if !b.AllowDepth() {
+		t.Error("AllowDepth() = false after the budget refilled past the reserve")
+	}
+
+	// Quotes may overdraw; depth waits until the debt is repaid
+	b.spend(5000)
+	now = now.Add(40 * time.Second)
+	if b.AllowDepth() {
+		t.Error("AllowDepth() = true while overdrawn")
+	}
+	now = now.Add(time.Hour)
+	if !b.AllowDepth() {
+		t.Error("AllowDepth() = false after a full refill")
+	}
+
+	var nilBudget *Budget
+	nilBudget.spend(1)
+	if !nilBudget.AllowDepth() {
+		t.Error("nil AllowDepth() = false, want true")
+	}
+}
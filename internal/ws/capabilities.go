@@ -0,0 +1,87 @@
+package ws
+
+import (
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// Capability names derived from ConnectionAck
+const (
+	CapabilityServerConfig = "server_config" // ConnectionAck carries ConnectionConfig
+	CapabilityServerTime   = "server_time"   // ConnectionAck carries server time
+)
+
+// Capabilities is the set of protocol features the server announced
+// The protocol has no explicit capability list, so capabilities are derived from
+// which ConnectionAck fields are populated. Fields unknown to this build are
+// reported as "ack_field_<number>" so newer servers are visible in logs.
+type Capabilities map[string]bool
+
+// Has checks if a capability is present
+func (c Capabilities) Has(name string) bool {
+	return c[name]
+}
+
+// Names returns the sorted capability names
+func (c Capabilities) Names() []string {
+	names := make([]string, 0, len(c))
+	for name, ok := range c {
+		if ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CapabilitiesFromAck derives capabilities from a ConnectionAck
+func CapabilitiesFromAck(ack *mmv1.ConnectionAck) Capabilities {
+	caps := make(Capabilities)
+	if ack == nil {
+		return caps
+	}
+	if ack.Config != nil {
+		caps[CapabilityServerConfig] = true
+	}
+	if ack.ServerTime != 0 {
+		caps[CapabilityServerTime] = true
+	}
+	for _, num := range UnknownFieldNumbers(ack) {
+		caps[fmt.Sprintf("ack_field_%d", num)] = true
+	}
+	return caps
+}
+
+// UnknownFieldNumbers returns the field numbers present in msg that this build does not know
+func UnknownFieldNumbers(msg protoreflect.ProtoMessage) []protowire.Number {
+	raw := msg.ProtoReflect().GetUnknown()
+	nums := make([]protowire.Number, 0)
+	for len(raw) > 0 {
+		num, typ, n := protowire.ConsumeTag(raw)
+		if n < 0 {
+			break
+		}
+		raw = raw[n:]
+		m := protowire.ConsumeFieldValue(num, typ, raw)
+		if m < 0 {
+			break
+		}
+		raw = raw[m:]
+		nums = append(nums, num)
+	}
+	return nums
+}
+
+// EnumName returns the enum value name, or "UNKNOWN(<n>)" for values this build does not know
+func EnumName(e protoreflect.Enum) string {
+	desc := e.Descriptor().Values().ByNumber(e.Number())
+	if desc == nil {
+		return fmt.Sprintf("UNKNOWN(%d)", e.Number())
+	}
+	return string(desc.Name())
+}
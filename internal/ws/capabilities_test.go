@@ -0,0 +1,54 @@
+package ws
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+func TestCapabilitiesFromAck(t *testing.T) {
+	ack := &mmv1.ConnectionAck{
+		Success:    true,
+		ServerTime: 1735084800000,
+		Config:     &mmv1.ConnectionConfig{DepthPushIntervalMs: 3000},
+	}
+	data, err := proto.Marshal(ack)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	// Simulate a field added by a newer server
+	data = protowire.AppendTag(data, 42, protowire.VarintType)
+	data = protowire.AppendVarint(data, 1)
+
+	decoded := &mmv1.ConnectionAck{}
+	if err := proto.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	caps := CapabilitiesFromAck(decoded)
+	for _, name := range []string{CapabilityServerConfig, CapabilityServerTime, "ack_field_42"} {
+		if !caps.Has(name) {
+			t.Errorf("Capability %s should be present", name)
+		}
+	}
+	if len(caps.Names()) != 3 {
+		t.Errorf("Names = %v, want 3 entries", caps.Names())
+	}
+
+	if caps := CapabilitiesFromAck(nil); len(caps) != 0 {
+		t.Errorf("CapabilitiesFromAck(nil) = %v, want empty", caps)
+	}
+}
+
+func TestEnumName(t *testing.T) {
+	if got := EnumName(mmv1.ErrorCode_ERROR_CODE_TIMEOUT); got != "ERROR_CODE_TIMEOUT" {
+		t.Errorf("EnumName = %s, want ERROR_CODE_TIMEOUT", got)
+	}
+	if got := EnumName(mmv1.ErrorCode(99)); got != "UNKNOWN(99)" {
+		t.Errorf("EnumName = %s, want UNKNOWN(99)", got)
+	}
+}
@@ -2,16 +2,18 @@ package ws
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
-	"net/http"
+	"net"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/gorilla/websocket"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/ThetaSpace/DarkPool-Market-Maker-Example/internal/events"
 	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
 )
 
@@ -54,7 +56,11 @@ type WSClient interface {
 	// Close closes the connection
 	Close() error
 	// Send sends a Protobuf message
+	// Implementations must not retain msg: callers may reuse it once Send returns
+	// A nil error means the message was written to the connection
 	Send(msg *mmv1.Message) error
+	// SendBatch sends several Protobuf messages back to back in one write burst
+	SendBatch(msgs []*mmv1.Message) error
 	// SetMessageHandler sets the message handler callback
 	SetMessageHandler(handler MessageHandler)
 	// SetReconnectedHandler sets the reconnection success callback
@@ -69,6 +75,20 @@ type WSClient interface {
 	TriggerReconnect()
 }
 
+// QueueDepther is implemented by clients that queue outbound messages
+type QueueDepther interface {
+	// QueueDepth returns the number of queued sends not yet written
+	QueueDepth() int
+}
+
+// QueueDepth returns the outbound queue depth of c, false if c does not queue sends
+func QueueDepth(c WSClient) (int, bool) {
+	if q, ok := c.(QueueDepther); ok {
+		return q.QueueDepth(), true
+	}
+	return 0, false
+}
+
 // Config WebSocket client configuration
 type Config struct {
 	ServerURL            string        // WebSocket server address
@@ -78,6 +98,20 @@ type Config struct {
 	HeartbeatInterval    time.Duration // Heartbeat interval
 	ReadTimeout          time.Duration // Read timeout
 	WriteTimeout         time.Duration // Write timeout
+	Traffic              *Traffic      // Counts messages on the wire, nil = not counted
+	Health               HealthFunc    // Health attached to heartbeat pings, nil = none
+	StormGuard           *StormGuard   // Process-wide reconnect limit, shared by clients; nil = none
+	Budget               *Budget       // Outbound byte budget, shared by clients; nil = unlimited
+	Transport            Transport     // Carries the frames, nil = WebSocket to ServerURL with APIToken
+	TLSPins              []Pin         // Gateway certificate pins of the WebSocket transport, empty = CA validation only
+	TLSConfig            *tls.Config   // Base TLS settings of the WebSocket transport, nil = defaults
+	HandshakeTimeout     time.Duration // TCP, TLS and WebSocket handshake, 0 = 10s
+	KeepAlive            time.Duration // TCP keepalive probe period, 0 = OS/Go default (15s), negative = off
+	TLSSessionCache      int           // TLS sessions kept for resumption on reconnect, 0 = full handshake every time
+	DNSRecheckInterval   time.Duration // Re-resolve ServerURL's host and reconnect if the connected address is gone, 0 = off
+	Events               *EventLog     // Connection history, shared by clients; nil = not kept
+	Bus                  *events.Bus   // Receives ConnectionStateChanged, nil = none
+	UserAgent            string        // User-Agent of the WebSocket handshake, identifies the MM build; empty = Go default
 }
 
 // DefaultConfig returns default configuration
@@ -93,20 +127,31 @@ func DefaultConfig() *Config {
 
 // client WebSocket client implementation
 type client struct {
-	config *Config
-	conn   *websocket.Conn
-	state  atomic.Int32
-	logger *slog.Logger
+	config     *Config
+	conn       Conn
+	transport  Transport
+	lookupHost func(ctx context.Context, host string) ([]string, error) // DNS re-resolution, replaced in tests
+	state      atomic.Int32
+	logger     *slog.Logger
 
 	handler            MessageHandler
 	reconnectedHandler ReconnectedHandler
 	mu                 sync.RWMutex
-	writeMu            sync.Mutex // Protects write operation concurrency
+
+	// Outbound writes: senders queue serialized frames in sendQ and writeLoop, the only
+	// writer of the connection, sends them and reports the outcome. gen changes on every
+	// connection, so frames queued for a dropped connection are discarded instead of being
+	// sent on the next one
+	sendQ      *sendRing
+	sendNotify chan struct{} // Wakes writeLoop when it is idle
+	writerIdle atomic.Bool   // writeLoop is waiting on sendNotify
+	gen        atomic.Uint64
 
 	ctx        context.Context
 	cancel     context.CancelFunc
 	wg         sync.WaitGroup
 	closeCh    chan struct{}
+	writerDone chan struct{} // Closed when writeLoop exits
 	reconnectC chan struct{}
 
 	// Reconnection control
@@ -128,11 +173,20 @@ func NewClient(config *Config, logger *slog.Logger) WSClient {
 		logger = slog.Default()
 	}
 
+	transport := config.Transport
+	if transport == nil {
+		transport = NewWebSocketTransport(config, logger)
+	}
+
 	c := &client{
 		config:     config,
+		transport:  transport,
+		lookupHost: net.DefaultResolver.LookupHost,
 		logger:     logger,
 		closeCh:    make(chan struct{}),
 		reconnectC: make(chan struct{}, 1),
+		sendQ:      newSendRing(sendQueueSize),
+		sendNotify: make(chan struct{}, 1),
 	}
 
 	c.state.Store(int32(StateDisconnected))
@@ -157,55 +211,64 @@ func (c *client) Connect(ctx context.Context) error {
 
 	c.ctx, c.cancel = context.WithCancel(ctx)
 	c.closeCh = make(chan struct{})
+	c.writerDone = make(chan struct{})
+	closeCh, writerDone := c.closeCh, c.writerDone
 	c.mu.Unlock()
 
-	return c.doConnect()
+	if err := c.doConnect(); err != nil {
+		return err
+	}
+
+	// One writer per client: it outlives reconnects and exits on Close
+	c.wg.Add(1)
+	go c.writeLoop(closeCh, writerDone)
+	return nil
 }
 
 // doConnect performs the actual connection operation
 func (c *client) doConnect() error {
 	c.SetState(StateConnecting)
 
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-	}
-
-	// Build request header, add token authentication
-	header := http.Header{}
-	if c.config.APIToken != "" {
-		header.Set("Authorization", "Bearer "+c.config.APIToken)
-	}
-
-	conn, resp, err := dialer.DialContext(c.ctx, c.config.ServerURL, header)
+	conn, err := c.transport.Dial(c.ctx)
 	if err != nil {
 		c.SetState(StateDisconnected)
-		if resp != nil {
-			c.logger.Error("WebSocket dial failed",
-				"status", resp.StatusCode,
-				"url", c.config.ServerURL,
-				"error", err)
+		var handshakeErr *HandshakeError
+		if errors.As(err, &handshakeErr) && handshakeErr.AuthFailed() {
+			c.recordEvent(EventAuthFailed, err.Error())
 		} else {
-			c.logger.Error("WebSocket dial failed",
-				"url", c.config.ServerURL,
-				"error", err)
+			c.recordEvent(EventDialFailed, err.Error())
 		}
+		if errors.Is(err, ErrPinMismatch) {
+			c.logger.Error("Gateway certificate does not match the pinned hashes, refusing the connection",
+				"url", c.config.ServerURL)
+		}
+		c.logger.Error("WebSocket dial failed",
+			"url", c.config.ServerURL,
+			"error", err)
 		return fmt.Errorf("websocket dial failed: %w", err)
 	}
 
 	c.mu.Lock()
 	c.conn = conn
+	c.gen.Add(1)
 	c.mu.Unlock()
 
 	c.SetState(StateConnected)
+	c.recordEvent(EventConnected, "")
 	c.logger.Info("WebSocket connected", "url", c.config.ServerURL)
 
 	// Start heartbeat
+	c.mu.Lock()
 	c.stopHeartbeat()
-	c.heartbeat = NewHeartbeat(c, &HeartbeatConfig{
+	heartbeat := NewHeartbeat(c, &HeartbeatConfig{
 		Interval:    c.config.HeartbeatInterval,
 		ReadTimeout: c.config.ReadTimeout,
+		Health:      c.config.Health,
 	}, c.logger)
+	c.heartbeat = heartbeat
 	c.heartbeatCtx, c.heartbeatCancel = context.WithCancel(c.ctx)
+	heartbeatCtx := c.heartbeatCtx
+	c.mu.Unlock()
 
 	// Start read loop
 	c.wg.Add(1)
@@ -213,7 +276,13 @@ func (c *client) doConnect() error {
 
 	// Start heartbeat
 	c.wg.Add(1)
-	go c.heartbeat.Start(c.heartbeatCtx, &c.wg)
+	go heartbeat.Start(heartbeatCtx, &c.wg)
+
+	// Follow DNS changes of the server host; stopped with the heartbeat
+	if c.config.DNSRecheckInterval > 0 {
+		c.wg.Add(1)
+		go c.dnsRecheckLoop(heartbeatCtx, &c.wg, conn, c.config.DNSRecheckInterval)
+	}
 
 	// Reset reconnector
 	c.reconnector.Reset()
@@ -265,59 +334,239 @@ func (c *client) Close() error {
 
 	// Close WebSocket connection
 	if c.conn != nil {
-		_ = c.conn.WriteControl(
-			websocket.CloseMessage,
-			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
-			time.Now().Add(time.Second),
-		)
-		_ = c.conn.Close()
+		_ = c.conn.Shutdown()
 		c.conn = nil
 	}
 
 	c.SetState(StateDisconnected)
+	c.recordEvent(EventClosed, "")
 	c.logger.Info("WebSocket connection closed")
 
 	return nil
 }
 
+// maxPooledFrame is the largest serialization buffer kept for reuse, so one large message does not pin memory
+const maxPooledFrame = 64 << 10
+
+// frameBufPool reuses serialization buffers across sends
+var frameBufPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 0, 1024)
+		return &buf
+	},
+}
+
+// putFrameBuf returns a serialization buffer to the pool
+func putFrameBuf(buf *[]byte) {
+	if buf == nil || cap(*buf) > maxPooledFrame {
+		return
+	}
+	frameBufPool.Put(buf)
+}
+
+// ErrSendQueueFull is returned by Send and SendBatch when the outbound queue is full
+var ErrSendQueueFull = errors.New("websocket send queue full")
+
+// ErrNotWritten is returned by Send and SendBatch when the connection the messages were queued
+// for was lost, or the client closed, before they were written
+var ErrNotWritten = errors.New("websocket connection lost before the write")
+
 // Send sends a Protobuf message
 func (c *client) Send(msg *mmv1.Message) error {
+	return c.SendBatch([]*mmv1.Message{msg})
+}
+
+// SendBatch sends several Protobuf messages back to back in one write burst
+// Each message is still its own WebSocket frame. The messages are serialized and queued as one
+// entry, so the writer sends them without interleaving other traffic. SendBatch returns once
+// the writer has flushed the frames, with the write error if any: concurrent senders share one
+// flush, and none holds a lock across the socket write. A depth snapshot superseded in the
+// queue by a newer snapshot of its pair is not written and reports success.
+func (c *client) SendBatch(msgs []*mmv1.Message) error {
 	if !c.IsConnected() {
 		return fmt.Errorf("websocket not connected")
 	}
+	c.mu.RLock()
+	writerDone := c.writerDone
+	c.mu.RUnlock()
 
-	// Serialize message
-	data, err := proto.Marshal(msg)
-	if err != nil {
-		return fmt.Errorf("failed to marshal message: %w", err)
+	// Serialize messages into pooled buffers, released once the writer reports the outcome
+	item := outboundPool.Get().(*outbound)
+	for _, msg := range msgs {
+		frame := frameBufPool.Get().(*[]byte)
+		item.frames = append(item.frames, frame)
+		data, err := proto.MarshalOptions{}.MarshalAppend((*frame)[:0], msg)
+		if err != nil {
+			item.release()
+			return fmt.Errorf("failed to marshal message: %w", err)
+		}
+		*frame = data
+		item.types = append(item.types, msg.Type)
+		key, isDepth := depthKeyOf(msg)
+		item.keys = append(item.keys, key)
+		item.isDepth = append(item.isDepth, isDepth)
+		item.superseded = append(item.superseded, false)
 	}
+	item.gen = c.gen.Load()
 
-	// Lock to ensure write operation atomicity
-	c.writeMu.Lock()
-	defer c.writeMu.Unlock()
+	if !c.sendQ.push(item) {
+		item.release()
+		return ErrSendQueueFull
+	}
+	if c.writerIdle.CompareAndSwap(true, false) {
+		select {
+		case c.sendNotify <- struct{}{}:
+		default:
+		}
+	}
+
+	select {
+	case err := <-item.done:
+		item.release()
+		return err
+	case <-writerDone:
+		// The writer reports everything it popped before exiting
+		select {
+		case err := <-item.done:
+			item.release()
+			return err
+		default:
+			return ErrNotWritten // Queued after the writer exited: the item is left to the GC
+		}
+	}
+}
+
+// writeLoop writes queued frames until closeCh is closed, then flushes what is left
+// It is the only consumer of sendQ and the only writer of the connection.
+// Everything queued when it wakes up is written as one burst (see writeBurst).
+// writerDone is closed on exit, once every popped entry has been reported.
+func (c *client) writeLoop(closeCh, writerDone chan struct{}) {
+	defer c.wg.Done()
+	defer close(writerDone)
+
+	burst := make([]*outbound, 0, maxBurst)
+	seen := make(map[depthKey]struct{})
+	var failedGen uint64 // After a failed write, the rest of that connection's entries are skipped
+
+	// collect pops queued entries into the burst, reporting whether it got any
+	collect := func() bool {
+		for len(burst) < maxBurst {
+			item, ok := c.sendQ.pop()
+			if !ok {
+				break
+			}
+			burst = append(burst, item)
+		}
+		return len(burst) > 0
+	}
+	// send writes the burst and hands every entry back to its sender with the outcome
+	send := func() {
+		c.writeBurst(burst, seen, &failedGen)
+		for i, item := range burst {
+			item.done <- item.err
+			burst[i] = nil
+		}
+		burst = burst[:0]
+	}
+
+	for {
+		if collect() {
+			send()
+			continue
+		}
+
+		// Idle: announce it, then re-check so a push racing with the announcement is not missed
+		c.writerIdle.Store(true)
+		if collect() {
+			c.writerIdle.Store(false)
+			send()
+			continue
+		}
+		select {
+		case <-c.sendNotify:
+		case <-closeCh:
+			c.writerIdle.Store(false)
+			for collect() {
+				send()
+			}
+			return
+		}
+	}
+}
+
+// writeBurst writes queued entries back to back and flushes them to the socket together,
+// setting the outcome of each entry
+// Depth snapshots superseded by a newer snapshot of the same pair in the burst are skipped,
+// as are entries queued for an earlier connection. When a write or the flush fails, every
+// entry of the burst not already failed gets the error: what reached the peer is unknown.
+func (c *client) writeBurst(burst []*outbound, seen map[depthKey]struct{}, failedGen *uint64) {
+	if dropped := markSuperseded(burst, seen); dropped > 0 {
+		c.logger.Debug("Dropping superseded depth snapshots", "count", dropped)
+	}
+	fail := func(err error) {
+		for _, item := range burst {
+			if item.err == nil {
+				item.err = err
+			}
+		}
+	}
 
 	c.mu.RLock()
 	conn := c.conn
+	gen := c.gen.Load()
 	c.mu.RUnlock()
 
-	if conn == nil {
-		return fmt.Errorf("websocket connection is nil")
+	if conn == nil || gen == *failedGen {
+		fail(ErrNotWritten)
+		return
 	}
 
-	// Set write timeout
+	// Set write timeout (covers the whole burst)
 	if err := conn.SetWriteDeadline(time.Now().Add(c.config.WriteTimeout)); err != nil {
+		c.logger.Error("Failed to set write deadline", "error", err)
+		*failedGen = gen
 		c.triggerReconnect()
-		return fmt.Errorf("failed to set write deadline: %w", err)
+		fail(fmt.Errorf("failed to set write deadline: %w", err))
+		return
 	}
 
-	// Send binary message
-	if err := conn.WriteMessage(websocket.BinaryMessage, data); err != nil {
+	for _, item := range burst {
+		if item.gen != gen {
+			c.logger.Debug("Dropping frames queued for a closed connection", "frames", len(item.frames))
+			item.err = ErrNotWritten
+			continue
+		}
+		// Send binary messages
+		for i, frame := range item.frames {
+			if item.superseded[i] {
+				continue
+			}
+			if err := conn.WriteFrame(*frame); err != nil {
+				c.logger.Error("Failed to write message", "type", item.types[i].String(), "error", err)
+				c.recordEvent(EventDropped, "write: "+err.Error())
+				*failedGen = gen
+				c.triggerReconnect()
+				_ = conn.Flush()
+				fail(fmt.Errorf("failed to write message: %w", err))
+				return
+			}
+			c.config.Traffic.observe(DirectionOut, item.types[i], len(*frame))
+			c.config.Budget.spend(len(*frame))
+			c.logger.Debug("Message sent", "type", item.types[i].String())
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		c.logger.Error("Failed to flush messages", "error", err)
+		c.recordEvent(EventDropped, "write: "+err.Error())
+		*failedGen = gen
 		c.triggerReconnect()
-		return fmt.Errorf("failed to write message: %w", err)
+		fail(fmt.Errorf("failed to flush messages: %w", err))
 	}
+}
 
-	c.logger.Debug("Message sent", "type", msg.Type.String())
-	return nil
+// QueueDepth returns the number of queued sends not yet written
+func (c *client) QueueDepth() int {
+	return c.sendQ.len()
 }
 
 // SetMessageHandler sets the message handler callback
@@ -350,6 +599,12 @@ func (c *client) SetState(state ConnectionState) {
 	old := ConnectionState(c.state.Swap(int32(state)))
 	if old != state {
 		c.logger.Info("WebSocket state changed", "from", old.String(), "to", state.String())
+		c.recordEvent(EventStateChanged, old.String()+" -> "+state.String())
+		c.config.Bus.Publish(events.Event{
+			Kind:   events.ConnectionStateChanged,
+			Detail: c.config.ServerURL + ": " + old.String() + " -> " + state.String(),
+			Data:   state,
+		})
 	}
 }
 
@@ -382,23 +637,18 @@ func (c *client) readLoop() {
 		}
 
 		// Read message
-		wsMsgType, data, err := conn.ReadMessage()
+		data, err := conn.ReadFrame()
 		if err != nil {
-			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+			if errors.Is(err, ErrClosedByPeer) {
 				c.logger.Info("WebSocket closed by server")
 			} else {
 				c.logger.Error("WebSocket read error", "error", err)
 			}
+			c.recordEvent(EventDropped, "read: "+err.Error())
 			c.triggerReconnect()
 			return
 		}
 
-		// Only handle binary messages
-		if wsMsgType != websocket.BinaryMessage {
-			c.logger.Warn("Received non-binary message", "type", wsMsgType)
-			continue
-		}
-
 		// Deserialize message
 		msg := &mmv1.Message{}
 		if err := proto.Unmarshal(data, msg); err != nil {
@@ -406,11 +656,18 @@ func (c *client) readLoop() {
 			continue
 		}
 
+		c.config.Traffic.observe(DirectionIn, msg.Type, len(data))
+		if ack := msg.GetConnectionAck(); ack != nil && !ack.Success {
+			c.recordEvent(EventAuthFailed, ack.ErrorMessage)
+		}
 		c.logger.Debug("Message received", "type", msg.Type.String())
 
 		// Update heartbeat time
-		if c.heartbeat != nil {
-			c.heartbeat.OnMessageReceived()
+		c.mu.RLock()
+		heartbeat := c.heartbeat
+		c.mu.RUnlock()
+		if heartbeat != nil {
+			heartbeat.OnMessageReceived()
 		}
 
 		// Call handler callback
@@ -426,6 +683,11 @@ func (c *client) readLoop() {
 	}
 }
 
+// recordEvent adds a connection event to the configured event log
+func (c *client) recordEvent(kind, detail string) {
+	c.config.Events.record(c.config.ServerURL, kind, detail)
+}
+
 // triggerReconnect triggers reconnection (internal use)
 func (c *client) triggerReconnect() {
 	c.TriggerReconnect()
@@ -443,8 +705,8 @@ func (c *client) TriggerReconnect() {
 
 // reconnectLoop reconnection loop
 func (c *client) reconnectLoop() {
-	c.stopHeartbeat()
 	c.mu.Lock()
+	c.stopHeartbeat()
 	// Close old connection
 	if c.conn != nil {
 		_ = c.conn.Close()
@@ -483,6 +745,19 @@ func (c *client) reconnectLoop() {
 			return
 		}
 
+		// Process-wide limit: a server rejecting every session is not redialed at full rate
+		if wait := c.config.StormGuard.Delay(); wait > 0 {
+			c.logger.Warn("Reconnect delayed by storm cool-off", "wait", wait)
+			select {
+			case <-time.After(wait):
+			case <-c.closeCh:
+				return
+			case <-c.ctx.Done():
+				return
+			}
+			continue
+		}
+
 		// Mark as reconnection state
 		c.isReconnect = true
 
@@ -503,6 +778,7 @@ func (c *client) reconnectLoop() {
 }
 
 // stopHeartbeat stops current heartbeat goroutine
+// The caller must hold c.mu
 func (c *client) stopHeartbeat() {
 	if c.heartbeatCancel != nil {
 		c.heartbeatCancel()
@@ -2,6 +2,7 @@ package ws
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -313,3 +314,109 @@ func TestClient_SendWhenNotConnected(t *testing.T) {
 		t.Error("Send should fail when not connected")
 	}
 }
+
+func TestClient_SendBatch(t *testing.T) {
+	receivedCh := make(chan *mmv1.Message, 3)
+
+	server := mockWSServer(t, func(conn *websocket.Conn) {
+		for i := 0; i < 3; i++ {
+			_, msgData, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			msg := &mmv1.Message{}
+			if err := proto.Unmarshal(msgData, msg); err != nil {
+				return
+			}
+			receivedCh <- msg
+		}
+	})
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	cfg := &Config{
+		ServerURL:         wsURL,
+		ReconnectInterval: 1 * time.Second,
+		HeartbeatInterval: 30 * time.Second,
+		ReadTimeout:       5 * time.Second,
+		WriteTimeout:      5 * time.Second,
+	}
+
+	client := NewClient(cfg, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Close()
+
+	msgs := make([]*mmv1.Message, 3)
+	for i := range msgs {
+		msgs[i] = &mmv1.Message{
+			Type:      mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT,
+			Timestamp: int64(i),
+			Payload: &mmv1.Message_DepthSnapshot{
+				DepthSnapshot: &mmv1.DepthSnapshot{ChainId: 56},
+			},
+		}
+	}
+
+	if err := client.SendBatch(msgs); err != nil {
+		t.Fatalf("SendBatch failed: %v", err)
+	}
+
+	// Verify server received all messages in order
+	for i := 0; i < 3; i++ {
+		select {
+		case received := <-receivedCh:
+			if received.Timestamp != int64(i) {
+				t.Errorf("Received timestamp = %d, want %d", received.Timestamp, i)
+			}
+		case <-time.After(1 * time.Second):
+			t.Fatal("Timeout waiting for message")
+		}
+	}
+}
+
+// failingTransport dials memory connections whose writes fail with err
+type failingTransport struct {
+	*MemoryTransport
+	err error
+}
+
+func (t *failingTransport) Dial(ctx context.Context) (Conn, error) {
+	conn, err := t.MemoryTransport.Dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &failingConn{Conn: conn, err: t.err}, nil
+}
+
+type failingConn struct {
+	Conn
+	err error
+}
+
+func (c *failingConn) WriteFrame([]byte) error { return c.err }
+
+func TestClient_SendReturnsWriteError(t *testing.T) {
+	errWrite := errors.New("broken pipe")
+	transport := &failingTransport{MemoryTransport: NewMemoryTransport(), err: errWrite}
+	client := memoryClient(t, transport)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() { _, _ = transport.Accept(ctx) }()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Close()
+
+	err := client.Send(&mmv1.Message{Type: mmv1.MessageType_MESSAGE_TYPE_QUOTE_RESPONSE})
+	if !errors.Is(err, errWrite) {
+		t.Errorf("Send error = %v, want %v", err, errWrite)
+	}
+}
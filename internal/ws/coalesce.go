@@ -0,0 +1,99 @@
+package ws
+
+import (
+	"bufio"
+	"net"
+	"sync"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// maxBurst is the most queued entries the writer coalesces into one flush
+const maxBurst = 64
+
+// coalesceBufSize is the write buffer of a burst; larger bursts flush when it fills
+const coalesceBufSize = 64 << 10
+
+// bufferedConn is a net.Conn whose writes can be held in a buffer and flushed together
+// The writer holds writes for a burst of frames so they reach the socket in one syscall.
+// Writes outside a burst (handshake, control frames) pass straight through.
+type bufferedConn struct {
+	net.Conn
+
+	mu      sync.Mutex
+	buf     *bufio.Writer
+	holding bool
+}
+
+// newBufferedConn wraps conn
+func newBufferedConn(conn net.Conn) *bufferedConn {
+	c := &bufferedConn{Conn: conn}
+	c.buf = bufio.NewWriterSize(conn, coalesceBufSize)
+	return c
+}
+
+// Write buffers p while holding, otherwise writes it through
+func (c *bufferedConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.holding {
+		return c.buf.Write(p)
+	}
+	return c.Conn.Write(p)
+}
+
+// hold starts buffering writes
+func (c *bufferedConn) hold() {
+	c.mu.Lock()
+	c.holding = true
+	c.mu.Unlock()
+}
+
+// flush writes the buffered bytes and stops buffering
+func (c *bufferedConn) flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.holding = false
+	return c.buf.Flush()
+}
+
+// depthKey identifies the pair of a depth snapshot frame
+type depthKey struct {
+	chainID uint64
+	pairID  string
+}
+
+// depthKeyOf returns the pair of a depth snapshot, false for other messages
+func depthKeyOf(msg *mmv1.Message) (depthKey, bool) {
+	snapshot := msg.GetDepthSnapshot()
+	if msg.Type != mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT || snapshot == nil {
+		return depthKey{}, false
+	}
+	return depthKey{chainID: snapshot.ChainId, pairID: snapshot.PairId}, true
+}
+
+// markSuperseded flags depth snapshots for which a later entry of the burst carries a newer
+// snapshot of the same pair, so only the freshest book of each pair is sent
+// Frames of one entry (one SendBatch) never supersede each other, and other messages are never dropped.
+// seen is scratch space, cleared before use
+func markSuperseded(burst []*outbound, seen map[depthKey]struct{}) (dropped int) {
+	clear(seen)
+	for i := len(burst) - 1; i >= 0; i-- {
+		item := burst[i]
+		for j := range item.frames {
+			_, newer := seen[item.keys[j]]
+			item.superseded[j] = item.isDepth[j] && newer
+			if item.superseded[j] {
+				dropped++
+			}
+		}
+		for j := range item.frames {
+			if item.isDepth[j] {
+				seen[item.keys[j]] = struct{}{}
+			}
+		}
+	}
+	return dropped
+}
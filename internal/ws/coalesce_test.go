@@ -0,0 +1,90 @@
+package ws
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// countingConn records the writes that reach the network
+type countingConn struct {
+	net.Conn
+	writes [][]byte
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	c.writes = append(c.writes, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func TestBufferedConn_HoldAndFlush(t *testing.T) {
+	wire := &countingConn{}
+	conn := newBufferedConn(wire)
+
+	// Outside a burst, writes pass straight through
+	if _, err := conn.Write([]byte("handshake")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if len(wire.writes) != 1 {
+		t.Fatalf("writes = %d, want 1", len(wire.writes))
+	}
+
+	conn.hold()
+	for _, frame := range []string{"a", "b", "c"} {
+		if _, err := conn.Write([]byte(frame)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if len(wire.writes) != 1 {
+		t.Fatalf("writes while holding = %d, want 1", len(wire.writes))
+	}
+	if err := conn.flush(); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if len(wire.writes) != 2 || !bytes.Equal(wire.writes[1], []byte("abc")) {
+		t.Fatalf("writes = %q, want the burst in one write", wire.writes)
+	}
+}
+
+// depthEntry builds a queued entry of depth snapshots for the given pairs
+func depthEntry(pairs ...string) *outbound {
+	item := &outbound{}
+	for _, pair := range pairs {
+		msg := &mmv1.Message{
+			Type:    mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT,
+			Payload: &mmv1.Message_DepthSnapshot{DepthSnapshot: &mmv1.DepthSnapshot{ChainId: 56, PairId: pair}},
+		}
+		key, isDepth := depthKeyOf(msg)
+		item.frames = append(item.frames, new([]byte))
+		item.keys = append(item.keys, key)
+		item.isDepth = append(item.isDepth, isDepth)
+		item.superseded = append(item.superseded, false)
+	}
+	return item
+}
+
+func TestMarkSuperseded(t *testing.T) {
+	quote := &outbound{frames: []*[]byte{new([]byte)}, keys: []depthKey{{}}, isDepth: []bool{false}, superseded: []bool{false}}
+	burst := []*outbound{
+		depthEntry("A"),      // Superseded by the last entry
+		quote,                // Never dropped
+		depthEntry("B", "A"), // B is the freshest B; A superseded
+		depthEntry("C", "C"), // Same entry: both kept
+		depthEntry("A"),
+	}
+
+	dropped := markSuperseded(burst, make(map[depthKey]struct{}))
+	if dropped != 2 {
+		t.Errorf("dropped = %d, want 2", dropped)
+	}
+	want := [][]bool{{true}, {false}, {false, true}, {false, false}, {false}}
+	for i, item := range burst {
+		for j, got := range item.superseded {
+			if got != want[i][j] {
+				t.Errorf("entry %d frame %d superseded = %v, want %v", i, j, got, want[i][j])
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package ws
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// remoteAddrConn is a Conn that knows the address of the server end
+type remoteAddrConn interface {
+	RemoteAddr() net.Addr
+}
+
+// dnsRecheckLoop re-resolves the server host every interval until ctx is done
+// When the connected address is no longer among the resolved ones, the gateway has moved
+// (DNS failover, NAT rebinding, load balancer replacement) and the client reconnects to follow
+// it instead of waiting for the old address to stop answering. Lookup failures keep the connection.
+func (c *client) dnsRecheckLoop(ctx context.Context, wg *sync.WaitGroup, conn Conn, interval time.Duration) {
+	defer wg.Done()
+
+	remote, ok := conn.(remoteAddrConn)
+	if !ok {
+		return
+	}
+	u, err := url.Parse(c.config.ServerURL)
+	if err != nil || u.Hostname() == "" || net.ParseIP(u.Hostname()) != nil {
+		return // Nothing to re-resolve
+	}
+	host := u.Hostname()
+	connected, _, err := net.SplitHostPort(remote.RemoteAddr().String())
+	if err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		addrs, err := c.lookupHost(ctx, host)
+		if err != nil || len(addrs) == 0 {
+			c.logger.Warn("Server host re-resolution failed, keeping the connection", "host", host, "error", err)
+			continue
+		}
+		if !containsIP(addrs, connected) {
+			c.logger.Warn("Server host no longer resolves to the connected address, reconnecting",
+				"host", host, "connected", connected, "resolved", addrs)
+			c.recordEvent(EventDropped, "dns: "+host+" moved from "+connected)
+			c.triggerReconnect()
+			return
+		}
+	}
+}
+
+// containsIP reports whether addrs holds ip, comparing parsed addresses
+func containsIP(addrs []string, ip string) bool {
+	want := net.ParseIP(ip)
+	for _, addr := range addrs {
+		if got := net.ParseIP(addr); got != nil && got.Equal(want) {
+			return true
+		}
+	}
+	return false
+}
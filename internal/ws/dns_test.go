@@ -0,0 +1,112 @@
+package ws
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestClient_DNSRecheck(t *testing.T) {
+	server := mockWSServer(t, func(conn *websocket.Conn) {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+	defer server.Close()
+
+	events := NewEventLog(32)
+	wsURL := "ws" + strings.TrimPrefix(strings.Replace(server.URL, "127.0.0.1", "localhost", 1), "http")
+	c := NewClient(&Config{
+		ServerURL:          wsURL,
+		ReconnectInterval:  10 * time.Millisecond,
+		HeartbeatInterval:  time.Minute,
+		ReadTimeout:        500 * time.Millisecond,
+		WriteTimeout:       5 * time.Second,
+		DNSRecheckInterval: 10 * time.Millisecond,
+		Events:             events,
+	}, nil).(*client)
+
+	var moved atomic.Bool
+	c.lookupHost = func(context.Context, string) ([]string, error) {
+		if moved.Load() {
+			return []string{"10.0.0.1"}, nil
+		}
+		return []string{"::1", "127.0.0.1"}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer c.Close()
+
+	time.Sleep(50 * time.Millisecond)
+	if got := countEvents(events, EventDropped); got != 0 {
+		t.Fatalf("dropped events = %d while the address still resolves, want 0", got)
+	}
+
+	moved.Store(true)
+	for countEvents(events, EventDropped) == 0 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("client did not reconnect after the host moved")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func countEvents(events *EventLog, kind string) int {
+	n := 0
+	for _, e := range events.Events() {
+		if e.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func TestWebSocketTransport_SessionResumption(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		_ = conn.Close()
+	}))
+	defer server.Close()
+
+	wsURL := "wss" + strings.TrimPrefix(server.URL, "https")
+	roots := server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	transport := NewWebSocketTransport(&Config{
+		ServerURL:       wsURL,
+		TLSConfig:       &tls.Config{RootCAs: roots},
+		TLSSessionCache: 8,
+	}, nil)
+
+	resumed := make([]bool, 2)
+	for i := range resumed {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		conn, err := transport.Dial(ctx)
+		cancel()
+		if err != nil {
+			t.Fatalf("Dial %d failed: %v", i, err)
+		}
+		_, _ = conn.ReadFrame() // Processes the session ticket, then sees the server close
+		resumed[i] = conn.(*wsConn).conn.UnderlyingConn().(*tls.Conn).ConnectionState().DidResume
+		_ = conn.Close()
+	}
+	if resumed[0] || !resumed[1] {
+		t.Errorf("DidResume = %v, want [false true]", resumed)
+	}
+}
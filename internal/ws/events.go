@@ -0,0 +1,67 @@
+package ws
+
+import (
+	"sync"
+	"time"
+)
+
+// Connection event kinds
+const (
+	EventConnected    = "connected"
+	EventDialFailed   = "dial_failed"
+	EventAuthFailed   = "auth_failed"
+	EventDropped      = "dropped"
+	EventStateChanged = "state_changed"
+	EventClosed       = "closed"
+)
+
+// ConnectionEvent is one entry of the connection history
+type ConnectionEvent struct {
+	Time       time.Time `json:"time"`
+	Connection string    `json:"connection"` // Server URL
+	Kind       string    `json:"kind"`
+	Detail     string    `json:"detail,omitempty"` // Drop reason, error or state transition
+}
+
+// EventLog keeps the most recent connection events in a fixed-size ring
+// One EventLog may be shared by several clients. A nil EventLog records nothing.
+type EventLog struct {
+	mu     sync.Mutex
+	events []ConnectionEvent
+	next   int  // Slot of the next event
+	full   bool // The ring has wrapped
+}
+
+// NewEventLog creates an event log keeping the last size events
+func NewEventLog(size int) *EventLog {
+	return &EventLog{events: make([]ConnectionEvent, max(size, 1))}
+}
+
+// record appends an event, overwriting the oldest one when the ring is full
+func (l *EventLog) record(connection, kind, detail string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events[l.next] = ConnectionEvent{Time: time.Now(), Connection: connection, Kind: kind, Detail: detail}
+	l.next = (l.next + 1) % len(l.events)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Events returns the recorded events, oldest first
+func (l *EventLog) Events() []ConnectionEvent {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.full {
+		return append([]ConnectionEvent(nil), l.events[:l.next]...)
+	}
+	out := make([]ConnectionEvent, 0, len(l.events))
+	out = append(out, l.events[l.next:]...)
+	return append(out, l.events[:l.next]...)
+}
@@ -0,0 +1,78 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestEventLog_Wraps(t *testing.T) {
+	log := NewEventLog(3)
+	for i := 0; i < 5; i++ {
+		log.record("ws://gw", EventDropped, fmt.Sprint(i))
+	}
+	events := log.Events()
+	if len(events) != 3 {
+		t.Fatalf("len(Events()) = %d, want 3", len(events))
+	}
+	for i, want := range []string{"2", "3", "4"} {
+		if events[i].Detail != want {
+			t.Errorf("Events()[%d].Detail = %q, want %q", i, events[i].Detail, want)
+		}
+	}
+
+	var none *EventLog
+	none.record("ws://gw", EventClosed, "")
+	if got := none.Events(); got != nil {
+		t.Errorf("nil log Events() = %v, want nil", got)
+	}
+}
+
+func TestClient_RecordsEvents(t *testing.T) {
+	transport := NewMemoryTransport()
+	events := NewEventLog(32)
+	client := NewClient(&Config{
+		ServerURL:         "ws://gw",
+		ReconnectInterval: 10 * time.Millisecond,
+		HeartbeatInterval: time.Minute,
+		ReadTimeout:       500 * time.Millisecond, // Bounds Close, which waits for the read loop
+		WriteTimeout:      5 * time.Second,
+		Transport:         transport,
+		Events:            events,
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	accepted := make(chan Conn, 1)
+	go func() {
+		conn, err := transport.Accept(ctx)
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	_ = (<-accepted).Close()
+	if _, err := transport.Accept(ctx); err != nil {
+		t.Fatalf("client did not reconnect: %v", err)
+	}
+	client.Close()
+
+	kinds := make(map[string]int)
+	for _, e := range events.Events() {
+		if e.Connection != "ws://gw" {
+			t.Errorf("event connection = %q, want ws://gw", e.Connection)
+		}
+		kinds[e.Kind]++
+	}
+	for kind, want := range map[string]int{EventConnected: 2, EventClosed: 1} {
+		if kinds[kind] != want {
+			t.Errorf("%s events = %d, want %d (all: %v)", kind, kinds[kind], want, kinds)
+		}
+	}
+	if kinds[EventDropped] == 0 || kinds[EventStateChanged] == 0 {
+		t.Errorf("missing dropped or state_changed events (all: %v)", kinds)
+	}
+}
@@ -0,0 +1,327 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// FailoverClient keeps an authenticated standby connection to a second gateway region and
+// fails over to it when the active connection degrades
+// Only the active connection quotes: sends go to it and only its messages reach the handler.
+// The standby answers heartbeats, rejects quote requests the gateway routes to it and drops
+// everything else. There is no failback: the degraded
+// connection reconnects by itself and becomes the new standby.
+type FailoverClient struct {
+	conns    [2]WSClient
+	names    [2]string
+	active   atomic.Int32
+	interval time.Duration // Health check interval, half a heartbeat interval
+	logger   *slog.Logger
+
+	mu                 sync.RWMutex
+	handler            MessageHandler
+	reconnectedHandler ReconnectedHandler
+	acks               [2]*mmv1.Message // Successful ConnectionAck of each connection's current session
+	started            [2]bool          // Connect succeeded; the client reconnects by itself from then on
+
+	degraded  int // Consecutive checks with the active connection not Ready (monitor only)
+	failovers atomic.Uint64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewFailoverClient creates a client that quotes on primary and keeps standby as a hot spare
+// A degraded active connection is replaced within one heartbeat interval.
+func NewFailoverClient(primary, standby WSClient, heartbeatInterval time.Duration, logger *slog.Logger) *FailoverClient {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	f := &FailoverClient{
+		conns:    [2]WSClient{primary, standby},
+		names:    [2]string{"primary", "standby"},
+		interval: heartbeatInterval / 2,
+		logger:   logger.With("component", "Failover"),
+	}
+	for i, conn := range f.conns {
+		conn.SetMessageHandler(func(msg *mmv1.Message) error { return f.onMessage(i, msg) })
+		conn.SetReconnectedHandler(func() { f.onReconnected(i) })
+	}
+	return f
+}
+
+// Connect connects both regions and starts monitoring the active connection
+// Only a primary failure is returned; the standby is retried until it connects.
+func (f *FailoverClient) Connect(ctx context.Context) error {
+	f.ctx, f.cancel = context.WithCancel(ctx)
+	if err := f.conns[0].Connect(ctx); err != nil {
+		f.cancel()
+		return err
+	}
+	f.mu.Lock()
+	f.started[0] = true
+	f.mu.Unlock()
+	f.connectStandby()
+
+	f.wg.Add(1)
+	go f.monitor()
+	return nil
+}
+
+// connectStandby connects the standby region if it never connected
+func (f *FailoverClient) connectStandby() {
+	f.mu.RLock()
+	started := f.started[1]
+	f.mu.RUnlock()
+	if started {
+		return
+	}
+	if err := f.conns[1].Connect(f.ctx); err != nil {
+		f.logger.Warn("Standby connection failed, will retry", "error", err)
+		return
+	}
+	f.mu.Lock()
+	f.started[1] = true
+	f.mu.Unlock()
+}
+
+// Close closes both connections
+func (f *FailoverClient) Close() error {
+	if f.cancel != nil {
+		f.cancel()
+	}
+	f.wg.Wait()
+	return errors.Join(f.conns[0].Close(), f.conns[1].Close())
+}
+
+// monitor checks the active connection every half heartbeat interval until Close
+func (f *FailoverClient) monitor() {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-ticker.C:
+			f.check()
+		}
+	}
+}
+
+// check fails over when the active connection was not Ready at two consecutive checks and the
+// standby is
+func (f *FailoverClient) check() {
+	f.connectStandby()
+
+	active := int(f.active.Load())
+	if f.conns[active].GetState() == StateReady {
+		f.degraded = 0
+		return
+	}
+	f.degraded++
+	if f.degraded < 2 {
+		return
+	}
+	standby := 1 - active
+	if f.conns[standby].GetState() != StateReady {
+		if f.degraded == 2 {
+			f.logger.Error("Active connection degraded and standby not ready",
+				"active", f.names[active],
+				"activeState", f.conns[active].GetState().String(),
+				"standbyState", f.conns[standby].GetState().String())
+		}
+		return
+	}
+	f.promote(standby)
+}
+
+// promote makes connection i the active one
+// The standby's ConnectionAck is replayed to the handler, as if the session had just started on it
+func (f *FailoverClient) promote(i int) {
+	old := 1 - i
+	f.active.Store(int32(i))
+	f.degraded = 0
+	f.failovers.Add(1)
+	f.logger.Warn("WebSocket failed over",
+		"from", f.names[old],
+		"to", f.names[i],
+		"fromState", f.conns[old].GetState().String())
+
+	// A degraded connection that is still up never completed its handshake; start a new session
+	if f.conns[old].IsConnected() {
+		f.conns[old].TriggerReconnect()
+	}
+
+	f.mu.RLock()
+	ack, handler, reconnected := f.acks[i], f.handler, f.reconnectedHandler
+	f.mu.RUnlock()
+	if ack != nil && handler != nil {
+		if err := handler(ack); err != nil {
+			f.logger.Error("Message handler error", "error", err)
+		}
+	}
+	if reconnected != nil {
+		go reconnected()
+	}
+}
+
+// onMessage routes a message of connection i
+func (f *FailoverClient) onMessage(i int, msg *mmv1.Message) error {
+	if ack := msg.GetConnectionAck(); ack != nil && ack.Success {
+		f.mu.Lock()
+		f.acks[i] = msg
+		f.mu.Unlock()
+	}
+
+	if int(f.active.Load()) == i {
+		f.mu.RLock()
+		handler := f.handler
+		f.mu.RUnlock()
+		if handler != nil {
+			return handler(msg)
+		}
+		return nil
+	}
+	return f.onStandbyMessage(i, msg)
+}
+
+// onStandbyMessage handles a message of the standby connection
+// The standby authenticates and keeps its heartbeat; it never quotes or pushes depth. A quote
+// request routed to it is rejected at once, so the taker does not wait for its deadline.
+func (f *FailoverClient) onStandbyMessage(i int, msg *mmv1.Message) error {
+	switch {
+	case msg.GetConnectionAck() != nil:
+		ack := msg.GetConnectionAck()
+		if !ack.Success {
+			f.logger.Error("Standby connection rejected", "connection", f.names[i], "error", ack.ErrorMessage)
+			return nil
+		}
+		f.conns[i].SetState(StateReady)
+		f.logger.Info("Standby connection authenticated", "connection", f.names[i], "sessionId", ack.SessionId)
+	case msg.GetHeartbeat() != nil:
+		if msg.GetHeartbeat().Ping {
+			return f.conns[i].Send(&mmv1.Message{
+				Type:      mmv1.MessageType_MESSAGE_TYPE_HEARTBEAT,
+				Timestamp: time.Now().UnixMilli(),
+				Payload: &mmv1.Message_Heartbeat{
+					Heartbeat: &mmv1.Heartbeat{Pong: true},
+				},
+			})
+		}
+	case msg.GetQuoteRequest() != nil:
+		req := msg.GetQuoteRequest()
+		f.logger.Warn("Rejecting quote request on standby connection", "connection", f.names[i], "quoteId", req.QuoteId)
+		return f.conns[i].Send(standbyReject(req))
+	default:
+		f.logger.Warn("Dropping message on standby connection", "connection", f.names[i], "type", msg.Type.String())
+	}
+	return nil
+}
+
+// standbyReject returns the reject of a quote request that reached the standby connection
+func standbyReject(req *mmv1.QuoteRequest) *mmv1.Message {
+	return &mmv1.Message{
+		Type:      mmv1.MessageType_MESSAGE_TYPE_QUOTE_REJECT,
+		Timestamp: time.Now().UnixMilli(),
+		Payload: &mmv1.Message_QuoteReject{
+			QuoteReject: &mmv1.QuoteReject{
+				QuoteId: req.QuoteId,
+				ChainId: req.ChainId,
+				MmId:    req.MmId,
+				Reason:  mmv1.RejectReason_REJECT_REASON_INTERNAL_ERROR,
+				Message: "standby connection does not quote",
+			},
+		},
+	}
+}
+
+// onReconnected handles a reconnect of connection i; only the active one reaches the handler
+func (f *FailoverClient) onReconnected(i int) {
+	f.mu.Lock()
+	f.acks[i] = nil // Sessions are authenticated anew
+	handler := f.reconnectedHandler
+	f.mu.Unlock()
+
+	if int(f.active.Load()) != i {
+		f.logger.Info("Standby connection reconnected", "connection", f.names[i])
+		return
+	}
+	if handler != nil {
+		handler()
+	}
+}
+
+// Active returns the name of the active connection, "primary" or "standby"
+func (f *FailoverClient) Active() string {
+	return f.names[f.active.Load()]
+}
+
+// Failovers returns how many times the client failed over
+func (f *FailoverClient) Failovers() uint64 {
+	return f.failovers.Load()
+}
+
+// activeConn returns the active connection
+func (f *FailoverClient) activeConn() WSClient {
+	return f.conns[f.active.Load()]
+}
+
+// Send sends a Protobuf message on the active connection
+func (f *FailoverClient) Send(msg *mmv1.Message) error {
+	return f.activeConn().Send(msg)
+}
+
+// SendBatch sends several Protobuf messages on the active connection
+func (f *FailoverClient) SendBatch(msgs []*mmv1.Message) error {
+	return f.activeConn().SendBatch(msgs)
+}
+
+// SetMessageHandler sets the message handler callback
+func (f *FailoverClient) SetMessageHandler(handler MessageHandler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handler = handler
+}
+
+// SetReconnectedHandler sets the reconnection success callback, also invoked after a failover
+func (f *FailoverClient) SetReconnectedHandler(handler ReconnectedHandler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reconnectedHandler = handler
+}
+
+// IsConnected checks if the active connection is connected
+func (f *FailoverClient) IsConnected() bool {
+	return f.activeConn().IsConnected()
+}
+
+// GetState gets the state of the active connection
+func (f *FailoverClient) GetState() ConnectionState {
+	return f.activeConn().GetState()
+}
+
+// SetState sets the state of the active connection
+func (f *FailoverClient) SetState(state ConnectionState) {
+	f.activeConn().SetState(state)
+}
+
+// TriggerReconnect reconnects the active connection
+func (f *FailoverClient) TriggerReconnect() {
+	f.activeConn().TriggerReconnect()
+}
+
+// QueueDepth returns the outbound queue depth of the active connection
+func (f *FailoverClient) QueueDepth() int {
+	depth, _ := QueueDepth(f.activeConn())
+	return depth
+}
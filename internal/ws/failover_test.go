@@ -0,0 +1,195 @@
+package ws
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// fakeConn is an in-memory WSClient recording sends
+type fakeConn struct {
+	mu         sync.Mutex
+	state      ConnectionState
+	sent       []*mmv1.Message
+	handler    MessageHandler
+	reconnects int
+}
+
+func (c *fakeConn) Connect(context.Context) error { c.SetState(StateConnected); return nil }
+func (c *fakeConn) Close() error                  { c.SetState(StateDisconnected); return nil }
+func (c *fakeConn) Send(msg *mmv1.Message) error  { return c.SendBatch([]*mmv1.Message{msg}) }
+func (c *fakeConn) SendBatch(msgs []*mmv1.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, msgs...)
+	return nil
+}
+func (c *fakeConn) SetMessageHandler(h MessageHandler)       { c.handler = h }
+func (c *fakeConn) SetReconnectedHandler(ReconnectedHandler) {}
+func (c *fakeConn) IsConnected() bool {
+	state := c.GetState()
+	return state == StateConnected || state == StateReady
+}
+func (c *fakeConn) GetState() ConnectionState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+func (c *fakeConn) SetState(state ConnectionState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state = state
+}
+func (c *fakeConn) TriggerReconnect() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reconnects++
+}
+func (c *fakeConn) Sent() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.sent)
+}
+
+func ackMessage() *mmv1.Message {
+	return &mmv1.Message{
+		Type:    mmv1.MessageType_MESSAGE_TYPE_CONNECTION_ACK,
+		Payload: &mmv1.Message_ConnectionAck{ConnectionAck: &mmv1.ConnectionAck{Success: true}},
+	}
+}
+
+// newTestFailover creates a failover client over two connected fakes, with the primary Ready
+// and the standby authenticated, recording the messages that reach the handler
+func newTestFailover(t *testing.T) (*FailoverClient, *fakeConn, *fakeConn, *[]*mmv1.Message) {
+	t.Helper()
+	primary := &fakeConn{state: StateConnected}
+	standby := &fakeConn{state: StateConnected}
+	f := NewFailoverClient(primary, standby, 0, nil)
+	f.started = [2]bool{true, true}
+	var received []*mmv1.Message
+	f.SetMessageHandler(func(msg *mmv1.Message) error {
+		received = append(received, msg)
+		return nil
+	})
+	primary.SetState(StateReady)
+	if err := standby.handler(ackMessage()); err != nil {
+		t.Fatalf("standby ack: %v", err)
+	}
+	return f, primary, standby, &received
+}
+
+func TestFailoverClient_StandbyStaysIdle(t *testing.T) {
+	f, primary, standby, received := newTestFailover(t)
+
+	if got := standby.GetState(); got != StateReady {
+		t.Errorf("standby state = %v, want %v", got, StateReady)
+	}
+	ping := &mmv1.Message{
+		Type:    mmv1.MessageType_MESSAGE_TYPE_HEARTBEAT,
+		Payload: &mmv1.Message_Heartbeat{Heartbeat: &mmv1.Heartbeat{Ping: true}},
+	}
+	_ = standby.handler(ping)
+	_ = standby.handler(&mmv1.Message{
+		Type:    mmv1.MessageType_MESSAGE_TYPE_QUOTE_REQUEST,
+		Payload: &mmv1.Message_QuoteRequest{QuoteRequest: &mmv1.QuoteRequest{}},
+	})
+	if len(*received) != 0 {
+		t.Errorf("handler received %d standby messages, want 0", len(*received))
+	}
+	if got := standby.Sent(); got != 2 {
+		t.Errorf("standby sent %d messages, want 2 (pong, reject)", got)
+	}
+
+	_ = primary.handler(ping)
+	if len(*received) != 1 {
+		t.Errorf("handler received %d primary messages, want 1", len(*received))
+	}
+	if err := f.Send(ping); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got := primary.Sent(); got != 1 {
+		t.Errorf("primary sent %d messages, want 1", got)
+	}
+}
+
+func TestFailoverClient_StandbyRejectsQuoteRequests(t *testing.T) {
+	_, primary, standby, received := newTestFailover(t)
+
+	req := &mmv1.QuoteRequest{QuoteId: "q1", ChainId: 56, MmId: "0xmm"}
+	if err := standby.handler(&mmv1.Message{
+		Type:    mmv1.MessageType_MESSAGE_TYPE_QUOTE_REQUEST,
+		Payload: &mmv1.Message_QuoteRequest{QuoteRequest: req},
+	}); err != nil {
+		t.Fatalf("standby handler: %v", err)
+	}
+	if len(*received) != 0 || primary.Sent() != 0 {
+		t.Fatalf("standby quote request reached the handler or the primary")
+	}
+	if got := standby.Sent(); got != 1 {
+		t.Fatalf("standby sent %d messages, want 1 (reject)", got)
+	}
+	reject := standby.sent[0].GetQuoteReject()
+	if reject == nil || reject.QuoteId != req.QuoteId || reject.ChainId != req.ChainId || reject.MmId != req.MmId {
+		t.Fatalf("standby sent %v, want a reject of %v", standby.sent[0], req)
+	}
+	if reject.Reason != mmv1.RejectReason_REJECT_REASON_INTERNAL_ERROR {
+		t.Errorf("reject reason = %v, want %v", reject.Reason, mmv1.RejectReason_REJECT_REASON_INTERNAL_ERROR)
+	}
+}
+
+func TestFailoverClient_FailsOver(t *testing.T) {
+	f, primary, standby, received := newTestFailover(t)
+	reconnected := make(chan struct{}, 1)
+	f.SetReconnectedHandler(func() { reconnected <- struct{}{} })
+
+	f.check()
+	if f.Active() != "primary" {
+		t.Fatalf("Active() = %v, want primary while it is Ready", f.Active())
+	}
+
+	// Authenticated but never acknowledged: degraded
+	primary.SetState(StateConnected)
+	f.check()
+	if f.Active() != "primary" {
+		t.Fatalf("Active() = %v, want primary after one degraded check", f.Active())
+	}
+	f.check()
+	if f.Active() != "standby" {
+		t.Fatalf("Active() = %v, want standby after two degraded checks", f.Active())
+	}
+	if f.Failovers() != 1 {
+		t.Errorf("Failovers() = %v, want 1", f.Failovers())
+	}
+	if primary.reconnects != 1 {
+		t.Errorf("primary reconnects = %v, want 1", primary.reconnects)
+	}
+	if len(*received) != 1 || (*received)[0].GetConnectionAck() == nil {
+		t.Errorf("handler received %v, want the standby ConnectionAck replayed", *received)
+	}
+	<-reconnected
+
+	if err := f.Send(ackMessage()); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got := standby.Sent(); got != 1 {
+		t.Errorf("standby sent %d messages, want 1", got)
+	}
+	if got := primary.Sent(); got != 0 {
+		t.Errorf("primary sent %d messages, want 0", got)
+	}
+}
+
+func TestFailoverClient_StandbyNotReady(t *testing.T) {
+	f, primary, standby, _ := newTestFailover(t)
+	standby.SetState(StateDisconnected)
+	primary.SetState(StateDisconnected)
+
+	for i := 0; i < 3; i++ {
+		f.check()
+	}
+	if f.Active() != "primary" {
+		t.Errorf("Active() = %v, want primary when the standby is not ready", f.Active())
+	}
+}
@@ -0,0 +1,47 @@
+package ws
+
+import (
+	"math"
+	"time"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// ClientHealth is client-side state attached to outgoing heartbeat pings
+// It travels as Heartbeat.health; servers built from an older mm.proto skip the field.
+type ClientHealth struct {
+	QueueDepth   int
+	DepthPushAge time.Duration // Since the stalest pair's last depth push
+	QuoteP95     time.Duration // Quote handling latency over recent requests
+	OpenQuotes   int           // Signed quotes not yet filled or expired
+	RejectRate   float64       // Rejected share of all quote requests, 0-1
+}
+
+// HealthFunc returns the current client health
+type HealthFunc func() ClientHealth
+
+// SetHeartbeatHealth attaches health to hb, replacing health attached before
+func SetHeartbeatHealth(hb *mmv1.Heartbeat, health ClientHealth) {
+	hb.Health = &mmv1.ClientHealth{
+		QueueDepth:     uint64(max(health.QueueDepth, 0)),
+		DepthPushAgeMs: uint64(max(health.DepthPushAge.Milliseconds(), 0)),
+		QuoteP95Us:     uint64(max(health.QuoteP95.Microseconds(), 0)),
+		OpenQuotes:     uint64(max(health.OpenQuotes, 0)),
+		RejectRateBps:  uint32(math.Round(min(max(health.RejectRate, 0), 1) * 10000)),
+	}
+}
+
+// HeartbeatHealth returns the health attached to hb, false if it carries none
+func HeartbeatHealth(hb *mmv1.Heartbeat) (ClientHealth, bool) {
+	h := hb.GetHealth()
+	if h == nil {
+		return ClientHealth{}, false
+	}
+	return ClientHealth{
+		QueueDepth:   int(h.QueueDepth),
+		DepthPushAge: time.Duration(h.DepthPushAgeMs) * time.Millisecond,
+		QuoteP95:     time.Duration(h.QuoteP95Us) * time.Microsecond,
+		OpenQuotes:   int(h.OpenQuotes),
+		RejectRate:   float64(h.RejectRateBps) / 10000,
+	}, true
+}
@@ -0,0 +1,59 @@
+package ws
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+func TestHeartbeatHealth_RoundTrip(t *testing.T) {
+	want := ClientHealth{QueueDepth: 12, DepthPushAge: 1500 * time.Millisecond, QuoteP95: 850 * time.Microsecond, OpenQuotes: 3, RejectRate: 0.025}
+	hb := &mmv1.Heartbeat{Ping: true}
+	SetHeartbeatHealth(hb, want)
+
+	// The field survives the wire
+	data, err := proto.Marshal(&mmv1.Message{Payload: &mmv1.Message_Heartbeat{Heartbeat: hb}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	msg := &mmv1.Message{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	got, ok := HeartbeatHealth(msg.GetHeartbeat())
+	if !ok {
+		t.Fatal("HeartbeatHealth() = false, want health")
+	}
+	if got != want {
+		t.Errorf("HeartbeatHealth() = %+v, want %+v", got, want)
+	}
+	if !msg.GetHeartbeat().Ping {
+		t.Error("Ping = false, want true")
+	}
+
+	if _, ok := HeartbeatHealth(&mmv1.Heartbeat{Ping: true}); ok {
+		t.Error("HeartbeatHealth(plain) = true, want no health")
+	}
+}
+
+func TestHeartbeat_PingCarriesHealth(t *testing.T) {
+	conn := &fakeConn{state: StateReady}
+	h := NewHeartbeat(conn, &HeartbeatConfig{
+		Interval:    time.Second,
+		ReadTimeout: time.Second,
+		Health:      func() ClientHealth { return ClientHealth{QuoteP95: time.Millisecond} },
+	}, nil)
+	if err := h.sendPing(); err != nil {
+		t.Fatalf("sendPing failed: %v", err)
+	}
+	if len(conn.sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(conn.sent))
+	}
+	got, ok := HeartbeatHealth(conn.sent[0].GetHeartbeat())
+	if !ok || got.QuoteP95 != time.Millisecond {
+		t.Errorf("HeartbeatHealth() = %+v, %v, want QuoteP95 1ms", got, ok)
+	}
+}
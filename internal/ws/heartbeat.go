@@ -14,6 +14,7 @@ import (
 type HeartbeatConfig struct {
 	Interval    time.Duration // Heartbeat interval
 	ReadTimeout time.Duration // Read timeout (triggers reconnection on timeout)
+	Health      HealthFunc    // Health attached to pings, nil = none; QueueDepth is filled in from the client
 }
 
 // Heartbeat heartbeat manager
@@ -96,14 +97,20 @@ func (h *Heartbeat) check() {
 
 // sendPing sends heartbeat ping
 func (h *Heartbeat) sendPing() error {
+	hb := &mmv1.Heartbeat{
+		Ping: true,
+		Pong: false,
+	}
+	if h.config.Health != nil {
+		health := h.config.Health()
+		health.QueueDepth, _ = QueueDepth(h.client)
+		SetHeartbeatHealth(hb, health)
+	}
 	msg := &mmv1.Message{
 		Type:      mmv1.MessageType_MESSAGE_TYPE_HEARTBEAT,
 		Timestamp: time.Now().UnixMilli(),
 		Payload: &mmv1.Message_Heartbeat{
-			Heartbeat: &mmv1.Heartbeat{
-				Ping: true,
-				Pong: false,
-			},
+			Heartbeat: hb,
 		},
 	}
 
@@ -0,0 +1,177 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// memoryQueueSize is the frames buffered per direction of a memory connection
+const memoryQueueSize = 256
+
+// MemoryTransport is an in-memory Transport for tests
+// Every Dial creates a connected pair of conns and blocks until the server end is taken
+// with Accept, so a test plays the server without sockets or a WebSocket stack.
+type MemoryTransport struct {
+	accept chan Conn
+}
+
+// NewMemoryTransport creates an in-memory transport
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{accept: make(chan Conn)}
+}
+
+// Dial creates a connection and hands its server end to Accept
+func (t *MemoryTransport) Dial(ctx context.Context) (Conn, error) {
+	client, server := newMemoryPipe()
+	select {
+	case t.accept <- server:
+		return client, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Accept returns the server end of the next dialed connection
+func (t *MemoryTransport) Accept(ctx context.Context) (Conn, error) {
+	select {
+	case conn := <-t.accept:
+		return conn, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// memoryPipe is the state shared by both ends of a memory connection
+type memoryPipe struct {
+	once     sync.Once
+	closed   chan struct{}
+	closedBy *memoryConn
+}
+
+// memoryConn is one end of a memory connection
+type memoryConn struct {
+	pipe          *memoryPipe
+	in            chan []byte
+	out           chan []byte
+	readDeadline  deadline
+	writeDeadline deadline
+}
+
+// newMemoryPipe creates the two connected ends of a memory connection
+func newMemoryPipe() (*memoryConn, *memoryConn) {
+	pipe := &memoryPipe{closed: make(chan struct{})}
+	a := make(chan []byte, memoryQueueSize)
+	b := make(chan []byte, memoryQueueSize)
+	return &memoryConn{pipe: pipe, in: a, out: b}, &memoryConn{pipe: pipe, in: b, out: a}
+}
+
+// ReadFrame returns the next frame; frames sent before a close are still delivered
+func (c *memoryConn) ReadFrame() ([]byte, error) {
+	select {
+	case frame := <-c.in:
+		return frame, nil
+	default:
+	}
+
+	timeout, stop := c.readDeadline.timer()
+	defer stop()
+	select {
+	case frame := <-c.in:
+		return frame, nil
+	case <-c.pipe.closed:
+		select {
+		case frame := <-c.in:
+			return frame, nil
+		default:
+		}
+		return nil, c.closeErr()
+	case <-timeout:
+		return nil, os.ErrDeadlineExceeded
+	}
+}
+
+// WriteFrame queues a copy of frame for the peer
+func (c *memoryConn) WriteFrame(frame []byte) error {
+	select {
+	case <-c.pipe.closed:
+		return c.closeErr()
+	default:
+	}
+
+	frame = append([]byte(nil), frame...)
+	timeout, stop := c.writeDeadline.timer()
+	defer stop()
+	select {
+	case c.out <- frame:
+		return nil
+	case <-c.pipe.closed:
+		return c.closeErr()
+	case <-timeout:
+		return os.ErrDeadlineExceeded
+	}
+}
+
+// Flush does nothing: frames are queued as they are written
+func (c *memoryConn) Flush() error { return nil }
+
+// SetReadDeadline sets the deadline of later reads
+func (c *memoryConn) SetReadDeadline(t time.Time) error {
+	c.readDeadline.set(t)
+	return nil
+}
+
+// SetWriteDeadline sets the deadline of later writes
+func (c *memoryConn) SetWriteDeadline(t time.Time) error {
+	c.writeDeadline.set(t)
+	return nil
+}
+
+// Shutdown closes the connection; the peer reads ErrClosedByPeer
+func (c *memoryConn) Shutdown() error { return c.Close() }
+
+// Close closes the connection; the peer reads ErrClosedByPeer
+func (c *memoryConn) Close() error {
+	c.pipe.once.Do(func() {
+		c.pipe.closedBy = c
+		close(c.pipe.closed)
+	})
+	return nil
+}
+
+// closeErr is the error of an operation on the closed pipe
+func (c *memoryConn) closeErr() error {
+	if c.pipe.closedBy == c {
+		return net.ErrClosed
+	}
+	return fmt.Errorf("memory transport: %w", ErrClosedByPeer)
+}
+
+// deadline is the deadline of one direction of a memory connection
+// A new deadline applies to the next call, not to one already blocked.
+type deadline struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+// set sets the deadline; zero means none
+func (d *deadline) set(t time.Time) {
+	d.mu.Lock()
+	d.t = t
+	d.mu.Unlock()
+}
+
+// timer returns a channel firing at the deadline (nil if none) and a function releasing it
+func (d *deadline) timer() (<-chan time.Time, func() bool) {
+	d.mu.Lock()
+	t := d.t
+	d.mu.Unlock()
+	if t.IsZero() {
+		return nil, func() bool { return false }
+	}
+	timer := time.NewTimer(time.Until(t))
+	return timer.C, timer.Stop
+}
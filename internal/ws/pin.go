@@ -0,0 +1,103 @@
+package ws
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Pin prefixes
+const (
+	pinPrefixKey  = "sha256/"      // SHA-256 of the certificate's SubjectPublicKeyInfo (HPKP style)
+	pinPrefixCert = "cert-sha256/" // SHA-256 of the whole DER certificate
+)
+
+// ErrPinMismatch is returned when no certificate of the gateway's chain matches a pin
+var ErrPinMismatch = errors.New("no certificate matches the pinned hashes")
+
+// Pin is a pinned certificate or public key hash of the gateway
+type Pin struct {
+	cert bool // Hash of the whole certificate, otherwise of its public key
+	hash [sha256.Size]byte
+}
+
+// ParsePin parses "sha256/<base64>" (public key) or "cert-sha256/<base64>" (certificate)
+func ParsePin(s string) (Pin, error) {
+	var pin Pin
+	var encoded string
+	switch {
+	case strings.HasPrefix(s, pinPrefixCert):
+		pin.cert = true
+		encoded = strings.TrimPrefix(s, pinPrefixCert)
+	case strings.HasPrefix(s, pinPrefixKey):
+		encoded = strings.TrimPrefix(s, pinPrefixKey)
+	default:
+		return Pin{}, fmt.Errorf("pin %q: want sha256/<base64> or cert-sha256/<base64>", s)
+	}
+	hash, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return Pin{}, fmt.Errorf("pin %q: %w", s, err)
+	}
+	if len(hash) != sha256.Size {
+		return Pin{}, fmt.Errorf("pin %q: hash is %d bytes, want %d", s, len(hash), sha256.Size)
+	}
+	copy(pin.hash[:], hash)
+	return pin, nil
+}
+
+// ParsePins parses a list of pins
+func ParsePins(pins []string) ([]Pin, error) {
+	out := make([]Pin, 0, len(pins))
+	for _, s := range pins {
+		pin, err := ParsePin(s)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, pin)
+	}
+	return out, nil
+}
+
+// String returns the pin in the form ParsePin accepts
+func (p Pin) String() string {
+	prefix := pinPrefixKey
+	if p.cert {
+		prefix = pinPrefixCert
+	}
+	return prefix + base64.StdEncoding.EncodeToString(p.hash[:])
+}
+
+// matches reports whether cert is the pinned certificate or carries the pinned public key
+func (p Pin) matches(cert *x509.Certificate) bool {
+	var hash [sha256.Size]byte
+	if p.cert {
+		hash = sha256.Sum256(cert.Raw)
+	} else {
+		hash = sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	}
+	return bytes.Equal(hash[:], p.hash[:])
+}
+
+// verifyPins returns a tls.Config.VerifyConnection accepting the gateway only if a
+// certificate of its verified chain matches one of pins
+// Pinning is checked on top of the usual CA validation, never instead of it, so a certificate
+// issued by a compromised CA for the gateway's name is refused.
+func verifyPins(pins []Pin) func(tls.ConnectionState) error {
+	return func(state tls.ConnectionState) error {
+		for _, chain := range state.VerifiedChains {
+			for _, cert := range chain {
+				for _, pin := range pins {
+					if pin.matches(cert) {
+						return nil
+					}
+				}
+			}
+		}
+		return ErrPinMismatch
+	}
+}
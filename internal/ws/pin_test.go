@@ -0,0 +1,97 @@
+package ws
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func pinOf(t *testing.T, prefix string, data []byte) Pin {
+	t.Helper()
+	hash := sha256.Sum256(data)
+	pin, err := ParsePin(prefix + base64.StdEncoding.EncodeToString(hash[:]))
+	if err != nil {
+		t.Fatalf("ParsePin failed: %v", err)
+	}
+	return pin
+}
+
+func TestParsePin(t *testing.T) {
+	valid := "sha256/" + base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))
+	tests := []struct {
+		pin     string
+		wantErr bool
+	}{
+		{valid, false},
+		{"cert-" + valid, false},
+		{"md5/" + strings.TrimPrefix(valid, "sha256/"), true},
+		{"sha256/not base64!", true},
+		{"sha256/" + base64.StdEncoding.EncodeToString([]byte("short")), true},
+	}
+	for _, tt := range tests {
+		pin, err := ParsePin(tt.pin)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePin(%q) error = %v, wantErr %v", tt.pin, err, tt.wantErr)
+			continue
+		}
+		if err == nil && pin.String() != tt.pin {
+			t.Errorf("String() = %q, want %q", pin.String(), tt.pin)
+		}
+	}
+}
+
+func TestWebSocketTransport_Pins(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		_ = conn.Close()
+	}))
+	defer server.Close()
+	wsURL := "wss" + strings.TrimPrefix(server.URL, "https")
+	cert := server.Certificate()
+	roots := server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	tests := []struct {
+		name string
+		pin  Pin
+		want error
+	}{
+		{"public key", pinOf(t, pinPrefixKey, cert.RawSubjectPublicKeyInfo), nil},
+		{"certificate", pinOf(t, pinPrefixCert, cert.Raw), nil},
+		{"other key", pinOf(t, pinPrefixKey, []byte("another key")), ErrPinMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := NewWebSocketTransport(&Config{
+				ServerURL: wsURL,
+				TLSConfig: &tls.Config{RootCAs: roots},
+				TLSPins:   []Pin{tt.pin},
+			}, nil)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			conn, err := transport.Dial(ctx)
+			if conn != nil {
+				_ = conn.Close()
+			}
+			if tt.want == nil && err != nil {
+				t.Errorf("Dial() error = %v, want nil", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("Dial() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package ws
 
 import (
+	"log/slog"
+	"math/rand"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -81,3 +84,89 @@ func (r *Reconnector) Reset() {
 	r.attempts.Store(0)
 	r.interval = r.config.InitialInterval
 }
+
+// StormGuard limits reconnect attempts across all clients of the process
+// Once more than MaxAttempts reconnects happen within Window, every client backs off for a
+// randomized cool-off of one to two CoolOff periods: a server rejecting every session is not
+// redialed at the rate of the per-client backoff, which restarts after each short-lived session.
+type StormGuard struct {
+	maxAttempts int
+	window      time.Duration
+	coolOff     time.Duration
+	logger      *slog.Logger
+	now         func() time.Time
+	jitter      func(time.Duration) time.Duration // Random duration in [0, d)
+
+	mu       sync.Mutex
+	attempts []time.Time // Within window, oldest first
+	until    time.Time   // End of the current cool-off
+	storms   uint64
+}
+
+// NewStormGuard creates a storm guard allowing maxAttempts reconnects per window
+func NewStormGuard(maxAttempts int, window, coolOff time.Duration, logger *slog.Logger) *StormGuard {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &StormGuard{
+		maxAttempts: maxAttempts,
+		window:      window,
+		coolOff:     coolOff,
+		logger:      logger.With("component", "ReconnectStormGuard"),
+		now:         time.Now,
+		jitter:      func(d time.Duration) time.Duration { return time.Duration(rand.Int63n(int64(d))) },
+	}
+}
+
+// Delay returns how long a client must wait before its next reconnect attempt
+// 0 allows the attempt, which is then counted. A nil guard allows every attempt.
+func (g *StormGuard) Delay() time.Duration {
+	if g == nil {
+		return 0
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	if now.Before(g.until) {
+		return g.until.Sub(now)
+	}
+	cutoff := now.Add(-g.window)
+	for len(g.attempts) > 0 && !g.attempts[0].After(cutoff) {
+		g.attempts = g.attempts[1:]
+	}
+	if len(g.attempts) < g.maxAttempts {
+		g.attempts = append(g.attempts, now)
+		return 0
+	}
+
+	coolOff := g.coolOff + g.jitter(g.coolOff)
+	g.until = now.Add(coolOff)
+	g.attempts = g.attempts[:0]
+	g.storms++
+	g.logger.Error("Reconnect storm detected, cooling off",
+		"attempts", g.maxAttempts,
+		"window", g.window,
+		"coolOff", coolOff)
+	return coolOff
+}
+
+// CoolOff returns the remaining cool-off, 0 when reconnects are allowed
+func (g *StormGuard) CoolOff() time.Duration {
+	if g == nil {
+		return 0
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return max(g.until.Sub(g.now()), 0)
+}
+
+// Storms returns how many cool-offs were started
+func (g *StormGuard) Storms() uint64 {
+	if g == nil {
+		return 0
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.storms
+}
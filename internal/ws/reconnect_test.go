@@ -0,0 +1,63 @@
+package ws
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStormGuard(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	g := NewStormGuard(3, time.Minute, 10*time.Minute, nil)
+	g.now = func() time.Time { return now }
+	g.jitter = func(d time.Duration) time.Duration { return d / 2 }
+
+	for i := 0; i < 3; i++ {
+		if wait := g.Delay(); wait != 0 {
+			t.Fatalf("attempt %d: Delay() = %v, want 0", i, wait)
+		}
+		now = now.Add(10 * time.Second)
+	}
+	if wait := g.Delay(); wait != 15*time.Minute {
+		t.Fatalf("Delay() = %v, want 15m cool-off", wait)
+	}
+	if g.Storms() != 1 {
+		t.Errorf("Storms() = %v, want 1", g.Storms())
+	}
+
+	// Other clients wait for the rest of the cool-off
+	now = now.Add(5 * time.Minute)
+	if wait := g.Delay(); wait != 10*time.Minute {
+		t.Errorf("Delay() = %v, want 10m", wait)
+	}
+	if got := g.CoolOff(); got != 10*time.Minute {
+		t.Errorf("CoolOff() = %v, want 10m", got)
+	}
+
+	// After the cool-off, attempts are counted afresh
+	now = now.Add(10 * time.Minute)
+	if wait := g.Delay(); wait != 0 {
+		t.Errorf("Delay() after cool-off = %v, want 0", wait)
+	}
+	if got := g.CoolOff(); got != 0 {
+		t.Errorf("CoolOff() = %v, want 0", got)
+	}
+}
+
+func TestStormGuard_WindowSlides(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	g := NewStormGuard(2, time.Minute, time.Hour, nil)
+	g.now = func() time.Time { return now }
+
+	// Two attempts per minute never trigger a cool-off
+	for i := 0; i < 10; i++ {
+		if wait := g.Delay(); wait != 0 {
+			t.Fatalf("attempt %d: Delay() = %v, want 0", i, wait)
+		}
+		now = now.Add(30 * time.Second)
+	}
+
+	var nilGuard *StormGuard
+	if wait := nilGuard.Delay(); wait != 0 {
+		t.Errorf("nil Delay() = %v, want 0", wait)
+	}
+}
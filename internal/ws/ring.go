@@ -0,0 +1,131 @@
+package ws
+
+import (
+	"sync"
+	"sync/atomic"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// sendQueueSize is the capacity of the outbound ring (a power of two)
+const sendQueueSize = 1024
+
+// outbound is a queued write: the serialized frames of one Send or SendBatch
+// The sender owns it: the writer reports the outcome on done and does not touch it afterwards,
+// and the sender releases it once it has the outcome.
+type outbound struct {
+	frames []*[]byte
+	types  []mmv1.MessageType
+	gen    uint64     // Connection generation the write was queued for
+	err    error      // Outcome, set by the writer
+	done   chan error // Receives err once the frames are flushed or dropped
+
+	// Per frame: the pair of depth snapshots, and whether a newer snapshot in the burst replaces it
+	keys       []depthKey
+	isDepth    []bool
+	superseded []bool
+}
+
+// outboundPool reuses queued writes
+var outboundPool = sync.Pool{
+	New: func() any { return &outbound{done: make(chan error, 1)} },
+}
+
+// release returns the frames and the write to their pools
+func (o *outbound) release() {
+	for i, frame := range o.frames {
+		putFrameBuf(frame)
+		o.frames[i] = nil
+	}
+	for i := range o.keys {
+		o.keys[i] = depthKey{}
+	}
+	o.frames = o.frames[:0]
+	o.types = o.types[:0]
+	o.keys = o.keys[:0]
+	o.isDepth = o.isDepth[:0]
+	o.superseded = o.superseded[:0]
+	o.err = nil
+	outboundPool.Put(o)
+}
+
+// ringSlot is a ring entry; seq tells producers and the consumer whose turn the slot is
+type ringSlot struct {
+	seq  atomic.Uint64
+	item *outbound
+}
+
+// sendRing is a bounded lock-free queue with any number of producers and a single consumer
+// Producers claim a slot by advancing tail with a CAS and publish it by bumping the slot's
+// sequence; the consumer owns head and needs no atomic read-modify-write at all
+// (head is stored atomically only so len can read it from other goroutines).
+// Slot protocol (Vyukov): seq == pos means free for the producer of pos,
+// seq == pos+1 means filled for the consumer of pos.
+type sendRing struct {
+	slots []ringSlot
+	mask  uint64
+	_     [56]byte // Keep tail off the slots' cache line
+
+	tail atomic.Uint64 // Next position to claim (producers)
+	_    [56]byte
+
+	head atomic.Uint64 // Next position to read (written by the consumer only)
+}
+
+// newSendRing creates a ring; size must be a power of two
+func newSendRing(size int) *sendRing {
+	if size <= 0 || size&(size-1) != 0 {
+		panic("ws: ring size must be a power of two")
+	}
+	r := &sendRing{
+		slots: make([]ringSlot, size),
+		mask:  uint64(size - 1),
+	}
+	for i := range r.slots {
+		r.slots[i].seq.Store(uint64(i))
+	}
+	return r
+}
+
+// push queues item, returning false if the ring is full
+// Safe for concurrent use by any number of producers
+func (r *sendRing) push(item *outbound) bool {
+	for {
+		pos := r.tail.Load()
+		slot := &r.slots[pos&r.mask]
+		switch seq := slot.seq.Load(); {
+		case seq == pos:
+			if r.tail.CompareAndSwap(pos, pos+1) {
+				slot.item = item
+				slot.seq.Store(pos + 1)
+				return true
+			}
+		case seq < pos:
+			// The consumer has not freed this slot since the last lap
+			return false
+		}
+		// Another producer claimed pos first; retry with the new tail
+	}
+}
+
+// pop removes the oldest item, returning false if the ring is empty
+// Only the single consumer may call pop
+func (r *sendRing) pop() (*outbound, bool) {
+	head := r.head.Load()
+	slot := &r.slots[head&r.mask]
+	if slot.seq.Load() != head+1 {
+		return nil, false
+	}
+	item := slot.item
+	slot.item = nil
+	slot.seq.Store(head + uint64(len(r.slots)))
+	r.head.Store(head + 1)
+	return item, true
+}
+
+// len returns the number of queued items, including slots claimed but not yet published
+// Safe to call from any goroutine; the result is a snapshot
+func (r *sendRing) len() int {
+	head := r.head.Load() // Before tail, so tail >= head
+	return int(r.tail.Load() - head)
+}
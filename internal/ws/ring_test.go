@@ -0,0 +1,236 @@
+package ws
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSendRing_OrderAndFull(t *testing.T) {
+	r := newSendRing(4)
+	items := make([]*outbound, 5)
+	for i := range items {
+		items[i] = &outbound{gen: uint64(i)}
+	}
+
+	for i := 0; i < 4; i++ {
+		if !r.push(items[i]) {
+			t.Fatalf("push %d failed on a ring with free slots", i)
+		}
+	}
+	if r.push(items[4]) {
+		t.Fatal("push succeeded on a full ring")
+	}
+	if got := r.len(); got != 4 {
+		t.Fatalf("len = %d, want 4", got)
+	}
+
+	// Wrap around: free one slot, fill it, then drain in order
+	if got, _ := r.pop(); got != items[0] {
+		t.Fatalf("pop = %v, want item 0", got)
+	}
+	if !r.push(items[4]) {
+		t.Fatal("push failed after pop")
+	}
+	for i := 1; i < 5; i++ {
+		got, ok := r.pop()
+		if !ok || got != items[i] {
+			t.Fatalf("pop = %v, %v, want item %d", got, ok, i)
+		}
+	}
+	if _, ok := r.pop(); ok {
+		t.Fatal("pop succeeded on an empty ring")
+	}
+	if got := r.len(); got != 0 {
+		t.Fatalf("len = %d, want 0", got)
+	}
+}
+
+func TestSendRing_ConcurrentProducers(t *testing.T) {
+	const producers, perProducer = 4, 500
+	r := newSendRing(64)
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				// gen carries producer and sequence
+				item := &outbound{gen: uint64(p)<<32 | uint64(i)}
+				for !r.push(item) {
+					runtime.Gosched()
+				}
+			}
+		}(p)
+	}
+
+	next := make([]uint64, producers)
+	for received := 0; received < producers*perProducer; {
+		item, ok := r.pop()
+		if !ok {
+			runtime.Gosched()
+			continue
+		}
+		p, i := item.gen>>32, item.gen&(1<<32-1)
+		if i != next[p] {
+			t.Fatalf("producer %d: got item %d, want %d", p, i, next[p])
+		}
+		next[p]++
+		received++
+	}
+	wg.Wait()
+	if _, ok := r.pop(); ok {
+		t.Fatal("ring not empty after receiving every item")
+	}
+}
+
+func TestNewSendRing_RejectsInvalidSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("newSendRing(3) did not panic")
+		}
+	}()
+	newSendRing(3)
+}
+
+// benchConn returns the client end of a loopback TCP connection whose server end is drained,
+// so a benchmark write costs the syscall a WebSocket write costs
+func benchConn(b *testing.B) net.Conn {
+	b.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Fatal(err)
+	}
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		server, err := ln.Accept()
+		if err != nil {
+			close(accepted)
+			return
+		}
+		accepted <- server
+		_, _ = io.Copy(io.Discard, server)
+	}()
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		b.Fatal(err)
+	}
+	server := <-accepted
+	b.Cleanup(func() {
+		conn.Close()
+		if server != nil {
+			server.Close()
+		}
+		ln.Close()
+	})
+	return conn
+}
+
+// BenchmarkSendPath compares the ring with the previous mutex-per-Send write path, for many
+// senders writing small frames to a socket and waiting for the outcome of their write
+func BenchmarkSendPath(b *testing.B) {
+	frame := make([]byte, 512)
+
+	// Each Send holds the lock across its own write syscall
+	b.Run("mutex", func(b *testing.B) {
+		conn := benchConn(b)
+		var mu sync.Mutex
+		b.ReportAllocs()
+		b.SetParallelism(16)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				mu.Lock()
+				_, err := conn.Write(frame)
+				mu.Unlock()
+				if err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+	})
+
+	// Same wake-up protocol and completion signal as client.writeLoop: one flush per burst
+	b.Run("ring", func(b *testing.B) {
+		w := bufio.NewWriterSize(benchConn(b), 64*1024)
+		r := newSendRing(sendQueueSize)
+		var idle atomic.Bool
+		notify := make(chan struct{}, 1)
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		burst := make([]*outbound, 0, maxBurst)
+		send := func() {
+			var err error
+			for _, item := range burst {
+				if err == nil {
+					_, err = w.Write(*item.frames[0])
+				}
+			}
+			if err == nil {
+				err = w.Flush()
+			}
+			for _, item := range burst {
+				item.done <- err
+			}
+			burst = burst[:0]
+		}
+		collect := func() bool {
+			for len(burst) < maxBurst {
+				item, ok := r.pop()
+				if !ok {
+					break
+				}
+				burst = append(burst, item)
+			}
+			return len(burst) > 0
+		}
+		go func() {
+			defer close(done)
+			for {
+				if collect() {
+					send()
+					continue
+				}
+				idle.Store(true)
+				if collect() {
+					idle.Store(false)
+					send()
+					continue
+				}
+				select {
+				case <-notify:
+				case <-stop:
+					return
+				}
+			}
+		}()
+
+		b.ReportAllocs()
+		b.SetParallelism(16)
+		b.RunParallel(func(pb *testing.PB) {
+			item := &outbound{frames: []*[]byte{&frame}, done: make(chan error, 1)}
+			for pb.Next() {
+				for !r.push(item) {
+					runtime.Gosched()
+				}
+				if idle.CompareAndSwap(true, false) {
+					select {
+					case notify <- struct{}{}:
+					default:
+					}
+				}
+				if err := <-item.done; err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+		close(stop)
+		<-done
+	})
+}
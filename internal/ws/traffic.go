@@ -0,0 +1,102 @@
+package ws
+
+import (
+	"sort"
+	"sync"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+// Traffic directions
+const (
+	DirectionIn  = "in"
+	DirectionOut = "out"
+)
+
+// TrafficSizeBuckets are the upper bounds of the message size buckets, in bytes
+var TrafficSizeBuckets = []int{64, 256, 1024, 4096, 16384, 65536}
+
+// Traffic counts the messages on the wire by direction and message type
+// One Traffic may be shared by several clients. A nil Traffic counts nothing.
+type Traffic struct {
+	mu     sync.Mutex
+	counts map[trafficKey]*TrafficCount
+}
+
+// trafficKey identifies one direction and message type
+type trafficKey struct {
+	direction string
+	typ       mmv1.MessageType
+}
+
+// TrafficCount is the traffic of one message type in one direction
+type TrafficCount struct {
+	Direction string
+	Type      string
+	Messages  uint64
+	Bytes     uint64
+	Sizes     []uint64 // Messages per size bucket (not cumulative): one per TrafficSizeBuckets bound, then larger ones
+}
+
+// NewTraffic creates an empty traffic counter
+func NewTraffic() *Traffic {
+	return &Traffic{counts: make(map[trafficKey]*TrafficCount)}
+}
+
+// observe counts one message of size bytes
+func (t *Traffic) observe(direction string, typ mmv1.MessageType, size int) {
+	if t == nil {
+		return
+	}
+	bucket := sort.SearchInts(TrafficSizeBuckets, size)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := trafficKey{direction, typ}
+	count, ok := t.counts[key]
+	if !ok {
+		count = &TrafficCount{
+			Direction: direction,
+			Type:      EnumName(typ),
+			Sizes:     make([]uint64, len(TrafficSizeBuckets)+1),
+		}
+		t.counts[key] = count
+	}
+	count.Messages++
+	count.Bytes += uint64(size)
+	count.Sizes[bucket]++
+}
+
+// Totals returns the bytes received and sent so far
+func (t *Traffic) Totals() (in, out uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, count := range t.counts {
+		if key.direction == DirectionIn {
+			in += count.Bytes
+		} else {
+			out += count.Bytes
+		}
+	}
+	return in, out
+}
+
+// Snapshot returns the traffic so far, sorted by direction and message type
+func (t *Traffic) Snapshot() []TrafficCount {
+	t.mu.Lock()
+	out := make([]TrafficCount, 0, len(t.counts))
+	for _, count := range t.counts {
+		c := *count
+		c.Sizes = append([]uint64(nil), count.Sizes...)
+		out = append(out, c)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Direction != out[j].Direction {
+			return out[i].Direction < out[j].Direction
+		}
+		return out[i].Type < out[j].Type
+	})
+	return out
+}
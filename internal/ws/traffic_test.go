@@ -0,0 +1,106 @@
+package ws
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+func TestTraffic_Observe(t *testing.T) {
+	traffic := NewTraffic()
+	traffic.observe(DirectionOut, mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT, 2000)
+	traffic.observe(DirectionOut, mmv1.MessageType_MESSAGE_TYPE_DEPTH_SNAPSHOT, 100000)
+	traffic.observe(DirectionIn, mmv1.MessageType_MESSAGE_TYPE_HEARTBEAT, 64)
+
+	var nilTraffic *Traffic
+	nilTraffic.observe(DirectionIn, mmv1.MessageType_MESSAGE_TYPE_HEARTBEAT, 10) // Must not panic
+
+	if in, out := traffic.Totals(); in != 64 || out != 102000 {
+		t.Errorf("Totals() = %v, %v, want 64, 102000", in, out)
+	}
+
+	got := traffic.Snapshot()
+	if len(got) != 2 {
+		t.Fatalf("len(Snapshot()) = %v, want 2", len(got))
+	}
+	in, out := got[0], got[1]
+	if in.Direction != DirectionIn || in.Type != "MESSAGE_TYPE_HEARTBEAT" || in.Messages != 1 || in.Sizes[0] != 1 {
+		t.Errorf("in = %+v, want one heartbeat in the first bucket", in)
+	}
+	if out.Messages != 2 || out.Bytes != 102000 {
+		t.Errorf("out = %+v, want 2 messages of 102000 bytes", out)
+	}
+	if out.Sizes[3] != 1 || out.Sizes[len(TrafficSizeBuckets)] != 1 {
+		t.Errorf("out.Sizes = %v, want one in the 4096 bucket and one above the largest", out.Sizes)
+	}
+}
+
+func TestClient_CountsTraffic(t *testing.T) {
+	ack, _ := proto.Marshal(&mmv1.Message{
+		Type:    mmv1.MessageType_MESSAGE_TYPE_CONNECTION_ACK,
+		Payload: &mmv1.Message_ConnectionAck{ConnectionAck: &mmv1.ConnectionAck{Success: true}},
+	})
+	receivedCh := make(chan struct{}, 1)
+	server := mockWSServer(t, func(conn *websocket.Conn) {
+		conn.WriteMessage(websocket.BinaryMessage, ack)
+		if _, _, err := conn.ReadMessage(); err == nil {
+			receivedCh <- struct{}{}
+		}
+		conn.ReadMessage() // Hold the connection until the client closes it
+	})
+	defer server.Close()
+
+	traffic := NewTraffic()
+	client := NewClient(&Config{
+		ServerURL:         "ws" + strings.TrimPrefix(server.URL, "http"),
+		ReconnectInterval: time.Second,
+		HeartbeatInterval: 30 * time.Second,
+		ReadTimeout:       5 * time.Second,
+		WriteTimeout:      5 * time.Second,
+		Traffic:           traffic,
+	}, nil)
+	ackCh := make(chan struct{}, 1)
+	client.SetMessageHandler(func(*mmv1.Message) error {
+		ackCh <- struct{}{}
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Close()
+
+	ping := &mmv1.Message{
+		Type:    mmv1.MessageType_MESSAGE_TYPE_HEARTBEAT,
+		Payload: &mmv1.Message_Heartbeat{Heartbeat: &mmv1.Heartbeat{Ping: true}},
+	}
+	if err := client.Send(ping); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	for _, ch := range []chan struct{}{ackCh, receivedCh} {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("Timeout waiting for messages")
+		}
+	}
+
+	got := traffic.Snapshot()
+	if len(got) != 2 {
+		t.Fatalf("Snapshot() = %+v, want one inbound and one outbound type", got)
+	}
+	if got[0].Type != "MESSAGE_TYPE_CONNECTION_ACK" || got[0].Bytes != uint64(len(ack)) {
+		t.Errorf("in = %+v, want the ack of %d bytes", got[0], len(ack))
+	}
+	if got[1].Type != "MESSAGE_TYPE_HEARTBEAT" || got[1].Bytes != uint64(proto.Size(ping)) {
+		t.Errorf("out = %+v, want the ping of %d bytes", got[1], proto.Size(ping))
+	}
+}
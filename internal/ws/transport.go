@@ -0,0 +1,219 @@
+package ws
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrClosedByPeer is returned by Conn.ReadFrame when the peer closed the connection cleanly
+var ErrClosedByPeer = errors.New("connection closed by peer")
+
+// HandshakeError is a dial refused by the server with an HTTP status
+type HandshakeError struct {
+	Status int
+	Err    error
+}
+
+func (e *HandshakeError) Error() string {
+	return fmt.Sprintf("handshake status %d: %v", e.Status, e.Err)
+}
+
+func (e *HandshakeError) Unwrap() error { return e.Err }
+
+// AuthFailed reports whether the server refused the credentials
+func (e *HandshakeError) AuthFailed() bool {
+	return e.Status == http.StatusUnauthorized || e.Status == http.StatusForbidden
+}
+
+// Transport dials the connections a client carries its frames over
+// The client keeps the protobuf framing, heartbeat and reconnection state machine; a
+// transport only moves opaque binary frames, so alternate transports (TLS TCP, gRPC streams,
+// in-memory pipes for tests) reuse all of it.
+type Transport interface {
+	// Dial opens a new connection; ctx bounds the dial and is not kept
+	Dial(ctx context.Context) (Conn, error)
+}
+
+// Conn is one transport connection carrying binary frames
+// ReadFrame is called from one goroutine and the write methods from another.
+type Conn interface {
+	// ReadFrame returns the next frame, ErrClosedByPeer after a clean close by the peer
+	ReadFrame() ([]byte, error)
+	// WriteFrame writes one frame; it may be buffered until Flush
+	WriteFrame(frame []byte) error
+	// Flush writes the frames buffered since the last flush
+	Flush() error
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+	// Shutdown tells the peer the connection is closing, then closes it
+	Shutdown() error
+	// Close closes the connection immediately
+	Close() error
+}
+
+// defaultHandshakeTimeout bounds the TCP, TLS and WebSocket handshakes when not configured
+const defaultHandshakeTimeout = 10 * time.Second
+
+// wsTransport is the default transport: WebSocket with bearer token authentication
+type wsTransport struct {
+	url              string
+	token            string
+	userAgent        string
+	tlsConfig        *tls.Config
+	handshakeTimeout time.Duration
+	keepAlive        time.Duration
+	logger           *slog.Logger
+}
+
+// NewWebSocketTransport creates the WebSocket transport of config: it dials ServerURL with
+// APIToken as bearer token (empty = no Authorization header), using the dialer and TLS settings
+func NewWebSocketTransport(config *Config, logger *slog.Logger) Transport {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	t := &wsTransport{
+		url:              config.ServerURL,
+		token:            config.APIToken,
+		userAgent:        config.UserAgent,
+		handshakeTimeout: config.HandshakeTimeout,
+		keepAlive:        config.KeepAlive,
+		logger:           logger,
+	}
+	if t.handshakeTimeout <= 0 {
+		t.handshakeTimeout = defaultHandshakeTimeout
+	}
+
+	if config.TLSConfig != nil {
+		t.tlsConfig = config.TLSConfig.Clone()
+	} else {
+		t.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if len(config.TLSPins) > 0 {
+		t.tlsConfig.VerifyConnection = verifyPins(config.TLSPins)
+	}
+	if config.TLSSessionCache > 0 {
+		// Shared by every dial, so a reconnect resumes the previous session
+		t.tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(config.TLSSessionCache)
+	}
+	return t
+}
+
+// Dial opens a WebSocket connection
+func (t *wsTransport) Dial(ctx context.Context) (Conn, error) {
+	var wire *bufferedConn
+	dialer := websocket.Dialer{
+		HandshakeTimeout: t.handshakeTimeout,
+		TLSClientConfig:  t.tlsConfig,
+		NetDialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			// The host is resolved again on every dial: a reconnect follows DNS changes
+			netDialer := &net.Dialer{Timeout: t.handshakeTimeout, KeepAlive: t.keepAlive}
+			netConn, err := netDialer.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			wire = newBufferedConn(netConn)
+			return wire, nil
+		},
+	}
+
+	// Build request header, add token authentication and the MM build
+	header := http.Header{}
+	if t.token != "" {
+		header.Set("Authorization", "Bearer "+t.token)
+	}
+	if t.userAgent != "" {
+		header.Set("User-Agent", t.userAgent)
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, t.url, header)
+	if err != nil {
+		if resp != nil {
+			return nil, &HandshakeError{Status: resp.StatusCode, Err: err}
+		}
+		return nil, err
+	}
+	return &wsConn{conn: conn, wire: wire, logger: t.logger}, nil
+}
+
+// wsConn is a WebSocket connection carrying frames as binary messages
+type wsConn struct {
+	conn    *websocket.Conn
+	wire    *bufferedConn // Network connection under conn, buffers the writer's bursts
+	holding bool          // Writes since the last flush are held in wire
+	logger  *slog.Logger
+}
+
+// RemoteAddr returns the address of the server end
+func (c *wsConn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// ReadFrame returns the next binary message, skipping other message types
+func (c *wsConn) ReadFrame() ([]byte, error) {
+	for {
+		msgType, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return nil, fmt.Errorf("%w: %v", ErrClosedByPeer, err)
+			}
+			return nil, err
+		}
+		// Only handle binary messages
+		if msgType != websocket.BinaryMessage {
+			c.logger.Warn("Received non-binary message", "type", msgType)
+			continue
+		}
+		return data, nil
+	}
+}
+
+// WriteFrame writes frame as a binary message, held in the buffer until Flush
+func (c *wsConn) WriteFrame(frame []byte) error {
+	if !c.holding && c.wire != nil {
+		c.wire.hold()
+		c.holding = true
+	}
+	return c.conn.WriteMessage(websocket.BinaryMessage, frame)
+}
+
+// Flush writes the held messages to the socket
+func (c *wsConn) Flush() error {
+	if !c.holding {
+		return nil
+	}
+	c.holding = false
+	return c.wire.flush()
+}
+
+// SetReadDeadline sets the read deadline
+func (c *wsConn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the write deadline
+func (c *wsConn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+// Shutdown sends a close frame, then closes the connection
+func (c *wsConn) Shutdown() error {
+	_ = c.conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(time.Second),
+	)
+	return c.conn.Close()
+}
+
+// Close closes the connection without a close frame
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,148 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+
+	mmv1 "github.com/ThetaSpace/DarkPool-Market-Maker-Example/mm/v1"
+)
+
+func memoryClient(t *testing.T, transport Transport) WSClient {
+	t.Helper()
+	return NewClient(&Config{
+		ReconnectInterval: 10 * time.Millisecond,
+		HeartbeatInterval: time.Minute,
+		ReadTimeout:       500 * time.Millisecond, // Bounds Close, which waits for the read loop
+		WriteTimeout:      5 * time.Second,
+		Transport:         transport,
+	}, nil)
+}
+
+func TestClient_MemoryTransport(t *testing.T) {
+	transport := NewMemoryTransport()
+	client := memoryClient(t, transport)
+	received := make(chan *mmv1.Message, 4)
+	client.SetMessageHandler(func(msg *mmv1.Message) error {
+		received <- msg
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	accepted := make(chan Conn, 1)
+	go func() {
+		conn, err := transport.Accept(ctx)
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer client.Close()
+	server := <-accepted
+
+	// Server to client
+	ack, _ := proto.Marshal(ackMessage())
+	if err := server.WriteFrame(ack); err != nil {
+		t.Fatalf("WriteFrame failed: %v", err)
+	}
+	select {
+	case msg := <-received:
+		if msg.Type != mmv1.MessageType_MESSAGE_TYPE_CONNECTION_ACK {
+			t.Errorf("received type = %v, want CONNECTION_ACK", msg.Type)
+		}
+	case <-ctx.Done():
+		t.Fatal("client did not receive the ack")
+	}
+
+	// Client to server
+	if err := client.Send(&mmv1.Message{Type: mmv1.MessageType_MESSAGE_TYPE_HEARTBEAT}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	_ = server.SetReadDeadline(time.Now().Add(2 * time.Second))
+	data, err := server.ReadFrame()
+	if err != nil {
+		t.Fatalf("ReadFrame failed: %v", err)
+	}
+	msg := &mmv1.Message{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if msg.Type != mmv1.MessageType_MESSAGE_TYPE_HEARTBEAT {
+		t.Errorf("sent type = %v, want HEARTBEAT", msg.Type)
+	}
+
+	// A server close makes the client redial over the same transport
+	_ = server.Close()
+	if _, err := transport.Accept(ctx); err != nil {
+		t.Fatalf("client did not reconnect: %v", err)
+	}
+}
+
+func TestMemoryConn_Close(t *testing.T) {
+	client, server := newMemoryPipe()
+	if err := client.WriteFrame([]byte("last")); err != nil {
+		t.Fatalf("WriteFrame failed: %v", err)
+	}
+	_ = client.Close()
+
+	frame, err := server.ReadFrame()
+	if err != nil || string(frame) != "last" {
+		t.Errorf("ReadFrame = %q, %v, want frame sent before close", frame, err)
+	}
+	if _, err := server.ReadFrame(); !errors.Is(err, ErrClosedByPeer) {
+		t.Errorf("ReadFrame error = %v, want ErrClosedByPeer", err)
+	}
+	if err := client.WriteFrame([]byte("x")); errors.Is(err, ErrClosedByPeer) || err == nil {
+		t.Errorf("WriteFrame after own close = %v, want net.ErrClosed", err)
+	}
+}
+
+func TestMemoryConn_ReadDeadline(t *testing.T) {
+	_, server := newMemoryPipe()
+	_ = server.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+	if _, err := server.ReadFrame(); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("ReadFrame error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestWebSocketTransport_HandshakeHeaders(t *testing.T) {
+	var auth, userAgent string
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, userAgent = r.Header.Get("Authorization"), r.Header.Get("User-Agent")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		_ = conn.Close()
+	}))
+	defer server.Close()
+
+	transport := NewWebSocketTransport(&Config{
+		ServerURL: "ws" + strings.TrimPrefix(server.URL, "http"),
+		APIToken:  "token",
+		UserAgent: "DarkPool-MM/v1.0.0 (abc; go1.22)",
+	}, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := transport.Dial(ctx)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	_ = conn.Close()
+	if auth != "Bearer token" || userAgent != "DarkPool-MM/v1.0.0 (abc; go1.22)" {
+		t.Errorf("Authorization = %q, User-Agent = %q", auth, userAgent)
+	}
+}
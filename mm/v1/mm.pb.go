@@ -611,8 +611,9 @@ type DepthSnapshot struct {
 	MmId          string                 `protobuf:"bytes,3,opt,name=mm_id,json=mmId,proto3" json:"mm_id,omitempty"`
 	TokenA        string                 `protobuf:"bytes,4,opt,name=token_a,json=tokenA,proto3" json:"token_a,omitempty"`
 	TokenB        string                 `protobuf:"bytes,5,opt,name=token_b,json=tokenB,proto3" json:"token_b,omitempty"`
-	Bids          []*PriceLevel          `protobuf:"bytes,6,rep,name=bids,proto3" json:"bids,omitempty"` // Bids (price descending)
-	Asks          []*PriceLevel          `protobuf:"bytes,7,rep,name=asks,proto3" json:"asks,omitempty"` // Asks (price ascending)
+	Bids          []*PriceLevel          `protobuf:"bytes,6,rep,name=bids,proto3" json:"bids,omitempty"`                // Bids (price descending)
+	Asks          []*PriceLevel          `protobuf:"bytes,7,rep,name=asks,proto3" json:"asks,omitempty"`                // Asks (price ascending)
+	Attestation   *DepthAttestation      `protobuf:"bytes,16,opt,name=attestation,proto3" json:"attestation,omitempty"` // MM signature over the snapshot, optional
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -696,6 +697,74 @@ func (x *DepthSnapshot) GetAsks() []*PriceLevel {
 	return nil
 }
 
+func (x *DepthSnapshot) GetAttestation() *DepthAttestation {
+	if x != nil {
+		return x.Attestation
+	}
+	return nil
+}
+
+// DepthAttestation MM's EIP-712 signature over a depth snapshot
+type DepthAttestation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`   // Per pair, increasing; starts from the MM's start time in ms
+	Timestamp     uint64                 `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Message.timestamp of the snapshot (ms)
+	Signature     []byte                 `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`  // 65-byte EIP-712 signature, v = 27/28
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DepthAttestation) Reset() {
+	*x = DepthAttestation{}
+	mi := &file_mm_v1_mm_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DepthAttestation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepthAttestation) ProtoMessage() {}
+
+func (x *DepthAttestation) ProtoReflect() protoreflect.Message {
+	mi := &file_mm_v1_mm_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepthAttestation.ProtoReflect.Descriptor instead.
+func (*DepthAttestation) Descriptor() ([]byte, []int) {
+	return file_mm_v1_mm_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DepthAttestation) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *DepthAttestation) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *DepthAttestation) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
 // PriceLevel price level
 type PriceLevel struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -707,7 +776,7 @@ type PriceLevel struct {
 
 func (x *PriceLevel) Reset() {
 	*x = PriceLevel{}
-	mi := &file_mm_v1_mm_proto_msgTypes[4]
+	mi := &file_mm_v1_mm_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -719,7 +788,7 @@ func (x *PriceLevel) String() string {
 func (*PriceLevel) ProtoMessage() {}
 
 func (x *PriceLevel) ProtoReflect() protoreflect.Message {
-	mi := &file_mm_v1_mm_proto_msgTypes[4]
+	mi := &file_mm_v1_mm_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -732,7 +801,7 @@ func (x *PriceLevel) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PriceLevel.ProtoReflect.Descriptor instead.
 func (*PriceLevel) Descriptor() ([]byte, []int) {
-	return file_mm_v1_mm_proto_rawDescGZIP(), []int{4}
+	return file_mm_v1_mm_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *PriceLevel) GetPrice() string {
@@ -768,7 +837,7 @@ type QuoteRequest struct {
 
 func (x *QuoteRequest) Reset() {
 	*x = QuoteRequest{}
-	mi := &file_mm_v1_mm_proto_msgTypes[5]
+	mi := &file_mm_v1_mm_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -780,7 +849,7 @@ func (x *QuoteRequest) String() string {
 func (*QuoteRequest) ProtoMessage() {}
 
 func (x *QuoteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mm_v1_mm_proto_msgTypes[5]
+	mi := &file_mm_v1_mm_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -793,7 +862,7 @@ func (x *QuoteRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QuoteRequest.ProtoReflect.Descriptor instead.
 func (*QuoteRequest) Descriptor() ([]byte, []int) {
-	return file_mm_v1_mm_proto_rawDescGZIP(), []int{5}
+	return file_mm_v1_mm_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *QuoteRequest) GetQuoteId() string {
@@ -880,7 +949,7 @@ type QuoteResponse struct {
 
 func (x *QuoteResponse) Reset() {
 	*x = QuoteResponse{}
-	mi := &file_mm_v1_mm_proto_msgTypes[6]
+	mi := &file_mm_v1_mm_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -892,7 +961,7 @@ func (x *QuoteResponse) String() string {
 func (*QuoteResponse) ProtoMessage() {}
 
 func (x *QuoteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mm_v1_mm_proto_msgTypes[6]
+	mi := &file_mm_v1_mm_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -905,7 +974,7 @@ func (x *QuoteResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QuoteResponse.ProtoReflect.Descriptor instead.
 func (*QuoteResponse) Descriptor() ([]byte, []int) {
-	return file_mm_v1_mm_proto_rawDescGZIP(), []int{6}
+	return file_mm_v1_mm_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *QuoteResponse) GetQuoteId() string {
@@ -960,7 +1029,7 @@ type SignedOrder struct {
 
 func (x *SignedOrder) Reset() {
 	*x = SignedOrder{}
-	mi := &file_mm_v1_mm_proto_msgTypes[7]
+	mi := &file_mm_v1_mm_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -972,7 +1041,7 @@ func (x *SignedOrder) String() string {
 func (*SignedOrder) ProtoMessage() {}
 
 func (x *SignedOrder) ProtoReflect() protoreflect.Message {
-	mi := &file_mm_v1_mm_proto_msgTypes[7]
+	mi := &file_mm_v1_mm_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -985,7 +1054,7 @@ func (x *SignedOrder) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SignedOrder.ProtoReflect.Descriptor instead.
 func (*SignedOrder) Descriptor() ([]byte, []int) {
-	return file_mm_v1_mm_proto_rawDescGZIP(), []int{7}
+	return file_mm_v1_mm_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *SignedOrder) GetSigner() string {
@@ -1058,7 +1127,7 @@ type QuoteReject struct {
 
 func (x *QuoteReject) Reset() {
 	*x = QuoteReject{}
-	mi := &file_mm_v1_mm_proto_msgTypes[8]
+	mi := &file_mm_v1_mm_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1070,7 +1139,7 @@ func (x *QuoteReject) String() string {
 func (*QuoteReject) ProtoMessage() {}
 
 func (x *QuoteReject) ProtoReflect() protoreflect.Message {
-	mi := &file_mm_v1_mm_proto_msgTypes[8]
+	mi := &file_mm_v1_mm_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1083,7 +1152,7 @@ func (x *QuoteReject) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QuoteReject.ProtoReflect.Descriptor instead.
 func (*QuoteReject) Descriptor() ([]byte, []int) {
-	return file_mm_v1_mm_proto_rawDescGZIP(), []int{8}
+	return file_mm_v1_mm_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *QuoteReject) GetQuoteId() string {
@@ -1126,13 +1195,14 @@ type Heartbeat struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ping          bool                   `protobuf:"varint,1,opt,name=ping,proto3" json:"ping,omitempty"`
 	Pong          bool                   `protobuf:"varint,2,opt,name=pong,proto3" json:"pong,omitempty"`
+	Health        *ClientHealth          `protobuf:"bytes,16,opt,name=health,proto3" json:"health,omitempty"` // MM pings only, optional
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Heartbeat) Reset() {
 	*x = Heartbeat{}
-	mi := &file_mm_v1_mm_proto_msgTypes[9]
+	mi := &file_mm_v1_mm_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1144,7 +1214,7 @@ func (x *Heartbeat) String() string {
 func (*Heartbeat) ProtoMessage() {}
 
 func (x *Heartbeat) ProtoReflect() protoreflect.Message {
-	mi := &file_mm_v1_mm_proto_msgTypes[9]
+	mi := &file_mm_v1_mm_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1157,7 +1227,7 @@ func (x *Heartbeat) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
 func (*Heartbeat) Descriptor() ([]byte, []int) {
-	return file_mm_v1_mm_proto_rawDescGZIP(), []int{9}
+	return file_mm_v1_mm_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Heartbeat) GetPing() bool {
@@ -1174,6 +1244,90 @@ func (x *Heartbeat) GetPong() bool {
 	return false
 }
 
+func (x *Heartbeat) GetHealth() *ClientHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+// ClientHealth MM client-side health and status summary (attached to MM pings)
+type ClientHealth struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	QueueDepth     uint64                 `protobuf:"varint,1,opt,name=queue_depth,json=queueDepth,proto3" json:"queue_depth,omitempty"`                 // Outbound messages queued, not yet written
+	DepthPushAgeMs uint64                 `protobuf:"varint,2,opt,name=depth_push_age_ms,json=depthPushAgeMs,proto3" json:"depth_push_age_ms,omitempty"` // Since the last depth push of the stalest pair
+	QuoteP95Us     uint64                 `protobuf:"varint,3,opt,name=quote_p95_us,json=quoteP95Us,proto3" json:"quote_p95_us,omitempty"`               // Quote handling latency over recent requests (microseconds)
+	OpenQuotes     uint64                 `protobuf:"varint,4,opt,name=open_quotes,json=openQuotes,proto3" json:"open_quotes,omitempty"`                 // Signed quotes not yet filled or expired
+	RejectRateBps  uint32                 `protobuf:"varint,5,opt,name=reject_rate_bps,json=rejectRateBps,proto3" json:"reject_rate_bps,omitempty"`      // Rejected share of all quote requests (basis points)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ClientHealth) Reset() {
+	*x = ClientHealth{}
+	mi := &file_mm_v1_mm_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientHealth) ProtoMessage() {}
+
+func (x *ClientHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_mm_v1_mm_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientHealth.ProtoReflect.Descriptor instead.
+func (*ClientHealth) Descriptor() ([]byte, []int) {
+	return file_mm_v1_mm_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ClientHealth) GetQueueDepth() uint64 {
+	if x != nil {
+		return x.QueueDepth
+	}
+	return 0
+}
+
+func (x *ClientHealth) GetDepthPushAgeMs() uint64 {
+	if x != nil {
+		return x.DepthPushAgeMs
+	}
+	return 0
+}
+
+func (x *ClientHealth) GetQuoteP95Us() uint64 {
+	if x != nil {
+		return x.QuoteP95Us
+	}
+	return 0
+}
+
+func (x *ClientHealth) GetOpenQuotes() uint64 {
+	if x != nil {
+		return x.OpenQuotes
+	}
+	return 0
+}
+
+func (x *ClientHealth) GetRejectRateBps() uint32 {
+	if x != nil {
+		return x.RejectRateBps
+	}
+	return 0
+}
+
 // Error error message
 type Error struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
@@ -1186,7 +1340,7 @@ type Error struct {
 
 func (x *Error) Reset() {
 	*x = Error{}
-	mi := &file_mm_v1_mm_proto_msgTypes[10]
+	mi := &file_mm_v1_mm_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1198,7 +1352,7 @@ func (x *Error) String() string {
 func (*Error) ProtoMessage() {}
 
 func (x *Error) ProtoReflect() protoreflect.Message {
-	mi := &file_mm_v1_mm_proto_msgTypes[10]
+	mi := &file_mm_v1_mm_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1211,7 +1365,7 @@ func (x *Error) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Error.ProtoReflect.Descriptor instead.
 func (*Error) Descriptor() ([]byte, []int) {
-	return file_mm_v1_mm_proto_rawDescGZIP(), []int{10}
+	return file_mm_v1_mm_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *Error) GetCode() ErrorCode {
@@ -1263,7 +1417,7 @@ const file_mm_v1_mm_proto_rawDesc = "" +
 	"\x10ConnectionConfig\x123\n" +
 	"\x16depth_push_interval_ms\x18\x01 \x01(\rR\x13depthPushIntervalMs\x12(\n" +
 	"\x10quote_timeout_ms\x18\x02 \x01(\rR\x0equoteTimeoutMs\x122\n" +
-	"\x15heartbeat_interval_ms\x18\x03 \x01(\rR\x13heartbeatIntervalMs\"\xd8\x01\n" +
+	"\x15heartbeat_interval_ms\x18\x03 \x01(\rR\x13heartbeatIntervalMs\"\x93\x02\n" +
 	"\rDepthSnapshot\x12\x19\n" +
 	"\bchain_id\x18\x01 \x01(\x04R\achainId\x12\x17\n" +
 	"\apair_id\x18\x02 \x01(\tR\x06pairId\x12\x13\n" +
@@ -1271,7 +1425,12 @@ const file_mm_v1_mm_proto_rawDesc = "" +
 	"\atoken_a\x18\x04 \x01(\tR\x06tokenA\x12\x17\n" +
 	"\atoken_b\x18\x05 \x01(\tR\x06tokenB\x12%\n" +
 	"\x04bids\x18\x06 \x03(\v2\x11.mm.v1.PriceLevelR\x04bids\x12%\n" +
-	"\x04asks\x18\a \x03(\v2\x11.mm.v1.PriceLevelR\x04asks\":\n" +
+	"\x04asks\x18\a \x03(\v2\x11.mm.v1.PriceLevelR\x04asks\x129\n" +
+	"\vattestation\x18\x10 \x01(\v2\x17.mm.v1.DepthAttestationR\vattestation\"j\n" +
+	"\x10DepthAttestation\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x04R\ttimestamp\x12\x1c\n" +
+	"\tsignature\x18\x03 \x01(\fR\tsignature\":\n" +
 	"\n" +
 	"PriceLevel\x12\x14\n" +
 	"\x05price\x18\x01 \x01(\tR\x05price\x12\x16\n" +
@@ -1311,10 +1470,20 @@ const file_mm_v1_mm_proto_rawDesc = "" +
 	"\bchain_id\x18\x02 \x01(\x04R\achainId\x12\x13\n" +
 	"\x05mm_id\x18\x03 \x01(\tR\x04mmId\x12+\n" +
 	"\x06reason\x18\x04 \x01(\x0e2\x13.mm.v1.RejectReasonR\x06reason\x12\x18\n" +
-	"\amessage\x18\x05 \x01(\tR\amessage\"3\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\"`\n" +
 	"\tHeartbeat\x12\x12\n" +
 	"\x04ping\x18\x01 \x01(\bR\x04ping\x12\x12\n" +
-	"\x04pong\x18\x02 \x01(\bR\x04pong\"q\n" +
+	"\x04pong\x18\x02 \x01(\bR\x04pong\x12+\n" +
+	"\x06health\x18\x10 \x01(\v2\x13.mm.v1.ClientHealthR\x06health\"\xc5\x01\n" +
+	"\fClientHealth\x12\x1f\n" +
+	"\vqueue_depth\x18\x01 \x01(\x04R\n" +
+	"queueDepth\x12)\n" +
+	"\x11depth_push_age_ms\x18\x02 \x01(\x04R\x0edepthPushAgeMs\x12 \n" +
+	"\fquote_p95_us\x18\x03 \x01(\x04R\n" +
+	"quoteP95Us\x12\x1f\n" +
+	"\vopen_quotes\x18\x04 \x01(\x04R\n" +
+	"openQuotes\x12&\n" +
+	"\x0freject_rate_bps\x18\x05 \x01(\rR\rrejectRateBps\"q\n" +
 	"\x05Error\x12$\n" +
 	"\x04code\x18\x01 \x01(\x0e2\x10.mm.v1.ErrorCodeR\x04code\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12(\n" +
@@ -1368,7 +1537,7 @@ func file_mm_v1_mm_proto_rawDescGZIP() []byte {
 }
 
 var file_mm_v1_mm_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_mm_v1_mm_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_mm_v1_mm_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_mm_v1_mm_proto_goTypes = []any{
 	(MessageType)(0),         // 0: mm.v1.MessageType
 	(QuoteStatus)(0),         // 1: mm.v1.QuoteStatus
@@ -1378,35 +1547,39 @@ var file_mm_v1_mm_proto_goTypes = []any{
 	(*ConnectionAck)(nil),    // 5: mm.v1.ConnectionAck
 	(*ConnectionConfig)(nil), // 6: mm.v1.ConnectionConfig
 	(*DepthSnapshot)(nil),    // 7: mm.v1.DepthSnapshot
-	(*PriceLevel)(nil),       // 8: mm.v1.PriceLevel
-	(*QuoteRequest)(nil),     // 9: mm.v1.QuoteRequest
-	(*QuoteResponse)(nil),    // 10: mm.v1.QuoteResponse
-	(*SignedOrder)(nil),      // 11: mm.v1.SignedOrder
-	(*QuoteReject)(nil),      // 12: mm.v1.QuoteReject
-	(*Heartbeat)(nil),        // 13: mm.v1.Heartbeat
-	(*Error)(nil),            // 14: mm.v1.Error
+	(*DepthAttestation)(nil), // 8: mm.v1.DepthAttestation
+	(*PriceLevel)(nil),       // 9: mm.v1.PriceLevel
+	(*QuoteRequest)(nil),     // 10: mm.v1.QuoteRequest
+	(*QuoteResponse)(nil),    // 11: mm.v1.QuoteResponse
+	(*SignedOrder)(nil),      // 12: mm.v1.SignedOrder
+	(*QuoteReject)(nil),      // 13: mm.v1.QuoteReject
+	(*Heartbeat)(nil),        // 14: mm.v1.Heartbeat
+	(*ClientHealth)(nil),     // 15: mm.v1.ClientHealth
+	(*Error)(nil),            // 16: mm.v1.Error
 }
 var file_mm_v1_mm_proto_depIdxs = []int32{
 	0,  // 0: mm.v1.Message.type:type_name -> mm.v1.MessageType
 	7,  // 1: mm.v1.Message.depth_snapshot:type_name -> mm.v1.DepthSnapshot
-	9,  // 2: mm.v1.Message.quote_request:type_name -> mm.v1.QuoteRequest
-	10, // 3: mm.v1.Message.quote_response:type_name -> mm.v1.QuoteResponse
-	12, // 4: mm.v1.Message.quote_reject:type_name -> mm.v1.QuoteReject
-	13, // 5: mm.v1.Message.heartbeat:type_name -> mm.v1.Heartbeat
-	14, // 6: mm.v1.Message.error:type_name -> mm.v1.Error
+	10, // 2: mm.v1.Message.quote_request:type_name -> mm.v1.QuoteRequest
+	11, // 3: mm.v1.Message.quote_response:type_name -> mm.v1.QuoteResponse
+	13, // 4: mm.v1.Message.quote_reject:type_name -> mm.v1.QuoteReject
+	14, // 5: mm.v1.Message.heartbeat:type_name -> mm.v1.Heartbeat
+	16, // 6: mm.v1.Message.error:type_name -> mm.v1.Error
 	5,  // 7: mm.v1.Message.connection_ack:type_name -> mm.v1.ConnectionAck
 	6,  // 8: mm.v1.ConnectionAck.config:type_name -> mm.v1.ConnectionConfig
-	8,  // 9: mm.v1.DepthSnapshot.bids:type_name -> mm.v1.PriceLevel
-	8,  // 10: mm.v1.DepthSnapshot.asks:type_name -> mm.v1.PriceLevel
-	1,  // 11: mm.v1.QuoteResponse.status:type_name -> mm.v1.QuoteStatus
-	11, // 12: mm.v1.QuoteResponse.order:type_name -> mm.v1.SignedOrder
-	2,  // 13: mm.v1.QuoteReject.reason:type_name -> mm.v1.RejectReason
-	3,  // 14: mm.v1.Error.code:type_name -> mm.v1.ErrorCode
-	15, // [15:15] is the sub-list for method output_type
-	15, // [15:15] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	9,  // 9: mm.v1.DepthSnapshot.bids:type_name -> mm.v1.PriceLevel
+	9,  // 10: mm.v1.DepthSnapshot.asks:type_name -> mm.v1.PriceLevel
+	8,  // 11: mm.v1.DepthSnapshot.attestation:type_name -> mm.v1.DepthAttestation
+	1,  // 12: mm.v1.QuoteResponse.status:type_name -> mm.v1.QuoteStatus
+	12, // 13: mm.v1.QuoteResponse.order:type_name -> mm.v1.SignedOrder
+	2,  // 14: mm.v1.QuoteReject.reason:type_name -> mm.v1.RejectReason
+	15, // 15: mm.v1.Heartbeat.health:type_name -> mm.v1.ClientHealth
+	3,  // 16: mm.v1.Error.code:type_name -> mm.v1.ErrorCode
+	17, // [17:17] is the sub-list for method output_type
+	17, // [17:17] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_mm_v1_mm_proto_init() }
@@ -1429,7 +1602,7 @@ func file_mm_v1_mm_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mm_v1_mm_proto_rawDesc), len(file_mm_v1_mm_proto_rawDesc)),
 			NumEnums:      4,
-			NumMessages:   11,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+/// @notice Minimal stand-in for the RFQ Manager signature check, used by the integration harness.
+/// Hashing mirrors internal/signer: same domain, MMQUOTE_SIGNATURE_HASH field order and extraData hashing.
+/// The domain uses address(this) as verifyingContract, so the MM must be configured with the deployed address.
+contract MMQuoteVerifier {
+    struct MMQuote {
+        address rfqManager;
+        address from;
+        address to;
+        address inputToken;
+        address outputToken;
+        uint256 amountIn;
+        uint256 amountOut;
+        uint256 deadline;
+        uint256 nonce;
+        bytes extraData;
+    }
+
+    bytes32 public constant MMQUOTE_SIGNATURE_HASH = keccak256(
+        "MMQuote(address rfq_manager,address from,address to,address inputToken,address outputToken,"
+        "uint256 amountIn,uint256 amountOut,uint256 deadline,uint256 nonce,bytes32 extraDataHash)"
+    );
+
+    bytes32 public immutable DOMAIN_SEPARATOR;
+
+    constructor(string memory name, string memory version) {
+        DOMAIN_SEPARATOR = keccak256(
+            abi.encode(
+                keccak256("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"),
+                keccak256(bytes(name)),
+                keccak256(bytes(version)),
+                block.chainid,
+                address(this)
+            )
+        );
+    }
+
+    function hashQuote(MMQuote calldata q) public view returns (bytes32) {
+        bytes32 structHash = keccak256(
+            abi.encode(
+                MMQUOTE_SIGNATURE_HASH,
+                q.rfqManager,
+                q.from,
+                q.to,
+                q.inputToken,
+                q.outputToken,
+                q.amountIn,
+                q.amountOut,
+                q.deadline,
+                q.nonce,
+                keccak256(q.extraData)
+            )
+        );
+        return keccak256(abi.encodePacked("\x19\x01", DOMAIN_SEPARATOR, structHash));
+    }
+
+    /// @notice Returns the address that signed the quote, or address(0) for a malformed signature
+    function recover(MMQuote calldata q, bytes calldata signature) external view returns (address) {
+        if (signature.length != 65) {
+            return address(0);
+        }
+        bytes32 r = bytes32(signature[0:32]);
+        bytes32 s = bytes32(signature[32:64]);
+        uint8 v = uint8(signature[64]);
+        return ecrecover(hashQuote(q), v, r, s);
+    }
+}